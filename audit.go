@@ -0,0 +1,64 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the action recorded by an audit event.
+type AuditAction string
+
+// Actions recorded by audit events.
+const (
+	// AuditCreate records the creation of a resource.
+	AuditCreate AuditAction = "create"
+	// AuditUpdate records the update of a resource.
+	AuditUpdate AuditAction = "update"
+	// AuditDelete records the deletion of a resource.
+	AuditDelete AuditAction = "delete"
+	// AuditDenied records a request that failed authentication or authorization.
+	AuditDenied AuditAction = "denied"
+	// AuditLogin records a sign in, successful or not.
+	AuditLogin AuditAction = "login"
+	// AuditLogout records a sign out.
+	AuditLogout AuditAction = "logout"
+)
+
+// AuditEvent is a record of the audit log.
+type AuditEvent struct {
+	Time   time.Time   `json:"time"`
+	Action AuditAction `json:"action"`
+
+	// OrgID is the organization of the resource, or of the authorization of
+	// the request when the resource does not belong to an organization.
+	OrgID ID `json:"orgID,omitempty"`
+
+	UserID          ID     `json:"userID,omitempty"`
+	AuthorizationID ID     `json:"authorizationID,omitempty"`
+	RemoteAddr      string `json:"remoteAddr,omitempty"`
+
+	ResourceType ResourceType `json:"resourceType,omitempty"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+
+	// Diff holds the fields of the resource that changed.
+	Diff map[string]AuditChange `json:"diff,omitempty"`
+
+	// Request is the method and path of a denied request.
+	Request string `json:"request,omitempty"`
+	// Error is the reason a request was denied or a sign in failed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditChange is the value of a field before and after a change, Before is
+// empty for created fields and After is empty for deleted fields.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLogger records audit events.
+type AuditLogger interface {
+	// LogAuditEvents records events.
+	LogAuditEvents(ctx context.Context, events ...*AuditEvent) error
+}
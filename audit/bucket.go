@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+const (
+	measurement = "audit"
+
+	actionTag       = "action"
+	resourceTypeTag = "resourceType"
+
+	userIDField          = "userID"
+	authorizationIDField = "authorizationID"
+	resourceIDField      = "resourceID"
+	remoteAddrField      = "remoteAddr"
+	requestField         = "request"
+	errorField           = "error"
+	diffField            = "diff"
+)
+
+var _ influxdb.AuditLogger = (*BucketLogger)(nil)
+
+// BucketLogger writes audit events to the audit system bucket of the
+// organization of the events. The events that do not belong to an organization
+// are written to the audit bucket of the organizations of their user.
+type BucketLogger struct {
+	PointsWriter               storage.PointsWriter
+	UserResourceMappingService influxdb.UserResourceMappingService
+}
+
+// NewBucketLogger returns a BucketLogger writing to pw.
+func NewBucketLogger(pw storage.PointsWriter, urms influxdb.UserResourceMappingService) *BucketLogger {
+	return &BucketLogger{
+		PointsWriter:               pw,
+		UserResourceMappingService: urms,
+	}
+}
+
+// LogAuditEvents writes events to the audit bucket.
+func (l *BucketLogger) LogAuditEvents(ctx context.Context, events ...*influxdb.AuditEvent) error {
+	var points []models.Point
+	for _, e := range events {
+		orgIDs, err := l.orgIDs(ctx, e)
+		if err != nil {
+			return err
+		}
+		if len(orgIDs) == 0 {
+			continue
+		}
+
+		p, err := point(e)
+		if err != nil {
+			return err
+		}
+		for _, orgID := range orgIDs {
+			ps, err := tsdb.ExplodePoints(orgID, influxdb.AuditSystemBucketID, models.Points{p})
+			if err != nil {
+				return err
+			}
+			points = append(points, ps...)
+		}
+	}
+
+	if len(points) == 0 {
+		return nil
+	}
+	return l.PointsWriter.WritePoints(ctx, points)
+}
+
+// orgIDs returns the organizations whose audit bucket e is written to.
+func (l *BucketLogger) orgIDs(ctx context.Context, e *influxdb.AuditEvent) ([]influxdb.ID, error) {
+	if e.OrgID.Valid() {
+		return []influxdb.ID{e.OrgID}, nil
+	}
+	if !e.UserID.Valid() || l.UserResourceMappingService == nil {
+		return nil, nil
+	}
+
+	ms, _, err := l.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID:       e.UserID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]influxdb.ID, 0, len(ms))
+	for _, m := range ms {
+		ids = append(ids, m.ResourceID)
+	}
+	return ids, nil
+}
+
+// point returns the point of e, the action and the type of resource are tags
+// and the remaining attributes of e fields.
+func point(e *influxdb.AuditEvent) (models.Point, error) {
+	tags := map[string]string{
+		actionTag: string(e.Action),
+	}
+	if e.ResourceType != "" {
+		tags[resourceTypeTag] = string(e.ResourceType)
+	}
+
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		diffField: string(diff),
+	}
+	if e.UserID.Valid() {
+		fields[userIDField] = e.UserID.String()
+	}
+	if e.AuthorizationID.Valid() {
+		fields[authorizationIDField] = e.AuthorizationID.String()
+	}
+	if e.ResourceID.Valid() {
+		fields[resourceIDField] = e.ResourceID.String()
+	}
+	if e.RemoteAddr != "" {
+		fields[remoteAddrField] = e.RemoteAddr
+	}
+	if e.Request != "" {
+		fields[requestField] = e.Request
+	}
+	if e.Error != "" {
+		fields[errorField] = e.Error
+	}
+
+	return models.NewPoint(measurement, models.NewTags(tags), fields, e.Time)
+}
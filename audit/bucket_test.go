@@ -0,0 +1,81 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestBucketLogger(t *testing.T) {
+	urms := mock.NewUserResourceMappingService()
+	urms.FindMappingsFn = func(ctx context.Context, filter influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+		if filter.UserID != 6 {
+			return nil, 0, nil
+		}
+		ms := []*influxdb.UserResourceMapping{
+			{UserID: 6, UserType: influxdb.Member, ResourceType: influxdb.OrgsResourceType, ResourceID: 20},
+			{UserID: 6, UserType: influxdb.Owner, ResourceType: influxdb.OrgsResourceType, ResourceID: 21},
+		}
+		return ms, len(ms), nil
+	}
+
+	tests := []struct {
+		name   string
+		event  *influxdb.AuditEvent
+		orgIDs []influxdb.ID
+	}{
+		{
+			name: "event of an org",
+			event: &influxdb.AuditEvent{
+				Action:       influxdb.AuditCreate,
+				OrgID:        10,
+				UserID:       6,
+				ResourceType: influxdb.BucketsResourceType,
+				ResourceID:   30,
+			},
+			orgIDs: []influxdb.ID{10},
+		},
+		{
+			name:   "event of a user",
+			event:  &influxdb.AuditEvent{Action: influxdb.AuditLogin, UserID: 6},
+			orgIDs: []influxdb.ID{20, 21},
+		},
+		{
+			name:  "event without org nor user",
+			event: &influxdb.AuditEvent{Action: influxdb.AuditDenied, Request: "GET /api/v2/buckets"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			tt.event.Time = time.Unix(0, 1000)
+			if err := audit.NewBucketLogger(pw, urms).LogAuditEvents(context.Background(), tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			var orgIDs []influxdb.ID
+			for _, p := range pw.Points {
+				org, bucket := tsdb.DecodeNameSlice(p.Name())
+				if bucket != influxdb.AuditSystemBucketID {
+					t.Errorf("point written to bucket %s", bucket)
+				}
+				if len(orgIDs) == 0 || orgIDs[len(orgIDs)-1] != org {
+					orgIDs = append(orgIDs, org)
+				}
+				if !p.Time().Equal(tt.event.Time) {
+					t.Errorf("unexpected time of point: %v", p.Time())
+				}
+			}
+			if diff := cmp.Diff(tt.orgIDs, orgIDs); diff != "" {
+				t.Errorf("unexpected orgs -want/+got\n%s", diff)
+			}
+		})
+	}
+}
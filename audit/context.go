@@ -0,0 +1,30 @@
+package audit
+
+import "context"
+
+type requestContextKey struct{}
+
+// request is an API request whose mutations are recorded.
+type request struct {
+	remoteAddr string
+}
+
+// WithRequest returns a copy of ctx that marks the mutations made with it as
+// made by an API request from the client address remoteAddr, for Store to
+// record them.
+func WithRequest(ctx context.Context, remoteAddr string) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, &request{remoteAddr: remoteAddr})
+}
+
+// RemoteAddr returns the client address of the API request of ctx.
+func RemoteAddr(ctx context.Context) string {
+	if r, ok := requestFromContext(ctx); ok {
+		return r.remoteAddr
+	}
+	return ""
+}
+
+func requestFromContext(ctx context.Context) (*request, bool) {
+	r, ok := ctx.Value(requestContextKey{}).(*request)
+	return r, ok
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+// NewEvent returns an event of action made by the authorizer of ctx, if any,
+// from the client address of the API request of ctx.
+func NewEvent(ctx context.Context, action influxdb.AuditAction) *influxdb.AuditEvent {
+	e := &influxdb.AuditEvent{
+		Time:       time.Now().UTC(),
+		Action:     action,
+		RemoteAddr: RemoteAddr(ctx),
+	}
+
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return e
+	}
+	e.UserID = a.GetUserID()
+	if auth, ok := a.(*influxdb.Authorization); ok {
+		e.AuthorizationID = auth.ID
+		e.OrgID = auth.OrgID
+	}
+	return e
+}
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AuditLogger = (*FileLogger)(nil)
+
+// FileLogger writes audit events as lines of JSON.
+type FileLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewFileLogger returns a FileLogger writing to w.
+func NewFileLogger(w io.Writer) *FileLogger {
+	return &FileLogger{w: w}
+}
+
+// OpenFileLogger returns a FileLogger appending to the file at path, the file
+// is created if it does not exist.
+func OpenFileLogger(path string) (*FileLogger, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileLogger(f), nil
+}
+
+// LogAuditEvents writes events, one per line.
+func (l *FileLogger) LogAuditEvents(ctx context.Context, events ...*influxdb.AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	enc := json.NewEncoder(l.w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the file written to, if any.
+func (l *FileLogger) Close() error {
+	if c, ok := l.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
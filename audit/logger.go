@@ -0,0 +1,40 @@
+// Package audit records the audit log of the API: the resources created,
+// updated and deleted, the requests denied and the sign ins and outs.
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AuditLogger = (*MultiLogger)(nil)
+
+// MultiLogger records audit events with each of its loggers.
+type MultiLogger struct {
+	mu      sync.RWMutex
+	loggers []influxdb.AuditLogger
+}
+
+// Add adds l to the loggers.
+func (m *MultiLogger) Add(l influxdb.AuditLogger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loggers = append(m.loggers, l)
+}
+
+// LogAuditEvents records events with each logger, a logger failing does not
+// prevent the others from recording them. It returns the first error.
+func (m *MultiLogger) LogAuditEvents(ctx context.Context, events ...*influxdb.AuditEvent) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var err error
+	for _, l := range m.loggers {
+		if e := l.LogAuditEvents(ctx, events...); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+// recorder sets the resource and the diff of the event e of the change of the
+// value of key from before to after, it returns false if the change is not
+// recorded.
+type recorder func(e *influxdb.AuditEvent, key, before, after []byte) bool
+
+// recorders are the recorders of the changes of the kv buckets holding audited
+// resources, by name of bucket.
+var recorders = map[string]recorder{
+	"authorizationsv1":       resource(influxdb.AuthorizationsResourceType),
+	"bucketsv1":              resource(influxdb.BucketsResourceType),
+	"checksv1":               resource(influxdb.ChecksResourceType),
+	"dashboardsv2":           resource(influxdb.DashboardsResourceType),
+	"labelsv1":               resource(influxdb.LabelsResourceType),
+	"notificationEndpointv1": resource(influxdb.NotificationEndpointResourceType),
+	"notificationRulev1":     resource(influxdb.NotificationRuleResourceType),
+	"organizationsv1":        resource(influxdb.OrgsResourceType),
+	"rolesv1":                resource(influxdb.RolesResourceType),
+	"scraperv2":              resource(influxdb.ScraperResourceType),
+	"sourcesv1":              resource(influxdb.SourcesResourceType),
+	"tasksv1":                resource(influxdb.TasksResourceType),
+	"telegrafv1":             resource(influxdb.TelegrafsResourceType),
+	"usersv1":                resource(influxdb.UsersResourceType),
+	"variablesv1":            resource(influxdb.VariablesResourceType),
+
+	"userresourcemappingsv1": mapping,
+	"userspasswordv1":        password,
+	"secretsv1":              secret,
+}
+
+// redacted is the value recorded in place of secrets.
+var redacted = json.RawMessage(`"[redacted]"`)
+
+var (
+	// redactedFields are the fields whose values are never recorded.
+	redactedFields = map[string]bool{
+		"token":        true,
+		"tokenHash":    true,
+		"password":     true,
+		"sharedSecret": true,
+	}
+
+	// ignoredFields are the fields updated by the system, updates that only
+	// change them are not recorded.
+	ignoredFields = map[string]bool{
+		"updatedAt":       true,
+		"lastUsedAt":      true,
+		"lastUsedFrom":    true,
+		"latestCompleted": true,
+	}
+)
+
+func action(before, after []byte) influxdb.AuditAction {
+	switch {
+	case before == nil:
+		return influxdb.AuditCreate
+	case after == nil:
+		return influxdb.AuditDelete
+	default:
+		return influxdb.AuditUpdate
+	}
+}
+
+// resource records the changes of resources of type rt stored as JSON by ID.
+func resource(rt influxdb.ResourceType) recorder {
+	return func(e *influxdb.AuditEvent, key, before, after []byte) bool {
+		var id influxdb.ID
+		if err := id.Decode(key); err != nil {
+			return false
+		}
+
+		d, err := diff(before, after)
+		if err != nil || len(d) == 0 {
+			return false
+		}
+
+		e.ResourceType = rt
+		e.ResourceID = id
+		e.Diff = d
+
+		if rt == influxdb.OrgsResourceType {
+			e.OrgID = id
+		} else if orgID, ok := resourceOrgID(before, after); ok {
+			e.OrgID = orgID
+		}
+		return true
+	}
+}
+
+// resourceOrgID returns the organization of a resource from its orgID field.
+func resourceOrgID(before, after []byte) (influxdb.ID, bool) {
+	v := after
+	if v == nil {
+		v = before
+	}
+	var r struct {
+		OrgID *influxdb.ID `json:"orgID"`
+	}
+	if err := json.Unmarshal(v, &r); err != nil || r.OrgID == nil || !r.OrgID.Valid() {
+		return 0, false
+	}
+	return *r.OrgID, true
+}
+
+// mapping records the changes of the members and owners of resources as
+// updates of the resources.
+func mapping(e *influxdb.AuditEvent, key, before, after []byte) bool {
+	v := after
+	if v == nil {
+		v = before
+	}
+	var m influxdb.UserResourceMapping
+	if err := json.Unmarshal(v, &m); err != nil {
+		return false
+	}
+
+	d, err := diff(before, after)
+	if err != nil || len(d) == 0 {
+		return false
+	}
+
+	e.Action = influxdb.AuditUpdate
+	e.ResourceType = m.ResourceType
+	e.ResourceID = m.ResourceID
+	e.Diff = map[string]influxdb.AuditChange{
+		string(m.UserType) + "s/" + m.UserID.String(): {
+			Before: nullable(before),
+			After:  nullable(after),
+		},
+	}
+	if m.ResourceType == influxdb.OrgsResourceType {
+		e.OrgID = m.ResourceID
+	}
+	return true
+}
+
+// password records the changes of the passwords of users as updates of the
+// users.
+func password(e *influxdb.AuditEvent, key, before, after []byte) bool {
+	var id influxdb.ID
+	if err := id.Decode(key); err != nil {
+		return false
+	}
+
+	e.Action = influxdb.AuditUpdate
+	e.ResourceType = influxdb.UsersResourceType
+	e.ResourceID = id
+	e.Diff = map[string]influxdb.AuditChange{
+		"password": {
+			Before: redact(before),
+			After:  redact(after),
+		},
+	}
+	return true
+}
+
+// secret records the changes of the secrets of organizations, keyed by the ID
+// of the organization followed by the key of the secret.
+func secret(e *influxdb.AuditEvent, key, before, after []byte) bool {
+	if len(key) < influxdb.IDLength {
+		return false
+	}
+	var id influxdb.ID
+	if err := id.Decode(key[:influxdb.IDLength]); err != nil {
+		return false
+	}
+
+	e.ResourceType = influxdb.SecretsResourceType
+	e.ResourceID = id
+	e.OrgID = id
+	e.Diff = map[string]influxdb.AuditChange{
+		string(key[influxdb.IDLength:]): {
+			Before: redact(before),
+			After:  redact(after),
+		},
+	}
+	return true
+}
+
+// diff returns the fields of the JSON objects before and after that differ.
+// Either may be nil when the object is created or deleted.
+func diff(before, after []byte) (map[string]influxdb.AuditChange, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	d := make(map[string]influxdb.AuditChange)
+	for k, v := range b {
+		if w, ok := a[k]; ok && bytes.Equal(v, w) {
+			continue
+		}
+		d[k] = influxdb.AuditChange{Before: v, After: a[k]}
+	}
+	for k, w := range a {
+		if _, ok := b[k]; ok {
+			continue
+		}
+		d[k] = influxdb.AuditChange{After: w}
+	}
+
+	for k, c := range d {
+		if ignoredFields[k] && before != nil && after != nil {
+			delete(d, k)
+			continue
+		}
+		if redactedFields[k] {
+			d[k] = influxdb.AuditChange{Before: redact(c.Before), After: redact(c.After)}
+			continue
+		}
+		d[k] = influxdb.AuditChange{Before: redactNested(c.Before), After: redactNested(c.After)}
+	}
+	return d, nil
+}
+
+func fields(v []byte) (map[string]json.RawMessage, error) {
+	m := make(map[string]json.RawMessage)
+	if v == nil {
+		return m, nil
+	}
+	if err := json.Unmarshal(v, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// redact returns the value recorded for the secret v.
+func redact(v []byte) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactNested returns v with the values of the redacted fields of the objects
+// nested in it redacted, such as the tokens of the output plugins of telegraf
+// configs. Values that cannot be decoded are redacted.
+func redactNested(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(v))
+	dec.UseNumber()
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return redacted
+	}
+	if !redactValue(x) {
+		return v
+	}
+	r, err := json.Marshal(x)
+	if err != nil {
+		return redacted
+	}
+	return r
+}
+
+// redactValue redacts the redacted fields of the objects of the decoded JSON
+// value x at any depth, it returns whether a field was redacted.
+func redactValue(x interface{}) bool {
+	var changed bool
+	switch x := x.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			if redactedFields[k] {
+				x[k] = redacted
+				changed = true
+				continue
+			}
+			if redactValue(v) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, v := range x {
+			if redactValue(v) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// nullable returns v as a JSON value, nil if v is nil.
+func nullable(v []byte) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	return json.RawMessage(v)
+}
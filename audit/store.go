@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+var _ kv.Store = (*Store)(nil)
+
+// Store is a kv.Store that records the resources created, updated and deleted
+// by API requests as audit events. Only the transactions made with a context
+// returned by WithRequest are recorded.
+type Store struct {
+	kv.Store
+
+	AuditLogger influxdb.AuditLogger
+	Logger      *zap.Logger
+}
+
+// NewStore returns a Store that records the changes made to s with l.
+func NewStore(s kv.Store, l influxdb.AuditLogger) *Store {
+	return &Store{
+		Store:       s,
+		AuditLogger: l,
+		Logger:      zap.NewNop(),
+	}
+}
+
+// Update opens up a transaction that will mutate data, the changes made by the
+// transaction are recorded once it is committed. Failing to record them does
+// not fail the transaction.
+func (s *Store) Update(ctx context.Context, fn func(kv.Tx) error) error {
+	if _, ok := requestFromContext(ctx); !ok {
+		return s.Store.Update(ctx, fn)
+	}
+
+	var cs *changes
+	err := s.Store.Update(ctx, func(t kv.Tx) error {
+		cs = &changes{index: make(map[string]*change)}
+		return fn(&tx{Tx: t, changes: cs})
+	})
+	if err != nil {
+		return err
+	}
+
+	events := cs.events(ctx)
+	if len(events) == 0 {
+		return nil
+	}
+	if err := s.AuditLogger.LogAuditEvents(ctx, events...); err != nil {
+		s.Logger.Info("Failed to record audit events", zap.Error(err))
+	}
+	return nil
+}
+
+type tx struct {
+	kv.Tx
+	changes *changes
+}
+
+// Bucket returns the bucket b, recording the changes made to it if it holds
+// audited resources.
+func (t *tx) Bucket(b []byte) (kv.Bucket, error) {
+	bkt, err := t.Tx.Bucket(b)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := recorders[string(b)]; !ok {
+		return bkt, nil
+	}
+	return &bucket{Bucket: bkt, name: string(b), changes: t.changes}, nil
+}
+
+type bucket struct {
+	kv.Bucket
+	name    string
+	changes *changes
+}
+
+// Put sets the value of key, recording its value before the first change.
+func (b *bucket) Put(key, value []byte) error {
+	c, err := b.change(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Put(key, value); err != nil {
+		return err
+	}
+	c.after = clone(value)
+	return nil
+}
+
+// Delete deletes key, recording its value before the first change.
+func (b *bucket) Delete(key []byte) error {
+	c, err := b.change(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Delete(key); err != nil {
+		return err
+	}
+	c.after = nil
+	return nil
+}
+
+// change returns the change of key, recording its current value if it has not
+// been changed yet by the transaction.
+func (b *bucket) change(key []byte) (*change, error) {
+	id := b.name + "/" + string(key)
+	if c, ok := b.changes.index[id]; ok {
+		return c, nil
+	}
+
+	v, err := b.Bucket.Get(key)
+	if err != nil && !kv.IsNotFound(err) {
+		return nil, err
+	}
+	c := &change{bucket: b.name, key: clone(key), before: clone(v), after: clone(v)}
+	b.changes.index[id] = c
+	b.changes.list = append(b.changes.list, c)
+	return c, nil
+}
+
+// changes are the changes made by a transaction in the order they were first
+// made.
+type changes struct {
+	index map[string]*change
+	list  []*change
+}
+
+type change struct {
+	bucket        string
+	key           []byte
+	before, after []byte
+}
+
+// events returns the audit events of the changes made by the authorizer of
+// ctx.
+func (cs *changes) events(ctx context.Context) []*influxdb.AuditEvent {
+	var events []*influxdb.AuditEvent
+	for _, c := range cs.list {
+		if c.before == nil && c.after == nil {
+			continue
+		}
+		e := NewEvent(ctx, action(c.before, c.after))
+		if !recorders[c.bucket](e, c.key, c.before, c.after) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func clone(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,268 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+func newService(t *testing.T) (*kv.Service, *mock.AuditLogger) {
+	t.Helper()
+
+	l := mock.NewAuditLogger()
+	svc := kv.NewService(audit.NewStore(inmem.NewKVStore(), l))
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return svc, l
+}
+
+var eventCmpOptions = cmp.Options{
+	cmpopts.IgnoreFields(influxdb.AuditEvent{}, "Time"),
+	cmp.Transformer("JSON", func(m json.RawMessage) string { return string(m) }),
+}
+
+func TestStore(t *testing.T) {
+	svc, l := newService(t)
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(context.Background(), org); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Events) != 0 {
+		t.Fatalf("recorded changes made outside of a request: %+v", l.Events)
+	}
+
+	a := &influxdb.Authorization{ID: 5, UserID: 6, OrgID: org.ID, Status: influxdb.Active}
+	ctx := icontext.SetAuthorizer(audit.WithRequest(context.Background(), "10.0.0.1"), a)
+
+	b := &influxdb.Bucket{OrgID: org.ID, Name: "telegraf"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	desc := "metrics of the hosts"
+	if _, err := svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{Description: &desc}); err != nil {
+		t.Fatal(err)
+	}
+	// Updates that do not change the bucket are not recorded.
+	if _, err := svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{Description: &desc}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(l.Events), l.Events)
+	}
+
+	event := func(action influxdb.AuditAction) *influxdb.AuditEvent {
+		return &influxdb.AuditEvent{
+			Action:          action,
+			OrgID:           org.ID,
+			UserID:          6,
+			AuthorizationID: 5,
+			RemoteAddr:      "10.0.0.1",
+			ResourceType:    influxdb.BucketsResourceType,
+			ResourceID:      b.ID,
+		}
+	}
+
+	update := event(influxdb.AuditUpdate)
+	update.Diff = map[string]influxdb.AuditChange{
+		"description": {Before: json.RawMessage(`""`), After: json.RawMessage(`"metrics of the hosts"`)},
+	}
+	if diff := cmp.Diff(update, l.Events[1], eventCmpOptions...); diff != "" {
+		t.Errorf("unexpected update event -want/+got\n%s", diff)
+	}
+
+	for i, action := range []influxdb.AuditAction{influxdb.AuditCreate, influxdb.AuditUpdate, influxdb.AuditDelete} {
+		e := l.Events[i]
+		if e.Action != action || e.ResourceID != b.ID || e.Time.IsZero() {
+			t.Errorf("unexpected event %d: %+v", i, e)
+		}
+	}
+	if got := string(l.Events[0].Diff["name"].After); got != `"telegraf"` {
+		t.Errorf("unexpected name of created bucket: %s", got)
+	}
+	if got := string(l.Events[2].Diff["name"].Before); got != `"telegraf"` {
+		t.Errorf("unexpected name of deleted bucket: %s", got)
+	}
+}
+
+func TestStore_Secrets(t *testing.T) {
+	svc, l := newService(t)
+
+	user := &influxdb.User{Name: "jane"}
+	if err := svc.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := icontext.SetAuthorizer(audit.WithRequest(context.Background(), "10.0.0.1"), &influxdb.Session{UserID: user.ID})
+	if err := svc.SetPassword(ctx, user.Name, "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(l.Events), l.Events)
+	}
+	want := &influxdb.AuditEvent{
+		Action:       influxdb.AuditUpdate,
+		UserID:       user.ID,
+		RemoteAddr:   "10.0.0.1",
+		ResourceType: influxdb.UsersResourceType,
+		ResourceID:   user.ID,
+		Diff: map[string]influxdb.AuditChange{
+			"password": {After: json.RawMessage(`"[redacted]"`)},
+		},
+	}
+	if diff := cmp.Diff(want, l.Events[0], eventCmpOptions...); diff != "" {
+		t.Errorf("unexpected event -want/+got\n%s", diff)
+	}
+}
+
+func TestStore_SourceCredentials(t *testing.T) {
+	svc, l := newService(t)
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(context.Background(), org); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &influxdb.Authorization{ID: 5, UserID: 6, OrgID: org.ID, Status: influxdb.Active}
+	ctx := icontext.SetAuthorizer(audit.WithRequest(context.Background(), "10.0.0.1"), a)
+
+	src := &influxdb.Source{
+		OrganizationID: org.ID,
+		Name:           "v1",
+		V1SourceFields: influxdb.V1SourceFields{
+			Username:     "admin",
+			Password:     "howdydoody",
+			SharedSecret: "shhh",
+		},
+	}
+	if err := svc.CreateSource(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	password := "doodyhowdy"
+	if _, err := svc.UpdateSource(ctx, src.ID, influxdb.SourceUpdate{Password: &password}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(l.Events), l.Events)
+	}
+	create := l.Events[0].Diff
+	for _, k := range []string{"password", "sharedSecret"} {
+		if got := string(create[k].After); got != `"[redacted]"` {
+			t.Errorf("unexpected %s of created source: %s", k, got)
+		}
+	}
+	if got := string(create["username"].After); got != `"admin"` {
+		t.Errorf("unexpected username of created source: %s", got)
+	}
+	want := influxdb.AuditChange{Before: json.RawMessage(`"[redacted]"`), After: json.RawMessage(`"[redacted]"`)}
+	if diff := cmp.Diff(want, l.Events[1].Diff["password"], eventCmpOptions...); diff != "" {
+		t.Errorf("unexpected password change -want/+got\n%s", diff)
+	}
+}
+
+func TestStore_TelegrafToken(t *testing.T) {
+	svc, l := newService(t)
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(context.Background(), org); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &influxdb.Authorization{ID: 5, UserID: 6, OrgID: org.ID, Status: influxdb.Active}
+	ctx := icontext.SetAuthorizer(audit.WithRequest(context.Background(), "10.0.0.1"), a)
+
+	output := &outputs.InfluxDBV2{
+		URLs:         []string{"http://127.0.0.1:9999"},
+		Token:        "sekret-token",
+		Organization: "org",
+		Bucket:       "bucket",
+	}
+	tc := &influxdb.TelegrafConfig{
+		OrgID: org.ID,
+		Name:  "hosts",
+		Agent: influxdb.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []influxdb.TelegrafPlugin{
+			{Config: &inputs.CPUStats{}},
+			{Config: output},
+		},
+	}
+	if err := svc.CreateTelegrafConfig(ctx, tc, a.UserID); err != nil {
+		t.Fatal(err)
+	}
+	output.Token = "other-token"
+	if _, err := svc.UpdateTelegrafConfig(ctx, tc.ID, tc, a.UserID); err != nil {
+		t.Fatal(err)
+	}
+
+	var plugins []json.RawMessage
+	for _, e := range l.Events {
+		if c, ok := e.Diff["plugins"]; ok {
+			plugins = append(plugins, c.After)
+		}
+		for k, c := range e.Diff {
+			for _, v := range []json.RawMessage{c.Before, c.After} {
+				if strings.Contains(string(v), "sekret-token") || strings.Contains(string(v), "other-token") {
+					t.Errorf("recorded the token of the output in %s of the %s event: %s", k, e.Action, v)
+				}
+			}
+		}
+	}
+	if len(plugins) != 2 {
+		t.Fatalf("expected 2 changes of the plugins, got %d", len(plugins))
+	}
+	for _, p := range plugins {
+		if !strings.Contains(string(p), `"token":"[redacted]"`) {
+			t.Errorf("expected the token of the output to be redacted: %s", p)
+		}
+	}
+}
+
+func TestStore_Members(t *testing.T) {
+	svc, l := newService(t)
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(context.Background(), org); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := audit.WithRequest(context.Background(), "10.0.0.1")
+	m := &influxdb.UserResourceMapping{
+		UserID:       6,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(l.Events), l.Events)
+	}
+	e := l.Events[0]
+	if e.Action != influxdb.AuditUpdate || e.ResourceType != influxdb.OrgsResourceType || e.ResourceID != org.ID || e.OrgID != org.ID {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if c, ok := e.Diff["members/0000000000000006"]; !ok || c.Before != nil || c.After == nil {
+		t.Errorf("unexpected diff: %+v", e.Diff)
+	}
+}
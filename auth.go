@@ -2,7 +2,10 @@ package influxdb
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -14,8 +17,19 @@ var (
 		Msg:  "unable to create token",
 		Code: EInvalid,
 	}
+
+	// ErrAuthorizationExpired is returned when an expired authorization is used.
+	ErrAuthorizationExpired = &Error{
+		Msg:  "authorization has expired",
+		Code: EUnauthorized,
+	}
 )
 
+// AuthorizationUsageInterval is the minimum time between two records of the use
+// of an authorization, so that authenticating requests does not write to the
+// store every time.
+var AuthorizationUsageInterval = time.Minute
+
 // Authorization is an authorization. 🎉
 type Authorization struct {
 	ID          ID           `json:"id"`
@@ -25,6 +39,18 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+
+	// RoleIDs are the roles of the organization of the authorization whose
+	// permissions are granted in addition to Permissions.
+	RoleIDs []ID `json:"roleIDs,omitempty"`
+
+	// ExpiresAt is the time after which the authorization can no longer be used.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// LastUsedAt and LastUsedFrom are the time and the client address of the
+	// last use of the authorization, recorded at most every AuthorizationUsageInterval.
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedFrom string     `json:"lastUsedFrom,omitempty"`
 }
 
 // AuthorizationUpdate is the authorization update request.
@@ -67,6 +93,11 @@ func (a *Authorization) IsActive() bool {
 	return a.Status == Active
 }
 
+// IsExpired returns true if the authorization has expired at t.
+func (a *Authorization) IsExpired(t time.Time) bool {
+	return a.ExpiresAt != nil && !t.Before(*a.ExpiresAt)
+}
+
 // GetUserID returns the user id.
 func (a *Authorization) GetUserID() ID {
 	return a.UserID
@@ -86,6 +117,7 @@ const (
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpUpdateAuthorization      = "UpdateAuthorization"
 	OpDeleteAuthorization      = "DeleteAuthorization"
+	OpRecordAuthorizationUsage = "RecordAuthorizationUsage"
 )
 
 // AuthorizationService represents a service for managing authorization data.
@@ -110,6 +142,19 @@ type AuthorizationService interface {
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationUsageRecorder records the use of authorizations.
+type AuthorizationUsageRecorder interface {
+	// RecordAuthorizationUsage sets the time and the client address of the last use of an authorization.
+	RecordAuthorizationUsage(ctx context.Context, id ID, at time.Time, from string) error
+}
+
+// HashToken returns the hash of a token. Tokens are not persisted, authorizations
+// are stored and looked up by the hash of their token instead.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
@@ -115,7 +115,8 @@ func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter in
 	return authorizations, len(authorizations), nil
 }
 
-// CreateAuthorization checks to see if the authorizer on context has write access to the global authorizations resource.
+// CreateAuthorization checks to see if the authorizer on context has write access to the global authorizations resource,
+// and to the roles of the organization if the authorization references roles.
 func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
 		return err
@@ -125,6 +126,20 @@ func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influ
 		return err
 	}
 
+	if len(a.RoleIDs) > 0 {
+		p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.RolesResourceType, a.OrgID)
+		if err != nil {
+			return err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return &influxdb.Error{
+				Err:  err,
+				Msg:  "roles are not allowed",
+				Code: influxdb.EForbidden,
+			}
+		}
+	}
+
 	return s.s.CreateAuthorization(ctx, a)
 }
 
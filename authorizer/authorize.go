@@ -9,14 +9,15 @@ import (
 )
 
 // IsAllowed checks to see if an action is authorized by retrieving the authorizer
-// off of context and authorizing the action appropriately.
+// off of context and authorizing the action appropriately, with the roles
+// resolved on context.
 func IsAllowed(ctx context.Context, p influxdb.Permission) error {
 	a, err := influxdbcontext.GetAuthorizer(ctx)
 	if err != nil {
 		return err
 	}
 
-	if !a.Allowed(p) {
+	if !Allowed(ctx, a, p) {
 		return &influxdb.Error{
 			Code: influxdb.EUnauthorized,
 			Msg:  fmt.Sprintf("%s is unauthorized", p),
@@ -0,0 +1,136 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+var _ influxdb.KVBackupService = (*KVBackupService)(nil)
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+
+// authorizeBackup ensures the authorizer on context can read every resource on
+// the server. A backup always contains the complete key-value store, so even a
+// partial backup discloses every resource.
+func authorizeBackup(ctx context.Context) error {
+	for _, rt := range influxdb.AllResourceTypes {
+		p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, rt)
+		if err != nil {
+			return err
+		}
+
+		if err := IsAllowed(ctx, *p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BackupService wraps a influxdb.BackupService and authorizes actions
+// against it appropriately.
+type BackupService struct {
+	s influxdb.BackupService
+}
+
+// NewBackupService constructs an instance of an authorizing backup service.
+func NewBackupService(s influxdb.BackupService) *BackupService {
+	return &BackupService{
+		s: s,
+	}
+}
+
+// CreateBackup checks to see if the authorizer on context has read access to all resources.
+func (s *BackupService) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.Backup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeBackup(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.s.CreateBackup(ctx, filter)
+}
+
+// FetchBackupFile checks to see if the authorizer on context has read access to all resources.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeBackup(ctx); err != nil {
+		return err
+	}
+
+	return s.s.FetchBackupFile(ctx, backupID, backupFile, w)
+}
+
+// DeleteBackup checks to see if the authorizer on context has read access to all resources.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeBackup(ctx); err != nil {
+		return err
+	}
+
+	return s.s.DeleteBackup(ctx, backupID)
+}
+
+// InternalBackupPath returns the local path of the backup. It is not authorized
+// as it does not read any data.
+func (s *BackupService) InternalBackupPath(backupID int) string {
+	return s.s.InternalBackupPath(backupID)
+}
+
+// KVBackupService wraps a influxdb.KVBackupService and authorizes actions
+// against it appropriately.
+type KVBackupService struct {
+	s influxdb.KVBackupService
+}
+
+// NewKVBackupService constructs an instance of an authorizing kv backup service.
+func NewKVBackupService(s influxdb.KVBackupService) *KVBackupService {
+	return &KVBackupService{
+		s: s,
+	}
+}
+
+// Backup checks to see if the authorizer on context has read access to all resources.
+func (s *KVBackupService) Backup(ctx context.Context, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeBackup(ctx); err != nil {
+		return err
+	}
+
+	return s.s.Backup(ctx, w)
+}
+
+// RestoreService wraps a influxdb.RestoreService and authorizes actions
+// against it appropriately.
+type RestoreService struct {
+	s influxdb.RestoreService
+}
+
+// NewRestoreService constructs an instance of an authorizing restore service.
+func NewRestoreService(s influxdb.RestoreService) *RestoreService {
+	return &RestoreService{
+		s: s,
+	}
+}
+
+// RestoreBucket checks to see if the authorizer on context has write access to the bucket provided.
+func (s *RestoreService) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, r io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, orgID, bucketID); err != nil {
+		return err
+	}
+
+	return s.s.RestoreBucket(ctx, orgID, bucketID, r)
+}
@@ -0,0 +1,132 @@
+package authorizer_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBackupService_CreateBackup(t *testing.T) {
+	type args struct {
+		permissions []influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to backup with operator permissions",
+			args: args{
+				permissions: influxdb.OperPermissions(),
+			},
+		},
+		{
+			name: "unauthorized to backup with owner permissions",
+			args: args{
+				permissions: influxdb.OwnerPermissions(10),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:authorizations is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBackupService(mock.NewBackupService())
+			kvs := authorizer.NewKVBackupService(mock.NewKVBackupService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			_, err := s.CreateBackup(ctx, influxdb.BackupFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			err = s.FetchBackupFile(ctx, 1, "000000001-000000001.tsm", &bytes.Buffer{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			err = kvs.Backup(ctx, &bytes.Buffer{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestRestoreService_RestoreBucket(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		orgID      influxdb.ID
+		bucketID   influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to restore to bucket",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.WriteAction,
+					Resource: influxdb.Resource{
+						Type:  influxdb.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+				},
+				orgID:    10,
+				bucketID: 1,
+			},
+		},
+		{
+			name: "unauthorized to restore to another bucket",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.WriteAction,
+					Resource: influxdb.Resource{
+						Type:  influxdb.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+						ID:    influxdbtesting.IDPtr(2),
+					},
+				},
+				orgID:    10,
+				bucketID: 1,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRestoreService(mock.NewRestoreService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			err := s.RestoreBucket(ctx, tt.args.orgID, tt.args.bucketID, &bytes.Buffer{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
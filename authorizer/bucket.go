@@ -140,9 +140,36 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 		return err
 	}
 
+	if err := authorizeDownsampleTiers(ctx, b.OrgID, b.DownsampleTiers); err != nil {
+		return err
+	}
+
 	return s.s.CreateBucket(ctx, b)
 }
 
+// authorizeDownsampleTiers checks to see if the authorizer on context can write
+// to the target buckets of tiers and create the tasks that write to them.
+func authorizeDownsampleTiers(ctx context.Context, orgID influxdb.ID, tiers []influxdb.DownsampleTier) error {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.TasksResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	for _, t := range tiers {
+		if err := authorizeWriteBucket(ctx, orgID, t.TargetBucketID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdateBucket checks to see if the authorizer on context has write access to the bucket provided.
 func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
 	b, err := s.s.FindBucketByID(ctx, id)
@@ -154,6 +181,12 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 		return nil, err
 	}
 
+	if upd.DownsampleTiers != nil {
+		if err := authorizeDownsampleTiers(ctx, b.OrgID, *upd.DownsampleTiers); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.s.UpdateBucket(ctx, id, upd)
 }
 
@@ -0,0 +1,123 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+var _ influxdb.MeasurementSchemaReader = (*MeasurementSchemaReader)(nil)
+
+// BucketSchemaService wraps a influxdb.BucketSchemaService and authorizes actions
+// against it appropriately. A measurement schema is authorized as its bucket.
+type BucketSchemaService struct {
+	s influxdb.BucketSchemaService
+}
+
+// NewBucketSchemaService constructs an instance of an authorizing bucket schema service.
+func NewBucketSchemaService(s influxdb.BucketSchemaService) *BucketSchemaService {
+	return &BucketSchemaService{
+		s: s,
+	}
+}
+
+// FindMeasurementSchemaByID checks to see if the authorizer on context has read access to the bucket of the schema.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ms, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, ms.OrgID, ms.BucketID); err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// FindMeasurementSchemas retrieves all schemas that match the provided filter and then filters the list down to only the
+// schemas of buckets that are authorized.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, err := s.s.FindMeasurementSchemas(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := ss[:0]
+	for _, ms := range ss {
+		err := authorizeReadBucket(ctx, ms.OrgID, ms.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		schemas = append(schemas, ms)
+	}
+
+	return schemas, nil
+}
+
+// CreateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, ms *influxdb.MeasurementSchema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, ms.OrgID, ms.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateMeasurementSchema(ctx, ms)
+}
+
+// UpdateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ms, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, ms.OrgID, ms.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+}
+
+// MeasurementSchemaReader wraps a influxdb.MeasurementSchemaReader and authorizes actions
+// against it appropriately.
+type MeasurementSchemaReader struct {
+	s influxdb.MeasurementSchemaReader
+}
+
+// NewMeasurementSchemaReader constructs an instance of an authorizing measurement schema reader.
+func NewMeasurementSchemaReader(s influxdb.MeasurementSchemaReader) *MeasurementSchemaReader {
+	return &MeasurementSchemaReader{
+		s: s,
+	}
+}
+
+// ReadMeasurementSchemas checks to see if the authorizer on context has read access to the bucket provided.
+func (s *MeasurementSchemaReader) ReadMeasurementSchemas(ctx context.Context, orgID, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.ReadMeasurementSchemas(ctx, orgID, bucketID)
+}
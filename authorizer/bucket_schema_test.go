@@ -0,0 +1,129 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBucketSchemaService_FindMeasurementSchemas(t *testing.T) {
+	type wants struct {
+		err     error
+		schemas []*influxdb.MeasurementSchema
+	}
+
+	schemas := []*influxdb.MeasurementSchema{
+		{ID: 1, OrgID: 10, BucketID: 1, Name: "cpu"},
+		{ID: 2, OrgID: 10, BucketID: 2, Name: "mem"},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wants      wants
+	}{
+		{
+			name: "authorized to read all buckets in org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			wants: wants{
+				schemas: schemas,
+			},
+		},
+		{
+			name: "authorized to read one bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(2),
+				},
+			},
+			wants: wants{
+				schemas: schemas[1:],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewBucketSchemaService()
+			m.FindMeasurementSchemasF = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+				return append([]*influxdb.MeasurementSchema(nil), schemas...), nil
+			}
+			s := authorizer.NewBucketSchemaService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			got, err := s.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			if diff := cmp.Diff(got, tt.wants.schemas); diff != "" {
+				t.Errorf("schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestBucketSchemaService_UpdateMeasurementSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write to bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized with read permission",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewBucketSchemaService()
+			m.FindMeasurementSchemaByIDF = func(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+				return &influxdb.MeasurementSchema{ID: id, OrgID: 10, BucketID: 1, Name: "cpu"}, nil
+			}
+			s := authorizer.NewBucketSchemaService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.UpdateMeasurementSchema(ctx, 1, 1, influxdb.MeasurementSchemaUpdate{})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -626,3 +626,66 @@ func TestBucketService_CreateBucket(t *testing.T) {
 		})
 	}
 }
+
+func TestBucketService_CreateBucketDownsampleTiers(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to create tier tasks",
+			permissions: []influxdb.Permission{
+				{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+				{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.TasksResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized to create tier tasks",
+			permissions: []influxdb.Permission{
+				{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketService(&mock.BucketService{
+				CreateBucketFn: func(ctx context.Context, b *influxdb.Bucket) error {
+					return nil
+				},
+			})
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.CreateBucket(ctx, &influxdb.Bucket{
+				OrgID:           10,
+				DownsampleTiers: []influxdb.DownsampleTier{{TargetBucketID: 2}},
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDBRPMappingService_FindBy(t *testing.T) {
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wants      wants
+	}{
+		{
+			name: "authorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(3),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/0000000000000001/buckets/0000000000000002 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewDBRPMappingService()
+			svc.FindByFn = func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+				return &influxdb.DBRPMapping{Cluster: cluster, Database: db, RetentionPolicy: rp, OrganizationID: 1, BucketID: 2}, nil
+			}
+			s := authorizer.NewDBRPMappingService(svc)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.FindBy(ctx, "local", "db", "rp")
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestDBRPMappingService_FindMany(t *testing.T) {
+	svc := mock.NewDBRPMappingService()
+	svc.FindManyFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+		return []*influxdb.DBRPMapping{
+			{Cluster: "local", Database: "db1", RetentionPolicy: "rp", OrganizationID: 1, BucketID: 2},
+			{Cluster: "local", Database: "db2", RetentionPolicy: "rp", OrganizationID: 1, BucketID: 3},
+		}, 2, nil
+	}
+	s := authorizer.NewDBRPMappingService(svc)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type: influxdb.BucketsResourceType,
+			ID:   influxdbtesting.IDPtr(3),
+		},
+	}}})
+
+	ms, n, err := s.FindMany(ctx, influxdb.DBRPMappingFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*influxdb.DBRPMapping{
+		{Cluster: "local", Database: "db2", RetentionPolicy: "rp", OrganizationID: 1, BucketID: 3},
+	}
+	if diff := cmp.Diff(ms, exp); diff != "" {
+		t.Errorf("unexpected mappings -got/+want\n%s", diff)
+	}
+	if n != 1 {
+		t.Errorf("unexpected count: got %d, exp 1", n)
+	}
+}
+
+func TestDBRPMappingService_Create(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/0000000000000001/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(mock.NewDBRPMappingService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.Create(ctx, &influxdb.DBRPMapping{Cluster: "local", Database: "db", RetentionPolicy: "rp", OrganizationID: 1, BucketID: 2})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.DeleteService = (*DeleteService)(nil)
+
+// DeleteService wraps a influxdb.DeleteService and authorizes actions
+// against it appropriately.
+type DeleteService struct {
+	s influxdb.DeleteService
+}
+
+// NewDeleteService constructs an instance of an authorizing delete service.
+func NewDeleteService(s influxdb.DeleteService) *DeleteService {
+	return &DeleteService{
+		s: s,
+	}
+}
+
+// DeleteBucketRangePredicate checks to see if the authorizer on context has write access to the bucket provided.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, orgID, bucketID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred)
+}
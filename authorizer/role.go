@@ -0,0 +1,186 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService wraps a influxdb.RoleService and authorizes actions
+// against it appropriately.
+type RoleService struct {
+	s influxdb.RoleService
+}
+
+// NewRoleService constructs an instance of an authorizing role service.
+func NewRoleService(s influxdb.RoleService) *RoleService {
+	return &RoleService{
+		s: s,
+	}
+}
+
+func newRolePermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.RolesResourceType, orgID)
+}
+
+func authorizeReadRole(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRolePermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteRole(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRolePermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRoleByID checks to see if the authorizer on context has read access to the id provided.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadRole(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindRoles retrieves all roles that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
+	// will likely be expensive.
+	rs, _, err := s.s.FindRoles(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	roles := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadRole(ctx, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		roles = append(roles, r)
+	}
+
+	return roles, len(roles), nil
+}
+
+// CreateRole checks to see if the authorizer on context has write access to the roles of the organization of the role.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.RolesResourceType, r.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateRole(ctx, r)
+}
+
+// UpdateRole checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateRole(ctx, id, upd)
+}
+
+// DeleteRole checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRole(ctx, id)
+}
+
+// AddRoleMember checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) AddRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, roleID); err != nil {
+		return err
+	}
+
+	return s.s.AddRoleMember(ctx, roleID, userID)
+}
+
+// RemoveRoleMember checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) RemoveRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, roleID); err != nil {
+		return err
+	}
+
+	return s.s.RemoveRoleMember(ctx, roleID, userID)
+}
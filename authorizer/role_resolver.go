@@ -0,0 +1,222 @@
+package authorizer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+type rolesContextKey struct{}
+
+// RoleResolver resolves the roles of an authorizer when a request is
+// authenticated. The roles of an authorization are the roles it references,
+// the roles of a session are the roles of the user in the organizations it is
+// a member or an owner of.
+type RoleResolver struct {
+	RoleService                influxdb.RoleService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	LabelService               influxdb.LabelService
+}
+
+// Resolve returns a copy of ctx with the roles of a, for Allowed to grant the
+// permissions of the roles in addition to the permissions of a.
+func (r *RoleResolver) Resolve(ctx context.Context, a influxdb.Authorizer) (context.Context, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var roles []*influxdb.Role
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		rs, err := r.findRoles(ctx, a.OrgID, a.RoleIDs)
+		if err != nil {
+			return ctx, err
+		}
+		roles = rs
+	case *influxdb.Session:
+		ms, _, err := r.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+			UserID:       a.UserID,
+			ResourceType: influxdb.OrgsResourceType,
+		})
+		if err != nil {
+			return ctx, err
+		}
+		for _, m := range ms {
+			rs, err := r.findRoles(ctx, m.ResourceID, m.RoleIDs)
+			if err != nil {
+				return ctx, err
+			}
+			roles = append(roles, rs...)
+		}
+	}
+
+	if len(roles) == 0 {
+		return ctx, nil
+	}
+
+	return context.WithValue(ctx, rolesContextKey{}, &roleSet{
+		kind:   a.Kind(),
+		id:     a.Identifier(),
+		roles:  roles,
+		labels: r.LabelService,
+		cache:  make(map[influxdb.ID]map[string]bool),
+	}), nil
+}
+
+// findRoles returns the roles of ids that belong to the organization, roles
+// that have been deleted are skipped.
+func (r *RoleResolver) findRoles(ctx context.Context, orgID influxdb.ID, ids []influxdb.ID) ([]*influxdb.Role, error) {
+	var roles []*influxdb.Role
+	for _, id := range ids {
+		role, err := r.RoleService.FindRoleByID(ctx, id)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if role.OrgID != orgID {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// roleSet holds the roles of the authorizer of a request and caches the labels
+// of the resources its label selectors are matched with.
+type roleSet struct {
+	kind   string
+	id     influxdb.ID
+	roles  []*influxdb.Role
+	labels influxdb.LabelService
+
+	mu    sync.Mutex
+	cache map[influxdb.ID]map[string]bool
+}
+
+func (s *roleSet) allowed(ctx context.Context, p influxdb.Permission) bool {
+	for _, r := range s.roles {
+		for _, rp := range r.Permissions {
+			if s.matches(ctx, r.OrgID, rp, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches returns whether the permission rp of a role of the organization
+// orgID grants p.
+func (s *roleSet) matches(ctx context.Context, orgID influxdb.ID, rp influxdb.RolePermission, p influxdb.Permission) bool {
+	if rp.Action != p.Action || rp.Resource.Type != p.Resource.Type {
+		return false
+	}
+
+	// Roles only grant permissions on the resources of their organization.
+	if p.Resource.Type == influxdb.OrgsResourceType {
+		if p.Resource.ID == nil || *p.Resource.ID != orgID {
+			return false
+		}
+	} else if p.Resource.OrgID == nil || *p.Resource.OrgID != orgID {
+		return false
+	}
+
+	if rp.Resource.ID != nil {
+		return p.Resource.ID != nil && *p.Resource.ID == *rp.Resource.ID
+	}
+
+	if len(rp.Resource.Labels) > 0 {
+		if p.Resource.ID == nil {
+			return false
+		}
+		labels := s.resourceLabels(ctx, p.Resource.Type, *p.Resource.ID)
+		for _, l := range rp.Resource.Labels {
+			if !labels[l] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// resourceLabels returns the set of the names of the labels of a resource.
+// Resources whose labels cannot be found have no labels.
+func (s *roleSet) resourceLabels(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if labels, ok := s.cache[id]; ok {
+		return labels
+	}
+
+	labels := make(map[string]bool)
+	if s.labels != nil {
+		ls, err := s.labels.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+			ResourceID:   id,
+			ResourceType: rt,
+		})
+		if err == nil {
+			for _, l := range ls {
+				labels[l.Name] = true
+			}
+		}
+	}
+	s.cache[id] = labels
+	return labels
+}
+
+// Allowed returns whether the authorizer a is allowed p by its permissions or
+// by the permissions of the roles resolved on ctx. Roles only grant
+// permissions to active authorizations and unexpired sessions.
+func Allowed(ctx context.Context, a influxdb.Authorizer, p influxdb.Permission) bool {
+	if a.Allowed(p) {
+		return true
+	}
+
+	// The roles are only granted to the authorizer they were resolved for,
+	// not to an authorizer set on context later on.
+	s, ok := ctx.Value(rolesContextKey{}).(*roleSet)
+	if !ok || s.kind != a.Kind() || s.id != a.Identifier() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		if !a.IsActive() {
+			return false
+		}
+	case *influxdb.Session:
+		if a.Expired() != nil {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return s.allowed(ctx, p)
+}
+
+// WithRoles returns an authorizer that is allowed the permissions a is allowed
+// with the roles resolved on ctx, for the checks that are made without a
+// context, such as those of the document options.
+func WithRoles(ctx context.Context, a influxdb.Authorizer) influxdb.Authorizer {
+	return &roleAuthorizer{Authorizer: a, ctx: ctx}
+}
+
+type roleAuthorizer struct {
+	influxdb.Authorizer
+	ctx context.Context
+}
+
+// Allowed returns whether the wrapped authorizer or its roles are allowed p.
+func (a *roleAuthorizer) Allowed(p influxdb.Permission) bool {
+	return Allowed(a.ctx, a.Authorizer, p)
+}
+
+// Unwrap returns the wrapped authorizer.
+func (a *roleAuthorizer) Unwrap() influxdb.Authorizer {
+	return a.Authorizer
+}
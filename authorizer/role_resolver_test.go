@@ -0,0 +1,262 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newRoleResolver(roles ...*influxdb.Role) *authorizer.RoleResolver {
+	rs := mock.NewRoleService()
+	rs.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+		for _, r := range roles {
+			if r.ID == id {
+				return r, nil
+			}
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrRoleNotFound}
+	}
+
+	ls := mock.NewLabelService()
+	ls.FindResourceLabelsFn = func(ctx context.Context, filter influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+		if filter.ResourceID == 100 {
+			return []*influxdb.Label{{Name: "team=infra"}, {Name: "env=prod"}}, nil
+		}
+		return []*influxdb.Label{{Name: "team=web"}}, nil
+	}
+
+	urms := mock.NewUserResourceMappingService()
+	urms.FindMappingsFn = func(ctx context.Context, filter influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+		ms := []*influxdb.UserResourceMapping{
+			{UserID: 2, UserType: influxdb.Member, ResourceType: influxdb.OrgsResourceType, ResourceID: 10, RoleIDs: []influxdb.ID{1}},
+			// The role of another org is not granted.
+			{UserID: 2, UserType: influxdb.Member, ResourceType: influxdb.OrgsResourceType, ResourceID: 11, RoleIDs: []influxdb.ID{2}},
+		}
+		return ms, len(ms), nil
+	}
+
+	return &authorizer.RoleResolver{
+		RoleService:                rs,
+		UserResourceMappingService: urms,
+		LabelService:               ls,
+	}
+}
+
+func TestRoleResolver_Allowed(t *testing.T) {
+	writer := &influxdb.Role{
+		ID:    1,
+		OrgID: 10,
+		Name:  "bucket-writer",
+		Permissions: []influxdb.RolePermission{
+			{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType, Labels: []string{"team=infra"}},
+			},
+		},
+	}
+	viewer := &influxdb.Role{
+		ID:    2,
+		OrgID: 10,
+		Name:  "dashboard-viewer",
+		Permissions: []influxdb.RolePermission{
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.DashboardsResourceType}},
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.OrgsResourceType}},
+		},
+	}
+
+	bucket := func(a influxdb.Action, orgID, id influxdb.ID) influxdb.Permission {
+		return influxdb.Permission{
+			Action: a,
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				OrgID: influxdbtesting.IDPtr(orgID),
+				ID:    influxdbtesting.IDPtr(id),
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		authorizer influxdb.Authorizer
+		permission influxdb.Permission
+		allowed    bool
+	}{
+		{
+			name:       "labelled bucket",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active},
+			permission: bucket(influxdb.WriteAction, 10, 100),
+			allowed:    true,
+		},
+		{
+			name:       "bucket without the label",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active},
+			permission: bucket(influxdb.WriteAction, 10, 101),
+		},
+		{
+			name:       "other action",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active},
+			permission: bucket(influxdb.ReadAction, 10, 100),
+		},
+		{
+			name:       "other org",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active},
+			permission: bucket(influxdb.WriteAction, 11, 100),
+		},
+		{
+			name:       "inactive authorization",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Inactive},
+			permission: bucket(influxdb.WriteAction, 10, 100),
+		},
+		{
+			name:       "role of another org of authorization",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 11, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active},
+			permission: bucket(influxdb.WriteAction, 10, 100),
+		},
+		{
+			name:       "role of session user",
+			authorizer: &influxdb.Session{ID: 6, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+			permission: bucket(influxdb.WriteAction, 10, 100),
+			allowed:    true,
+		},
+		{
+			name:       "role of session user in another org",
+			authorizer: &influxdb.Session{ID: 6, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			},
+		},
+		{
+			name:       "all resources of a type",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{2}, Status: influxdb.Active},
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			},
+			allowed: true,
+		},
+		{
+			name:       "org of role",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{2}, Status: influxdb.Active},
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(10)},
+			},
+			allowed: true,
+		},
+		{
+			name:       "other org than org of role",
+			authorizer: &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{2}, Status: influxdb.Active},
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(11)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := newRoleResolver(writer, viewer).Resolve(context.Background(), tt.authorizer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := authorizer.Allowed(ctx, tt.authorizer, tt.permission); got != tt.allowed {
+				t.Fatalf("Allowed() = %v, want %v", got, tt.allowed)
+			}
+
+			ctx = influxdbcontext.SetAuthorizer(ctx, tt.authorizer)
+			if err := authorizer.IsAllowed(ctx, tt.permission); (err == nil) != tt.allowed {
+				t.Fatalf("IsAllowed() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRoleResolver_OtherAuthorizer(t *testing.T) {
+	r := &influxdb.Role{
+		ID:    1,
+		OrgID: 10,
+		Name:  "dashboard-viewer",
+		Permissions: []influxdb.RolePermission{
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.DashboardsResourceType}},
+		},
+	}
+	p := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+	}
+
+	a := &influxdb.Authorization{ID: 5, OrgID: 10, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active}
+	ctx, err := newRoleResolver(r).Resolve(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The roles are not granted to another authorizer set on the context.
+	other := &influxdb.Authorization{ID: 6, OrgID: 10, Status: influxdb.Active}
+	if authorizer.Allowed(ctx, other, p) {
+		t.Fatal("Allowed() granted the roles of another authorizer")
+	}
+}
+
+func TestWithRoles_Documents(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	s, err := svc.CreateDocumentStore(ctx, "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &influxdb.Document{Meta: influxdb.DocumentMeta{Name: "d"}}
+	if err := s.CreateDocument(ctx, d, influxdb.WithOrgID(o.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &influxdb.Role{
+		ID:    1,
+		OrgID: o.ID,
+		Name:  "template-editor",
+		Permissions: []influxdb.RolePermission{
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.DocumentsResourceType}},
+			{Action: influxdb.WriteAction, Resource: influxdb.RoleResource{Type: influxdb.DocumentsResourceType}},
+		},
+	}
+	a := &influxdb.Authorization{ID: 5, OrgID: o.ID, RoleIDs: []influxdb.ID{1}, Status: influxdb.Active}
+	rctx, err := newRoleResolver(r).Resolve(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.FindDocuments(ctx, influxdb.AuthorizedWhereID(a, d.ID)); err == nil {
+		t.Fatal("expected the authorization without its roles not to find the document")
+	}
+
+	ra := authorizer.WithRoles(rctx, a)
+	if ds, err := s.FindDocuments(ctx, influxdb.AuthorizedWhereID(ra, d.ID)); err != nil || len(ds) != 1 {
+		t.Fatalf("FindDocuments() by id = %v, %v; want the document", ds, err)
+	}
+	if ds, err := s.FindDocuments(ctx, influxdb.AuthorizedWhere(ra)); err != nil || len(ds) != 1 {
+		t.Fatalf("FindDocuments() = %v, %v; want the document", ds, err)
+	}
+	if err := s.UpdateDocument(ctx, d, influxdb.Authorized(ra)); err != nil {
+		t.Fatalf("UpdateDocument() unexpected error: %v", err)
+	}
+	nd := &influxdb.Document{Meta: influxdb.DocumentMeta{Name: "nd"}}
+	if err := s.CreateDocument(ctx, nd, influxdb.AuthorizedWithOrgID(ra, o.ID)); err != nil {
+		t.Fatalf("CreateDocument() unexpected error: %v", err)
+	}
+}
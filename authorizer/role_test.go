@@ -0,0 +1,127 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRoleService_FindRoles(t *testing.T) {
+	roles := []*influxdb.Role{
+		{ID: 1, OrgID: 10, Name: "bucket-writer"},
+		{ID: 2, OrgID: 10, Name: "dashboard-viewer"},
+		{ID: 3, OrgID: 11, Name: "bucket-writer"},
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		roles      []*influxdb.Role
+	}{
+		{
+			name: "authorized to read all roles",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.RolesResourceType},
+			},
+			roles: roles,
+		},
+		{
+			name: "authorized to read the roles of an org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.RolesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			roles: roles[:2],
+		},
+		{
+			name: "authorized to read one role",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.RolesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(2),
+				},
+			},
+			roles: roles[1:2],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRoleService()
+			m.FindRolesFn = func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+				return append([]*influxdb.Role(nil), roles...), len(roles), nil
+			}
+			s := authorizer.NewRoleService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			got, _, err := s.FindRoles(ctx, influxdb.RoleFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tt.roles); diff != "" {
+				t.Errorf("roles are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestRoleService_AddRoleMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the roles of the org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.RolesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized with read permission",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.RolesResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/roles/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRoleService()
+			m.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+				return &influxdb.Role{ID: id, OrgID: 10, Name: "bucket-writer"}, nil
+			}
+			s := authorizer.NewRoleService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.AddRoleMember(ctx, 1, 2)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
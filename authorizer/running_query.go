@@ -0,0 +1,116 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+)
+
+var _ query.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService wraps a query.RunningQueryService and authorizes actions
+// against it appropriately.
+type RunningQueryService struct {
+	s query.RunningQueryService
+}
+
+// NewRunningQueryService constructs an instance of an authorizing running query service.
+func NewRunningQueryService(s query.RunningQueryService) *RunningQueryService {
+	return &RunningQueryService{
+		s: s,
+	}
+}
+
+// authorizeRunningQuery checks the authorizer on context has the action on the
+// organization of a query. Queries without an organization require the action
+// on all organizations.
+func authorizeRunningQuery(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	if orgID.Valid() {
+		p, err := newOrgPermission(a, orgID)
+		if err != nil {
+			return err
+		}
+		return IsAllowed(ctx, *p)
+	}
+
+	p, err := influxdb.NewGlobalPermission(a, influxdb.OrgsResourceType)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// FindRunningQueries retrieves the running queries that match the provided
+// filter and then filters the list down to the queries of the organizations
+// the authorizer on context can read.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if filter.OrganizationID != nil {
+		if err := authorizeReadOrg(ctx, *filter.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	rqs, err := s.s.FindRunningQueries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	queries := rqs[:0]
+	for _, rq := range rqs {
+		err := authorizeRunningQuery(ctx, influxdb.ReadAction, rq.OrganizationID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		queries = append(queries, rq)
+	}
+
+	return queries, nil
+}
+
+// FindRunningQueryByID checks to see if the authorizer on context has read
+// access to the organization of the query.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rq, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.ReadAction, rq.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	return rq, nil
+}
+
+// CancelRunningQuery checks to see if the authorizer on context has write
+// access to the organization of the query.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id uint64) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rq, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.WriteAction, rq.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CancelRunningQuery(ctx, id)
+}
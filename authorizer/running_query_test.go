@@ -0,0 +1,213 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newRunningQueryService() *mock.RunningQueryService {
+	rqs := []*query.RunningQuery{
+		{ID: 1, OrganizationID: 10},
+		{ID: 2, OrganizationID: 10},
+		{ID: 3, OrganizationID: 11},
+		{ID: 4},
+	}
+	return &mock.RunningQueryService{
+		FindRunningQueriesF: func(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+			var found []*query.RunningQuery
+			for _, rq := range rqs {
+				if filter.OrganizationID == nil || *filter.OrganizationID == rq.OrganizationID {
+					found = append(found, rq)
+				}
+			}
+			return found, nil
+		},
+		FindRunningQueryByIDF: func(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+			for _, rq := range rqs {
+				if rq.ID == id {
+					return rq, nil
+				}
+			}
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "query not found"}
+		},
+		CancelRunningQueryF: func(ctx context.Context, id uint64) error {
+			return nil
+		},
+	}
+}
+
+func TestRunningQueryService_FindRunningQueries(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		filter     query.RunningQueryFilter
+	}
+	type wants struct {
+		err error
+		ids []uint64
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to read all orgs",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.ReadAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				ids: []uint64{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "authorized to read a single org",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.ReadAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				ids: []uint64{1, 2},
+			},
+		},
+		{
+			name: "unauthorized to read the org in the filter",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.ReadAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				filter: query.RunningQueryFilter{
+					OrganizationID: influxdbtesting.IDPtr(11),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000b is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRunningQueryService(newRunningQueryService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			rqs, err := s.FindRunningQueries(ctx, tt.args.filter)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			var ids []uint64
+			for _, rq := range rqs {
+				ids = append(ids, rq.ID)
+			}
+			if diff := cmp.Diff(ids, tt.wants.ids); diff != "" {
+				t.Errorf("running queries are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunningQueryService_CancelRunningQuery(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		id         uint64
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to cancel a query of the org",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.WriteAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				id: 1,
+			},
+		},
+		{
+			name: "unauthorized to cancel a query of the org with read access",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.ReadAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				id: 1,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+		{
+			name: "unauthorized to cancel a query without an org",
+			args: args{
+				permission: influxdb.Permission{
+					Action: influxdb.WriteAction,
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				id: 4,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRunningQueryService(newRunningQueryService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			err := s.CancelRunningQuery(ctx, tt.args.id)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
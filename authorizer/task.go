@@ -46,7 +46,7 @@ type taskServiceValidator struct {
 func NewTaskService(logger *zap.Logger, ts influxdb.TaskService, bs influxdb.BucketService) influxdb.TaskService {
 	return &taskServiceValidator{
 		TaskService: ts,
-		preAuth:     query.NewPreAuthorizer(bs),
+		preAuth:     query.NewPreAuthorizer(bs, Allowed),
 		logger:      logger,
 	}
 }
@@ -102,7 +102,7 @@ func (ts *taskServiceValidator) FindTasks(ctx context.Context, filter influxdb.T
 		}
 
 		// We don't want to log authorization errors on this one.
-		if !auth.Allowed(*perm) {
+		if !Allowed(ctx, auth, *perm) {
 			continue
 		}
 
@@ -345,7 +345,7 @@ func (ts *taskServiceValidator) validatePermission(ctx context.Context, perm inf
 		return err
 	}
 
-	if !auth.Allowed(perm) {
+	if !Allowed(ctx, auth, perm) {
 		ts.logger.With(loggerFields...).Info("Authorization failed",
 			zap.String("user_id", auth.GetUserID().String()),
 			zap.String("auth_kind", auth.Kind()),
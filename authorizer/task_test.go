@@ -696,3 +696,69 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"other_bucket", org:"other_
 		})
 	}
 }
+
+func TestTaskService_Roles(t *testing.T) {
+	svc := inmem.NewService()
+	r, err := svc.Generate(context.Background(), &influxdb.OnboardingRequest{
+		User:            "Setec Astronomy",
+		Password:        "too many secrets",
+		Org:             "thing",
+		Bucket:          "holder",
+		RetentionPeriod: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orgID := r.Org.ID
+	ts := authorizer.NewTaskService(zaptest.NewLogger(t), mockTaskService(orgID, 2, 1), svc)
+
+	// The token only has a role granting the tasks and the buckets of the org.
+	role := &influxdb.Role{
+		ID:    1,
+		OrgID: orgID,
+		Name:  "task-writer",
+		Permissions: []influxdb.RolePermission{
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.TasksResourceType}},
+			{Action: influxdb.WriteAction, Resource: influxdb.RoleResource{Type: influxdb.TasksResourceType}},
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType}},
+			{Action: influxdb.WriteAction, Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType}},
+		},
+	}
+	a := &influxdb.Authorization{ID: 5, OrgID: orgID, UserID: r.User.ID, RoleIDs: []influxdb.ID{role.ID}, Status: influxdb.Active}
+
+	check := func(ctx context.Context) error {
+		if _, err := ts.FindTaskByID(ctx, 2); err != nil {
+			return err
+		}
+		tasks, _, err := ts.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &orgID})
+		if err != nil {
+			return err
+		}
+		if len(tasks) != 1 {
+			return fmt.Errorf("FindTasks() found %d tasks, want 1", len(tasks))
+		}
+		_, err = ts.CreateTask(ctx, influxdb.TaskCreate{
+			OrganizationID: orgID,
+			OwnerID:        r.User.ID,
+			Flux: `option task = {
+ name: "my_task",
+ every: 1s,
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
+		})
+		return err
+	}
+
+	if err := check(pctx.SetAuthorizer(context.Background(), a)); err == nil {
+		t.Fatal("expected error without the roles of the token")
+	}
+
+	ctx, err := newRoleResolver(role).Resolve(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := check(pctx.SetAuthorizer(ctx, a)); err != nil {
+		t.Fatalf("unexpected error with the roles of the token: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.UsageService = (*UsageService)(nil)
+
+// UsageService wraps a influxdb.UsageService and authorizes actions
+// against it appropriately.
+type UsageService struct {
+	s influxdb.UsageService
+}
+
+// NewUsageService constructs an instance of an authorizing usage service.
+func NewUsageService(s influxdb.UsageService) *UsageService {
+	return &UsageService{
+		s: s,
+	}
+}
+
+// GetUsage checks to see if the authorizer on context has read access to the
+// bucket or organization in the filter. The usage of the whole server requires
+// read access to all organizations.
+func (s *UsageService) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	switch {
+	case filter.BucketID != nil:
+		if filter.OrgID == nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is required for the usage of a bucket",
+			}
+		}
+		if err := authorizeReadBucket(ctx, *filter.OrgID, *filter.BucketID); err != nil {
+			return nil, err
+		}
+	case filter.OrgID != nil:
+		if err := authorizeReadOrg(ctx, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	default:
+		p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, influxdb.OrgsResourceType)
+		if err != nil {
+			return nil, err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.GetUsage(ctx, filter)
+}
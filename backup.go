@@ -0,0 +1,51 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BackupFilter selects the data to include in a backup. The zero value selects
+// all data on the server.
+type BackupFilter struct {
+	OrgID    *ID        `json:"orgID,omitempty"`
+	BucketID *ID        `json:"bucketID,omitempty"`
+	Start    *time.Time `json:"start,omitempty"`
+	Stop     *time.Time `json:"stop,omitempty"`
+}
+
+// Backup describes the files that make up a backup.
+type Backup struct {
+	ID    int      `json:"id"`
+	Files []string `json:"files"`
+}
+
+// BackupService represents the data backup functions of InfluxDB.
+type BackupService interface {
+	// CreateBackup creates a local copy of the TSM data selected by filter.
+	// The returned backup is used to download each backup file.
+	CreateBackup(ctx context.Context, filter BackupFilter) (*Backup, error)
+
+	// FetchBackupFile writes a single backup file to w.
+	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+
+	// DeleteBackup removes a backup and all of its files.
+	DeleteBackup(ctx context.Context, backupID int) error
+
+	// InternalBackupPath returns the local path of the backup with the given ID.
+	InternalBackupPath(backupID int) string
+}
+
+// KVBackupService represents the meta data backup functions of InfluxDB.
+type KVBackupService interface {
+	// Backup writes a consistent snapshot of the key-value store to w.
+	Backup(ctx context.Context, w io.Writer) error
+}
+
+// RestoreService represents the data restore functions of InfluxDB.
+type RestoreService interface {
+	// RestoreBucket imports the TSM file read from r into the bucket. Every key in
+	// the file must already be encoded for the given organization and bucket.
+	RestoreBucket(ctx context.Context, orgID, bucketID ID, r io.Reader) error
+}
@@ -15,6 +15,13 @@ var (
 
 var _ platform.AuthorizationService = (*Client)(nil)
 
+// authorizationRecord is an authorization as it is stored: the hash of its
+// token is stored in place of the token.
+type authorizationRecord struct {
+	platform.Authorization
+	TokenHash string `json:"tokenHash,omitempty"`
+}
+
 func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists([]byte(authorizationBucket)); err != nil {
 		return err
@@ -22,6 +29,34 @@ func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) erro
 	if _, err := tx.CreateBucketIfNotExists([]byte(authorizationIndex)); err != nil {
 		return err
 	}
+	return c.migrateAuthorizationTokens(ctx, tx)
+}
+
+// migrateAuthorizationTokens replaces the tokens of the authorizations stored
+// before tokens were hashed by their hash, both in the authorizations and in
+// the token index. The tokens cannot be restored once hashed.
+func (c *Client) migrateAuthorizationTokens(ctx context.Context, tx *bolt.Tx) error {
+	var legacy []*authorizationRecord
+	cur := tx.Bucket(authorizationBucket).Cursor()
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		r := &authorizationRecord{}
+		if err := decodeAuthorization(v, r); err != nil {
+			return err
+		}
+		if r.TokenHash == "" && r.Token != "" {
+			legacy = append(legacy, r)
+		}
+	}
+
+	for _, r := range legacy {
+		// Legacy authorizations are indexed by their token.
+		if err := tx.Bucket(authorizationIndex).Delete([]byte(r.Token)); err != nil {
+			return err
+		}
+		if pe := c.putAuthorization(ctx, tx, &r.Authorization); pe != nil {
+			return pe
+		}
+	}
 	return nil
 }
 
@@ -43,6 +78,15 @@ func (c *Client) FindAuthorizationByID(ctx context.Context, id platform.ID) (*pl
 }
 
 func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Authorization, *platform.Error) {
+	r, pe := c.findAuthorizationRecordByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+	r.Token = ""
+	return &r.Authorization, nil
+}
+
+func (c *Client) findAuthorizationRecordByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*authorizationRecord, *platform.Error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -51,7 +95,7 @@ func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id plat
 		}
 	}
 
-	var a platform.Authorization
+	var r authorizationRecord
 	v := tx.Bucket(authorizationBucket).Get(encodedID)
 
 	if len(v) == 0 {
@@ -61,14 +105,14 @@ func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id plat
 		}
 	}
 
-	if err := decodeAuthorization(v, &a); err != nil {
+	if err := decodeAuthorization(v, &r); err != nil {
 		return nil, &platform.Error{
 			Code: platform.EInvalid,
 			Err:  err,
 		}
 	}
 
-	return &a, nil
+	return &r, nil
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
@@ -89,7 +133,7 @@ func (c *Client) FindAuthorizationByToken(ctx context.Context, n string) (*platf
 }
 
 func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n string) (*platform.Authorization, *platform.Error) {
-	a := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(n))
+	a := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(platform.HashToken(n)))
 	if a == nil {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
@@ -103,7 +147,21 @@ func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n st
 			Err:  err,
 		}
 	}
-	return c.findAuthorizationByID(ctx, tx, id)
+	auth, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	// Expired tokens cannot be used, their authorizations are only found by id.
+	if auth.IsExpired(c.Now()) {
+		return nil, &platform.Error{
+			Code: platform.ErrAuthorizationExpired.Code,
+			Msg:  platform.ErrAuthorizationExpired.Msg,
+		}
+	}
+
+	auth.Token = n
+	return auth, nil
 }
 
 func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
@@ -113,11 +171,6 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *platform.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
 		return func(a *platform.Authorization) bool {
@@ -242,10 +295,6 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 			return platform.ErrUnableToCreateToken
 		}
 
-		if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
-			return platform.ErrUnableToCreateToken
-		}
-
 		if a.Token == "" {
 			token, err := c.TokenGenerator.Token()
 			if err != nil {
@@ -257,6 +306,10 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 			a.Token = token
 		}
 
+		if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
+			return platform.ErrUnableToCreateToken
+		}
+
 		a.ID = c.IDGenerator.ID()
 
 		pe := c.putAuthorization(ctx, tx, a)
@@ -269,7 +322,8 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 	})
 }
 
-// PutAuthorization will put a authorization without setting an ID.
+// PutAuthorization will put a authorization without setting an ID. Only the
+// hash of the token of the authorization is stored.
 func (c *Client) PutAuthorization(ctx context.Context, a *platform.Authorization) (err error) {
 	return c.db.Update(func(tx *bolt.Tx) error {
 		pe := c.putAuthorization(ctx, tx, a)
@@ -280,11 +334,11 @@ func (c *Client) PutAuthorization(ctx context.Context, a *platform.Authorization
 	})
 }
 
-func encodeAuthorization(a *platform.Authorization) ([]byte, error) {
-	switch a.Status {
+func encodeAuthorization(r *authorizationRecord) ([]byte, error) {
+	switch r.Status {
 	case platform.Active, platform.Inactive:
 	case "":
-		a.Status = platform.Active
+		r.Status = platform.Active
 	default:
 		return nil, &platform.Error{
 			Code: platform.EInvalid,
@@ -292,11 +346,17 @@ func encodeAuthorization(a *platform.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	stored := *r
+	stored.Token = ""
+	return json.Marshal(&stored)
 }
 
 func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) *platform.Error {
-	v, err := encodeAuthorization(a)
+	r := &authorizationRecord{
+		Authorization: *a,
+		TokenHash:     platform.HashToken(a.Token),
+	}
+	v, err := encodeAuthorization(r)
 	if err != nil {
 		return &platform.Error{
 			Code: platform.EInvalid,
@@ -312,7 +372,7 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 		}
 	}
 
-	if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.Token), encodedID); err != nil {
+	if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(r.TokenHash), encodedID); err != nil {
 		return &platform.Error{
 			Code: platform.EInternal,
 			Err:  err,
@@ -325,19 +385,21 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 		}
 	}
 
+	a.Status = r.Status
 	return nil
 }
 
-func authorizationIndexKey(n string) []byte {
-	return []byte(n)
+// authorizationIndexKey returns the key of the token index of the token hash h.
+func authorizationIndexKey(h string) []byte {
+	return []byte(h)
 }
 
-func decodeAuthorization(b []byte, a *platform.Authorization) error {
-	if err := json.Unmarshal(b, a); err != nil {
+func decodeAuthorization(b []byte, r *authorizationRecord) error {
+	if err := json.Unmarshal(b, r); err != nil {
 		return err
 	}
-	if a.Status == "" {
-		a.Status = platform.Active
+	if r.Status == "" {
+		r.Status = platform.Active
 	}
 	return nil
 }
@@ -346,12 +408,13 @@ func decodeAuthorization(b []byte, a *platform.Authorization) error {
 func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(*platform.Authorization) bool) error {
 	cur := tx.Bucket(authorizationBucket).Cursor()
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
-		a := &platform.Authorization{}
+		r := &authorizationRecord{}
 
-		if err := decodeAuthorization(v, a); err != nil {
+		if err := decodeAuthorization(v, r); err != nil {
 			return err
 		}
-		if !fn(a) {
+		r.Token = ""
+		if !fn(&r.Authorization) {
 			break
 		}
 	}
@@ -360,7 +423,7 @@ func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(
 }
 
 func (c *Client) uniqueAuthorizationToken(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) bool {
-	v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(a.Token))
+	v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(platform.HashToken(a.Token)))
 	return len(v) == 0
 }
 
@@ -378,11 +441,11 @@ func (c *Client) DeleteAuthorization(ctx context.Context, id platform.ID) error
 }
 
 func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
-	a, pe := c.findAuthorizationByID(ctx, tx, id)
+	r, pe := c.findAuthorizationRecordByID(ctx, tx, id)
 	if pe != nil {
 		return pe
 	}
-	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.Token)); err != nil {
+	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(r.TokenHash)); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -420,19 +483,19 @@ func (c *Client) UpdateAuthorization(ctx context.Context, id platform.ID, upd *p
 }
 
 func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, *platform.Error) {
-	a, pe := c.findAuthorizationByID(ctx, tx, id)
+	r, pe := c.findAuthorizationRecordByID(ctx, tx, id)
 	if pe != nil {
 		return nil, pe
 	}
 
 	if upd.Status != nil {
-		a.Status = *upd.Status
+		r.Status = *upd.Status
 	}
 	if upd.Description != nil {
-		a.Description = *upd.Description
+		r.Description = *upd.Description
 	}
 
-	b, err := encodeAuthorization(a)
+	b, err := encodeAuthorization(r)
 	if err != nil {
 		return nil, &platform.Error{
 			Err: err,
@@ -451,5 +514,6 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 			Err: err,
 		}
 	}
-	return a, nil
+	r.Token = ""
+	return &r.Authorization, nil
 }
@@ -2,8 +2,10 @@ package bolt_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	bbolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
 	platformtesting "github.com/influxdata/influxdb/testing"
@@ -61,3 +63,56 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 func TestAuthorizationService(t *testing.T) {
 	platformtesting.AuthorizationService(initAuthorizationService, t)
 }
+
+func TestClient_MigrateAuthorizationTokens(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	// An authorization stored before tokens were hashed, indexed by its token.
+	id := platform.ID(1)
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"id":"0000000000000001","token":"legacy-token","status":"active","orgID":"0000000000000002","userID":"0000000000000003"}`
+	err = c.DB().Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket([]byte("authorizationsv1")).Put(encodedID, []byte(legacy)); err != nil {
+			return err
+		}
+		return tx.Bucket([]byte("authorizationindexv1")).Put([]byte("legacy-token"), encodedID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := c.FindAuthorizationByToken(context.Background(), "legacy-token")
+	if err != nil {
+		t.Fatalf("failed to find the migrated authorization: %v", err)
+	}
+	if a.ID != id {
+		t.Fatalf("unexpected authorization: %+v", a)
+	}
+
+	err = c.DB().View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket([]byte("authorizationindexv1")).Get([]byte("legacy-token")); v != nil {
+			t.Error("expected the token to be removed from the index")
+		}
+		if v := tx.Bucket([]byte("authorizationsv1")).Get(encodedID); strings.Contains(string(v), "legacy-token") {
+			t.Errorf("expected the token not to be stored: %s", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
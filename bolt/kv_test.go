@@ -2,8 +2,11 @@ package bolt_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
+	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
@@ -39,3 +42,70 @@ func initKVStore(f platformtesting.KVStoreFields, t *testing.T) (kv.Store, func(
 func TestKVStore(t *testing.T) {
 	platformtesting.KVStore(initKVStore, t)
 }
+
+func TestKVStore_Migrations(t *testing.T) {
+	platformtesting.KVMigrations(func(t *testing.T) (kv.Store, func()) {
+		s, closeFn, err := NewTestKVStore()
+		if err != nil {
+			t.Fatalf("failed to create new kv store: %v", err)
+		}
+		return s, closeFn
+	}, t)
+}
+
+func TestKVStore_Backup(t *testing.T) {
+	s, closeFn, err := NewTestKVStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("failed to put keys: %v", err)
+	}
+
+	f, err := ioutil.TempFile("", "influxdata-platform-bolt-backup-")
+	if err != nil {
+		t.Fatalf("unable to create backup file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.Backup(ctx, f); err != nil {
+		t.Fatalf("failed to backup kv store: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close backup file: %v", err)
+	}
+
+	restored := bolt.NewKVStore(f.Name())
+	if err := restored.Open(ctx); err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer restored.Close()
+
+	err = restored.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		v, err := b.Get([]byte("key"))
+		if err != nil {
+			return err
+		}
+		if got, exp := string(v), "value"; got != exp {
+			t.Errorf("unexpected value: got %q, exp %q", got, exp)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read backup: %v", err)
+	}
+}
@@ -6,13 +6,17 @@ import (
 	"time"
 )
 
-// TasksSystemBucketID and MonitoringSystemBucketID are IDs that are reserved for system buckets.
+// TasksSystemBucketID, MonitoringSystemBucketID, AuditSystemBucketID and QueriesSystemBucketID are IDs that are reserved for system buckets.
 // If any system bucket IDs are added, Bucket.IsSystem must be updated to include them.
 const (
 	// TasksSystemBucketID is the fixed ID for our tasks system bucket
 	TasksSystemBucketID = ID(10)
 	// MonitoringSystemBucketID is the fixed ID for our monitoring system bucket
 	MonitoringSystemBucketID = ID(11)
+	// AuditSystemBucketID is the fixed ID for our audit system bucket
+	AuditSystemBucketID = ID(12)
+	// QueriesSystemBucketID is the fixed ID for our queries system bucket
+	QueriesSystemBucketID = ID(13)
 
 	// BucketTypeUser is a user created bucket
 	BucketTypeUser = BucketType(0)
@@ -25,13 +29,15 @@ const InfiniteRetention = 0
 
 // Bucket is a bucket. 🎉
 type Bucket struct {
-	ID                  ID            `json:"id,omitempty"`
-	OrgID               ID            `json:"orgID,omitempty"`
-	Type                BucketType    `json:"type"`
-	Name                string        `json:"name"`
-	Description         string        `json:"description"`
-	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
-	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	ID                  ID               `json:"id,omitempty"`
+	OrgID               ID               `json:"orgID,omitempty"`
+	Type                BucketType       `json:"type"`
+	Name                string           `json:"name"`
+	Description         string           `json:"description"`
+	RetentionPolicyName string           `json:"rp,omitempty"` // This to support v1 sources
+	RetentionPeriod     time.Duration    `json:"retentionPeriod"`
+	SchemaType          SchemaType       `json:"schemaType,omitempty"`
+	DownsampleTiers     []DownsampleTier `json:"downsampleTiers,omitempty"`
 	CRUDLog
 }
 
@@ -49,7 +55,7 @@ func (bt BucketType) String() string {
 // TODO(jade): move this logic to a type set directly on Bucket.
 // IsSystem returns true if a bucket is a known system bucket
 func (b *Bucket) IsSystem() bool {
-	return b.ID == TasksSystemBucketID || b.ID == MonitoringSystemBucketID
+	return b.ID == TasksSystemBucketID || b.ID == MonitoringSystemBucketID || b.ID == AuditSystemBucketID || b.ID == QueriesSystemBucketID
 }
 
 // ops for buckets error and buckets op logs.
@@ -88,9 +94,11 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name            *string           `json:"name,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	RetentionPeriod *time.Duration    `json:"retentionPeriod,omitempty"`
+	SchemaType      *SchemaType       `json:"schemaType,omitempty"`
+	DownsampleTiers *[]DownsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -0,0 +1,230 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// SchemaType determines how the storage engine treats the schema of the data
+// written to a bucket.
+type SchemaType string
+
+const (
+	// SchemaTypeImplicit buckets infer the type of every field from the first
+	// value written to it. It is the default schema type.
+	SchemaTypeImplicit SchemaType = "implicit"
+	// SchemaTypeExplicit buckets only accept points of measurements that have
+	// been declared with a MeasurementSchema.
+	SchemaTypeExplicit SchemaType = "explicit"
+)
+
+// Valid returns an error if the schema type is unknown. The empty schema type
+// is the implicit schema type.
+func (t SchemaType) Valid() error {
+	switch t {
+	case "", SchemaTypeImplicit, SchemaTypeExplicit:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid schema type %q, must be %q or %q", t, SchemaTypeImplicit, SchemaTypeExplicit),
+	}
+}
+
+// String returns the schema type, the empty schema type is reported as implicit.
+func (t SchemaType) String() string {
+	if t == "" {
+		return string(SchemaTypeImplicit)
+	}
+	return string(t)
+}
+
+// SchemaFieldType is the data type of a field of a measurement schema.
+type SchemaFieldType string
+
+// Field types of measurement schemas.
+const (
+	SchemaFieldTypeFloat    SchemaFieldType = "float"
+	SchemaFieldTypeInteger  SchemaFieldType = "integer"
+	SchemaFieldTypeUnsigned SchemaFieldType = "unsigned"
+	SchemaFieldTypeString   SchemaFieldType = "string"
+	SchemaFieldTypeBoolean  SchemaFieldType = "boolean"
+)
+
+// Valid returns an error if the field type is unknown.
+func (t SchemaFieldType) Valid() error {
+	switch t {
+	case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned, SchemaFieldTypeString, SchemaFieldTypeBoolean:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid field type %q", t),
+	}
+}
+
+// ops for measurement schemas.
+var (
+	OpFindMeasurementSchemaByID = "FindMeasurementSchemaByID"
+	OpFindMeasurementSchemas    = "FindMeasurementSchemas"
+	OpCreateMeasurementSchema   = "CreateMeasurementSchema"
+	OpUpdateMeasurementSchema   = "UpdateMeasurementSchema"
+)
+
+// MeasurementSchema declares the tag keys and the fields of a measurement
+// of a bucket with an explicit schema.
+type MeasurementSchema struct {
+	ID       ID                       `json:"id,omitempty"`
+	OrgID    ID                       `json:"orgID,omitempty"`
+	BucketID ID                       `json:"bucketID,omitempty"`
+	Name     string                   `json:"name"`
+	Tags     []string                 `json:"tags"`
+	Fields   []MeasurementSchemaField `json:"fields"`
+	CRUDLog
+}
+
+// MeasurementSchemaField is a field of a measurement schema.
+type MeasurementSchemaField struct {
+	Name string          `json:"name"`
+	Type SchemaFieldType `json:"type"`
+}
+
+// Valid returns an error if the schema has no name or fields, if any tag or
+// field name is declared twice or if any field type is unknown.
+func (s *MeasurementSchema) Valid() error {
+	if s.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement schema name is required",
+		}
+	}
+	if !s.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is required",
+		}
+	}
+	if len(s.Fields) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement schema must have at least one field",
+		}
+	}
+
+	names := make(map[string]bool, len(s.Tags)+len(s.Fields))
+	checkName := func(kind, name string) error {
+		if name == "" || name == "time" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid %s name %q", kind, name),
+			}
+		}
+		if names[name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s %q is declared more than once", kind, name),
+			}
+		}
+		names[name] = true
+		return nil
+	}
+	for _, t := range s.Tags {
+		if err := checkName("tag", t); err != nil {
+			return err
+		}
+	}
+	for _, f := range s.Fields {
+		if err := checkName("field", f.Name); err != nil {
+			return err
+		}
+		if err := f.Type.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasTag returns true if the schema declares the tag key.
+func (s *MeasurementSchema) HasTag(key string) bool {
+	for _, t := range s.Tags {
+		if t == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the declared field named name.
+func (s *MeasurementSchema) Field(name string) (MeasurementSchemaField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return MeasurementSchemaField{}, false
+}
+
+// MeasurementSchemaUpdate adds tag keys and fields to a measurement schema.
+// Data that has been written under a schema must remain valid, so tags and
+// fields can only be added and existing fields cannot change their type.
+type MeasurementSchemaUpdate struct {
+	Tags   []string                 `json:"tags,omitempty"`
+	Fields []MeasurementSchemaField `json:"fields,omitempty"`
+}
+
+// Apply adds the tags and fields of the update to the schema. Tags and fields
+// that are already declared are skipped, a field that is declared with a
+// different type is an error.
+func (u MeasurementSchemaUpdate) Apply(s *MeasurementSchema) error {
+	for _, t := range u.Tags {
+		if !s.HasTag(t) {
+			s.Tags = append(s.Tags, t)
+		}
+	}
+	for _, f := range u.Fields {
+		existing, ok := s.Field(f.Name)
+		if !ok {
+			s.Fields = append(s.Fields, f)
+			continue
+		}
+		if existing.Type != f.Type {
+			return &Error{
+				Code: EConflict,
+				Msg:  fmt.Sprintf("field %q of measurement %q is of type %s and cannot be changed to %s", f.Name, s.Name, existing.Type, f.Type),
+			}
+		}
+	}
+	return s.Valid()
+}
+
+// MeasurementSchemaFilter represents a set of filters that restrict the
+// returned measurement schemas.
+type MeasurementSchemaFilter struct {
+	BucketID ID
+	Name     *string
+}
+
+// BucketSchemaService represents a service for managing the measurement
+// schemas of buckets.
+type BucketSchemaService interface {
+	// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+	FindMeasurementSchemaByID(ctx context.Context, bucketID, id ID) (*MeasurementSchema, error)
+
+	// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+	FindMeasurementSchemas(ctx context.Context, filter MeasurementSchemaFilter) ([]*MeasurementSchema, error)
+
+	// CreateMeasurementSchema creates a new measurement schema and sets s.ID with the new identifier.
+	CreateMeasurementSchema(ctx context.Context, s *MeasurementSchema) error
+
+	// UpdateMeasurementSchema adds tags and fields to a measurement schema of a bucket.
+	// Returns the new measurement schema after update.
+	UpdateMeasurementSchema(ctx context.Context, bucketID, id ID, upd MeasurementSchemaUpdate) (*MeasurementSchema, error)
+}
+
+// MeasurementSchemaReader derives measurement schemas from the data that has
+// already been written to a bucket.
+type MeasurementSchemaReader interface {
+	// ReadMeasurementSchemas returns a schema for every measurement of the bucket,
+	// with the tag keys and fields of the series that have been written to it.
+	ReadMeasurementSchemas(ctx context.Context, orgID, bucketID ID) ([]*MeasurementSchema, error)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -21,8 +22,10 @@ var authorizationCmd = &cobra.Command{
 
 // AuthorizationCreateFlags are command line args used when creating a authorization
 type AuthorizationCreateFlags struct {
-	user string
-	org  string
+	user      string
+	org       string
+	expiresIn time.Duration
+	roles     []string
 
 	writeUserPermission bool
 	readUserPermission  bool
@@ -68,6 +71,8 @@ func init() {
 	authorizationCreateCmd.MarkFlagRequired("org")
 
 	authorizationCreateCmd.Flags().StringVarP(&authorizationCreateFlags.user, "user", "u", "", "The user name")
+	authorizationCreateCmd.Flags().DurationVarP(&authorizationCreateFlags.expiresIn, "expires-in", "", 0, "Duration after which the authorization expires, it never expires if not set")
+	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.roles, "role", "", []string{}, "The ID of a role of the organization whose permissions are granted (repeatable)")
 
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.writeUserPermission, "write-user", "", false, "Grants the permission to perform mutative actions against organization users")
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.readUserPermission, "read-user", "", false, "Grants the permission to perform read actions against organization users")
@@ -292,6 +297,19 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		OrgID:       o.ID,
 	}
 
+	for _, r := range authorizationCreateFlags.roles {
+		id, err := decodeRoleID(r)
+		if err != nil {
+			return err
+		}
+		authorization.RoleIDs = append(authorization.RoleIDs, id)
+	}
+
+	if authorizationCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authorizationCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
+
 	if userName := authorizationCreateFlags.user; userName != "" {
 		userSvc, err := newUserService(flags)
 		if err != nil {
@@ -321,6 +339,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		"Token",
 		"Status",
 		"UserID",
+		"ExpiresAt",
 		"Permissions",
 	)
 
@@ -334,6 +353,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		"Token":       authorization.Token,
 		"Status":      authorization.Status,
 		"UserID":      authorization.UserID.String(),
+		"ExpiresAt":   formatAuthorizationTime(authorization.ExpiresAt),
 		"Permissions": ps,
 	})
 
@@ -422,10 +442,12 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
+		"ExpiresAt",
+		"LastUsedAt",
+		"LastUsedFrom",
 		"Permissions",
 	)
 
@@ -436,11 +458,13 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 		}
 
 		w.Write(map[string]interface{}{
-			"ID":          a.ID,
-			"Token":       a.Token,
-			"Status":      a.Status,
-			"UserID":      a.UserID.String(),
-			"Permissions": permissions,
+			"ID":           a.ID,
+			"Status":       a.Status,
+			"UserID":       a.UserID.String(),
+			"ExpiresAt":    formatAuthorizationTime(a.ExpiresAt),
+			"LastUsedAt":   formatAuthorizationTime(a.LastUsedAt),
+			"LastUsedFrom": a.LastUsedFrom,
+			"Permissions":  permissions,
 		})
 	}
 
@@ -449,6 +473,14 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatAuthorizationTime formats the optional times of authorizations.
+func formatAuthorizationTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // AuthorizationDeleteFlags are command line args used when deleting a authorization
 type AuthorizationDeleteFlags struct {
 	id string
@@ -493,7 +525,6 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"User",
 		"UserID",
 		"Permissions",
@@ -507,7 +538,6 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
 		"Deleted":     true,
@@ -564,7 +594,6 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
@@ -578,7 +607,6 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"Status":      a.Status,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
@@ -635,7 +663,6 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
@@ -649,7 +676,6 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"Status":      a.Status,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [path]",
+	Short: "Backup the data in InfluxDB",
+	Long: `Backs up the metadata store and the TSM data of InfluxDB into a local directory
+while the server is running. The backup may be limited to an organization, a
+bucket or a time range; the metadata store is always included in full.
+
+The index is not backed up; it is rebuilt from the TSM data by influx restore.`,
+	Args: cobra.ExactArgs(1),
+	RunE: wrapCheckSetup(backupF),
+}
+
+var backupFlags struct {
+	OrgID    string
+	Org      string
+	BucketID string
+	Bucket   string
+	Start    string
+	Stop     string
+}
+
+// backupManifestFile is the name of the file describing the contents of a local backup.
+const backupManifestFile = "manifest.json"
+
+// backupManifest describes the contents of a local backup directory.
+type backupManifest struct {
+	Filter platform.BackupFilter `json:"filter"`
+	Files  []string              `json:"files"`
+}
+
+func init() {
+	backupCmd.PersistentFlags().StringVar(&backupFlags.OrgID, "org-id", "", "The ID of the organization to backup")
+	backupCmd.PersistentFlags().StringVarP(&backupFlags.Org, "org", "o", "", "The name of the organization to backup")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		backupFlags.Org = h
+	}
+
+	backupCmd.PersistentFlags().StringVar(&backupFlags.BucketID, "bucket-id", "", "The ID of the bucket to backup")
+	backupCmd.PersistentFlags().StringVarP(&backupFlags.Bucket, "bucket", "b", "", "The name of the bucket to backup")
+
+	backupCmd.PersistentFlags().StringVar(&backupFlags.Start, "start", "", "Only backup data at or after this time, in RFC3339Nano format")
+	backupCmd.PersistentFlags().StringVar(&backupFlags.Stop, "stop", "", "Only backup data at or before this time, in RFC3339Nano format")
+}
+
+func backupF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if backupFlags.Org != "" && backupFlags.OrgID != "" {
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+	if backupFlags.Bucket != "" && backupFlags.BucketID != "" {
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	filter, err := newBackupFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := args[0]
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+
+	s := &http.BackupService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	backup, err := s.CreateBackup(ctx, *filter)
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %v", err)
+	}
+
+	// Always remove the backup from the server, even if downloading it failed.
+	defer func() {
+		if err := s.DeleteBackup(ctx, backup.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove backup %d from server: %v\n", backup.ID, err)
+		}
+	}()
+
+	for _, file := range backup.Files {
+		if err := fetchBackupFile(ctx, s, backup.ID, file, filepath.Join(path, file)); err != nil {
+			return fmt.Errorf("failed to fetch backup file %q: %v", file, err)
+		}
+	}
+
+	manifest, err := json.MarshalIndent(backupManifest{Filter: *filter, Files: backup.Files}, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := writeFileSync(filepath.Join(path, backupManifestFile), manifest); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup of %d files written to %s\n", len(backup.Files), path)
+	return nil
+}
+
+// newBackupFilter resolves the organization and bucket flags into a backup filter.
+func newBackupFilter(ctx context.Context) (*platform.BackupFilter, error) {
+	filter := &platform.BackupFilter{}
+
+	if backupFlags.OrgID != "" {
+		id, err := platform.IDFromString(backupFlags.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		filter.OrgID = id
+	}
+	if backupFlags.Org != "" {
+		orgSvc := &http.OrganizationService{
+			Addr:  flags.host,
+			Token: flags.token,
+		}
+		org, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: &backupFlags.Org})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organization %q: %v", backupFlags.Org, err)
+		}
+		filter.OrgID = &org.ID
+	}
+
+	if backupFlags.BucketID != "" {
+		id, err := platform.IDFromString(backupFlags.BucketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+		filter.BucketID = id
+	}
+	if backupFlags.Bucket != "" {
+		if filter.OrgID == nil {
+			return nil, fmt.Errorf("please specify one of org or org-id to backup a bucket by name")
+		}
+		bs := &http.BucketService{
+			Addr:  flags.host,
+			Token: flags.token,
+		}
+		b, err := bs.FindBucket(ctx, platform.BucketFilter{OrganizationID: filter.OrgID, Name: &backupFlags.Bucket})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bucket %q: %v", backupFlags.Bucket, err)
+		}
+		filter.BucketID = &b.ID
+	}
+
+	if backupFlags.Start != "" {
+		start, err := time.Parse(time.RFC3339Nano, backupFlags.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time %q: %v", backupFlags.Start, err)
+		}
+		filter.Start = &start
+	}
+	if backupFlags.Stop != "" {
+		stop, err := time.Parse(time.RFC3339Nano, backupFlags.Stop)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stop time %q: %v", backupFlags.Stop, err)
+		}
+		filter.Stop = &stop
+	}
+
+	return filter, nil
+}
+
+func fetchBackupFile(ctx context.Context, s platform.BackupService, backupID int, backupFile, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := s.FetchBackupFile(ctx, backupID, backupFile, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -25,9 +25,10 @@ func bucketF(cmd *cobra.Command, args []string) {
 
 // BucketCreateFlags define the Create Command
 type BucketCreateFlags struct {
-	name      string
-	orgID     string
-	retention time.Duration
+	name       string
+	orgID      string
+	retention  time.Duration
+	schemaType string
 }
 
 var bucketCreateFlags BucketCreateFlags
@@ -42,6 +43,7 @@ func init() {
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.name, "name", "n", "", "Name of bucket that will be created")
 	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the bucket")
+	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.schemaType, "schema-type", "", "", "The schema type of the bucket, implicit or explicit")
 	bucketCreateCmd.MarkFlagRequired("name")
 
 	bucketCmd.AddCommand(bucketCreateCmd)
@@ -70,6 +72,7 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 	b := &platform.Bucket{
 		Name:            bucketCreateFlags.name,
 		RetentionPeriod: bucketCreateFlags.retention,
+		SchemaType:      platform.SchemaType(bucketCreateFlags.schemaType),
 	}
 
 	if bucketCreateFlags.orgID != "" {
@@ -191,9 +194,10 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 
 // BucketUpdateFlags define the Update Command
 type BucketUpdateFlags struct {
-	id        string
-	name      string
-	retention time.Duration
+	id         string
+	name       string
+	retention  time.Duration
+	schemaType string
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -208,6 +212,7 @@ func init() {
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.id, "id", "i", "", "The bucket ID (required)")
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.name, "name", "n", "", "New bucket name")
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "New duration data will live in bucket")
+	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.schemaType, "schema-type", "", "", "New schema type of the bucket, implicit or explicit")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -231,6 +236,10 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 	if bucketUpdateFlags.retention != 0 {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
+	if bucketUpdateFlags.schemaType != "" {
+		st := platform.SchemaType(bucketUpdateFlags.schemaType)
+		update.SchemaType = &st
+	}
 
 	b, err := s.UpdateBucket(context.Background(), id, update)
 	if err != nil {
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Bucket Schema Command
+var bucketSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Measurement schema management commands for buckets with an explicit schema",
+	Run:   bucketSchemaF,
+}
+
+func bucketSchemaF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(bucketSchemaCmd)
+	bucketCmd.AddCommand(bucketSchemaCmd)
+}
+
+func newBucketSchemaService(f Flags) *http.BucketSchemaService {
+	return &http.BucketSchemaService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeMeasurementSchemas(ss ...*platform.MeasurementSchema) error {
+	if jsonOutput {
+		return writeJSON(ss)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Measurement",
+		"Tags",
+		"Fields",
+		"BucketID",
+	)
+	for _, s := range ss {
+		fields := make([]string, 0, len(s.Fields))
+		for _, f := range s.Fields {
+			fields = append(fields, f.Name+":"+string(f.Type))
+		}
+		w.Write(map[string]interface{}{
+			"ID":          s.ID.String(),
+			"Measurement": s.Name,
+			"Tags":        strings.Join(s.Tags, ","),
+			"Fields":      strings.Join(fields, ","),
+			"BucketID":    s.BucketID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// parseSchemaFields parses fields given as name:type, e.g. usage:float.
+func parseSchemaFields(fields []string) ([]platform.MeasurementSchemaField, error) {
+	fs := make([]platform.MeasurementSchemaField, 0, len(fields))
+	for _, f := range fields {
+		i := strings.LastIndex(f, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("field %q must be given as name:type", f)
+		}
+		typ := platform.SchemaFieldType(f[i+1:])
+		if err := typ.Valid(); err != nil {
+			return nil, fmt.Errorf("field %q: %v", f[:i], err)
+		}
+		fs = append(fs, platform.MeasurementSchemaField{Name: f[:i], Type: typ})
+	}
+	return fs, nil
+}
+
+// BucketSchemaCreateFlags define the Create Command
+type BucketSchemaCreateFlags struct {
+	bucketID string
+	name     string
+	tags     []string
+	fields   []string
+	fromData bool
+}
+
+var bucketSchemaCreateFlags BucketSchemaCreateFlags
+
+func init() {
+	bucketSchemaCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create measurement schema",
+		Long: `Create a measurement schema from the given tags and fields, or with --from-data
+from the measurements that have already been written to the bucket.`,
+		RunE: wrapCheckSetup(bucketSchemaCreateF),
+	}
+
+	bucketSchemaCreateCmd.Flags().StringVarP(&bucketSchemaCreateFlags.bucketID, "bucket-id", "", "", "The ID of the bucket (required)")
+	bucketSchemaCreateCmd.Flags().StringVarP(&bucketSchemaCreateFlags.name, "name", "n", "", "Name of the measurement")
+	bucketSchemaCreateCmd.Flags().StringArrayVarP(&bucketSchemaCreateFlags.tags, "tag", "", []string{}, "Tag key of the measurement")
+	bucketSchemaCreateCmd.Flags().StringArrayVarP(&bucketSchemaCreateFlags.fields, "field", "", []string{}, "Field of the measurement as name:type, type is one of float, integer, unsigned, string or boolean")
+	bucketSchemaCreateCmd.Flags().BoolVarP(&bucketSchemaCreateFlags.fromData, "from-data", "", false, "Create the schemas of all measurements, or only of --name, written to the bucket")
+	bucketSchemaCreateCmd.MarkFlagRequired("bucket-id")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaCreateCmd)
+}
+
+func bucketSchemaCreateF(cmd *cobra.Command, args []string) error {
+	bucketID, err := platform.IDFromString(bucketSchemaCreateFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaCreateFlags.bucketID, err)
+	}
+
+	s := newBucketSchemaService(flags)
+	ctx := context.Background()
+
+	var schemas []*platform.MeasurementSchema
+	if bucketSchemaCreateFlags.fromData {
+		if len(bucketSchemaCreateFlags.tags) > 0 || len(bucketSchemaCreateFlags.fields) > 0 {
+			return fmt.Errorf("must not specify tags or fields with from-data")
+		}
+		observed, err := s.ReadMeasurementSchemas(ctx, 0, *bucketID)
+		if err != nil {
+			return fmt.Errorf("failed to read measurement schemas from bucket data: %v", err)
+		}
+		for _, ms := range observed {
+			if bucketSchemaCreateFlags.name == "" || ms.Name == bucketSchemaCreateFlags.name {
+				schemas = append(schemas, ms)
+			}
+		}
+		if len(schemas) == 0 {
+			return fmt.Errorf("no measurements found in bucket %s", bucketID)
+		}
+	} else {
+		if bucketSchemaCreateFlags.name == "" {
+			return fmt.Errorf("must specify name")
+		}
+		fields, err := parseSchemaFields(bucketSchemaCreateFlags.fields)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, &platform.MeasurementSchema{
+			Name:   bucketSchemaCreateFlags.name,
+			Tags:   bucketSchemaCreateFlags.tags,
+			Fields: fields,
+		})
+	}
+
+	for _, ms := range schemas {
+		ms.BucketID = *bucketID
+		if err := s.CreateMeasurementSchema(ctx, ms); err != nil {
+			return fmt.Errorf("failed to create measurement schema %q: %v", ms.Name, err)
+		}
+	}
+
+	return writeMeasurementSchemas(schemas...)
+}
+
+// BucketSchemaUpdateFlags define the Update Command
+type BucketSchemaUpdateFlags struct {
+	bucketID string
+	id       string
+	name     string
+	tags     []string
+	fields   []string
+}
+
+var bucketSchemaUpdateFlags BucketSchemaUpdateFlags
+
+func init() {
+	bucketSchemaUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Add tags and fields to a measurement schema",
+		RunE:  wrapCheckSetup(bucketSchemaUpdateF),
+	}
+
+	bucketSchemaUpdateCmd.Flags().StringVarP(&bucketSchemaUpdateFlags.bucketID, "bucket-id", "", "", "The ID of the bucket (required)")
+	bucketSchemaUpdateCmd.Flags().StringVarP(&bucketSchemaUpdateFlags.id, "id", "i", "", "The measurement schema ID")
+	bucketSchemaUpdateCmd.Flags().StringVarP(&bucketSchemaUpdateFlags.name, "name", "n", "", "Name of the measurement")
+	bucketSchemaUpdateCmd.Flags().StringArrayVarP(&bucketSchemaUpdateFlags.tags, "tag", "", []string{}, "Tag key to add to the measurement")
+	bucketSchemaUpdateCmd.Flags().StringArrayVarP(&bucketSchemaUpdateFlags.fields, "field", "", []string{}, "Field to add to the measurement as name:type")
+	bucketSchemaUpdateCmd.MarkFlagRequired("bucket-id")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaUpdateCmd)
+}
+
+func bucketSchemaUpdateF(cmd *cobra.Command, args []string) error {
+	bucketID, err := platform.IDFromString(bucketSchemaUpdateFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaUpdateFlags.bucketID, err)
+	}
+
+	if (bucketSchemaUpdateFlags.id == "") == (bucketSchemaUpdateFlags.name == "") {
+		return fmt.Errorf("must specify exactly one of id and name")
+	}
+
+	fields, err := parseSchemaFields(bucketSchemaUpdateFlags.fields)
+	if err != nil {
+		return err
+	}
+
+	s := newBucketSchemaService(flags)
+	ctx := context.Background()
+
+	var id platform.ID
+	if bucketSchemaUpdateFlags.id != "" {
+		if err := id.DecodeFromString(bucketSchemaUpdateFlags.id); err != nil {
+			return fmt.Errorf("failed to decode measurement schema id %q: %v", bucketSchemaUpdateFlags.id, err)
+		}
+	} else {
+		ss, err := s.FindMeasurementSchemas(ctx, platform.MeasurementSchemaFilter{
+			BucketID: *bucketID,
+			Name:     &bucketSchemaUpdateFlags.name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve measurement schema: %v", err)
+		}
+		if len(ss) == 0 {
+			return fmt.Errorf("measurement schema %q not found", bucketSchemaUpdateFlags.name)
+		}
+		id = ss[0].ID
+	}
+
+	ms, err := s.UpdateMeasurementSchema(ctx, *bucketID, id, platform.MeasurementSchemaUpdate{
+		Tags:   bucketSchemaUpdateFlags.tags,
+		Fields: fields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update measurement schema: %v", err)
+	}
+
+	return writeMeasurementSchemas(ms)
+}
+
+// BucketSchemaListFlags define the List Command
+type BucketSchemaListFlags struct {
+	bucketID string
+	name     string
+}
+
+var bucketSchemaListFlags BucketSchemaListFlags
+
+func init() {
+	bucketSchemaListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List measurement schemas",
+		RunE:  wrapCheckSetup(bucketSchemaListF),
+	}
+
+	bucketSchemaListCmd.Flags().StringVarP(&bucketSchemaListFlags.bucketID, "bucket-id", "", "", "The ID of the bucket (required)")
+	bucketSchemaListCmd.Flags().StringVarP(&bucketSchemaListFlags.name, "name", "n", "", "Name of the measurement")
+	bucketSchemaListCmd.MarkFlagRequired("bucket-id")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaListCmd)
+}
+
+func bucketSchemaListF(cmd *cobra.Command, args []string) error {
+	bucketID, err := platform.IDFromString(bucketSchemaListFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaListFlags.bucketID, err)
+	}
+
+	filter := platform.MeasurementSchemaFilter{BucketID: *bucketID}
+	if bucketSchemaListFlags.name != "" {
+		filter.Name = &bucketSchemaListFlags.name
+	}
+
+	ss, err := newBucketSchemaService(flags).FindMeasurementSchemas(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve measurement schemas: %v", err)
+	}
+
+	return writeMeasurementSchemas(ss...)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/spf13/cobra"
+)
+
+// Check Command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check management commands",
+	Run:   checkF,
+}
+
+func checkF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(checkCmd)
+}
+
+func newCheckService(f Flags) *http.CheckService {
+	return &http.CheckService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeChecks(cs ...platform.Check) error {
+	rs := make([]notificationResource, len(cs))
+	for i, c := range cs {
+		rs[i] = c
+	}
+	return writeNotificationResources(rs...)
+}
+
+var checkCreateFlags NotificationCreateFlags
+
+func init() {
+	checkCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create check",
+		RunE:  wrapCheckSetup(checkCreateF),
+	}
+
+	checkCreateFlags.register(checkCreateCmd, "check")
+
+	checkCmd.AddCommand(checkCreateCmd)
+}
+
+func checkCreateF(cmd *cobra.Command, args []string) error {
+	b, err := ioutil.ReadFile(checkCreateFlags.file)
+	if err != nil {
+		return err
+	}
+
+	c, err := check.UnmarshalJSON(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse check %s: %v", checkCreateFlags.file, err)
+	}
+
+	ctx := context.Background()
+	if err := checkCreateFlags.setOrg(ctx, cmd, c); err != nil {
+		return err
+	}
+
+	if err := newCheckService(flags).CreateCheck(ctx, c, 0); err != nil {
+		return fmt.Errorf("failed to create check: %v", err)
+	}
+
+	return writeChecks(c)
+}
+
+var checkFindFlags struct {
+	NotificationFindFlags
+	name string
+}
+
+func init() {
+	checkFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find checks",
+		RunE:  wrapCheckSetup(checkFindF),
+	}
+
+	checkFindFlags.register(checkFindCmd, "check")
+	checkFindCmd.Flags().StringVarP(&checkFindFlags.name, "name", "n", "", "The check name")
+
+	checkCmd.AddCommand(checkFindCmd)
+}
+
+func checkFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newCheckService(flags)
+
+	if checkFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(checkFindFlags.id); err != nil {
+			return fmt.Errorf("failed to decode check id %q: %v", checkFindFlags.id, err)
+		}
+
+		c, err := s.FindCheckByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find check with id %q: %v", id, err)
+		}
+		return writeChecks(c)
+	}
+
+	filter := platform.CheckFilter{}
+	if checkFindFlags.name != "" {
+		filter.Name = &checkFindFlags.name
+	}
+
+	var err error
+	filter.OrgID, filter.Org, err = orgFilter(checkFindFlags.org, checkFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	cs, _, err := s.FindChecks(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve checks: %v", err)
+	}
+
+	return writeChecks(cs...)
+}
+
+var checkUpdateFlags NotificationUpdateFlags
+
+func init() {
+	checkUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update check",
+		RunE:  wrapCheckSetup(checkUpdateF),
+	}
+
+	checkUpdateFlags.register(checkUpdateCmd, "check")
+
+	checkCmd.AddCommand(checkUpdateCmd)
+}
+
+func checkUpdateF(cmd *cobra.Command, args []string) error {
+	id, name, description, status, err := checkUpdateFlags.update(cmd, "check")
+	if err != nil {
+		return err
+	}
+
+	c, err := newCheckService(flags).PatchCheck(context.Background(), id, platform.CheckUpdate{
+		Name:        name,
+		Description: description,
+		Status:      status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update check: %v", err)
+	}
+
+	return writeChecks(c)
+}
+
+var checkDeleteFlags NotificationDeleteFlags
+
+func init() {
+	checkDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete check",
+		RunE:  wrapCheckSetup(checkDeleteF),
+	}
+
+	checkDeleteFlags.register(checkDeleteCmd, "check")
+
+	checkCmd.AddCommand(checkDeleteCmd)
+}
+
+func checkDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(checkDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode check id %q: %v", checkDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newCheckService(flags)
+	c, err := s.FindCheckByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find check with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteCheck(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete check with id %q: %v", id, err)
+	}
+
+	return writeChecks(c)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Dashboard Command
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Dashboard management commands",
+	Run:   dashboardF,
+}
+
+func dashboardF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(dashboardCmd)
+}
+
+func newDashboardService(f Flags) platform.DashboardService {
+	return &http.DashboardService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeDashboards(ds ...*platform.Dashboard) error {
+	if jsonOutput {
+		return writeJSON(ds)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Description",
+		"Cells",
+		"OrganizationID",
+	)
+	for _, d := range ds {
+		w.Write(map[string]interface{}{
+			"ID":             d.ID.String(),
+			"Name":           d.Name,
+			"Description":    d.Description,
+			"Cells":          len(d.Cells),
+			"OrganizationID": d.OrganizationID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// DashboardCreateFlags define the Create Command
+type DashboardCreateFlags struct {
+	name        string
+	description string
+	org         string
+	orgID       string
+}
+
+var dashboardCreateFlags DashboardCreateFlags
+
+func init() {
+	dashboardCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create dashboard",
+		RunE:  wrapCheckSetup(dashboardCreateF),
+	}
+
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.name, "name", "n", "", "Name of dashboard that will be created")
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.description, "description", "d", "", "Description of dashboard that will be created")
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the dashboard")
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.org, "org", "o", "", "The name of the organization that owns the dashboard")
+	dashboardCreateCmd.MarkFlagRequired("name")
+
+	dashboardCmd.AddCommand(dashboardCreateCmd)
+}
+
+func dashboardCreateF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	orgID, err := resolveOrgID(ctx, cmd, dashboardCreateFlags.org, dashboardCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	d := &platform.Dashboard{
+		OrganizationID: orgID,
+		Name:           dashboardCreateFlags.name,
+		Description:    dashboardCreateFlags.description,
+	}
+	if err := newDashboardService(flags).CreateDashboard(ctx, d); err != nil {
+		return fmt.Errorf("failed to create dashboard: %v", err)
+	}
+
+	return writeDashboards(d)
+}
+
+// DashboardFindFlags define the Find Command
+type DashboardFindFlags struct {
+	id    string
+	org   string
+	orgID string
+}
+
+var dashboardFindFlags DashboardFindFlags
+
+func init() {
+	dashboardFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find dashboards",
+		RunE:  wrapCheckSetup(dashboardFindF),
+	}
+
+	dashboardFindCmd.Flags().StringVarP(&dashboardFindFlags.id, "id", "i", "", "The dashboard ID")
+	dashboardFindCmd.Flags().StringVarP(&dashboardFindFlags.orgID, "org-id", "", "", "The dashboard organization ID")
+	dashboardFindCmd.Flags().StringVarP(&dashboardFindFlags.org, "org", "o", "", "The dashboard organization name")
+
+	dashboardCmd.AddCommand(dashboardFindCmd)
+}
+
+func dashboardFindF(cmd *cobra.Command, args []string) error {
+	filter := platform.DashboardFilter{}
+	if dashboardFindFlags.id != "" {
+		id, err := platform.IDFromString(dashboardFindFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode dashboard id %q: %v", dashboardFindFlags.id, err)
+		}
+		filter.IDs = []*platform.ID{id}
+	}
+
+	var err error
+	filter.OrganizationID, filter.Organization, err = orgFilter(dashboardFindFlags.org, dashboardFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	ds, _, err := newDashboardService(flags).FindDashboards(context.Background(), filter, platform.DefaultDashboardFindOptions)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dashboards: %v", err)
+	}
+
+	return writeDashboards(ds...)
+}
+
+// DashboardUpdateFlags define the Update Command
+type DashboardUpdateFlags struct {
+	id          string
+	name        string
+	description string
+}
+
+var dashboardUpdateFlags DashboardUpdateFlags
+
+func init() {
+	dashboardUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update dashboard",
+		RunE:  wrapCheckSetup(dashboardUpdateF),
+	}
+
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.id, "id", "i", "", "The dashboard ID (required)")
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.name, "name", "n", "", "New dashboard name")
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.description, "description", "d", "", "New dashboard description")
+	dashboardUpdateCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardUpdateCmd)
+}
+
+func dashboardUpdateF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode dashboard id %q: %v", dashboardUpdateFlags.id, err)
+	}
+
+	update := platform.DashboardUpdate{}
+	if cmd.Flags().Changed("name") {
+		update.Name = &dashboardUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		update.Description = &dashboardUpdateFlags.description
+	}
+
+	d, err := newDashboardService(flags).UpdateDashboard(context.Background(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update dashboard: %v", err)
+	}
+
+	return writeDashboards(d)
+}
+
+// DashboardDeleteFlags define the Delete command
+type DashboardDeleteFlags struct {
+	id string
+}
+
+var dashboardDeleteFlags DashboardDeleteFlags
+
+func init() {
+	dashboardDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete dashboard",
+		RunE:  wrapCheckSetup(dashboardDeleteF),
+	}
+
+	dashboardDeleteCmd.Flags().StringVarP(&dashboardDeleteFlags.id, "id", "i", "", "The dashboard ID (required)")
+	dashboardDeleteCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardDeleteCmd)
+}
+
+func dashboardDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode dashboard id %q: %v", dashboardDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newDashboardService(flags)
+	d, err := s.FindDashboardByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find dashboard with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteDashboard(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete dashboard with id %q: %v", id, err)
+	}
+
+	return writeDashboards(d)
+}
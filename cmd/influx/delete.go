@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete points from InfluxDB",
+	Long: `Delete points from a bucket between a start and stop time.
+If a predicate is given, only series matching it are deleted.`,
+	Args: cobra.NoArgs,
+	RunE: wrapCheckSetup(fluxDeleteF),
+}
+
+var deleteFlags struct {
+	OrgID     string
+	Org       string
+	BucketID  string
+	Bucket    string
+	Start     string
+	Stop      string
+	Predicate string
+}
+
+func init() {
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.OrgID, "org-id", "", "The ID of the organization that owns the bucket")
+	viper.BindEnv("ORG_ID")
+	if h := viper.GetString("ORG_ID"); h != "" {
+		deleteFlags.OrgID = h
+	}
+
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Org, "org", "o", "", "The name of the organization that owns the bucket")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		deleteFlags.Org = h
+	}
+
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.BucketID, "bucket-id", "", "The ID of the bucket to delete from")
+	viper.BindEnv("BUCKET_ID")
+	if h := viper.GetString("BUCKET_ID"); h != "" {
+		deleteFlags.BucketID = h
+	}
+
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Bucket, "bucket", "b", "", "The name of the bucket to delete from")
+	viper.BindEnv("BUCKET_NAME")
+	if h := viper.GetString("BUCKET_NAME"); h != "" {
+		deleteFlags.Bucket = h
+	}
+
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.Start, "start", "", "The start time in RFC3339Nano format, e.g. 2009-01-02T23:00:00Z")
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.Stop, "stop", "", "The stop time in RFC3339Nano format, e.g. 2009-01-02T23:00:00Z")
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Predicate, "predicate", "p", "", `InfluxQL-style predicate, e.g. '_measurement="cpu" AND host="a"'`)
+}
+
+func fluxDeleteF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if deleteFlags.Org != "" && deleteFlags.OrgID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if deleteFlags.Bucket != "" && deleteFlags.BucketID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	if deleteFlags.Bucket == "" && deleteFlags.BucketID == "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, deleteFlags.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q: %v", deleteFlags.Start, err)
+	}
+
+	stop, err := time.Parse(time.RFC3339Nano, deleteFlags.Stop)
+	if err != nil {
+		return fmt.Errorf("invalid stop time %q: %v", deleteFlags.Stop, err)
+	}
+
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	filter := platform.BucketFilter{}
+
+	if deleteFlags.BucketID != "" {
+		filter.ID, err = platform.IDFromString(deleteFlags.BucketID)
+		if err != nil {
+			return fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+	}
+	if deleteFlags.Bucket != "" {
+		filter.Name = &deleteFlags.Bucket
+	}
+
+	if deleteFlags.OrgID != "" {
+		filter.OrganizationID, err = platform.IDFromString(deleteFlags.OrgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode org-id id: %v", err)
+		}
+	}
+	if deleteFlags.Org != "" {
+		filter.Org = &deleteFlags.Org
+	}
+
+	buckets, n, err := bs.FindBuckets(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve buckets: %v", err)
+	}
+
+	if n == 0 {
+		if deleteFlags.Bucket != "" {
+			return fmt.Errorf("bucket %q was not found", deleteFlags.Bucket)
+		}
+		return fmt.Errorf("bucket with id %q does not exist", deleteFlags.BucketID)
+	}
+
+	bucketID, orgID := buckets[0].ID, buckets[0].OrgID
+
+	s := &http.DeleteService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	ctx = signals.WithStandardSignals(ctx)
+	if err := s.Delete(ctx, orgID, bucketID, start, stop, deleteFlags.Predicate); err != nil && err != context.Canceled {
+		return fmt.Errorf("failed to delete data: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Label Command
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Label management commands",
+	Run:   labelF,
+}
+
+func labelF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(labelCmd)
+}
+
+func newLabelService(f Flags) platform.LabelService {
+	return &http.LabelService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeLabels(ls ...*platform.Label) error {
+	if jsonOutput {
+		return writeJSON(ls)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Color",
+		"Description",
+		"OrganizationID",
+	)
+	for _, l := range ls {
+		w.Write(map[string]interface{}{
+			"ID":             l.ID.String(),
+			"Name":           l.Name,
+			"Color":          l.Properties["color"],
+			"Description":    l.Properties["description"],
+			"OrganizationID": l.OrgID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// labelProperties returns the properties of a label set by the color and
+// description flags of cmd.
+func labelProperties(cmd *cobra.Command, color, description string) map[string]string {
+	props := map[string]string{}
+	if cmd.Flags().Changed("color") {
+		props["color"] = color
+	}
+	if cmd.Flags().Changed("description") {
+		props["description"] = description
+	}
+	return props
+}
+
+// LabelCreateFlags define the Create Command
+type LabelCreateFlags struct {
+	name        string
+	color       string
+	description string
+	org         string
+	orgID       string
+}
+
+var labelCreateFlags LabelCreateFlags
+
+func init() {
+	labelCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create label",
+		RunE:  wrapCheckSetup(labelCreateF),
+	}
+
+	labelCreateCmd.Flags().StringVarP(&labelCreateFlags.name, "name", "n", "", "Name of label that will be created")
+	labelCreateCmd.Flags().StringVarP(&labelCreateFlags.color, "color", "c", "", "Hex color of label that will be created, e.g. #326BBA")
+	labelCreateCmd.Flags().StringVarP(&labelCreateFlags.description, "description", "d", "", "Description of label that will be created")
+	labelCreateCmd.Flags().StringVarP(&labelCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the label")
+	labelCreateCmd.Flags().StringVarP(&labelCreateFlags.org, "org", "o", "", "The name of the organization that owns the label")
+	labelCreateCmd.MarkFlagRequired("name")
+
+	labelCmd.AddCommand(labelCreateCmd)
+}
+
+func labelCreateF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	orgID, err := resolveOrgID(ctx, cmd, labelCreateFlags.org, labelCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	l := &platform.Label{
+		OrgID:      orgID,
+		Name:       labelCreateFlags.name,
+		Properties: labelProperties(cmd, labelCreateFlags.color, labelCreateFlags.description),
+	}
+	if err := newLabelService(flags).CreateLabel(ctx, l); err != nil {
+		return fmt.Errorf("failed to create label: %v", err)
+	}
+
+	return writeLabels(l)
+}
+
+// LabelFindFlags define the Find Command
+type LabelFindFlags struct {
+	id    string
+	name  string
+	org   string
+	orgID string
+}
+
+var labelFindFlags LabelFindFlags
+
+func init() {
+	labelFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find labels",
+		RunE:  wrapCheckSetup(labelFindF),
+	}
+
+	labelFindCmd.Flags().StringVarP(&labelFindFlags.id, "id", "i", "", "The label ID")
+	labelFindCmd.Flags().StringVarP(&labelFindFlags.name, "name", "n", "", "The label name")
+	labelFindCmd.Flags().StringVarP(&labelFindFlags.orgID, "org-id", "", "", "The label organization ID")
+	labelFindCmd.Flags().StringVarP(&labelFindFlags.org, "org", "o", "", "The label organization name")
+
+	labelCmd.AddCommand(labelFindCmd)
+}
+
+func labelFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newLabelService(flags)
+
+	if labelFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(labelFindFlags.id); err != nil {
+			return fmt.Errorf("failed to decode label id %q: %v", labelFindFlags.id, err)
+		}
+
+		l, err := s.FindLabelByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find label with id %q: %v", id, err)
+		}
+		return writeLabels(l)
+	}
+
+	filter := platform.LabelFilter{
+		Name: labelFindFlags.name,
+	}
+	if labelFindFlags.org != "" || labelFindFlags.orgID != "" {
+		orgID, err := resolveOrgID(ctx, cmd, labelFindFlags.org, labelFindFlags.orgID)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	ls, err := s.FindLabels(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve labels: %v", err)
+	}
+
+	return writeLabels(ls...)
+}
+
+// LabelUpdateFlags define the Update Command
+type LabelUpdateFlags struct {
+	id          string
+	name        string
+	color       string
+	description string
+}
+
+var labelUpdateFlags LabelUpdateFlags
+
+func init() {
+	labelUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update label",
+		RunE:  wrapCheckSetup(labelUpdateF),
+	}
+
+	labelUpdateCmd.Flags().StringVarP(&labelUpdateFlags.id, "id", "i", "", "The label ID (required)")
+	labelUpdateCmd.Flags().StringVarP(&labelUpdateFlags.name, "name", "n", "", "New label name")
+	labelUpdateCmd.Flags().StringVarP(&labelUpdateFlags.color, "color", "c", "", "New label color")
+	labelUpdateCmd.Flags().StringVarP(&labelUpdateFlags.description, "description", "d", "", "New label description")
+	labelUpdateCmd.MarkFlagRequired("id")
+
+	labelCmd.AddCommand(labelUpdateCmd)
+}
+
+func labelUpdateF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(labelUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode label id %q: %v", labelUpdateFlags.id, err)
+	}
+
+	update := platform.LabelUpdate{
+		Name:       labelUpdateFlags.name,
+		Properties: labelProperties(cmd, labelUpdateFlags.color, labelUpdateFlags.description),
+	}
+
+	l, err := newLabelService(flags).UpdateLabel(context.Background(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update label: %v", err)
+	}
+
+	return writeLabels(l)
+}
+
+// LabelDeleteFlags define the Delete command
+type LabelDeleteFlags struct {
+	id string
+}
+
+var labelDeleteFlags LabelDeleteFlags
+
+func init() {
+	labelDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete label",
+		RunE:  wrapCheckSetup(labelDeleteF),
+	}
+
+	labelDeleteCmd.Flags().StringVarP(&labelDeleteFlags.id, "id", "i", "", "The label ID (required)")
+	labelDeleteCmd.MarkFlagRequired("id")
+
+	labelCmd.AddCommand(labelDeleteCmd)
+}
+
+func labelDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(labelDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode label id %q: %v", labelDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newLabelService(flags)
+	l, err := s.FindLabelByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find label with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteLabel(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete label with id %q: %v", id, err)
+	}
+
+	return writeLabels(l)
+}
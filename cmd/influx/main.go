@@ -28,13 +28,27 @@ var influxCmd = &cobra.Command{
 }
 
 func init() {
+	influxCmd.AddCommand(applyCmd)
 	influxCmd.AddCommand(authorizationCmd)
+	influxCmd.AddCommand(backupCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(checkCmd)
+	influxCmd.AddCommand(dashboardCmd)
+	influxCmd.AddCommand(deleteCmd)
+	influxCmd.AddCommand(exportCmd)
+	influxCmd.AddCommand(labelCmd)
+	influxCmd.AddCommand(notificationEndpointCmd)
+	influxCmd.AddCommand(notificationRuleCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
+	influxCmd.AddCommand(restoreCmd)
+	influxCmd.AddCommand(roleCmd)
+	influxCmd.AddCommand(scraperCmd)
+	influxCmd.AddCommand(secretCmd)
 	influxCmd.AddCommand(setupCmd)
 	influxCmd.AddCommand(taskCmd)
+	influxCmd.AddCommand(telegrafCmd)
 	influxCmd.AddCommand(userCmd)
 	influxCmd.AddCommand(writeCmd)
 	influxCmd.AddCommand(pingCmd)
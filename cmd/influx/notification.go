@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/spf13/cobra"
+)
+
+// notificationResource is implemented by checks, notification rules and
+// notification endpoints, which are all managed the same way.
+type notificationResource interface {
+	platform.Getter
+	Type() string
+}
+
+func writeNotificationResources(rs ...notificationResource) error {
+	if jsonOutput {
+		return writeJSON(rs)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Type",
+		"Status",
+		"Description",
+		"OrganizationID",
+	)
+	for _, r := range rs {
+		w.Write(map[string]interface{}{
+			"ID":             r.GetID().String(),
+			"Name":           r.GetName(),
+			"Type":           r.Type(),
+			"Status":         r.GetStatus(),
+			"Description":    r.GetDescription(),
+			"OrganizationID": r.GetOrgID().String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// NotificationCreateFlags define the Create Commands of checks, notification
+// rules and notification endpoints, which are read from a JSON file.
+type NotificationCreateFlags struct {
+	file  string
+	org   string
+	orgID string
+}
+
+func (f *NotificationCreateFlags) register(cmd *cobra.Command, resource string) {
+	cmd.Flags().StringVarP(&f.file, "file", "f", "", fmt.Sprintf("The JSON file of the %s (required)", resource))
+	cmd.Flags().StringVarP(&f.orgID, "org-id", "", "", fmt.Sprintf("The ID of the organization that owns the %s; defaults to the orgID of the file", resource))
+	cmd.Flags().StringVarP(&f.org, "org", "o", "", fmt.Sprintf("The name of the organization that owns the %s; defaults to the orgID of the file", resource))
+	cmd.MarkFlagRequired("file")
+}
+
+// setOrg sets the organization of r to the organization of the flags, if any.
+func (f *NotificationCreateFlags) setOrg(ctx context.Context, cmd *cobra.Command, r platform.Updater) error {
+	if f.org == "" && f.orgID == "" {
+		return nil
+	}
+
+	orgID, err := resolveOrgID(ctx, cmd, f.org, f.orgID)
+	if err != nil {
+		return err
+	}
+	r.SetOrgID(orgID)
+	return nil
+}
+
+// NotificationUpdateFlags define the Update Commands of checks, notification
+// rules and notification endpoints.
+type NotificationUpdateFlags struct {
+	id          string
+	name        string
+	description string
+	status      string
+}
+
+func (f *NotificationUpdateFlags) register(cmd *cobra.Command, resource string) {
+	cmd.Flags().StringVarP(&f.id, "id", "i", "", fmt.Sprintf("The %s ID (required)", resource))
+	cmd.Flags().StringVarP(&f.name, "name", "n", "", fmt.Sprintf("New %s name", resource))
+	cmd.Flags().StringVarP(&f.description, "description", "d", "", fmt.Sprintf("New %s description", resource))
+	cmd.Flags().StringVarP(&f.status, "status", "s", "", fmt.Sprintf("New %s status; active or inactive", resource))
+	cmd.MarkFlagRequired("id")
+}
+
+// update returns the ID and the changes of the update flags.
+func (f *NotificationUpdateFlags) update(cmd *cobra.Command, resource string) (id platform.ID, name, description *string, status *platform.Status, err error) {
+	if err := id.DecodeFromString(f.id); err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("failed to decode %s id %q: %v", resource, f.id, err)
+	}
+
+	if cmd.Flags().Changed("name") {
+		name = &f.name
+	}
+	if cmd.Flags().Changed("description") {
+		description = &f.description
+	}
+	if cmd.Flags().Changed("status") {
+		s := platform.Status(f.status)
+		if err := s.Valid(); err != nil {
+			return 0, nil, nil, nil, err
+		}
+		status = &s
+	}
+	return id, name, description, status, nil
+}
+
+// NotificationFindFlags define the Find Commands of checks, notification
+// rules and notification endpoints.
+type NotificationFindFlags struct {
+	id    string
+	org   string
+	orgID string
+}
+
+func (f *NotificationFindFlags) register(cmd *cobra.Command, resource string) {
+	cmd.Flags().StringVarP(&f.id, "id", "i", "", fmt.Sprintf("The %s ID", resource))
+	cmd.Flags().StringVarP(&f.orgID, "org-id", "", "", fmt.Sprintf("The %s organization ID", resource))
+	cmd.Flags().StringVarP(&f.org, "org", "o", "", fmt.Sprintf("The %s organization name", resource))
+}
+
+// NotificationDeleteFlags define the Delete Commands of checks, notification
+// rules and notification endpoints.
+type NotificationDeleteFlags struct {
+	id string
+}
+
+func (f *NotificationDeleteFlags) register(cmd *cobra.Command, resource string) {
+	cmd.Flags().StringVarP(&f.id, "id", "i", "", fmt.Sprintf("The %s ID (required)", resource))
+	cmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/spf13/cobra"
+)
+
+// Notification Endpoint Command
+var notificationEndpointCmd = &cobra.Command{
+	Use:   "notification-endpoint",
+	Short: "Notification endpoint management commands",
+	Run:   notificationEndpointF,
+}
+
+func notificationEndpointF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(notificationEndpointCmd)
+}
+
+func newNotificationEndpointService(f Flags) *http.NotificationEndpointService {
+	return &http.NotificationEndpointService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeNotificationEndpoints(edps ...platform.NotificationEndpoint) error {
+	rs := make([]notificationResource, len(edps))
+	for i, edp := range edps {
+		rs[i] = edp
+	}
+	return writeNotificationResources(rs...)
+}
+
+var notificationEndpointCreateFlags NotificationCreateFlags
+
+func init() {
+	notificationEndpointCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create notification endpoint",
+		RunE:  wrapCheckSetup(notificationEndpointCreateF),
+	}
+
+	notificationEndpointCreateFlags.register(notificationEndpointCreateCmd, "notification endpoint")
+
+	notificationEndpointCmd.AddCommand(notificationEndpointCreateCmd)
+}
+
+func notificationEndpointCreateF(cmd *cobra.Command, args []string) error {
+	b, err := ioutil.ReadFile(notificationEndpointCreateFlags.file)
+	if err != nil {
+		return err
+	}
+
+	edp, err := endpoint.UnmarshalJSON(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse notification endpoint %s: %v", notificationEndpointCreateFlags.file, err)
+	}
+
+	ctx := context.Background()
+	if err := notificationEndpointCreateFlags.setOrg(ctx, cmd, edp); err != nil {
+		return err
+	}
+
+	if err := newNotificationEndpointService(flags).CreateNotificationEndpoint(ctx, edp, 0); err != nil {
+		return fmt.Errorf("failed to create notification endpoint: %v", err)
+	}
+
+	return writeNotificationEndpoints(edp)
+}
+
+var notificationEndpointFindFlags NotificationFindFlags
+
+func init() {
+	notificationEndpointFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find notification endpoints",
+		RunE:  wrapCheckSetup(notificationEndpointFindF),
+	}
+
+	notificationEndpointFindFlags.register(notificationEndpointFindCmd, "notification endpoint")
+
+	notificationEndpointCmd.AddCommand(notificationEndpointFindCmd)
+}
+
+func notificationEndpointFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newNotificationEndpointService(flags)
+
+	if notificationEndpointFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(notificationEndpointFindFlags.id); err != nil {
+			return fmt.Errorf("failed to decode notification endpoint id %q: %v", notificationEndpointFindFlags.id, err)
+		}
+
+		edp, err := s.FindNotificationEndpointByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find notification endpoint with id %q: %v", id, err)
+		}
+		return writeNotificationEndpoints(edp)
+	}
+
+	filter := platform.NotificationEndpointFilter{}
+	var err error
+	filter.OrgID, filter.Org, err = orgFilter(notificationEndpointFindFlags.org, notificationEndpointFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	edps, _, err := s.FindNotificationEndpoints(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve notification endpoints: %v", err)
+	}
+
+	return writeNotificationEndpoints(edps...)
+}
+
+var notificationEndpointUpdateFlags NotificationUpdateFlags
+
+func init() {
+	notificationEndpointUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update notification endpoint",
+		RunE:  wrapCheckSetup(notificationEndpointUpdateF),
+	}
+
+	notificationEndpointUpdateFlags.register(notificationEndpointUpdateCmd, "notification endpoint")
+
+	notificationEndpointCmd.AddCommand(notificationEndpointUpdateCmd)
+}
+
+func notificationEndpointUpdateF(cmd *cobra.Command, args []string) error {
+	id, name, description, status, err := notificationEndpointUpdateFlags.update(cmd, "notification endpoint")
+	if err != nil {
+		return err
+	}
+
+	edp, err := newNotificationEndpointService(flags).PatchNotificationEndpoint(context.Background(), id, platform.NotificationEndpointUpdate{
+		Name:        name,
+		Description: description,
+		Status:      status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notification endpoint: %v", err)
+	}
+
+	return writeNotificationEndpoints(edp)
+}
+
+var notificationEndpointDeleteFlags NotificationDeleteFlags
+
+func init() {
+	notificationEndpointDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete notification endpoint",
+		RunE:  wrapCheckSetup(notificationEndpointDeleteF),
+	}
+
+	notificationEndpointDeleteFlags.register(notificationEndpointDeleteCmd, "notification endpoint")
+
+	notificationEndpointCmd.AddCommand(notificationEndpointDeleteCmd)
+}
+
+func notificationEndpointDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(notificationEndpointDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode notification endpoint id %q: %v", notificationEndpointDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newNotificationEndpointService(flags)
+	edp, err := s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find notification endpoint with id %q: %v", id, err)
+	}
+
+	if _, _, err := s.DeleteNotificationEndpoint(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete notification endpoint with id %q: %v", id, err)
+	}
+
+	return writeNotificationEndpoints(edp)
+}
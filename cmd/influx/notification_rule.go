@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/notification/rule"
+	"github.com/spf13/cobra"
+)
+
+// Notification Rule Command
+var notificationRuleCmd = &cobra.Command{
+	Use:   "notification-rule",
+	Short: "Notification rule management commands",
+	Run:   notificationRuleF,
+}
+
+func notificationRuleF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(notificationRuleCmd)
+}
+
+func newNotificationRuleService(f Flags) *http.NotificationRuleService {
+	return &http.NotificationRuleService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeNotificationRules(nrs ...platform.NotificationRule) error {
+	rs := make([]notificationResource, len(nrs))
+	for i, nr := range nrs {
+		rs[i] = nr
+	}
+	return writeNotificationResources(rs...)
+}
+
+var notificationRuleCreateFlags NotificationCreateFlags
+
+func init() {
+	notificationRuleCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create notification rule",
+		RunE:  wrapCheckSetup(notificationRuleCreateF),
+	}
+
+	notificationRuleCreateFlags.register(notificationRuleCreateCmd, "notification rule")
+
+	notificationRuleCmd.AddCommand(notificationRuleCreateCmd)
+}
+
+func notificationRuleCreateF(cmd *cobra.Command, args []string) error {
+	b, err := ioutil.ReadFile(notificationRuleCreateFlags.file)
+	if err != nil {
+		return err
+	}
+
+	nr, err := rule.UnmarshalJSON(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse notification rule %s: %v", notificationRuleCreateFlags.file, err)
+	}
+
+	ctx := context.Background()
+	if err := notificationRuleCreateFlags.setOrg(ctx, cmd, nr); err != nil {
+		return err
+	}
+
+	if err := newNotificationRuleService(flags).CreateNotificationRule(ctx, nr, 0); err != nil {
+		return fmt.Errorf("failed to create notification rule: %v", err)
+	}
+
+	return writeNotificationRules(nr)
+}
+
+var notificationRuleFindFlags NotificationFindFlags
+
+func init() {
+	notificationRuleFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find notification rules",
+		RunE:  wrapCheckSetup(notificationRuleFindF),
+	}
+
+	notificationRuleFindFlags.register(notificationRuleFindCmd, "notification rule")
+
+	notificationRuleCmd.AddCommand(notificationRuleFindCmd)
+}
+
+func notificationRuleFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newNotificationRuleService(flags)
+
+	if notificationRuleFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(notificationRuleFindFlags.id); err != nil {
+			return fmt.Errorf("failed to decode notification rule id %q: %v", notificationRuleFindFlags.id, err)
+		}
+
+		nr, err := s.FindNotificationRuleByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find notification rule with id %q: %v", id, err)
+		}
+		return writeNotificationRules(nr)
+	}
+
+	filter := platform.NotificationRuleFilter{}
+	var err error
+	filter.OrgID, filter.Organization, err = orgFilter(notificationRuleFindFlags.org, notificationRuleFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	nrs, _, err := s.FindNotificationRules(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve notification rules: %v", err)
+	}
+
+	return writeNotificationRules(nrs...)
+}
+
+var notificationRuleUpdateFlags NotificationUpdateFlags
+
+func init() {
+	notificationRuleUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update notification rule",
+		RunE:  wrapCheckSetup(notificationRuleUpdateF),
+	}
+
+	notificationRuleUpdateFlags.register(notificationRuleUpdateCmd, "notification rule")
+
+	notificationRuleCmd.AddCommand(notificationRuleUpdateCmd)
+}
+
+func notificationRuleUpdateF(cmd *cobra.Command, args []string) error {
+	id, name, description, status, err := notificationRuleUpdateFlags.update(cmd, "notification rule")
+	if err != nil {
+		return err
+	}
+
+	nr, err := newNotificationRuleService(flags).PatchNotificationRule(context.Background(), id, platform.NotificationRuleUpdate{
+		Name:        name,
+		Description: description,
+		Status:      status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notification rule: %v", err)
+	}
+
+	return writeNotificationRules(nr)
+}
+
+var notificationRuleDeleteFlags NotificationDeleteFlags
+
+func init() {
+	notificationRuleDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete notification rule",
+		RunE:  wrapCheckSetup(notificationRuleDeleteF),
+	}
+
+	notificationRuleDeleteFlags.register(notificationRuleDeleteCmd, "notification rule")
+
+	notificationRuleCmd.AddCommand(notificationRuleDeleteCmd)
+}
+
+func notificationRuleDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(notificationRuleDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode notification rule id %q: %v", notificationRuleDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newNotificationRuleService(flags)
+	nr, err := s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find notification rule with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteNotificationRule(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete notification rule with id %q: %v", id, err)
+	}
+
+	return writeNotificationRules(nr)
+}
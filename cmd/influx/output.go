@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/spf13/cobra"
+)
+
+// jsonOutput is set by the --json flag of the resource commands that can
+// print their results as JSON instead of as a table.
+var jsonOutput bool
+
+// addJSONFlag adds the --json flag to cmd and all of its subcommands.
+func addJSONFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output data as JSON")
+}
+
+// writeJSON writes v to stdout as indented JSON.
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(v)
+}
+
+// orgFilter returns the organization given by either its name or its ID to
+// filter resources by. Both are nil if neither is given.
+func orgFilter(org, orgID string) (*platform.ID, *string, error) {
+	if org != "" && orgID != "" {
+		return nil, nil, fmt.Errorf("must specify at most one of org and org-id")
+	}
+
+	if orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode org id %q: %v", orgID, err)
+		}
+		return id, nil, nil
+	}
+	if org != "" {
+		return nil, &org, nil
+	}
+	return nil, nil, nil
+}
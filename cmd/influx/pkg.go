@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/pkger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the resources of an organization as a package",
+	Long: `Export the buckets, checks, dashboards, labels, notification rules,
+telegraf configs and variables of an organization as a package.
+The package is written as YAML, or as JSON if the file has a .json extension.`,
+	Args: cobra.NoArgs,
+	RunE: wrapCheckSetup(exportF),
+}
+
+var exportFlags struct {
+	OrgID       string
+	Org         string
+	File        string
+	Name        string
+	Version     string
+	Description string
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a package to an organization",
+	Long: `Create and update the resources of an organization that differ from a package.
+Resources are matched by kind and name; resources of the organization
+that are not in the package are left untouched.`,
+	Args: cobra.NoArgs,
+	RunE: wrapCheckSetup(applyF),
+}
+
+var applyFlags struct {
+	OrgID  string
+	Org    string
+	File   string
+	DryRun bool
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVar(&exportFlags.OrgID, "org-id", "", "The ID of the organization to export")
+	viper.BindEnv("ORG_ID")
+	if h := viper.GetString("ORG_ID"); h != "" {
+		exportFlags.OrgID = h
+	}
+
+	exportCmd.PersistentFlags().StringVarP(&exportFlags.Org, "org", "o", "", "The name of the organization to export")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		exportFlags.Org = h
+	}
+
+	exportCmd.PersistentFlags().StringVarP(&exportFlags.File, "file", "f", "", "The file to write the package to; defaults to stdout")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Name, "name", "", "The name of the package")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Version, "version", "", "The version of the package")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Description, "description", "", "The description of the package")
+
+	applyCmd.PersistentFlags().StringVar(&applyFlags.OrgID, "org-id", "", "The ID of the organization to apply the package to")
+	viper.BindEnv("ORG_ID")
+	if h := viper.GetString("ORG_ID"); h != "" {
+		applyFlags.OrgID = h
+	}
+
+	applyCmd.PersistentFlags().StringVarP(&applyFlags.Org, "org", "o", "", "The name of the organization to apply the package to")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		applyFlags.Org = h
+	}
+
+	applyCmd.PersistentFlags().StringVarP(&applyFlags.File, "file", "f", "", "The package file; YAML, or JSON if it has a .json extension")
+	applyCmd.MarkPersistentFlagRequired("file")
+	applyCmd.PersistentFlags().BoolVar(&applyFlags.DryRun, "dry-run", false, "Print the changes applying the package would make without making them")
+}
+
+func newPkgerService() *http.PkgerService {
+	return &http.PkgerService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+}
+
+// pkgEncoding returns the encoding of a package file from its extension.
+func pkgEncoding(path string) pkger.Encoding {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return pkger.EncodingJSON
+	}
+	return pkger.EncodingYAML
+}
+
+// resolveOrgID returns the ID of the organization given by either its name or its ID.
+func resolveOrgID(ctx context.Context, cmd *cobra.Command, org, orgID string) (platform.ID, error) {
+	if (org == "") == (orgID == "") {
+		cmd.Usage()
+		return 0, fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		return *id, nil
+	}
+
+	orgSvc, err := newOrganizationService(flags)
+	if err != nil {
+		return 0, err
+	}
+	o, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find organization %q: %v", org, err)
+	}
+	return o.ID, nil
+}
+
+func exportF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	orgID, err := resolveOrgID(ctx, cmd, exportFlags.Org, exportFlags.OrgID)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := newPkgerService().CreatePkg(ctx, orgID, pkger.Metadata{
+		Name:        exportFlags.Name,
+		Version:     exportFlags.Version,
+		Description: exportFlags.Description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export package: %v", err)
+	}
+
+	b, err := pkg.Encode(pkgEncoding(exportFlags.File))
+	if err != nil {
+		return fmt.Errorf("failed to encode package: %v", err)
+	}
+
+	if exportFlags.File == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(exportFlags.File, b, 0644)
+}
+
+func applyF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	orgID, err := resolveOrgID(ctx, cmd, applyFlags.Org, applyFlags.OrgID)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(applyFlags.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	pkg, err := pkger.Parse(pkgEncoding(applyFlags.File), f)
+	if err != nil {
+		return fmt.Errorf("failed to parse package %s: %v", applyFlags.File, err)
+	}
+
+	s := newPkgerService()
+	var diff pkger.Diff
+	if applyFlags.DryRun {
+		diff, err = s.DryRun(ctx, orgID, pkg)
+	} else {
+		diff, err = s.Apply(ctx, orgID, pkg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply package: %v", err)
+	}
+
+	printPkgDiff(os.Stdout, diff, applyFlags.DryRun)
+	return nil
+}
+
+// printPkgDiff prints the resources that are created or updated, followed by a summary.
+func printPkgDiff(w io.Writer, diff pkger.Diff, dryRun bool) {
+	var created, updated, unchanged int
+	for _, e := range diff {
+		switch e.Change {
+		case pkger.ChangeCreate:
+			created++
+			fmt.Fprintf(w, "+ %s %q\n", e.Kind, e.Name)
+		case pkger.ChangeUpdate:
+			updated++
+			fmt.Fprintf(w, "~ %s %q (%s)\n", e.Kind, e.Name, strings.Join(e.Fields, ", "))
+		default:
+			unchanged++
+		}
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "%d to create, %d to update, %d unchanged\n", created, updated, unchanged)
+		return
+	}
+	fmt.Fprintf(w, "%d created, %d updated, %d unchanged\n", created, updated, unchanged)
+}
@@ -3,9 +3,17 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,8 +29,10 @@ or execute a literal Flux query contained in a file by specifying the file prefi
 }
 
 var queryFlags struct {
-	OrgID string
-	Org   string
+	OrgID   string
+	Org     string
+	Explain bool
+	Analyze bool
 }
 
 func init() {
@@ -37,6 +47,10 @@ func init() {
 	if h := viper.GetString("ORG"); h != "" {
 		queryFlags.Org = h
 	}
+
+	queryCmd.Flags().BoolVar(&queryFlags.Explain, "explain", false, "Print the plan of the query instead of its results")
+	queryCmd.Flags().BoolVar(&queryFlags.Analyze, "analyze", false, "Execute the query and print its plan with the profile of each node instead of its results")
+	queryCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the plan of the query as JSON")
 }
 
 func fluxQueryF(cmd *cobra.Command, args []string) error {
@@ -76,6 +90,10 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 		orgID = o.ID
 	}
 
+	if queryFlags.Explain || queryFlags.Analyze {
+		return explainQuery(orgID, q, queryFlags.Analyze)
+	}
+
 	r, err := getFluxREPL(flags.host, flags.token, orgID)
 	if err != nil {
 		return fmt.Errorf("failed to get the flux REPL: %v", err)
@@ -87,3 +105,208 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func explainQuery(orgID platform.ID, q string, analyze bool) error {
+	s := &http.ExplainService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	e, err := s.Explain(context.Background(), &query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: q},
+	}, analyze)
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %v", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(e)
+	}
+
+	fmt.Println("Logical plan:")
+	writePlanNodes(e.Logical, false)
+	fmt.Println()
+	fmt.Println("Physical plan:")
+	writePlanNodes(e.Physical, analyze)
+	fmt.Println()
+	fmt.Println("Applied rules:")
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Phase",
+		"Rule",
+		"Node",
+		"Result",
+	)
+	for _, r := range e.Rules {
+		w.Write(map[string]interface{}{
+			"Phase":  r.Phase,
+			"Rule":   r.Name,
+			"Node":   r.Node,
+			"Result": r.Result,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// writePlanNodes writes the nodes of a plan, with their profiles if profiled is
+// true.
+func writePlanNodes(nodes []*query.PlanNode, profiled bool) {
+	w := internal.NewTabWriter(os.Stdout)
+	headers := []string{
+		"ID",
+		"Kind",
+		"Predecessors",
+		"Details",
+	}
+	if profiled {
+		headers = append(headers,
+			"Tables",
+			"Rows",
+			"Bytes",
+			"FirstTable",
+			"Finished",
+			"ScannedValues",
+			"ScannedBytes",
+		)
+	}
+	w.WriteHeaders(headers...)
+	for _, n := range nodes {
+		m := map[string]interface{}{
+			"ID":           n.ID,
+			"Kind":         n.Kind,
+			"Predecessors": strings.Join(n.Predecessors, ","),
+			"Details":      n.Details,
+		}
+		if p := n.Profile; p != nil {
+			m["Tables"] = p.Tables
+			m["Rows"] = p.Rows
+			m["Bytes"] = p.Bytes
+			m["FirstTable"] = p.FirstTable.Round(time.Microsecond)
+			m["Finished"] = p.Finished.Round(time.Microsecond)
+			m["ScannedValues"] = p.ScannedValues
+			m["ScannedBytes"] = p.ScannedBytes
+		} else if profiled {
+			for _, h := range headers[4:] {
+				m[h] = ""
+			}
+		}
+		w.Write(m)
+	}
+	w.Flush()
+}
+
+func newRunningQueryService(f Flags) *http.RunningQueryService {
+	return &http.RunningQueryService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func init() {
+	queryPsCmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List the running queries",
+		Long: `List the running queries of the organizations the token can read,
+or of the organization given with --org or --org-id.`,
+		Args: cobra.NoArgs,
+		RunE: wrapCheckSetup(queryPsF),
+	}
+	addJSONFlag(queryPsCmd)
+
+	queryKillCmd := &cobra.Command{
+		Use:   "kill [query ID]",
+		Short: "Cancel a running query",
+		Args:  cobra.ExactArgs(1),
+		RunE:  wrapCheckSetup(queryKillF),
+	}
+
+	queryCmd.AddCommand(queryPsCmd, queryKillCmd)
+}
+
+func queryPsF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for query ps command")
+	}
+
+	orgID, org, err := orgFilter(queryFlags.Org, queryFlags.OrgID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if org != nil {
+		orgSvc, err := newOrganizationService(flags)
+		if err != nil {
+			return fmt.Errorf("failed to initialized organization service client: %v", err)
+		}
+		o, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: org})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve organization %q: %v", *org, err)
+		}
+		orgID = &o.ID
+	}
+
+	rqs, err := newRunningQueryService(flags).FindRunningQueries(ctx, query.RunningQueryFilter{OrganizationID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to list running queries: %v", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(rqs)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"OrganizationID",
+		"UserID",
+		"State",
+		"Elapsed",
+		"Queued",
+		"Memory",
+		"Query",
+	)
+	for _, rq := range rqs {
+		w.Write(map[string]interface{}{
+			"ID":             rq.ID,
+			"OrganizationID": validIDString(rq.OrganizationID),
+			"UserID":         validIDString(rq.UserID),
+			"State":          rq.State,
+			"Elapsed":        rq.Elapsed.Round(time.Millisecond),
+			"Queued":         rq.QueueDuration.Round(time.Millisecond),
+			"Memory":         rq.MemoryAllocated,
+			"Query":          rq.Query,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// validIDString returns the string of id, or an empty string if it is not set.
+func validIDString(id platform.ID) string {
+	if !id.Valid() {
+		return ""
+	}
+	return id.String()
+}
+
+func queryKillF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for query kill command")
+	}
+
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to decode query id %q: %v", args[0], err)
+	}
+
+	if err := newRunningQueryService(flags).CancelRunningQuery(context.Background(), id); err != nil {
+		return fmt.Errorf("failed to cancel query %d: %v", id, err)
+	}
+
+	fmt.Printf("Query %d canceled\n", id)
+	return nil
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [path]",
+	Short: "Restore data to InfluxDB from a backup",
+	Long: `Restores the organizations, buckets and TSM data of a backup created by
+influx backup while the server is running. Organizations and buckets are
+matched by name to those on the target server and created if they do not
+exist, so IDs are remapped when the target server already has data. The index
+is rebuilt from the restored data.
+
+Other metadata, such as users, tokens and dashboards, is not restored. To
+restore a complete server, stop influxd, replace its bolt file with the
+influxd.bolt file of the backup, start influxd and then run influx restore.`,
+	Args: cobra.ExactArgs(1),
+	RunE: wrapCheckSetup(restoreF),
+}
+
+var restoreFlags struct {
+	Org       string
+	Bucket    string
+	NewOrg    string
+	NewBucket string
+}
+
+func init() {
+	restoreCmd.PersistentFlags().StringVarP(&restoreFlags.Org, "org", "o", "", "The name of the organization in the backup to restore")
+	restoreCmd.PersistentFlags().StringVarP(&restoreFlags.Bucket, "bucket", "b", "", "The name of the bucket in the backup to restore")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.NewOrg, "new-org", "", "The name of the organization to restore to, if different from the backup")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.NewBucket, "new-bucket", "", "The name of the bucket to restore to, if different from the backup")
+}
+
+// restoreTarget maps a bucket in a backup to the bucket its data is restored to.
+type restoreTarget struct {
+	src *platform.Bucket
+	dst *platform.Bucket
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if restoreFlags.Bucket != "" && restoreFlags.Org == "" {
+		return fmt.Errorf("please specify org to restore a bucket")
+	}
+	if restoreFlags.NewOrg != "" && restoreFlags.Org == "" {
+		return fmt.Errorf("please specify org to restore to new-org")
+	}
+	if restoreFlags.NewBucket != "" && restoreFlags.Bucket == "" {
+		return fmt.Errorf("please specify bucket to restore to new-bucket")
+	}
+
+	path := args[0]
+	manifest, err := readBackupManifest(path)
+	if err != nil {
+		return err
+	}
+
+	src, closeFn, err := openBackupKVService(filepath.Join(path, http.BackupKVFile))
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	targets, err := newRestoreTargets(ctx, src, manifest.Filter)
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+
+	rs := &http.RestoreService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	for _, file := range files {
+		if err := restoreTSMFile(ctx, rs, file, targets); err != nil {
+			return fmt.Errorf("failed to restore %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	for _, t := range targets {
+		fmt.Printf("Restored bucket %s to %s\n", t.src.Name, t.dst.ID)
+	}
+	return nil
+}
+
+func readBackupManifest(path string) (*backupManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, backupManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %v", err)
+	}
+
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode backup manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+// openBackupKVService opens the metadata snapshot of a backup.
+func openBackupKVService(path string) (*kv.Service, func(), error) {
+	store := bolt.NewKVStore(path)
+	if err := store.Open(context.Background()); err != nil {
+		return nil, nil, fmt.Errorf("failed to open backup metadata: %v", err)
+	}
+	return kv.NewService(store), func() { store.Close() }, nil
+}
+
+// newRestoreTargets finds the buckets in the backup selected by the flags and
+// the manifest filter, and finds or creates the bucket on the server that each
+// is restored to.
+func newRestoreTargets(ctx context.Context, src *kv.Service, filter platform.BackupFilter) ([]restoreTarget, error) {
+	orgFilter := platform.OrganizationFilter{ID: filter.OrgID}
+	if restoreFlags.Org != "" {
+		orgFilter.Name = &restoreFlags.Org
+	}
+	orgs, _, err := src.FindOrganizations(ctx, orgFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organizations in backup: %v", err)
+	}
+
+	orgSvc := &http.OrganizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	bucketSvc := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var targets []restoreTarget
+	for _, org := range orgs {
+		bucketFilter := platform.BucketFilter{OrganizationID: &org.ID, ID: filter.BucketID}
+		if restoreFlags.Bucket != "" {
+			bucketFilter.Name = &restoreFlags.Bucket
+		}
+		buckets, _, err := src.FindBuckets(ctx, bucketFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find buckets in backup: %v", err)
+		}
+
+		var dstOrg *platform.Organization
+		for _, b := range buckets {
+			// System buckets belong to the server and are never restored.
+			if b.IsSystem() || b.OrgID != org.ID {
+				continue
+			}
+
+			if dstOrg == nil {
+				name := org.Name
+				if restoreFlags.NewOrg != "" {
+					name = restoreFlags.NewOrg
+				}
+				if dstOrg, err = findOrCreateOrganization(ctx, orgSvc, name, org); err != nil {
+					return nil, err
+				}
+			}
+
+			name := b.Name
+			if restoreFlags.NewBucket != "" {
+				name = restoreFlags.NewBucket
+			}
+			dst, err := findOrCreateBucket(ctx, bucketSvc, dstOrg.ID, name, b)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, restoreTarget{src: b, dst: dst})
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no buckets to restore were found in the backup")
+	}
+	return targets, nil
+}
+
+func findOrCreateOrganization(ctx context.Context, s platform.OrganizationService, name string, src *platform.Organization) (*platform.Organization, error) {
+	org, err := s.FindOrganization(ctx, platform.OrganizationFilter{Name: &name})
+	if err == nil {
+		return org, nil
+	} else if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, fmt.Errorf("failed to find organization %q: %v", name, err)
+	}
+
+	org = &platform.Organization{
+		Name:        name,
+		Description: src.Description,
+	}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to create organization %q: %v", name, err)
+	}
+	return org, nil
+}
+
+func findOrCreateBucket(ctx context.Context, s platform.BucketService, orgID platform.ID, name string, src *platform.Bucket) (*platform.Bucket, error) {
+	b, err := s.FindBucket(ctx, platform.BucketFilter{OrganizationID: &orgID, Name: &name})
+	if err == nil {
+		return b, nil
+	} else if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, fmt.Errorf("failed to find bucket %q: %v", name, err)
+	}
+
+	b = &platform.Bucket{
+		OrgID:           orgID,
+		Name:            name,
+		Description:     src.Description,
+		RetentionPeriod: src.RetentionPeriod,
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		return nil, fmt.Errorf("failed to create bucket %q: %v", name, err)
+	}
+	return b, nil
+}
+
+// restoreTSMFile uploads the data of each target bucket in the TSM file at path,
+// rewriting each key for the bucket it is restored to.
+func restoreTSMFile(ctx context.Context, s platform.RestoreService, path string, targets []restoreTarget) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer r.Close()
+
+	for _, t := range targets {
+		opts := storage.BucketCopyOptions(t.src.OrgID, t.src.ID, t.dst.OrgID, t.dst.ID)
+		if err := restoreBucketData(ctx, s, r, opts, t.dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreBucketData(ctx context.Context, s platform.RestoreService, r *tsm1.TSMReader, opts tsm1.CopyOptions, dst *platform.Bucket) error {
+	f, err := ioutil.TempFile("", "influx-restore-*."+tsm1.TSMFileExtension)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer os.Remove(tsm1.StatsFilename(f.Name()))
+
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	n, err := tsm1.Copy(r, w, opts)
+	if err == nil && n > 0 {
+		err = w.WriteIndex()
+	}
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || n == 0 {
+		return err
+	}
+
+	data, err := os.Open(f.Name())
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	return s.RestoreBucket(ctx, dst.OrgID, dst.ID, data)
+}
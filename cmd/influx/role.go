@@ -0,0 +1,473 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Role Command
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Role management commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
+func init() {
+	addJSONFlag(roleCmd)
+}
+
+const rolePermissionUsage = "Permission granted by the role as action:type[:id=<id>|:labels=<label>[,<label>...]], e.g. write:buckets:labels=team=infra (repeatable)"
+
+func newRoleService(f Flags) *http.RoleService {
+	return &http.RoleService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeRoles(rs ...*platform.Role) error {
+	if jsonOutput {
+		return writeJSON(rs)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Description",
+		"OrganizationID",
+		"Permissions",
+	)
+	for _, r := range rs {
+		ps := []string{}
+		for _, p := range r.Permissions {
+			ps = append(ps, formatRolePermission(p))
+		}
+		w.Write(map[string]interface{}{
+			"ID":             r.ID.String(),
+			"Name":           r.Name,
+			"Description":    r.Description,
+			"OrganizationID": r.OrgID.String(),
+			"Permissions":    ps,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// parseRolePermission parses a permission of the form
+// action:type[:id=<id>|:labels=<label>[,<label>...]].
+func parseRolePermission(s string) (platform.RolePermission, error) {
+	var p platform.RolePermission
+
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return p, fmt.Errorf("invalid permission %q, expected action:type", s)
+	}
+	p.Action = platform.Action(parts[0])
+	p.Resource.Type = platform.ResourceType(parts[1])
+
+	if len(parts) == 3 {
+		sel := parts[2]
+		switch {
+		case strings.HasPrefix(sel, "id="):
+			id, err := platform.IDFromString(strings.TrimPrefix(sel, "id="))
+			if err != nil {
+				return p, fmt.Errorf("invalid resource id of permission %q: %v", s, err)
+			}
+			p.Resource.ID = id
+		case strings.HasPrefix(sel, "labels="):
+			p.Resource.Labels = strings.Split(strings.TrimPrefix(sel, "labels="), ",")
+		default:
+			return p, fmt.Errorf("invalid resource selector of permission %q, expected id=<id> or labels=<labels>", s)
+		}
+	}
+
+	if err := p.Valid(); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func parseRolePermissions(ss []string) ([]platform.RolePermission, error) {
+	ps := make([]platform.RolePermission, 0, len(ss))
+	for _, s := range ss {
+		p, err := parseRolePermission(s)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// formatRolePermission is the inverse of parseRolePermission.
+func formatRolePermission(p platform.RolePermission) string {
+	s := fmt.Sprintf("%s:%s", p.Action, p.Resource.Type)
+	if p.Resource.ID != nil {
+		s += ":id=" + p.Resource.ID.String()
+	}
+	if len(p.Resource.Labels) > 0 {
+		s += ":labels=" + strings.Join(p.Resource.Labels, ",")
+	}
+	return s
+}
+
+func decodeRoleID(s string) (platform.ID, error) {
+	var id platform.ID
+	if err := id.DecodeFromString(s); err != nil {
+		return 0, fmt.Errorf("failed to decode role id %q: %v", s, err)
+	}
+	return id, nil
+}
+
+// RoleCreateFlags define the Create Command
+type RoleCreateFlags struct {
+	name        string
+	description string
+	org         string
+	orgID       string
+	permissions []string
+}
+
+var roleCreateFlags RoleCreateFlags
+
+func init() {
+	roleCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create role",
+		RunE:  wrapCheckSetup(roleCreateF),
+	}
+
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.name, "name", "n", "", "Name of role that will be created")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.description, "description", "d", "", "Description of role that will be created")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.orgID, "org-id", "", "", "The ID of the organization of the role")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.org, "org", "o", "", "The name of the organization of the role")
+	roleCreateCmd.Flags().StringArrayVarP(&roleCreateFlags.permissions, "permission", "p", []string{}, rolePermissionUsage)
+	roleCreateCmd.MarkFlagRequired("name")
+
+	roleCmd.AddCommand(roleCreateCmd)
+}
+
+func roleCreateF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	orgID, err := resolveOrgID(ctx, cmd, roleCreateFlags.org, roleCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	ps, err := parseRolePermissions(roleCreateFlags.permissions)
+	if err != nil {
+		return err
+	}
+
+	r := &platform.Role{
+		OrgID:       orgID,
+		Name:        roleCreateFlags.name,
+		Description: roleCreateFlags.description,
+		Permissions: ps,
+	}
+	if err := newRoleService(flags).CreateRole(ctx, r); err != nil {
+		return fmt.Errorf("failed to create role: %v", err)
+	}
+
+	return writeRoles(r)
+}
+
+// RoleFindFlags define the Find Command
+type RoleFindFlags struct {
+	id    string
+	name  string
+	org   string
+	orgID string
+}
+
+var roleFindFlags RoleFindFlags
+
+func init() {
+	roleFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find roles",
+		RunE:  wrapCheckSetup(roleFindF),
+	}
+
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.id, "id", "i", "", "The role ID")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.name, "name", "n", "", "The role name")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.orgID, "org-id", "", "", "The role organization ID")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.org, "org", "o", "", "The role organization name")
+
+	roleCmd.AddCommand(roleFindCmd)
+}
+
+func roleFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newRoleService(flags)
+
+	if roleFindFlags.id != "" {
+		id, err := decodeRoleID(roleFindFlags.id)
+		if err != nil {
+			return err
+		}
+
+		r, err := s.FindRoleByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find role with id %q: %v", id, err)
+		}
+		return writeRoles(r)
+	}
+
+	var filter platform.RoleFilter
+	if roleFindFlags.name != "" {
+		filter.Name = &roleFindFlags.name
+	}
+	if roleFindFlags.org != "" || roleFindFlags.orgID != "" {
+		orgID, err := resolveOrgID(ctx, cmd, roleFindFlags.org, roleFindFlags.orgID)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	rs, _, err := s.FindRoles(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve roles: %v", err)
+	}
+
+	return writeRoles(rs...)
+}
+
+// RoleUpdateFlags define the Update Command
+type RoleUpdateFlags struct {
+	id          string
+	name        string
+	description string
+	permissions []string
+}
+
+var roleUpdateFlags RoleUpdateFlags
+
+func init() {
+	roleUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update role",
+		RunE:  wrapCheckSetup(roleUpdateF),
+	}
+
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.id, "id", "i", "", "The role ID (required)")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.name, "name", "n", "", "New role name")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.description, "description", "d", "", "New role description")
+	roleUpdateCmd.Flags().StringArrayVarP(&roleUpdateFlags.permissions, "permission", "p", []string{}, rolePermissionUsage+", replaces the permissions of the role")
+	roleUpdateCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleUpdateCmd)
+}
+
+func roleUpdateF(cmd *cobra.Command, args []string) error {
+	id, err := decodeRoleID(roleUpdateFlags.id)
+	if err != nil {
+		return err
+	}
+
+	var upd platform.RoleUpdate
+	if cmd.Flags().Changed("name") {
+		upd.Name = &roleUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		upd.Description = &roleUpdateFlags.description
+	}
+	if cmd.Flags().Changed("permission") {
+		ps, err := parseRolePermissions(roleUpdateFlags.permissions)
+		if err != nil {
+			return err
+		}
+		upd.Permissions = &ps
+	}
+
+	r, err := newRoleService(flags).UpdateRole(context.Background(), id, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %v", err)
+	}
+
+	return writeRoles(r)
+}
+
+// RoleDeleteFlags define the Delete command
+type RoleDeleteFlags struct {
+	id string
+}
+
+var roleDeleteFlags RoleDeleteFlags
+
+func init() {
+	roleDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete role",
+		RunE:  wrapCheckSetup(roleDeleteF),
+	}
+
+	roleDeleteCmd.Flags().StringVarP(&roleDeleteFlags.id, "id", "i", "", "The role ID (required)")
+	roleDeleteCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleDeleteCmd)
+}
+
+func roleDeleteF(cmd *cobra.Command, args []string) error {
+	id, err := decodeRoleID(roleDeleteFlags.id)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	s := newRoleService(flags)
+	r, err := s.FindRoleByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find role with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteRole(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete role with id %q: %v", id, err)
+	}
+
+	return writeRoles(r)
+}
+
+var roleMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Role membership commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
+// RoleMembersFlags define the members commands
+type RoleMembersFlags struct {
+	id       string
+	memberID string
+}
+
+var roleMembersListFlags RoleMembersFlags
+
+func init() {
+	roleMembersListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users the role is granted to",
+		RunE:  wrapCheckSetup(roleMembersListF),
+	}
+
+	roleMembersListCmd.Flags().StringVarP(&roleMembersListFlags.id, "id", "i", "", "The role ID (required)")
+	roleMembersListCmd.MarkFlagRequired("id")
+
+	roleMembersCmd.AddCommand(roleMembersListCmd)
+}
+
+func roleMembersListF(cmd *cobra.Command, args []string) error {
+	id, err := decodeRoleID(roleMembersListFlags.id)
+	if err != nil {
+		return err
+	}
+
+	users, err := newRoleService(flags).FindRoleMembers(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("failed to find role members: %v", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(users)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+	)
+	for _, u := range users {
+		w.Write(map[string]interface{}{
+			"ID":   u.ID.String(),
+			"Name": u.Name,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+var roleMembersAddFlags RoleMembersFlags
+
+func init() {
+	roleMembersAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Grant the role to a member of its organization",
+		RunE:  wrapCheckSetup(roleMembersAddF),
+	}
+
+	roleMembersAddCmd.Flags().StringVarP(&roleMembersAddFlags.id, "id", "i", "", "The role ID (required)")
+	roleMembersAddCmd.Flags().StringVarP(&roleMembersAddFlags.memberID, "member", "m", "", "The member ID (required)")
+	roleMembersAddCmd.MarkFlagRequired("id")
+	roleMembersAddCmd.MarkFlagRequired("member")
+
+	roleMembersCmd.AddCommand(roleMembersAddCmd)
+}
+
+func roleMembersAddF(cmd *cobra.Command, args []string) error {
+	id, err := decodeRoleID(roleMembersAddFlags.id)
+	if err != nil {
+		return err
+	}
+	var memberID platform.ID
+	if err := memberID.DecodeFromString(roleMembersAddFlags.memberID); err != nil {
+		return fmt.Errorf("failed to decode member id %q: %v", roleMembersAddFlags.memberID, err)
+	}
+
+	if err := newRoleService(flags).AddRoleMember(context.Background(), id, memberID); err != nil {
+		return fmt.Errorf("failed to add role member: %v", err)
+	}
+
+	fmt.Printf("Role %s granted to member %s\n", id, memberID)
+	return nil
+}
+
+var roleMembersRemoveFlags RoleMembersFlags
+
+func init() {
+	roleMembersRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Revoke the role from a member",
+		RunE:  wrapCheckSetup(roleMembersRemoveF),
+	}
+
+	roleMembersRemoveCmd.Flags().StringVarP(&roleMembersRemoveFlags.id, "id", "i", "", "The role ID (required)")
+	roleMembersRemoveCmd.Flags().StringVarP(&roleMembersRemoveFlags.memberID, "member", "m", "", "The member ID (required)")
+	roleMembersRemoveCmd.MarkFlagRequired("id")
+	roleMembersRemoveCmd.MarkFlagRequired("member")
+
+	roleMembersCmd.AddCommand(roleMembersRemoveCmd)
+	roleCmd.AddCommand(roleMembersCmd)
+}
+
+func roleMembersRemoveF(cmd *cobra.Command, args []string) error {
+	id, err := decodeRoleID(roleMembersRemoveFlags.id)
+	if err != nil {
+		return err
+	}
+	var memberID platform.ID
+	if err := memberID.DecodeFromString(roleMembersRemoveFlags.memberID); err != nil {
+		return fmt.Errorf("failed to decode member id %q: %v", roleMembersRemoveFlags.memberID, err)
+	}
+
+	if err := newRoleService(flags).RemoveRoleMember(context.Background(), id, memberID); err != nil {
+		return fmt.Errorf("failed to remove role member: %v", err)
+	}
+
+	fmt.Printf("Role %s revoked from member %s\n", id, memberID)
+	return nil
+}
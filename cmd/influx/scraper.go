@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Scraper Command
+var scraperCmd = &cobra.Command{
+	Use:   "scraper",
+	Short: "Scraper target management commands",
+	Run:   scraperF,
+}
+
+func scraperF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(scraperCmd)
+}
+
+func newScraperService(f Flags) *http.ScraperService {
+	return &http.ScraperService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeScrapers(ts ...platform.ScraperTarget) error {
+	if jsonOutput {
+		return writeJSON(ts)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+		"OrganizationID",
+		"BucketID",
+	)
+	for _, t := range ts {
+		w.Write(map[string]interface{}{
+			"ID":             t.ID.String(),
+			"Name":           t.Name,
+			"Type":           t.Type,
+			"URL":            t.URL,
+			"OrganizationID": t.OrgID.String(),
+			"BucketID":       t.BucketID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// ScraperCreateFlags define the Create Command
+type ScraperCreateFlags struct {
+	name     string
+	typ      string
+	url      string
+	org      string
+	orgID    string
+	bucketID string
+	interval time.Duration
+	timeout  time.Duration
+
+	// json targets
+	measurement string
+	root        string
+	fields      []string
+	tags        []string
+	timePath    string
+
+	// graphite targets
+	template  string
+	separator string
+
+	// statsd targets
+	percentiles []string
+}
+
+var scraperCreateFlags ScraperCreateFlags
+
+func init() {
+	scraperCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create scraper target",
+		RunE:  wrapCheckSetup(scraperCreateF),
+	}
+
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.name, "name", "n", "", "Name of scraper target that will be created")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.typ, "type", "t", platform.PrometheusScraperType, "The type of the scraper target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.url, "url", "u", "", "The URL to scrape (required)")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the scraper target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.org, "org", "o", "", "The name of the organization that owns the scraper target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.bucketID, "bucket-id", "b", "", "The ID of the bucket scraped data is written to (required)")
+	scraperCreateCmd.Flags().DurationVarP(&scraperCreateFlags.interval, "interval", "", 0, "The interval between two scrapes of the target, defaults to the interval of the server")
+	scraperCreateCmd.Flags().DurationVarP(&scraperCreateFlags.timeout, "timeout", "", 0, "The maximum duration of a scrape of the target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.measurement, "measurement", "", "", "The measurement of the metrics of json targets")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.root, "root", "", "", "The JSONPath of the objects mapped to metrics of json targets, such as $.servers[*]")
+	scraperCreateCmd.Flags().StringArrayVarP(&scraperCreateFlags.fields, "field", "", nil, "A field of json targets as name=JSONPath, can be repeated")
+	scraperCreateCmd.Flags().StringArrayVarP(&scraperCreateFlags.tags, "tag", "", nil, "A tag of json targets as name=JSONPath, can be repeated")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.timePath, "time-path", "", "", "The JSONPath of the time of the metrics of json targets")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.template, "template", "", "", "The template of the metric paths of graphite targets, such as host.measurement.field*")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.separator, "separator", "", "", "The separator joining the parts of graphite paths")
+	scraperCreateCmd.Flags().StringSliceVarP(&scraperCreateFlags.percentiles, "percentile", "", nil, "The percentiles of the timings of statsd targets")
+	scraperCreateCmd.MarkFlagRequired("url")
+	scraperCreateCmd.MarkFlagRequired("bucket-id")
+
+	scraperCmd.AddCommand(scraperCreateCmd)
+}
+
+func scraperCreateF(cmd *cobra.Command, args []string) error {
+	if !platform.ValidScraperType(scraperCreateFlags.typ) {
+		return fmt.Errorf("invalid scraper type %q", scraperCreateFlags.typ)
+	}
+
+	ctx := context.Background()
+	orgID, err := resolveOrgID(ctx, cmd, scraperCreateFlags.org, scraperCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	var bucketID platform.ID
+	if err := bucketID.DecodeFromString(scraperCreateFlags.bucketID); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", scraperCreateFlags.bucketID, err)
+	}
+
+	t := &platform.ScraperTarget{
+		Name:     scraperCreateFlags.name,
+		Type:     platform.ScraperType(scraperCreateFlags.typ),
+		URL:      scraperCreateFlags.url,
+		OrgID:    orgID,
+		BucketID: bucketID,
+	}
+	if scraperCreateFlags.interval != 0 {
+		t.Interval = &platform.Duration{Duration: scraperCreateFlags.interval}
+	}
+	if scraperCreateFlags.timeout != 0 {
+		t.Timeout = &platform.Duration{Duration: scraperCreateFlags.timeout}
+	}
+	switch t.Type {
+	case platform.JSONScraperType:
+		fields, err := parseJSONPathMappings(scraperCreateFlags.fields)
+		if err != nil {
+			return err
+		}
+		tags, err := parseJSONPathMappings(scraperCreateFlags.tags)
+		if err != nil {
+			return err
+		}
+		t.JSON = &platform.JSONScraperConfig{
+			Measurement: scraperCreateFlags.measurement,
+			Root:        scraperCreateFlags.root,
+			Fields:      fields,
+			Tags:        tags,
+			TimePath:    scraperCreateFlags.timePath,
+		}
+	case platform.GraphiteScraperType:
+		t.Graphite = &platform.GraphiteScraperConfig{
+			Template:  scraperCreateFlags.template,
+			Separator: scraperCreateFlags.separator,
+		}
+	case platform.StatsDScraperType:
+		t.StatsD = &platform.StatsDScraperConfig{}
+		for _, p := range scraperCreateFlags.percentiles {
+			f, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				return fmt.Errorf("invalid percentile %q: %v", p, err)
+			}
+			t.StatsD.Percentiles = append(t.StatsD.Percentiles, f)
+		}
+	}
+	if err := newScraperService(flags).AddTarget(ctx, t, 0); err != nil {
+		return fmt.Errorf("failed to create scraper target: %v", err)
+	}
+
+	return writeScrapers(*t)
+}
+
+// parseJSONPathMappings parses mappings of the form name=path.
+func parseJSONPathMappings(mappings []string) ([]platform.JSONPathMapping, error) {
+	ms := make([]platform.JSONPathMapping, 0, len(mappings))
+	for _, m := range mappings {
+		kv := strings.SplitN(m, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid mapping %q, must be of the form name=path", m)
+		}
+		ms = append(ms, platform.JSONPathMapping{Name: kv[0], Path: kv[1]})
+	}
+	return ms, nil
+}
+
+// ScraperFindFlags define the Find Command
+type ScraperFindFlags struct {
+	id    string
+	name  string
+	org   string
+	orgID string
+}
+
+var scraperFindFlags ScraperFindFlags
+
+func init() {
+	scraperFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find scraper targets",
+		RunE:  wrapCheckSetup(scraperFindF),
+	}
+
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.id, "id", "i", "", "The scraper target ID")
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.name, "name", "n", "", "The scraper target name")
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.orgID, "org-id", "", "", "The scraper target organization ID")
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.org, "org", "o", "", "The scraper target organization name")
+
+	scraperCmd.AddCommand(scraperFindCmd)
+}
+
+func scraperFindF(cmd *cobra.Command, args []string) error {
+	filter := platform.ScraperTargetFilter{}
+	if scraperFindFlags.id != "" {
+		id, err := platform.IDFromString(scraperFindFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode scraper target id %q: %v", scraperFindFlags.id, err)
+		}
+		filter.IDs = map[platform.ID]bool{*id: false}
+	}
+	if scraperFindFlags.name != "" {
+		filter.Name = &scraperFindFlags.name
+	}
+
+	var err error
+	filter.OrgID, filter.Org, err = orgFilter(scraperFindFlags.org, scraperFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	ts, err := newScraperService(flags).ListTargets(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve scraper targets: %v", err)
+	}
+
+	return writeScrapers(ts...)
+}
+
+// ScraperUpdateFlags define the Update Command
+type ScraperUpdateFlags struct {
+	id       string
+	name     string
+	url      string
+	bucketID string
+	interval time.Duration
+	timeout  time.Duration
+}
+
+var scraperUpdateFlags ScraperUpdateFlags
+
+func init() {
+	scraperUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update scraper target",
+		RunE:  wrapCheckSetup(scraperUpdateF),
+	}
+
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.id, "id", "i", "", "The scraper target ID (required)")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.name, "name", "n", "", "New scraper target name")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.url, "url", "u", "", "New URL to scrape")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.bucketID, "bucket-id", "b", "", "New ID of the bucket scraped data is written to")
+	scraperUpdateCmd.Flags().DurationVarP(&scraperUpdateFlags.interval, "interval", "", 0, "New interval between two scrapes of the target")
+	scraperUpdateCmd.Flags().DurationVarP(&scraperUpdateFlags.timeout, "timeout", "", 0, "New maximum duration of a scrape of the target")
+	scraperUpdateCmd.MarkFlagRequired("id")
+
+	scraperCmd.AddCommand(scraperUpdateCmd)
+}
+
+func scraperUpdateF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(scraperUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode scraper target id %q: %v", scraperUpdateFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newScraperService(flags)
+	t, err := s.GetTargetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find scraper target with id %q: %v", id, err)
+	}
+
+	if scraperUpdateFlags.name != "" {
+		t.Name = scraperUpdateFlags.name
+	}
+	if scraperUpdateFlags.url != "" {
+		t.URL = scraperUpdateFlags.url
+	}
+	if scraperUpdateFlags.bucketID != "" {
+		if err := t.BucketID.DecodeFromString(scraperUpdateFlags.bucketID); err != nil {
+			return fmt.Errorf("failed to decode bucket id %q: %v", scraperUpdateFlags.bucketID, err)
+		}
+	}
+
+	if scraperUpdateFlags.interval != 0 {
+		t.Interval = &platform.Duration{Duration: scraperUpdateFlags.interval}
+	}
+	if scraperUpdateFlags.timeout != 0 {
+		t.Timeout = &platform.Duration{Duration: scraperUpdateFlags.timeout}
+	}
+
+	t, err = s.UpdateTarget(ctx, t, 0)
+	if err != nil {
+		return fmt.Errorf("failed to update scraper target: %v", err)
+	}
+
+	return writeScrapers(*t)
+}
+
+// ScraperStatusFlags define the Status command
+type ScraperStatusFlags struct {
+	id string
+}
+
+var scraperStatusFlags ScraperStatusFlags
+
+func init() {
+	scraperStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the outcome of the last scrape of a scraper target",
+		RunE:  wrapCheckSetup(scraperStatusF),
+	}
+
+	scraperStatusCmd.Flags().StringVarP(&scraperStatusFlags.id, "id", "i", "", "The scraper target ID (required)")
+	scraperStatusCmd.MarkFlagRequired("id")
+
+	scraperCmd.AddCommand(scraperStatusCmd)
+}
+
+func scraperStatusF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(scraperStatusFlags.id); err != nil {
+		return fmt.Errorf("failed to decode scraper target id %q: %v", scraperStatusFlags.id, err)
+	}
+
+	st, err := newScraperService(flags).GetTargetStatus(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve scraper target status: %v", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(st)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Status",
+		"LastScrape",
+		"Duration",
+		"HTTPStatus",
+		"Samples",
+		"Error",
+	)
+	w.Write(map[string]interface{}{
+		"ID":         st.TargetID.String(),
+		"Status":     st.Status,
+		"LastScrape": st.LastScrape,
+		"Duration":   st.Duration.String(),
+		"HTTPStatus": st.HTTPStatus,
+		"Samples":    st.Samples,
+		"Error":      st.Error,
+	})
+	w.Flush()
+
+	return nil
+}
+
+// ScraperDeleteFlags define the Delete command
+type ScraperDeleteFlags struct {
+	id string
+}
+
+var scraperDeleteFlags ScraperDeleteFlags
+
+func init() {
+	scraperDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete scraper target",
+		RunE:  wrapCheckSetup(scraperDeleteF),
+	}
+
+	scraperDeleteCmd.Flags().StringVarP(&scraperDeleteFlags.id, "id", "i", "", "The scraper target ID (required)")
+	scraperDeleteCmd.MarkFlagRequired("id")
+
+	scraperCmd.AddCommand(scraperDeleteCmd)
+}
+
+func scraperDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(scraperDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode scraper target id %q: %v", scraperDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newScraperService(flags)
+	t, err := s.GetTargetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find scraper target with id %q: %v", id, err)
+	}
+
+	if err := s.RemoveTarget(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete scraper target with id %q: %v", id, err)
+	}
+
+	return writeScrapers(*t)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Secret Command
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Secret management commands",
+	Run:   secretF,
+}
+
+func secretF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// secretFlags are the flags shared by all secret commands.
+var secretFlags struct {
+	org   string
+	orgID string
+}
+
+func init() {
+	addJSONFlag(secretCmd)
+	secretCmd.PersistentFlags().StringVar(&secretFlags.orgID, "org-id", "", "The ID of the organization that owns the secrets")
+	secretCmd.PersistentFlags().StringVarP(&secretFlags.org, "org", "o", "", "The name of the organization that owns the secrets")
+}
+
+func newSecretService(f Flags) platform.SecretService {
+	return &http.SecretService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+// secretKeys returns the organization of the secret commands and the keys of its secrets.
+func secretKeys(ctx context.Context, cmd *cobra.Command, s platform.SecretService) (platform.ID, []string, error) {
+	orgID, err := resolveOrgID(ctx, cmd, secretFlags.org, secretFlags.orgID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ks, err := s.GetSecretKeys(ctx, orgID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to retrieve secret keys: %v", err)
+	}
+	return orgID, ks, nil
+}
+
+func hasSecretKey(ks []string, k string) bool {
+	for _, key := range ks {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+func writeSecretKeys(orgID platform.ID, ks ...string) error {
+	sort.Strings(ks)
+	if jsonOutput {
+		return writeJSON(struct {
+			OrgID   platform.ID `json:"orgID"`
+			Secrets []string    `json:"secrets"`
+		}{orgID, ks})
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Key",
+		"OrganizationID",
+	)
+	for _, k := range ks {
+		w.Write(map[string]interface{}{
+			"Key":            k,
+			"OrganizationID": orgID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// SecretCreateFlags define the Create and Update Commands
+type SecretCreateFlags struct {
+	key   string
+	value string
+}
+
+var secretCreateFlags, secretUpdateFlags SecretCreateFlags
+
+func init() {
+	secretCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create secret",
+		RunE:  wrapCheckSetup(secretCreateF),
+	}
+
+	secretCreateCmd.Flags().StringVarP(&secretCreateFlags.key, "key", "k", "", "The key of the secret (required)")
+	secretCreateCmd.Flags().StringVarP(&secretCreateFlags.value, "value", "v", "", "The value of the secret (required)")
+	secretCreateCmd.MarkFlagRequired("key")
+	secretCreateCmd.MarkFlagRequired("value")
+
+	secretCmd.AddCommand(secretCreateCmd)
+
+	secretUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update secret",
+		RunE:  wrapCheckSetup(secretUpdateF),
+	}
+
+	secretUpdateCmd.Flags().StringVarP(&secretUpdateFlags.key, "key", "k", "", "The key of the secret (required)")
+	secretUpdateCmd.Flags().StringVarP(&secretUpdateFlags.value, "value", "v", "", "The new value of the secret (required)")
+	secretUpdateCmd.MarkFlagRequired("key")
+	secretUpdateCmd.MarkFlagRequired("value")
+
+	secretCmd.AddCommand(secretUpdateCmd)
+}
+
+func secretCreateF(cmd *cobra.Command, args []string) error {
+	return putSecret(cmd, secretCreateFlags, false)
+}
+
+func secretUpdateF(cmd *cobra.Command, args []string) error {
+	return putSecret(cmd, secretUpdateFlags, true)
+}
+
+// putSecret stores a secret. Creating a secret fails if it already exists and
+// updating a secret fails if it does not.
+func putSecret(cmd *cobra.Command, f SecretCreateFlags, exists bool) error {
+	ctx := context.Background()
+	s := newSecretService(flags)
+	orgID, ks, err := secretKeys(ctx, cmd, s)
+	if err != nil {
+		return err
+	}
+
+	if found := hasSecretKey(ks, f.key); found && !exists {
+		return fmt.Errorf("secret %q already exists", f.key)
+	} else if !found && exists {
+		return fmt.Errorf("secret %q not found", f.key)
+	}
+
+	if err := s.PutSecret(ctx, orgID, f.key, f.value); err != nil {
+		return fmt.Errorf("failed to store secret %q: %v", f.key, err)
+	}
+
+	return writeSecretKeys(orgID, f.key)
+}
+
+func init() {
+	secretFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the keys of secrets",
+		RunE:  wrapCheckSetup(secretFindF),
+	}
+
+	secretCmd.AddCommand(secretFindCmd)
+}
+
+func secretFindF(cmd *cobra.Command, args []string) error {
+	orgID, ks, err := secretKeys(context.Background(), cmd, newSecretService(flags))
+	if err != nil {
+		return err
+	}
+
+	return writeSecretKeys(orgID, ks...)
+}
+
+// SecretDeleteFlags define the Delete command
+type SecretDeleteFlags struct {
+	key string
+}
+
+var secretDeleteFlags SecretDeleteFlags
+
+func init() {
+	secretDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete secret",
+		RunE:  wrapCheckSetup(secretDeleteF),
+	}
+
+	secretDeleteCmd.Flags().StringVarP(&secretDeleteFlags.key, "key", "k", "", "The key of the secret (required)")
+	secretDeleteCmd.MarkFlagRequired("key")
+
+	secretCmd.AddCommand(secretDeleteCmd)
+}
+
+func secretDeleteF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newSecretService(flags)
+	orgID, ks, err := secretKeys(ctx, cmd, s)
+	if err != nil {
+		return err
+	}
+
+	if !hasSecretKey(ks, secretDeleteFlags.key) {
+		return fmt.Errorf("secret %q not found", secretDeleteFlags.key)
+	}
+
+	if err := s.DeleteSecret(ctx, orgID, secretDeleteFlags.key); err != nil {
+		return fmt.Errorf("failed to delete secret %q: %v", secretDeleteFlags.key, err)
+	}
+
+	return writeSecretKeys(orgID, secretDeleteFlags.key)
+}
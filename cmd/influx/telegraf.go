@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Telegraf Command
+var telegrafCmd = &cobra.Command{
+	Use:   "telegraf",
+	Short: "Telegraf config management commands",
+	Run:   telegrafF,
+}
+
+func telegrafF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	addJSONFlag(telegrafCmd)
+}
+
+func newTelegrafService(f Flags) *http.TelegrafService {
+	return &http.TelegrafService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func writeTelegrafs(tcs ...*platform.TelegrafConfig) error {
+	if jsonOutput {
+		return writeJSON(tcs)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Description",
+		"Plugins",
+		"OrganizationID",
+	)
+	for _, tc := range tcs {
+		w.Write(map[string]interface{}{
+			"ID":             tc.ID.String(),
+			"Name":           tc.Name,
+			"Description":    tc.Description,
+			"Plugins":        len(tc.Plugins),
+			"OrganizationID": tc.OrgID.String(),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// readTelegrafConfig reads a telegraf config from a file. The file is parsed
+// as a telegraf TOML config if it has a .toml extension, and as the JSON
+// representation of the API otherwise.
+func readTelegrafConfig(path string) (*platform.TelegrafConfig, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := new(platform.TelegrafConfig)
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(b, tc)
+	} else {
+		err = json.Unmarshal(b, tc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse telegraf config %s: %v", path, err)
+	}
+	return tc, nil
+}
+
+// TelegrafCreateFlags define the Create Command
+type TelegrafCreateFlags struct {
+	file        string
+	name        string
+	description string
+	org         string
+	orgID       string
+}
+
+var telegrafCreateFlags TelegrafCreateFlags
+
+func init() {
+	telegrafCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create telegraf config",
+		RunE:  wrapCheckSetup(telegrafCreateF),
+	}
+
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.file, "file", "f", "", "The telegraf config; TOML if it has a .toml extension, JSON otherwise (required)")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.name, "name", "n", "", "Name of telegraf config that will be created")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.description, "description", "d", "", "Description of telegraf config that will be created")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the telegraf config")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.org, "org", "o", "", "The name of the organization that owns the telegraf config")
+	telegrafCreateCmd.MarkFlagRequired("file")
+
+	telegrafCmd.AddCommand(telegrafCreateCmd)
+}
+
+func telegrafCreateF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	orgID, err := resolveOrgID(ctx, cmd, telegrafCreateFlags.org, telegrafCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	tc, err := readTelegrafConfig(telegrafCreateFlags.file)
+	if err != nil {
+		return err
+	}
+	tc.OrgID = orgID
+	if telegrafCreateFlags.name != "" {
+		tc.Name = telegrafCreateFlags.name
+	}
+	if telegrafCreateFlags.description != "" {
+		tc.Description = telegrafCreateFlags.description
+	}
+
+	if err := newTelegrafService(flags).CreateTelegrafConfig(ctx, tc, 0); err != nil {
+		return fmt.Errorf("failed to create telegraf config: %v", err)
+	}
+
+	return writeTelegrafs(tc)
+}
+
+// TelegrafFindFlags define the Find Command
+type TelegrafFindFlags struct {
+	id    string
+	org   string
+	orgID string
+}
+
+var telegrafFindFlags TelegrafFindFlags
+
+func init() {
+	telegrafFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find telegraf configs",
+		RunE:  wrapCheckSetup(telegrafFindF),
+	}
+
+	telegrafFindCmd.Flags().StringVarP(&telegrafFindFlags.id, "id", "i", "", "The telegraf config ID")
+	telegrafFindCmd.Flags().StringVarP(&telegrafFindFlags.orgID, "org-id", "", "", "The telegraf config organization ID")
+	telegrafFindCmd.Flags().StringVarP(&telegrafFindFlags.org, "org", "o", "", "The telegraf config organization name")
+
+	telegrafCmd.AddCommand(telegrafFindCmd)
+}
+
+func telegrafFindF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	s := newTelegrafService(flags)
+
+	if telegrafFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(telegrafFindFlags.id); err != nil {
+			return fmt.Errorf("failed to decode telegraf config id %q: %v", telegrafFindFlags.id, err)
+		}
+
+		tc, err := s.FindTelegrafConfigByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find telegraf config with id %q: %v", id, err)
+		}
+		return writeTelegrafs(tc)
+	}
+
+	filter := platform.TelegrafConfigFilter{}
+	var err error
+	filter.OrgID, filter.Organization, err = orgFilter(telegrafFindFlags.org, telegrafFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	tcs, _, err := s.FindTelegrafConfigs(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve telegraf configs: %v", err)
+	}
+
+	return writeTelegrafs(tcs...)
+}
+
+// TelegrafUpdateFlags define the Update Command
+type TelegrafUpdateFlags struct {
+	id          string
+	file        string
+	name        string
+	description string
+}
+
+var telegrafUpdateFlags TelegrafUpdateFlags
+
+func init() {
+	telegrafUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update telegraf config",
+		RunE:  wrapCheckSetup(telegrafUpdateF),
+	}
+
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.id, "id", "i", "", "The telegraf config ID (required)")
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.file, "file", "f", "", "New telegraf config; TOML if it has a .toml extension, JSON otherwise")
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.name, "name", "n", "", "New telegraf config name")
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.description, "description", "d", "", "New telegraf config description")
+	telegrafUpdateCmd.MarkFlagRequired("id")
+
+	telegrafCmd.AddCommand(telegrafUpdateCmd)
+}
+
+func telegrafUpdateF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(telegrafUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode telegraf config id %q: %v", telegrafUpdateFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newTelegrafService(flags)
+	tc, err := s.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find telegraf config with id %q: %v", id, err)
+	}
+
+	if telegrafUpdateFlags.file != "" {
+		upd, err := readTelegrafConfig(telegrafUpdateFlags.file)
+		if err != nil {
+			return err
+		}
+		tc.Agent = upd.Agent
+		tc.Plugins = upd.Plugins
+	}
+	if telegrafUpdateFlags.name != "" {
+		tc.Name = telegrafUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		tc.Description = telegrafUpdateFlags.description
+	}
+
+	tc, err = s.UpdateTelegrafConfig(ctx, id, tc, 0)
+	if err != nil {
+		return fmt.Errorf("failed to update telegraf config: %v", err)
+	}
+
+	return writeTelegrafs(tc)
+}
+
+// TelegrafDeleteFlags define the Delete command
+type TelegrafDeleteFlags struct {
+	id string
+}
+
+var telegrafDeleteFlags TelegrafDeleteFlags
+
+func init() {
+	telegrafDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete telegraf config",
+		RunE:  wrapCheckSetup(telegrafDeleteF),
+	}
+
+	telegrafDeleteCmd.Flags().StringVarP(&telegrafDeleteFlags.id, "id", "i", "", "The telegraf config ID (required)")
+	telegrafDeleteCmd.MarkFlagRequired("id")
+
+	telegrafCmd.AddCommand(telegrafDeleteCmd)
+}
+
+func telegrafDeleteF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(telegrafDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode telegraf config id %q: %v", telegrafDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	s := newTelegrafService(flags)
+	tc, err := s.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find telegraf config with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteTelegrafConfig(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete telegraf config with id %q: %v", id, err)
+	}
+
+	return writeTelegrafs(tc)
+}
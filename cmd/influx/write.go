@@ -1,11 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
@@ -20,7 +22,10 @@ var writeCmd = &cobra.Command{
 	Use:   "write line protocol or @/path/to/points.txt",
 	Short: "Write points to InfluxDB",
 	Long: `Write a single line of line protocol to InfluxDB,
-or add an entire file specified with an @ prefix.`,
+or add an entire file specified with an @ prefix.
+
+Annotated CSV and JSON are written with --format; files with a
+.csv or .json extension use that format by default.`,
 	Args: cobra.ExactArgs(1),
 	RunE: wrapCheckSetup(fluxWriteF),
 }
@@ -31,6 +36,7 @@ var writeFlags struct {
 	BucketID  string
 	Bucket    string
 	Precision string
+	Format    string
 }
 
 func init() {
@@ -63,6 +69,8 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Format, "format", "", "Format of the data; lp, csv or json. Defaults to the file extension, or lp")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -83,6 +91,18 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	format := write.Format(writeFlags.Format)
+	if format == "" {
+		format = write.FormatLineProtocol
+		if len(args[0]) > 0 && args[0][0] == '@' {
+			format = write.FormatFromPath(args[0][1:])
+		}
+	}
+	if err := format.Valid(); err != nil {
+		cmd.Usage()
+		return err
+	}
+
 	bs := &http.BucketService{
 		Addr:  flags.host,
 		Token: flags.token,
@@ -142,11 +162,23 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		r = strings.NewReader(args[0])
 	}
 
+	precision := writeFlags.Precision
+	if format != write.FormatLineProtocol {
+		// CSV and JSON are converted to line protocol up front, so that they
+		// are batched like line protocol and errors are reported before
+		// anything is written.
+		points, err := write.ParsePoints(format, r, time.Now(), precision)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %v", format, err)
+		}
+		r, precision = bytes.NewReader(write.LineProtocol(points)), "ns"
+	}
+
 	s := write.Batcher{
 		Service: &http.WriteService{
 			Addr:      flags.host,
 			Token:     flags.token,
-			Precision: writeFlags.Precision,
+			Precision: precision,
 		},
 	}
 
@@ -22,6 +22,7 @@ func NewCommand() *cobra.Command {
 		NewVerifySeriesFileCommand(),
 		NewDumpWALCommand(),
 		NewDumpTSICommand(),
+		NewVerifyKVCommand(),
 	}
 
 	base.AddCommand(subCommands...)
@@ -0,0 +1,101 @@
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/spf13/cobra"
+)
+
+// NewVerifyKVCommand creates the verify-kv command.
+func NewVerifyKVCommand() *cobra.Command {
+	verifyKVCommand := &cobra.Command{
+		Use:   `verify-kv`,
+		Short: "Check the indexes of the metadata store",
+		Long: `
+This command verifies the secondary indexes of the metadata store, such as the
+index of the users by name, against the resources they index. With --repair,
+the dangling entries are removed from the indexes and the missing entries are
+inserted. influxd must not be running.
+
+For each index, the following is output:
+	* The name of the index;
+	* "consistent" OR
+	  The number of dangling and missing entries, followed by the entries;
+In the summary section, the following is printed:
+	* The number of inconsistent indexes;
+	* Whether the indexes were repaired.`,
+		Args: cobra.NoArgs,
+		RunE: inspectVerifyKV,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	verifyKVCommand.Flags().StringVar(&verifyKVFlags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to boltdb database")
+	verifyKVCommand.Flags().BoolVar(&verifyKVFlags.repair, "repair", false, "remove the dangling entries and insert the missing entries of the indexes")
+
+	return verifyKVCommand
+}
+
+var verifyKVFlags = struct {
+	boltPath string
+	repair   bool
+}{}
+
+// inspectVerifyKV runs the verify-kv tool.
+func inspectVerifyKV(cmd *cobra.Command, args []string) error {
+	// Opening the store would create it.
+	if _, err := os.Stat(verifyKVFlags.boltPath); err != nil {
+		return fmt.Errorf("unable to find metadata store: %v", err)
+	}
+
+	ctx := context.Background()
+	store := bolt.NewKVStore(verifyKVFlags.boltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	reports, err := kv.NewService(store).VerifyIndexes(ctx, verifyKVFlags.repair)
+	if err != nil {
+		return err
+	}
+	writeIndexReports(os.Stdout, reports, verifyKVFlags.repair)
+	return nil
+}
+
+func writeIndexReports(w io.Writer, reports []*kv.IndexReport, repaired bool) {
+	var inconsistent int
+	for _, r := range reports {
+		if r.Consistent() {
+			fmt.Fprintf(w, "%s: consistent\n", r.Index)
+			continue
+		}
+
+		inconsistent++
+		fmt.Fprintf(w, "%s: %d dangling, %d missing\n", r.Index, len(r.Dangling), len(r.Missing))
+		for _, e := range r.Dangling {
+			fmt.Fprintf(w, "\tdangling %q -> %x\n", e.Key, e.SourceKey)
+		}
+		for _, e := range r.Missing {
+			fmt.Fprintf(w, "\tmissing %q -> %x\n", e.Key, e.SourceKey)
+		}
+	}
+
+	fmt.Fprintf(w, "\nSummary:\n\t%d of %d indexes are inconsistent\n", inconsistent, len(reports))
+	switch {
+	case inconsistent == 0:
+	case repaired:
+		fmt.Fprintln(w, "\tthe inconsistent indexes were repaired")
+	default:
+		fmt.Fprintln(w, "\trun with --repair to repair the inconsistent indexes")
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/chronograf/server"
@@ -28,11 +29,17 @@ import (
 	"github.com/influxdata/influxdb/kit/signals"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/limits"
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/oidc"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/query/explain"
+	"github.com/influxdata/influxdb/query/querylog"
+	influxdbv1 "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/v1"
+	"github.com/influxdata/influxdb/secret"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -164,11 +171,67 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerOrg,
+			Flag:    "storage-max-series-per-org",
+			Default: 0,
+			Desc:    "maximum number of series of an organization, new series beyond it are rejected on write; 0 means no limit",
+		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerBucket,
+			Flag:    "storage-max-series-per-bucket",
+			Default: 0,
+			Desc:    "maximum number of series of a bucket, new series beyond it are rejected on write; 0 means no limit",
+		},
+		{
+			DestP:   &l.limitsConfig.WriteBytesPerSecond,
+			Flag:    "org-write-bytes-per-second",
+			Default: 0,
+			Desc:    "maximum rate of the bytes written by an organization, writes beyond it are rejected with 429; 0 means no limit",
+		},
+		{
+			DestP:   &l.limitsConfig.WritePointsPerSecond,
+			Flag:    "org-write-points-per-second",
+			Default: 0,
+			Desc:    "maximum rate of the points written by an organization, writes beyond it are rejected with 429; 0 means no limit",
+		},
+		{
+			DestP:   &l.limitsConfig.QueryConcurrency,
+			Flag:    "org-query-concurrency",
+			Default: 0,
+			Desc:    "maximum number of concurrent queries of an organization, queries beyond it are rejected with 429; 0 means no limit",
+		},
+		{
+			DestP:   &l.limitsConfig.QueryMemoryBytes,
+			Flag:    "org-query-memory-bytes",
+			Default: 0,
+			Desc:    "maximum number of bytes a query of an organization is allowed to use; 0 means no limit",
+		},
+		{
+			DestP: &l.orgLimits,
+			Flag:  "org-limit",
+			Desc:  "overrides a limit of an organization, of the form orgID:limit=value where limit is write-bytes-per-second, write-points-per-second, query-concurrency or query-memory-bytes; may be repeated",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
 			Default: "bolt",
-			Desc:    "data store for secrets (bolt or vault)",
+			Desc:    "data store for secrets (bolt, vault, env or file); env and file stores are read-only",
+		},
+		{
+			DestP: &l.secretKeyFile,
+			Flag:  "secret-key-file",
+			Desc:  "path of the keys encrypting the secrets of the bolt secret store, one id:base64key per line; the first key encrypts and the others are rotated out on start",
+		},
+		{
+			DestP: &l.secretKeys,
+			Flag:  "secret-keys",
+			Desc:  "keys encrypting the secrets of the bolt secret store as comma separated id:base64key, an alternative to secret-key-file",
+		},
+		{
+			DestP: &l.secretPath,
+			Flag:  "secret-path",
+			Desc:  "directory of the secrets of the file secret store, stored at <secret-path>/<org id>/<key>",
 		},
 		{
 			DestP:   &l.reportingDisabled,
@@ -188,6 +251,72 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP: &l.oidcConfig.Issuer,
+			Flag:  "oidc-issuer",
+			Desc:  "URL of the OpenID Connect provider used for single sign-on; single sign-on is disabled if empty",
+		},
+		{
+			DestP: &l.oidcConfig.ClientID,
+			Flag:  "oidc-client-id",
+			Desc:  "client id registered with the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.ClientSecret,
+			Flag:  "oidc-client-secret",
+			Desc:  "client secret registered with the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.RedirectURL,
+			Flag:  "oidc-redirect-url",
+			Desc:  "public URL of /api/v2/signin/sso/callback registered with the OpenID Connect provider",
+		},
+		{
+			DestP:   &l.oidcConfig.Scopes,
+			Flag:    "oidc-scopes",
+			Default: oidc.DefaultScopes,
+			Desc:    "scopes requested from the OpenID Connect provider",
+		},
+		{
+			DestP:   &l.oidcConfig.UsernameClaim,
+			Flag:    "oidc-username-claim",
+			Default: oidc.DefaultUsernameClaim,
+			Desc:    "claim of the id token used as user name",
+		},
+		{
+			DestP:   &l.oidcConfig.GroupsClaim,
+			Flag:    "oidc-groups-claim",
+			Default: oidc.DefaultGroupsClaim,
+			Desc:    "claim of the id token that lists the groups of the user",
+		},
+		{
+			DestP: &l.oidcGroupMappings,
+			Flag:  "oidc-group-mapping",
+			Desc:  "grants the members of a group access to an organization, of the form group=orgID[:owner|member]; may be repeated",
+		},
+		{
+			DestP:   &l.oidcConfig.MapExistingUsers,
+			Flag:    "oidc-map-existing-users",
+			Default: false,
+			Desc:    "allows single sign-on as existing users of the same name that were not created by the OpenID Connect provider",
+		},
+		{
+			DestP: &l.auditLogPath,
+			Flag:  "audit-log-path",
+			Desc:  "path of a file the audit log is appended to as lines of JSON, in addition to the _audit bucket",
+		},
+		{
+			DestP:   &l.queryLogDisabled,
+			Flag:    "query-log-disabled",
+			Default: false,
+			Desc:    "disables recording the completed queries in the _queries bucket of their organization",
+		},
+		{
+			DestP:   &l.queryLogSlowThreshold,
+			Flag:    "query-log-slow-threshold",
+			Default: 10 * time.Second,
+			Desc:    "duration from which the full text and profile of a query are recorded in the _queries bucket; 0 records no slow query",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
@@ -207,6 +336,16 @@ type Launcher struct {
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
 
+	oidcConfig        oidc.Config
+	oidcGroupMappings []string
+
+	auditLogPath string
+	auditLogger  *audit.MultiLogger
+	auditFile    *audit.FileLogger
+
+	queryLogDisabled      bool
+	queryLogSlowThreshold time.Duration
+
 	logLevel          string
 	tracingType       string
 	reportingDisabled bool
@@ -215,12 +354,19 @@ type Launcher struct {
 	boltPath        string
 	enginePath      string
 	secretStore     string
+	secretKeyFile   string
+	secretKeys      string
+	secretPath      string
 
 	boltClient    *bolt.Client
 	kvService     *kv.Service
 	engine        *storage.Engine
 	StorageConfig storage.Config
 
+	limitsConfig limits.Config
+	orgLimits    []string
+	limiter      *limits.Limiter
+
 	queryController *control.Controller
 
 	httpPort   int
@@ -307,6 +453,12 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.logger.Error("failed to close engine", zap.Error(err))
 	}
 
+	if m.auditFile != nil {
+		if err := m.auditFile.Close(); err != nil {
+			m.logger.Info("Failed closing audit log", zap.Error(err))
+		}
+	}
+
 	m.wg.Wait()
 
 	if m.jaegerTracerCloser != nil {
@@ -318,6 +470,47 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.logger.Sync()
 }
 
+// auditStore returns s recording the changes made by API requests in the
+// audit log.
+func (m *Launcher) auditStore(s kv.Store) kv.Store {
+	store := audit.NewStore(s, m.auditLogger)
+	store.Logger = m.logger.With(zap.String("service", "audit"))
+	return store
+}
+
+// encryptSecrets configures the encryption of the secrets of the bolt secret
+// store when secret keys are set, and encrypts the secrets stored with a
+// previous key or before the keys were set.
+func (m *Launcher) encryptSecrets(ctx context.Context) error {
+	var (
+		keyring *secret.Keyring
+		err     error
+	)
+	switch {
+	case m.secretKeyFile != "" && m.secretKeys != "":
+		return fmt.Errorf("only one of secret-key-file and secret-keys can be set")
+	case m.secretKeyFile != "":
+		keyring, err = secret.ReadKeyringFile(m.secretKeyFile)
+	case m.secretKeys != "":
+		keyring, err = secret.ParseKeyring(m.secretKeys)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	m.kvService.SecretKeyring = keyring
+	n, err := m.kvService.RotateSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		m.logger.Info("Encrypted secrets with primary secret key", zap.String("key_id", keyring.PrimaryKeyID()), zap.Int("secrets", n))
+	}
+	return nil
+}
+
 // Cancel executes the context cancel on the program. Used for testing.
 func (m *Launcher) Cancel() { m.cancel() }
 
@@ -403,18 +596,36 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SessionLength: time.Duration(m.sessionLength) * time.Minute,
 	}
 
-	var flusher http.Flusher
+	// The changes made to the store by API requests are recorded in the audit
+	// log, the audit bucket is added to its loggers once the engine is open.
+	m.auditLogger = &audit.MultiLogger{}
+	if m.auditLogPath != "" {
+		f, err := audit.OpenFileLogger(m.auditLogPath)
+		if err != nil {
+			m.logger.Error("failed opening audit log", zap.Error(err))
+			return err
+		}
+		m.auditFile = f
+		m.auditLogger.Add(f)
+	}
+
+	var (
+		flusher     http.Flusher
+		kvBackupSvc platform.KVBackupService
+	)
 	switch m.storeType {
 	case BoltStore:
 		store := bolt.NewKVStore(m.boltPath)
 		store.WithDB(m.boltClient.DB())
-		m.kvService = kv.NewService(store, serviceConfig)
+		m.kvService = kv.NewService(m.auditStore(store), serviceConfig)
+		kvBackupSvc = store
 		if m.testing {
 			flusher = store
 		}
 	case MemoryStore:
 		store := inmem.NewKVStore()
-		m.kvService = kv.NewService(store, serviceConfig)
+		m.kvService = kv.NewService(m.auditStore(store), serviceConfig)
+		kvBackupSvc = store
 		if m.testing {
 			flusher = store
 		}
@@ -430,6 +641,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err := m.encryptSecrets(ctx); err != nil {
+		m.logger.Error("failed to encrypt secrets", zap.Error(err))
+		return err
+	}
+
 	m.reg = prom.NewRegistry()
 	m.reg.MustRegister(
 		prometheus.NewGoCollector(),
@@ -460,6 +676,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		secretSvc               platform.SecretService                   = m.kvService
 		lookupSvc               platform.LookupService                   = m.kvService
 		notificationEndpointSvc platform.NotificationEndpointService     = m.kvService
+		dbrpMappingSvc          platform.DBRPMappingService              = m.kvService
+		bucketSchemaSvc         platform.BucketSchemaService             = m.kvService
 	)
 
 	switch m.secretStore {
@@ -474,8 +692,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 		secretSvc = svc
+	case "env":
+		// Secrets are set as INFLUXD_SECRETS_<org id>_<key> environment variables.
+		secretSvc = secret.NewEnvService(secret.DefaultEnvPrefix, os.Environ())
+	case "file":
+		svc, err := secret.NewFileService(m.secretPath)
+		if err != nil {
+			m.logger.Error("failed initializing file secret service", zap.Error(err))
+			return err
+		}
+		secretSvc = svc
 	default:
-		err := fmt.Errorf("unknown secret service %q, expected \"bolt\" or \"vault\"", m.secretStore)
+		err := fmt.Errorf("unknown secret service %q, expected \"bolt\", \"vault\", \"env\" or \"file\"", m.secretStore)
 		m.logger.Error("failed setting secret service", zap.Error(err))
 		return err
 	}
@@ -486,9 +714,37 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	var ssoSvc platform.SSOService
+	if m.oidcConfig.Issuer != "" {
+		for _, s := range m.oidcGroupMappings {
+			gm, err := oidc.ParseGroupMapping(s)
+			if err != nil {
+				m.logger.Error("failed parsing oidc group mapping", zap.Error(err))
+				return err
+			}
+			m.oidcConfig.GroupMappings = append(m.oidcConfig.GroupMappings, gm)
+		}
+
+		oidcSvc, err := oidc.NewService(m.oidcConfig, nil, userSvc, userResourceSvc, sessionSvc)
+		if err != nil {
+			m.logger.Error("failed creating oidc service", zap.Error(err))
+			return err
+		}
+		oidcSvc.Logger = m.logger.With(zap.String("service", "oidc"))
+		ssoSvc = oidcSvc
+	}
+
+	for _, s := range m.orgLimits {
+		if err := m.limitsConfig.AddOrgLimit(s); err != nil {
+			m.logger.Error("failed parsing organization limit", zap.Error(err))
+			return err
+		}
+	}
+	m.limiter = limits.NewLimiter(m.limitsConfig)
+
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithSchemaEnforcer(m.kvService), storage.WithDownsampleTaskFinder(m.kvService), storage.WithRetentionEnforcer(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
 		if err := m.engine.Open(ctx); err != nil {
@@ -499,6 +755,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
 		pointsWriter = m.engine
+		m.auditLogger.Add(audit.NewBucketLogger(pointsWriter, m.kvService))
 
 		// TODO(cwolff): Figure out a good default per-query memory limit:
 		//   https://github.com/influxdata/influxdb/issues/13642
@@ -514,6 +771,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			MemoryBytesQuotaPerQuery: int64(memoryBytesQuotaPerQuery),
 			QueueSize:                QueueSize,
 			Logger:                   m.logger.With(zap.String("service", "storage-reads")),
+			OrgLimiter:               m.limiter,
 		}
 
 		authBucketSvc := authorizer.NewBucketService(bucketSvc)
@@ -526,6 +784,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 
+		if err := influxdbv1.InjectDatabasesDependencies(cc.ExecutorDependencies, influxdbv1.DatabasesDependencies{
+			DBRP:         authorizer.NewDBRPMappingService(dbrpMappingSvc),
+			BucketLookup: authBucketSvc,
+		}); err != nil {
+			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
+			return err
+		}
+
 		c, err := control.New(cc)
 		if err != nil {
 			m.logger.Error("Failed to create query controller", zap.Error(err))
@@ -535,7 +801,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 	}
 
-	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
+	var storageQueryService query.ProxyQueryService = readservice.NewProxyQueryService(m.queryController)
+	if !m.queryLogDisabled {
+		storageQueryService = &query.LoggingProxyQueryService{
+			ProxyQueryService: storageQueryService,
+			QueryLogger:       querylog.NewBucketLogger(pointsWriter, m.queryLogSlowThreshold),
+			Logger:            m.logger.With(zap.String("service", "query-log")),
+		}
+	}
 	var taskSvc platform.TaskService
 	{
 
@@ -544,7 +817,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		// define the executor and build analytical storage middleware
 		combinedTaskService := taskbackend.NewAnalyticalStorage(m.logger.With(zap.String("service", "task-analytical-store")), m.kvService, m.kvService, pointsWriter, query.QueryServiceBridge{AsyncQueryService: m.queryController})
-		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, combinedTaskService)
+		roleResolver := &authorizer.RoleResolver{
+			RoleService:                m.kvService,
+			UserResourceMappingService: m.kvService,
+			LabelService:               m.kvService,
+		}
+		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, combinedTaskService, roleResolver)
 
 		// create the scheduler
 		m.scheduler = taskbackend.NewScheduler(combinedTaskService, executor, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
@@ -576,6 +854,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		notificationRuleSvc = middleware.NewNotificationRuleStore(m.kvService, m.kvService, coordinator)
 	}
 
+	{
+		coordinator := coordinator.New(m.logger, m.scheduler)
+		bucketSvc = middleware.NewBucketService(bucketSvc, m.kvService, coordinator)
+	}
+
 	// NATS streaming server
 	m.natsServer = nats.NewServer()
 	if err := m.natsServer.Open(); err != nil {
@@ -602,6 +885,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			Writer: pointsWriter,
 		},
 	})
+	subscriber.Subscribe(gather.StatusSubject, "metrics", &gather.StatusHandler{
+		Logger: m.logger,
+		Status: m.kvService,
+		Recorder: gather.PointWriter{
+			Writer: pointsWriter,
+		},
+	})
 	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, publisher, subscriber, 10*time.Second, 30*time.Second)
 	if err != nil {
 		m.logger.Error("failed to create scraper subscriber", zap.Error(err))
@@ -630,10 +920,22 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		WriteLimiter:         m.limiter,
+		DeleteService:        m.engine,
+		BackupService:        m.engine,
+		KVBackupService:      kvBackupSvc,
+		RestoreService:       m.engine,
 		AuthorizationService: authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		DBRPMappingService:              dbrpMappingSvc,
+		BucketSchemaService:             bucketSchemaSvc,
+		MeasurementSchemaReader:         m.engine,
+		UsageService:                    platform.MultiUsageService{m.engine, m.limiter},
 		SessionService:                  sessionSvc,
+		SSOService:                      ssoSvc,
+		AuthorizationUsageRecorder:      m.kvService,
+		AuditLogger:                     m.auditLogger,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
@@ -649,14 +951,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
+		RunningQueryService:             m.queryController,
+		ExplainService:                  explain.NewService(m.queryController),
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     notificationEndpointSvc,
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
+		ScraperTargetStatusService:      m.kvService,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
+		RoleService:                     m.kvService,
 		LookupService:                   lookupSvc,
 		DocumentService:                 m.kvService,
 		OrgLookupService:                m.kvService,
@@ -0,0 +1,59 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_OrgWriteLimit(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--org-write-points-per-second", "2")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, "m f=1 1\nm f=2 2\n")
+
+	resp, err := nethttp.DefaultClient.Do(l.NewHTTPRequestOrFail(t, "POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), l.Auth.Token, "m f=3 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusTooManyRequests {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Fatal("expected a Retry-After header")
+	}
+
+	resp, err = nethttp.DefaultClient.Do(l.NewHTTPRequestOrFail(t, "GET", fmt.Sprintf("/api/v2/usage?orgID=%s", l.Org.ID), l.Auth.Token, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status code: %d: %s", resp.StatusCode, body)
+	}
+
+	var usage map[influxdb.UsageMetric]*influxdb.Usage
+	if err := json.Unmarshal(body, &usage); err != nil {
+		t.Fatal(err)
+	}
+	if u := usage[influxdb.UsageWriteRequestsThrottled]; u == nil || u.Value != 1 {
+		t.Errorf("expected 1 throttled write, got %+v", u)
+	}
+	if u := usage[influxdb.UsageLimitWritePoints]; u == nil || u.Value != 2 {
+		t.Errorf("expected a limit of 2 points per second, got %+v", u)
+	}
+	if _, ok := usage[influxdb.UsageSeries]; !ok {
+		t.Error("expected the series usage of the storage engine")
+	}
+}
@@ -0,0 +1,78 @@
+package launcher_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/pkger"
+)
+
+func TestLauncher_Pkger(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	svc := &http.PkgerService{Addr: l.URL(), Token: l.Auth.Token}
+
+	pkg, err := pkger.Parse(pkger.EncodingYAML, strings.NewReader(`apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: team
+  pkgVersion: "1"
+spec:
+  resources:
+    - kind: Label
+      name: team
+      color: "#326BBA"
+    - kind: Bucket
+      name: `+l.Bucket.Name+`
+      associations:
+        - kind: Label
+          name: team
+    - kind: Variable
+      name: hosts
+      type: constant
+      values: [a, b]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := svc.DryRun(ctx, l.Org.ID, pkg)
+	if err != nil {
+		t.Fatalf("unable to dry run package: %v", err)
+	}
+	exp := map[pkger.Kind]pkger.Change{
+		pkger.KindLabel:    pkger.ChangeCreate,
+		pkger.KindBucket:   pkger.ChangeUpdate,
+		pkger.KindVariable: pkger.ChangeCreate,
+	}
+	for _, e := range diff {
+		if e.Change != exp[e.Kind] {
+			t.Errorf("unexpected change of %s %q: got %s, exp %s", e.Kind, e.Name, e.Change, exp[e.Kind])
+		}
+	}
+
+	if _, err := svc.Apply(ctx, l.Org.ID, pkg); err != nil {
+		t.Fatalf("unable to apply package: %v", err)
+	}
+	if diff, err = svc.DryRun(ctx, l.Org.ID, pkg); err != nil {
+		t.Fatalf("unable to dry run package: %v", err)
+	} else if diff.HasChanges() {
+		t.Fatalf("expected no changes after applying the package, got %+v", diff)
+	}
+
+	exported, err := svc.CreatePkg(ctx, l.Org.ID, pkger.Metadata{Name: "team"})
+	if err != nil {
+		t.Fatalf("unable to export package: %v", err)
+	}
+	var names []string
+	for _, r := range exported.Spec.Resources {
+		names = append(names, string(r.Kind())+"/"+r.Name())
+	}
+	if got, exp := strings.Join(names, ","), "Label/team,Bucket/"+l.Bucket.Name+",Variable/hosts"; got != exp {
+		t.Errorf("unexpected exported resources: got %s, exp %s", got, exp)
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb"
@@ -255,3 +257,158 @@ from(bucket: "%s")
 		t.Errorf("unexpected error code -want/+got:\n\t- %v\n\t+ %v", got, want)
 	}
 }
+
+// This test checks that window aggregates pushed down to storage produce the
+// same tables as Flux does when it computes them itself.
+func TestPipeline_Query_WindowAggregatePushDown(t *testing.T) {
+	be := launcher.RunTestLauncherOrFail(t, ctx)
+	be.SetupOrFail(t)
+	defer be.ShutdownOrFail(t, ctx)
+
+	start := time.Date(2019, 11, 25, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 90; i++ {
+		ts := start.Add(time.Duration(i) * time.Second).UnixNano()
+		for _, host := range []string{"a", "b"} {
+			lines = append(lines, fmt.Sprintf(`cpu,host=%s f=%d.5,i=%di,s="v%d",b=%t %d`,
+				host, (i*7)%13, (i*5)%11-5, i%4, i%3 == 0, ts))
+		}
+	}
+	be.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	readTables := func(q string) []*executetest.Table {
+		t.Helper()
+		res := be.MustExecuteQuery(q)
+		defer res.Done()
+
+		var tables []*executetest.Table
+		for _, r := range res.Results {
+			if err := r.Tables().Do(func(tbl flux.Table) error {
+				ct, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				tables = append(tables, ct)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		executetest.NormalizeTables(tables)
+		return tables
+	}
+
+	for _, tt := range []struct {
+		agg    string
+		fields string
+	}{
+		{agg: "count", fields: `r._field == "f" or r._field == "i" or r._field == "s" or r._field == "b"`},
+		{agg: "sum", fields: `r._field == "f" or r._field == "i"`},
+		{agg: "mean", fields: `r._field == "f" or r._field == "i"`},
+		{agg: "min", fields: `r._field == "f" or r._field == "i"`},
+		{agg: "max", fields: `r._field == "f" or r._field == "i"`},
+		{agg: "first", fields: `r._field == "f" or r._field == "i" or r._field == "s" or r._field == "b"`},
+		{agg: "last", fields: `r._field == "f" or r._field == "i" or r._field == "s" or r._field == "b"`},
+	} {
+		t.Run(tt.agg, func(t *testing.T) {
+			q := fmt.Sprintf(`from(bucket: "%s")
+	|> range(start: 2019-11-25T00:00:03Z, stop: 2019-11-25T00:01:27Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and (%s))
+	|> window(every: 10s)`, be.Bucket.Name, tt.fields)
+
+			// The filter after the window prevents the push down.
+			want := readTables(q + fmt.Sprintf(`
+	|> filter(fn: (r) => true)
+	|> %s()`, tt.agg))
+			got := readTables(q + fmt.Sprintf(`
+	|> %s()`, tt.agg))
+
+			if len(want) == 0 {
+				t.Fatal("expected tables")
+			}
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestPipeline_QueryLog(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `m,k=v f=1i 946684800000000000`)
+	l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(`from(bucket: %q) |> range(start: 2000-01-01T00:00:00Z)`, l.Bucket.Name))
+
+	// The query is recorded once its response has been written, poll the
+	// queries bucket until it is.
+	qs := `from(bucket: "_queries")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._field == "buckets")
+	|> keep(columns: ["_value", "compilerType", "status"])`
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, qs)
+		if strings.Contains(got, l.Bucket.Name) {
+			if !strings.Contains(got, "flux") || !strings.Contains(got, "success") {
+				t.Fatalf("unexpected query log:\n%s", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("query not found in the query log:\n%s", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPipeline_ExplainAnalyze(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `m,k=v0 f=1i 946684800000000000
+m,k=v0 f=2i 946684810000000000
+m,k=v1 f=3i 946684800000000000
+n,k=v0 f=4i 946684800000000000`)
+
+	s := &phttp.ExplainService{
+		Addr:  l.URL(),
+		Token: l.Auth.Token,
+	}
+	e, err := s.Explain(ctx, &query.Request{
+		OrganizationID: l.Org.ID,
+		Compiler: lang.FluxCompiler{
+			Query: fmt.Sprintf(`from(bucket: %q) |> range(start: 2000-01-01T00:00:00Z) |> filter(fn: (r) => r._measurement == "m")`, l.Bucket.Name),
+		},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rules []string
+	for _, r := range e.Rules {
+		rules = append(rules, r.Name)
+	}
+	if diff := cmp.Diff([]string{"PushDownRangeRule", "PushDownFilterRule"}, rules); diff != "" {
+		t.Errorf("unexpected rules -want/+got\n%s", diff)
+	}
+
+	read := e.Physical[0]
+	if read.Kind != "ReadRangePhysKind" {
+		t.Fatalf("unexpected source kind %s", read.Kind)
+	}
+	p := read.Profile
+	if p == nil {
+		t.Fatal("missing profile of the source")
+	}
+	if p.Tables != 2 || p.Rows != 3 {
+		t.Errorf("unexpected profile: got %d tables and %d rows, want 2 and 3", p.Tables, p.Rows)
+	}
+	// The statistics are those the storage engine reports for the query,
+	// which may count values more than once.
+	if p.ScannedValues < 3 || p.ScannedBytes == 0 {
+		t.Errorf("unexpected cursor statistics: got %d values and %d bytes", p.ScannedValues, p.ScannedBytes)
+	}
+}
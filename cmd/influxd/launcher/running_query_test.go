@@ -0,0 +1,33 @@
+package launcher_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestLauncher_RunningQueries(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	s := &http.RunningQueryService{
+		Addr:  l.URL(),
+		Token: l.Auth.Token,
+	}
+
+	rqs, err := s.FindRunningQueries(ctx, query.RunningQueryFilter{OrganizationID: &l.Org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 0 {
+		t.Fatalf("expected no running queries, got %d", len(rqs))
+	}
+
+	if err := s.CancelRunningQuery(ctx, 1); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected canceling a missing query to fail with not found, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package launcher_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/secret"
+)
+
+func TestLauncher_EncryptedSecrets(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.KeySize))
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--secret-keys", "k1:"+key)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	if err := l.SecretService().PutSecret(ctx, l.Org.ID, "mytoken", "secrettoken"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := l.SecretService().LoadSecret(ctx, l.Org.ID, "mytoken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "secrettoken" {
+		t.Fatalf("unexpected secret %q", v)
+	}
+}
+
+func TestLauncher_InvalidSecretKeys(t *testing.T) {
+	l := launcher.NewTestLauncher()
+	defer os.RemoveAll(l.Path)
+	if err := l.Run(ctx, "--secret-keys", "k1:short"); err == nil {
+		l.Shutdown(ctx)
+		t.Fatal("expected launcher to fail with invalid secret keys")
+	}
+}
+
+func TestLauncher_FileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--secret-store", "file", "--secret-path", dir)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	orgDir := filepath.Join(dir, l.Org.ID.String())
+	if err := os.Mkdir(orgDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(orgDir, "mytoken"), []byte("secrettoken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := l.SecretService().LoadSecret(ctx, l.Org.ID, "mytoken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "secrettoken" {
+		t.Fatalf("unexpected secret %q", v)
+	}
+	if err := l.SecretService().PutSecret(ctx, l.Org.ID, "other", "v"); err != secret.ErrReadOnly {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+}
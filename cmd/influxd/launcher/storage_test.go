@@ -1,10 +1,14 @@
 package launcher_test
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/toml"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
@@ -91,11 +96,99 @@ func TestLauncher_WriteAndQuery(t *testing.T) {
 	}
 }
 
-func TestLauncher_BucketDelete(t *testing.T) {
+func TestLauncher_LegacyWriteAndQuery(t *testing.T) {
 	l := launcher.RunTestLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
 	defer l.ShutdownOrFail(t, ctx)
 
+	if err := l.KeyValueService().Create(ctx, &influxdb.DBRPMapping{
+		Cluster:         influxdb.DefaultDBRPCluster,
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  l.Org.ID,
+		BucketID:        l.Bucket.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write with the token as the password of the p parameter.
+	resp, err := nethttp.Post(l.URL()+"/write?db=db0&precision=s&u=USER&p="+l.Auth.Token, "text/plain", strings.NewReader("m,k=v f=100i 946684800\nm,k=v f=200i 946684810"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	tests := []struct {
+		name   string
+		params url.Values
+		exp    string
+	}{
+		{
+			name: "epoch",
+			params: url.Values{
+				"db":    {"db0"},
+				"q":     {"SELECT f FROM m"},
+				"epoch": {"s"},
+			},
+			exp: `{"results":[{"statement_id":0,"series":[{"name":"m","columns":["time","f"],"values":[[946684800,100],[946684810,200]]}]}]}` + "\n",
+		},
+		{
+			name: "chunked",
+			params: url.Values{
+				"db":         {"db0"},
+				"rp":         {"autogen"},
+				"q":          {"SELECT f FROM m"},
+				"chunked":    {"true"},
+				"chunk_size": {"1"},
+			},
+			exp: `{"results":[{"statement_id":0,"series":[{"name":"m","columns":["time","f"],"values":[["2000-01-01T00:00:00Z",100]],"partial":true}],"partial":true}]}` + "\n" +
+				`{"results":[{"statement_id":0,"series":[{"name":"m","columns":["time","f"],"values":[["2000-01-01T00:00:10Z",200]]}]}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := nethttp.NewRequest("GET", l.URL()+"/query?"+tt.params.Encode(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.SetBasicAuth("USER", l.Auth.Token)
+
+			resp, err := nethttp.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != nethttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+			}
+			if diff := cmp.Diff(string(body), tt.exp); diff != "" {
+				t.Errorf("unexpected query results -got/+exp\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLauncher_BucketDelete(t *testing.T) {
+	// The query below would be recorded in the queries bucket of the org,
+	// disable the query log to count the series of the deleted bucket only.
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--query-log-disabled")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
 	// Execute single write against the server.
 	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), `m,k=v f=100i 946684800000000000`))
 	if err != nil {
@@ -151,6 +244,12 @@ func TestLauncher_BucketDelete(t *testing.T) {
 		t.Fatalf("unexpected status code: %d, body: %s, headers: %v", resp.StatusCode, body, resp.Header)
 	}
 
+	// The deletion of the bucket is recorded in the audit bucket of the org,
+	// remove it to count the series of the deleted bucket only.
+	if err := engine.DeleteBucket(ctx, l.Org.ID, influxdb.AuditSystemBucketID); err != nil {
+		t.Fatal(err)
+	}
+
 	// Verify that the data has been removed from the storage engine.
 	if got, exp := engine.SeriesCardinality(), int64(0); got != exp {
 		t.Fatalf("after bucket delete got %d, exp %d", got, exp)
@@ -247,3 +346,145 @@ func TestStorage_CacheSnapshot_Age(t *testing.T) {
 		t.Fatalf("got %d series in TSM files, expected %d", got, exp)
 	}
 }
+
+func TestStorage_DeletePredicate(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `
+cpu,host=a f=1i 946684800000000000
+cpu,host=b f=2i 946684800000000000
+mem,host=a f=3i 946684800000000000
+`)
+
+	body := `{"start":"2000-01-01T00:00:00Z","stop":"2000-01-02T00:00:00Z","predicate":"_measurement=\"cpu\" AND host=\"a\""}`
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/delete?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	qs := `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> keep(columns: ["_measurement", "host", "_value"])`
+	exp := `,result,table,_value,_measurement,host` + "\r\n" +
+		`,_result,0,2,cpu,b` + "\r\n" +
+		`,_result,1,3,mem,a` + "\r\n\r\n"
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, qs); !cmp.Equal(got, exp) {
+		t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+func TestStorage_BackupRestore(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `
+cpu,host=a f=1i 946684800000000000
+cpu,host=b f=2i 946771200000000000
+mem,host=a f=3i 946684800000000000
+`)
+
+	dir, err := ioutil.TempDir("", "backup-restore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Backup a single day of the bucket.
+	start, stop := time.Unix(0, 946684800000000000).UTC(), time.Unix(0, 946771199000000000).UTC()
+	bs := &http.BackupService{Addr: l.URL(), Token: l.Auth.Token}
+	backup, err := bs.CreateBackup(ctx, influxdb.BackupFilter{BucketID: &l.Bucket.ID, Start: &start, Stop: &stop})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	for _, file := range backup.Files {
+		path := filepath.Join(dir, file)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bs.FetchBackupFile(ctx, backup.ID, file, f); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(file) == "."+tsm1.TSMFileExtension {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		t.Fatal("expected backup to contain TSM files")
+	}
+
+	if err := bs.DeleteBackup(ctx, backup.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(l.Engine().InternalBackupPath(backup.ID)); !os.IsNotExist(err) {
+		t.Fatalf("expected backup to be removed, got %v", err)
+	}
+
+	// Restore the backup into a new bucket.
+	dst := &influxdb.Bucket{OrgID: l.Org.ID, Name: "RESTORED"}
+	if err := l.BucketService().CreateBucket(ctx, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := &http.RestoreService{Addr: l.URL(), Token: l.Auth.Token}
+	for _, file := range files {
+		restoreBucketOrFail(t, rs, file, storage.BucketCopyOptions(l.Org.ID, l.Bucket.ID, dst.OrgID, dst.ID), dst)
+	}
+
+	qs := `from(bucket:"RESTORED") |> range(start:2000-01-01T00:00:00Z,stop:2000-01-03T00:00:00Z) |> keep(columns: ["_measurement", "host", "_value"])`
+	exp := `,result,table,_value,_measurement,host` + "\r\n" +
+		`,_result,0,1,cpu,a` + "\r\n" +
+		`,_result,1,3,mem,a` + "\r\n\r\n"
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, qs); !cmp.Equal(got, exp) {
+		t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+// restoreBucketOrFail rewrites the keys of the TSM file at path with opts and
+// restores them into the bucket.
+func restoreBucketOrFail(tb testing.TB, rs influxdb.RestoreService, path string, opts tsm1.CopyOptions, b *influxdb.Bucket) {
+	tb.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	w, err := tsm1.NewTSMWriter(&buf)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if n, err := tsm1.Copy(r, w, opts); err != nil {
+		tb.Fatal(err)
+	} else if n == 0 {
+		return
+	}
+	if err := w.WriteIndex(); err != nil {
+		tb.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := rs.RestoreBucket(ctx, b.OrgID, b.ID, &buf); err != nil {
+		tb.Fatal(err)
+	}
+}
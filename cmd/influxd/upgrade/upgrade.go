@@ -0,0 +1,132 @@
+// Package upgrade provides the command migrating the metadata store of influxd.
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/spf13/cobra"
+)
+
+var upgradeFlags = struct {
+	boltPath string
+	dryRun   bool
+	to       int
+}{}
+
+// NewCommand creates the upgrade command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Migrate the metadata store to the version of this release",
+		Long: `
+This command applies the pending migrations of the metadata store, or reverts
+the migrations after the version given with --to. influxd applies the pending
+migrations when it starts; reverting migrations is required before running an
+older release. influxd must not be running.
+
+Some migrations cannot be reverted, such as the hashing of the tokens of
+authorizations; migrating to a version before them fails without changing
+the store.
+
+For each migration, the following is output:
+	* The version and the name of the migration;
+	* Whether it is applied or pending, and when it was applied;
+	* With --dry-run, whether it would be applied or reverted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return upgrade(context.Background(), os.Stdout)
+		},
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringVar(&upgradeFlags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to boltdb database")
+	cmd.Flags().BoolVar(&upgradeFlags.dryRun, "dry-run", false, "report the pending migrations without applying them")
+	cmd.Flags().IntVar(&upgradeFlags.to, "to", -1, "version to migrate to, the migrations after it are reverted; defaults to the latest version")
+
+	return cmd
+}
+
+func upgrade(ctx context.Context, w io.Writer) error {
+	if _, err := os.Stat(upgradeFlags.boltPath); err != nil {
+		return fmt.Errorf("unable to find metadata store: %v", err)
+	}
+
+	store := bolt.NewKVStore(upgradeFlags.boltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	m, err := kv.NewService(store).Migrator()
+	if err != nil {
+		return err
+	}
+	to := upgradeFlags.to
+	if to < 0 {
+		to = m.Latest()
+	}
+
+	if err := m.CheckMigrateTo(ctx, to); err != nil {
+		return err
+	}
+	if !upgradeFlags.dryRun {
+		if err := m.MigrateTo(ctx, to); err != nil {
+			return err
+		}
+	}
+	return report(ctx, w, m, to)
+}
+
+// report writes the status of each migration, and what migrating to version
+// would do during a dry run.
+func report(ctx context.Context, w io.Writer, m *kv.Migrator, to int) error {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	header := "VERSION\tNAME\tSTATUS\tAPPLIED AT"
+	if upgradeFlags.dryRun {
+		header += "\tACTION"
+	}
+	fmt.Fprintln(tw, header)
+	for _, a := range applied {
+		line := fmt.Sprintf("%d\t%s\tapplied\t%s", a.Version, a.Name, a.AppliedAt.Format(time.RFC3339))
+		if upgradeFlags.dryRun && a.Version > to {
+			line += "\trevert"
+		}
+		fmt.Fprintln(tw, line)
+	}
+	for _, p := range pending {
+		line := fmt.Sprintf("%d\t%s\tpending\t", p.Version, p.Name)
+		if upgradeFlags.dryRun && p.Version <= to {
+			line += "\tapply"
+		}
+		fmt.Fprintln(tw, line)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if upgradeFlags.dryRun {
+		fmt.Fprintf(w, "\n%d pending migration(s); dry run, nothing was changed\n", len(pending))
+	}
+	return nil
+}
@@ -7,6 +7,10 @@ import (
 	"unicode"
 )
 
+// DefaultDBRPCluster is the cluster of the mappings resolved by the 1.x
+// compatible /query and /write endpoints of this server.
+const DefaultDBRPCluster = "local"
+
 // DBRPMappingService provides a mapping of cluster, database and retention policy to an organization ID and bucket ID.
 type DBRPMappingService interface {
 	// FindBy returns the dbrp mapping the for cluster, db and rp.
@@ -0,0 +1,18 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// Predicate is something that can match on a series key.
+type Predicate interface {
+	Matches(key []byte) bool
+	Marshal() ([]byte, error)
+}
+
+// DeleteService deletes data from a bucket.
+type DeleteService interface {
+	// DeleteBucketRangePredicate deletes the data in the bucket within [min, max].
+	// If pred is non-nil only series matching the predicate are removed.
+	DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID ID, min, max int64, pred Predicate) error
+}
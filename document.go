@@ -113,12 +113,26 @@ func WithOrg(org string) func(ID, DocumentIndex) error {
 	}
 }
 
+// tokenAuthorization returns the authorization of the authorizer provided, looking
+// through the authorizers wrapping another one, such as those granting its roles.
+func tokenAuthorization(a Authorizer) (*Authorization, bool) {
+	for {
+		switch t := a.(type) {
+		case *Authorization:
+			return t, true
+		case interface{ Unwrap() Authorizer }:
+			a = t.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
 // AuthorizedWithOrg adds the provided org as an owner of the document if
 // the authorizer is allowed to access the org in being added.
 func AuthorizedWithOrg(a Authorizer, org string) func(ID, DocumentIndex) error {
-	switch t := a.(type) {
-	case *Authorization:
-		return TokenAuthorizedWithOrg(t, org)
+	if _, ok := tokenAuthorization(a); ok {
+		return TokenAuthorizedWithOrg(a, org)
 	}
 
 	return func(id ID, idx DocumentIndex) error {
@@ -137,9 +151,10 @@ func AuthorizedWithOrg(a Authorizer, org string) func(ID, DocumentIndex) error {
 
 // TokenAuthorizedWithOrg ensures that the authorization provided is allowed to perform
 // write actions against the org provided.
-func TokenAuthorizedWithOrg(a *Authorization, org string) func(ID, DocumentIndex) error {
+func TokenAuthorizedWithOrg(a Authorizer, org string) func(ID, DocumentIndex) error {
 	return func(id ID, idx DocumentIndex) error {
-		if !a.IsActive() {
+		t, ok := tokenAuthorization(a)
+		if !ok || !t.IsActive() {
 			return &Error{
 				Code: EUnauthorized,
 				Msg:  "authorization cannot add org as document owner",
@@ -180,9 +195,8 @@ func WithOrgID(orgID ID) func(ID, DocumentIndex) error {
 // AuthorizedWithOrgID adds the provided org as an owner of the document if
 // the authorizer is allowed to access the org in being added.
 func AuthorizedWithOrgID(a Authorizer, orgID ID) func(ID, DocumentIndex) error {
-	switch t := a.(type) {
-	case *Authorization:
-		return TokenAuthorizedWithOrgID(t, orgID)
+	if _, ok := tokenAuthorization(a); ok {
+		return TokenAuthorizedWithOrgID(a, orgID)
 	}
 
 	return func(id ID, idx DocumentIndex) error {
@@ -196,9 +210,10 @@ func AuthorizedWithOrgID(a Authorizer, orgID ID) func(ID, DocumentIndex) error {
 
 // TokenAuthorizedWithOrgID ensures that the authorization provided is allowed to perform
 // write actions against the org provided.
-func TokenAuthorizedWithOrgID(a *Authorization, orgID ID) func(ID, DocumentIndex) error {
+func TokenAuthorizedWithOrgID(a Authorizer, orgID ID) func(ID, DocumentIndex) error {
 	return func(id ID, idx DocumentIndex) error {
-		if !a.IsActive() {
+		t, ok := tokenAuthorization(a)
+		if !ok || !t.IsActive() {
 			return &Error{
 				Code: EUnauthorized,
 				Msg:  "authorization cannot add org as document owner",
@@ -257,9 +272,8 @@ func WithoutLabel(lid ID) func(ID, DocumentIndex) error {
 // it checks to see if the user associated with the authorizer is an accessor
 // of the of the org that owns the document.
 func Authorized(a Authorizer) func(ID, DocumentIndex) error {
-	switch t := a.(type) {
-	case *Authorization:
-		return TokenAuthorized(t)
+	if _, ok := tokenAuthorization(a); ok {
+		return TokenAuthorized(a)
 	}
 
 	return func(docID ID, idx DocumentIndex) error {
@@ -282,9 +296,10 @@ func Authorized(a Authorizer) func(ID, DocumentIndex) error {
 }
 
 // TokenAuthorized checks to see if the authorization provided is allowed access the orgs documents.
-func TokenAuthorized(a *Authorization) func(ID, DocumentIndex) error {
+func TokenAuthorized(a Authorizer) func(ID, DocumentIndex) error {
 	return func(id ID, idx DocumentIndex) error {
-		if !a.IsActive() {
+		t, ok := tokenAuthorization(a)
+		if !ok || !t.IsActive() {
 			return &Error{
 				Code: EUnauthorized,
 				Msg:  "authorizer cannot access document",
@@ -300,7 +315,7 @@ func TokenAuthorized(a *Authorization) func(ID, DocumentIndex) error {
 			orgs[oid] = true
 		}
 
-		for _, p := range a.Permissions {
+		for _, p := range t.Permissions {
 			if p.Action == ReadAction {
 				continue
 			}
@@ -320,6 +335,16 @@ func TokenAuthorized(a *Authorization) func(ID, DocumentIndex) error {
 
 		}
 
+		// The authorizer may also be allowed by the roles granted to it.
+		for _, oid := range oids {
+			if a.Allowed(Permission{
+				Action:   WriteAction,
+				Resource: Resource{Type: DocumentsResourceType, OrgID: &oid, ID: &id},
+			}) {
+				return nil
+			}
+		}
+
 		return &Error{
 			Code: EUnauthorized,
 			Msg:  "authorization cannot access document",
@@ -350,12 +375,7 @@ func AuthorizedWhereOrgID(a Authorizer, id ID) func(DocumentIndex, DocumentDecor
 }
 
 func authorizedWhereOrgID(a Authorizer, id ID, idx DocumentIndex) ([]ID, error) {
-	var isTokenAuth bool
-	switch a.(type) {
-	case *Authorization:
-		isTokenAuth = true
-	}
-	if isTokenAuth {
+	if _, isTokenAuth := tokenAuthorization(a); isTokenAuth {
 		if !a.Allowed(Permission{
 			// TODO(desa): this should be configurable, but should be sufficient for now. In particular this
 			// means that tokens cannot be used to delete documents for now if the AuthorizedWhereOrg is called.
@@ -392,7 +412,7 @@ func AuthorizedWhereOrg(a Authorizer, org string) func(DocumentIndex, DocumentDe
 
 // TokenAuthorizedWhereOrg ensures that the authorization is allowed to access the org provideds documents and then
 // retrieves a list of the ids of the documents that belong to the provided org.
-func TokenAuthorizedWhereOrg(a *Authorization, org string) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
+func TokenAuthorizedWhereOrg(a Authorizer, org string) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
 	return func(idx DocumentIndex, _ DocumentDecorator) ([]ID, error) {
 		oid, err := idx.FindOrganizationByName(org)
 		if err != nil {
@@ -406,9 +426,8 @@ func TokenAuthorizedWhereOrg(a *Authorization, org string) func(DocumentIndex, D
 // retrieving the list of all orgs where the user is an accessor and the retrieving all of their
 // documents.
 func AuthorizedWhere(a Authorizer) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
-	switch t := a.(type) {
-	case *Authorization:
-		return TokenAuthorizedWhere(t)
+	if _, ok := tokenAuthorization(a); ok {
+		return TokenAuthorizedWhere(a)
 	}
 
 	var ids []ID
@@ -440,19 +459,22 @@ func AuthorizedWhere(a Authorizer) func(DocumentIndex, DocumentDecorator) ([]ID,
 }
 
 // TokenAuthorizedWhere retrieves all documents that the authorization is allowed to access.
-func TokenAuthorizedWhere(a *Authorization) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
+func TokenAuthorizedWhere(a Authorizer) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
 	// TODO(desa): what to do about retrieving all documents using auth? (e.g. write/read:documents/*)
 	var ids []ID
 	return func(idx DocumentIndex, _ DocumentDecorator) ([]ID, error) {
-		if !a.IsActive() {
+		t, ok := tokenAuthorization(a)
+		if !ok || !t.IsActive() {
 			return nil, &Error{
 				Code: EUnauthorized,
 				Msg:  "authorizer cannot access documents",
 			}
 		}
 
-		for _, p := range a.Permissions {
+		orgs := map[ID]bool{}
+		for _, p := range t.Permissions {
 			if p.Resource.Type == DocumentsResourceType && p.Resource.OrgID != nil {
+				orgs[*p.Resource.OrgID] = true
 				oids, err := idx.GetAccessorsDocuments("org", *p.Resource.OrgID)
 				if err != nil {
 					return nil, err
@@ -465,6 +487,18 @@ func TokenAuthorizedWhere(a *Authorization) func(DocumentIndex, DocumentDecorato
 			}
 		}
 
+		// The roles granted to the authorization may allow the documents of its org.
+		if !orgs[t.OrgID] && a.Allowed(Permission{
+			Action:   ReadAction,
+			Resource: Resource{Type: DocumentsResourceType, OrgID: &t.OrgID},
+		}) {
+			oids, err := idx.GetAccessorsDocuments("org", t.OrgID)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, oids...)
+		}
+
 		return ids, nil
 	}
 }
@@ -479,9 +513,8 @@ func WhereID(docID ID) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
 // AuthorizedWhereID ensures that the authorizer provided either has the permission to access the document
 // or the user associated with the authorizer is an org accessor.
 func AuthorizedWhereID(a Authorizer, docID ID) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
-	switch t := a.(type) {
-	case *Authorization:
-		return TokenAuthorizedWhereID(t, docID)
+	if _, ok := tokenAuthorization(a); ok {
+		return TokenAuthorizedWhereID(a, docID)
 	}
 
 	return func(idx DocumentIndex, _ DocumentDecorator) ([]ID, error) {
@@ -504,9 +537,10 @@ func AuthorizedWhereID(a Authorizer, docID ID) func(DocumentIndex, DocumentDecor
 }
 
 // TokenAuthorizedWhereID ensures that the authorization provided has the permission to access the document.
-func TokenAuthorizedWhereID(a *Authorization, docID ID) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
+func TokenAuthorizedWhereID(a Authorizer, docID ID) func(DocumentIndex, DocumentDecorator) ([]ID, error) {
 	return func(idx DocumentIndex, _ DocumentDecorator) ([]ID, error) {
-		if !a.IsActive() {
+		t, ok := tokenAuthorization(a)
+		if !ok || !t.IsActive() {
 			return nil, &Error{
 				Code: EUnauthorized,
 				Msg:  "authorizer cannot access documents",
@@ -522,7 +556,7 @@ func TokenAuthorizedWhereID(a *Authorization, docID ID) func(DocumentIndex, Docu
 			orgs[oid] = true
 		}
 
-		for _, p := range a.Permissions {
+		for _, p := range t.Permissions {
 			// If the authz has a direct permission to access the resource
 			if p.Resource.Type == DocumentsResourceType && p.Resource.ID != nil && docID == *p.Resource.ID {
 				return []ID{docID}, nil
@@ -537,6 +571,16 @@ func TokenAuthorizedWhereID(a *Authorization, docID ID) func(DocumentIndex, Docu
 			}
 		}
 
+		// The authorizer may also be allowed by the roles granted to it.
+		for _, oid := range oids {
+			if a.Allowed(Permission{
+				Action:   ReadAction,
+				Resource: Resource{Type: DocumentsResourceType, OrgID: &oid, ID: &docID},
+			}) {
+				return []ID{docID}, nil
+			}
+		}
+
 		return nil, &Error{
 			Code: EUnauthorized,
 			Msg:  "authorization cannot access document",
@@ -0,0 +1,122 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskTypeDownsample is the type of the tasks that downsample the data of a
+// bucket into the target bucket of one of its downsample tiers.
+const TaskTypeDownsample = "downsample"
+
+// downsampleAggregates are the aggregate functions that can be applied to the
+// fields of each type.
+var downsampleAggregates = map[SchemaFieldType][]string{
+	SchemaFieldTypeFloat:    {"count", "first", "last", "max", "mean", "median", "min", "spread", "stddev", "sum"},
+	SchemaFieldTypeInteger:  {"count", "first", "last", "max", "mean", "median", "min", "spread", "stddev", "sum"},
+	SchemaFieldTypeUnsigned: {"count", "first", "last", "max", "mean", "median", "min", "spread", "stddev", "sum"},
+	SchemaFieldTypeString:   {"count", "first", "last"},
+	SchemaFieldTypeBoolean:  {"count", "first", "last"},
+}
+
+// DownsampleTier rolls the data of a bucket up into a target bucket once it is
+// older than After. Every field is aggregated over windows of Every by each of
+// the aggregate functions of its type and written to the target bucket as
+// <field>_<function>.
+//
+// The tier is backed by a task that is managed by the bucket service. The raw
+// data of the bucket is only deleted by retention once the task has
+// downsampled it.
+type DownsampleTier struct {
+	TargetBucketID ID                           `json:"targetBucketID"`
+	Every          time.Duration                `json:"every"`
+	After          time.Duration                `json:"after"`
+	Aggregates     map[SchemaFieldType][]string `json:"aggregates"`
+	TaskID         ID                           `json:"taskID,omitempty"`
+}
+
+// Valid returns an error if the tier has no target bucket, a window shorter
+// than a second, a negative age threshold or an unknown aggregate function.
+func (t *DownsampleTier) Valid() error {
+	if !t.TargetBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier must have a target bucket",
+		}
+	}
+	if t.Every < time.Second {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier window must be at least one second",
+		}
+	}
+	if t.After < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier age threshold must not be negative",
+		}
+	}
+	if len(t.Aggregates) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier must have at least one aggregate function",
+		}
+	}
+	for typ, fns := range t.Aggregates {
+		if err := typ.Valid(); err != nil {
+			return err
+		}
+		seen := make(map[string]bool, len(fns))
+		for _, fn := range fns {
+			if !isDownsampleAggregate(typ, fn) {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("aggregate function %q cannot be applied to %s fields", fn, typ),
+				}
+			}
+			if seen[fn] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("aggregate function %q is declared twice for %s fields", fn, typ),
+				}
+			}
+			seen[fn] = true
+		}
+	}
+	return nil
+}
+
+func isDownsampleAggregate(typ SchemaFieldType, fn string) bool {
+	for _, a := range downsampleAggregates[typ] {
+		if a == fn {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidDownsampleTiers returns an error if any tier of bucketID is invalid,
+// targets the bucket itself or targets the same bucket as another tier.
+func ValidDownsampleTiers(bucketID ID, tiers []DownsampleTier) error {
+	targets := make(map[ID]bool, len(tiers))
+	for i := range tiers {
+		t := &tiers[i]
+		if err := t.Valid(); err != nil {
+			return err
+		}
+		if t.TargetBucketID == bucketID {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "downsample tier must not target its own bucket",
+			}
+		}
+		if targets[t.TargetBucketID] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("bucket %s is the target of more than one downsample tier", t.TargetBucketID),
+			}
+		}
+		targets[t.TargetBucketID] = true
+	}
+	return nil
+}
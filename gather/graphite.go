@@ -0,0 +1,199 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	defaultGraphiteTemplate  = "measurement*"
+	defaultGraphiteSeparator = "."
+	defaultGraphiteField     = "value"
+
+	// graphiteReadTimeout is the time the metrics are read from a graphite
+	// socket that does not close the connection.
+	graphiteReadTimeout = 5 * time.Second
+)
+
+// graphiteScraper reads the metrics a graphite target writes in the plaintext
+// protocol to the connections made to its socket, until it closes the
+// connection or graphiteReadTimeout expires.
+// implements Scraper interfaces.
+type graphiteScraper struct{}
+
+// Gather connects to the socket of the target and parses the metrics read.
+func (s *graphiteScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	u, err := url.Parse(target.URL)
+	if err != nil {
+		return collected, err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return collected, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(graphiteReadTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return collected, err
+	}
+
+	return s.parse(conn, target, time.Now())
+}
+
+func (s *graphiteScraper) parse(r io.Reader, target influxdb.ScraperTarget, now time.Time) (collected MetricsCollection, err error) {
+	tmpl := newGraphiteTemplate(target.Graphite)
+
+	ms := make([]Metrics, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		m, err := tmpl.parseLine(line, now)
+		if err != nil {
+			return collected, err
+		}
+		ms = append(ms, m)
+	}
+	// The socket not closing the connection before the deadline is not an
+	// error, the metrics read so far are gathered.
+	if err := scanner.Err(); err != nil {
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			return collected, err
+		}
+	}
+
+	collected = MetricsCollection{
+		MetricsSlice: ms,
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}
+	return collected, nil
+}
+
+// graphiteTemplate maps the parts of graphite paths to a measurement, tags
+// and a field.
+type graphiteTemplate struct {
+	parts     []string
+	greedy    bool
+	separator string
+}
+
+func newGraphiteTemplate(cfg *influxdb.GraphiteScraperConfig) *graphiteTemplate {
+	tmpl, sep := defaultGraphiteTemplate, defaultGraphiteSeparator
+	if cfg != nil && cfg.Template != "" {
+		tmpl = cfg.Template
+	}
+	if cfg != nil && cfg.Separator != "" {
+		sep = cfg.Separator
+	}
+
+	t := &graphiteTemplate{
+		parts:     strings.Split(tmpl, "."),
+		separator: sep,
+	}
+	if last := t.parts[len(t.parts)-1]; strings.HasSuffix(last, "*") {
+		t.parts[len(t.parts)-1] = strings.TrimSuffix(last, "*")
+		t.greedy = true
+	}
+	return t
+}
+
+// parseLine parses a line of the plaintext protocol, "path value [timestamp]",
+// where the path may be followed by tags as in "path;tag=value".
+func (t *graphiteTemplate) parseLine(line string, now time.Time) (Metrics, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return Metrics{}, fmt.Errorf("invalid graphite line %q", line)
+	}
+
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return Metrics{}, fmt.Errorf("invalid value of graphite line %q", line)
+	}
+
+	ts := now
+	if len(fields) == 3 {
+		sec, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return Metrics{}, fmt.Errorf("invalid timestamp of graphite line %q", line)
+		}
+		// Graphite clients send -1 to let the server set the time.
+		if sec >= 0 {
+			ts = time.Unix(0, int64(sec*1e9))
+		}
+	}
+
+	pathTags := strings.Split(fields[0], ";")
+	measurement, tags, field := t.apply(pathTags[0])
+	for _, tag := range pathTags[1:] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 2 && kv[0] != "" && kv[1] != "" {
+			tags[kv[0]] = kv[1]
+		}
+	}
+
+	return Metrics{
+		Name:      measurement,
+		Tags:      tags,
+		Fields:    map[string]interface{}{field: value},
+		Timestamp: ts,
+		Type:      MetricTypeUntyped,
+	}, nil
+}
+
+// apply maps the parts of path to the measurement, tags and field named by the
+// template.
+func (t *graphiteTemplate) apply(path string) (string, map[string]string, string) {
+	nodes := strings.Split(path, ".")
+
+	var measurement, field []string
+	tagValues := make(map[string][]string)
+	for i, name := range t.parts {
+		if i >= len(nodes) {
+			break
+		}
+		values := nodes[i : i+1]
+		if i == len(t.parts)-1 && t.greedy {
+			values = nodes[i:]
+		}
+		switch name {
+		case "":
+		case "measurement":
+			measurement = append(measurement, values...)
+		case "field":
+			field = append(field, values...)
+		default:
+			tagValues[name] = append(tagValues[name], values...)
+		}
+	}
+
+	if len(measurement) == 0 {
+		measurement = nodes
+	}
+	tags := make(map[string]string, len(tagValues))
+	for k, v := range tagValues {
+		tags[k] = strings.Join(v, t.separator)
+	}
+	f := defaultGraphiteField
+	if len(field) > 0 {
+		f = strings.Join(field, t.separator)
+	}
+	return strings.Join(measurement, t.separator), tags, f
+}
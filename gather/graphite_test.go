@@ -0,0 +1,103 @@
+package gather
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestGraphiteScraper(t *testing.T) {
+	now := time.Unix(100, 0)
+	cases := []struct {
+		name   string
+		lines  string
+		cfg    *influxdb.GraphiteScraperConfig
+		ms     []Metrics
+		hasErr bool
+	}{
+		{
+			name:  "default template",
+			lines: "servers.a.cpu 1.5 1546300800\nservers.b.cpu 2 -1\n",
+			ms: []Metrics{
+				{
+					Name:      "servers.a.cpu",
+					Tags:      map[string]string{},
+					Fields:    map[string]interface{}{"value": 1.5},
+					Timestamp: time.Unix(1546300800, 0),
+					Type:      MetricTypeUntyped,
+				},
+				{
+					Name:      "servers.b.cpu",
+					Tags:      map[string]string{},
+					Fields:    map[string]interface{}{"value": float64(2)},
+					Timestamp: now,
+					Type:      MetricTypeUntyped,
+				},
+			},
+		},
+		{
+			name:  "template with tags and fields",
+			lines: "us.a.cpu.user.total;env=prod 3\n",
+			cfg: &influxdb.GraphiteScraperConfig{
+				Template:  "region.host.measurement.field*",
+				Separator: "_",
+			},
+			ms: []Metrics{
+				{
+					Name:      "cpu",
+					Tags:      map[string]string{"region": "us", "host": "a", "env": "prod"},
+					Fields:    map[string]interface{}{"user_total": float64(3)},
+					Timestamp: now,
+					Type:      MetricTypeUntyped,
+				},
+			},
+		},
+		{
+			name:  "skipped parts",
+			lines: "stats.cpu 4\n",
+			cfg:   &influxdb.GraphiteScraperConfig{Template: ".measurement"},
+			ms: []Metrics{
+				{
+					Name:      "cpu",
+					Tags:      map[string]string{},
+					Fields:    map[string]interface{}{"value": float64(4)},
+					Timestamp: now,
+					Type:      MetricTypeUntyped,
+				},
+			},
+		},
+		{
+			name:   "invalid value",
+			lines:  "servers.a.cpu high\n",
+			hasErr: true,
+		},
+		{
+			name:   "invalid line",
+			lines:  "servers.a.cpu\n",
+			hasErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			target := influxdb.ScraperTarget{
+				Type:     influxdb.GraphiteScraperType,
+				OrgID:    *orgID,
+				BucketID: *bucketID,
+				Graphite: c.cfg,
+			}
+			collected, err := new(graphiteScraper).parse(strings.NewReader(c.lines), target, now)
+			if (err != nil) != c.hasErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if c.hasErr {
+				return
+			}
+			if diff := cmp.Diff(MetricsSlice(c.ms), collected.MetricsSlice); diff != "" {
+				t.Errorf("unexpected metrics -want/+got\n%s", diff)
+			}
+		})
+	}
+}
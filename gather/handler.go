@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/nats"
 	"go.uber.org/zap"
 )
 
+// defaultScrapeTimeout is the maximum duration of the scrapes of the targets
+// without a timeout.
+const defaultScrapeTimeout = 30 * time.Second
+
 // handler implents nats Handler interface.
 type handler struct {
 	Scraper   Scraper
@@ -29,7 +34,35 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 		return
 	}
 
-	ms, err := h.Scraper.Gather(context.TODO(), *req)
+	timeout := defaultScrapeTimeout
+	if req.Timeout != nil {
+		timeout = req.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var httpStatus int
+	start := time.Now()
+	ms, err := h.Scraper.Gather(withHTTPStatus(ctx, &httpStatus), *req)
+	status := targetStatus{
+		OrgID: req.OrgID,
+		Name:  req.Name,
+		Type:  req.Type,
+		Status: influxdb.ScraperTargetStatus{
+			TargetID:   req.ID,
+			Status:     influxdb.ScraperTargetUp,
+			LastScrape: start,
+			Duration:   influxdb.Duration{Duration: time.Since(start)},
+			HTTPStatus: httpStatus,
+			Samples:    len(ms.MetricsSlice),
+		},
+	}
+	if err != nil {
+		status.Status.Status = influxdb.ScraperTargetDown
+		status.Status.Error = err.Error()
+	}
+	defer h.publishStatus(status)
+
 	if err != nil {
 		h.Logger.Error("unable to gather", zap.Error(err))
 		return
@@ -48,3 +81,16 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 	}
 
 }
+
+// publishStatus sends the outcome of a scrape to the status queue.
+func (h *handler) publishStatus(status targetStatus) {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(status); err != nil {
+		h.Logger.Error("unable to marshal json", zap.Error(err))
+		return
+	}
+
+	if err := h.Publisher.Publish(StatusSubject, buf); err != nil {
+		h.Logger.Error("unable to publish scraper status", zap.Error(err))
+	}
+}
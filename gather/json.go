@@ -0,0 +1,175 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// jsonScraper maps the documents of json endpoints to metrics with the JSONPath
+// expressions of the configuration of the targets.
+// implements Scraper interfaces.
+type jsonScraper struct{}
+
+// Gather requests the document at the target url and maps it to metrics.
+func (s *jsonScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	if target.JSON == nil {
+		return collected, fmt.Errorf("json scraper target %s has no json configuration", target.ID)
+	}
+
+	req, err := http.NewRequest("GET", target.URL, nil)
+	if err != nil {
+		return collected, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return collected, err
+	}
+	defer resp.Body.Close()
+	setHTTPStatus(ctx, resp.StatusCode)
+
+	if resp.StatusCode/100 != 2 {
+		return collected, fmt.Errorf("json scraper target %s returned status %s", target.ID, resp.Status)
+	}
+
+	return s.parse(resp.Body, target, time.Now())
+}
+
+func (s *jsonScraper) parse(r io.Reader, target influxdb.ScraperTarget, now time.Time) (collected MetricsCollection, err error) {
+	cfg := target.JSON
+
+	var doc interface{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return collected, fmt.Errorf("reading json document failed: %s", err)
+	}
+
+	objects := []interface{}{doc}
+	if cfg.Root != "" {
+		root, err := parseJSONPath(cfg.Root)
+		if err != nil {
+			return collected, err
+		}
+		objects = root.eval(doc)
+	}
+
+	fields, err := parseJSONPathMappings(cfg.Fields)
+	if err != nil {
+		return collected, err
+	}
+	tags, err := parseJSONPathMappings(cfg.Tags)
+	if err != nil {
+		return collected, err
+	}
+	var timePath jsonPath
+	if cfg.TimePath != "" {
+		if timePath, err = parseJSONPath(cfg.TimePath); err != nil {
+			return collected, err
+		}
+	}
+
+	ms := make([]Metrics, 0, len(objects))
+	for _, o := range objects {
+		m := Metrics{
+			Name:      cfg.Measurement,
+			Tags:      make(map[string]string),
+			Fields:    make(map[string]interface{}),
+			Timestamp: now,
+			Type:      MetricTypeUntyped,
+		}
+		for name, p := range fields {
+			if v, ok := jsonFieldValue(first(p.eval(o))); ok {
+				m.Fields[name] = v
+			}
+		}
+		if len(m.Fields) == 0 {
+			continue
+		}
+		for name, p := range tags {
+			if v, ok := jsonTagValue(first(p.eval(o))); ok {
+				m.Tags[name] = v
+			}
+		}
+		if timePath != nil {
+			if t, ok := jsonTimeValue(first(timePath.eval(o))); ok {
+				m.Timestamp = t
+			}
+		}
+		ms = append(ms, m)
+	}
+
+	collected = MetricsCollection{
+		MetricsSlice: ms,
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}
+	return collected, nil
+}
+
+func parseJSONPathMappings(mappings []influxdb.JSONPathMapping) (map[string]jsonPath, error) {
+	paths := make(map[string]jsonPath, len(mappings))
+	for _, m := range mappings {
+		p, err := parseJSONPath(m.Path)
+		if err != nil {
+			return nil, err
+		}
+		paths[m.Name] = p
+	}
+	return paths, nil
+}
+
+func first(values []interface{}) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// jsonFieldValue returns the value of the field of the json value v, numbers
+// are floats. Objects, arrays and nulls are not fields.
+func jsonFieldValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+		return v, true
+	case bool, string:
+		return v, true
+	}
+	return nil, false
+}
+
+// jsonTagValue returns the value of the tag of the json value v.
+func jsonTagValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// jsonTimeValue returns the time of the json value v, either a RFC3339 string
+// or a number of seconds since the epoch.
+func jsonTimeValue(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	case float64:
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+	return time.Time{}, false
+}
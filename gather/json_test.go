@@ -0,0 +1,153 @@
+package gather
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestJSONScraper(t *testing.T) {
+	now := time.Unix(100, 0)
+	doc := `{
+	"servers": [
+		{"host": "a", "cpu": {"user": 1.5}, "up": true, "time": "2019-01-01T00:00:00Z"},
+		{"host": "b", "cpu": {"user": 2}, "up": false, "time": 1546300800},
+		{"host": "c", "cpu": {}}
+	]
+}`
+	cases := []struct {
+		name   string
+		doc    string
+		cfg    *influxdb.JSONScraperConfig
+		ms     []Metrics
+		hasErr bool
+	}{
+		{
+			name: "root objects",
+			doc:  doc,
+			cfg: &influxdb.JSONScraperConfig{
+				Measurement: "servers",
+				Root:        "$.servers[*]",
+				Fields: []influxdb.JSONPathMapping{
+					{Name: "user", Path: "$.cpu.user"},
+					{Name: "up", Path: "$['up']"},
+				},
+				Tags:     []influxdb.JSONPathMapping{{Name: "host", Path: "$.host"}},
+				TimePath: "$.time",
+			},
+			ms: []Metrics{
+				{
+					Name:      "servers",
+					Tags:      map[string]string{"host": "a"},
+					Fields:    map[string]interface{}{"user": 1.5, "up": true},
+					Timestamp: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+					Type:      MetricTypeUntyped,
+				},
+				{
+					Name:      "servers",
+					Tags:      map[string]string{"host": "b"},
+					Fields:    map[string]interface{}{"user": float64(2), "up": false},
+					Timestamp: time.Unix(1546300800, 0),
+					Type:      MetricTypeUntyped,
+				},
+			},
+		},
+		{
+			name: "whole document",
+			doc:  doc,
+			cfg: &influxdb.JSONScraperConfig{
+				Measurement: "first",
+				Fields:      []influxdb.JSONPathMapping{{Name: "user", Path: "$.servers[0].cpu.user"}},
+			},
+			ms: []Metrics{
+				{
+					Name:      "first",
+					Tags:      map[string]string{},
+					Fields:    map[string]interface{}{"user": 1.5},
+					Timestamp: now,
+					Type:      MetricTypeUntyped,
+				},
+			},
+		},
+		{
+			name: "invalid document",
+			doc:  `{"servers":`,
+			cfg: &influxdb.JSONScraperConfig{
+				Measurement: "m",
+				Fields:      []influxdb.JSONPathMapping{{Name: "f", Path: "$.f"}},
+			},
+			hasErr: true,
+		},
+		{
+			name: "invalid path",
+			doc:  doc,
+			cfg: &influxdb.JSONScraperConfig{
+				Measurement: "m",
+				Fields:      []influxdb.JSONPathMapping{{Name: "f", Path: "servers"}},
+			},
+			hasErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			target := influxdb.ScraperTarget{
+				Type:     influxdb.JSONScraperType,
+				OrgID:    *orgID,
+				BucketID: *bucketID,
+				JSON:     c.cfg,
+			}
+			collected, err := new(jsonScraper).parse(strings.NewReader(c.doc), target, now)
+			if (err != nil) != c.hasErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if c.hasErr {
+				return
+			}
+			if diff := cmp.Diff(MetricsSlice(c.ms), collected.MetricsSlice); diff != "" {
+				t.Errorf("unexpected metrics -want/+got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": []interface{}{float64(1), float64(2), float64(3)},
+		"b": map[string]interface{}{"y": "2", "x": "1"},
+	}
+	cases := []struct {
+		path   string
+		values []interface{}
+		hasErr bool
+	}{
+		{path: "$", values: []interface{}{doc}},
+		{path: "$.a[1]", values: []interface{}{float64(2)}},
+		{path: "$.a[-1]", values: []interface{}{float64(3)}},
+		{path: "$.a[5]"},
+		{path: "$.a[*]", values: []interface{}{float64(1), float64(2), float64(3)}},
+		{path: "$.b.*", values: []interface{}{"1", "2"}},
+		{path: `$["b"]['x']`, values: []interface{}{"1"}},
+		{path: "$.c.d"},
+		{path: "a", hasErr: true},
+		{path: "$.", hasErr: true},
+		{path: "$[0", hasErr: true},
+		{path: "$[x]", hasErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			p, err := parseJSONPath(c.path)
+			if (err != nil) != c.hasErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if c.hasErr {
+				return
+			}
+			if diff := cmp.Diff(c.values, p.eval(doc)); diff != "" {
+				t.Errorf("unexpected values -want/+got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package gather
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath expression. It supports the subset of JSONPath
+// needed to map documents to metrics: member names with dot or bracket
+// notation, array indexes and the * wildcard, as in $.servers[*]['cpu'].
+type jsonPath []jsonPathSegment
+
+// jsonPathSegment selects the member name of objects, the element at index of
+// arrays, or every member or element if wildcard is set.
+type jsonPathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the expression s, which must start with $.
+func parseJSONPath(s string) (jsonPath, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("json path %q must start with $", s)
+	}
+
+	var p jsonPath
+	rest := s[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("json path %q has an empty member name", s)
+			}
+			if name == "*" {
+				p = append(p, jsonPathSegment{wildcard: true})
+			} else {
+				p = append(p, jsonPathSegment{name: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("json path %q has an unterminated bracket", s)
+			}
+			sel := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case sel == "*":
+				p = append(p, jsonPathSegment{wildcard: true})
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				p = append(p, jsonPathSegment{name: sel[1 : len(sel)-1]})
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, fmt.Errorf("json path %q has an invalid index %q", s, sel)
+				}
+				p = append(p, jsonPathSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("json path %q is invalid at %q", s, rest)
+		}
+	}
+	return p, nil
+}
+
+// eval returns the values of v selected by p, in document order for arrays.
+func (p jsonPath) eval(v interface{}) []interface{} {
+	values := []interface{}{v}
+	for _, seg := range p {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, seg.eval(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (seg jsonPathSegment) eval(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			values := make([]interface{}, 0, len(keys))
+			for _, k := range keys {
+				values = append(values, v[k])
+			}
+			return values
+		}
+		if seg.isIndex {
+			return nil
+		}
+		if m, ok := v[seg.name]; ok {
+			return []interface{}{m}
+		}
+	case []interface{}:
+		if seg.wildcard {
+			return v
+		}
+		if seg.isIndex {
+			i := seg.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []interface{}{v[i]}
+			}
+		}
+	}
+	return nil
+}
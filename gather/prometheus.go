@@ -21,11 +21,16 @@ type prometheusScraper struct{}
 
 // Gather parse metrics from a scraper target url.
 func (p *prometheusScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
-	resp, err := http.Get(target.URL)
+	req, err := http.NewRequest("GET", target.URL, nil)
+	if err != nil {
+		return collected, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return collected, err
 	}
 	defer resp.Body.Close()
+	setHTTPStatus(ctx, resp.StatusCode)
 
 	return p.parse(resp.Body, resp.Header, target)
 }
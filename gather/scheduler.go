@@ -15,8 +15,11 @@ import (
 
 // nats subjects
 const (
-	MetricsSubject    = "metrics"
-	promTargetSubject = "promTarget"
+	MetricsSubject        = "metrics"
+	promTargetSubject     = "promTarget"
+	jsonTargetSubject     = "jsonTarget"
+	graphiteTargetSubject = "graphiteTarget"
+	statsdTargetSubject   = "statsdTarget"
 )
 
 // Scheduler is struct to run scrape jobs.
@@ -33,6 +36,9 @@ type Scheduler struct {
 	Logger *zap.Logger
 
 	gather chan struct{}
+	statsd *statsdScraper
+	// next is the time of the next scrape of the targets.
+	next map[influxdb.ID]time.Time
 }
 
 // NewScheduler creates a new Scheduler and subscriptions for scraper jobs.
@@ -58,16 +64,26 @@ func NewScheduler(
 		Publisher: p,
 		Logger:    l,
 		gather:    make(chan struct{}, 100),
+		statsd:    newStatsDScraper(l),
+		next:      make(map[influxdb.ID]time.Time),
 	}
 
 	for i := 0; i < numScrapers; i++ {
-		err := s.Subscribe(promTargetSubject, "metrics", &handler{
-			Scraper:   new(prometheusScraper),
-			Publisher: p,
-			Logger:    l,
-		})
-		if err != nil {
-			return nil, err
+		scrapers := map[string]Scraper{
+			promTargetSubject:     new(prometheusScraper),
+			jsonTargetSubject:     new(jsonScraper),
+			graphiteTargetSubject: new(graphiteScraper),
+			statsdTargetSubject:   scheduler.statsd,
+		}
+		for subject, scraper := range scrapers {
+			err := s.Subscribe(subject, "metrics", &handler{
+				Scraper:   scraper,
+				Publisher: p,
+				Logger:    l,
+			})
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -78,11 +94,18 @@ func NewScheduler(
 // and publish them to nats job queue for gather.
 func (s *Scheduler) Run(ctx context.Context) error {
 	go func(s *Scheduler, ctx context.Context) {
+		// Targets can be scraped more often than the interval of the scheduler.
+		tick := s.Interval
+		if tick > influxdb.MinScraperTargetInterval {
+			tick = influxdb.MinScraperTargetInterval
+		}
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-time.After(s.Interval): // TODO: change to ticker because of garbage collection
+			case <-ticker.C:
 				s.gather <- struct{}{}
 			}
 		}
@@ -91,6 +114,7 @@ func (s *Scheduler) Run(ctx context.Context) error {
 }
 
 func (s *Scheduler) run(ctx context.Context) error {
+	defer s.statsd.close()
 	for {
 		select {
 		case <-ctx.Done():
@@ -113,7 +137,15 @@ func (s *Scheduler) doGather(ctx context.Context) {
 		tracing.LogError(span, err)
 		return
 	}
+	// Stop listening for the metrics of the statsd targets that were deleted.
+	s.statsd.retain(targets)
+	s.retain(targets)
+
+	now := time.Now()
 	for _, target := range targets {
+		if !s.due(target, now) {
+			continue
+		}
 		if err := requestScrape(target, s.Publisher); err != nil {
 			s.Logger.Error("json encoding error", zap.Error(err))
 			tracing.LogError(span, err)
@@ -121,6 +153,39 @@ func (s *Scheduler) doGather(ctx context.Context) {
 	}
 }
 
+// due returns whether target must be scraped at now, and schedules its next
+// scrape if so.
+func (s *Scheduler) due(target influxdb.ScraperTarget, now time.Time) bool {
+	interval := s.Interval
+	if target.Interval != nil {
+		interval = target.Interval.Duration
+	}
+
+	next, ok := s.next[target.ID]
+	if ok && now.Before(next) {
+		return false
+	}
+	// Keep the scrapes of a target evenly spaced, unless they fell behind.
+	if !ok || now.Sub(next) >= interval {
+		next = now
+	}
+	s.next[target.ID] = next.Add(interval)
+	return true
+}
+
+// retain forgets the schedule of the targets that are not in targets.
+func (s *Scheduler) retain(targets []influxdb.ScraperTarget) {
+	ids := make(map[influxdb.ID]bool, len(targets))
+	for _, t := range targets {
+		ids[t.ID] = true
+	}
+	for id := range s.next {
+		if !ids[id] {
+			delete(s.next, id)
+		}
+	}
+}
+
 func requestScrape(t influxdb.ScraperTarget, publisher nats.Publisher) error {
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(t)
@@ -130,6 +195,12 @@ func requestScrape(t influxdb.ScraperTarget, publisher nats.Publisher) error {
 	switch t.Type {
 	case influxdb.PrometheusScraperType:
 		return publisher.Publish(promTargetSubject, buf)
+	case influxdb.JSONScraperType:
+		return publisher.Publish(jsonTargetSubject, buf)
+	case influxdb.GraphiteScraperType:
+		return publisher.Publish(graphiteTargetSubject, buf)
+	case influxdb.StatsDScraperType:
+		return publisher.Publish(statsdTargetSubject, buf)
 	}
 	return fmt.Errorf("unsupported target scrape type: %s", t.Type)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +47,14 @@ func TestScheduler(t *testing.T) {
 		Logger:   logger,
 		Recorder: storage,
 	})
+	status := &mockStatus{
+		Statuses: make(chan influxdb.ScraperTargetStatus, totalGatherJobs),
+	}
+	subscriber.Subscribe(StatusSubject, "", &StatusHandler{
+		Logger:   logger,
+		Status:   status,
+		Recorder: status,
+	})
 
 	scheduler, err := NewScheduler(10, logger,
 		storage, publisher, subscriber, time.Millisecond, time.Microsecond)
@@ -89,9 +98,76 @@ func TestScheduler(t *testing.T) {
 			t.Fatalf("scraper parse metrics want %v, got %v", want, v)
 		}
 	}
+
+	for i := 0; i < totalGatherJobs; i++ {
+		st := <-status.Statuses
+		if st.TargetID != storage.Targets[0].ID || st.Status != influxdb.ScraperTargetUp || st.HTTPStatus != 200 || st.Samples != 1 {
+			t.Fatalf("unexpected scraper target status %+v", st)
+		}
+	}
+	status.Lock()
+	defer status.Unlock()
+	for _, c := range status.Collected {
+		if c.BucketID != influxdb.MonitoringSystemBucketID || c.MetricsSlice[0].Fields["up"] != int64(1) {
+			t.Fatalf("unexpected scraper target metrics %+v", c)
+		}
+	}
 	ts.Close()
 }
 
+func TestScheduler_Interval(t *testing.T) {
+	s := &Scheduler{
+		Interval: 10 * time.Second,
+		next:     make(map[influxdb.ID]time.Time),
+	}
+	fast := influxdb.ScraperTarget{ID: 1, Interval: &influxdb.Duration{Duration: 2 * time.Second}}
+	slow := influxdb.ScraperTarget{ID: 2}
+
+	start := time.Unix(0, 0)
+	var scrapes []influxdb.ID
+	for sec := 0; sec < 12; sec++ {
+		now := start.Add(time.Duration(sec) * time.Second)
+		for _, target := range []influxdb.ScraperTarget{fast, slow} {
+			if s.due(target, now) {
+				scrapes = append(scrapes, target.ID)
+			}
+		}
+	}
+	want := []influxdb.ID{1, 2, 1, 1, 1, 1, 1, 2}
+	if diff := cmp.Diff(want, scrapes); diff != "" {
+		t.Errorf("unexpected scrapes -want/+got\n%s", diff)
+	}
+
+	s.retain([]influxdb.ScraperTarget{slow})
+	if _, ok := s.next[fast.ID]; ok {
+		t.Errorf("schedule of removed target was kept")
+	}
+}
+
+// mockStatus implements influxdb.ScraperTargetStatusService and the
+// recorder interface for the status of the targets.
+type mockStatus struct {
+	sync.Mutex
+	Statuses  chan influxdb.ScraperTargetStatus
+	Collected []MetricsCollection
+}
+
+func (s *mockStatus) GetTargetStatus(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	return nil, nil
+}
+
+func (s *mockStatus) PutTargetStatus(ctx context.Context, status *influxdb.ScraperTargetStatus) error {
+	s.Statuses <- *status
+	return nil
+}
+
+func (s *mockStatus) Record(collected MetricsCollection) error {
+	s.Lock()
+	defer s.Unlock()
+	s.Collected = append(s.Collected, collected)
+	return nil
+}
+
 const sampleRespSmall = `
 # HELP go_goroutines Number of goroutines that currently exist.
 # TYPE go_goroutines gauge
@@ -10,3 +10,19 @@ import (
 type Scraper interface {
 	Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error)
 }
+
+type httpStatusKey struct{}
+
+// withHTTPStatus returns a context in which the scrapers of http targets set
+// status to the status code of the response of the target.
+func withHTTPStatus(ctx context.Context, status *int) context.Context {
+	return context.WithValue(ctx, httpStatusKey{}, status)
+}
+
+// setHTTPStatus sets the status code of the response of the target scraped
+// with ctx.
+func setHTTPStatus(ctx context.Context, code int) {
+	if status, ok := ctx.Value(httpStatusKey{}).(*int); ok {
+		*status = code
+	}
+}
@@ -0,0 +1,375 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStatsDPercentile = 90
+
+	// statsdPacketSize is the largest packet read from statsd clients.
+	statsdPacketSize = 64 * 1024
+)
+
+// statsdScraper listens for the metrics pushed by statsd clients to the udp
+// address of the targets and aggregates them between two gathers. A single
+// statsdScraper is shared by the handlers of the scheduler since it holds the
+// metrics received.
+// implements Scraper interfaces.
+type statsdScraper struct {
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	listeners map[influxdb.ID]*statsdListener
+}
+
+func newStatsDScraper(logger *zap.Logger) *statsdScraper {
+	return &statsdScraper{
+		logger:    logger,
+		listeners: make(map[influxdb.ID]*statsdListener),
+	}
+}
+
+// Gather returns the metrics aggregated since the previous gather of the
+// target. The first gather of a target starts listening to its address, so
+// it gathers no metrics.
+func (s *statsdScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	l, err := s.listener(target)
+	if err != nil {
+		return collected, err
+	}
+
+	var percentiles []float64
+	if target.StatsD != nil {
+		percentiles = target.StatsD.Percentiles
+	}
+	if len(percentiles) == 0 {
+		percentiles = []float64{defaultStatsDPercentile}
+	}
+
+	collected = MetricsCollection{
+		MetricsSlice: l.flush(time.Now(), percentiles),
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}
+	return collected, nil
+}
+
+// listener returns the listener of target, starting it if the target is not
+// listened to yet or if its address changed.
+func (s *statsdScraper) listener(target influxdb.ScraperTarget) (*statsdListener, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.listeners[target.ID]; ok {
+		if l.url == target.URL {
+			return l, nil
+		}
+		l.close()
+		delete(s.listeners, target.ID)
+	}
+
+	u, err := url.Parse(target.URL)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenPacket("udp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &statsdListener{
+		url:     target.URL,
+		conn:    conn,
+		logger:  s.logger.With(zap.String("target", target.ID.String())),
+		metrics: make(map[string]*statsdMetric),
+	}
+	go l.listen()
+	s.listeners[target.ID] = l
+	return l, nil
+}
+
+// retain stops listening for the targets that are not in targets.
+func (s *statsdScraper) retain(targets []influxdb.ScraperTarget) {
+	ids := make(map[influxdb.ID]bool, len(targets))
+	for _, t := range targets {
+		if t.Type == influxdb.StatsDScraperType {
+			ids[t.ID] = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, l := range s.listeners {
+		if !ids[id] {
+			l.close()
+			delete(s.listeners, id)
+		}
+	}
+}
+
+// close stops listening for all targets.
+func (s *statsdScraper) close() {
+	s.retain(nil)
+}
+
+// statsdListener aggregates the metrics received on a udp socket.
+type statsdListener struct {
+	url    string
+	conn   net.PacketConn
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	metrics map[string]*statsdMetric
+}
+
+// statsdMetric is the aggregate of a metric since the last flush, gauges keep
+// their value across flushes.
+type statsdMetric struct {
+	name string
+	tags map[string]string
+	typ  string
+
+	value   float64
+	count   float64
+	timings []float64
+	set     map[string]bool
+}
+
+func (l *statsdListener) listen() {
+	buf := make([]byte, statsdPacketSize)
+	for {
+		n, _, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if err := l.add(line); err != nil {
+				l.logger.Debug("Invalid statsd metric", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (l *statsdListener) close() {
+	l.conn.Close()
+}
+
+// add adds a metric of the form name:value|type[|@rate][|#tag:value,...] to
+// the aggregates. Tags can also follow the name as in name,tag=value.
+func (l *statsdListener) add(line string) error {
+	colon := strings.LastIndex(line, ":")
+	if colon <= 0 {
+		return fmt.Errorf("invalid statsd metric %q", line)
+	}
+	// The value is after the last colon that is not part of a dogstatsd tag.
+	if i := strings.Index(line, "|"); i != -1 {
+		colon = strings.LastIndex(line[:i], ":")
+		if colon <= 0 {
+			return fmt.Errorf("invalid statsd metric %q", line)
+		}
+	}
+
+	name, tags := parseStatsDName(line[:colon])
+	parts := strings.Split(line[colon+1:], "|")
+	if len(parts) < 2 || name == "" {
+		return fmt.Errorf("invalid statsd metric %q", line)
+	}
+	raw, typ := parts[0], parts[1]
+
+	rate := 1.0
+	for _, p := range parts[2:] {
+		switch {
+		case strings.HasPrefix(p, "@"):
+			r, err := strconv.ParseFloat(p[1:], 64)
+			if err != nil || r <= 0 || r > 1 {
+				return fmt.Errorf("invalid sample rate of statsd metric %q", line)
+			}
+			rate = r
+		case strings.HasPrefix(p, "#"):
+			for _, tag := range strings.Split(p[1:], ",") {
+				kv := strings.SplitN(tag, ":", 2)
+				if len(kv) == 2 && kv[0] != "" && kv[1] != "" {
+					tags[kv[0]] = kv[1]
+				}
+			}
+		}
+	}
+
+	switch typ {
+	case "c", "g", "ms", "h", "d", "s":
+	default:
+		return fmt.Errorf("invalid type of statsd metric %q", line)
+	}
+	if typ == "h" || typ == "d" {
+		typ = "ms"
+	}
+
+	var value float64
+	if typ != "s" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid value of statsd metric %q", line)
+		}
+		value = v
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := statsdKey(typ, name, tags)
+	m, ok := l.metrics[key]
+	if !ok {
+		m = &statsdMetric{name: name, tags: tags, typ: typ}
+		l.metrics[key] = m
+	}
+
+	switch typ {
+	case "c":
+		m.value += value / rate
+		m.count++
+	case "g":
+		// Gauges prefixed with a sign are relative to the previous value.
+		if strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-") {
+			m.value += value
+		} else {
+			m.value = value
+		}
+		m.count++
+	case "ms":
+		m.timings = append(m.timings, value)
+		m.count += 1 / rate
+	case "s":
+		if m.set == nil {
+			m.set = make(map[string]bool)
+		}
+		m.set[raw] = true
+		m.count++
+	}
+	return nil
+}
+
+// flush returns the metrics aggregated since the previous flush and resets
+// the aggregates, except for the value of gauges.
+func (l *statsdListener) flush(now time.Time, percentiles []float64) []Metrics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keys := make([]string, 0, len(l.metrics))
+	for k := range l.metrics {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ms := make([]Metrics, 0, len(keys))
+	for _, k := range keys {
+		m := l.metrics[k]
+		me := Metrics{
+			Name:      m.name,
+			Tags:      m.tags,
+			Fields:    make(map[string]interface{}),
+			Timestamp: now,
+		}
+
+		switch m.typ {
+		case "c":
+			me.Type = MetricTypeCounter
+			me.Fields["value"] = m.value
+			delete(l.metrics, k)
+		case "g":
+			me.Type = MetricTypeGauge
+			me.Fields["value"] = m.value
+		case "ms":
+			me.Type = MetricTypeSummary
+			timingFields(me.Fields, m.timings, m.count, percentiles)
+			delete(l.metrics, k)
+		case "s":
+			me.Type = MetricTypeGauge
+			me.Fields["value"] = float64(len(m.set))
+			delete(l.metrics, k)
+		}
+		ms = append(ms, me)
+	}
+	return ms
+}
+
+// timingFields sets the statistics of the timings ts to fields, count is the
+// number of timings accounting for their sample rates.
+func timingFields(fields map[string]interface{}, ts []float64, count float64, percentiles []float64) {
+	sort.Float64s(ts)
+
+	var sum float64
+	for _, t := range ts {
+		sum += t
+	}
+	mean := sum / float64(len(ts))
+	var variance float64
+	for _, t := range ts {
+		variance += (t - mean) * (t - mean)
+	}
+
+	fields["count"] = count
+	fields["sum"] = sum
+	fields["mean"] = mean
+	fields["lower"] = ts[0]
+	fields["upper"] = ts[len(ts)-1]
+	fields["stddev"] = math.Sqrt(variance / float64(len(ts)))
+	for _, p := range percentiles {
+		i := int(math.Ceil(p/100*float64(len(ts)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		fields[strconv.FormatFloat(p, 'f', -1, 64)+"_percentile"] = ts[i]
+	}
+}
+
+// parseStatsDName returns the name and the tags of name,tag=value,... names.
+func parseStatsDName(s string) (string, map[string]string) {
+	parts := strings.Split(s, ",")
+	tags := make(map[string]string)
+	for _, tag := range parts[1:] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 2 && kv[0] != "" && kv[1] != "" {
+			tags[kv[0]] = kv[1]
+		}
+	}
+	return parts[0], tags
+}
+
+// statsdKey returns the key of the aggregate of the metric of type typ, name
+// and tags.
+func statsdKey(typ, name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(typ)
+	b.WriteByte('|')
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteByte(',')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tags[k])
+	}
+	return b.String()
+}
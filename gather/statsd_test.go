@@ -0,0 +1,121 @@
+package gather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestStatsDListener(t *testing.T) {
+	now := time.Unix(100, 0)
+	l := &statsdListener{
+		logger:  zap.NewNop(),
+		metrics: make(map[string]*statsdMetric),
+	}
+
+	lines := []string{
+		"hits:1|c",
+		"hits:2|c|@0.5",
+		"hits,host=a:1|c",
+		"temp:20|g",
+		"temp:-5|g",
+		"latency:10|ms",
+		"latency:30|ms",
+		"latency:20|h|#host:b",
+		"users:alice|s",
+		"users:bob|s",
+		"users:alice|s",
+	}
+	for _, line := range lines {
+		if err := l.add(line); err != nil {
+			t.Fatalf("adding %q failed: %v", line, err)
+		}
+	}
+	for _, line := range []string{"hits", "hits:1", "hits:x|c", "hits:1|z", "hits:1|c|@2", ":1|c"} {
+		if err := l.add(line); err == nil {
+			t.Errorf("adding %q did not fail", line)
+		}
+	}
+
+	want := MetricsSlice{
+		{
+			Name:      "hits",
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"value": float64(5)},
+			Timestamp: now,
+			Type:      MetricTypeCounter,
+		},
+		{
+			Name:      "hits",
+			Tags:      map[string]string{"host": "a"},
+			Fields:    map[string]interface{}{"value": float64(1)},
+			Timestamp: now,
+			Type:      MetricTypeCounter,
+		},
+		{
+			Name:      "temp",
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"value": float64(15)},
+			Timestamp: now,
+			Type:      MetricTypeGauge,
+		},
+		{
+			Name: "latency",
+			Tags: map[string]string{},
+			Fields: map[string]interface{}{
+				"count":         float64(2),
+				"sum":           float64(40),
+				"mean":          float64(20),
+				"lower":         float64(10),
+				"upper":         float64(30),
+				"stddev":        float64(10),
+				"50_percentile": float64(10),
+				"90_percentile": float64(30),
+			},
+			Timestamp: now,
+			Type:      MetricTypeSummary,
+		},
+		{
+			Name: "latency",
+			Tags: map[string]string{"host": "b"},
+			Fields: map[string]interface{}{
+				"count":         float64(1),
+				"sum":           float64(20),
+				"mean":          float64(20),
+				"lower":         float64(20),
+				"upper":         float64(20),
+				"stddev":        float64(0),
+				"50_percentile": float64(20),
+				"90_percentile": float64(20),
+			},
+			Timestamp: now,
+			Type:      MetricTypeSummary,
+		},
+		{
+			Name:      "users",
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"value": float64(2)},
+			Timestamp: now,
+			Type:      MetricTypeGauge,
+		},
+	}
+	if diff := cmp.Diff(want, MetricsSlice(l.flush(now, []float64{50, 90}))); diff != "" {
+		t.Errorf("unexpected metrics -want/+got\n%s", diff)
+	}
+
+	// Only the gauges are kept after a flush.
+	want = MetricsSlice{
+		{
+			Name:      "temp",
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"value": float64(15)},
+			Timestamp: now,
+			Type:      MetricTypeGauge,
+		},
+	}
+	if diff := cmp.Diff(want, MetricsSlice(l.flush(now, nil))); diff != "" {
+		t.Errorf("unexpected metrics after flush -want/+got\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/nats"
+	"go.uber.org/zap"
+)
+
+// StatusSubject is the nats subject of the outcome of the scrapes.
+const StatusSubject = "scrapeStatus"
+
+// targetStatus is the outcome of a scrape with the target it belongs to.
+type targetStatus struct {
+	OrgID  influxdb.ID                  `json:"orgID"`
+	Name   string                       `json:"name"`
+	Type   influxdb.ScraperType         `json:"type"`
+	Status influxdb.ScraperTargetStatus `json:"status"`
+}
+
+// metrics returns the scraper_target metric of the outcome of the scrape,
+// written to the monitoring system bucket so that checks can alert on the
+// targets that are down.
+func (s targetStatus) metrics() Metrics {
+	var up int64
+	if s.Status.Status == influxdb.ScraperTargetUp {
+		up = 1
+	}
+	fields := map[string]interface{}{
+		"up":               up,
+		"duration_seconds": s.Status.Duration.Seconds(),
+		"samples":          int64(s.Status.Samples),
+	}
+	if s.Status.HTTPStatus != 0 {
+		fields["http_status"] = int64(s.Status.HTTPStatus)
+	}
+	if s.Status.Error != "" {
+		fields["error"] = s.Status.Error
+	}
+	return Metrics{
+		Name: "scraper_target",
+		Tags: map[string]string{
+			"target_id": s.Status.TargetID.String(),
+			"name":      s.Name,
+			"type":      string(s.Type),
+		},
+		Fields:    fields,
+		Timestamp: s.Status.LastScrape,
+		Type:      MetricTypeGauge,
+	}
+}
+
+// StatusHandler records the outcome of the scrapes published to
+// StatusSubject. It implements nats Handler interface.
+type StatusHandler struct {
+	Status   influxdb.ScraperTargetStatusService
+	Recorder Recorder
+	Logger   *zap.Logger
+}
+
+// Process stores the status of the target and records its scraper_target
+// metric.
+func (h *StatusHandler) Process(s nats.Subscription, m nats.Message) {
+	defer m.Ack()
+
+	status := new(targetStatus)
+	if err := json.Unmarshal(m.Data(), status); err != nil {
+		h.Logger.Error("unable to unmarshal json", zap.Error(err))
+		return
+	}
+
+	if err := h.Status.PutTargetStatus(context.TODO(), &status.Status); err != nil {
+		h.Logger.Error("unable to store scraper target status", zap.Error(err))
+		return
+	}
+
+	err := h.Recorder.Record(MetricsCollection{
+		OrgID:        status.OrgID,
+		BucketID:     influxdb.MonitoringSystemBucketID,
+		MetricsSlice: MetricsSlice{status.metrics()},
+	})
+	if err != nil {
+		h.Logger.Error("unable to record scraper target status", zap.Error(err))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/http/metric"
 	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/influxdata/influxdb/pkger"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,8 +23,13 @@ type APIHandler struct {
 	UserHandler                 *UserHandler
 	OrgHandler                  *OrgHandler
 	AuthorizationHandler        *AuthorizationHandler
+	BackupHandler               *BackupHandler
 	DashboardHandler            *DashboardHandler
+	DBRPMappingHandler          *DBRPMappingHandler
+	DeleteHandler               *DeleteHandler
 	LabelHandler                *LabelHandler
+	LegacyHandler               *LegacyHandler
+	PkgerHandler                *PkgerHandler
 	AssetHandler                *AssetHandler
 	ChronografHandler           *ChronografHandler
 	ScraperHandler              *ScraperHandler
@@ -32,7 +38,10 @@ type APIHandler struct {
 	TaskHandler                 *TaskHandler
 	CheckHandler                *CheckHandler
 	TelegrafHandler             *TelegrafHandler
+	UsageHandler                *UsageHandler
 	QueryHandler                *FluxHandler
+	RunningQueryHandler         *RunningQueryHandler
+	RestoreHandler              *RestoreHandler
 	WriteHandler                *WriteHandler
 	DocumentHandler             *DocumentHandler
 	SetupHandler                *SetupHandler
@@ -40,6 +49,7 @@ type APIHandler struct {
 	SwaggerHandler              http.Handler
 	NotificationRuleHandler     *NotificationRuleHandler
 	NotificationEndpointHandler *NotificationEndpointHandler
+	RoleHandler                 *RoleHandler
 }
 
 // APIBackend is all services and associated parameters required to construct
@@ -57,9 +67,19 @@ type APIBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	WriteLimiter                    WriteLimiter
+	DeleteService                   influxdb.DeleteService
+	BackupService                   influxdb.BackupService
+	KVBackupService                 influxdb.KVBackupService
+	RestoreService                  influxdb.RestoreService
 	AuthorizationService            influxdb.AuthorizationService
+	AuthorizationUsageRecorder      influxdb.AuthorizationUsageRecorder
 	BucketService                   influxdb.BucketService
+	DBRPMappingService              influxdb.DBRPMappingService
+	BucketSchemaService             influxdb.BucketSchemaService
+	MeasurementSchemaReader         influxdb.MeasurementSchemaReader
 	SessionService                  influxdb.SessionService
+	SSOService                      influxdb.SSOService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
 	UserResourceMappingService      influxdb.UserResourceMappingService
@@ -75,17 +95,23 @@ type APIBackend struct {
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
+	RunningQueryService             query.RunningQueryService
+	ExplainService                  query.ExplainService
 	TaskService                     influxdb.TaskService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
+	ScraperTargetStatusService      influxdb.ScraperTargetStatusService
 	SecretService                   influxdb.SecretService
+	RoleService                     influxdb.RoleService
 	LookupService                   influxdb.LookupService
 	ChronografService               *server.Service
 	OrgLookupService                authorizer.OrganizationService
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	UsageService                    influxdb.UsageService
+	AuditLogger                     influxdb.AuditLogger
 }
 
 // PrometheusCollectors exposes the prometheus collectors associated with an APIBackend.
@@ -110,6 +136,7 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	}
 
 	internalURM := b.UserResourceMappingService
+	roleResolver := newRoleResolver(b)
 	b.UserResourceMappingService = authorizer.NewURMService(b.OrgLookupService, b.UserResourceMappingService)
 
 	documentBackend := NewDocumentBackend(b)
@@ -120,6 +147,8 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	bucketBackend := NewBucketBackend(b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.BucketSchemaService = authorizer.NewBucketSchemaService(b.BucketSchemaService)
+	bucketBackend.MeasurementSchemaReader = authorizer.NewMeasurementSchemaReader(b.MeasurementSchemaReader)
 	h.BucketHandler = NewBucketHandler(bucketBackend)
 
 	orgBackend := NewOrgBackend(b)
@@ -174,6 +203,10 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 		b.UserResourceMappingService, b.OrganizationService)
 	h.NotificationEndpointHandler = NewNotificationEndpointHandler(notificationEndpointBackend)
 
+	roleBackend := NewRoleBackend(b)
+	roleBackend.RoleService = authorizer.NewRoleService(b.RoleService)
+	h.RoleHandler = NewRoleHandler(roleBackend)
+
 	checkBackend := NewCheckBackend(b)
 	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService,
 		b.UserResourceMappingService, b.OrganizationService)
@@ -182,13 +215,55 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	writeBackend := NewWriteBackend(b)
 	h.WriteHandler = NewWriteHandler(writeBackend)
 
+	deleteBackend := NewDeleteBackend(b)
+	deleteBackend.DeleteService = authorizer.NewDeleteService(b.DeleteService)
+	h.DeleteHandler = NewDeleteHandler(deleteBackend)
+
+	backupBackend := NewBackupBackend(b)
+	backupBackend.BackupService = authorizer.NewBackupService(b.BackupService)
+	backupBackend.KVBackupService = authorizer.NewKVBackupService(b.KVBackupService)
+	h.BackupHandler = NewBackupHandler(backupBackend)
+
+	restoreBackend := NewRestoreBackend(b)
+	restoreBackend.RestoreService = authorizer.NewRestoreService(b.RestoreService)
+	h.RestoreHandler = NewRestoreHandler(restoreBackend)
+
+	dbrpMappingBackend := NewDBRPMappingBackend(b)
+	dbrpMappingBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	h.DBRPMappingHandler = NewDBRPMappingHandler(dbrpMappingBackend)
+
+	pkgerBackend := NewPkgerBackend(b)
+	pkgerBackend.PkgerService = &pkger.Service{
+		Logger:                      b.Logger.With(zap.String("service", "pkger")),
+		BucketService:               bucketBackend.BucketService,
+		LabelService:                authorizer.NewLabelService(b.LabelService),
+		DashboardService:            dashboardBackend.DashboardService,
+		VariableService:             variableBackend.VariableService,
+		CheckService:                checkBackend.CheckService,
+		NotificationRuleStore:       notificationRuleBackend.NotificationRuleStore,
+		NotificationEndpointService: notificationEndpointBackend.NotificationEndpointService,
+		TelegrafService:             telegrafBackend.TelegrafService,
+	}
+	h.PkgerHandler = NewPkgerHandler(pkgerBackend)
+
+	legacyBackend := NewLegacyBackend(b, roleResolver)
+	h.LegacyHandler = NewLegacyHandler(legacyBackend)
+
 	fluxBackend := NewFluxBackend(b)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	runningQueryBackend := NewRunningQueryBackend(b)
+	runningQueryBackend.RunningQueryService = authorizer.NewRunningQueryService(b.RunningQueryService)
+	h.RunningQueryHandler = NewRunningQueryHandler(runningQueryBackend)
+
 	h.ChronografHandler = NewChronografHandler(b.ChronografService, b.HTTPErrorHandler)
 	h.SwaggerHandler = newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler)
 	h.LabelHandler = NewLabelHandler(authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler)
 
+	h.UsageHandler = NewUsageHandler(b.HTTPErrorHandler)
+	h.UsageHandler.Logger = b.Logger.With(zap.String("handler", "usage"))
+	h.UsageHandler.UsageService = authorizer.NewUsageService(b.UsageService)
+
 	return h
 }
 
@@ -196,8 +271,11 @@ var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
 	"authorizations": "/api/v2/authorizations",
+	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"dbrps":          "/api/v2/dbrps",
+	"delete":         "/api/v2/delete",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -207,12 +285,17 @@ var apiLinks = map[string]interface{}{
 	"notificationRules":     "/api/v2/notificationRules",
 	"notificationEndpoints": "/api/v2/notificationEndpoints",
 	"orgs":                  "/api/v2/orgs",
+	"packages":              "/api/v2/packages",
+	"queries":               "/api/v2/queries",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
 		"analyze":     "/api/v2/query/analyze",
+		"explain":     "/api/v2/query/explain",
 		"suggestions": "/api/v2/query/suggestions",
 	},
+	"restore":  "/api/v2/restore",
+	"roles":    "/api/v2/roles",
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
@@ -227,6 +310,7 @@ var apiLinks = map[string]interface{}{
 	"tasks":     "/api/v2/tasks",
 	"checks":    "/api/v2/checks",
 	"telegrafs": "/api/v2/telegrafs",
+	"usage":     "/api/v2/usage",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 }
@@ -251,7 +335,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" {
+	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" || strings.HasPrefix(r.URL.Path, ssoSigninPath) {
 		h.SessionHandler.ServeHTTP(w, r)
 		return
 	}
@@ -266,6 +350,26 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/delete") {
+		h.DeleteHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/backup") {
+		h.BackupHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/restore") {
+		h.RestoreHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/queries") {
+		h.RunningQueryHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
@@ -276,6 +380,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dbrps") {
+		h.DBRPMappingHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/labels") {
 		h.LabelHandler.ServeHTTP(w, r)
 		return
@@ -296,6 +405,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/roles") {
+		h.RoleHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/authorizations") {
 		h.AuthorizationHandler.ServeHTTP(w, r)
 		return
@@ -346,11 +460,26 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/packages") {
+		h.PkgerHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/usage") {
+		h.UsageHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/documents") {
 		h.DocumentHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if isLegacyPath(r.URL.Path) {
+		h.LegacyHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.ChronografHandler.ServeHTTP(w, r)
 		return
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -75,29 +76,37 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 }
 
 type authResponse struct {
-	ID          platform.ID          `json:"id"`
-	Token       string               `json:"token"`
-	Status      platform.Status      `json:"status"`
-	Description string               `json:"description"`
-	OrgID       platform.ID          `json:"orgID"`
-	Org         string               `json:"org"`
-	UserID      platform.ID          `json:"userID"`
-	User        string               `json:"user"`
-	Permissions []permissionResponse `json:"permissions"`
-	Links       map[string]string    `json:"links"`
+	ID           platform.ID          `json:"id"`
+	Token        string               `json:"token,omitempty"`
+	Status       platform.Status      `json:"status"`
+	Description  string               `json:"description"`
+	OrgID        platform.ID          `json:"orgID"`
+	Org          string               `json:"org"`
+	UserID       platform.ID          `json:"userID"`
+	User         string               `json:"user"`
+	Permissions  []permissionResponse `json:"permissions"`
+	RoleIDs      []platform.ID        `json:"roleIDs,omitempty"`
+	ExpiresAt    *time.Time           `json:"expiresAt,omitempty"`
+	LastUsedAt   *time.Time           `json:"lastUsedAt,omitempty"`
+	LastUsedFrom string               `json:"lastUsedFrom,omitempty"`
+	Links        map[string]string    `json:"links"`
 }
 
 func newAuthResponse(a *platform.Authorization, org *platform.Organization, user *platform.User, ps []permissionResponse) *authResponse {
 	res := &authResponse{
-		ID:          a.ID,
-		Token:       a.Token,
-		Status:      a.Status,
-		Description: a.Description,
-		OrgID:       a.OrgID,
-		UserID:      a.UserID,
-		User:        user.Name,
-		Org:         org.Name,
-		Permissions: ps,
+		ID:           a.ID,
+		Token:        a.Token,
+		Status:       a.Status,
+		Description:  a.Description,
+		OrgID:        a.OrgID,
+		UserID:       a.UserID,
+		User:         user.Name,
+		Org:          org.Name,
+		Permissions:  ps,
+		RoleIDs:      a.RoleIDs,
+		ExpiresAt:    a.ExpiresAt,
+		LastUsedAt:   a.LastUsedAt,
+		LastUsedFrom: a.LastUsedFrom,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
@@ -108,12 +117,16 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 
 func (a *authResponse) toPlatform() *platform.Authorization {
 	res := &platform.Authorization{
-		ID:          a.ID,
-		Token:       a.Token,
-		Status:      a.Status,
-		Description: a.Description,
-		OrgID:       a.OrgID,
-		UserID:      a.UserID,
+		ID:           a.ID,
+		Token:        a.Token,
+		Status:       a.Status,
+		Description:  a.Description,
+		OrgID:        a.OrgID,
+		UserID:       a.UserID,
+		RoleIDs:      a.RoleIDs,
+		ExpiresAt:    a.ExpiresAt,
+		LastUsedAt:   a.LastUsedAt,
+		LastUsedFrom: a.LastUsedFrom,
 	}
 	for _, p := range a.Permissions {
 		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
@@ -238,6 +251,8 @@ type postAuthorizationRequest struct {
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	RoleIDs     []platform.ID         `json:"roleIDs,omitempty"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -246,7 +261,9 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Status:      p.Status,
 		Description: p.Description,
 		Permissions: p.Permissions,
+		RoleIDs:     p.RoleIDs,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -255,7 +272,9 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		OrgID:       a.OrgID,
 		Description: a.Description,
 		Permissions: a.Permissions,
+		RoleIDs:     a.RoleIDs,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -274,10 +293,10 @@ func (p *postAuthorizationRequest) SetDefaults() {
 }
 
 func (p *postAuthorizationRequest) Validate() error {
-	if len(p.Permissions) == 0 {
+	if len(p.Permissions) == 0 && len(p.RoleIDs) == 0 {
 		return &platform.Error{
 			Code: platform.EInvalid,
-			Msg:  "authorization must include permissions",
+			Msg:  "authorization must include permissions or roles",
 		}
 	}
 
@@ -297,6 +316,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		}
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "authorization must expire in the future",
+		}
+	}
+
 	if p.Status == "" {
 		p.Status = platform.Active
 	}
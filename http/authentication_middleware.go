@@ -3,10 +3,13 @@ package http
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	"github.com/influxdata/influxdb/authorizer"
 	platcontext "github.com/influxdata/influxdb/context"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -21,6 +24,17 @@ type AuthenticationHandler struct {
 	SessionService       platform.SessionService
 	SessionRenewDisabled bool
 
+	// AuthorizationUsageRecorder records the use of authorizations if set.
+	AuthorizationUsageRecorder platform.AuthorizationUsageRecorder
+
+	// RoleResolver resolves the roles of the authorizer of requests if set.
+	RoleResolver *authorizer.RoleResolver
+
+	// AuditLogger records the requests that fail authentication or
+	// authorization if set, the mutations of the requests are then recorded
+	// by the audit store.
+	AuditLogger platform.AuditLogger
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -67,6 +81,10 @@ func ProbeAuthScheme(r *http.Request) (string, error) {
 
 // ServeHTTP extracts the session or token from the http request and places the resulting authorizer on the request context.
 func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.AuditLogger != nil {
+		r = r.WithContext(audit.WithRequest(r.Context(), clientAddress(r)))
+	}
+
 	if handler, _, _ := h.noAuthRouter.Lookup(r.Method, r.URL.Path); handler != nil {
 		h.Handler.ServeHTTP(w, r)
 		return
@@ -85,22 +103,50 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		if err != nil {
 			break
 		}
-		r = r.WithContext(ctx)
-		h.Handler.ServeHTTP(w, r)
+		h.serveAuthenticated(w, r.WithContext(ctx))
 		return
 	case sessionAuthScheme:
 		ctx, err = h.extractSession(ctx, r)
 		if err != nil {
 			break
 		}
-		r = r.WithContext(ctx)
-		h.Handler.ServeHTTP(w, r)
+		h.serveAuthenticated(w, r.WithContext(ctx))
 		return
 	}
 
+	h.logDenied(ctx, r, err.Error())
 	UnauthorizedError(ctx, h, w)
 }
 
+// serveAuthenticated serves the authenticated request r, recording it if the
+// authorizer of the request is not allowed to make it.
+func (h *AuthenticationHandler) serveAuthenticated(w http.ResponseWriter, r *http.Request) {
+	if h.AuditLogger == nil {
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	sw := newStatusResponseWriter(w)
+	h.Handler.ServeHTTP(sw, r)
+	if code := sw.code(); code == http.StatusUnauthorized || code == http.StatusForbidden {
+		h.logDenied(r.Context(), r, http.StatusText(code))
+	}
+}
+
+// logDenied records that the request r has been denied with the reason msg.
+func (h *AuthenticationHandler) logDenied(ctx context.Context, r *http.Request, msg string) {
+	if h.AuditLogger == nil {
+		return
+	}
+
+	e := audit.NewEvent(ctx, platform.AuditDenied)
+	e.Request = r.Method + " " + r.URL.Path
+	e.Error = msg
+	if err := h.AuditLogger.LogAuditEvents(ctx, e); err != nil {
+		h.Logger.Info("Failed to record denied request", zap.Error(err))
+	}
+}
+
 func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *http.Request) (context.Context, error) {
 	t, err := GetToken(r)
 	if err != nil {
@@ -112,9 +158,48 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return ctx, err
 	}
 
+	h.recordAuthorizationUsage(ctx, a, time.Now(), clientAddress(r))
+
+	return h.setAuthorizer(ctx, a)
+}
+
+// setAuthorizer sets a on ctx along with its roles.
+func (h *AuthenticationHandler) setAuthorizer(ctx context.Context, a platform.Authorizer) (context.Context, error) {
+	if h.RoleResolver != nil {
+		var err error
+		if ctx, err = h.RoleResolver.Resolve(ctx, a); err != nil {
+			return ctx, err
+		}
+	}
 	return platcontext.SetAuthorizer(ctx, a), nil
 }
 
+// recordAuthorizationUsage records the use of a at now from the client address
+// from, unless its last use from the same address has been recorded less than
+// AuthorizationUsageInterval ago.
+func (h *AuthenticationHandler) recordAuthorizationUsage(ctx context.Context, a *platform.Authorization, now time.Time, from string) {
+	if h.AuthorizationUsageRecorder == nil {
+		return
+	}
+
+	if a.LastUsedAt != nil && a.LastUsedFrom == from && now.Sub(*a.LastUsedAt) < platform.AuthorizationUsageInterval {
+		return
+	}
+
+	if err := h.AuthorizationUsageRecorder.RecordAuthorizationUsage(ctx, a.ID, now, from); err != nil {
+		h.Logger.Info("Failed to record authorization usage", zap.Error(err))
+	}
+}
+
+// clientAddress returns the address of the client of r, without its port.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (context.Context, error) {
 	k, err := decodeCookieSession(ctx, r)
 	if err != nil {
@@ -134,5 +219,5 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 		}
 	}
 
-	return platcontext.SetAuthorizer(ctx, s), nil
+	return h.setAuthorizer(ctx, s)
 }
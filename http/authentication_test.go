@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
 	platformhttp "github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/mock"
@@ -86,6 +87,23 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusOK,
 			},
 		},
+		{
+			name: "token expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						return nil, platform.ErrAuthorizationExpired
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
 		{
 			name: "token does not exist",
 			fields: fields{
@@ -147,6 +165,83 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+type authorizationUsageRecorder struct {
+	ids   []platform.ID
+	froms []string
+}
+
+func (r *authorizationUsageRecorder) RecordAuthorizationUsage(ctx context.Context, id platform.ID, at time.Time, from string) error {
+	r.ids = append(r.ids, id)
+	r.froms = append(r.froms, from)
+	return nil
+}
+
+func TestAuthenticationHandler_RecordAuthorizationUsage(t *testing.T) {
+	recent := time.Now().Add(-time.Second)
+	old := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		auth   platform.Authorization
+		record bool
+	}{
+		{
+			name:   "never used",
+			auth:   platform.Authorization{ID: 1},
+			record: true,
+		},
+		{
+			name:   "used recently from the same address",
+			auth:   platform.Authorization{ID: 1, LastUsedAt: &recent, LastUsedFrom: "192.0.2.1"},
+			record: false,
+		},
+		{
+			name:   "used recently from another address",
+			auth:   platform.Authorization{ID: 1, LastUsedAt: &recent, LastUsedFrom: "192.0.2.2"},
+			record: true,
+		},
+		{
+			name:   "used long ago",
+			auth:   platform.Authorization{ID: 1, LastUsedAt: &old, LastUsedFrom: "192.0.2.1"},
+			record: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &authorizationUsageRecorder{}
+
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+					a := tt.auth
+					return &a, nil
+				},
+			}
+			h.AuthorizationUsageRecorder = recorder
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://any.url", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			platformhttp.SetToken("abc123", r)
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, http.StatusOK; got != want {
+				t.Fatalf("expected status code to be %d got %d", want, got)
+			}
+			if got := len(recorder.ids) == 1; got != tt.record {
+				t.Fatalf("expected usage to be recorded: %v, got %v", tt.record, recorder.ids)
+			}
+			if tt.record && (recorder.ids[0] != tt.auth.ID || recorder.froms[0] != "192.0.2.1") {
+				t.Errorf("unexpected usage of %v from %v", recorder.ids, recorder.froms)
+			}
+		})
+	}
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
@@ -295,3 +390,83 @@ func TestAuthenticationHandler_NoAuthRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticationHandler_AuditDenied(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		events []platform.AuditEvent
+	}{
+		{
+			name:   "allowed",
+			token:  "abc123",
+			status: http.StatusOK,
+		},
+		{
+			name:   "unknown token",
+			token:  "unknown",
+			status: http.StatusUnauthorized,
+			events: []platform.AuditEvent{{
+				Action:     platform.AuditDenied,
+				RemoteAddr: "192.0.2.1",
+				Request:    "POST /api/v2/buckets",
+				Error:      "authorization not found",
+			}},
+		},
+		{
+			name:   "forbidden",
+			token:  "abc123",
+			status: http.StatusForbidden,
+			events: []platform.AuditEvent{{
+				Action:          platform.AuditDenied,
+				OrgID:           10,
+				UserID:          2,
+				AuthorizationID: 1,
+				RemoteAddr:      "192.0.2.1",
+				Request:         "POST /api/v2/buckets",
+				Error:           "Forbidden",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := mock.NewAuditLogger()
+
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+					if token != "abc123" {
+						return nil, fmt.Errorf("authorization not found")
+					}
+					return &platform.Authorization{ID: 1, UserID: 2, OrgID: 10, Status: platform.Active}, nil
+				},
+			}
+			h.AuditLogger = l
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/buckets", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			platformhttp.SetToken(tt.token, r)
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("expected status code to be %d got %d", want, got)
+			}
+			if len(l.Events) != len(tt.events) {
+				t.Fatalf("expected %d events, got %+v", len(tt.events), l.Events)
+			}
+			for i, e := range l.Events {
+				got := *e
+				got.Time = time.Time{}
+				if diff := cmp.Diff(tt.events[i], got); diff != "" {
+					t.Errorf("unexpected event -want/+got\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+// BackupKVFile is the name of the key-value store snapshot included in every backup.
+const BackupKVFile = "influxd.bolt"
+
+// BackupBackend is all services and associated parameters required to construct the BackupHandler.
+type BackupBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+	BucketService   influxdb.BucketService
+}
+
+// NewBackupBackend returns a new instance of BackupBackend.
+func NewBackupBackend(b *APIBackend) *BackupBackend {
+	return &BackupBackend{
+		Logger:           b.Logger.With(zap.String("handler", "backup")),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+		BucketService:   b.BucketService,
+	}
+}
+
+// BackupHandler creates backups of the server and serves their files.
+type BackupHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+	BucketService   influxdb.BucketService
+}
+
+const (
+	backupPath          = "/api/v2/backup"
+	backupIDPath        = "/api/v2/backup/:backup_id"
+	backupIDParamName   = "backup_id"
+	backupFilePath      = "/api/v2/backup/:backup_id/file/:backup_file"
+	backupFileParamName = "backup_file"
+)
+
+// NewBackupHandler creates a new handler at /api/v2/backup to receive backup requests.
+func NewBackupHandler(b *BackupBackend) *BackupHandler {
+	h := &BackupHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+		BucketService:   b.BucketService,
+	}
+
+	h.HandlerFunc("POST", backupPath, h.handleCreate)
+	h.HandlerFunc("DELETE", backupIDPath, h.handleDelete)
+	h.HandlerFunc("GET", backupFilePath, h.handleFetchFile)
+	return h
+}
+
+func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreate")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	filter, err := decodeCreateBackupRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// A bucket is stored beneath its organization, so the organization is
+	// needed to select its data.
+	if filter.BucketID != nil && filter.OrgID == nil {
+		b, err := h.BucketService.FindBucketByID(ctx, *filter.BucketID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		filter.OrgID = &b.OrgID
+	}
+
+	backup, err := h.BackupService.CreateBackup(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.backupKV(ctx, backup.ID); err != nil {
+		if derr := h.BackupService.DeleteBackup(ctx, backup.ID); derr != nil {
+			h.Logger.Info("Failed to remove incomplete backup", zap.Int("backup_id", backup.ID), zap.Error(derr))
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	backup.Files = append(backup.Files, BackupKVFile)
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, backup); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// backupKV writes a snapshot of the key-value store into the backup directory.
+func (h *BackupHandler) backupKV(ctx context.Context, backupID int) error {
+	f, err := os.Create(filepath.Join(h.BackupService.InternalBackupPath(backupID), BackupKVFile))
+	if err != nil {
+		return err
+	}
+
+	if err := h.KVBackupService.Backup(ctx, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func decodeCreateBackupRequest(ctx context.Context, r *http.Request) (*influxdb.BackupFilter, error) {
+	filter := &influxdb.BackupFilter{}
+	if err := json.NewDecoder(r.Body).Decode(filter); err != nil && err != io.EOF {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}
+	}
+
+	if filter.Start != nil && filter.Stop != nil && filter.Start.After(*filter.Stop) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "start must not be after stop",
+		}
+	}
+	return filter, nil
+}
+
+func (h *BackupHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleDelete")
+	defer span.Finish()
+
+	ctx := r.Context()
+	backupID, err := decodeBackupID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BackupService.DeleteBackup(ctx, backupID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BackupHandler) handleFetchFile(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleFetchFile")
+	defer span.Finish()
+
+	ctx := r.Context()
+	backupID, err := decodeBackupID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	backupFile := httprouter.ParamsFromContext(ctx).ByName(backupFileParamName)
+
+	// Defer writing the response headers until the first write, so that errors
+	// opening the file are still reported with the appropriate status code.
+	bw := &lazyOctetStreamWriter{w: w}
+	if err := h.BackupService.FetchBackupFile(ctx, backupID, backupFile, bw); err != nil {
+		if !bw.written {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		h.Logger.Info("Failed to fetch backup file", zap.String("backup_file", backupFile), zap.Error(err))
+		return
+	}
+
+	if !bw.written {
+		bw.writeHeader()
+	}
+}
+
+// lazyOctetStreamWriter sets the response headers for a binary stream on the
+// first write.
+type lazyOctetStreamWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (w *lazyOctetStreamWriter) writeHeader() {
+	w.w.Header().Set("Content-Type", "application/octet-stream")
+	w.w.WriteHeader(http.StatusOK)
+	w.written = true
+}
+
+func (w *lazyOctetStreamWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.writeHeader()
+	}
+	return w.w.Write(p)
+}
+
+func decodeBackupID(ctx context.Context) (int, error) {
+	id := httprouter.ParamsFromContext(ctx).ByName(backupIDParamName)
+	backupID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup id %q", id),
+			Err:  err,
+		}
+	}
+	return backupID, nil
+}
+
+// BackupService is the client implementation of influxdb.BackupService.
+type BackupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+
+// CreateBackup creates a backup on the server and returns the files it contains.
+func (s *BackupService) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.Backup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+	req = req.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var backup influxdb.Backup
+	if err := json.NewDecoder(resp.Body).Decode(&backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// FetchBackupFile downloads a single file of the backup to w.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, path.Join(backupPath, strconv.Itoa(backupID), "file", backupFile))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+	req = req.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// DeleteBackup removes the backup from the server.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, path.Join(backupPath, strconv.Itoa(backupID)))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+	req = req.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// InternalBackupPath is not available to clients.
+func (s *BackupService) InternalBackupPath(backupID int) string {
+	panic("internal method not implemented here")
+}
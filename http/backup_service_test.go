@@ -0,0 +1,223 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+// NewMockBackupBackend returns a BackupBackend with mock services.
+func NewMockBackupBackend() *BackupBackend {
+	return &BackupBackend{
+		Logger: zap.NewNop().With(zap.String("handler", "backup")),
+
+		BackupService:   mock.NewBackupService(),
+		KVBackupService: mock.NewKVBackupService(),
+		BucketService:   mock.NewBucketService(),
+	}
+}
+
+func TestBackupHandler_handleCreate(t *testing.T) {
+	type wants struct {
+		statusCode int
+		filter     platform.BackupFilter
+		files      []string
+	}
+
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name  string
+		body  string
+		wants wants
+	}{
+		{
+			name: "full backup",
+			wants: wants{
+				statusCode: http.StatusCreated,
+				files:      []string{"000000001-000000001.tsm", BackupKVFile},
+			},
+		},
+		{
+			name: "bucket backup looks up organization",
+			body: `{"bucketID":"0000000000000002"}`,
+			wants: wants{
+				statusCode: http.StatusCreated,
+				filter:     platform.BackupFilter{OrgID: &orgID, BucketID: &bucketID},
+				files:      []string{"000000001-000000001.tsm", BackupKVFile},
+			},
+		},
+		{
+			name: "invalid json",
+			body: `{"bucketID":`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "backup-handler-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			var gotFilter platform.BackupFilter
+			backupBackend := NewMockBackupBackend()
+			backupBackend.HTTPErrorHandler = ErrorHandler(0)
+			backupBackend.BucketService = &mock.BucketService{
+				FindBucketByIDFn: func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+					return &platform.Bucket{ID: id, OrgID: orgID, Name: "bucket1"}, nil
+				},
+			}
+			backupBackend.BackupService = &mock.BackupService{
+				CreateBackupF: func(ctx context.Context, filter platform.BackupFilter) (*platform.Backup, error) {
+					gotFilter = filter
+					return &platform.Backup{ID: 1, Files: []string{"000000001-000000001.tsm"}}, nil
+				},
+				InternalBackupPathF: func(backupID int) string {
+					return dir
+				},
+			}
+			backupBackend.KVBackupService = &mock.KVBackupService{
+				BackupF: func(ctx context.Context, w io.Writer) error {
+					_, err := io.WriteString(w, "kv")
+					return err
+				},
+			}
+			h := NewBackupHandler(backupBackend)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/backup", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			if got, exp := res.StatusCode, tt.wants.statusCode; got != exp {
+				body, _ := ioutil.ReadAll(res.Body)
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if tt.wants.statusCode != http.StatusCreated {
+				return
+			}
+
+			if !reflect.DeepEqual(gotFilter, tt.wants.filter) {
+				t.Errorf("unexpected filter: got %+v, exp %+v", gotFilter, tt.wants.filter)
+			}
+
+			var backup platform.Backup
+			if err := json.NewDecoder(res.Body).Decode(&backup); err != nil {
+				t.Fatalf("unable to decode backup: %v", err)
+			}
+			if !reflect.DeepEqual(backup.Files, tt.wants.files) {
+				t.Errorf("unexpected files: got %v, exp %v", backup.Files, tt.wants.files)
+			}
+
+			kv, err := ioutil.ReadFile(filepath.Join(dir, BackupKVFile))
+			if err != nil {
+				t.Fatalf("unable to read kv backup: %v", err)
+			}
+			if got, exp := string(kv), "kv"; got != exp {
+				t.Errorf("unexpected kv backup: got %q, exp %q", got, exp)
+			}
+		})
+	}
+}
+
+func TestBackupHandler_handleFetchFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		body       string
+	}{
+		{
+			name:       "fetch file",
+			path:       "/api/v2/backup/1/file/000000001-000000001.tsm",
+			statusCode: http.StatusOK,
+			body:       "tsm",
+		},
+		{
+			name:       "missing file",
+			path:       "/api/v2/backup/1/file/missing.tsm",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "invalid backup id",
+			path:       "/api/v2/backup/abc/file/000000001-000000001.tsm",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backupBackend := NewMockBackupBackend()
+			backupBackend.HTTPErrorHandler = ErrorHandler(0)
+			backupBackend.BackupService = &mock.BackupService{
+				FetchBackupFileF: func(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+					if backupID != 1 || backupFile != "000000001-000000001.tsm" {
+						return &platform.Error{Code: platform.ENotFound, Msg: "backup file not found"}
+					}
+					_, err := io.WriteString(w, "tsm")
+					return err
+				},
+			}
+			h := NewBackupHandler(backupBackend)
+
+			r := httptest.NewRequest("GET", "http://localhost:9999"+tt.path, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if got, exp := res.StatusCode, tt.statusCode; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if tt.statusCode == http.StatusOK {
+				if got, exp := string(body), tt.body; got != exp {
+					t.Errorf("unexpected body: got %q, exp %q", got, exp)
+				}
+				if got, exp := res.Header.Get("Content-Type"), "application/octet-stream"; got != exp {
+					t.Errorf("unexpected content type: got %q, exp %q", got, exp)
+				}
+			}
+		})
+	}
+}
+
+func TestBackupHandler_handleDelete(t *testing.T) {
+	var gotID int
+	backupBackend := NewMockBackupBackend()
+	backupBackend.HTTPErrorHandler = ErrorHandler(0)
+	backupBackend.BackupService = &mock.BackupService{
+		DeleteBackupF: func(ctx context.Context, backupID int) error {
+			gotID = backupID
+			return nil
+		},
+	}
+	h := NewBackupHandler(backupBackend)
+
+	r := httptest.NewRequest("DELETE", "http://localhost:9999/api/v2/backup/3", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if got, exp := w.Result().StatusCode, http.StatusNoContent; got != exp {
+		t.Fatalf("unexpected status code: got %d, exp %d", got, exp)
+	}
+	if got, exp := gotID, 3; got != exp {
+		t.Errorf("unexpected backup id: got %d, exp %d", got, exp)
+	}
+}
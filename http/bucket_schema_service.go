@@ -0,0 +1,294 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+type measurementSchemasResponse struct {
+	MeasurementSchemas []*influxdb.MeasurementSchema `json:"measurementSchemas"`
+}
+
+func newMeasurementSchemasResponse(ss []*influxdb.MeasurementSchema) *measurementSchemasResponse {
+	if ss == nil {
+		ss = []*influxdb.MeasurementSchema{}
+	}
+	return &measurementSchemasResponse{
+		MeasurementSchemas: ss,
+	}
+}
+
+// handleGetMeasurementSchemas is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handleGetMeasurementSchemas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.MeasurementSchemaFilter{BucketID: bucketID}
+	if name := r.URL.Query().Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	ss, err := h.BucketSchemaService.FindMeasurementSchemas(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("measurement schemas retrieved", zap.String("bucketID", bucketID.String()), zap.Int("count", len(ss)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemasResponse(ss)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostMeasurementSchema is the HTTP handler for the POST /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handlePostMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms := &influxdb.MeasurementSchema{}
+	if err := json.NewDecoder(r.Body).Decode(ms); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	ms.BucketID = b.ID
+	ms.OrgID = b.OrgID
+
+	if err := h.BucketSchemaService.CreateMeasurementSchema(ctx, ms); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("measurement schema created", zap.String("schema", fmt.Sprint(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, ms); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetMeasurementSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handleGetMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, err := h.BucketSchemaService.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("measurement schema retrieved", zap.String("schema", fmt.Sprint(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, ms); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchMeasurementSchema is the HTTP handler for the PATCH /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handlePatchMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.MeasurementSchemaUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	ms, err := h.BucketSchemaService.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("measurement schema updated", zap.String("schema", fmt.Sprint(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, ms); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetObservedMeasurementSchemas is the HTTP handler for the GET /api/v2/buckets/:id/schema/observed route.
+// It responds with the schemas of the data that has been written to the bucket.
+func (h *BucketHandler) handleGetObservedMeasurementSchemas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, err := h.MeasurementSchemaReader.ReadMeasurementSchemas(ctx, b.OrgID, b.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemasResponse(ss)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeMeasurementSchemaIDs(ctx context.Context) (bucketID, id influxdb.ID, err error) {
+	if bucketID, err = decodeIDParam(ctx, "id"); err != nil {
+		return 0, 0, err
+	}
+	if id, err = decodeIDParam(ctx, "measurementID"); err != nil {
+		return 0, 0, err
+	}
+	return bucketID, id, nil
+}
+
+// decodeIDParam decodes the ID in the url parameter named name.
+func decodeIDParam(ctx context.Context, name string) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName(name)
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing " + name,
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return i, nil
+}
+
+// BucketSchemaService connects to Influx via HTTP using tokens to manage the
+// measurement schemas of buckets.
+type BucketSchemaService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+var _ influxdb.MeasurementSchemaReader = (*BucketSchemaService)(nil)
+
+func measurementSchemasPath(bucketID influxdb.ID) string {
+	return path.Join(bucketPath, bucketID.String(), "schema", "measurements")
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ms influxdb.MeasurementSchema
+	if err := s.do(ctx, "GET", path.Join(measurementSchemasPath(bucketID), id.String()), nil, nil, &ms); err != nil {
+		return nil, err
+	}
+	return &ms, nil
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	query := url.Values{}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res measurementSchemasResponse
+	if err := s.do(ctx, "GET", measurementSchemasPath(filter.BucketID), query, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.MeasurementSchemas, nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and sets ms.ID with the new identifier.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, ms *influxdb.MeasurementSchema) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.do(ctx, "POST", measurementSchemasPath(ms.BucketID), nil, ms, ms)
+}
+
+// UpdateMeasurementSchema adds tags and fields to a measurement schema of a bucket.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ms influxdb.MeasurementSchema
+	if err := s.do(ctx, "PATCH", path.Join(measurementSchemasPath(bucketID), id.String()), nil, upd, &ms); err != nil {
+		return nil, err
+	}
+	return &ms, nil
+}
+
+// ReadMeasurementSchemas returns the schemas of the data that has been written to the bucket.
+// The orgID is implied by the bucket.
+func (s *BucketSchemaService) ReadMeasurementSchemas(ctx context.Context, orgID, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res measurementSchemasResponse
+	if err := s.do(ctx, "GET", path.Join(bucketPath, bucketID.String(), "schema", "observed"), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.MeasurementSchemas, nil
+}
+
+func (s *BucketSchemaService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
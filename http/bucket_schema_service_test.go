@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBucketSchemaService_Client(t *testing.T) {
+	ctx := context.Background()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket", SchemaType: influxdb.SchemaTypeExplicit}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	observed := []*influxdb.MeasurementSchema{{
+		OrgID:    org.ID,
+		BucketID: bucket.ID,
+		Name:     "mem",
+		Tags:     []string{"host"},
+		Fields:   []influxdb.MeasurementSchemaField{{Name: "free", Type: influxdb.SchemaFieldTypeInteger}},
+	}}
+
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	backend.BucketService = svc
+	backend.BucketSchemaService = svc
+	backend.MeasurementSchemaReader = &mock.MeasurementSchemaReader{
+		ReadMeasurementSchemasF: func(ctx context.Context, orgID, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+			if orgID != org.ID || bucketID != bucket.ID {
+				t.Errorf("unexpected bucket %s/%s", orgID, bucketID)
+			}
+			return observed, nil
+		},
+	}
+	server := newAuthorizedTestServer(NewBucketHandler(backend), 1)
+	defer server.Close()
+
+	client := &BucketSchemaService{Addr: server.URL}
+	ms := &influxdb.MeasurementSchema{
+		BucketID: bucket.ID,
+		Name:     "cpu",
+		Tags:     []string{"host"},
+		Fields:   []influxdb.MeasurementSchemaField{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}},
+	}
+	if err := client.CreateMeasurementSchema(ctx, ms); err != nil {
+		t.Fatalf("unable to create measurement schema: %v", err)
+	}
+	if !ms.ID.Valid() || ms.OrgID != org.ID {
+		t.Fatalf("unexpected measurement schema: %+v", ms)
+	}
+
+	if err := client.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+		BucketID: bucket.ID,
+		Name:     "cpu",
+		Fields:   []influxdb.MeasurementSchemaField{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}},
+	}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected conflict creating a duplicate schema, got %v", err)
+	}
+
+	found, err := client.FindMeasurementSchemaByID(ctx, bucket.ID, ms.ID)
+	if err != nil {
+		t.Fatalf("unable to find measurement schema: %v", err)
+	}
+	if !cmp.Equal(found, ms) {
+		t.Errorf("unexpected measurement schema -got/+want\n%s", cmp.Diff(found, ms))
+	}
+
+	updated, err := client.UpdateMeasurementSchema(ctx, bucket.ID, ms.ID, influxdb.MeasurementSchemaUpdate{
+		Fields: []influxdb.MeasurementSchemaField{{Name: "idle", Type: influxdb.SchemaFieldTypeFloat}},
+	})
+	if err != nil {
+		t.Fatalf("unable to update measurement schema: %v", err)
+	}
+	if len(updated.Fields) != 2 {
+		t.Errorf("unexpected fields after update: %+v", updated.Fields)
+	}
+
+	_, err = client.UpdateMeasurementSchema(ctx, bucket.ID, ms.ID, influxdb.MeasurementSchemaUpdate{
+		Fields: []influxdb.MeasurementSchemaField{{Name: "idle", Type: influxdb.SchemaFieldTypeString}},
+	})
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected conflict changing a field type, got %v", err)
+	}
+
+	name := "cpu"
+	list, err := client.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID, Name: &name})
+	if err != nil {
+		t.Fatalf("unable to find measurement schemas: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != ms.ID {
+		t.Errorf("unexpected measurement schemas: %+v", list)
+	}
+
+	got, err := client.ReadMeasurementSchemas(ctx, org.ID, bucket.ID)
+	if err != nil {
+		t.Fatalf("unable to read observed schemas: %v", err)
+	}
+	if !cmp.Equal(got, observed) {
+		t.Errorf("unexpected observed schemas -got/+want\n%s", cmp.Diff(got, observed))
+	}
+}
@@ -28,6 +28,8 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	BucketSchemaService        influxdb.BucketSchemaService
+	MeasurementSchemaReader    influxdb.MeasurementSchemaReader
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -42,6 +44,8 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		BucketSchemaService:        b.BucketSchemaService,
+		MeasurementSchemaReader:    b.MeasurementSchemaReader,
 	}
 }
 
@@ -57,6 +61,8 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	BucketSchemaService        influxdb.BucketSchemaService
+	MeasurementSchemaReader    influxdb.MeasurementSchemaReader
 }
 
 const (
@@ -69,6 +75,10 @@ const (
 	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
 	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
 	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+
+	bucketsIDSchemaMeasurementsPath   = "/api/v2/buckets/:id/schema/measurements"
+	bucketsIDSchemaMeasurementsIDPath = "/api/v2/buckets/:id/schema/measurements/:measurementID"
+	bucketsIDSchemaObservedPath       = "/api/v2/buckets/:id/schema/observed"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -84,6 +94,8 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		BucketSchemaService:        b.BucketSchemaService,
+		MeasurementSchemaReader:    b.MeasurementSchemaReader,
 	}
 
 	h.HandlerFunc("POST", bucketsPath, h.handlePostBucket)
@@ -93,6 +105,12 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsPath, h.handleGetMeasurementSchemas)
+	h.HandlerFunc("POST", bucketsIDSchemaMeasurementsPath, h.handlePostMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsIDPath, h.handleGetMeasurementSchema)
+	h.HandlerFunc("PATCH", bucketsIDSchemaMeasurementsIDPath, h.handlePatchMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaObservedPath, h.handleGetObservedMeasurementSchemas)
+
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
 		Logger:                     b.Logger.With(zap.String("handler", "member")),
@@ -132,13 +150,15 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Type                string          `json:"type"`
-	Description         string          `json:"description,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                  influxdb.ID      `json:"id,omitempty"`
+	OrgID               influxdb.ID      `json:"orgID,omitempty"`
+	Type                string           `json:"type"`
+	Description         string           `json:"description,omitempty"`
+	Name                string           `json:"name"`
+	RetentionPolicyName string           `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule  `json:"retentionRules"`
+	SchemaType          string           `json:"schemaType,omitempty"`
+	DownsampleTiers     []downsampleTier `json:"downsampleTiers,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -148,6 +168,49 @@ type retentionRule struct {
 	EverySeconds int64  `json:"everySeconds"`
 }
 
+// downsampleTier is a downsample tier of a bucket with its durations in seconds.
+type downsampleTier struct {
+	TargetBucketID influxdb.ID                           `json:"targetBucketID"`
+	EverySeconds   int64                                 `json:"everySeconds"`
+	AfterSeconds   int64                                 `json:"afterSeconds"`
+	Aggregates     map[influxdb.SchemaFieldType][]string `json:"aggregates"`
+	TaskID         influxdb.ID                           `json:"taskID,omitempty"`
+}
+
+func newDownsampleTiers(ts []influxdb.DownsampleTier) []downsampleTier {
+	if ts == nil {
+		return nil
+	}
+	tiers := make([]downsampleTier, 0, len(ts))
+	for _, t := range ts {
+		tiers = append(tiers, downsampleTier{
+			TargetBucketID: t.TargetBucketID,
+			EverySeconds:   int64(t.Every.Round(time.Second) / time.Second),
+			AfterSeconds:   int64(t.After.Round(time.Second) / time.Second),
+			Aggregates:     t.Aggregates,
+			TaskID:         t.TaskID,
+		})
+	}
+	return tiers
+}
+
+func downsampleTiersToInfluxDB(ts []downsampleTier) []influxdb.DownsampleTier {
+	if ts == nil {
+		return nil
+	}
+	tiers := make([]influxdb.DownsampleTier, 0, len(ts))
+	for _, t := range ts {
+		tiers = append(tiers, influxdb.DownsampleTier{
+			TargetBucketID: t.TargetBucketID,
+			Every:          time.Duration(t.EverySeconds) * time.Second,
+			After:          time.Duration(t.AfterSeconds) * time.Second,
+			Aggregates:     t.Aggregates,
+			TaskID:         t.TaskID,
+		})
+	}
+	return tiers
+}
+
 func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 	if b == nil {
 		return nil, nil
@@ -173,6 +236,8 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		SchemaType:          influxdb.SchemaType(b.SchemaType),
+		DownsampleTiers:     downsampleTiersToInfluxDB(b.DownsampleTiers),
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -199,15 +264,19 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		SchemaType:          string(pb.SchemaType),
+		DownsampleTiers:     newDownsampleTiers(pb.DownsampleTiers),
 		CRUDLog:             pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name            *string           `json:"name,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	RetentionRules  []retentionRule   `json:"retentionRules,omitempty"`
+	SchemaType      *string           `json:"schemaType,omitempty"`
+	DownsampleTiers *[]downsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -227,11 +296,23 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		}
 	}
 
-	return &influxdb.BucketUpdate{
+	upd := &influxdb.BucketUpdate{
 		Name:            b.Name,
 		Description:     b.Description,
 		RetentionPeriod: &d,
-	}, nil
+	}
+	if b.SchemaType != nil {
+		st := influxdb.SchemaType(*b.SchemaType)
+		upd.SchemaType = &st
+	}
+	if b.DownsampleTiers != nil {
+		tiers := downsampleTiersToInfluxDB(*b.DownsampleTiers)
+		if tiers == nil {
+			tiers = []influxdb.DownsampleTier{}
+		}
+		upd.DownsampleTiers = &tiers
+	}
+	return upd, nil
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -245,6 +326,19 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 		RetentionRules: []retentionRule{},
 	}
 
+	if pb.SchemaType != nil {
+		st := string(*pb.SchemaType)
+		up.SchemaType = &st
+	}
+
+	if pb.DownsampleTiers != nil {
+		tiers := newDownsampleTiers(*pb.DownsampleTiers)
+		if tiers == nil {
+			tiers = []downsampleTier{}
+		}
+		up.DownsampleTiers = &tiers
+	}
+
 	if pb.RetentionPeriod != nil {
 		d := int64((*pb.RetentionPeriod).Round(time.Second) / time.Second)
 		up.RetentionRules = append(up.RetentionRules, retentionRule{
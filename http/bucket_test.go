@@ -31,6 +31,8 @@ func NewMockBucketBackend() *BucketBackend {
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
 		OrganizationService:        mock.NewOrganizationService(),
+		BucketSchemaService:        mock.NewBucketSchemaService(),
+		MeasurementSchemaReader:    &mock.MeasurementSchemaReader{},
 	}
 }
 
@@ -1118,3 +1120,65 @@ func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.B
 func TestBucketService(t *testing.T) {
 	platformtesting.BucketService(initBucketService, t)
 }
+
+func TestBucketService_DownsampleTiers(t *testing.T) {
+	ctx := context.Background()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	target := &platform.Bucket{OrgID: org.ID, Name: "1h"}
+	if err := svc.CreateBucket(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	backend.BucketService = svc
+	server := newAuthorizedTestServer(NewBucketHandler(backend), 1)
+	defer server.Close()
+
+	client := &BucketService{Addr: server.URL}
+	tier := platform.DownsampleTier{
+		TargetBucketID: target.ID,
+		Every:          time.Hour,
+		After:          24 * time.Hour,
+		Aggregates: map[platform.SchemaFieldType][]string{
+			platform.SchemaFieldTypeFloat: {"mean"},
+		},
+	}
+	raw := &platform.Bucket{
+		OrgID:           org.ID,
+		Name:            "raw",
+		SchemaType:      platform.SchemaTypeExplicit,
+		DownsampleTiers: []platform.DownsampleTier{tier},
+	}
+	if err := client.CreateBucket(ctx, raw); err != nil {
+		t.Fatalf("unable to create bucket: %v", err)
+	}
+	if len(raw.DownsampleTiers) != 1 {
+		t.Fatalf("unexpected downsample tiers: %+v", raw.DownsampleTiers)
+	}
+	got := raw.DownsampleTiers[0]
+	if !got.TaskID.Valid() || got.Every != tier.Every || got.After != tier.After {
+		t.Errorf("unexpected downsample tier: %+v", got)
+	}
+
+	updated, err := client.UpdateBucket(ctx, raw.ID, platform.BucketUpdate{
+		DownsampleTiers: &[]platform.DownsampleTier{},
+	})
+	if err != nil {
+		t.Fatalf("unable to update bucket: %v", err)
+	}
+	if len(updated.DownsampleTiers) != 0 {
+		t.Errorf("expected downsample tiers to be removed, got %+v", updated.DownsampleTiers)
+	}
+	if _, err := svc.FindTaskByID(ctx, got.TaskID); err != platform.ErrTaskNotFound {
+		t.Errorf("expected the tier task to be deleted, got %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
@@ -315,7 +317,10 @@ func decodeCheckFilter(ctx context.Context, r *http.Request) (*influxdb.CheckFil
 		}
 		f.OrgID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Org = orgNameStr
+		f.Org = &orgNameStr
+	}
+	if name := q.Get("name"); name != "" {
+		f.Name = &name
 	}
 	return f, opts, err
 }
@@ -520,3 +525,113 @@ func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// CheckService connects to Influx via HTTP using tokens to manage checks.
+type CheckService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindCheckByID returns a single check by ID.
+func (s *CheckService) FindCheckByID(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "GET", checkIDPath(id), nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(b)
+}
+
+// FindCheck returns the first check that matches filter.
+func (s *CheckService) FindCheck(ctx context.Context, filter influxdb.CheckFilter) (influxdb.Check, error) {
+	cs, n, err := s.FindChecks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindCheck,
+			Msg:  "check not found",
+		}
+	}
+
+	return cs[0], nil
+}
+
+// FindChecks returns a list of checks that match filter and the total count of matching checks.
+func (s *CheckService) FindChecks(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+	query := filter.QueryParams()
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			query[k] = vs
+		}
+	}
+
+	var res struct {
+		Checks []json.RawMessage `json:"checks"`
+	}
+	if err := s.do(ctx, "GET", checksPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	cs := make([]influxdb.Check, 0, len(res.Checks))
+	for _, b := range res.Checks {
+		c, err := check.UnmarshalJSON(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, len(cs), nil
+}
+
+// CreateCheck creates a new check and sets its ID with the new identifier.
+// The check is owned by the user of the token, so userID is ignored.
+func (s *CheckService) CreateCheck(ctx context.Context, c influxdb.Check, userID influxdb.ID) error {
+	var b json.RawMessage
+	if err := s.do(ctx, "POST", checksPath, nil, c, &b); err != nil {
+		return err
+	}
+
+	created, err := check.UnmarshalJSON(b)
+	if err != nil {
+		return err
+	}
+	c.SetID(created.GetID())
+	return nil
+}
+
+// UpdateCheck updates a single check.
+// Returns the new check after update.
+func (s *CheckService) UpdateCheck(ctx context.Context, id influxdb.ID, c influxdb.Check) (influxdb.Check, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PUT", checkIDPath(id), nil, c, &b); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(b)
+}
+
+// PatchCheck updates a single check with changeset.
+// Returns the new check after update.
+func (s *CheckService) PatchCheck(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (influxdb.Check, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PATCH", checkIDPath(id), nil, upd, &b); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(b)
+}
+
+// DeleteCheck removes a check by ID.
+func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
+	return s.do(ctx, "DELETE", checkIDPath(id), nil, nil, nil)
+}
+
+func (s *CheckService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
+
+func checkIDPath(id influxdb.ID) string {
+	return path.Join(checksPath, id.String())
+}
@@ -1421,3 +1421,113 @@ func TestService_handlePostCheckOwner(t *testing.T) {
 // func TestCheckService(t *testing.T) {
 // 	influxTestingCheckService(initCheckService, t)
 // }
+
+// newAuthorizedTestServer returns a test server for h that authorizes all requests as userID.
+func newAuthorizedTestServer(h http.Handler, userID influxdb.ID) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: userID}))
+		h.ServeHTTP(w, r)
+	}))
+}
+
+func TestCheckService_Client(t *testing.T) {
+	orgID := influxTesting.MustIDBase16("6f626f7274697320")
+	checkID := influxTesting.MustIDBase16("020f755c3c082000")
+
+	var stored influxdb.Check
+	var filter influxdb.CheckFilter
+	checkBackend := NewMockCheckBackend()
+	checkBackend.HTTPErrorHandler = ErrorHandler(0)
+	checkBackend.CheckService = &mock.CheckService{
+		CreateCheckFn: func(ctx context.Context, c influxdb.Check, userID influxdb.ID) error {
+			c.SetID(checkID)
+			c.SetOwnerID(userID)
+			stored = c
+			return nil
+		},
+		FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+			if stored == nil || id != stored.GetID() {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "check not found"}
+			}
+			return stored, nil
+		},
+		FindChecksFn: func(ctx context.Context, f influxdb.CheckFilter, opts ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+			filter = f
+			return []influxdb.Check{stored}, 1, nil
+		},
+		PatchCheckFn: func(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (influxdb.Check, error) {
+			if upd.Name != nil {
+				stored.SetName(*upd.Name)
+			}
+			if upd.Status != nil {
+				stored.SetStatus(*upd.Status)
+			}
+			return stored, nil
+		},
+		DeleteCheckFn: func(ctx context.Context, id influxdb.ID) error {
+			stored = nil
+			return nil
+		},
+	}
+	server := newAuthorizedTestServer(NewCheckHandler(checkBackend), 1)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &CheckService{Addr: server.URL}
+	c := &check.Deadman{
+		Base: check.Base{
+			Name:   "hello",
+			OrgID:  orgID,
+			Status: influxdb.Active,
+			Every:  mustDuration("5m"),
+		},
+		TimeSince: mustDuration("13s"),
+		StaleTime: mustDuration("1h"),
+		Level:     notification.Critical,
+	}
+	if err := client.CreateCheck(ctx, c, 0); err != nil {
+		t.Fatalf("unable to create check: %v", err)
+	}
+	if c.ID != checkID {
+		t.Fatalf("unexpected check id: got %s, exp %s", c.ID, checkID)
+	}
+
+	found, err := client.FindCheckByID(ctx, checkID)
+	if err != nil {
+		t.Fatalf("unable to find check: %v", err)
+	}
+	if got, ok := found.(*check.Deadman); !ok || got.Name != "hello" || got.Level != notification.Critical {
+		t.Errorf("unexpected check: %+v", found)
+	}
+
+	name := "hello"
+	cs, n, err := client.FindChecks(ctx, influxdb.CheckFilter{OrgID: &orgID, Name: &name})
+	if err != nil {
+		t.Fatalf("unable to find checks: %v", err)
+	}
+	if n != 1 || cs[0].GetID() != checkID {
+		t.Errorf("unexpected checks: %+v", cs)
+	}
+	if filter.OrgID == nil || *filter.OrgID != orgID || filter.Name == nil || *filter.Name != name {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+
+	newName := "world"
+	patched, err := client.PatchCheck(ctx, checkID, influxdb.CheckUpdate{
+		Name:   &newName,
+		Status: influxdb.Inactive.Ptr(),
+	})
+	if err != nil {
+		t.Fatalf("unable to patch check: %v", err)
+	}
+	if patched.GetName() != newName || patched.GetStatus() != influxdb.Inactive {
+		t.Errorf("unexpected patched check: %+v", patched)
+	}
+
+	if err := client.DeleteCheck(ctx, checkID); err != nil {
+		t.Fatalf("unable to delete check: %v", err)
+	}
+	if _, err := client.FindCheckByID(ctx, checkID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected deleted check to be not found, got %v", err)
+	}
+}
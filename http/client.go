@@ -1,6 +1,10 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -64,6 +68,52 @@ func NewURL(addr, path string) (*url.URL, error) {
 	return u, nil
 }
 
+// doJSONRequest sends a request with body encoded as JSON, if any, to path
+// and decodes the JSON response into v, if any.
+func doJSONRequest(ctx context.Context, addr, token string, insecure bool, method, path string, query url.Values, body, v interface{}) error {
+	u, err := NewURL(addr, path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = query.Encode()
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if v != nil {
+		req.Header.Set("Accept", "application/json")
+	}
+	SetToken(token, req)
+
+	hc := NewClient(u.Scheme, insecure)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
 // NewClient returns an http.Client that pools connections and injects a span.
 func NewClient(scheme string, insecure bool) *traceClient {
 	hc := &traceClient{
@@ -0,0 +1,349 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+const (
+	dbrpPath = "/api/v2/dbrps"
+)
+
+// DBRPMappingBackend is all services and associated parameters required to construct
+// the DBRPMappingHandler.
+type DBRPMappingBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingBackend returns a new instance of DBRPMappingBackend.
+func NewDBRPMappingBackend(b *APIBackend) *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "dbrp")),
+
+		DBRPMappingService: b.DBRPMappingService,
+	}
+}
+
+// DBRPMappingHandler is the handler for the mappings of 1.x databases and
+// retention policies to buckets.
+type DBRPMappingHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingHandler returns a new instance of DBRPMappingHandler.
+func NewDBRPMappingHandler(b *DBRPMappingBackend) *DBRPMappingHandler {
+	h := &DBRPMappingHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DBRPMappingService: b.DBRPMappingService,
+	}
+
+	h.HandlerFunc("GET", dbrpPath, h.handleGetDBRPMappings)
+	h.HandlerFunc("POST", dbrpPath, h.handlePostDBRPMapping)
+	h.HandlerFunc("DELETE", dbrpPath, h.handleDeleteDBRPMapping)
+	return h
+}
+
+type dbrpMappingsResponse struct {
+	Links        map[string]string       `json:"links"`
+	DBRPMappings []*influxdb.DBRPMapping `json:"dbrps"`
+}
+
+func newDBRPMappingsResponse(ms []*influxdb.DBRPMapping) *dbrpMappingsResponse {
+	return &dbrpMappingsResponse{
+		Links: map[string]string{
+			"self": dbrpPath,
+		},
+		DBRPMappings: ms,
+	}
+}
+
+// handlePostDBRPMapping is the HTTP handler for the POST /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handlePostDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DBRPMappingHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	m, err := decodePostDBRPMappingRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Create(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping created", zap.String("database", m.Database), zap.String("retention_policy", m.RetentionPolicy))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, m); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostDBRPMappingRequest(ctx context.Context, r *http.Request) (*influxdb.DBRPMapping, error) {
+	m := &influxdb.DBRPMapping{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}
+	}
+
+	if m.Cluster == "" {
+		m.Cluster = influxdb.DefaultDBRPCluster
+	}
+	return m, nil
+}
+
+// handleGetDBRPMappings is the HTTP handler for the GET /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handleGetDBRPMappings(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DBRPMappingHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := decodeGetDBRPMappingsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.DBRPMappingService.FindMany(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mappings retrieved", zap.Int("count", len(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPMappingsResponse(ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetDBRPMappingsRequest(ctx context.Context, r *http.Request) (*influxdb.DBRPMappingFilter, error) {
+	qp := r.URL.Query()
+	filter := &influxdb.DBRPMappingFilter{}
+
+	if cluster := qp.Get("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+	if db := qp.Get("db"); db != "" {
+		filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	if def := qp.Get("default"); def != "" {
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "default must be true or false",
+				Err:  err,
+			}
+		}
+		filter.Default = &b
+	}
+	return filter, nil
+}
+
+// handleDeleteDBRPMapping is the HTTP handler for the DELETE /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handleDeleteDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DBRPMappingHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	qp := r.URL.Query()
+	cluster, db, rp := qp.Get("cluster"), qp.Get("db"), qp.Get("rp")
+	if cluster == "" {
+		cluster = influxdb.DefaultDBRPCluster
+	}
+	if db == "" || rp == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "db and rp are required",
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Delete(ctx, cluster, db, rp); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping deleted", zap.String("database", db), zap.String("retention_policy", rp))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DBRPMappingService is the client implementation of influxdb.DBRPMappingService.
+type DBRPMappingService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *DBRPMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	return s.Find(ctx, influxdb.DBRPMappingFilter{
+		Cluster:         &cluster,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+}
+
+// Find returns the first dbrp mapping that matches the filter.
+func (s *DBRPMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	ms, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "dbrp mapping not found",
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns a list of dbrp mappings that match filter and the total count of matching dbrp mappings.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, dbrpPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	params := u.Query()
+	if filter.Cluster != nil {
+		params.Set("cluster", *filter.Cluster)
+	}
+	if filter.Database != nil {
+		params.Set("db", *filter.Database)
+	}
+	if filter.RetentionPolicy != nil {
+		params.Set("rp", *filter.RetentionPolicy)
+	}
+	if filter.Default != nil {
+		params.Set("default", strconv.FormatBool(*filter.Default))
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var res dbrpMappingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, 0, err
+	}
+	return res.DBRPMappings, len(res.DBRPMappings), nil
+}
+
+// Create creates a new dbrp mapping, if a different mapping exists an error is returned.
+func (s *DBRPMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, dbrpPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(m)
+}
+
+// Delete removes a dbrp mapping.
+func (s *DBRPMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, dbrpPath)
+	if err != nil {
+		return err
+	}
+
+	params := u.Query()
+	params.Set("cluster", cluster)
+	params.Set("db", db)
+	params.Set("rp", rp)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+// NewMockDBRPMappingBackend returns a DBRPMappingBackend with mock services.
+func NewMockDBRPMappingBackend() *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		Logger: zap.NewNop().With(zap.String("handler", "dbrp")),
+
+		DBRPMappingService: mock.NewDBRPMappingService(),
+	}
+}
+
+func TestDBRPMappingHandler_handleGetDBRPMappings(t *testing.T) {
+	var gotFilter platform.DBRPMappingFilter
+	dbrpBackend := NewMockDBRPMappingBackend()
+	dbrpBackend.HTTPErrorHandler = ErrorHandler(0)
+	dbrpBackend.DBRPMappingService = &mock.DBRPMappingService{
+		FindManyFn: func(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
+			gotFilter = filter
+			return []*platform.DBRPMapping{
+				{Cluster: "local", Database: "db0", RetentionPolicy: "autogen", Default: true, OrganizationID: 1, BucketID: 2},
+			}, 1, nil
+		},
+	}
+	h := NewDBRPMappingHandler(dbrpBackend)
+
+	r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/dbrps?db=db0&default=true", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if got, exp := res.StatusCode, http.StatusOK; got != exp {
+		t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+	}
+
+	db, def := "db0", true
+	if exp := (platform.DBRPMappingFilter{Database: &db, Default: &def}); !reflect.DeepEqual(gotFilter, exp) {
+		t.Errorf("unexpected filter: got %+v, exp %+v", gotFilter, exp)
+	}
+
+	exp := `{"links":{"self":"/api/v2/dbrps"},"dbrps":[{"cluster":"local","database":"db0","retention_policy":"autogen","default":true,"organization_id":"0000000000000001","bucket_id":"0000000000000002"}]}`
+	if eq, diff, _ := jsonEqual(string(body), exp); !eq {
+		t.Errorf("unexpected body -got/+exp\n%s", diff)
+	}
+}
+
+func TestDBRPMappingHandler_handlePostDBRPMapping(t *testing.T) {
+	var got *platform.DBRPMapping
+	dbrpBackend := NewMockDBRPMappingBackend()
+	dbrpBackend.HTTPErrorHandler = ErrorHandler(0)
+	dbrpBackend.DBRPMappingService = &mock.DBRPMappingService{
+		CreateFn: func(ctx context.Context, m *platform.DBRPMapping) error {
+			got = m
+			return nil
+		},
+	}
+	h := NewDBRPMappingHandler(dbrpBackend)
+
+	body := `{"database":"db0","retention_policy":"autogen","default":true,"organization_id":"0000000000000001","bucket_id":"0000000000000002"}`
+	r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/dbrps", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	if got, exp := res.StatusCode, http.StatusCreated; got != exp {
+		b, _ := ioutil.ReadAll(res.Body)
+		t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, b)
+	}
+
+	exp := &platform.DBRPMapping{
+		Cluster:         platform.DefaultDBRPCluster,
+		Database:        "db0",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  1,
+		BucketID:        2,
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("unexpected mapping: got %+v, exp %+v", got, exp)
+	}
+
+	var m platform.DBRPMapping
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		t.Fatalf("unable to decode mapping: %v", err)
+	}
+	if !reflect.DeepEqual(&m, exp) {
+		t.Errorf("unexpected response: got %+v, exp %+v", m, exp)
+	}
+}
+
+func TestDBRPMappingHandler_handleDeleteDBRPMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		statusCode int
+	}{
+		{
+			name:       "delete mapping",
+			url:        "/api/v2/dbrps?db=db0&rp=autogen",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "missing retention policy",
+			url:        "/api/v2/dbrps?db=db0",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCluster string
+			dbrpBackend := NewMockDBRPMappingBackend()
+			dbrpBackend.HTTPErrorHandler = ErrorHandler(0)
+			dbrpBackend.DBRPMappingService = &mock.DBRPMappingService{
+				DeleteFn: func(ctx context.Context, cluster, db, rp string) error {
+					gotCluster = cluster
+					return nil
+				},
+			}
+			h := NewDBRPMappingHandler(dbrpBackend)
+
+			r := httptest.NewRequest("DELETE", "http://localhost:9999"+tt.url, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, exp := w.Result().StatusCode, tt.statusCode; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d", got, exp)
+			}
+			if tt.statusCode == http.StatusNoContent && gotCluster != platform.DefaultDBRPCluster {
+				t.Errorf("unexpected cluster: got %q, exp %q", gotCluster, platform.DefaultDBRPCluster)
+			}
+		})
+	}
+}
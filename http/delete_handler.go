@@ -0,0 +1,238 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/storage"
+)
+
+// DeleteBackend is all services and associated parameters required to construct
+// the DeleteHandler.
+type DeleteBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DeleteService       platform.DeleteService
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+// NewDeleteBackend returns a new instance of DeleteBackend.
+func NewDeleteBackend(b *APIBackend) *DeleteBackend {
+	return &DeleteBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "delete")),
+
+		DeleteService:       b.DeleteService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// DeleteHandler receives a delete request with a predicate and sends it to storage.
+type DeleteHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DeleteService       platform.DeleteService
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+const (
+	deletePath = "/api/v2/delete"
+)
+
+// NewDeleteHandler creates a new handler at /api/v2/delete to receive delete requests.
+func NewDeleteHandler(b *DeleteBackend) *DeleteHandler {
+	h := &DeleteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DeleteService:       b.DeleteService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", deletePath, h.handleDelete)
+	return h
+}
+
+func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DeleteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	req, err := decodeDeleteRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		logger.Info("Failed to find organization", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bucket, err := queryBucket(ctx, org.ID, req.Bucket, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Op:  "http/handleDelete",
+			Err: err,
+		}, w)
+		return
+	}
+
+	if bucket.IsSystem() {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   "http/handleDelete",
+			Msg:  fmt.Sprintf("cannot delete from internal bucket %s", bucket.Name),
+		}, w)
+		return
+	}
+
+	if err := h.DeleteService.DeleteBucketRangePredicate(ctx, org.ID, bucket.ID, req.Start, req.Stop, req.Predicate); err != nil {
+		logger.Info("Error deleting data", zap.Error(err))
+		h.HandleHTTPError(ctx, &platform.Error{
+			Op:  "http/handleDelete",
+			Err: err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteRequest is the JSON body of a request to /api/v2/delete.
+type deleteRequest struct {
+	Start     time.Time `json:"start"`
+	Stop      time.Time `json:"stop"`
+	Predicate string    `json:"predicate,omitempty"`
+}
+
+type postDeleteRequest struct {
+	Org       string
+	Bucket    string
+	Start     int64
+	Stop      int64
+	Predicate platform.Predicate
+}
+
+func decodeDeleteRequest(ctx context.Context, r *http.Request) (*postDeleteRequest, error) {
+	qp := r.URL.Query()
+	req := &postDeleteRequest{
+		Org:    qp.Get(Org),
+		Bucket: qp.Get("bucket"),
+	}
+	if req.Bucket == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "bucket is required",
+		}
+	}
+
+	var dr deleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dr); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}
+	}
+
+	if dr.Start.IsZero() || dr.Stop.IsZero() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "start and stop are required",
+		}
+	}
+	if dr.Start.After(dr.Stop) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "start must not be after stop",
+		}
+	}
+	req.Start, req.Stop = dr.Start.UnixNano(), dr.Stop.UnixNano()
+
+	pred, err := storage.ParsePredicate(dr.Predicate)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  "http/decodeDeleteRequest",
+			Err: err,
+		}
+	}
+	req.Predicate = pred
+
+	return req, nil
+}
+
+// DeleteService sends delete requests over HTTP to influxdb.
+type DeleteService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Delete removes the data in the bucket between start and stop that matches the predicate.
+// An empty predicate deletes all data in the time range.
+func (s *DeleteService) Delete(ctx context.Context, orgID, bucketID platform.ID, start, stop time.Time, predicate string) error {
+	u, err := NewURL(s.Addr, deletePath)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(deleteRequest{
+		Start:     start,
+		Stop:      stop,
+		Predicate: predicate,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	params.Set("org", orgID.String())
+	params.Set("bucket", bucketID.String())
+	req.URL.RawQuery = params.Encode()
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
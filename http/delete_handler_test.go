@@ -0,0 +1,211 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+// NewMockDeleteBackend returns a DeleteBackend with mock services.
+func NewMockDeleteBackend() *DeleteBackend {
+	return &DeleteBackend{
+		Logger: zap.NewNop().With(zap.String("handler", "delete")),
+
+		DeleteService:       mock.NewDeleteService(),
+		BucketService:       mock.NewBucketService(),
+		OrganizationService: mock.NewOrganizationService(),
+	}
+}
+
+func TestDeleteHandler_handleDelete(t *testing.T) {
+	type args struct {
+		queryParams string
+		body        string
+	}
+	type wants struct {
+		statusCode int
+		start      int64
+		stop       int64
+		predicate  bool
+	}
+
+	start := time.Date(2019, 8, 24, 14, 15, 22, 0, time.UTC)
+	stop := time.Date(2019, 8, 25, 14, 15, 22, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "delete with predicate",
+			args: args{
+				queryParams: "?org=org1&bucket=bucket1",
+				body:        `{"start":"2019-08-24T14:15:22Z","stop":"2019-08-25T14:15:22Z","predicate":"_measurement=\"cpu\" AND host='a'"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				start:      start.UnixNano(),
+				stop:       stop.UnixNano(),
+				predicate:  true,
+			},
+		},
+		{
+			name: "delete without predicate",
+			args: args{
+				queryParams: "?org=org1&bucket=bucket1",
+				body:        `{"start":"2019-08-24T14:15:22Z","stop":"2019-08-25T14:15:22Z"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				start:      start.UnixNano(),
+				stop:       stop.UnixNano(),
+			},
+		},
+		{
+			name: "missing bucket",
+			args: args{
+				queryParams: "?org=org1",
+				body:        `{"start":"2019-08-24T14:15:22Z","stop":"2019-08-25T14:15:22Z"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "missing stop",
+			args: args{
+				queryParams: "?org=org1&bucket=bucket1",
+				body:        `{"start":"2019-08-24T14:15:22Z"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "start after stop",
+			args: args{
+				queryParams: "?org=org1&bucket=bucket1",
+				body:        `{"start":"2019-08-25T14:15:22Z","stop":"2019-08-24T14:15:22Z"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "invalid predicate",
+			args: args{
+				queryParams: "?org=org1&bucket=bucket1",
+				body:        `{"start":"2019-08-24T14:15:22Z","stop":"2019-08-25T14:15:22Z","predicate":"host > 1"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				called           bool
+				gotMin, gotMax   int64
+				gotPred          platform.Predicate
+				gotOrg, gotBuckt platform.ID
+			)
+
+			deleteBackend := NewMockDeleteBackend()
+			deleteBackend.HTTPErrorHandler = ErrorHandler(0)
+			deleteBackend.OrganizationService = &mock.OrganizationService{
+				FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+					return &platform.Organization{ID: 1, Name: "org1"}, nil
+				},
+			}
+			deleteBackend.BucketService = &mock.BucketService{
+				FindBucketFn: func(ctx context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+					return &platform.Bucket{ID: 2, OrgID: 1, Name: "bucket1"}, nil
+				},
+			}
+			deleteBackend.DeleteService = &mock.DeleteService{
+				DeleteBucketRangePredicateF: func(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error {
+					called = true
+					gotOrg, gotBuckt = orgID, bucketID
+					gotMin, gotMax, gotPred = min, max, pred
+					return nil
+				},
+			}
+			h := NewDeleteHandler(deleteBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url"+deletePath+tt.args.queryParams, bytes.NewBufferString(tt.args.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wants.statusCode {
+				t.Fatalf("handleDelete() = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.statusCode != http.StatusNoContent {
+				if called {
+					t.Errorf("handleDelete() called delete service on invalid request")
+				}
+				return
+			}
+
+			if gotOrg != 1 || gotBuckt != 2 {
+				t.Errorf("handleDelete() org/bucket = %v/%v, want 1/2", gotOrg, gotBuckt)
+			}
+			if gotMin != tt.wants.start || gotMax != tt.wants.stop {
+				t.Errorf("handleDelete() range = [%d, %d], want [%d, %d]", gotMin, gotMax, tt.wants.start, tt.wants.stop)
+			}
+			if (gotPred != nil) != tt.wants.predicate {
+				t.Errorf("handleDelete() predicate = %v, want predicate %v", gotPred, tt.wants.predicate)
+			}
+		})
+	}
+}
+
+func TestDeleteService_Delete(t *testing.T) {
+	start := time.Date(2019, 8, 24, 14, 15, 22, 0, time.UTC)
+	stop := time.Date(2019, 8, 25, 14, 15, 22, 0, time.UTC)
+
+	var (
+		org, bucket *platform.ID
+		got         deleteRequest
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org, _ = platform.IDFromString(r.URL.Query().Get("org"))
+		bucket, _ = platform.IDFromString(r.URL.Query().Get("bucket"))
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("unable to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	s := &DeleteService{
+		Addr: ts.URL,
+	}
+	if err := s.Delete(context.Background(), 1, 2, start, stop, `host="a"`); err != nil {
+		t.Fatalf("DeleteService.Delete() error = %v", err)
+	}
+
+	if *org != 1 || *bucket != 2 {
+		t.Errorf("DeleteService.Delete() org/bucket = %v/%v, want 1/2", *org, *bucket)
+	}
+	if !got.Start.Equal(start) || !got.Stop.Equal(stop) {
+		t.Errorf("DeleteService.Delete() range = [%v, %v], want [%v, %v]", got.Start, got.Stop, start, stop)
+	}
+	if got.Predicate != `host="a"` {
+		t.Errorf("DeleteService.Delete() predicate = %q, want %q", got.Predicate, `host="a"`)
+	}
+}
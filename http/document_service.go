@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -128,6 +129,7 @@ func (h *DocumentHandler) handlePostDocument(w http.ResponseWriter, r *http.Requ
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	a = authorizer.WithRoles(ctx, a)
 
 	opts := []influxdb.DocumentOptions{}
 	if req.OrgID.Valid() {
@@ -212,6 +214,7 @@ func (h *DocumentHandler) handleGetDocuments(w http.ResponseWriter, r *http.Requ
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	a = authorizer.WithRoles(ctx, a)
 
 	opts := []influxdb.DocumentFindOptions{influxdb.IncludeLabels}
 	if req.Org != "" && req.OrgID != nil {
@@ -383,6 +386,7 @@ func (h *DocumentHandler) getDocument(w http.ResponseWriter, r *http.Request) (*
 	if err != nil {
 		return nil, "", err
 	}
+	a = authorizer.WithRoles(ctx, a)
 	ds, err := s.FindDocuments(ctx, influxdb.AuthorizedWhereID(a, req.ID), influxdb.IncludeContent, influxdb.IncludeLabels)
 	if err != nil {
 		return nil, "", err
@@ -474,6 +478,7 @@ func (h *DocumentHandler) handleDeleteDocument(w http.ResponseWriter, r *http.Re
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	a = authorizer.WithRoles(ctx, a)
 
 	if err := s.DeleteDocuments(ctx, influxdb.AuthorizedWhereID(a, req.ID)); err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -544,6 +549,7 @@ func (h *DocumentHandler) handlePutDocument(w http.ResponseWriter, r *http.Reque
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	a = authorizer.WithRoles(ctx, a)
 
 	if err := s.UpdateDocument(ctx, req.Document, influxdb.Authorized(a)); err != nil {
 		h.HandleHTTPError(ctx, err, w)
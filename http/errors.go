@@ -7,8 +7,10 @@ import (
 	stderrors "errors"
 	"fmt"
 	"io"
+	"math"
 	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
 	platform "github.com/influxdata/influxdb"
@@ -113,6 +115,7 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	}
 	w.Header().Set(PlatformErrorCodeHeader, code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	setRetryAfter(w, err)
 	w.WriteHeader(httpCode)
 	var e error
 	if pe, ok := err.(*platform.Error); ok {
@@ -132,6 +135,20 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	_, _ = w.Write(b)
 }
 
+// setRetryAfter sets the Retry-After header, in whole seconds, of the responses
+// to requests rejected because of a limit.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	d, ok := platform.RetryAfter(err)
+	if !ok {
+		return
+	}
+	secs := int64(math.Ceil(d.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+}
+
 // UnauthorizedError encodes a error message and status code for unauthorized access.
 func UnauthorizedError(ctx context.Context, h platform.HTTPErrorHandler, w http.ResponseWriter) {
 	h.HandleHTTPError(ctx, &platform.Error{
@@ -147,6 +147,10 @@ func decodeGetLabelsRequest(ctx context.Context, r *http.Request) (*getLabelsReq
 		req.filter.OrgID = id
 	}
 
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = name
+	}
+
 	return req, nil
 }
 
@@ -567,8 +571,44 @@ func (s *LabelService) FindLabelByID(ctx context.Context, id influxdb.ID) (*infl
 	return &lr.Label, nil
 }
 
+// FindLabels returns a list of labels that match filter.
 func (s *LabelService) FindLabels(ctx context.Context, filter influxdb.LabelFilter, opt ...influxdb.FindOptions) ([]*influxdb.Label, error) {
-	return nil, nil
+	u, err := NewURL(s.Addr, labelsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Set("orgID", filter.OrgID.String())
+	}
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var lr labelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lr); err != nil {
+		return nil, err
+	}
+	return lr.Labels, nil
 }
 
 // FindResourceLabels returns a list of labels, derived from a label mapping filter.
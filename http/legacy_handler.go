@@ -0,0 +1,588 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+const (
+	legacyQueryPath = "/query"
+	legacyWritePath = "/write"
+	legacyPingPath  = "/ping"
+
+	// legacyDefaultChunkSize is the number of values of a series in each chunk
+	// of a chunked response when chunk_size is not specified.
+	legacyDefaultChunkSize = 10000
+)
+
+// LegacyBackend is all services and associated parameters required to construct
+// the LegacyHandler.
+type LegacyBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger             *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	AuthorizationService influxdb.AuthorizationService
+	DBRPMappingService   influxdb.DBRPMappingService
+	PointsWriter         storage.PointsWriter
+	WriteLimiter         WriteLimiter
+	ProxyQueryService    query.ProxyQueryService
+	RoleResolver         *authorizer.RoleResolver
+}
+
+// NewLegacyBackend returns a new instance of LegacyBackend. The roles of the
+// requests are resolved with rr, only the permissions of their authorizations
+// are granted when it is nil.
+func NewLegacyBackend(b *APIBackend, rr *authorizer.RoleResolver) *LegacyBackend {
+	return &LegacyBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Logger:             b.Logger.With(zap.String("handler", "legacy")),
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		AuthorizationService: b.AuthorizationService,
+		DBRPMappingService:   b.DBRPMappingService,
+		PointsWriter:         b.PointsWriter,
+		WriteLimiter:         b.WriteLimiter,
+		ProxyQueryService:    b.FluxService,
+		RoleResolver:         rr,
+	}
+}
+
+// LegacyHandler serves the InfluxDB 1.x compatible /query, /write and /ping
+// endpoints. Databases and retention policies are resolved to buckets through
+// the dbrp mappings.
+//
+// The handler authenticates requests itself, as 1.x clients pass the token as
+// the password of the p parameter or of basic authentication, and reports
+// errors in the 1.x format.
+type LegacyHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AuthorizationService influxdb.AuthorizationService
+	DBRPMappingService   influxdb.DBRPMappingService
+	PointsWriter         storage.PointsWriter
+	WriteLimiter         WriteLimiter
+	ProxyQueryService    query.ProxyQueryService
+
+	// RoleResolver resolves the roles of the authorizations of the requests,
+	// only their permissions are granted when it is nil.
+	RoleResolver *authorizer.RoleResolver
+
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+}
+
+// NewLegacyHandler returns a new handler for the 1.x compatible endpoints.
+func NewLegacyHandler(b *LegacyBackend) *LegacyHandler {
+	h := &LegacyHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		AuthorizationService: b.AuthorizationService,
+		DBRPMappingService:   b.DBRPMappingService,
+		PointsWriter:         b.PointsWriter,
+		WriteLimiter:         b.WriteLimiter,
+		ProxyQueryService:    b.ProxyQueryService,
+		RoleResolver:         b.RoleResolver,
+
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+	}
+
+	h.HandlerFunc("GET", legacyQueryPath, h.handleQuery)
+	h.HandlerFunc("POST", legacyQueryPath, h.handleQuery)
+	h.HandlerFunc("POST", legacyWritePath, h.handleWrite)
+	h.HandlerFunc("GET", legacyPingPath, h.handlePing)
+	h.HandlerFunc("HEAD", legacyPingPath, h.handlePing)
+	return h
+}
+
+// handlePing is the HTTP handler for the GET and HEAD /ping routes.
+func (h *LegacyHandler) handlePing(w http.ResponseWriter, r *http.Request) {
+	info := influxdb.GetBuildInfo()
+	w.Header().Set("X-Influxdb-Build", "OSS")
+	w.Header().Set("X-Influxdb-Version", info.Version)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleQuery is the HTTP handler for the GET and POST /query routes.
+func (h *LegacyHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	var orgID influxdb.ID
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.QueryEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	a, err := h.authorize(ctx, r)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+	ctx, err = h.setAuthorizer(ctx, a)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLegacyQueryRequest(r)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+
+	orgID, err = h.findQueryOrgID(ctx, a, req.DB, req.RP)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+
+	compiler := influxql.NewCompiler(authorizer.NewDBRPMappingService(h.DBRPMappingService))
+	compiler.Cluster = influxdb.DefaultDBRPCluster
+	compiler.DB = req.DB
+	compiler.RP = req.RP
+	compiler.Query = req.Query
+
+	dialect := &influxql.Dialect{
+		TimeFormat: req.TimeFormat,
+		Encoding:   influxql.JSON,
+		ChunkSize:  req.ChunkSize,
+	}
+	preq := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  a,
+			OrganizationID: orgID,
+			Compiler:       compiler,
+		},
+		Dialect: dialect,
+	}
+
+	dialect.SetHeaders(w)
+
+	var out io.Writer = w
+	if req.ChunkSize > 0 {
+		// Each chunk is sent to the client as soon as it is encoded.
+		out = &flushWriter{w: w}
+	}
+	cw := iocounter.Writer{Writer: out}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, preq); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.handleLegacyError(ctx, err, w)
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "legacy"),
+			zap.Error(err),
+		)
+	}
+}
+
+// findQueryOrgID returns the organization a query is run as. This is the
+// organization of the mapping of the default database of the query if it
+// has one, and the organization of the authorization otherwise.
+func (h *LegacyHandler) findQueryOrgID(ctx context.Context, a *influxdb.Authorization, db, rp string) (influxdb.ID, error) {
+	if db == "" {
+		return a.OrgID, nil
+	}
+
+	cluster := influxdb.DefaultDBRPCluster
+	filter := influxdb.DBRPMappingFilter{
+		Cluster:  &cluster,
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+
+	m, err := h.DBRPMappingService.Find(ctx, filter)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return a.OrgID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return m.OrganizationID, nil
+}
+
+type legacyQueryRequest struct {
+	Query      string
+	DB         string
+	RP         string
+	TimeFormat influxql.TimeFormat
+	ChunkSize  int
+}
+
+func decodeLegacyQueryRequest(r *http.Request) (*legacyQueryRequest, error) {
+	req := &legacyQueryRequest{
+		Query: r.FormValue("q"),
+		DB:    r.FormValue("db"),
+		RP:    r.FormValue("rp"),
+	}
+	if req.Query == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  `missing required parameter "q"`,
+		}
+	}
+
+	tf, err := legacyTimeFormat(r.FormValue("epoch"))
+	if err != nil {
+		return nil, err
+	}
+	req.TimeFormat = tf
+
+	if r.FormValue("chunked") == "true" {
+		req.ChunkSize = legacyDefaultChunkSize
+		if s := r.FormValue("chunk_size"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "chunk_size must be a positive integer",
+				}
+			}
+			req.ChunkSize = n
+		}
+	}
+	return req, nil
+}
+
+// legacyTimeFormat returns the format of the timestamps for the epoch
+// parameter of a query.
+func legacyTimeFormat(epoch string) (influxql.TimeFormat, error) {
+	switch epoch {
+	case "":
+		return influxql.RFC3339Nano, nil
+	case "h":
+		return influxql.Hour, nil
+	case "m":
+		return influxql.Minute, nil
+	case "s":
+		return influxql.Second, nil
+	case "ms":
+		return influxql.Millisecond, nil
+	case "u", "µ":
+		return influxql.Microsecond, nil
+	case "n", "ns":
+		return influxql.Nanosecond, nil
+	default:
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch %q; valid epochs are h, m, s, ms, u and ns", epoch),
+		}
+	}
+}
+
+// handleWrite is the HTTP handler for the POST /write route.
+func (h *LegacyHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	var requestBytes int
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.WriteEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	a, err := h.authorize(ctx, r)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+	ctx, err = h.setAuthorizer(ctx, a)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+
+	qp := r.URL.Query()
+	db, rp := qp.Get("db"), qp.Get("rp")
+	if db == "" {
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "database is required",
+		}, w)
+		return
+	}
+
+	precision, err := legacyPrecision(qp.Get("precision"))
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+
+	m, err := h.findWriteMapping(ctx, db, rp)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+	orgID = m.OrganizationID
+
+	p, err := influxdb.NewPermissionAtID(m.BucketID, influxdb.WriteAction, influxdb.BucketsResourceType, m.OrganizationID)
+	if err != nil {
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if !authorizer.Allowed(ctx, a, *p) {
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "insufficient permissions for write",
+		}, w)
+		return
+	}
+
+	in := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			h.handleLegacyError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			}, w)
+			return
+		}
+		defer in.Close()
+	}
+
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+	requestBytes = len(data)
+
+	release := func() {}
+	if h.WriteLimiter != nil {
+		if release, err = h.WriteLimiter.ReserveWriteBytes(m.OrganizationID, len(data)); err != nil {
+			h.handleLegacyError(ctx, err, w)
+			return
+		}
+	}
+
+	logger := h.Logger.With(zap.String("db", db), zap.String("rp", m.RetentionPolicy))
+
+	encoded := tsdb.EncodeName(m.OrganizationID, m.BucketID)
+	mm := models.EscapeMeasurement(encoded[:])
+	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), precision)
+	if err != nil {
+		logger.Error("Error parsing points", zap.Error(err))
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unable to parse points: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if h.WriteLimiter != nil {
+		if err := h.WriteLimiter.AllowWritePoints(m.OrganizationID, len(points)); err != nil {
+			// The request is not written, so its bytes are given back.
+			release()
+			h.handleLegacyError(ctx, err, w)
+			return
+		}
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		logger.Error("Error writing points", zap.Error(err))
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: writeErrorCode(err),
+			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findWriteMapping returns the mapping of the database and retention policy
+// of a write. The default mapping of the database is used if rp is empty.
+func (h *LegacyHandler) findWriteMapping(ctx context.Context, db, rp string) (*influxdb.DBRPMapping, error) {
+	cluster := influxdb.DefaultDBRPCluster
+	filter := influxdb.DBRPMappingFilter{
+		Cluster:  &cluster,
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		def := true
+		filter.Default = &def
+	}
+
+	m, err := h.DBRPMappingService.Find(ctx, filter)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		if rp != "" {
+			return nil, &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  fmt.Sprintf("retention policy not found: %q", rp),
+			}
+		}
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("database not found: %q", db),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// legacyPrecision returns the line protocol precision for the precision
+// parameter of a write.
+func legacyPrecision(p string) (string, error) {
+	switch p {
+	case "", "n", "ns":
+		return "ns", nil
+	case "u", "µ", "us":
+		return "us", nil
+	case "ms", "s", "m", "h":
+		return p, nil
+	default:
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid precision %q; valid precisions are n, u, ms, s, m and h", p),
+		}
+	}
+}
+
+// authorize finds the authorization of the token of a request. The token is
+// the password of the p parameter or of basic authentication, or is passed in
+// the Authorization header with the Token scheme. The username is ignored.
+func (h *LegacyHandler) authorize(ctx context.Context, r *http.Request) (*influxdb.Authorization, error) {
+	token, err := GetToken(r)
+	if err != nil {
+		if _, p, ok := r.BasicAuth(); ok {
+			token = p
+		} else {
+			token = r.URL.Query().Get("p")
+		}
+	}
+
+	if token == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "unable to parse authentication credentials",
+		}
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization failed",
+			Err:  err,
+		}
+	}
+	return a, nil
+}
+
+// setAuthorizer sets a on ctx along with its roles.
+func (h *LegacyHandler) setAuthorizer(ctx context.Context, a *influxdb.Authorization) (context.Context, error) {
+	if h.RoleResolver != nil {
+		var err error
+		if ctx, err = h.RoleResolver.Resolve(ctx, a); err != nil {
+			return ctx, err
+		}
+	}
+	return pcontext.SetAuthorizer(ctx, a), nil
+}
+
+// handleLegacyError writes err in the 1.x error format.
+func (h *LegacyHandler) handleLegacyError(ctx context.Context, err error, w http.ResponseWriter) {
+	code := influxdb.ErrorCode(err)
+	httpCode, ok := statusCodePlatformError[code]
+	if !ok {
+		httpCode = http.StatusBadRequest
+	}
+
+	if httpCode == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="InfluxDB"`)
+	}
+	setRetryAfter(w, err)
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(httpCode)
+
+	msg := influxdb.ErrorMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+	if err := json.NewEncoder(w).Encode(influxql.Response{Err: msg}); err != nil {
+		h.Logger.Info("Error writing response to client", zap.Error(err))
+	}
+}
+
+// flushWriter flushes the underlying http.ResponseWriter after each write.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
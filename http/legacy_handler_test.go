@@ -0,0 +1,228 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+// NewMockLegacyBackend returns a LegacyBackend with mock services.
+func NewMockLegacyBackend() *LegacyBackend {
+	return &LegacyBackend{
+		Logger:             zap.NewNop().With(zap.String("handler", "legacy")),
+		WriteEventRecorder: noopEventRecorder{},
+		QueryEventRecorder: noopEventRecorder{},
+
+		AuthorizationService: mock.NewAuthorizationService(),
+		DBRPMappingService:   mock.NewDBRPMappingService(),
+		PointsWriter:         &mock.PointsWriter{},
+	}
+}
+
+func TestLegacyHandler_handlePing(t *testing.T) {
+	h := NewLegacyHandler(NewMockLegacyBackend())
+
+	for _, method := range []string{"GET", "HEAD"} {
+		r := httptest.NewRequest(method, "http://localhost:9999/ping", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		res := w.Result()
+		if got, exp := res.StatusCode, http.StatusNoContent; got != exp {
+			t.Errorf("%s: unexpected status code: got %d, exp %d", method, got, exp)
+		}
+		if _, ok := res.Header["X-Influxdb-Version"]; !ok {
+			t.Errorf("%s: expected X-Influxdb-Version header", method)
+		}
+	}
+}
+
+func TestLegacyHandler_handleWrite(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+		times      []int64
+	}
+
+	const token = "token"
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name  string
+		url   string
+		auth  func(r *http.Request)
+		perms []platform.Permission
+		roles []platform.ID
+		wants wants
+	}{
+		{
+			name: "token in p parameter",
+			url:  "/write?db=db0&precision=s&u=user&p=" + token,
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+			},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				times:      []int64{1000000000},
+			},
+		},
+		{
+			name: "token in basic authentication",
+			url:  "/write?db=db0&rp=autogen&precision=m",
+			auth: func(r *http.Request) { r.SetBasicAuth("user", token) },
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID}},
+			},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				times:      []int64{60000000000},
+			},
+		},
+		{
+			name: "token in authorization header",
+			url:  "/write?db=db0",
+			auth: func(r *http.Request) { SetToken(token, r) },
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID}},
+			},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				times:      []int64{1},
+			},
+		},
+		{
+			name:  "token with a role",
+			url:   "/write?db=db0&p=" + token,
+			roles: []platform.ID{3},
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				times:      []int64{1},
+			},
+		},
+		{
+			name: "missing credentials",
+			url:  "/write?db=db0",
+			wants: wants{
+				statusCode: http.StatusUnauthorized,
+				body:       `{"error":"unable to parse authentication credentials"}`,
+			},
+		},
+		{
+			name: "invalid token",
+			url:  "/write?db=db0&p=invalid",
+			wants: wants{
+				statusCode: http.StatusUnauthorized,
+				body:       `{"error":"authorization failed"}`,
+			},
+		},
+		{
+			name: "unknown database",
+			url:  "/write?db=db1&p=" + token,
+			wants: wants{
+				statusCode: http.StatusNotFound,
+				body:       `{"error":"database not found: \"db1\""}`,
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			url:  "/write?db=db0&p=" + token,
+			perms: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID}},
+			},
+			wants: wants{
+				statusCode: http.StatusForbidden,
+				body:       `{"error":"insufficient permissions for write"}`,
+			},
+		},
+		{
+			name: "invalid precision",
+			url:  "/write?db=db0&precision=d&p=" + token,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"error":"invalid precision \"d\"; valid precisions are n, u, ms, s, m and h"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			legacyBackend := NewMockLegacyBackend()
+			legacyBackend.HTTPErrorHandler = ErrorHandler(0)
+			legacyBackend.PointsWriter = pw
+			legacyBackend.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, t string) (*platform.Authorization, error) {
+					if t != token {
+						return nil, &platform.Error{Code: platform.ENotFound, Msg: "authorization not found"}
+					}
+					return &platform.Authorization{ID: 1, Token: token, OrgID: orgID, Status: platform.Active, Permissions: tt.perms, RoleIDs: tt.roles}, nil
+				},
+			}
+			legacyBackend.RoleResolver = &authorizer.RoleResolver{
+				RoleService: &mock.RoleService{
+					FindRoleByIDFn: func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+						return &platform.Role{
+							ID:    id,
+							OrgID: orgID,
+							Name:  "bucket-writer",
+							Permissions: []platform.RolePermission{
+								{Action: platform.WriteAction, Resource: platform.RoleResource{Type: platform.BucketsResourceType}},
+							},
+						}, nil
+					},
+				},
+			}
+			legacyBackend.DBRPMappingService = &mock.DBRPMappingService{
+				FindFn: func(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+					if *filter.Database != "db0" {
+						return nil, &platform.Error{Code: platform.ENotFound, Msg: "dbrp mapping not found"}
+					}
+					return &platform.DBRPMapping{
+						Cluster:         *filter.Cluster,
+						Database:        "db0",
+						RetentionPolicy: "autogen",
+						Default:         true,
+						OrganizationID:  orgID,
+						BucketID:        bucketID,
+					}, nil
+				},
+			}
+			h := NewLegacyHandler(legacyBackend)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999"+tt.url, strings.NewReader("m,k=v f=1i 1"))
+			if tt.auth != nil {
+				tt.auth(r)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if got, exp := res.StatusCode, tt.wants.statusCode; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if tt.wants.body != "" {
+				if got, exp := strings.TrimSpace(string(body)), tt.wants.body; got != exp {
+					t.Errorf("unexpected body: got %s, exp %s", got, exp)
+				}
+			}
+
+			if got, exp := len(pw.Points), len(tt.wants.times); got != exp {
+				t.Fatalf("unexpected number of points: got %d, exp %d", got, exp)
+			}
+			for i, p := range pw.Points {
+				if got, exp := p.Time().UnixNano(), tt.wants.times[i]; got != exp {
+					t.Errorf("unexpected time of point %d: got %d, exp %d", i, got, exp)
+				}
+			}
+		})
+	}
+}
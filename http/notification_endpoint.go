@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
@@ -280,7 +282,7 @@ func decodeNotificationEndpointFilter(ctx context.Context, r *http.Request) (*in
 		}
 		f.OrgID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Org = orgNameStr
+		f.Org = &orgNameStr
 	}
 	return f, opts, err
 }
@@ -534,3 +536,100 @@ func (h *NotificationEndpointHandler) handleDeleteNotificationEndpoint(w http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// NotificationEndpointService connects to Influx via HTTP using tokens to manage notification endpoints.
+type NotificationEndpointService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "GET", notificationEndpointIDPath(id), nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(b)
+}
+
+// FindNotificationEndpoints returns a list of notification endpoints that match filter and the total count of matching notification endpoints.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationEndpoint, int, error) {
+	query := filter.QueryParams()
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			query[k] = vs
+		}
+	}
+
+	var res struct {
+		NotificationEndpoints []json.RawMessage `json:"notificationEndpoints"`
+	}
+	if err := s.do(ctx, "GET", notificationEndpointsPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	edps := make([]influxdb.NotificationEndpoint, 0, len(res.NotificationEndpoints))
+	for _, b := range res.NotificationEndpoints {
+		edp, err := endpoint.UnmarshalJSON(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		edps = append(edps, edp)
+	}
+	return edps, len(edps), nil
+}
+
+// CreateNotificationEndpoint creates a new notification endpoint and sets its ID with the new identifier.
+// The notification endpoint is owned by the user of the token, so userID is ignored.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, ne influxdb.NotificationEndpoint, userID influxdb.ID) error {
+	var b json.RawMessage
+	if err := s.do(ctx, "POST", notificationEndpointsPath, nil, ne, &b); err != nil {
+		return err
+	}
+
+	created, err := endpoint.UnmarshalJSON(b)
+	if err != nil {
+		return err
+	}
+	ne.SetID(created.GetID())
+	return nil
+}
+
+// UpdateNotificationEndpoint updates a single notification endpoint.
+// Returns the new notification endpoint after update.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id influxdb.ID, ne influxdb.NotificationEndpoint, userID influxdb.ID) (influxdb.NotificationEndpoint, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PUT", notificationEndpointIDPath(id), nil, ne, &b); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(b)
+}
+
+// PatchNotificationEndpoint updates a single notification endpoint with changeset.
+// Returns the new notification endpoint after update.
+func (s *NotificationEndpointService) PatchNotificationEndpoint(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (influxdb.NotificationEndpoint, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PATCH", notificationEndpointIDPath(id), nil, upd, &b); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(b)
+}
+
+// DeleteNotificationEndpoint removes a notification endpoint by ID.
+// The server removes the secrets of the notification endpoint itself,
+// so no secret fields are returned.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+	if err := s.do(ctx, "DELETE", notificationEndpointIDPath(id), nil, nil, nil); err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, nil
+}
+
+func (s *NotificationEndpointService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
+
+func notificationEndpointIDPath(id influxdb.ID) string {
+	return path.Join(notificationEndpointsPath, id.String())
+}
@@ -1169,3 +1169,100 @@ func TestService_handlePostNotificationEndpointOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationEndpointService_Client(t *testing.T) {
+	orgID := influxTesting.MustIDBase16("50f7ba1150f7ba11")
+	edpID := influxTesting.MustIDBase16("0b501e7e557ab1ed")
+
+	var stored influxdb.NotificationEndpoint
+	backend := NewMockNotificationEndpointBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	backend.NotificationEndpointService = &mock.NotificationEndpointService{
+		CreateNotificationEndpointF: func(ctx context.Context, edp influxdb.NotificationEndpoint, userID influxdb.ID) error {
+			edp.SetID(edpID)
+			stored = edp
+			return nil
+		},
+		FindNotificationEndpointByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+			if stored == nil || id != stored.GetID() {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification endpoint not found"}
+			}
+			return stored, nil
+		},
+		FindNotificationEndpointsF: func(ctx context.Context, f influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationEndpoint, int, error) {
+			return []influxdb.NotificationEndpoint{stored}, 1, nil
+		},
+		PatchNotificationEndpointF: func(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (influxdb.NotificationEndpoint, error) {
+			if upd.Name != nil {
+				stored.SetName(*upd.Name)
+			}
+			return stored, nil
+		},
+		DeleteNotificationEndpointF: func(ctx context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+			flds := stored.SecretFields()
+			stored = nil
+			return flds, orgID, nil
+		},
+	}
+	backend.SecretService = &mock.SecretService{
+		PutSecretFn: func(ctx context.Context, orgID influxdb.ID, k string, v string) error {
+			return nil
+		},
+		DeleteSecretFn: func(ctx context.Context, orgID influxdb.ID, ks ...string) error {
+			return nil
+		},
+	}
+	server := newAuthorizedTestServer(NewNotificationEndpointHandler(backend), 1)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &NotificationEndpointService{Addr: server.URL}
+	url := "http://example.com"
+	edp := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name:   "hello",
+			OrgID:  orgID,
+			Status: influxdb.Active,
+		},
+		URL:   url,
+		Token: influxdb.SecretField{Value: &url},
+	}
+	if err := client.CreateNotificationEndpoint(ctx, edp, 0); err != nil {
+		t.Fatalf("unable to create notification endpoint: %v", err)
+	}
+	if edp.ID != edpID {
+		t.Fatalf("unexpected notification endpoint id: got %s, exp %s", edp.ID, edpID)
+	}
+
+	found, err := client.FindNotificationEndpointByID(ctx, edpID)
+	if err != nil {
+		t.Fatalf("unable to find notification endpoint: %v", err)
+	}
+	if got, ok := found.(*endpoint.Slack); !ok || got.URL != url || got.Name != "hello" {
+		t.Errorf("unexpected notification endpoint: %+v", found)
+	}
+
+	edps, n, err := client.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("unable to find notification endpoints: %v", err)
+	}
+	if n != 1 || edps[0].GetID() != edpID {
+		t.Errorf("unexpected notification endpoints: %+v", edps)
+	}
+
+	name := "world"
+	patched, err := client.PatchNotificationEndpoint(ctx, edpID, influxdb.NotificationEndpointUpdate{Name: &name})
+	if err != nil {
+		t.Fatalf("unable to patch notification endpoint: %v", err)
+	}
+	if patched.GetName() != name {
+		t.Errorf("unexpected patched notification endpoint: %+v", patched)
+	}
+
+	if _, _, err := client.DeleteNotificationEndpoint(ctx, edpID); err != nil {
+		t.Fatalf("unable to delete notification endpoint: %v", err)
+	}
+	if _, err := client.FindNotificationEndpointByID(ctx, edpID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected deleted notification endpoint to be not found, got %v", err)
+	}
+}
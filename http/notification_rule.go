@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
@@ -320,7 +322,7 @@ func decodeNotificationRuleFilter(ctx context.Context, r *http.Request) (*influx
 		}
 		f.OrgID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Organization = orgNameStr
+		f.Organization = &orgNameStr
 	}
 
 	for _, tag := range q["tag"] {
@@ -561,3 +563,95 @@ func (h *NotificationRuleHandler) handleDeleteNotificationRule(w http.ResponseWr
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// NotificationRuleService connects to Influx via HTTP using tokens to manage notification rules.
+type NotificationRuleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "GET", notificationRuleIDPath(id), nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(b)
+}
+
+// FindNotificationRules returns a list of notification rules that match filter and the total count of matching notification rules.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+	query := filter.QueryParams()
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			query[k] = vs
+		}
+	}
+
+	var res struct {
+		NotificationRules []json.RawMessage `json:"notificationRules"`
+	}
+	if err := s.do(ctx, "GET", notificationRulesPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	nrs := make([]influxdb.NotificationRule, 0, len(res.NotificationRules))
+	for _, b := range res.NotificationRules {
+		nr, err := rule.UnmarshalJSON(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		nrs = append(nrs, nr)
+	}
+	return nrs, len(nrs), nil
+}
+
+// CreateNotificationRule creates a new notification rule and sets its ID with the new identifier.
+// The notification rule is owned by the user of the token, so userID is ignored.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, nr influxdb.NotificationRule, userID influxdb.ID) error {
+	var b json.RawMessage
+	if err := s.do(ctx, "POST", notificationRulesPath, nil, nr, &b); err != nil {
+		return err
+	}
+
+	created, err := rule.UnmarshalJSON(b)
+	if err != nil {
+		return err
+	}
+	nr.SetID(created.GetID())
+	return nil
+}
+
+// UpdateNotificationRule updates a single notification rule.
+// Returns the new notification rule after update.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id influxdb.ID, nr influxdb.NotificationRule, userID influxdb.ID) (influxdb.NotificationRule, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PUT", notificationRuleIDPath(id), nil, nr, &b); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(b)
+}
+
+// PatchNotificationRule updates a single notification rule with changeset.
+// Returns the new notification rule after update.
+func (s *NotificationRuleService) PatchNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (influxdb.NotificationRule, error) {
+	var b json.RawMessage
+	if err := s.do(ctx, "PATCH", notificationRuleIDPath(id), nil, upd, &b); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(b)
+}
+
+// DeleteNotificationRule removes a notification rule by ID.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id influxdb.ID) error {
+	return s.do(ctx, "DELETE", notificationRuleIDPath(id), nil, nil, nil)
+}
+
+func (s *NotificationRuleService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
+
+func notificationRuleIDPath(id influxdb.ID) string {
+	return path.Join(notificationRulesPath, id.String())
+}
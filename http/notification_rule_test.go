@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/notification/rule"
 	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
 )
 
 func Test_newNotificationRuleResponses(t *testing.T) {
@@ -283,3 +284,106 @@ func Test_newNotificationRuleResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationRuleService_Client(t *testing.T) {
+	orgID := influxTesting.MustIDBase16("6f626f7274697320")
+	ruleID := influxTesting.MustIDBase16("020f755c3c082000")
+
+	var stored influxdb.NotificationRule
+	var filter influxdb.NotificationRuleFilter
+	store := &mock.NotificationRuleStore{
+		CreateNotificationRuleF: func(ctx context.Context, nr influxdb.NotificationRule, userID influxdb.ID) error {
+			nr.SetID(ruleID)
+			nr.SetOwnerID(userID)
+			stored = nr
+			return nil
+		},
+		FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+			if stored == nil || id != stored.GetID() {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification rule not found"}
+			}
+			return stored, nil
+		},
+		FindNotificationRulesF: func(ctx context.Context, f influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+			filter = f
+			return []influxdb.NotificationRule{stored}, 1, nil
+		},
+		PatchNotificationRuleF: func(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (influxdb.NotificationRule, error) {
+			if upd.Status != nil {
+				stored.SetStatus(*upd.Status)
+			}
+			return stored, nil
+		},
+		DeleteNotificationRuleF: func(ctx context.Context, id influxdb.ID) error {
+			stored = nil
+			return nil
+		},
+	}
+	handler := NewNotificationRuleHandler(&NotificationRuleBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+
+		NotificationRuleStore:       store,
+		NotificationEndpointService: &mock.NotificationEndpointService{},
+		UserResourceMappingService:  mock.NewUserResourceMappingService(),
+		LabelService:                mock.NewLabelService(),
+		UserService:                 mock.NewUserService(),
+		OrganizationService:         mock.NewOrganizationService(),
+	})
+	server := newAuthorizedTestServer(handler, 1)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &NotificationRuleService{Addr: server.URL}
+	nr := &rule.Slack{
+		Channel:         "ch1",
+		MessageTemplate: "message",
+		Base: rule.Base{
+			OrgID:      orgID,
+			EndpointID: 4,
+			Name:       "name1",
+			Status:     influxdb.Active,
+			Every:      mustDuration("5m"),
+		},
+	}
+	if err := client.CreateNotificationRule(ctx, nr, 0); err != nil {
+		t.Fatalf("unable to create notification rule: %v", err)
+	}
+	if nr.ID != ruleID {
+		t.Fatalf("unexpected notification rule id: got %s, exp %s", nr.ID, ruleID)
+	}
+
+	found, err := client.FindNotificationRuleByID(ctx, ruleID)
+	if err != nil {
+		t.Fatalf("unable to find notification rule: %v", err)
+	}
+	if got, ok := found.(*rule.Slack); !ok || got.Channel != "ch1" || got.Name != "name1" {
+		t.Errorf("unexpected notification rule: %+v", found)
+	}
+
+	nrs, n, err := client.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("unable to find notification rules: %v", err)
+	}
+	if n != 1 || nrs[0].GetID() != ruleID {
+		t.Errorf("unexpected notification rules: %+v", nrs)
+	}
+	if filter.OrgID == nil || *filter.OrgID != orgID {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+
+	patched, err := client.PatchNotificationRule(ctx, ruleID, influxdb.NotificationRuleUpdate{Status: influxdb.Inactive.Ptr()})
+	if err != nil {
+		t.Fatalf("unable to patch notification rule: %v", err)
+	}
+	if patched.GetStatus() != influxdb.Inactive {
+		t.Errorf("unexpected patched notification rule: %+v", patched)
+	}
+
+	if err := client.DeleteNotificationRule(ctx, ruleID); err != nil {
+		t.Fatalf("unable to delete notification rule: %v", err)
+	}
+	if _, err := client.FindNotificationRuleByID(ctx, ruleID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected deleted notification rule to be not found, got %v", err)
+	}
+}
@@ -894,3 +894,72 @@ func newOrganizationLogResponse(id influxdb.ID, es []*influxdb.OperationLogEntry
 		Logs: logs,
 	}
 }
+
+// SecretService connects to Influx via HTTP using tokens to manage the secrets of organizations.
+type SecretService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// LoadSecret is not supported over HTTP; secret values are never returned by the API.
+func (s *SecretService) LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+	return "", &influxdb.Error{
+		Code: influxdb.EMethodNotAllowed,
+		Op:   "http/LoadSecret",
+		Msg:  "secret values cannot be read over HTTP",
+	}
+}
+
+// GetSecretKeys returns the keys of all secrets of the organization.
+func (s *SecretService) GetSecretKeys(ctx context.Context, orgID influxdb.ID) ([]string, error) {
+	var res secretsResponse
+	if err := s.do(ctx, "GET", secretsPath(orgID), nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Secrets, nil
+}
+
+// PutSecret stores the secret pair (k,v) for the organization.
+func (s *SecretService) PutSecret(ctx context.Context, orgID influxdb.ID, k string, v string) error {
+	return s.PatchSecrets(ctx, orgID, map[string]string{k: v})
+}
+
+// PutSecrets stores all provided secrets for the organization and removes all others.
+func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	ks, err := s.GetSecretKeys(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for _, k := range ks {
+		if _, ok := m[k]; !ok {
+			stale = append(stale, k)
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.DeleteSecret(ctx, orgID, stale...); err != nil {
+			return err
+		}
+	}
+	return s.PatchSecrets(ctx, orgID, m)
+}
+
+// PatchSecrets stores all provided secrets for the organization and updates any previous values.
+func (s *SecretService) PatchSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	return s.do(ctx, "PATCH", secretsPath(orgID), m, nil)
+}
+
+// DeleteSecret removes the secrets with the given keys from the organization.
+func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, ks ...string) error {
+	return s.do(ctx, "POST", path.Join(secretsPath(orgID), "delete"), deleteSecretsRequest{Secrets: ks}, nil)
+}
+
+func (s *SecretService) do(ctx context.Context, method, path string, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, nil, body, v)
+}
+
+func secretsPath(orgID influxdb.ID) string {
+	return path.Join(organizationIDPath(orgID), "secrets")
+}
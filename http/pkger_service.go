@@ -0,0 +1,260 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkger"
+)
+
+// PkgerBackend is all services and associated parameters required to construct
+// the PkgerHandler.
+type PkgerBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PkgerService pkger.SVC
+}
+
+// NewPkgerBackend returns a new instance of PkgerBackend. Its PkgerService
+// must be set to a service built on authorized resource services.
+func NewPkgerBackend(b *APIBackend) *PkgerBackend {
+	return &PkgerBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "pkger")),
+	}
+}
+
+// PkgerHandler exports the resources of organizations as packages and applies
+// packages to organizations.
+type PkgerHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PkgerService pkger.SVC
+}
+
+const (
+	pkgerPath      = "/api/v2/packages"
+	pkgerApplyPath = "/api/v2/packages/apply"
+)
+
+// NewPkgerHandler returns a new instance of PkgerHandler.
+func NewPkgerHandler(b *PkgerBackend) *PkgerHandler {
+	h := &PkgerHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		PkgerService: b.PkgerService,
+	}
+
+	h.HandlerFunc("POST", pkgerPath, h.handlePostPkg)
+	h.HandlerFunc("POST", pkgerApplyPath, h.handleApplyPkg)
+	return h
+}
+
+// postPkgRequest is the JSON body of a request to create a package.
+type postPkgRequest struct {
+	OrgID       influxdb.ID `json:"orgID"`
+	Name        string      `json:"pkgName"`
+	Version     string      `json:"pkgVersion"`
+	Description string      `json:"description,omitempty"`
+}
+
+// handlePostPkg is the HTTP handler for the POST /api/v2/packages route.
+func (h *PkgerHandler) handlePostPkg(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PkgerHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	var req postPkgRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handlePostPkg",
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}, w)
+		return
+	}
+	if !req.OrgID.Valid() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handlePostPkg",
+			Msg:  "orgID is required",
+		}, w)
+		return
+	}
+
+	pkg, err := h.PkgerService.CreatePkg(ctx, req.OrgID, pkger.Metadata{
+		Name:        req.Name,
+		Version:     req.Version,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, pkg); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// applyPkgRequest is the JSON body of a request to apply a package.
+type applyPkgRequest struct {
+	OrgID   influxdb.ID `json:"orgID"`
+	DryRun  bool        `json:"dryRun"`
+	Package *pkger.Pkg  `json:"package"`
+}
+
+// applyPkgResponse is the JSON body of the response to a request to apply a package.
+type applyPkgResponse struct {
+	Diff pkger.Diff `json:"diff"`
+}
+
+// handleApplyPkg is the HTTP handler for the POST /api/v2/packages/apply route.
+func (h *PkgerHandler) handleApplyPkg(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PkgerHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	var req applyPkgRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleApplyPkg",
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}, w)
+		return
+	}
+	if !req.OrgID.Valid() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleApplyPkg",
+			Msg:  "orgID is required",
+		}, w)
+		return
+	}
+	if req.Package == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleApplyPkg",
+			Msg:  "package is required",
+		}, w)
+		return
+	}
+
+	var (
+		diff pkger.Diff
+		err  error
+	)
+	if req.DryRun {
+		diff, err = h.PkgerService.DryRun(ctx, req.OrgID, req.Package)
+	} else {
+		diff, err = h.PkgerService.Apply(ctx, req.OrgID, req.Package)
+	}
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("package applied", zap.Bool("dryRun", req.DryRun), zap.Int("resources", len(diff)))
+
+	if diff == nil {
+		diff = pkger.Diff{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, applyPkgResponse{Diff: diff}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// PkgerService connects to Influx via HTTP using tokens to export and apply packages.
+type PkgerService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ pkger.SVC = (*PkgerService)(nil)
+
+// CreatePkg returns a package of the resources of the organization.
+func (s *PkgerService) CreatePkg(ctx context.Context, orgID influxdb.ID, meta pkger.Metadata) (*pkger.Pkg, error) {
+	var pkg pkger.Pkg
+	err := s.post(ctx, pkgerPath, postPkgRequest{
+		OrgID:       orgID,
+		Name:        meta.Name,
+		Version:     meta.Version,
+		Description: meta.Description,
+	}, &pkg)
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// DryRun returns the changes that applying the package to the organization would make.
+func (s *PkgerService) DryRun(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg) (pkger.Diff, error) {
+	return s.apply(ctx, orgID, pkg, true)
+}
+
+// Apply creates and updates the resources of the organization that differ
+// from the package and returns the changes it made.
+func (s *PkgerService) Apply(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg) (pkger.Diff, error) {
+	return s.apply(ctx, orgID, pkg, false)
+}
+
+func (s *PkgerService) apply(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg, dryRun bool) (pkger.Diff, error) {
+	var res applyPkgResponse
+	err := s.post(ctx, pkgerApplyPath, applyPkgRequest{
+		OrgID:   orgID,
+		DryRun:  dryRun,
+		Package: pkg,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Diff, nil
+}
+
+func (s *PkgerService) post(ctx context.Context, path string, body, v interface{}) error {
+	u, err := NewURL(s.Addr, path)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
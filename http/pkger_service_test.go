@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkger"
+	"go.uber.org/zap"
+)
+
+// fakePkgerSVC records the packages it is asked to export and apply.
+type fakePkgerSVC struct {
+	orgID  platform.ID
+	meta   pkger.Metadata
+	pkg    *pkger.Pkg
+	dryRun bool
+	diff   pkger.Diff
+}
+
+func (s *fakePkgerSVC) CreatePkg(ctx context.Context, orgID platform.ID, meta pkger.Metadata) (*pkger.Pkg, error) {
+	s.orgID, s.meta = orgID, meta
+	return &pkger.Pkg{
+		APIVersion: pkger.APIVersion,
+		Kind:       pkger.KindPackage,
+		Metadata:   meta,
+		Spec: pkger.Spec{
+			Resources: []pkger.Resource{{"kind": "Label", "name": "team"}},
+		},
+	}, nil
+}
+
+func (s *fakePkgerSVC) DryRun(ctx context.Context, orgID platform.ID, pkg *pkger.Pkg) (pkger.Diff, error) {
+	s.orgID, s.pkg, s.dryRun = orgID, pkg, true
+	return s.diff, nil
+}
+
+func (s *fakePkgerSVC) Apply(ctx context.Context, orgID platform.ID, pkg *pkger.Pkg) (pkger.Diff, error) {
+	s.orgID, s.pkg, s.dryRun = orgID, pkg, false
+	return s.diff, nil
+}
+
+func newTestPkgerServer(svc pkger.SVC) *httptest.Server {
+	h := NewPkgerHandler(&PkgerBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		PkgerService:     svc,
+	})
+	return httptest.NewServer(h)
+}
+
+func TestPkgerService_CreatePkg(t *testing.T) {
+	svc := &fakePkgerSVC{}
+	server := newTestPkgerServer(svc)
+	defer server.Close()
+
+	client := &PkgerService{Addr: server.URL}
+	meta := pkger.Metadata{Name: "p", Version: "1"}
+	pkg, err := client.CreatePkg(context.Background(), 1, meta)
+	if err != nil {
+		t.Fatalf("unable to create package: %v", err)
+	}
+
+	if svc.orgID != 1 || svc.meta != meta {
+		t.Errorf("unexpected request: got org %s and metadata %+v", svc.orgID, svc.meta)
+	}
+	if err := pkg.Validate(); err != nil {
+		t.Errorf("unexpected invalid package: %v", err)
+	}
+	if got, exp := pkg.Spec.Resources, []pkger.Resource{{"kind": "Label", "name": "team"}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("unexpected resources: got %v, exp %v", got, exp)
+	}
+}
+
+func TestPkgerService_Apply(t *testing.T) {
+	diff := pkger.Diff{
+		{Kind: pkger.KindLabel, Name: "team", Change: pkger.ChangeUpdate, ID: 2, Fields: []string{"color"}},
+	}
+	pkg := &pkger.Pkg{
+		APIVersion: pkger.APIVersion,
+		Kind:       pkger.KindPackage,
+		Spec: pkger.Spec{
+			Resources: []pkger.Resource{{"kind": "Label", "name": "team", "color": "red"}},
+		},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		svc := &fakePkgerSVC{diff: diff}
+		server := newTestPkgerServer(svc)
+
+		client := &PkgerService{Addr: server.URL}
+		apply := client.Apply
+		if dryRun {
+			apply = client.DryRun
+		}
+		got, err := apply(context.Background(), 1, pkg)
+		server.Close()
+		if err != nil {
+			t.Fatalf("dryRun=%v: unable to apply package: %v", dryRun, err)
+		}
+
+		if svc.dryRun != dryRun {
+			t.Errorf("dryRun=%v: package applied with dryRun=%v", dryRun, svc.dryRun)
+		}
+		if !reflect.DeepEqual(svc.pkg, pkg) {
+			t.Errorf("dryRun=%v: unexpected package: got %+v, exp %+v", dryRun, svc.pkg, pkg)
+		}
+		if !reflect.DeepEqual(got, diff) {
+			t.Errorf("dryRun=%v: unexpected diff: got %+v, exp %+v", dryRun, got, diff)
+		}
+	}
+}
+
+func TestPkgerHandler_handleApplyPkg_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing org", body: `{"package":{}}`},
+		{name: "missing package", body: `{"orgID":"0000000000000001"}`},
+		{name: "invalid json", body: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestPkgerServer(&fakePkgerSVC{})
+			defer server.Close()
+
+			res, err := http.Post(server.URL+pkgerApplyPath, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if got, exp := res.StatusCode, http.StatusBadRequest; got != exp {
+				t.Errorf("unexpected status code: got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/influxdata/influxdb/authorizer"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -22,21 +23,50 @@ func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// newRoleResolver returns a resolver of the roles of requests built with the
+// services of the backend, or nil when the backend has no role service.
+func newRoleResolver(b *APIBackend) *authorizer.RoleResolver {
+	if b.RoleService == nil {
+		return nil
+	}
+	return &authorizer.RoleResolver{
+		RoleService:                b.RoleService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		LabelService:               b.LabelService,
+	}
+}
+
 // NewPlatformHandler returns a platform handler that serves the API and associated assets.
 func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h := NewAuthenticationHandler(b.HTTPErrorHandler)
+
+	// The roles are resolved with the services of the backend before the api
+	// handler wraps them with authorization.
+	h.RoleResolver = newRoleResolver(b)
+
 	h.Handler = NewAPIHandler(b)
 	h.AuthorizationService = b.AuthorizationService
+	h.AuthorizationUsageRecorder = b.AuthorizationUsageRecorder
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
+	h.AuditLogger = b.AuditLogger
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
+	h.RegisterNoAuthRoute("GET", ssoSigninPath)
+	h.RegisterNoAuthRoute("GET", ssoCallbackPath)
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
 
+	// The 1.x compatible endpoints authenticate requests themselves.
+	h.RegisterNoAuthRoute("GET", legacyQueryPath)
+	h.RegisterNoAuthRoute("POST", legacyQueryPath)
+	h.RegisterNoAuthRoute("POST", legacyWritePath)
+	h.RegisterNoAuthRoute("GET", legacyPingPath)
+	h.RegisterNoAuthRoute("HEAD", legacyPingPath)
+
 	assetHandler := NewAssetHandler()
 	assetHandler.Path = b.AssetsPath
 
@@ -63,7 +93,8 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
 		!strings.HasPrefix(r.URL.Path, "/api/v2") &&
-		!strings.HasPrefix(r.URL.Path, "/chronograf/") {
+		!strings.HasPrefix(r.URL.Path, "/chronograf/") &&
+		!isLegacyPath(r.URL.Path) {
 		h.AssetHandler.ServeHTTP(w, r)
 		return
 	}
@@ -71,6 +102,11 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.APIHandler.ServeHTTP(w, r)
 }
 
+// isLegacyPath returns true if path is one of the 1.x compatible endpoints.
+func isLegacyPath(path string) bool {
+	return path == legacyQueryPath || path == legacyWritePath || path == legacyPingPath
+}
+
 // PrometheusCollectors satisfies the prom.PrometheusCollector interface.
 func (h *PlatformHandler) PrometheusCollectors() []prometheus.Collector {
 	// TODO: collect and return relevant metrics.
@@ -31,7 +31,8 @@ import (
 )
 
 const (
-	fluxPath = "/api/v2/query"
+	fluxPath        = "/api/v2/query"
+	fluxExplainPath = "/api/v2/query/explain"
 )
 
 // FluxBackend is all services and associated parameters required to construct
@@ -43,6 +44,7 @@ type FluxBackend struct {
 
 	OrganizationService influxdb.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+	ExplainService      query.ExplainService
 }
 
 // NewFluxBackend returns a new instance of FluxBackend.
@@ -53,6 +55,7 @@ func NewFluxBackend(b *APIBackend) *FluxBackend {
 		QueryEventRecorder: b.QueryEventRecorder,
 
 		ProxyQueryService:   b.FluxService,
+		ExplainService:      b.ExplainService,
 		OrganizationService: b.OrganizationService,
 	}
 }
@@ -71,6 +74,7 @@ type FluxHandler struct {
 	Now                 func() time.Time
 	OrganizationService influxdb.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+	ExplainService      query.ExplainService
 
 	EventRecorder metric.EventRecorder
 }
@@ -84,6 +88,7 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 		Logger:           b.Logger,
 
 		ProxyQueryService:   b.ProxyQueryService,
+		ExplainService:      b.ExplainService,
 		OrganizationService: b.OrganizationService,
 		EventRecorder:       b.QueryEventRecorder,
 	}
@@ -93,6 +98,7 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 	h.Handler("POST", fluxPath, qh)
 	h.HandlerFunc("POST", "/api/v2/query/ast", h.postFluxAST)
 	h.HandlerFunc("POST", "/api/v2/query/analyze", h.postQueryAnalyze)
+	h.HandlerFunc("POST", fluxExplainPath, h.postQueryExplain)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions", h.getFluxSuggestions)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions/:name", h.getFluxSuggestion)
 	return h
@@ -251,6 +257,52 @@ func (h *FluxHandler) postQueryAnalyze(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// postQueryExplain returns the plan of a query. The query is executed and its
+// plan profiled if the analyze parameter is true.
+func (h *FluxHandler) postQueryExplain(w http.ResponseWriter, r *http.Request) {
+	const op = "http/postQueryExplain"
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	req, _, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService)
+	if err != nil && err != influxdb.ErrAuthorizerNotSupported {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request body",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	// Transform the context into one with the request's authorization.
+	ctx = pcontext.SetAuthorizer(ctx, req.Request.Authorization)
+
+	analyze := r.URL.Query().Get("analyze") == "true"
+	e, err := h.ExplainService.Explain(ctx, &req.Request, analyze)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, e); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 // fluxParams contain flux funciton parameters as defined by the semantic graph
 type fluxParams map[string]string
 
@@ -464,6 +516,72 @@ func (s FluxQueryService) Check(ctx context.Context) check.Response {
 	return QueryHealthCheck(s.Addr, s.InsecureSkipVerify)
 }
 
+var _ query.ExplainService = (*ExplainService)(nil)
+
+// ExplainService implements query.ExplainService by making HTTP requests to the /api/v2/query/explain API endpoint.
+type ExplainService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Explain returns the plan of the query of r, profiled if analyze is true.
+func (s *ExplainService) Explain(ctx context.Context, r *query.Request, analyze bool) (*query.Explanation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, fluxExplainPath)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	params := url.Values{}
+	params.Set(OrgID, r.OrganizationID.String())
+	if analyze {
+		params.Set("analyze", "true")
+	}
+	u.RawQuery = params.Encode()
+
+	preq := &query.ProxyRequest{
+		Request: *r,
+		Dialect: csv.DefaultDialect(),
+	}
+	qreq, err := QueryRequestFromProxyRequest(preq)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(qreq); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	hreq, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	SetToken(s.Token, hreq)
+
+	hreq.Header.Set("Content-Type", "application/json")
+	hreq = hreq.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	var e query.Explanation
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &e, nil
+}
+
 // SimpleQuery runs a flux query with common parameters and returns CSV results.
 func SimpleQuery(addr, flux, org, token string) ([]byte, error) {
 	u, err := NewURL(addr, fluxPath)
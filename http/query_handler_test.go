@@ -433,6 +433,80 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 	})
 }
 
+func TestExplainService_Explain(t *testing.T) {
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+	explanation := &query.Explanation{
+		Logical: []*query.PlanNode{
+			{ID: "influxDBFrom0", Kind: "influxDBFrom"},
+			{ID: "range1", Kind: "range", Predecessors: []string{"influxDBFrom0"}},
+		},
+		Physical: []*query.PlanNode{
+			{
+				ID:      "ReadRange2",
+				Kind:    "ReadRangePhysKind",
+				Details: `bucket "b"`,
+				Profile: &query.PlanNodeProfile{Tables: 1, Rows: 10, Bytes: 80, Finished: time.Second, ScannedValues: 10},
+			},
+		},
+		Rules: []*query.PlanRule{
+			{Name: "PushDownRangeRule", Phase: "physical", Node: "range1", Result: "ReadRange2"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		analyze bool
+	}{
+		{name: "explain"},
+		{name: "analyze", analyze: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewFluxHandler(&FluxBackend{
+				HTTPErrorHandler:    ErrorHandler(0),
+				Logger:              zaptest.NewLogger(t),
+				QueryEventRecorder:  noopEventRecorder{},
+				OrganizationService: i,
+				ExplainService: &mock.ExplainService{
+					ExplainF: func(ctx context.Context, req *query.Request, analyze bool) (*query.Explanation, error) {
+						if req.OrganizationID != org.ID {
+							t.Errorf("unexpected organization %s", req.OrganizationID)
+						}
+						if c, ok := req.Compiler.(lang.FluxCompiler); !ok || c.Query != `from(bucket: "b") |> range(start: -1h)` {
+							t.Errorf("unexpected compiler %#v", req.Compiler)
+						}
+						if analyze != tt.analyze {
+							t.Errorf("unexpected analyze %v", analyze)
+						}
+						return explanation, nil
+					},
+				},
+			})
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Authorization{}))
+				h.ServeHTTP(w, r)
+			}))
+			defer ts.Close()
+
+			s := &ExplainService{Addr: ts.URL}
+			got, err := s.Explain(context.Background(), &query.Request{
+				OrganizationID: org.ID,
+				Compiler:       lang.FluxCompiler{Query: `from(bucket: "b") |> range(start: -1h)`},
+			}, tt.analyze)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(explanation, got); diff != "" {
+				t.Errorf("unexpected explanation -want/+got\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFluxService_Query_gzip(t *testing.T) {
 	// orgService is just to mock out orgs by returning
 	// the same org every time.
@@ -35,3 +35,25 @@ func queryOrganization(ctx context.Context, r *http.Request, svc platform.Organi
 	}
 	return svc.FindOrganization(ctx, filter)
 }
+
+// queryBucket returns the bucket within the organization orgID that is identified
+// by bucket, which may be either the ID or the name of the bucket.
+func queryBucket(ctx context.Context, orgID platform.ID, bucket string, svc platform.BucketService) (*platform.Bucket, error) {
+	if id, err := platform.IDFromString(bucket); err == nil {
+		// Decoded ID successfully. Make sure it's a real bucket.
+		b, err := svc.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			ID:             id,
+		})
+		if err == nil {
+			return b, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &bucket,
+	})
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+// RestoreBackend is all services and associated parameters required to construct the RestoreHandler.
+type RestoreBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	RestoreService      influxdb.RestoreService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRestoreBackend returns a new instance of RestoreBackend.
+func NewRestoreBackend(b *APIBackend) *RestoreBackend {
+	return &RestoreBackend{
+		Logger:           b.Logger.With(zap.String("handler", "restore")),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+
+		RestoreService:      b.RestoreService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// RestoreHandler receives TSM files and imports them into a bucket.
+type RestoreHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RestoreService      influxdb.RestoreService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	restorePath = "/api/v2/restore"
+)
+
+// NewRestoreHandler creates a new handler at /api/v2/restore to receive restore requests.
+func NewRestoreHandler(b *RestoreBackend) *RestoreHandler {
+	h := &RestoreHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		RestoreService:      b.RestoreService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", restorePath, h.handleRestore)
+	return h
+}
+
+func (h *RestoreHandler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	bucketName := r.URL.Query().Get("bucket")
+	if bucketName == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleRestore",
+			Msg:  "bucket is required",
+		}, w)
+		return
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bucket, err := queryBucket(ctx, org.ID, bucketName, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Op:  "http/handleRestore",
+			Err: err,
+		}, w)
+		return
+	}
+
+	if bucket.IsSystem() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handleRestore",
+			Msg:  fmt.Sprintf("cannot restore to internal bucket %s", bucket.Name),
+		}, w)
+		return
+	}
+
+	if err := h.RestoreService.RestoreBucket(ctx, org.ID, bucket.ID, r.Body); err != nil {
+		h.Logger.Info("Error restoring data", zap.String("bucket", bucket.ID.String()), zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Op:  "http/handleRestore",
+			Err: err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreService is the client implementation of influxdb.RestoreService.
+type RestoreService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+
+// RestoreBucket uploads the TSM file read from r to the bucket.
+func (s *RestoreService) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, r io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, restorePath)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	params.Set("orgID", orgID.String())
+	params.Set("bucket", bucketID.String())
+	req.URL.RawQuery = params.Encode()
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
@@ -0,0 +1,529 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// RoleBackend is all services and associated parameters required to construct
+// the RoleHandler.
+type RoleBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RoleService                influxdb.RoleService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewRoleBackend returns a new instance of RoleBackend.
+func NewRoleBackend(b *APIBackend) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "role")),
+
+		RoleService:                b.RoleService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// RoleHandler is the handler for the role service
+type RoleHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RoleService                influxdb.RoleService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+const (
+	rolesPath            = "/api/v2/roles"
+	rolesIDPath          = "/api/v2/roles/:id"
+	rolesIDMembersPath   = "/api/v2/roles/:id/members"
+	rolesIDMembersIDPath = "/api/v2/roles/:id/members/:userID"
+)
+
+// NewRoleHandler returns a new instance of RoleHandler.
+func NewRoleHandler(b *RoleBackend) *RoleHandler {
+	h := &RoleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		RoleService:                b.RoleService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+
+	h.HandlerFunc("POST", rolesPath, h.handlePostRole)
+	h.HandlerFunc("GET", rolesPath, h.handleGetRoles)
+	h.HandlerFunc("GET", rolesIDPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", rolesIDPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", rolesIDPath, h.handleDeleteRole)
+
+	h.HandlerFunc("GET", rolesIDMembersPath, h.handleGetRoleMembers)
+	h.HandlerFunc("POST", rolesIDMembersPath, h.handlePostRoleMember)
+	h.HandlerFunc("DELETE", rolesIDMembersIDPath, h.handleDeleteRoleMember)
+	return h
+}
+
+type roleResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Role
+}
+
+func newRoleResponse(r *influxdb.Role) *roleResponse {
+	if r.Permissions == nil {
+		r.Permissions = []influxdb.RolePermission{}
+	}
+	return &roleResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/roles/%s", r.ID),
+			"members": fmt.Sprintf("/api/v2/roles/%s/members", r.ID),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+		Role: *r,
+	}
+}
+
+type rolesResponse struct {
+	Links map[string]string `json:"links"`
+	Roles []*roleResponse   `json:"roles"`
+}
+
+func newRolesResponse(rs []*influxdb.Role) *rolesResponse {
+	res := &rolesResponse{
+		Links: map[string]string{
+			"self": rolesPath,
+		},
+		Roles: make([]*roleResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Roles = append(res.Roles, newRoleResponse(r))
+	}
+	return res
+}
+
+type roleMembersResponse struct {
+	Links map[string]string `json:"links"`
+	Users []*userResponse   `json:"users"`
+}
+
+func newRoleMembersResponse(roleID influxdb.ID, users []*influxdb.User) *roleMembersResponse {
+	res := &roleMembersResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/roles/%s/members", roleID),
+		},
+		Users: make([]*userResponse, 0, len(users)),
+	}
+	for _, u := range users {
+		res.Users = append(res.Users, newUserResponse(u))
+	}
+	return res
+}
+
+// handlePostRole is the HTTP handler for the POST /api/v2/roles route.
+func (h *RoleHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role := &influxdb.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role created", zap.String("role", fmt.Sprint(role)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetRoles is the HTTP handler for the GET /api/v2/roles route.
+func (h *RoleHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeRoleFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.RoleService.FindRoles(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("roles retrieved", zap.Int("count", len(rs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRolesResponse(rs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeRoleFilter(r *http.Request) (influxdb.RoleFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.RoleFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	}
+
+	if org := qp.Get("org"); org != "" {
+		filter.Org = &org
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// handleGetRole is the HTTP handler for the GET /api/v2/roles/:id route.
+func (h *RoleHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role retrieved", zap.String("role", fmt.Sprint(role)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchRole is the HTTP handler for the PATCH /api/v2/roles/:id route.
+func (h *RoleHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	role, err := h.RoleService.UpdateRole(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role updated", zap.String("role", fmt.Sprint(role)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRole is the HTTP handler for the DELETE /api/v2/roles/:id route.
+func (h *RoleHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role deleted", zap.String("roleID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetRoleMembers is the HTTP handler for the GET /api/v2/roles/:id/members route.
+func (h *RoleHandler) handleGetRoleMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   role.OrgID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	users := []*influxdb.User{}
+	for _, m := range ms {
+		if !influxdb.HasRole(m.RoleIDs, id) {
+			continue
+		}
+		u, err := h.UserService.FindUserByID(ctx, m.UserID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		users = append(users, u)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleMembersResponse(id, users)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostRoleMember is the HTTP handler for the POST /api/v2/roles/:id/members route.
+func (h *RoleHandler) handlePostRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u := &influxdb.User{}
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	if !u.ID.Valid() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "user id missing or invalid",
+		}, w)
+		return
+	}
+
+	user, err := h.UserService.FindUserByID(ctx, u.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.AddRoleMember(ctx, id, user.ID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role member added", zap.String("roleID", id.String()), zap.String("userID", user.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newUserResponse(user)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRoleMember is the HTTP handler for the DELETE /api/v2/roles/:id/members/:userID route.
+func (h *RoleHandler) handleDeleteRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	userID, err := decodeIDParam(ctx, "userID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.RemoveRoleMember(ctx, id, userID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role member removed", zap.String("roleID", id.String()), zap.String("userID", userID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RoleService connects to Influx via HTTP using tokens to manage roles.
+type RoleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+func roleIDPath(id influxdb.ID) string {
+	return path.Join(rolesPath, id.String())
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r roleResponse
+	if err := s.do(ctx, "GET", roleIDPath(id), nil, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r.Role, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Role{r}, 1, nil
+	}
+
+	query := url.Values{}
+	if filter.OrgID != nil {
+		query.Set("orgID", filter.OrgID.String())
+	}
+	if filter.Org != nil {
+		query.Set("org", *filter.Org)
+	}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res rolesResponse
+	if err := s.do(ctx, "GET", rolesPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	rs := make([]*influxdb.Role, 0, len(res.Roles))
+	for _, r := range res.Roles {
+		rs = append(rs, &r.Role)
+	}
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res roleResponse
+	if err := s.do(ctx, "POST", rolesPath, nil, r, &res); err != nil {
+		return err
+	}
+	*r = res.Role
+	return nil
+}
+
+// UpdateRole updates a single role with changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r roleResponse
+	if err := s.do(ctx, "PATCH", roleIDPath(id), nil, upd, &r); err != nil {
+		return nil, err
+	}
+	return &r.Role, nil
+}
+
+// DeleteRole removes a role by ID, and its references from members.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.do(ctx, "DELETE", roleIDPath(id), nil, nil, nil)
+}
+
+// FindRoleMembers returns the users the role is granted to.
+func (s *RoleService) FindRoleMembers(ctx context.Context, roleID influxdb.ID) ([]*influxdb.User, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res roleMembersResponse
+	if err := s.do(ctx, "GET", path.Join(roleIDPath(roleID), "members"), nil, nil, &res); err != nil {
+		return nil, err
+	}
+
+	users := make([]*influxdb.User, 0, len(res.Users))
+	for _, u := range res.Users {
+		users = append(users, &u.User)
+	}
+	return users, nil
+}
+
+// AddRoleMember grants a role to a user.
+func (s *RoleService) AddRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.do(ctx, "POST", path.Join(roleIDPath(roleID), "members"), nil, &influxdb.User{ID: userID}, nil)
+}
+
+// RemoveRoleMember revokes a role from a user.
+func (s *RoleService) RemoveRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.do(ctx, "DELETE", path.Join(roleIDPath(roleID), "members", userID.String()), nil, nil, nil)
+}
+
+func (s *RoleService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+func newTestRoleService(t *testing.T) (*kv.Service, *influxdb.Organization, *influxdb.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "jane"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return svc, org, user
+}
+
+func TestRoleService_Client(t *testing.T) {
+	ctx := context.Background()
+	svc, org, user := newTestRoleService(t)
+
+	server := newAuthorizedTestServer(NewRoleHandler(&RoleBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		RoleService:                svc,
+		UserResourceMappingService: svc,
+		UserService:                svc,
+	}), user.ID)
+	defer server.Close()
+
+	client := &RoleService{Addr: server.URL}
+	role := &influxdb.Role{
+		OrgID: org.ID,
+		Name:  "bucket-writer",
+		Permissions: []influxdb.RolePermission{
+			{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType, Labels: []string{"team=infra"}},
+			},
+		},
+	}
+	if err := client.CreateRole(ctx, role); err != nil {
+		t.Fatalf("unable to create role: %v", err)
+	}
+	if !role.ID.Valid() {
+		t.Fatalf("unexpected role: %+v", role)
+	}
+
+	if err := client.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: "bucket-writer"}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected conflict creating a duplicate role, got %v", err)
+	}
+
+	roles, _, err := client.FindRoles(ctx, influxdb.RoleFilter{Org: &org.Name})
+	if err != nil {
+		t.Fatalf("unable to find roles: %v", err)
+	}
+	if diff := cmp.Diff([]*influxdb.Role{role}, roles); diff != "" {
+		t.Errorf("unexpected roles -want/+got\n%s", diff)
+	}
+
+	desc := "writes to the buckets of the infra team"
+	updated, err := client.UpdateRole(ctx, role.ID, influxdb.RoleUpdate{Description: &desc})
+	if err != nil {
+		t.Fatalf("unable to update role: %v", err)
+	}
+	if updated.Description != desc || len(updated.Permissions) != 1 {
+		t.Errorf("unexpected role after update: %+v", updated)
+	}
+
+	if err := client.AddRoleMember(ctx, role.ID, user.ID); err != nil {
+		t.Fatalf("unable to add role member: %v", err)
+	}
+	members, err := client.FindRoleMembers(ctx, role.ID)
+	if err != nil {
+		t.Fatalf("unable to find role members: %v", err)
+	}
+	if len(members) != 1 || members[0].ID != user.ID {
+		t.Errorf("unexpected role members: %+v", members)
+	}
+
+	if err := client.RemoveRoleMember(ctx, role.ID, user.ID); err != nil {
+		t.Fatalf("unable to remove role member: %v", err)
+	}
+	if err := client.DeleteRole(ctx, role.ID); err != nil {
+		t.Fatalf("unable to delete role: %v", err)
+	}
+	if _, err := client.FindRoleByID(ctx, role.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+}
+
+func TestAuthenticationHandler_Roles(t *testing.T) {
+	ctx := context.Background()
+	svc, org, user := newTestRoleService(t)
+
+	infra := &influxdb.Bucket{OrgID: org.ID, Name: "infra"}
+	web := &influxdb.Bucket{OrgID: org.ID, Name: "web"}
+	for _, b := range []*influxdb.Bucket{infra, web} {
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	label := &influxdb.Label{OrgID: org.ID, Name: "team=infra"}
+	if err := svc.CreateLabel(ctx, label); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateLabelMapping(ctx, &influxdb.LabelMapping{
+		LabelID:      label.ID,
+		ResourceID:   infra.ID,
+		ResourceType: influxdb.BucketsResourceType,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	role := &influxdb.Role{
+		OrgID: org.ID,
+		Name:  "bucket-writer",
+		Permissions: []influxdb.RolePermission{
+			{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType, Labels: []string{"team=infra"}},
+			},
+		},
+	}
+	if err := svc.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+	a := &influxdb.Authorization{
+		OrgID:   org.ID,
+		UserID:  user.ID,
+		Status:  influxdb.Active,
+		RoleIDs: []influxdb.ID{role.ID},
+	}
+	if err := svc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewAuthenticationHandler(ErrorHandler(0))
+	h.AuthorizationService = svc
+	h.RoleResolver = &authorizer.RoleResolver{
+		RoleService:                svc,
+		UserResourceMappingService: svc,
+		LabelService:               svc,
+	}
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := influxdb.IDFromString(r.URL.Query().Get("bucket"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := influxdb.NewPermissionAtID(*id, influxdb.WriteAction, influxdb.BucketsResourceType, org.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := authorizer.IsAllowed(r.Context(), *p); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		bucket *influxdb.Bucket
+		status int
+	}{
+		{bucket: infra, status: http.StatusNoContent},
+		{bucket: web, status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bucket.Name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v2/write?bucket="+tt.bucket.ID.String(), nil)
+			SetToken(a.Token, r)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("unexpected status: got %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// RunningQueryBackend is all services and associated parameters required to
+// construct the RunningQueryHandler.
+type RunningQueryBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RunningQueryService query.RunningQueryService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRunningQueryBackend returns a new instance of RunningQueryBackend.
+func NewRunningQueryBackend(b *APIBackend) *RunningQueryBackend {
+	return &RunningQueryBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "running_query")),
+
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// RunningQueryHandler represents an HTTP API handler for the running queries.
+type RunningQueryHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RunningQueryService query.RunningQueryService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	runningQueriesPath   = "/api/v2/queries"
+	runningQueriesIDPath = runningQueriesPath + "/:id"
+)
+
+// NewRunningQueryHandler returns a new instance of RunningQueryHandler.
+func NewRunningQueryHandler(b *RunningQueryBackend) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router:              NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger,
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", runningQueriesPath, h.handleGetRunningQueries)
+	h.HandlerFunc("GET", runningQueriesIDPath, h.handleGetRunningQuery)
+	h.HandlerFunc("DELETE", runningQueriesIDPath, h.handleDeleteRunningQuery)
+	return h
+}
+
+type runningQueryResponse struct {
+	Links           map[string]string `json:"links"`
+	ID              uint64            `json:"id"`
+	OrgID           *influxdb.ID      `json:"orgID,omitempty"`
+	AuthorizationID *influxdb.ID      `json:"authorizationID,omitempty"`
+	UserID          *influxdb.ID      `json:"userID,omitempty"`
+	Query           string            `json:"query"`
+	CompilerType    string            `json:"compilerType"`
+	State           string            `json:"state"`
+	StartedAt       time.Time         `json:"startedAt"`
+	Elapsed         string            `json:"elapsed"`
+	QueueDuration   string            `json:"queueDuration"`
+	MemoryAllocated int64             `json:"memoryAllocated"`
+}
+
+func newRunningQueryResponse(rq *query.RunningQuery) *runningQueryResponse {
+	validID := func(id influxdb.ID) *influxdb.ID {
+		if !id.Valid() {
+			return nil
+		}
+		return &id
+	}
+
+	return &runningQueryResponse{
+		Links: map[string]string{
+			"self": runningQueryIDPath(rq.ID),
+		},
+		ID:              rq.ID,
+		OrgID:           validID(rq.OrganizationID),
+		AuthorizationID: validID(rq.AuthorizationID),
+		UserID:          validID(rq.UserID),
+		Query:           rq.Query,
+		CompilerType:    rq.CompilerType,
+		State:           rq.State,
+		StartedAt:       rq.StartedAt,
+		Elapsed:         rq.Elapsed.String(),
+		QueueDuration:   rq.QueueDuration.String(),
+		MemoryAllocated: rq.MemoryAllocated,
+	}
+}
+
+func (r *runningQueryResponse) toRunningQuery() (*query.RunningQuery, error) {
+	rq := &query.RunningQuery{
+		ID:              r.ID,
+		Query:           r.Query,
+		CompilerType:    r.CompilerType,
+		State:           r.State,
+		StartedAt:       r.StartedAt,
+		MemoryAllocated: r.MemoryAllocated,
+	}
+	if r.OrgID != nil {
+		rq.OrganizationID = *r.OrgID
+	}
+	if r.AuthorizationID != nil {
+		rq.AuthorizationID = *r.AuthorizationID
+	}
+	if r.UserID != nil {
+		rq.UserID = *r.UserID
+	}
+
+	var err error
+	if rq.Elapsed, err = time.ParseDuration(r.Elapsed); err != nil {
+		return nil, err
+	}
+	if rq.QueueDuration, err = time.ParseDuration(r.QueueDuration); err != nil {
+		return nil, err
+	}
+	return rq, nil
+}
+
+type runningQueriesResponse struct {
+	Links   map[string]string       `json:"links"`
+	Queries []*runningQueryResponse `json:"queries"`
+}
+
+func newRunningQueriesResponse(rqs []*query.RunningQuery) *runningQueriesResponse {
+	res := &runningQueriesResponse{
+		Links: map[string]string{
+			"self": runningQueriesPath,
+		},
+		Queries: make([]*runningQueryResponse, 0, len(rqs)),
+	}
+	for _, rq := range rqs {
+		res.Queries = append(res.Queries, newRunningQueryResponse(rq))
+	}
+	return res
+}
+
+// handleGetRunningQueries is the HTTP handler for the GET /api/v2/queries route.
+func (h *RunningQueryHandler) handleGetRunningQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filter query.RunningQueryFilter
+	qp := r.URL.Query()
+	if qp.Get(Org) != "" || qp.Get(OrgID) != "" {
+		o, err := queryOrganization(ctx, r, h.OrganizationService)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		filter.OrganizationID = &o.ID
+	}
+
+	rqs, err := h.RunningQueryService.FindRunningQueries(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueriesResponse(rqs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetRunningQuery is the HTTP handler for the GET /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleGetRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rq, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueryResponse(rq)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRunningQuery is the HTTP handler for the DELETE /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleDeleteRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.CancelRunningQuery(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query canceled", zap.Uint64("queryID", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeRunningQueryID(ctx context.Context, r *http.Request) (uint64, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id, err := strconv.ParseUint(params.ByName("id"), 10, 64)
+	if err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid query id %q", params.ByName("id")),
+		}
+	}
+	return id, nil
+}
+
+func runningQueryIDPath(id uint64) string {
+	return path.Join(runningQueriesPath, strconv.FormatUint(id, 10))
+}
+
+// RunningQueryService connects to Influx via HTTP using tokens to manage the
+// running queries.
+type RunningQueryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ query.RunningQueryService = (*RunningQueryService)(nil)
+
+// FindRunningQueries returns the running queries matching the filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+	url, err := NewURL(s.Addr, runningQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := url.Query()
+	if filter.OrganizationID != nil {
+		qp.Set(OrgID, filter.OrganizationID.String())
+	}
+	url.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res runningQueriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	rqs := make([]*query.RunningQuery, 0, len(res.Queries))
+	for _, r := range res.Queries {
+		rq, err := r.toRunningQuery()
+		if err != nil {
+			return nil, err
+		}
+		rqs = append(rqs, rq)
+	}
+	return rqs, nil
+}
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+	url, err := NewURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res runningQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.toRunningQuery()
+}
+
+// CancelRunningQuery cancels a running query by ID.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id uint64) error {
+	url, err := NewURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", url.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap"
+)
+
+func newTestRunningQueryService(canceled *[]uint64) *querymock.RunningQueryService {
+	rqs := []*query.RunningQuery{
+		{
+			ID:              1,
+			OrganizationID:  10,
+			AuthorizationID: 20,
+			UserID:          30,
+			Query:           `from(bucket: "b") |> range(start: -1h)`,
+			CompilerType:    "flux",
+			State:           "executing",
+			StartedAt:       time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			Elapsed:         1500 * time.Millisecond,
+			QueueDuration:   time.Millisecond,
+			MemoryAllocated: 1024,
+		},
+		{
+			ID:           2,
+			CompilerType: "influxql",
+			State:        "queueing",
+			StartedAt:    time.Date(2019, 1, 1, 0, 0, 1, 0, time.UTC),
+		},
+	}
+	find := func(id uint64) (*query.RunningQuery, error) {
+		for _, rq := range rqs {
+			if rq.ID == id {
+				return rq, nil
+			}
+		}
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "query not found",
+		}
+	}
+
+	return &querymock.RunningQueryService{
+		FindRunningQueriesF: func(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+			var found []*query.RunningQuery
+			for _, rq := range rqs {
+				if filter.OrganizationID == nil || *filter.OrganizationID == rq.OrganizationID {
+					found = append(found, rq)
+				}
+			}
+			return found, nil
+		},
+		FindRunningQueryByIDF: func(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+			return find(id)
+		},
+		CancelRunningQueryF: func(ctx context.Context, id uint64) error {
+			if _, err := find(id); err != nil {
+				return err
+			}
+			*canceled = append(*canceled, id)
+			return nil
+		},
+	}
+}
+
+func newTestRunningQueryHandler(canceled *[]uint64) *RunningQueryHandler {
+	return NewRunningQueryHandler(&RunningQueryBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		RunningQueryService: newTestRunningQueryService(canceled),
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return &influxdb.Organization{ID: 10, Name: "org"}, nil
+			},
+		},
+	})
+}
+
+func TestRunningQueryHandler_handleGetRunningQueries(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{
+			name: "list all running queries",
+			url:  "http://any.tld/api/v2/queries",
+			body: `
+{
+  "links": {
+    "self": "/api/v2/queries"
+  },
+  "queries": [
+    {
+      "links": {
+        "self": "/api/v2/queries/1"
+      },
+      "id": 1,
+      "orgID": "000000000000000a",
+      "authorizationID": "0000000000000014",
+      "userID": "000000000000001e",
+      "query": "from(bucket: \"b\") |> range(start: -1h)",
+      "compilerType": "flux",
+      "state": "executing",
+      "startedAt": "2019-01-01T00:00:00Z",
+      "elapsed": "1.5s",
+      "queueDuration": "1ms",
+      "memoryAllocated": 1024
+    },
+    {
+      "links": {
+        "self": "/api/v2/queries/2"
+      },
+      "id": 2,
+      "query": "",
+      "compilerType": "influxql",
+      "state": "queueing",
+      "startedAt": "2019-01-01T00:00:01Z",
+      "elapsed": "0s",
+      "queueDuration": "0s",
+      "memoryAllocated": 0
+    }
+  ]
+}`,
+		},
+		{
+			name: "list the running queries of an organization by name",
+			url:  "http://any.tld/api/v2/queries?org=org",
+			body: `
+{
+  "links": {
+    "self": "/api/v2/queries"
+  },
+  "queries": [
+    {
+      "links": {
+        "self": "/api/v2/queries/1"
+      },
+      "id": 1,
+      "orgID": "000000000000000a",
+      "authorizationID": "0000000000000014",
+      "userID": "000000000000001e",
+      "query": "from(bucket: \"b\") |> range(start: -1h)",
+      "compilerType": "flux",
+      "state": "executing",
+      "startedAt": "2019-01-01T00:00:00Z",
+      "elapsed": "1.5s",
+      "queueDuration": "1ms",
+      "memoryAllocated": 1024
+    }
+  ]
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestRunningQueryHandler(nil)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("handleGetRunningQueries() = %v, want %v: %s", res.StatusCode, http.StatusOK, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.body); err != nil {
+				t.Errorf("handleGetRunningQueries(). error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("handleGetRunningQueries() = ***%s***", diff)
+			}
+		})
+	}
+}
+
+func TestRunningQueryService(t *testing.T) {
+	var canceled []uint64
+	server := httptest.NewServer(newTestRunningQueryHandler(&canceled))
+	defer server.Close()
+
+	s := &RunningQueryService{Addr: server.URL}
+	ctx := context.Background()
+
+	orgID := influxdb.ID(10)
+	rqs, err := s.FindRunningQueries(ctx, query.RunningQueryFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := newTestRunningQueryService(nil).FindRunningQueryByID(ctx, 1)
+	if diff := cmp.Diff(rqs, []*query.RunningQuery{want}); diff != "" {
+		t.Fatalf("running queries are different -got/+want\ndiff %s", diff)
+	}
+
+	rq, err := s.FindRunningQueryByID(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rq.ID != 2 || rq.OrganizationID.Valid() || rq.State != "queueing" {
+		t.Fatalf("unexpected running query %+v", rq)
+	}
+
+	if err := s.CancelRunningQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(canceled, []uint64{1}); diff != "" {
+		t.Fatalf("canceled queries are different -got/+want\ndiff %s", diff)
+	}
+
+	if err := s.CancelRunningQuery(ctx, 3); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected canceling a missing query to fail with not found, got %v", err)
+	}
+	if _, err := s.FindRunningQueryByID(ctx, 3); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a missing query to not be found, got %v", err)
+	}
+}
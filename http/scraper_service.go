@@ -21,6 +21,7 @@ type ScraperBackend struct {
 	Logger *zap.Logger
 
 	ScraperStorageService      influxdb.ScraperTargetStoreService
+	ScraperStatusService       influxdb.ScraperTargetStatusService
 	BucketService              influxdb.BucketService
 	OrganizationService        influxdb.OrganizationService
 	UserService                influxdb.UserService
@@ -35,6 +36,7 @@ func NewScraperBackend(b *APIBackend) *ScraperBackend {
 		Logger:           b.Logger.With(zap.String("handler", "scraper")),
 
 		ScraperStorageService:      b.ScraperTargetStoreService,
+		ScraperStatusService:       b.ScraperTargetStatusService,
 		BucketService:              b.BucketService,
 		OrganizationService:        b.OrganizationService,
 		UserService:                b.UserService,
@@ -52,6 +54,7 @@ type ScraperHandler struct {
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	ScraperStorageService      influxdb.ScraperTargetStoreService
+	ScraperStatusService       influxdb.ScraperTargetStatusService
 	BucketService              influxdb.BucketService
 	OrganizationService        influxdb.OrganizationService
 }
@@ -64,6 +67,7 @@ const (
 	targetsIDOwnersIDPath  = targetsPath + "/:id/owners/:userID"
 	targetsIDLabelsPath    = targetsPath + "/:id/labels"
 	targetsIDLabelsIDPath  = targetsPath + "/:id/labels/:lid"
+	targetsIDStatusPath    = targetsPath + "/:id/status"
 )
 
 // NewScraperHandler returns a new instance of ScraperHandler.
@@ -76,6 +80,7 @@ func NewScraperHandler(b *ScraperBackend) *ScraperHandler {
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		ScraperStorageService:      b.ScraperStorageService,
+		ScraperStatusService:       b.ScraperStatusService,
 		BucketService:              b.BucketService,
 		OrganizationService:        b.OrganizationService,
 	}
@@ -84,6 +89,7 @@ func NewScraperHandler(b *ScraperBackend) *ScraperHandler {
 	h.HandlerFunc("GET", targetsPath+"/:id", h.handleGetScraperTarget)
 	h.HandlerFunc("PATCH", targetsPath+"/:id", h.handlePatchScraperTarget)
 	h.HandlerFunc("DELETE", targetsPath+"/:id", h.handleDeleteScraperTarget)
+	h.HandlerFunc("GET", targetsIDStatusPath, h.handleGetScraperTargetStatus)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -240,6 +246,33 @@ func (h *ScraperHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.R
 	}
 }
 
+// handleGetScraperTargetStatus is the HTTP handler for the GET /api/v2/scrapers/:id/status route.
+func (h *ScraperHandler) handleGetScraperTargetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeScraperTargetIDRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	// Reading the target checks that the status can be read.
+	if _, err := h.ScraperStorageService.GetTargetByID(ctx, *id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	status, err := h.ScraperStatusService.GetTargetStatus(ctx, *id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("scraper status retrieved", zap.String("status", fmt.Sprint(status)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, status); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 type getScraperTargetsRequest struct {
 	filter influxdb.ScraperTargetFilter
 }
@@ -503,6 +536,7 @@ func (s *ScraperService) AddTarget(ctx context.Context, target *influxdb.Scraper
 	if err := json.NewDecoder(resp.Body).Decode(targetResp); err != nil {
 		return err
 	}
+	target.ID = targetResp.ID
 
 	return nil
 }
@@ -562,6 +596,38 @@ func (s *ScraperService) GetTargetByID(ctx context.Context, id influxdb.ID) (*in
 	return &targetResp.ScraperTarget, nil
 }
 
+// GetTargetStatus returns the status of the last scrape of a target.
+func (s *ScraperService) GetTargetStatus(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	url, err := NewURL(s.Addr, path.Join(targetIDPath(id), "status"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var status influxdb.ScraperTargetStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
 func targetIDPath(id influxdb.ID) string {
 	return path.Join(targetsPath, id.String())
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -37,6 +38,7 @@ func NewMockScraperBackend() *ScraperBackend {
 		Logger: zap.NewNop().With(zap.String("handler", "scraper")),
 
 		ScraperStorageService:      &mock.ScraperTargetStoreService{},
+		ScraperStatusService:       &mock.ScraperTargetStatusService{},
 		BucketService:              mock.NewBucketService(),
 		OrganizationService:        mock.NewOrganizationService(),
 		UserService:                mock.NewUserService(),
@@ -387,6 +389,107 @@ func TestService_handleGetScraperTarget(t *testing.T) {
 	}
 }
 
+func TestService_handleGetScraperTargetStatus(t *testing.T) {
+	targets := &mock.ScraperTargetStoreService{
+		GetTargetByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTarget, error) {
+			if id == targetOneID {
+				return &influxdb.ScraperTarget{ID: targetOneID}, nil
+			}
+			return nil, &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "scraper target is not found",
+			}
+		},
+	}
+	statuses := &mock.ScraperTargetStatusService{
+		GetTargetStatusF: func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+			return &influxdb.ScraperTargetStatus{
+				TargetID:   id,
+				Status:     influxdb.ScraperTargetDown,
+				LastScrape: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				Duration:   influxdb.Duration{Duration: 1500 * time.Millisecond},
+				HTTPStatus: http.StatusServiceUnavailable,
+				Error:      "unexpected status",
+			}, nil
+		},
+	}
+
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		wants wants
+	}{
+		{
+			name: "get the status of a scraper target",
+			id:   targetOneIDString,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: fmt.Sprintf(`
+{
+  "targetID": "%s",
+  "status": "down",
+  "lastScrape": "2019-01-01T00:00:00Z",
+  "duration": "1.5s",
+  "httpStatus": 503,
+  "samples": 0,
+  "error": "unexpected status"
+}`, targetOneIDString),
+			},
+		},
+		{
+			name: "get the status of a missing scraper target",
+			id:   targetTwoIDString,
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraperBackend := NewMockScraperBackend()
+			scraperBackend.HTTPErrorHandler = ErrorHandler(0)
+			scraperBackend.ScraperStorageService = targets
+			scraperBackend.ScraperStatusService = statuses
+			h := NewScraperHandler(scraperBackend)
+
+			r := httptest.NewRequest("GET", "http://any.tld", nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.id,
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handleGetScraperTargetStatus(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handleGetScraperTargetStatus() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handleGetScraperTargetStatus(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handleGetScraperTargetStatus() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
 func TestService_handleDeleteScraperTarget(t *testing.T) {
 	type fields struct {
 		Service influxdb.ScraperTargetStoreService
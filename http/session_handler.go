@@ -2,9 +2,14 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
+	"fmt"
 	"net/http"
+	"strings"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	"github.com/influxdata/influxdb/rand"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -17,6 +22,8 @@ type SessionBackend struct {
 
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
+	SSOService       platform.SSOService
+	AuditLogger      platform.AuditLogger
 }
 
 // NewSessionBackend creates a new SessionBackend with associated logger.
@@ -27,6 +34,8 @@ func NewSessionBackend(b *APIBackend) *SessionBackend {
 
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
+		SSOService:       b.SSOService,
+		AuditLogger:      b.AuditLogger,
 	}
 }
 
@@ -38,6 +47,8 @@ type SessionHandler struct {
 
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
+	SSOService       platform.SSOService
+	AuditLogger      platform.AuditLogger
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -49,10 +60,16 @@ func NewSessionHandler(b *SessionBackend) *SessionHandler {
 
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
+		SSOService:       b.SSOService,
+		AuditLogger:      b.AuditLogger,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
 	h.HandlerFunc("POST", "/api/v2/signout", h.handleSignout)
+	if h.SSOService != nil {
+		h.HandlerFunc("GET", ssoSigninPath, h.handleSSOSignin)
+		h.HandlerFunc("GET", ssoCallbackPath, h.handleSSOCallback)
+	}
 	return h
 }
 
@@ -68,20 +85,47 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.PasswordsService.ComparePassword(ctx, req.Username, req.Password); err != nil {
 		// Don't log here, it should already be handled by the service
+		h.logSignin(ctx, nil, fmt.Sprintf("invalid password for user %q", req.Username))
 		UnauthorizedError(ctx, h, w)
 		return
 	}
 
 	s, e := h.SessionService.CreateSession(ctx, req.Username)
 	if e != nil {
+		h.logSignin(ctx, nil, e.Error())
 		UnauthorizedError(ctx, h, w)
 		return
 	}
 
+	h.logSignin(ctx, s, "")
 	encodeCookieSession(w, s)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// logSignin records the sign in that created the session s, or that failed
+// with the reason msg.
+func (h *SessionHandler) logSignin(ctx context.Context, s *platform.Session, msg string) {
+	h.logAudit(ctx, platform.AuditLogin, s, msg)
+}
+
+// logAudit records the action of the user of the session s, if any.
+func (h *SessionHandler) logAudit(ctx context.Context, action platform.AuditAction, s *platform.Session, msg string) {
+	if h.AuditLogger == nil {
+		return
+	}
+
+	e := audit.NewEvent(ctx, action)
+	e.Error = msg
+	if s != nil {
+		e.UserID = s.UserID
+		e.ResourceType = platform.UsersResourceType
+		e.ResourceID = s.UserID
+	}
+	if err := h.AuditLogger.LogAuditEvents(ctx, e); err != nil {
+		h.Logger.Info("Failed to record sign in or out", zap.Error(err))
+	}
+}
+
 type signinRequest struct {
 	Username string
 	Password string
@@ -112,11 +156,18 @@ func (h *SessionHandler) handleSignout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The session is found first for the sign out to be recorded with its user.
+	s, _ := h.SessionService.FindSession(ctx, req.Key)
+
 	if err := h.SessionService.ExpireSession(ctx, req.Key); err != nil {
 		UnauthorizedError(ctx, h, w)
 		return
 	}
 
+	if s != nil {
+		h.logAudit(ctx, platform.AuditLogout, s, "")
+	}
+
 	// TODO(desa): not sure what to do here maybe redirect?
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -165,3 +216,129 @@ func SetCookieSession(key string, r *http.Request) {
 
 	r.AddCookie(c)
 }
+
+const (
+	ssoSigninPath   = "/api/v2/signin/sso"
+	ssoCallbackPath = "/api/v2/signin/sso/callback"
+
+	// cookieSSOStateName is the cookie that binds the state and nonce of a
+	// single sign-on to the browser that started it.
+	cookieSSOStateName = "sso_state"
+	ssoStateMaxAge     = 10 * 60 // seconds
+)
+
+// handleSSOSignin is the HTTP handler for the GET /api/v2/signin/sso route. It
+// redirects to the login page of the identity provider.
+func (h *SessionHandler) handleSSOSignin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	gen := rand.NewTokenGenerator(32)
+	state, err := gen.Token()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	nonce, err := gen.Token()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.SSOService.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieSSOStateName,
+		Value:    state + "." + nonce,
+		Path:     ssoSigninPath,
+		MaxAge:   ssoStateMaxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// handleSSOCallback is the HTTP handler for the GET /api/v2/signin/sso/callback
+// route. The identity provider redirects to it once the user is authenticated,
+// it signs the user in and redirects to the UI.
+func (h *SessionHandler) handleSSOCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeSSOCallbackRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("Invalid single sign-on callback", zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	// The state is only valid once.
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieSSOStateName,
+		Path:   ssoSigninPath,
+		MaxAge: -1,
+	})
+
+	s, e := h.SSOService.Login(ctx, req.Code, req.Nonce)
+	if e != nil {
+		h.Logger.Info("Failed single sign-on", zap.Error(e))
+		h.logSignin(ctx, nil, e.Error())
+		if platform.ErrorCode(e) == platform.EUnavailable {
+			h.HandleHTTPError(ctx, e, w)
+			return
+		}
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	h.logSignin(ctx, s, "")
+	encodeCookieSession(w, s)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+type ssoCallbackRequest struct {
+	Code  string
+	Nonce string
+}
+
+func decodeSSOCallbackRequest(ctx context.Context, r *http.Request) (*ssoCallbackRequest, *platform.Error) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  fmt.Sprintf("identity provider returned %s: %s", e, q.Get("error_description")),
+		}
+	}
+
+	c, err := r.Cookie(cookieSSOStateName)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "missing single sign-on state",
+			Err:  err,
+		}
+	}
+	parts := strings.SplitN(c.Value, ".", 2)
+	if len(parts) != 2 || q.Get("state") == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(q.Get("state"))) != 1 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "single sign-on state does not match",
+		}
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "missing authorization code",
+		}
+	}
+
+	return &ssoCallbackRequest{
+		Code:  code,
+		Nonce: parts[1],
+	}, nil
+}
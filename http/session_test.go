@@ -3,15 +3,21 @@ package http_test
 import (
 	"context"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	jwt "github.com/dgrijalva/jwt-go"
 	"go.uber.org/zap"
 
 	platform "github.com/influxdata/influxdb"
 	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
 )
 
 // NewMockSessionBackend returns a SessionBackend with mock services.
@@ -99,3 +105,246 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionHandler_handleSSOCallback(t *testing.T) {
+	type args struct {
+		query  string
+		cookie string
+	}
+	type wants struct {
+		cookie string
+		code   int
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "successful sign in",
+			args: args{
+				query:  "code=abc&state=state",
+				cookie: "state.nonce",
+			},
+			wants: wants{
+				cookie: "session=abc123xyz",
+				code:   http.StatusFound,
+			},
+		},
+		{
+			name: "state mismatch",
+			args: args{
+				query:  "code=abc&state=other",
+				cookie: "state.nonce",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "missing state cookie",
+			args: args{
+				query: "code=abc&state=state",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "provider error",
+			args: args{
+				query:  "error=access_denied&state=state",
+				cookie: "state.nonce",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "failed login",
+			args: args{
+				query:  "code=invalid&state=state",
+				cookie: "state.nonce",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMockSessionBackend()
+			b.HTTPErrorHandler = platformhttp.ErrorHandler(0)
+			b.SSOService = &mock.SSOService{
+				LoginFn: func(ctx context.Context, code, nonce string) (*platform.Session, error) {
+					if code != "abc" || nonce != "nonce" {
+						return nil, &platform.Error{Code: platform.EUnauthorized}
+					}
+					return &platform.Session{Key: "abc123xyz"}, nil
+				},
+			}
+			h := platformhttp.NewSessionHandler(b)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/sso/callback?"+tt.args.query, nil)
+			if tt.args.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "sso_state", Value: tt.args.cookie})
+			}
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("bad status code: got %d want %d", got, want)
+			}
+
+			var session string
+			for _, c := range w.Result().Cookies() {
+				if c.Name == "session" {
+					session = c.String()
+				}
+			}
+			if got, want := session, tt.wants.cookie; got != want {
+				t.Errorf("unexpected session cookie: got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_SSO(t *testing.T) {
+	p := oidctest.NewProvider("influxdb", "secret")
+	defer p.Close()
+	p.Claims = jwt.MapClaims{"sub": "1234", "email": "jane@example.com"}
+
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The redirect URL of the client is the callback of the server.
+	var h http.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	sso, err := oidc.NewService(oidc.Config{
+		Issuer:       p.Issuer(),
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  srv.URL + "/api/v2/signin/sso/callback",
+	}, p.Client(), svc, svc, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewMockSessionBackend()
+	b.HTTPErrorHandler = platformhttp.ErrorHandler(0)
+	b.SessionService = svc
+	b.SSOService = sso
+	h = platformhttp.NewSessionHandler(b)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{
+		Jar: jar,
+		// Stop at the redirect to the UI.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Path == "/" {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(srv.URL + "/api/v2/signin/sso")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/" {
+		t.Fatalf("unexpected response: %s %s", resp.Status, resp.Header.Get("Location"))
+	}
+
+	var key string
+	for _, c := range resp.Cookies() {
+		if c.Name == "session" {
+			key = c.Value
+		}
+	}
+	s, err := svc.FindSession(ctx, key)
+	if err != nil {
+		t.Fatalf("session not found: %v", err)
+	}
+	u, err := svc.FindUserByID(ctx, s.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jane@example.com" {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+}
+
+func TestSessionHandler_Audit(t *testing.T) {
+	l := mock.NewAuditLogger()
+
+	b := NewMockSessionBackend()
+	b.HTTPErrorHandler = platformhttp.ErrorHandler(0)
+	b.AuditLogger = l
+	b.PasswordsService = &mock.PasswordsService{
+		ComparePasswordFn: func(ctx context.Context, name string, password string) error {
+			if password != "supersecret" {
+				return &platform.Error{Code: platform.EForbidden, Msg: "your username or password is incorrect"}
+			}
+			return nil
+		},
+	}
+	session := &platform.Session{
+		ID:        platform.ID(2),
+		Key:       "abc123xyz",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UserID:    platform.ID(1),
+	}
+	b.SessionService = &mock.SessionService{
+		CreateSessionFn: func(context.Context, string) (*platform.Session, error) {
+			return session, nil
+		},
+		FindSessionFn: func(context.Context, string) (*platform.Session, error) {
+			return session, nil
+		},
+		ExpireSessionFn: func(context.Context, string) error {
+			return nil
+		},
+	}
+	h := platformhttp.NewSessionHandler(b)
+
+	for _, password := range []string{"wrong", "supersecret"} {
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signin", nil)
+		r.SetBasicAuth("user1", password)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signout", nil)
+	platformhttp.SetCookieSession(session.Key, r)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	want := []struct {
+		action platform.AuditAction
+		userID platform.ID
+		failed bool
+	}{
+		{action: platform.AuditLogin, failed: true},
+		{action: platform.AuditLogin, userID: 1},
+		{action: platform.AuditLogout, userID: 1},
+	}
+	if len(l.Events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), l.Events)
+	}
+	for i, e := range l.Events {
+		if e.Action != want[i].action || e.UserID != want[i].userID || (e.Error != "") != want[i].failed {
+			t.Errorf("unexpected event %d: %+v", i, e)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 
 	"github.com/golang/gddo/httputil"
@@ -317,7 +319,7 @@ func decodeTelegrafConfigFilter(ctx context.Context, r *http.Request) (*platform
 		}
 		f.OrgID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Organization = orgNameStr
+		f.Organization = &orgNameStr
 	}
 	return f, err
 }
@@ -429,3 +431,86 @@ func (h *TelegrafHandler) handleDeleteTelegraf(w http.ResponseWriter, r *http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// TelegrafService connects to Influx via HTTP using tokens to manage telegraf configs.
+type TelegrafService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindTelegrafConfigByID returns a single telegraf config by ID.
+func (s *TelegrafService) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+	var tc platform.TelegrafConfig
+	if err := s.do(ctx, "GET", telegrafIDPath(id), nil, nil, &tc); err != nil {
+		return nil, err
+	}
+	return &tc, nil
+}
+
+// FindTelegrafConfigs returns a list of telegraf configs that match filter and the total count of matching telegraf configs.
+func (s *TelegrafService) FindTelegrafConfigs(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, error) {
+	query := url.Values{}
+	if filter.OrgID != nil {
+		query.Set("orgID", filter.OrgID.String())
+	}
+	if filter.Organization != nil {
+		query.Set("org", *filter.Organization)
+	}
+	if filter.UserID.Valid() {
+		query.Set("userID", filter.UserID.String())
+	}
+
+	var res struct {
+		TelegrafConfigs []*platform.TelegrafConfig `json:"configurations"`
+	}
+	if err := s.do(ctx, "GET", telegrafsPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.TelegrafConfigs, len(res.TelegrafConfigs), nil
+}
+
+// CreateTelegrafConfig creates a new telegraf config and sets tc.ID with the new identifier.
+// The telegraf config is owned by the user of the token, so userID is ignored.
+func (s *TelegrafService) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+	var created platform.TelegrafConfig
+	if err := s.do(ctx, "POST", telegrafsPath, nil, newTelegrafRequest(tc), &created); err != nil {
+		return err
+	}
+	tc.ID = created.ID
+	return nil
+}
+
+// UpdateTelegrafConfig updates a single telegraf config.
+// Returns the new telegraf config after update.
+func (s *TelegrafService) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
+	var updated platform.TelegrafConfig
+	if err := s.do(ctx, "PUT", telegrafIDPath(id), nil, newTelegrafRequest(tc), &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTelegrafConfig removes a telegraf config by ID.
+func (s *TelegrafService) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
+	return s.do(ctx, "DELETE", telegrafIDPath(id), nil, nil, nil)
+}
+
+func (s *TelegrafService) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, method, path, query, body, v)
+}
+
+// newTelegrafRequest returns the request body for tc. The ID of tc is not
+// known before it is created, so it is encoded with a placeholder that the
+// server replaces.
+func newTelegrafRequest(tc *platform.TelegrafConfig) *platform.TelegrafConfig {
+	req := *tc
+	if !req.ID.Valid() {
+		req.ID = 1
+	}
+	return &req
+}
+
+func telegrafIDPath(id platform.ID) string {
+	return path.Join(telegrafsPath, id.String())
+}
@@ -935,3 +935,89 @@ func Test_newTelegrafResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegrafService_Client(t *testing.T) {
+	var stored *platform.TelegrafConfig
+	backend := NewMockTelegrafBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	backend.TelegrafService = &mock.TelegrafConfigStore{
+		CreateTelegrafConfigF: func(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+			tc.ID = 2
+			stored = tc
+			return nil
+		},
+		FindTelegrafConfigByIDF: func(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+			if stored == nil || id != stored.ID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrTelegrafConfigNotFound}
+			}
+			return stored, nil
+		},
+		FindTelegrafConfigsF: func(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, error) {
+			if filter.OrgID == nil || *filter.OrgID != stored.OrgID {
+				return nil, 0, fmt.Errorf("unexpected filter %+v", filter)
+			}
+			return []*platform.TelegrafConfig{stored}, 1, nil
+		},
+		UpdateTelegrafConfigF: func(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
+			stored = tc
+			return tc, nil
+		},
+		DeleteTelegrafConfigF: func(ctx context.Context, id platform.ID) error {
+			stored = nil
+			return nil
+		},
+	}
+	server := newAuthorizedTestServer(NewTelegrafHandler(backend), 1)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &TelegrafService{Addr: server.URL}
+	tc := &platform.TelegrafConfig{
+		OrgID: 1,
+		Name:  "tc1",
+		Agent: platform.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []platform.TelegrafPlugin{
+			{Config: &inputs.CPUStats{}},
+			{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.1:9999"}, Token: "token", Organization: "org", Bucket: "bucket"}},
+		},
+	}
+	if err := client.CreateTelegrafConfig(ctx, tc, 0); err != nil {
+		t.Fatalf("unable to create telegraf config: %v", err)
+	}
+	if tc.ID != 2 {
+		t.Fatalf("unexpected telegraf config id: got %s", tc.ID)
+	}
+
+	found, err := client.FindTelegrafConfigByID(ctx, tc.ID)
+	if err != nil {
+		t.Fatalf("unable to find telegraf config: %v", err)
+	}
+	if found.Name != "tc1" || len(found.Plugins) != 2 {
+		t.Errorf("unexpected telegraf config: %+v", found)
+	}
+
+	orgID := platform.ID(1)
+	tcs, n, err := client.FindTelegrafConfigs(ctx, platform.TelegrafConfigFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("unable to find telegraf configs: %v", err)
+	}
+	if n != 1 || tcs[0].ID != tc.ID {
+		t.Errorf("unexpected telegraf configs: %+v", tcs)
+	}
+
+	found.Name = "tc2"
+	updated, err := client.UpdateTelegrafConfig(ctx, tc.ID, found, 0)
+	if err != nil {
+		t.Fatalf("unable to update telegraf config: %v", err)
+	}
+	if updated.Name != "tc2" {
+		t.Errorf("unexpected updated telegraf config: %+v", updated)
+	}
+
+	if err := client.DeleteTelegrafConfig(ctx, tc.ID); err != nil {
+		t.Fatalf("unable to delete telegraf config: %v", err)
+	}
+	if _, err := client.FindTelegrafConfigByID(ctx, tc.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected deleted telegraf config to be not found, got %v", err)
+	}
+}
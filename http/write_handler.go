@@ -1,24 +1,31 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/influxdata/influxdb/http/metric"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/write"
 )
 
 // WriteBackend is all services and associated parameters required to construct
@@ -31,6 +38,7 @@ type WriteBackend struct {
 	PointsWriter        storage.PointsWriter
 	BucketService       platform.BucketService
 	OrganizationService platform.OrganizationService
+	WriteLimiter        WriteLimiter
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
@@ -43,10 +51,12 @@ func NewWriteBackend(b *APIBackend) *WriteBackend {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		WriteLimiter:        b.WriteLimiter,
 	}
 }
 
-// WriteHandler receives line protocol and sends to a publish function.
+// WriteHandler receives line protocol, annotated CSV or JSON and sends it to
+// a publish function.
 type WriteHandler struct {
 	*httprouter.Router
 	platform.HTTPErrorHandler
@@ -57,16 +67,32 @@ type WriteHandler struct {
 
 	PointsWriter storage.PointsWriter
 
+	// WriteLimiter limits the writes of each organization, writes are not
+	// limited if it is nil.
+	WriteLimiter WriteLimiter
+
 	EventRecorder metric.EventRecorder
 }
 
+// WriteLimiter limits the rate of the writes of each organization.
+type WriteLimiter interface {
+	// ReserveWriteBytes returns an error if a write request of n bytes
+	// exceeds the limits of the organization, and otherwise a function giving
+	// the bytes back if the request is rejected once parsed.
+	ReserveWriteBytes(orgID platform.ID, n int) (func(), error)
+	// AllowWritePoints returns an error if writing n points exceeds the
+	// limits of the organization.
+	AllowWritePoints(orgID platform.ID, n int) error
+}
+
 const (
 	writePath            = "/api/v2/write"
 	errInvalidGzipHeader = "gzipped HTTP body contains an invalid header"
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
 
-// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
+// NewWriteHandler creates a new handler at /api/v2/write to receive line
+// protocol, annotated CSV or JSON.
 func NewWriteHandler(b *WriteBackend) *WriteHandler {
 	h := &WriteHandler{
 		Router:           NewRouter(b.HTTPErrorHandler),
@@ -76,6 +102,7 @@ func NewWriteHandler(b *WriteBackend) *WriteHandler {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		WriteLimiter:        b.WriteLimiter,
 		EventRecorder:       b.WriteEventRecorder,
 	}
 
@@ -146,35 +173,13 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	orgID = org.ID
 
-	var bucket *platform.Bucket
-	if id, err := platform.IDFromString(req.Bucket); err == nil {
-		// Decoded ID successfully. Make sure it's a real bucket.
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			ID:             id,
-		})
-		if err == nil {
-			bucket = b
-		} else if platform.ErrorCode(err) != platform.ENotFound {
-			h.HandleHTTPError(ctx, err, w)
-			return
-		}
-	}
-
-	if bucket == nil {
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			Name:           &req.Bucket,
-		})
-		if err != nil {
-			h.HandleHTTPError(ctx, &platform.Error{
-				Op:  "http/handleWrite",
-				Err: err,
-			}, w)
-			return
-		}
-
-		bucket = b
+	bucket, err := queryBucket(ctx, org.ID, req.Bucket, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Op:  "http/handleWrite",
+			Err: err,
+		}, w)
+		return
 	}
 
 	// TODO(jade): remove this after system buckets issue is resolved
@@ -198,7 +203,7 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.Allowed(*p) {
+	if !authorizer.Allowed(ctx, a, *p) {
 		h.HandleHTTPError(ctx, &platform.Error{
 			Code: platform.EForbidden,
 			Op:   "http/handleWrite",
@@ -223,9 +228,22 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	}
 	requestBytes = len(data)
 
+	release := func() {}
+	if h.WriteLimiter != nil {
+		if release, err = h.WriteLimiter.ReserveWriteBytes(org.ID, len(data)); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	encoded := tsdb.EncodeName(org.ID, bucket.ID)
 	mm := models.EscapeMeasurement(encoded[:])
-	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
+	if req.Partial {
+		h.writePartial(w, r, logger, org.ID, data, mm, req.Precision, release)
+		return
+	}
+
+	points, err := parsePoints(req.Format, data, mm, req.Precision)
 	if err != nil {
 		logger.Error("Error parsing points", zap.Error(err))
 		h.HandleHTTPError(ctx, &platform.Error{
@@ -237,10 +255,19 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.WriteLimiter != nil {
+		if err := h.WriteLimiter.AllowWritePoints(org.ID, len(points)); err != nil {
+			// The request is not written, so its bytes are given back.
+			release()
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
 		logger.Error("Error writing points", zap.Error(err))
 		h.HandleHTTPError(ctx, &platform.Error{
-			Code: platform.EInternal,
+			Code: writeErrorCode(err),
 			Op:   "http/handleWrite",
 			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
 			Err:  err,
@@ -251,6 +278,168 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// maxRejectedLineLength is the maximum length of the text of a rejected line
+// in the response of a partial write.
+const maxRejectedLineLength = 256
+
+// partialWriteResponse is the response of a partial write. Accepted and
+// Rejected count lines of line protocol.
+type partialWriteResponse struct {
+	Accepted int            `json:"accepted"`
+	Rejected int            `json:"rejected"`
+	Errors   []rejectedLine `json:"errors"`
+}
+
+// rejectedLine is a line of a partial write that was not written.
+type rejectedLine struct {
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Message string `json:"message"`
+}
+
+// writePartial writes every line of line protocol that can be parsed and
+// responds with the lines that were rejected, either because they could not
+// be parsed or because the storage engine dropped them, such as on a field
+// type conflict. A line is rejected if any of its fields was dropped. The bytes
+// of the request were reserved by the caller, release gives them back.
+func (h *WriteHandler) writePartial(w http.ResponseWriter, r *http.Request, logger *zap.Logger, orgID platform.ID, data, mm []byte, precision string, release func()) {
+	ctx := r.Context()
+	points, lines, lineErrs := models.ParseLinesWithPrecision(data, mm, time.Now(), precision)
+
+	if h.WriteLimiter != nil {
+		if err := h.WriteLimiter.AllowWritePoints(orgID, len(points)); err != nil {
+			release()
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	rejected := make(map[int]rejectedLine, len(lineErrs))
+	for _, le := range lineErrs {
+		rejected[le.Line] = rejectedLine{
+			Line:    le.Line,
+			Text:    truncateLine(le.Text),
+			Message: le.Err.Error(),
+		}
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		pwe, ok := partialWriteError(err)
+		if !ok {
+			logger.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   "http/handleWrite",
+				Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+
+		pointLines := make(map[models.Point]int, len(points))
+		for i, p := range points {
+			pointLines[p] = lines[i]
+		}
+		for _, dp := range pwe.DroppedPoints {
+			line, ok := pointLines[dp.Point]
+			if !ok {
+				continue
+			}
+			if _, ok := rejected[line]; ok {
+				continue
+			}
+			rejected[line] = rejectedLine{
+				Line:    line,
+				Text:    truncateLine(lineText(data, line)),
+				Message: dp.Reason,
+			}
+		}
+	}
+
+	accepted := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		if _, ok := rejected[line]; !ok {
+			accepted[line] = true
+		}
+	}
+
+	res := partialWriteResponse{
+		Accepted: len(accepted),
+		Rejected: len(rejected),
+		Errors:   make([]rejectedLine, 0, len(rejected)),
+	}
+	for _, rl := range rejected {
+		res.Errors = append(res.Errors, rl)
+	}
+	sort.Slice(res.Errors, func(i, j int) bool { return res.Errors[i].Line < res.Errors[j].Line })
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(logger, r, err)
+	}
+}
+
+// writeErrorCode returns the code of an error writing points. Writes rejected
+// by a series limit are unprocessable, any other error is internal.
+func writeErrorCode(err error) string {
+	if platform.ErrorCode(err) == platform.EUnprocessableEntity {
+		return platform.EUnprocessableEntity
+	}
+	return platform.EInternal
+}
+
+// partialWriteError returns the tsdb.PartialWriteError of err, which the
+// storage engine wraps in a platform error when series limits were exceeded.
+func partialWriteError(err error) (tsdb.PartialWriteError, bool) {
+	if perr, ok := err.(*platform.Error); ok {
+		err = perr.Err
+	}
+	pwe, ok := err.(tsdb.PartialWriteError)
+	return pwe, ok
+}
+
+// lineText returns the 1-based line of data, without leading white space.
+func lineText(data []byte, line int) string {
+	for i := 1; i < line; i++ {
+		n := bytes.IndexByte(data, '\n')
+		if n < 0 {
+			return ""
+		}
+		data = data[n+1:]
+	}
+	if n := bytes.IndexByte(data, '\n'); n >= 0 {
+		data = data[:n]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// truncateLine truncates the text of a rejected line to maxRejectedLineLength
+// bytes, without splitting a multi-byte character.
+func truncateLine(text string) string {
+	if len(text) <= maxRejectedLineLength {
+		return text
+	}
+	n := maxRejectedLineLength
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n] + "..."
+}
+
+// parsePoints parses the body of a write request. CSV and JSON are converted
+// to line protocol first, so that all formats are written to the bucket the
+// same way.
+func parsePoints(format write.Format, data, mm []byte, precision string) ([]models.Point, error) {
+	now := time.Now()
+	if format != write.FormatLineProtocol {
+		points, err := write.ParsePoints(format, bytes.NewReader(data), now, precision)
+		if err != nil {
+			return nil, err
+		}
+		data, precision = write.LineProtocol(points), "ns"
+	}
+	return models.ParsePointsWithPrecision(data, mm, now, precision)
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
@@ -266,24 +455,49 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
-	return &postWriteRequest{
+	req := &postWriteRequest{
 		Bucket:    qp.Get("bucket"),
 		Org:       qp.Get("org"),
 		Precision: p,
-	}, nil
+		Format:    write.FormatFromContentType(r.Header.Get("Content-Type")),
+	}
+
+	if partial := qp.Get("partial"); partial != "" {
+		var err error
+		if req.Partial, err = strconv.ParseBool(partial); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  "partial must be true or false",
+			}
+		}
+		if req.Partial && req.Format != write.FormatLineProtocol {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  "partial writes are only supported for line protocol",
+			}
+		}
+	}
+
+	return req, nil
 }
 
 type postWriteRequest struct {
 	Org       string
 	Bucket    string
 	Precision string
+	Format    write.Format
+	Partial   bool
 }
 
-// WriteService sends data over HTTP to influxdb via line protocol.
+// WriteService sends data over HTTP to influxdb via line protocol, or in
+// the given format.
 type WriteService struct {
 	Addr               string
 	Token              string
 	Precision          string
+	Format             write.Format
 	InsecureSkipVerify bool
 }
 
@@ -318,7 +532,7 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 		return err
 	}
 
-	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Type", s.Format.ContentType())
 	req.Header.Set("Content-Encoding", "gzip")
 	SetToken(s.Token, req)
 
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"io"
@@ -10,7 +11,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/limits"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +78,287 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+// newTestWriteHandler returns a WriteHandler that writes to the bucket bucketID
+// of the organization orgID, whatever their names.
+func newTestWriteHandler(pw storage.PointsWriter, orgID, bucketID platform.ID) *WriteHandler {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+		return &platform.Organization{ID: orgID, Name: "org"}, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: bucketID, OrgID: orgID, Name: "bucket"}, nil
+	}
+
+	return NewWriteHandler(&WriteBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		WriteEventRecorder:  noopEventRecorder{},
+		PointsWriter:        pw,
+		BucketService:       buckets,
+		OrganizationService: orgs,
+	})
+}
+
+// newTestWriteRequest returns a write request that is allowed to write to the
+// buckets of the organization orgID.
+func newTestWriteRequest(url, contentType, body string, orgID platform.ID) *http.Request {
+	r := httptest.NewRequest("POST", url, strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+		Status: platform.Active,
+		Permissions: []platform.Permission{
+			{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID}},
+		},
+	}))
+}
+
+func TestWriteHandler_handleWrite(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+		points     []string
+	}
+
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wants       wants
+	}{
+		{
+			name:        "line protocol",
+			contentType: "text/plain; charset=utf-8",
+			body:        "m,t=v f=1 1000",
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				points:     []string{"m,t=v f=1 1000"},
+			},
+		},
+		{
+			name:        "annotated csv",
+			contentType: "text/csv",
+			body:        "#datatype measurement,tag,long,dateTime:number\nm,t,f,time\nm,v,1,1000\n",
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				points:     []string{"m,t=v f=1i 1000"},
+			},
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `[{"measurement":"m","tags":{"t":"v"},"fields":{"f":1},"time":1000}]`,
+			wants: wants{
+				statusCode: http.StatusNoContent,
+				points:     []string{"m,t=v f=1 1000"},
+			},
+		},
+		{
+			name:        "invalid csv",
+			contentType: "text/csv",
+			body:        "#datatype measurement,tag,long\nm,t,f\nm,v,x\n",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code":"invalid","op":"http/handleWrite","message":"unable to parse points: row 3, column 3: invalid long value \"x\"","error":"row 3, column 3: invalid long value \"x\""}`,
+			},
+		},
+		{
+			name:        "invalid json",
+			contentType: "application/json",
+			body:        "[\n  {\"measurement\":\"m\",\"fields\":{}}\n]",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code":"invalid","op":"http/handleWrite","message":"unable to parse points: row 2, column 3: point has no fields","error":"row 2, column 3: point has no fields"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw, orgID, bucketID)
+
+			r := newTestWriteRequest("/api/v2/write?org=org&bucket=bucket", tt.contentType, tt.body, orgID)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if got, exp := res.StatusCode, tt.wants.statusCode; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, _ := jsonEqual(string(body), tt.wants.body); !eq {
+					t.Errorf("unexpected body -got/+want\n%s", diff)
+				}
+			}
+
+			var got []string
+			for _, p := range pw.Points {
+				name, tags := models.ParseKeyBytes(p.Key())
+				if !bytes.Equal(tags.Get(models.MeasurementTagKeyBytes), []byte("m")) {
+					t.Errorf("unexpected measurement of point %s", p)
+				}
+				if org, bucket := tsdb.DecodeNameSlice(name); org != orgID || bucket != bucketID {
+					t.Errorf("unexpected org and bucket of point %s: %s, %s", p, org, bucket)
+				}
+				fields, err := p.Fields()
+				if err != nil {
+					t.Fatal(err)
+				}
+				for k, v := range fields {
+					pt := models.MustNewPoint("m", models.NewTags(map[string]string{"t": string(tags.Get([]byte("t")))}), models.Fields{k: v}, p.Time())
+					got = append(got, pt.String())
+				}
+			}
+			if !cmp.Equal(got, tt.wants.points) {
+				t.Errorf("unexpected points -got/+want\n%s", cmp.Diff(got, tt.wants.points))
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWriteSeriesLimit(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	pw := &mock.PointsWriter{}
+	pw.ForceError(&platform.Error{
+		Code: platform.EUnprocessableEntity,
+		Op:   "storage/WritePoints",
+		Msg:  "series limit exceeded: 1 points of new series were rejected",
+		Err:  tsdb.PartialWriteError{Reason: "series limit exceeded", Dropped: 1},
+	})
+	h := newTestWriteHandler(pw, orgID, bucketID)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newTestWriteRequest("/api/v2/write?org=org&bucket=bucket", "text/plain", "m f=1 1\n", orgID))
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if got, exp := res.StatusCode, http.StatusUnprocessableEntity; got != exp {
+		t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+	}
+	if !strings.Contains(string(body), `"code":"unprocessable entity"`) {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestWriteHandler_handleWriteRateLimit(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw, orgID, bucketID)
+	h.WriteLimiter = limits.NewLimiter(limits.Config{WritePointsPerSecond: 2})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "within the limit", body: "m f=1 1\nm f=2 2\n", status: http.StatusNoContent},
+		{name: "exceeds the limit", body: "m f=3 3\n", status: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newTestWriteRequest("/api/v2/write?org=org&bucket=bucket", "text/plain", tt.body, orgID))
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if got, exp := res.StatusCode, tt.status; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if tt.status == http.StatusTooManyRequests && res.Header.Get("Retry-After") != "1" {
+				t.Errorf("unexpected Retry-After header: %q", res.Header.Get("Retry-After"))
+			}
+		})
+	}
+}
+
+// conflictPointsWriter drops the points with string fields, as if they
+// conflicted with the type of existing fields.
+type conflictPointsWriter struct {
+	points []models.Point
+}
+
+func (pw *conflictPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	var pwe tsdb.PartialWriteError
+	for _, p := range points {
+		fi := p.FieldIterator()
+		if fi.Next() && fi.Type() == models.String {
+			pwe.Dropped++
+			pwe.DroppedKeys = append(pwe.DroppedKeys, p.Key())
+			pwe.DroppedPoints = append(pwe.DroppedPoints, tsdb.DroppedPoint{Point: p, Reason: "conflicting field type"})
+			continue
+		}
+		pw.points = append(pw.points, p)
+	}
+	if pwe.Dropped > 0 {
+		return pwe
+	}
+	return nil
+}
+
+func TestWriteHandler_handleWritePartial(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		statusCode int
+		want       string
+		written    int
+	}{
+		{
+			name:       "all lines written",
+			url:        "/api/v2/write?org=org&bucket=bucket&partial=true",
+			body:       "m f=1 1\nm f=2 2\n",
+			statusCode: http.StatusOK,
+			want:       `{"accepted":2,"rejected":0,"errors":[]}`,
+			written:    2,
+		},
+		{
+			name:       "parse errors and field type conflicts",
+			url:        "/api/v2/write?org=org&bucket=bucket&partial=true",
+			body:       "m f=1 1\nm f= 2\n\nm f=3,g=\"x\" 3\n  m f=4 4\n" + "m " + strings.Repeat("x", 300) + "\n",
+			statusCode: http.StatusOK,
+			want: `{"accepted":2,"rejected":3,"errors":[
+				{"line":2,"text":"m f= 2","message":"missing field value"},
+				{"line":4,"text":"m f=3,g=\"x\" 3","message":"conflicting field type"},
+				{"line":6,"text":"m ` + strings.Repeat("x", 254) + `...","message":"invalid field format"}
+			]}`,
+			written: 3,
+		},
+		{
+			name:       "invalid partial",
+			url:        "/api/v2/write?org=org&bucket=bucket&partial=maybe",
+			body:       "m f=1 1\n",
+			statusCode: http.StatusBadRequest,
+			want:       `{"code":"invalid","op":"http/decodeWriteRequest","message":"partial must be true or false"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &conflictPointsWriter{}
+			h := newTestWriteHandler(pw, orgID, bucketID)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newTestWriteRequest(tt.url, "text/plain", tt.body, orgID))
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if got, exp := res.StatusCode, tt.statusCode; got != exp {
+				t.Fatalf("unexpected status code: got %d, exp %d: %s", got, exp, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body -got/+want\n%s %v", diff, err)
+			}
+			if got, exp := len(pw.points), tt.written; got != exp {
+				t.Errorf("unexpected number of written points: got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	platform "github.com/influxdata/influxdb"
 )
 
+// loadAuthorization returns the authorization of id as it is stored, with the
+// hash of its token in place of the token.
 func (s *Service) loadAuthorization(ctx context.Context, id platform.ID) (*platform.Authorization, *platform.Error) {
 	i, ok := s.authorizationKV.Load(id.String())
 	if !ok {
@@ -30,24 +32,27 @@ func (s *Service) loadAuthorization(ctx context.Context, id platform.ID) (*platf
 	return &a, nil
 }
 
-// PutAuthorization overwrites the authorization with the contents of a.
+// PutAuthorization overwrites the authorization with the contents of a. Only
+// the hash of the token of the authorization is stored.
 func (s *Service) PutAuthorization(ctx context.Context, a *platform.Authorization) error {
 	if a.Status == "" {
 		a.Status = platform.Active
 	}
-	s.authorizationKV.Store(a.ID.String(), *a)
+	stored := *a
+	stored.Token = platform.HashToken(a.Token)
+	s.authorizationKV.Store(a.ID.String(), stored)
 	return nil
 }
 
 // FindAuthorizationByID returns an authorization given an ID.
 func (s *Service) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
-	var err error
 	a, pe := s.loadAuthorization(ctx, id)
 	if pe != nil {
 		pe.Op = OpPrefix + platform.OpFindAuthorizationByID
-		err = pe
+		return nil, pe
 	}
-	return a, err
+	a.Token = ""
+	return a, nil
 }
 
 // FindAuthorizationByToken returns an authorization given a token.
@@ -70,6 +75,15 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, t string) (*plat
 			Op:   op,
 		}
 	}
+
+	// Expired tokens cannot be used, their authorizations are only found by id.
+	if as[0].IsExpired(s.Now()) {
+		return nil, &platform.Error{
+			Code: platform.ErrAuthorizationExpired.Code,
+			Msg:  platform.ErrAuthorizationExpired.Msg,
+			Op:   op,
+		}
+	}
 	return as[0], nil
 }
 
@@ -81,8 +95,9 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 	}
 
 	if filter.Token != nil {
+		h := platform.HashToken(*filter.Token)
 		return func(a *platform.Authorization) bool {
-			return a.Token == *filter.Token
+			return a.Token == h
 		}
 	}
 
@@ -160,6 +175,11 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 		}
 
 		if filterF(&a) {
+			// Only the authorizations found by token are returned with their token.
+			a.Token = ""
+			if filter.Token != nil {
+				a.Token = *filter.Token
+			}
 			as = append(as, &a)
 		}
 
@@ -220,10 +240,10 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id platform.ID) error
 // UpdateAuthorization updates the status and description if available.
 func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	op := OpPrefix + platform.OpUpdateAuthorization
-	a, err := s.FindAuthorizationByID(ctx, id)
-	if err != nil {
+	a, pe := s.loadAuthorization(ctx, id)
+	if pe != nil {
 		return nil, &platform.Error{
-			Err: err,
+			Err: pe,
 			Op:  op,
 		}
 	}
@@ -246,5 +266,7 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *
 		a.Description = *upd.Description
 	}
 
-	return a, s.PutAuthorization(ctx, a)
+	s.authorizationKV.Store(a.ID.String(), *a)
+	a.Token = ""
+	return a, nil
 }
@@ -246,6 +246,22 @@ func (s *Service) findSystemBucket(n string) (*platform.Bucket, error) {
 			RetentionPeriod: time.Hour * 24 * 7,
 			Description:     "System bucket for monitoring logs",
 		}, nil
+	case "_audit":
+		return &platform.Bucket{
+			ID:              platform.AuditSystemBucketID,
+			Type:            platform.BucketTypeSystem,
+			Name:            "_audit",
+			RetentionPeriod: time.Hour * 24 * 30,
+			Description:     "System bucket for audit logs",
+		}, nil
+	case "_queries":
+		return &platform.Bucket{
+			ID:              platform.QueriesSystemBucketID,
+			Type:            platform.BucketTypeSystem,
+			Name:            "_queries",
+			RetentionPeriod: time.Hour * 24 * 7,
+			Description:     "System bucket for query logs",
+		}, nil
 	default:
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
@@ -311,6 +327,10 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.Description = *upd.Description
 	}
 
+	if upd.SchemaType != nil {
+		b.SchemaType = *upd.SchemaType
+	}
+
 	b0, err := s.FindBucket(ctx, platform.BucketFilter{
 		Name: upd.Name,
 	})
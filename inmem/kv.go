@@ -3,7 +3,9 @@ package inmem
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/google/btree"
@@ -62,6 +64,11 @@ func (s *KVStore) Flush(ctx context.Context) {
 	}
 }
 
+// Backup is not supported by the in memory store.
+func (s *KVStore) Backup(ctx context.Context, w io.Writer) error {
+	return errors.New("in-memory kv store does not support backups")
+}
+
 // Buckets returns the names of all buckets within inmem.KVStore.
 func (s *KVStore) Buckets(ctx context.Context) [][]byte {
 	s.mu.RLock()
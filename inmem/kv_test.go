@@ -38,6 +38,12 @@ func TestKVStore(t *testing.T) {
 	platformtesting.KVStore(initKVStore, t)
 }
 
+func TestKVStore_Migrations(t *testing.T) {
+	platformtesting.KVMigrations(func(t *testing.T) (kv.Store, func()) {
+		return inmem.NewKVStore(), func() {}
+	}, t)
+}
+
 func TestKVStore_Buckets(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 )
@@ -13,7 +14,33 @@ var (
 	authIndex  = []byte("authorizationindexv1")
 )
 
-var _ influxdb.AuthorizationService = (*Service)(nil)
+// authTokenIndex indexes the authorizations by the hash of their token, or by
+// their token for the authorizations stored before tokens were hashed.
+var authTokenIndex = NewIndex("authorizations by token", authBucket, authIndex, func(v []byte) ([]byte, error) {
+	var r authorizationRecord
+	if err := decodeAuthorization(v, &r); err != nil {
+		return nil, err
+	}
+	switch {
+	case r.TokenHash != "":
+		return authIndexKey(r.TokenHash), nil
+	case r.Token != "":
+		return []byte(r.Token), nil
+	}
+	return nil, nil
+})
+
+var (
+	_ influxdb.AuthorizationService       = (*Service)(nil)
+	_ influxdb.AuthorizationUsageRecorder = (*Service)(nil)
+)
+
+// authorizationRecord is an authorization as it is stored: the hash of its
+// token is stored in place of the token.
+type authorizationRecord struct {
+	influxdb.Authorization
+	TokenHash string `json:"tokenHash,omitempty"`
+}
 
 func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(authBucket); err != nil {
@@ -25,6 +52,36 @@ func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	return nil
 }
 
+// migrateAuthorizationTokens replaces the tokens of the authorizations stored
+// before tokens were hashed by their hash, both in the authorizations and in
+// the token index. The tokens cannot be restored, so the migration cannot be
+// reverted.
+func (s *Service) migrateAuthorizationTokens(ctx context.Context, tx Tx) error {
+	var legacy []*authorizationRecord
+	err := s.forEachAuthorizationRecord(ctx, tx, func(r *authorizationRecord) bool {
+		if r.TokenHash == "" && r.Token != "" {
+			legacy = append(legacy, r)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range legacy {
+		// Legacy authorizations are indexed by their token.
+		if err := authTokenIndex.Delete(tx, []byte(r.Token)); err != nil {
+			return err
+		}
+
+		r.TokenHash = influxdb.HashToken(r.Token)
+		if err := s.putAuthorizationRecord(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // FindAuthorizationByID retrieves a authorization by id.
 func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
@@ -46,6 +103,15 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*i
 }
 
 func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Authorization, error) {
+	r, err := s.findAuthorizationRecordByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.Token = ""
+	return &r.Authorization, nil
+}
+
+func (s *Service) findAuthorizationRecordByID(ctx context.Context, tx Tx, id influxdb.ID) (*authorizationRecord, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -71,18 +137,20 @@ func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.
 		return nil, err
 	}
 
-	a := &influxdb.Authorization{}
-	if err := decodeAuthorization(v, a); err != nil {
+	r := &authorizationRecord{}
+	if err := decodeAuthorization(v, r); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
 
-	return a, nil
+	return r, nil
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
+// The token of the authorization is set to n, the tokens of the authorizations
+// returned by other methods are empty.
 func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
 	err := s.kv.View(ctx, func(tx Tx) error {
@@ -104,12 +172,7 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*infl
 }
 
 func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string) (*influxdb.Authorization, error) {
-	idx, err := authIndexBucket(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	a, err := idx.Get(authIndexKey(n))
+	a, err := authTokenIndex.Lookup(tx, authIndexKey(influxdb.HashToken(n)))
 	if IsNotFound(err) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -124,7 +187,19 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 			Err:  err,
 		}
 	}
-	return s.findAuthorizationByID(ctx, tx, id)
+
+	auth, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expired tokens cannot be used, their authorizations are only found by id.
+	if auth.IsExpired(s.Now()) {
+		return nil, influxdb.ErrAuthorizationExpired
+	}
+
+	auth.Token = n
+	return auth, nil
 }
 
 func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxdb.Authorization) bool {
@@ -134,12 +209,6 @@ func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxd
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *influxdb.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
-
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
 		return func(a *influxdb.Authorization) bool {
@@ -262,7 +331,7 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 		return influxdb.ErrUnableToCreateToken
 	}
 
-	if err := s.uniqueAuthToken(ctx, tx, a); err != nil {
+	if err := s.validRoleIDs(ctx, tx, a.OrgID, a.RoleIDs); err != nil {
 		return err
 	}
 
@@ -276,6 +345,10 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 		a.Token = token
 	}
 
+	if err := s.uniqueAuthToken(ctx, tx, a); err != nil {
+		return err
+	}
+
 	a.ID = s.IDGenerator.ID()
 
 	if err := s.putAuthorization(ctx, tx, a); err != nil {
@@ -285,18 +358,19 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 	return nil
 }
 
-// PutAuthorization will put a authorization without setting an ID.
+// PutAuthorization will put a authorization without setting an ID. Only the
+// hash of the token of the authorization is stored.
 func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		return s.putAuthorization(ctx, tx, a)
 	})
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
-	switch a.Status {
+func encodeAuthorization(r *authorizationRecord) ([]byte, error) {
+	switch r.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
-		a.Status = influxdb.Active
+		r.Status = influxdb.Active
 	default:
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -304,11 +378,25 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	stored := *r
+	stored.Token = ""
+	return json.Marshal(&stored)
 }
 
 func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	v, err := encodeAuthorization(a)
+	r := &authorizationRecord{
+		Authorization: *a,
+		TokenHash:     influxdb.HashToken(a.Token),
+	}
+	if err := s.putAuthorizationRecord(ctx, tx, r); err != nil {
+		return err
+	}
+	a.Status = r.Status
+	return nil
+}
+
+func (s *Service) putAuthorizationRecord(ctx context.Context, tx Tx, r *authorizationRecord) error {
+	v, err := encodeAuthorization(r)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -316,7 +404,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		}
 	}
 
-	encodedID, err := a.ID.Encode()
+	encodedID, err := r.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -324,12 +412,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		}
 	}
 
-	idx, err := authIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := authTokenIndex.Insert(tx, authIndexKey(r.TokenHash), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -350,22 +433,33 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 	return nil
 }
 
-func authIndexKey(n string) []byte {
-	return []byte(n)
+// authIndexKey returns the key of the token index of the token hash h.
+func authIndexKey(h string) []byte {
+	return []byte(h)
 }
 
-func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
-	if err := json.Unmarshal(b, a); err != nil {
+// decodeAuthorization decodes a stored authorization. The token of the
+// authorization is only set for authorizations stored before tokens were
+// hashed, which are migrated by the first migration of the service.
+func decodeAuthorization(b []byte, r *authorizationRecord) error {
+	if err := json.Unmarshal(b, r); err != nil {
 		return err
 	}
-	if a.Status == "" {
-		a.Status = influxdb.Active
+	if r.Status == "" {
+		r.Status = influxdb.Active
 	}
 	return nil
 }
 
 // forEachAuthorization will iterate through all authorizations while fn returns true.
 func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*influxdb.Authorization) bool) error {
+	return s.forEachAuthorizationRecord(ctx, tx, func(r *authorizationRecord) bool {
+		r.Token = ""
+		return fn(&r.Authorization)
+	})
+}
+
+func (s *Service) forEachAuthorizationRecord(ctx context.Context, tx Tx, fn func(*authorizationRecord) bool) error {
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
 		return err
@@ -377,12 +471,12 @@ func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*infl
 	}
 
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
-		a := &influxdb.Authorization{}
+		r := &authorizationRecord{}
 
-		if err := decodeAuthorization(v, a); err != nil {
+		if err := decodeAuthorization(v, r); err != nil {
 			return err
 		}
-		if !fn(a) {
+		if !fn(r) {
 			break
 		}
 	}
@@ -398,17 +492,12 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error
 }
 
 func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
-	if err != nil {
-		return err
-	}
-
-	idx, err := authIndexBucket(tx)
+	r, err := s.findAuthorizationRecordByID(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
+	if err := authTokenIndex.Delete(tx, authIndexKey(r.TokenHash)); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -445,43 +534,74 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *
 }
 
 func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	r, err := s.findAuthorizationRecordByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if upd.Status != nil {
-		a.Status = *upd.Status
+		r.Status = *upd.Status
 	}
 	if upd.Description != nil {
-		a.Description = *upd.Description
+		r.Description = *upd.Description
+	}
+
+	if err := s.replaceAuthorizationRecord(ctx, tx, r); err != nil {
+		return nil, err
+	}
+	return &r.Authorization, nil
+}
+
+// RecordAuthorizationUsage sets the time and the client address of the last use
+// of an authorization.
+func (s *Service) RecordAuthorizationUsage(ctx context.Context, id influxdb.ID, at time.Time, from string) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findAuthorizationRecordByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		r.LastUsedAt = &at
+		r.LastUsedFrom = from
+		return s.replaceAuthorizationRecord(ctx, tx, r)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRecordAuthorizationUsage,
+			Err: err,
+		}
 	}
+	return nil
+}
 
-	v, err := encodeAuthorization(a)
+// replaceAuthorizationRecord replaces the stored authorization of r, whose
+// token is unchanged.
+func (s *Service) replaceAuthorizationRecord(ctx context.Context, tx Tx, r *authorizationRecord) error {
+	v, err := encodeAuthorization(r)
 	if err != nil {
-		return nil, &influxdb.Error{
+		return &influxdb.Error{
 			Err: err,
 		}
 	}
 
-	encodedID, err := id.Encode()
+	encodedID, err := r.ID.Encode()
 	if err != nil {
-		return nil, &influxdb.Error{
+		return &influxdb.Error{
 			Err: err,
 		}
 	}
 
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if err = b.Put(encodedID, v); err != nil {
-		return nil, &influxdb.Error{
+		return &influxdb.Error{
 			Err: err,
 		}
 	}
-	return a, nil
+	return nil
 }
 
 func authIndexBucket(tx Tx) (Bucket, error) {
@@ -503,7 +623,7 @@ func UnexpectedAuthIndexError(err error) *influxdb.Error {
 }
 
 func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	err := s.unique(ctx, tx, authIndex, authIndexKey(a.Token))
+	err := s.unique(ctx, tx, authIndex, authIndexKey(influxdb.HashToken(a.Token)))
 	if err == NotUniqueError {
 		// by returning a generic error we are trying to hide when
 		// a token is non-unique.
@@ -2,7 +2,9 @@ package kv_test
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
@@ -91,3 +93,98 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+func TestService_AuthorizationTokens(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+
+	// An authorization stored before tokens were hashed.
+	id := influxdbtesting.MustIDBase16("020f755c3c082000")
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		if err := b.Put(encodedID, []byte(`{"id":"020f755c3c082000","token":"legacy","status":"active","orgID":"020f755c3c083000","userID":"020f755c3c084000"}`)); err != nil {
+			return err
+		}
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		return idx.Put([]byte("legacy"), encodedID)
+	})
+	if err != nil {
+		t.Fatalf("failed to store legacy authorization: %v", err)
+	}
+
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error migrating authorizations: %v", err)
+	}
+
+	a, err := svc.FindAuthorizationByToken(ctx, "legacy")
+	if err != nil {
+		t.Fatalf("failed to find migrated authorization: %v", err)
+	}
+	if a.ID != id || a.Token != "legacy" {
+		t.Fatalf("unexpected authorization %v", a)
+	}
+
+	err = s.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		v, err := b.Get(encodedID)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(v), "legacy") {
+			t.Errorf("stored authorization contains its token: %s", v)
+		}
+
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		if _, err := idx.Get([]byte("legacy")); !kv.IsNotFound(err) {
+			t.Errorf("token index contains the token")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	if err := svc.RecordAuthorizationUsage(ctx, id, now, "10.0.0.1"); err != nil {
+		t.Fatalf("failed to record authorization usage: %v", err)
+	}
+	a, err = svc.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.LastUsedAt == nil || !a.LastUsedAt.Equal(now) || a.LastUsedFrom != "10.0.0.1" {
+		t.Fatalf("unexpected usage of authorization: %v from %q", a.LastUsedAt, a.LastUsedFrom)
+	}
+	if a.Token != "" {
+		t.Fatalf("unexpected token %q", a.Token)
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, "legacy"); err != nil {
+		t.Fatalf("failed to find authorization after recording usage: %v", err)
+	}
+}
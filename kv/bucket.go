@@ -17,6 +17,15 @@ var (
 	bucketIndex  = []byte("bucketindexv1")
 )
 
+// bucketNameIndex indexes the buckets by organization and name.
+var bucketNameIndex = NewIndex("buckets by name", bucketBucket, bucketIndex, func(v []byte) ([]byte, error) {
+	var b influxdb.Bucket
+	if err := json.Unmarshal(v, &b); err != nil {
+		return nil, err
+	}
+	return bucketIndexKey(&b)
+})
+
 var _ influxdb.BucketService = (*Service)(nil)
 var _ influxdb.BucketOperationLogService = (*Service)(nil)
 
@@ -159,6 +168,22 @@ func (s *Service) findSystemBucket(n string) (*influxdb.Bucket, error) {
 			RetentionPeriod: time.Hour * 24 * 7,
 			Description:     "System bucket for monitoring logs",
 		}, nil
+	case "_audit":
+		return &influxdb.Bucket{
+			ID:              influxdb.AuditSystemBucketID,
+			Type:            influxdb.BucketTypeSystem,
+			Name:            "_audit",
+			RetentionPeriod: time.Hour * 24 * 30,
+			Description:     "System bucket for audit logs",
+		}, nil
+	case "_queries":
+		return &influxdb.Bucket{
+			ID:              influxdb.QueriesSystemBucketID,
+			Type:            influxdb.BucketTypeSystem,
+			Name:            "_queries",
+			RetentionPeriod: time.Hour * 24 * 7,
+			Description:     "System bucket for query logs",
+		}, nil
 	default:
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -183,12 +208,7 @@ func (s *Service) findBucketByName(ctx context.Context, tx Tx, orgID influxdb.ID
 		}
 	}
 
-	idx, err := s.bucketsIndexBucket(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	buf, err := idx.Get(key)
+	buf, err := bucketNameIndex.Lookup(tx, key)
 	if IsNotFound(err) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -344,7 +364,16 @@ func (s *Service) FindBuckets(ctx context.Context, filter influxdb.BucketFilter,
 	if error != nil {
 		return bs, 0, error
 	}
-	bs = append(bs, tasks, monitoring)
+
+	audit, error := s.findSystemBucket("_audit")
+	if error != nil {
+		return bs, 0, error
+	}
+	queries, error := s.findSystemBucket("_queries")
+	if error != nil {
+		return bs, 0, error
+	}
+	bs = append(bs, tasks, monitoring, audit, queries)
 
 	return bs, len(bs), nil
 }
@@ -424,10 +453,18 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 		return err
 	}
 
+	if err := b.SchemaType.Valid(); err != nil {
+		return err
+	}
+
 	if b.ID, err = s.generateBucketID(ctx, tx); err != nil {
 		return err
 	}
 
+	if err := s.validDownsampleTiers(ctx, tx, b); err != nil {
+		return err
+	}
+
 	b.CreatedAt = s.Now()
 	b.UpdatedAt = s.Now()
 
@@ -437,6 +474,10 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 		}
 	}
 
+	if err := s.putDownsampleTasks(ctx, tx, b, nil); err != nil {
+		return err
+	}
+
 	if err := s.putBucket(ctx, tx, b); err != nil {
 		return err
 	}
@@ -518,12 +559,7 @@ func (s *Service) putBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) erro
 		return pe
 	}
 
-	idx, err := s.bucketsIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Put(key, encodedID); err != nil {
+	if err := bucketNameIndex.Insert(tx, key, encodedID); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -653,6 +689,13 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.Description = *upd.Description
 	}
 
+	if upd.SchemaType != nil {
+		if err := upd.SchemaType.Valid(); err != nil {
+			return nil, err
+		}
+		b.SchemaType = *upd.SchemaType
+	}
+
 	if upd.Name != nil {
 		b0, err := s.findBucketByName(ctx, tx, b.OrgID, *upd.Name)
 		if err == nil && b0.ID != id {
@@ -665,17 +708,24 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		if err != nil {
 			return nil, err
 		}
-		idx, err := s.bucketsIndexBucket(tx)
-		if err != nil {
-			return nil, err
-		}
 		// Buckets are indexed by name and so the bucket index must be pruned when name is modified.
-		if err := idx.Delete(key); err != nil {
+		if err := bucketNameIndex.Delete(tx, key); err != nil {
 			return nil, err
 		}
 		b.Name = *upd.Name
 	}
 
+	previous := b.DownsampleTiers
+	if upd.DownsampleTiers != nil {
+		b.DownsampleTiers = *upd.DownsampleTiers
+	}
+	if err := s.validDownsampleTiers(ctx, tx, b); err != nil {
+		return nil, err
+	}
+	if err := s.putDownsampleTasks(ctx, tx, b, previous); err != nil {
+		return nil, err
+	}
+
 	b.UpdatedAt = s.Now()
 
 	if err := s.appendBucketEventToLog(ctx, tx, b.ID, bucketUpdatedEvent); err != nil {
@@ -706,17 +756,16 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return pe
 	}
 
+	if err := s.validDownsampleTargetDelete(ctx, tx, id); err != nil {
+		return err
+	}
+
 	key, pe := bucketIndexKey(b)
 	if pe != nil {
 		return pe
 	}
 
-	idx, err := s.bucketsIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Delete(key); err != nil {
+	if err := bucketNameIndex.Delete(tx, key); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -748,6 +797,14 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.deleteBucketMeasurementSchemas(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteDownsampleTasks(ctx, tx, b); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,350 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/notification/flux"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// downsampleFieldTypes is the order in which the fields of each type are
+// downsampled by a tier task.
+var downsampleFieldTypes = []influxdb.SchemaFieldType{
+	influxdb.SchemaFieldTypeFloat,
+	influxdb.SchemaFieldTypeInteger,
+	influxdb.SchemaFieldTypeUnsigned,
+	influxdb.SchemaFieldTypeString,
+	influxdb.SchemaFieldTypeBoolean,
+}
+
+// validDownsampleTiers returns an error if the tiers of b are invalid. Since
+// the aggregate functions depend on the type of each field, a bucket can only
+// be downsampled if it has an explicit schema. The target buckets must belong
+// to the organization of b.
+func (s *Service) validDownsampleTiers(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
+	if len(b.DownsampleTiers) == 0 {
+		return nil
+	}
+
+	if b.SchemaType != influxdb.SchemaTypeExplicit {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "downsample tiers require a bucket with an explicit schema",
+		}
+	}
+
+	if err := influxdb.ValidDownsampleTiers(b.ID, b.DownsampleTiers); err != nil {
+		return err
+	}
+
+	for _, t := range b.DownsampleTiers {
+		target, err := s.findBucketByID(ctx, tx, t.TargetBucketID)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("downsample target bucket %s not found", t.TargetBucketID),
+				Err:  err,
+			}
+		}
+		if target.OrgID != b.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("downsample target bucket %s must belong to the organization of the bucket", t.TargetBucketID),
+			}
+		}
+	}
+	return nil
+}
+
+// putDownsampleTasks creates or updates the task of every tier of b and sets
+// the task IDs of the tiers. The task of a tier that targets the same bucket as
+// one of the previous tiers is updated so that it keeps its progress, unless
+// it has been deleted. The tasks of the previous tiers that are no longer
+// declared are deleted.
+func (s *Service) putDownsampleTasks(ctx context.Context, tx Tx, b *influxdb.Bucket, previous []influxdb.DownsampleTier) error {
+	tasks := make(map[influxdb.ID]influxdb.ID, len(previous))
+	for _, t := range previous {
+		tasks[t.TargetBucketID] = t.TaskID
+	}
+
+	for i := range b.DownsampleTiers {
+		t := &b.DownsampleTiers[i]
+		script, err := s.downsampleFlux(ctx, tx, b, t)
+		if err != nil {
+			return err
+		}
+
+		if id, ok := tasks[t.TargetBucketID]; ok {
+			delete(tasks, t.TargetBucketID)
+			_, err := s.updateTask(ctx, tx, id, influxdb.TaskUpdate{Flux: &script})
+			if err == nil {
+				t.TaskID = id
+				continue
+			}
+			if err != influxdb.ErrTaskNotFound {
+				return err
+			}
+		}
+
+		ownerID, err := s.downsampleTaskOwner(ctx, tx, b.OrgID)
+		if err != nil {
+			return err
+		}
+		task, err := s.createTask(ctx, tx, influxdb.TaskCreate{
+			Type:           influxdb.TaskTypeDownsample,
+			Flux:           script,
+			OwnerID:        ownerID,
+			OrganizationID: b.OrgID,
+			Status:         string(backend.TaskActive),
+		})
+		if err != nil {
+			return err
+		}
+		t.TaskID = task.ID
+	}
+
+	for _, id := range tasks {
+		if err := s.deleteTask(ctx, tx, id); err != nil && err != influxdb.ErrTaskNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// downsampleTaskOwner returns the user that declares the tiers of the bucket
+// or, without one, an owner of its organization.
+func (s *Service) downsampleTaskOwner(ctx context.Context, tx Tx, orgID influxdb.ID) (influxdb.ID, error) {
+	if a, err := icontext.GetAuthorizer(ctx); err == nil && a.GetUserID().Valid() {
+		return a.GetUserID(), nil
+	}
+
+	owners, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   orgID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserType:     influxdb.Owner,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(owners) == 0 {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "downsample tiers require a user to own their tasks",
+		}
+	}
+	return owners[0].UserID, nil
+}
+
+// updateDownsampleTasksFlux regenerates the scripts of the tier tasks of a
+// bucket after its measurement schemas have changed.
+func (s *Service) updateDownsampleTasksFlux(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
+	if len(b.DownsampleTiers) == 0 {
+		return nil
+	}
+	previous := make([]influxdb.DownsampleTier, len(b.DownsampleTiers))
+	copy(previous, b.DownsampleTiers)
+	if err := s.putDownsampleTasks(ctx, tx, b, previous); err != nil {
+		return err
+	}
+	return s.putBucket(ctx, tx, b)
+}
+
+// deleteDownsampleTasks deletes the tier tasks of b.
+func (s *Service) deleteDownsampleTasks(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
+	for _, t := range b.DownsampleTiers {
+		if err := s.deleteTask(ctx, tx, t.TaskID); err != nil && err != influxdb.ErrTaskNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// validTaskNotDownsample returns an error if the task id is the task of a
+// downsample tier. Their scripts are generated from the tiers of their bucket
+// and they are deleted with the tiers, as retention waits for their runs.
+func (s *Service) validTaskNotDownsample(ctx context.Context, tx Tx, id influxdb.ID) error {
+	t, err := s.findTaskByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if t.Type == influxdb.TaskTypeDownsample {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  "task is the task of a downsample tier, update the downsample tiers of its bucket instead",
+		}
+	}
+	return nil
+}
+
+// validDownsampleTargetDelete returns an error if the bucket id is the target
+// of a downsample tier of another bucket.
+func (s *Service) validDownsampleTargetDelete(ctx context.Context, tx Tx, id influxdb.ID) error {
+	var source *influxdb.Bucket
+	err := s.forEachBucket(ctx, tx, false, func(b *influxdb.Bucket) bool {
+		for _, t := range b.DownsampleTiers {
+			if t.TargetBucketID == id {
+				source = b
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if source != nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("bucket is the downsample target of bucket %q", source.Name),
+		}
+	}
+	return nil
+}
+
+// downsampleFlux returns the script of the task of tier t of bucket b.
+func (s *Service) downsampleFlux(ctx context.Context, tx Tx, b *influxdb.Bucket, t *influxdb.DownsampleTier) (string, error) {
+	target, err := s.findBucketByID(ctx, tx, t.TargetBucketID)
+	if err != nil {
+		return "", err
+	}
+
+	schemas, err := s.findMeasurementSchemas(ctx, tx, influxdb.MeasurementSchemaFilter{BucketID: b.ID})
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("Downsample %s into %s", b.Name, target.Name)
+	return ast.Format(generateDownsampleFluxAST(name, b, t, schemas)), nil
+}
+
+// generateDownsampleFluxAST returns the AST of a task that downsamples the
+// fields of each type declared by schemas. Every run aggregates the window
+// that has just become older than the age threshold of the tier, so once the
+// run scheduled at T has completed all data before T-t.After is downsampled.
+func generateDownsampleFluxAST(name string, b *influxdb.Bucket, t *influxdb.DownsampleTier, schemas []*influxdb.MeasurementSchema) *ast.Package {
+	var body []ast.Statement
+	body = append(body, flux.DefineTaskOption(flux.Object(
+		flux.Property("name", flux.String(name)),
+		flux.Property("every", durationLiteral(t.Every)),
+	)))
+
+	rangeProps := []*ast.Property{flux.Property("start", flux.Negative(durationLiteral(t.Every+t.After)))}
+	if t.After > 0 {
+		rangeProps = append(rangeProps, flux.Property("stop", flux.Negative(durationLiteral(t.After))))
+	}
+	body = append(body, flux.DefineVariable("data", flux.Pipe(
+		flux.Call(flux.Identifier("from"), flux.Object(flux.Property("bucketID", flux.String(b.ID.String())))),
+		flux.Call(flux.Identifier("range"), flux.Object(rangeProps...)),
+	)))
+
+	for _, typ := range downsampleFieldTypes {
+		fns := t.Aggregates[typ]
+		if len(fns) == 0 {
+			continue
+		}
+
+		data := string(typ) + "_data"
+		fn := flux.Function(flux.FunctionParams("r"), downsampleFieldPredicate(typ, schemas))
+		body = append(body, flux.DefineVariable(data, flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", fn))),
+		)))
+
+		for _, agg := range fns {
+			rename := flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r",
+				flux.Property("_field", flux.Add(flux.Member("r", "_field"), flux.String("_"+agg))),
+			))
+			body = append(body, flux.ExpressionStatement(flux.Pipe(
+				flux.Identifier(data),
+				flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+					flux.Property("every", durationLiteral(t.Every)),
+					flux.Property("fn", flux.Identifier(agg)),
+					flux.Property("createEmpty", flux.Bool(false)),
+				)),
+				flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", rename))),
+				flux.Call(flux.Identifier("to"), flux.Object(
+					flux.Property("bucketID", flux.String(t.TargetBucketID.String())),
+					flux.Property("orgID", flux.String(b.OrgID.String())),
+				)),
+				flux.Call(flux.Identifier("yield"), flux.Object(flux.Property("name", flux.String(string(typ)+"_"+agg)))),
+			)))
+		}
+	}
+
+	return &ast.Package{
+		Package: "main",
+		Files:   []*ast.File{flux.File("", nil, body)},
+	}
+}
+
+// downsampleFieldPredicate returns an expression that matches the fields of
+// type typ in schemas. It is false if there are no such fields.
+func downsampleFieldPredicate(typ influxdb.SchemaFieldType, schemas []*influxdb.MeasurementSchema) ast.Expression {
+	ss := make([]*influxdb.MeasurementSchema, len(schemas))
+	copy(ss, schemas)
+	sort.Slice(ss, func(i, j int) bool { return ss[i].Name < ss[j].Name })
+
+	var measurements ast.Expression
+	for _, ms := range ss {
+		var fields ast.Expression
+		for _, f := range ms.Fields {
+			if f.Type != typ {
+				continue
+			}
+			e := flux.Equal(flux.Member("r", "_field"), flux.String(f.Name))
+			if fields == nil {
+				fields = e
+			} else {
+				fields = flux.Or(fields, e)
+			}
+		}
+		if fields == nil {
+			continue
+		}
+
+		e := flux.And(flux.Equal(flux.Member("r", "_measurement"), flux.String(ms.Name)), fields)
+		if measurements == nil {
+			measurements = e
+		} else {
+			measurements = flux.Or(measurements, e)
+		}
+	}
+
+	if measurements == nil {
+		return flux.Bool(false)
+	}
+	return measurements
+}
+
+// durationLiteral returns d as a duration literal, e.g. 1h30m.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	units := []struct {
+		unit string
+		d    time.Duration
+	}{
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+		{"ms", time.Millisecond},
+		{"us", time.Microsecond},
+		{"ns", time.Nanosecond},
+	}
+
+	lit := &ast.DurationLiteral{}
+	for _, u := range units {
+		if m := d / u.d; m > 0 {
+			lit.Values = append(lit.Values, ast.Duration{Magnitude: int64(m), Unit: u.unit})
+			d -= m * u.d
+		}
+	}
+	if len(lit.Values) == 0 {
+		lit.Values = []ast.Duration{{Magnitude: 0, Unit: "s"}}
+	}
+	return lit
+}
@@ -0,0 +1,190 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestInmemBucketDownsampleTiers(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	testBucketDownsampleTiers(s, t)
+}
+
+func testBucketDownsampleTiers(s kv.Store, t *testing.T) {
+	ctx := context.Background()
+
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	ctx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: user.ID})
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	target := &influxdb.Bucket{OrgID: org.ID, Name: "5m"}
+	if err := svc.CreateBucket(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+
+	tier := influxdb.DownsampleTier{
+		TargetBucketID: target.ID,
+		Every:          5 * time.Minute,
+		After:          time.Hour,
+		Aggregates: map[influxdb.SchemaFieldType][]string{
+			influxdb.SchemaFieldTypeFloat:  {"mean", "max"},
+			influxdb.SchemaFieldTypeString: {"last"},
+		},
+	}
+
+	t.Run("implicit schema", func(t *testing.T) {
+		err := svc.CreateBucket(ctx, &influxdb.Bucket{
+			OrgID:           org.ID,
+			Name:            "implicit",
+			DownsampleTiers: []influxdb.DownsampleTier{tier},
+		})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	t.Run("invalid aggregate", func(t *testing.T) {
+		invalid := tier
+		invalid.Aggregates = map[influxdb.SchemaFieldType][]string{
+			influxdb.SchemaFieldTypeString: {"mean"},
+		}
+		err := svc.CreateBucket(ctx, &influxdb.Bucket{
+			OrgID:           org.ID,
+			Name:            "invalid",
+			SchemaType:      influxdb.SchemaTypeExplicit,
+			DownsampleTiers: []influxdb.DownsampleTier{invalid},
+		})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	raw := &influxdb.Bucket{
+		OrgID:           org.ID,
+		Name:            "raw",
+		SchemaType:      influxdb.SchemaTypeExplicit,
+		DownsampleTiers: []influxdb.DownsampleTier{tier},
+	}
+	if err := svc.CreateBucket(ctx, raw); err != nil {
+		t.Fatalf("CreateBucket() unexpected error: %v", err)
+	}
+	taskID := raw.DownsampleTiers[0].TaskID
+	if !taskID.Valid() {
+		t.Fatalf("CreateBucket() did not create the tier task")
+	}
+
+	if err := svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+		BucketID: raw.ID,
+		Name:     "cpu",
+		Tags:     []string{"host"},
+		Fields: []influxdb.MeasurementSchemaField{
+			{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+			{Name: "state", Type: influxdb.SchemaFieldTypeString},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := svc.FindTaskByID(ctx, taskID)
+	if err != nil {
+		t.Fatalf("unable to find tier task: %v", err)
+	}
+	if task.Type != influxdb.TaskTypeDownsample || task.OwnerID != user.ID || task.Every != "5m" {
+		t.Errorf("unexpected tier task: %+v", task)
+	}
+	if errs := ast.GetErrors(parser.ParseSource(task.Flux)); len(errs) > 0 {
+		t.Fatalf("tier task flux has errors: %v\n%s", errs, task.Flux)
+	}
+	for _, want := range []string{
+		`from(bucketID: "` + raw.ID.String() + `")`,
+		`range(start: -1h5m, stop: -1h)`,
+		`r._measurement == "cpu" and r._field == "usage"`,
+		`aggregateWindow(every: 5m, fn: mean, createEmpty: false)`,
+		`_field: r._field + "_max"`,
+		`aggregateWindow(every: 5m, fn: last, createEmpty: false)`,
+		`to(bucketID: "` + target.ID.String() + `", orgID: "` + org.ID.String() + `")`,
+	} {
+		if !strings.Contains(task.Flux, want) {
+			t.Errorf("tier task flux does not contain %s:\n%s", want, task.Flux)
+		}
+	}
+
+	t.Run("delete target", func(t *testing.T) {
+		if err := svc.DeleteBucket(ctx, target.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Errorf("expected conflict deleting a downsample target, got %v", err)
+		}
+	})
+
+	t.Run("manage tier task", func(t *testing.T) {
+		if err := svc.DeleteTask(ctx, taskID); influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Errorf("expected conflict deleting a tier task, got %v", err)
+		}
+		flux := task.Flux + "\n"
+		if _, err := svc.UpdateTask(ctx, taskID, influxdb.TaskUpdate{Flux: &flux}); influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Errorf("expected conflict editing the flux of a tier task, got %v", err)
+		}
+		inactive := string(influxdb.TaskStatusInactive)
+		if _, err := svc.UpdateTask(ctx, taskID, influxdb.TaskUpdate{Status: &inactive}); err != nil {
+			t.Errorf("UpdateTask() unexpected error updating the status of a tier task: %v", err)
+		}
+	})
+
+	t.Run("update tier", func(t *testing.T) {
+		upd := tier
+		upd.Every = time.Hour
+		b, err := svc.UpdateBucket(ctx, raw.ID, influxdb.BucketUpdate{
+			DownsampleTiers: &[]influxdb.DownsampleTier{upd},
+		})
+		if err != nil {
+			t.Fatalf("UpdateBucket() unexpected error: %v", err)
+		}
+		if b.DownsampleTiers[0].TaskID != taskID {
+			t.Errorf("expected the tier to keep task %s, got %s", taskID, b.DownsampleTiers[0].TaskID)
+		}
+		task, err := svc.FindTaskByID(ctx, taskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Every != "1h" {
+			t.Errorf("expected the tier task to run every hour, got %s", task.Every)
+		}
+	})
+
+	t.Run("remove tiers", func(t *testing.T) {
+		if _, err := svc.UpdateBucket(ctx, raw.ID, influxdb.BucketUpdate{
+			DownsampleTiers: &[]influxdb.DownsampleTier{},
+		}); err != nil {
+			t.Fatalf("UpdateBucket() unexpected error: %v", err)
+		}
+		if _, err := svc.FindTaskByID(ctx, taskID); err != influxdb.ErrTaskNotFound {
+			t.Errorf("expected the tier task to be deleted, got %v", err)
+		}
+		if err := svc.DeleteBucket(ctx, target.ID); err != nil {
+			t.Errorf("DeleteBucket() unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,373 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var (
+	measurementSchemaBucket = []byte("measurementschemasv1")
+	measurementSchemaIndex  = []byte("measurementschemaindexv1")
+)
+
+var _ influxdb.BucketSchemaService = (*Service)(nil)
+
+var errMeasurementSchemaNotFound = &influxdb.Error{
+	Code: influxdb.ENotFound,
+	Msg:  "measurement schema not found",
+}
+
+func (s *Service) initializeMeasurementSchemas(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(measurementSchemaBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(measurementSchemaIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// measurementSchemaIndexKey is the key of a schema in the index, the encoded
+// bucket ID followed by the name of the measurement.
+func measurementSchemaIndexKey(bucketID influxdb.ID, name string) ([]byte, error) {
+	prefix, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, name...), nil
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *Service) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ms *influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		m, err := s.findBucketMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+		ms = m
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchemaByID,
+			Err: err,
+		}
+	}
+	return ms, nil
+}
+
+// findBucketMeasurementSchemaByID returns the schema with the id, unless it
+// belongs to another bucket.
+func (s *Service) findBucketMeasurementSchemaByID(ctx context.Context, tx Tx, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	ms, err := s.findMeasurementSchemaByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ms.BucketID != bucketID {
+		return nil, errMeasurementSchemaNotFound
+	}
+	return ms, nil
+}
+
+func (s *Service) findMeasurementSchemaByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, errMeasurementSchemaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ms influxdb.MeasurementSchema
+	if err := json.Unmarshal(v, &ms); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return &ms, nil
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match filter.
+func (s *Service) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var schemas []*influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		ms, err := s.findMeasurementSchemas(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		schemas = ms
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchemas,
+			Err: err,
+		}
+	}
+	return schemas, nil
+}
+
+func (s *Service) findMeasurementSchemas(ctx context.Context, tx Tx, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	if filter.Name != nil {
+		id, err := s.findMeasurementSchemaIDByName(ctx, tx, filter.BucketID, *filter.Name)
+		if err == errMeasurementSchemaNotFound {
+			return []*influxdb.MeasurementSchema{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ms, err := s.findMeasurementSchemaByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []*influxdb.MeasurementSchema{ms}, nil
+	}
+
+	prefix, err := measurementSchemaIndexKey(filter.BucketID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := tx.Bucket(measurementSchemaIndex)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := []*influxdb.MeasurementSchema{}
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		var id influxdb.ID
+		if err := id.Decode(v); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		ms, err := s.findMeasurementSchemaByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, ms)
+	}
+	return schemas, nil
+}
+
+func (s *Service) findMeasurementSchemaIDByName(ctx context.Context, tx Tx, bucketID influxdb.ID, name string) (influxdb.ID, error) {
+	key, err := measurementSchemaIndexKey(bucketID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	idx, err := tx.Bucket(measurementSchemaIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := idx.Get(key)
+	if IsNotFound(err) {
+		return 0, errMeasurementSchemaNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(v); err != nil {
+		return 0, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return id, nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and sets ms.ID with the new identifier.
+func (s *Service) CreateMeasurementSchema(ctx context.Context, ms *influxdb.MeasurementSchema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.createMeasurementSchema(ctx, tx, ms)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateMeasurementSchema,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) createMeasurementSchema(ctx context.Context, tx Tx, ms *influxdb.MeasurementSchema) error {
+	if err := ms.Valid(); err != nil {
+		return err
+	}
+
+	b, err := s.findBucketByID(ctx, tx, ms.BucketID)
+	if err != nil {
+		return err
+	}
+	ms.OrgID = b.OrgID
+
+	if _, err := s.findMeasurementSchemaIDByName(ctx, tx, ms.BucketID, ms.Name); err == nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("measurement schema %q already exists", ms.Name),
+		}
+	} else if err != errMeasurementSchemaNotFound {
+		return err
+	}
+
+	if ms.ID, err = s.generateSafeID(ctx, tx, measurementSchemaBucket); err != nil {
+		return err
+	}
+	ms.CreatedAt = s.Now()
+	ms.UpdatedAt = s.Now()
+
+	if err := s.putMeasurementSchema(ctx, tx, ms); err != nil {
+		return err
+	}
+
+	key, err := measurementSchemaIndexKey(ms.BucketID, ms.Name)
+	if err != nil {
+		return err
+	}
+	encodedID, err := ms.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	idx, err := tx.Bucket(measurementSchemaIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put(key, encodedID); err != nil {
+		return err
+	}
+	return s.updateDownsampleTasksFlux(ctx, tx, b)
+}
+
+func (s *Service) putMeasurementSchema(ctx context.Context, tx Tx, ms *influxdb.MeasurementSchema) error {
+	v, err := json.Marshal(ms)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	encodedID, err := ms.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// UpdateMeasurementSchema adds tags and fields to a measurement schema of a bucket.
+func (s *Service) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ms *influxdb.MeasurementSchema
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findBucketMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+		if err := upd.Apply(m); err != nil {
+			return err
+		}
+		m.UpdatedAt = s.Now()
+		if err := s.putMeasurementSchema(ctx, tx, m); err != nil {
+			return err
+		}
+		b, err := s.findBucketByID(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		if err := s.updateDownsampleTasksFlux(ctx, tx, b); err != nil {
+			return err
+		}
+		ms = m
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateMeasurementSchema,
+			Err: err,
+		}
+	}
+	return ms, nil
+}
+
+// deleteBucketMeasurementSchemas removes all measurement schemas of a bucket.
+func (s *Service) deleteBucketMeasurementSchemas(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	schemas, err := s.findMeasurementSchemas(ctx, tx, influxdb.MeasurementSchemaFilter{BucketID: bucketID})
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return err
+	}
+	idx, err := tx.Bucket(measurementSchemaIndex)
+	if err != nil {
+		return err
+	}
+
+	for _, ms := range schemas {
+		key, err := measurementSchemaIndexKey(ms.BucketID, ms.Name)
+		if err != nil {
+			return err
+		}
+		if err := idx.Delete(key); err != nil {
+			return err
+		}
+		encodedID, err := ms.ID.Encode()
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(encodedID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
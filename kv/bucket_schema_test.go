@@ -0,0 +1,186 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBoltBucketSchemaService(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testBucketSchemaService(s, t)
+}
+
+func TestInmemBucketSchemaService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	testBucketSchemaService(s, t)
+}
+
+func testBucketSchemaService(s kv.Store, t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := kv.NewService(s)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket", SchemaType: influxdb.SchemaTypeExplicit}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := &influxdb.MeasurementSchema{
+		BucketID: bucket.ID,
+		Name:     "cpu",
+		Tags:     []string{"host"},
+		Fields: []influxdb.MeasurementSchemaField{
+			{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+		},
+	}
+	if err := svc.CreateMeasurementSchema(ctx, cpu); err != nil {
+		t.Fatalf("CreateMeasurementSchema() unexpected error: %v", err)
+	}
+	if !cpu.ID.Valid() || cpu.OrgID != org.ID || !cpu.CreatedAt.Equal(now) {
+		t.Fatalf("CreateMeasurementSchema() did not set id, org and timestamps: %+v", cpu)
+	}
+
+	mem := &influxdb.MeasurementSchema{
+		BucketID: bucket.ID,
+		Name:     "mem",
+		Fields: []influxdb.MeasurementSchemaField{
+			{Name: "free", Type: influxdb.SchemaFieldTypeUnsigned},
+		},
+	}
+	if err := svc.CreateMeasurementSchema(ctx, mem); err != nil {
+		t.Fatalf("CreateMeasurementSchema() unexpected error: %v", err)
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+			BucketID: bucket.ID,
+			Name:     "cpu",
+			Fields:   []influxdb.MeasurementSchemaField{{Name: "f", Type: influxdb.SchemaFieldTypeFloat}},
+		})
+		if influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Errorf("CreateMeasurementSchema() error = %v, want conflict", err)
+		}
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		err := svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+			BucketID: bucket.ID,
+			Name:     "disk",
+			Tags:     []string{"path"},
+			Fields:   []influxdb.MeasurementSchemaField{{Name: "path", Type: influxdb.SchemaFieldTypeFloat}},
+		})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("CreateMeasurementSchema() error = %v, want invalid", err)
+		}
+	})
+
+	t.Run("missing bucket", func(t *testing.T) {
+		err := svc.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+			BucketID: influxdb.ID(1),
+			Name:     "disk",
+			Fields:   []influxdb.MeasurementSchemaField{{Name: "f", Type: influxdb.SchemaFieldTypeFloat}},
+		})
+		if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("CreateMeasurementSchema() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("find", func(t *testing.T) {
+		got, err := svc.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []*influxdb.MeasurementSchema{cpu, mem}; !cmp.Equal(got, want) {
+			t.Errorf("FindMeasurementSchemas() -got/+want\n%s", cmp.Diff(got, want))
+		}
+
+		name := "mem"
+		got, err = svc.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID, Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []*influxdb.MeasurementSchema{mem}; !cmp.Equal(got, want) {
+			t.Errorf("FindMeasurementSchemas() -got/+want\n%s", cmp.Diff(got, want))
+		}
+
+		name = "disk"
+		got, err = svc.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID, Name: &name})
+		if err != nil || len(got) != 0 {
+			t.Errorf("FindMeasurementSchemas() = %v, %v, want no schemas", got, err)
+		}
+
+		if _, err := svc.FindMeasurementSchemaByID(ctx, bucket.ID, influxdb.ID(1)); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("FindMeasurementSchemaByID() error = %v, want not found", err)
+		}
+		if _, err := svc.FindMeasurementSchemaByID(ctx, influxdb.ID(1), cpu.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("FindMeasurementSchemaByID() of another bucket error = %v, want not found", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		got, err := svc.UpdateMeasurementSchema(ctx, bucket.ID, cpu.ID, influxdb.MeasurementSchemaUpdate{
+			Tags: []string{"host", "region"},
+			Fields: []influxdb.MeasurementSchemaField{
+				{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+				{Name: "cores", Type: influxdb.SchemaFieldTypeInteger},
+			},
+		})
+		if err != nil {
+			t.Fatalf("UpdateMeasurementSchema() unexpected error: %v", err)
+		}
+		want := *cpu
+		want.Tags = []string{"host", "region"}
+		want.Fields = []influxdb.MeasurementSchemaField{
+			{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+			{Name: "cores", Type: influxdb.SchemaFieldTypeInteger},
+		}
+		if !cmp.Equal(got, &want) {
+			t.Errorf("UpdateMeasurementSchema() -got/+want\n%s", cmp.Diff(got, &want))
+		}
+
+		_, err = svc.UpdateMeasurementSchema(ctx, bucket.ID, cpu.ID, influxdb.MeasurementSchemaUpdate{
+			Fields: []influxdb.MeasurementSchemaField{{Name: "usage", Type: influxdb.SchemaFieldTypeString}},
+		})
+		if influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Errorf("UpdateMeasurementSchema() error = %v, want conflict", err)
+		}
+	})
+
+	t.Run("delete bucket", func(t *testing.T) {
+		if err := svc.DeleteBucket(ctx, bucket.ID); err != nil {
+			t.Fatal(err)
+		}
+		got, err := svc.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: bucket.ID})
+		if err != nil || len(got) != 0 {
+			t.Errorf("FindMeasurementSchemas() = %v, %v, want no schemas", got, err)
+		}
+		if _, err := svc.FindMeasurementSchemaByID(ctx, bucket.ID, cpu.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("FindMeasurementSchemaByID() error = %v, want not found", err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltDBRPMappingService_CreateDBRPMapping(t *testing.T) {
+	influxdbtesting.CreateDBRPMapping(initBoltDBRPMappingService, t)
+}
+
+func TestBoltDBRPMappingService_FindDBRPMappingByKey(t *testing.T) {
+	influxdbtesting.FindDBRPMappingByKey(initBoltDBRPMappingService, t)
+}
+
+func TestBoltDBRPMappingService_FindDBRPMappings(t *testing.T) {
+	influxdbtesting.FindDBRPMappings(initBoltDBRPMappingService, t)
+}
+
+func TestBoltDBRPMappingService_FindDBRPMapping(t *testing.T) {
+	influxdbtesting.FindDBRPMapping(initBoltDBRPMappingService, t)
+}
+
+func TestBoltDBRPMappingService_DeleteDBRPMapping(t *testing.T) {
+	influxdbtesting.DeleteDBRPMapping(initBoltDBRPMappingService, t)
+}
+
+func TestInmemDBRPMappingService_CreateDBRPMapping(t *testing.T) {
+	influxdbtesting.CreateDBRPMapping(initInmemDBRPMappingService, t)
+}
+
+func TestInmemDBRPMappingService_FindDBRPMappings(t *testing.T) {
+	influxdbtesting.FindDBRPMappings(initInmemDBRPMappingService, t)
+}
+
+func initBoltDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeStore()
+	}
+}
+
+func initDBRPMappingService(s kv.Store, f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing dbrp mapping service: %v", err)
+	}
+
+	if err := f.Populate(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	return svc, func() {
+		if err := influxdbtesting.CleanupDBRPMappings(ctx, svc); err != nil {
+			t.Logf("failed to remove dbrp mappings: %v", err)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+// IndexKeyFunc returns the index key of the resource stored with value v in
+// the source bucket of an index. A nil key means the resource is not indexed.
+type IndexKeyFunc func(v []byte) ([]byte, error)
+
+// Index is a secondary index of the resources stored in a source bucket. Its
+// entries map the index key of each resource, such as its name, to the key of
+// the resource in the source bucket. Entries are maintained in the
+// transaction changing the resource, and the index can be verified against
+// and rebuilt from the source bucket.
+type Index struct {
+	name     string
+	source   []byte
+	index    []byte
+	indexKey IndexKeyFunc
+}
+
+// NewIndex returns the index named name of the resources of the source bucket,
+// stored in the index bucket.
+func NewIndex(name string, source, index []byte, fn IndexKeyFunc) *Index {
+	return &Index{
+		name:     name,
+		source:   source,
+		index:    index,
+		indexKey: fn,
+	}
+}
+
+// Indexes returns the secondary indexes of the resources of the service.
+func (s *Service) Indexes() []*Index {
+	return []*Index{
+		authTokenIndex,
+		bucketNameIndex,
+		organizationNameIndex,
+		userNameIndex,
+	}
+}
+
+// VerifyIndexes verifies each index of the service against its source bucket
+// and, if repair is true, removes the dangling entries and inserts the missing
+// entries of the inconsistent indexes.
+func (s *Service) VerifyIndexes(ctx context.Context, repair bool) ([]*IndexReport, error) {
+	var reports []*IndexReport
+	verify := func(tx Tx) error {
+		reports = reports[:0]
+		for _, idx := range s.Indexes() {
+			report, err := idx.Verify(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if repair && !report.Consistent() {
+				if err := idx.Repair(ctx, tx, report); err != nil {
+					return err
+				}
+			}
+			reports = append(reports, report)
+		}
+		return nil
+	}
+
+	var err error
+	if repair {
+		err = s.kv.Update(ctx, verify)
+	} else {
+		err = s.kv.View(ctx, verify)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Name returns the name of the index.
+func (i *Index) Name() string {
+	return i.name
+}
+
+// Insert maps key to the key of a resource in the source bucket.
+func (i *Index) Insert(tx Tx, key, sourceKey []byte) error {
+	b, err := tx.Bucket(i.index)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, sourceKey)
+}
+
+// Delete removes the entry of key.
+func (i *Index) Delete(tx Tx, key []byte) error {
+	b, err := tx.Bucket(i.index)
+	if err != nil {
+		return err
+	}
+	return b.Delete(key)
+}
+
+// Lookup returns the key of the resource in the source bucket indexed by key,
+// or ErrKeyNotFound.
+func (i *Index) Lookup(tx Tx, key []byte) ([]byte, error) {
+	b, err := tx.Bucket(i.index)
+	if err != nil {
+		return nil, err
+	}
+	return b.Get(key)
+}
+
+// IndexEntry is an entry of an index.
+type IndexEntry struct {
+	Key       []byte
+	SourceKey []byte
+}
+
+// IndexReport is the outcome of the verification of an index.
+type IndexReport struct {
+	// Index is the name of the index.
+	Index string
+	// Dangling are the entries of resources which are missing from the source
+	// bucket, or indexed under another key.
+	Dangling []IndexEntry
+	// Missing are the entries of resources of the source bucket which are
+	// missing from the index.
+	Missing []IndexEntry
+}
+
+// Consistent returns whether the index matches its source bucket.
+func (r *IndexReport) Consistent() bool {
+	return len(r.Dangling) == 0 && len(r.Missing) == 0
+}
+
+// Verify compares the index with the entries of the resources of its source
+// bucket. When several resources have the same index key, the index is
+// consistent if it refers to any of them.
+func (i *Index) Verify(ctx context.Context, tx Tx) (*IndexReport, error) {
+	expected, order, err := i.expectedEntries(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(i.index)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IndexReport{Index: i.name}
+	found := make(map[string]bool)
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		if containsKey(expected[string(k)], v) {
+			found[string(k)] = true
+			continue
+		}
+		report.Dangling = append(report.Dangling, IndexEntry{
+			Key:       copyBytes(k),
+			SourceKey: copyBytes(v),
+		})
+	}
+
+	for _, k := range order {
+		if !found[k] {
+			report.Missing = append(report.Missing, IndexEntry{
+				Key:       []byte(k),
+				SourceKey: expected[k][0],
+			})
+		}
+	}
+	return report, nil
+}
+
+// Repair removes the dangling entries of the report and inserts its missing
+// entries.
+func (i *Index) Repair(ctx context.Context, tx Tx, report *IndexReport) error {
+	for _, e := range report.Dangling {
+		if err := i.Delete(tx, e.Key); err != nil {
+			return err
+		}
+	}
+	for _, e := range report.Missing {
+		if err := i.Insert(tx, e.Key, e.SourceKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rebuild replaces all the entries of the index by the entries of the
+// resources of its source bucket.
+func (i *Index) Rebuild(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(i.index)
+	if err != nil {
+		return err
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := cur.First(); k != nil; k, _ = cur.Next() {
+		keys = append(keys, copyBytes(k))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	expected, order, err := i.expectedEntries(tx)
+	if err != nil {
+		return err
+	}
+	for _, k := range order {
+		if err := b.Put([]byte(k), expected[k][0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectedEntries returns the source keys of each index key of the resources
+// of the source bucket, and the index keys in the order they were found.
+func (i *Index) expectedEntries(tx Tx) (map[string][][]byte, []string, error) {
+	b, err := tx.Bucket(i.source)
+	if err != nil {
+		return nil, nil, err
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expected := make(map[string][][]byte)
+	var order []string
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		key, err := i.indexKey(v)
+		if err != nil {
+			return nil, nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("failed to compute the %s key of %x", i.name, k),
+				Err:  err,
+			}
+		}
+		if key == nil {
+			continue
+		}
+		if _, ok := expected[string(key)]; !ok {
+			order = append(order, string(key))
+		}
+		expected[string(key)] = append(expected[string(key)], copyBytes(k))
+	}
+	return expected, order, nil
+}
+
+func containsKey(keys [][]byte, key []byte) bool {
+	for _, k := range keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyBytes copies b, the keys and values returned by cursors are only valid
+// during their transaction and may be reused.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
@@ -0,0 +1,206 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestIndex(t *testing.T) {
+	stores := []struct {
+		name string
+		new  func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", new: NewTestBoltStore},
+		{name: "inmem", new: NewTestInmemStore},
+	}
+	for _, st := range stores {
+		t.Run(st.name, func(t *testing.T) {
+			s, closeStore, err := st.new()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+			testIndex(t, s)
+		})
+	}
+}
+
+func testIndex(t *testing.T, s kv.Store) {
+	source, index := []byte("things"), []byte("thingsindex")
+	// Things are indexed by their value, empty values are not indexed.
+	idx := kv.NewIndex("things by value", source, index, func(v []byte) ([]byte, error) {
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return v, nil
+	})
+
+	ctx := context.Background()
+	put := func(b []byte, kvs ...string) {
+		t.Helper()
+		err := s.Update(ctx, func(tx kv.Tx) error {
+			bkt, err := tx.Bucket(b)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < len(kvs); i += 2 {
+				if err := bkt.Put([]byte(kvs[i]), []byte(kvs[i+1])); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	verify := func() *kv.IndexReport {
+		t.Helper()
+		var report *kv.IndexReport
+		err := s.View(ctx, func(tx kv.Tx) (err error) {
+			report, err = idx.Verify(ctx, tx)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	put(source, "1", "a", "2", "b", "3", "")
+	// The entry of b is missing, c is dangling and x refers to another thing.
+	put(index, "a", "1", "c", "4", "x", "1")
+
+	report := verify()
+	if report.Consistent() {
+		t.Fatal("expected index to be inconsistent")
+	}
+	if report.Index != "things by value" {
+		t.Errorf("unexpected index name %q", report.Index)
+	}
+	if got := entries(report.Dangling); got != "c:4 x:1" {
+		t.Errorf("unexpected dangling entries %q", got)
+	}
+	if got := entries(report.Missing); got != "b:2" {
+		t.Errorf("unexpected missing entries %q", got)
+	}
+
+	err := s.Update(ctx, func(tx kv.Tx) error {
+		return idx.Repair(ctx, tx, report)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := verify(); !report.Consistent() {
+		t.Fatalf("expected repaired index to be consistent, got %+v", report)
+	}
+	err = s.View(ctx, func(tx kv.Tx) error {
+		k, err := idx.Lookup(tx, []byte("b"))
+		if err != nil {
+			return err
+		}
+		if string(k) != "2" {
+			t.Errorf("expected b to refer to 2, got %q", k)
+		}
+		if _, err := idx.Lookup(tx, []byte("c")); !kv.IsNotFound(err) {
+			t.Errorf("expected c to be removed, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	put(index, "d", "1")
+	put(source, "4", "e")
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		return idx.Rebuild(ctx, tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := verify(); !report.Consistent() {
+		t.Fatalf("expected rebuilt index to be consistent, got %+v", report)
+	}
+}
+
+func entries(es []kv.IndexEntry) string {
+	var s string
+	for i, e := range es {
+		if i > 0 {
+			s += " "
+		}
+		s += string(e.Key) + ":" + string(e.SourceKey)
+	}
+	return s
+}
+
+func TestService_VerifyIndexes(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUser(ctx, &influxdb.User{Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	// Drop the index entry of the user, as a crash of an older release could.
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("userindexv1"))
+		if err != nil {
+			return err
+		}
+		return b.Delete([]byte("user"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inconsistent := func(reports []*kv.IndexReport) []string {
+		var names []string
+		for _, r := range reports {
+			if !r.Consistent() {
+				names = append(names, r.Index)
+			}
+		}
+		return names
+	}
+
+	reports, err := svc.VerifyIndexes(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != len(svc.Indexes()) {
+		t.Fatalf("expected %d reports, got %d", len(svc.Indexes()), len(reports))
+	}
+	if got := inconsistent(reports); len(got) != 1 || got[0] != "users by name" {
+		t.Fatalf("expected the user index to be inconsistent, got %v", got)
+	}
+	name := "user"
+	if _, err := svc.FindUser(ctx, influxdb.UserFilter{Name: &name}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected user to not be found by name, got %v", err)
+	}
+
+	if _, err := svc.VerifyIndexes(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	reports, err = svc.VerifyIndexes(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inconsistent(reports); len(got) != 0 {
+		t.Fatalf("expected repaired indexes to be consistent, got %v", got)
+	}
+	if _, err := svc.FindUser(ctx, influxdb.UserFilter{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+}
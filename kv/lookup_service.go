@@ -57,6 +57,12 @@ func (s *Service) Name(ctx context.Context, resource influxdb.ResourceType, id i
 			return "", err
 		}
 		return r.Name, nil
+	case influxdb.RolesResourceType: // 17
+		r, err := s.FindRoleByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
 	}
 
 	return "", nil
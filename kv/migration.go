@@ -0,0 +1,304 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var migrationBucket = []byte("migrationsv1")
+
+// Migration is a versioned change of the data of a store, such as a change of
+// a stored struct or of an index.
+type Migration struct {
+	// Version orders the migrations, they are applied in increasing order.
+	Version int
+	// Name describes the migration.
+	Name string
+	// Up applies the migration. It must not depend on the buckets created by
+	// the initialization of the service, as the harness testing migrations
+	// runs them against empty stores.
+	Up func(ctx context.Context, tx Tx) error
+	// Down reverts the migration, a migration without Down cannot be
+	// reverted.
+	Down func(ctx context.Context, tx Tx) error
+}
+
+// AppliedMigration is the record of a migration applied to a store.
+type AppliedMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// Migrations returns the migrations of the data of the service, ordered by
+// version. New migrations are appended with the next version; migrations are
+// never reordered or removed once released.
+func (s *Service) Migrations() []Migration {
+	return []Migration{
+		{
+			// The tokens cannot be recovered from their hashes, so the
+			// migration cannot be reverted.
+			Version: 1,
+			Name:    "hash the tokens of authorizations",
+			Up:      s.migrateAuthorizationTokens,
+		},
+	}
+}
+
+// Migrator returns the migrator of the migrations of the service.
+func (s *Service) Migrator() (*Migrator, error) {
+	m, err := NewMigrator(s.kv, s.Migrations()...)
+	if err != nil {
+		return nil, err
+	}
+	m.now = s.Now
+	return m, nil
+}
+
+// Migrator applies and reverts the migrations of a store. The versions of the
+// applied migrations are recorded in the store, each migration being applied
+// or reverted in the transaction recording it.
+type Migrator struct {
+	store      Store
+	migrations []Migration
+	now        func() time.Time
+}
+
+// NewMigrator returns a migrator of migrations, which must be ordered by
+// strictly increasing positive versions.
+func NewMigrator(store Store, migrations ...Migration) (*Migrator, error) {
+	for i, m := range migrations {
+		if m.Version <= 0 || m.Name == "" || m.Up == nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("migration %d %q requires a positive version, a name and an up function", m.Version, m.Name),
+			}
+		}
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("migration %d %q is out of order", m.Version, m.Name),
+			}
+		}
+	}
+
+	return &Migrator{
+		store:      store,
+		migrations: migrations,
+		now:        time.Now,
+	}, nil
+}
+
+// Latest returns the version of the last migration, 0 without migrations.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Applied returns the migrations applied to the store, ordered by version.
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	var applied []AppliedMigration
+	err := m.store.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(migrationBucket)
+		if err != nil {
+			// The bucket cannot be created in a read-only transaction, the
+			// store has never been migrated.
+			return nil
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			var a AppliedMigration
+			if err := json.Unmarshal(v, &a); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Msg:  fmt.Sprintf("failed to decode applied migration %x", k),
+					Err:  err,
+				}
+			}
+			applied = append(applied, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(applied, func(i, j int) bool {
+		return applied[i].Version < applied[j].Version
+	})
+	return applied, nil
+}
+
+// Pending returns the migrations which are not applied to the store. It
+// returns an error if the store has migrations this migrator does not know,
+// applied by a newer release.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if !applied[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.MigrateTo(ctx, m.Latest())
+}
+
+// CheckMigrateTo returns an EConflict error if the store cannot be migrated to
+// version because an applied migration after it cannot be reverted.
+func (m *Migrator) CheckMigrateTo(ctx context.Context, version int) error {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	return m.checkMigrateTo(applied, version)
+}
+
+func (m *Migrator) checkMigrateTo(applied map[int]bool, version int) error {
+	for _, mig := range m.migrations {
+		if mig.Version <= version || !applied[mig.Version] || mig.Down != nil {
+			continue
+		}
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("cannot migrate to version %d: migration %d %q cannot be reverted", version, mig.Version, mig.Name),
+		}
+	}
+	return nil
+}
+
+// MigrateTo applies the pending migrations up to version, and reverts the
+// applied migrations after it, latest first. Nothing is applied or reverted
+// if a migration to revert cannot be reverted.
+func (m *Migrator) MigrateTo(ctx context.Context, version int) error {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.checkMigrateTo(applied, version); err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version > version || applied[mig.Version] {
+			continue
+		}
+		if err := m.apply(ctx, mig); err != nil {
+			return err
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if mig.Version <= version || !applied[mig.Version] {
+			continue
+		}
+		if err := m.revert(ctx, mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	return m.store.Update(ctx, func(tx Tx) error {
+		if err := mig.Up(ctx, tx); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("failed to apply migration %d %q", mig.Version, mig.Name),
+				Err:  err,
+			}
+		}
+
+		v, err := json.Marshal(AppliedMigration{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			AppliedAt: m.now(),
+		})
+		if err != nil {
+			return err
+		}
+		b, err := tx.Bucket(migrationBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(migrationKey(mig.Version), v)
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, mig Migration) error {
+	if mig.Down == nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("migration %d %q cannot be reverted", mig.Version, mig.Name),
+		}
+	}
+
+	return m.store.Update(ctx, func(tx Tx) error {
+		if err := mig.Down(ctx, tx); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("failed to revert migration %d %q", mig.Version, mig.Name),
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(migrationBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(migrationKey(mig.Version))
+	})
+}
+
+// appliedVersions returns the set of the versions applied to the store.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+	}
+
+	versions := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		if !known[a.Version] {
+			return nil, &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("store has migration %d %q which is unknown to this release; revert it with the release that applied it", a.Version, a.Name),
+			}
+		}
+		versions[a.Version] = true
+	}
+	return versions, nil
+}
+
+// migrationKey returns the key of the record of version, ordered by version.
+func migrationKey(version int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(version))
+	return k
+}
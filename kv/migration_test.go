@@ -0,0 +1,255 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestNewMigrator(t *testing.T) {
+	up := func(context.Context, kv.Tx) error { return nil }
+
+	tests := []struct {
+		name       string
+		migrations []kv.Migration
+		wantErr    bool
+	}{
+		{
+			name: "ordered migrations",
+			migrations: []kv.Migration{
+				{Version: 1, Name: "one", Up: up},
+				{Version: 3, Name: "three", Up: up},
+			},
+		},
+		{
+			name: "out of order",
+			migrations: []kv.Migration{
+				{Version: 2, Name: "two", Up: up},
+				{Version: 1, Name: "one", Up: up},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate version",
+			migrations: []kv.Migration{
+				{Version: 1, Name: "one", Up: up},
+				{Version: 1, Name: "other", Up: up},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "missing up",
+			migrations: []kv.Migration{{Version: 1, Name: "one"}},
+			wantErr:    true,
+		},
+		{
+			name:       "zero version",
+			migrations: []kv.Migration{{Name: "zero", Up: up}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := NewTestInmemStore()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeStore()
+
+			_, err = kv.NewMigrator(s, tt.migrations...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	var ran []string
+	migration := func(version int, name string, down bool) kv.Migration {
+		m := kv.Migration{
+			Version: version,
+			Name:    name,
+			Up: func(ctx context.Context, tx kv.Tx) error {
+				ran = append(ran, "up "+name)
+				return nil
+			},
+		}
+		if down {
+			m.Down = func(ctx context.Context, tx kv.Tx) error {
+				ran = append(ran, "down "+name)
+				return nil
+			}
+		}
+		return m
+	}
+	one, two, three := migration(1, "one", false), migration(2, "two", true), migration(3, "three", true)
+
+	ctx := context.Background()
+	m, err := kv.NewMigrator(s, one, two)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"up one", "up two"}; !reflect.DeepEqual(ran, exp) {
+		t.Fatalf("expected migrations %v to run, got %v", exp, ran)
+	}
+
+	// A new release adds a migration.
+	m, err = kv.NewMigrator(s, one, two, three)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Version != 3 {
+		t.Fatalf("expected migration 3 to be pending, got %v", pending)
+	}
+
+	ran = nil
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MigrateTo(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"up three", "down three", "down two"}; !reflect.DeepEqual(ran, exp) {
+		t.Fatalf("expected migrations %v to run, got %v", exp, ran)
+	}
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0].Version != 1 || applied[0].Name != "one" || applied[0].AppliedAt.IsZero() {
+		t.Fatalf("unexpected applied migrations %+v", applied)
+	}
+
+	// Nothing is reverted when an irreversible migration would be.
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ran = nil
+	if err := m.CheckMigrateTo(ctx, 0); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected checking the revert of an irreversible migration to fail, got %v", err)
+	}
+	if err := m.MigrateTo(ctx, 0); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected reverting an irreversible migration to fail, got %v", err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("expected no migrations to run, got %v", ran)
+	}
+	if pending, err := m.Pending(ctx); err != nil || len(pending) != 0 {
+		t.Fatalf("expected the migrations to stay applied, got %v pending: %v", pending, err)
+	}
+
+	// An older release does not know the migrations of newer ones.
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	old, err := kv.NewMigrator(s, one)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := old.Up(ctx); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected migrating a store of a newer release to fail, got %v", err)
+	}
+}
+
+func TestMigrator_FailedMigration(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	m, err := kv.NewMigrator(s, kv.Migration{
+		Version: 1,
+		Name:    "failing",
+		Up: func(ctx context.Context, tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("failing"))
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte("k"), []byte("v")); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(ctx); err == nil {
+		t.Fatal("expected migration to fail")
+	}
+
+	// The changes of the migration are rolled back with its record.
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected the failed migration to be pending, got %v", pending)
+	}
+	err = s.View(ctx, func(tx kv.Tx) error {
+		if _, err := tx.Bucket([]byte("failing")); err == nil {
+			t.Error("expected the bucket of the failed migration to be rolled back")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_Initialize_Migrations(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := svc.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending migrations after initialization, got %v", pending)
+	}
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(svc.Migrations()) {
+		t.Fatalf("expected %d applied migrations, got %d", len(svc.Migrations()), len(applied))
+	}
+}
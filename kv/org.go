@@ -34,6 +34,15 @@ var ErrFailureGeneratingID = &influxdb.Error{
 	Msg:  "unable to generate valid id",
 }
 
+// organizationNameIndex indexes the organizations by name.
+var organizationNameIndex = NewIndex("organizations by name", organizationBucket, organizationIndex, func(v []byte) ([]byte, error) {
+	var o influxdb.Organization
+	if err := json.Unmarshal(v, &o); err != nil {
+		return nil, err
+	}
+	return organizationIndexKey(o.Name), nil
+})
+
 var _ influxdb.OrganizationService = (*Service)(nil)
 var _ influxdb.OrganizationOperationLogService = (*Service)(nil)
 
@@ -130,12 +139,7 @@ func (s *Service) findOrganizationByName(ctx context.Context, tx Tx, n string) (
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
-	b, err := tx.Bucket(organizationIndex)
-	if err != nil {
-		return nil, err
-	}
-
-	o, err := b.Get(organizationIndexKey(n))
+	o, err := organizationNameIndex.Lookup(tx, organizationIndexKey(n))
 	if IsNotFound(err) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -331,12 +335,7 @@ func (s *Service) putOrganization(ctx context.Context, tx Tx, o *influxdb.Organi
 		}
 	}
 
-	idx, err := tx.Bucket(organizationIndex)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Put(organizationIndexKey(o.Name), encodedID); err != nil {
+	if err := organizationNameIndex.Insert(tx, organizationIndexKey(o.Name), encodedID); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -425,11 +424,7 @@ func (s *Service) updateOrganization(ctx context.Context, tx Tx, id influxdb.ID,
 	if upd.Name != nil {
 		// Organizations are indexed by name and so the organization index must be pruned
 		// when name is modified.
-		idx, err := tx.Bucket(organizationIndex)
-		if err != nil {
-			return nil, err
-		}
-		if err := idx.Delete(organizationIndexKey(o.Name)); err != nil {
+		if err := organizationNameIndex.Delete(tx, organizationIndexKey(o.Name)); err != nil {
 			return nil, &influxdb.Error{
 				Err: err,
 			}
@@ -500,12 +495,7 @@ func (s *Service) deleteOrganization(ctx context.Context, tx Tx, id influxdb.ID)
 		return pe
 	}
 
-	idx, err := tx.Bucket(organizationIndex)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Delete(organizationIndexKey(o.Name)); err != nil {
+	if err := organizationNameIndex.Delete(tx, organizationIndexKey(o.Name)); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -678,6 +668,12 @@ func (s *Service) FindResourceOrganizationID(ctx context.Context, rt influxdb.Re
 			return influxdb.InvalidID(), err
 		}
 		return r.OrgID, nil
+	case influxdb.RolesResourceType:
+		r, err := s.FindRoleByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
 	}
 
 	return influxdb.InvalidID(), &influxdb.Error{
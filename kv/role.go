@@ -0,0 +1,521 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var (
+	roleBucket = []byte("rolesv1")
+	roleIndex  = []byte("roleindexv1")
+)
+
+var _ influxdb.RoleService = (*Service)(nil)
+
+var errRoleNotFound = &influxdb.Error{
+	Code: influxdb.ENotFound,
+	Msg:  influxdb.ErrRoleNotFound,
+}
+
+func (s *Service) initializeRoles(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(roleBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(roleIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// roleIndexKey is the key of a role in the index, the encoded org ID followed
+// by the name of the role.
+func roleIndexKey(orgID influxdb.ID, name string) ([]byte, error) {
+	prefix, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, name...), nil
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *Service) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		role, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = role
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRoleByID,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+func (s *Service) findRoleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Role, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, errRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var r influxdb.Role
+	if err := json.Unmarshal(v, &r); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return &r, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *Service) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var roles []*influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rs, err := s.findRoles(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		roles = rs
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindRoles,
+			Err: err,
+		}
+	}
+	return roles, len(roles), nil
+}
+
+func (s *Service) findRoles(ctx context.Context, tx Tx, filter influxdb.RoleFilter) ([]*influxdb.Role, error) {
+	if filter.ID != nil {
+		r, err := s.findRoleByID(ctx, tx, *filter.ID)
+		if err == errRoleNotFound {
+			return []*influxdb.Role{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterRoleFn(filter)(r) {
+			return []*influxdb.Role{}, nil
+		}
+		return []*influxdb.Role{r}, nil
+	}
+
+	if filter.Org != nil {
+		o, err := s.findOrganizationByName(ctx, tx, *filter.Org)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	roles := []*influxdb.Role{}
+	filterFn := filterRoleFn(filter)
+	if filter.OrgID == nil {
+		err := s.forEachRole(ctx, tx, func(r *influxdb.Role) {
+			if filterFn(r) {
+				roles = append(roles, r)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		return roles, nil
+	}
+
+	name := ""
+	if filter.Name != nil {
+		name = *filter.Name
+	}
+	prefix, err := roleIndexKey(*filter.OrgID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := tx.Bucket(roleIndex)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		var id influxdb.ID
+		if err := id.Decode(v); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if filterFn(r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles, nil
+}
+
+func filterRoleFn(filter influxdb.RoleFilter) func(r *influxdb.Role) bool {
+	return func(r *influxdb.Role) bool {
+		return (filter.ID == nil || *filter.ID == r.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == r.OrgID) &&
+			(filter.Name == nil || *filter.Name == r.Name)
+	}
+}
+
+func (s *Service) forEachRole(ctx context.Context, tx Tx, fn func(*influxdb.Role)) error {
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		var r influxdb.Role
+		if err := json.Unmarshal(v, &r); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+		fn(&r)
+	}
+	return nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *Service) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := r.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+			return err
+		}
+
+		key, err := roleIndexKey(r.OrgID, r.Name)
+		if err != nil {
+			return err
+		}
+		if err := s.unique(ctx, tx, roleIndex, key); err == NotUniqueError {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("role with name %s already exists", r.Name),
+			}
+		} else if err != nil {
+			return err
+		}
+
+		r.ID = s.IDGenerator.ID()
+		return s.putRole(ctx, tx, r)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateRole,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putRole(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	encodedID, err := r.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key, err := roleIndexKey(r.OrgID, r.Name)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(encodedID, v); err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(roleIndex)
+	if err != nil {
+		return err
+	}
+	return idx.Put(key, encodedID)
+}
+
+func (s *Service) deleteRoleIndex(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	key, err := roleIndexKey(r.OrgID, r.Name)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(roleIndex)
+	if err != nil {
+		return err
+	}
+	return idx.Delete(key)
+}
+
+// UpdateRole updates a single role with changeset.
+func (s *Service) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var role *influxdb.Role
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if upd.Name != nil && *upd.Name != r.Name {
+			key, err := roleIndexKey(r.OrgID, *upd.Name)
+			if err != nil {
+				return err
+			}
+			if err := s.unique(ctx, tx, roleIndex, key); err == NotUniqueError {
+				return &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  fmt.Sprintf("role with name %s already exists", *upd.Name),
+				}
+			} else if err != nil {
+				return err
+			}
+			if err := s.deleteRoleIndex(ctx, tx, r); err != nil {
+				return err
+			}
+		}
+
+		if err := upd.Apply(r); err != nil {
+			return err
+		}
+		if err := s.putRole(ctx, tx, r); err != nil {
+			return err
+		}
+
+		role = r
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateRole,
+			Err: err,
+		}
+	}
+	return role, nil
+}
+
+// DeleteRole removes a role by ID, and its references from members.
+func (s *Service) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+			ResourceID:   r.OrgID,
+			ResourceType: influxdb.OrgsResourceType,
+		})
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			if influxdb.HasRole(m.RoleIDs, id) {
+				m.RoleIDs = removeRoleID(m.RoleIDs, id)
+				if err := s.putUserResourceMapping(ctx, tx, m); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := s.deleteRoleIndex(ctx, tx, r); err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+		b, err := tx.Bucket(roleBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodedID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteRole,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// AddRoleMember grants a role to a member or an owner of the organization of the role.
+func (s *Service) AddRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findRoleMapping(ctx, tx, roleID, userID)
+		if err != nil {
+			return err
+		}
+		if influxdb.HasRole(m.RoleIDs, roleID) {
+			return nil
+		}
+		m.RoleIDs = append(m.RoleIDs, roleID)
+		return s.putUserResourceMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAddRoleMember,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// RemoveRoleMember revokes a role from a user.
+func (s *Service) RemoveRoleMember(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findRoleMapping(ctx, tx, roleID, userID)
+		if err != nil {
+			return err
+		}
+		if !influxdb.HasRole(m.RoleIDs, roleID) {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "user is not a member of the role",
+			}
+		}
+		m.RoleIDs = removeRoleID(m.RoleIDs, roleID)
+		return s.putUserResourceMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRemoveRoleMember,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// findRoleMapping returns the mapping of the user to the organization of the role.
+func (s *Service) findRoleMapping(ctx context.Context, tx Tx, roleID, userID influxdb.ID) (*influxdb.UserResourceMapping, error) {
+	r, err := s.findRoleByID(ctx, tx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.findUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   r.OrgID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       userID,
+	})
+	if err == ErrURMNotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "user is not a member of the organization of the role",
+		}
+	}
+	return m, err
+}
+
+// validRoleIDs returns an error unless all roles exist and belong to the organization.
+func (s *Service) validRoleIDs(ctx context.Context, tx Tx, orgID influxdb.ID, ids []influxdb.ID) error {
+	for _, id := range ids {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if r.OrgID != orgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("role %s does not belong to org %s", id, orgID),
+			}
+		}
+	}
+	return nil
+}
+
+func removeRoleID(ids []influxdb.ID, id influxdb.ID) []influxdb.ID {
+	res := ids[:0]
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
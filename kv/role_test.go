@@ -0,0 +1,247 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestBoltRoleService(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testRoleService(s, t)
+}
+
+func TestInmemRoleService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	testRoleService(s, t)
+}
+
+func testRoleService(s kv.Store, t *testing.T) {
+	ctx := context.Background()
+
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	other := &influxdb.Organization{Name: "other"}
+	if err := svc.CreateOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "jane"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := &influxdb.Role{
+		OrgID: org.ID,
+		Name:  "bucket-writer",
+		Permissions: []influxdb.RolePermission{
+			{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType, Labels: []string{"team=infra"}},
+			},
+		},
+	}
+	if err := svc.CreateRole(ctx, writer); err != nil {
+		t.Fatalf("CreateRole() unexpected error: %v", err)
+	}
+	if !writer.ID.Valid() {
+		t.Fatalf("CreateRole() did not set id: %+v", writer)
+	}
+
+	viewer := &influxdb.Role{
+		OrgID: org.ID,
+		Name:  "dashboard-viewer",
+		Permissions: []influxdb.RolePermission{
+			{Action: influxdb.ReadAction, Resource: influxdb.RoleResource{Type: influxdb.DashboardsResourceType}},
+		},
+	}
+	if err := svc.CreateRole(ctx, viewer); err != nil {
+		t.Fatalf("CreateRole() unexpected error: %v", err)
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: "bucket-writer"})
+		if code := influxdb.ErrorCode(err); code != influxdb.EConflict {
+			t.Fatalf("CreateRole() expected conflict, got %q: %v", code, err)
+		}
+		// The same name is allowed in another organization.
+		if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: other.ID, Name: "bucket-writer"}); err != nil {
+			t.Fatalf("CreateRole() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid permission", func(t *testing.T) {
+		id := influxdb.ID(1)
+		err := svc.CreateRole(ctx, &influxdb.Role{
+			OrgID: org.ID,
+			Name:  "invalid",
+			Permissions: []influxdb.RolePermission{
+				{
+					Action:   influxdb.ReadAction,
+					Resource: influxdb.RoleResource{Type: influxdb.BucketsResourceType, ID: &id, Labels: []string{"a"}},
+				},
+			},
+		})
+		if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+			t.Fatalf("CreateRole() expected invalid, got %q: %v", code, err)
+		}
+	})
+
+	t.Run("find", func(t *testing.T) {
+		roles, n, err := svc.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Fatalf("FindRoles() expected 2 roles, got %d", n)
+		}
+		if diff := cmp.Diff([]*influxdb.Role{writer, viewer}, roles); diff != "" {
+			t.Fatalf("FindRoles() unexpected roles -want/+got\n%s", diff)
+		}
+
+		name := "bucket"
+		roles, _, err = svc.FindRoles(ctx, influxdb.RoleFilter{Org: &org.Name, Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roles) != 0 {
+			t.Fatalf("FindRoles() expected an exact name match, got %+v", roles)
+		}
+
+		roles, _, err = svc.FindRoles(ctx, influxdb.RoleFilter{Org: &org.Name, Name: &viewer.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*influxdb.Role{viewer}, roles); diff != "" {
+			t.Fatalf("FindRoles() unexpected roles -want/+got\n%s", diff)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		name := "dashboard-reader"
+		r, err := svc.UpdateRole(ctx, viewer.ID, influxdb.RoleUpdate{Name: &name})
+		if err != nil {
+			t.Fatalf("UpdateRole() unexpected error: %v", err)
+		}
+		if r.Name != name {
+			t.Fatalf("UpdateRole() did not rename role: %+v", r)
+		}
+
+		roles, _, err := svc.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID, Name: &viewer.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roles) != 0 {
+			t.Fatalf("FindRoles() found role by its previous name: %+v", roles)
+		}
+
+		_, err = svc.UpdateRole(ctx, viewer.ID, influxdb.RoleUpdate{Name: &writer.Name})
+		if code := influxdb.ErrorCode(err); code != influxdb.EConflict {
+			t.Fatalf("UpdateRole() expected conflict, got %q: %v", code, err)
+		}
+	})
+
+	t.Run("members", func(t *testing.T) {
+		err := svc.AddRoleMember(ctx, writer.ID, user.ID)
+		if code := influxdb.ErrorCode(err); code != influxdb.ENotFound {
+			t.Fatalf("AddRoleMember() expected not found for a user outside of the org, got %q: %v", code, err)
+		}
+
+		if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			UserID:       user.ID,
+			UserType:     influxdb.Member,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   org.ID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, id := range []influxdb.ID{writer.ID, viewer.ID, writer.ID} {
+			if err := svc.AddRoleMember(ctx, id, user.ID); err != nil {
+				t.Fatalf("AddRoleMember() unexpected error: %v", err)
+			}
+		}
+		roleIDs := func() []influxdb.ID {
+			ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+				UserID:       user.ID,
+				ResourceID:   org.ID,
+				ResourceType: influxdb.OrgsResourceType,
+			})
+			if err != nil || len(ms) != 1 {
+				t.Fatalf("unexpected mappings %+v: %v", ms, err)
+			}
+			return ms[0].RoleIDs
+		}
+		if diff := cmp.Diff([]influxdb.ID{writer.ID, viewer.ID}, roleIDs()); diff != "" {
+			t.Fatalf("AddRoleMember() unexpected role ids -want/+got\n%s", diff)
+		}
+
+		if err := svc.RemoveRoleMember(ctx, viewer.ID, user.ID); err != nil {
+			t.Fatalf("RemoveRoleMember() unexpected error: %v", err)
+		}
+		if err := svc.RemoveRoleMember(ctx, viewer.ID, user.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Fatalf("RemoveRoleMember() expected not found, got %v", err)
+		}
+
+		if err := svc.DeleteRole(ctx, writer.ID); err != nil {
+			t.Fatalf("DeleteRole() unexpected error: %v", err)
+		}
+		if ids := roleIDs(); len(ids) != 0 {
+			t.Fatalf("DeleteRole() did not remove role from members: %v", ids)
+		}
+		if _, err := svc.FindRoleByID(ctx, writer.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Fatalf("FindRoleByID() expected not found, got %v", err)
+		}
+	})
+
+	t.Run("authorization roles", func(t *testing.T) {
+		r := &influxdb.Role{OrgID: other.ID, Name: "elsewhere"}
+		if err := svc.CreateRole(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+
+		err := svc.CreateAuthorization(ctx, &influxdb.Authorization{
+			OrgID:   org.ID,
+			UserID:  user.ID,
+			RoleIDs: []influxdb.ID{r.ID},
+		})
+		if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+			t.Fatalf("CreateAuthorization() expected invalid for a role of another org, got %q: %v", code, err)
+		}
+
+		a := &influxdb.Authorization{
+			OrgID:   org.ID,
+			UserID:  user.ID,
+			RoleIDs: []influxdb.ID{viewer.ID},
+		}
+		if err := svc.CreateAuthorization(ctx, a); err != nil {
+			t.Fatalf("CreateAuthorization() unexpected error: %v", err)
+		}
+		got, err := svc.FindAuthorizationByID(ctx, a.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]influxdb.ID{viewer.ID}, got.RoleIDs); diff != "" {
+			t.Fatalf("FindAuthorizationByID() unexpected role ids -want/+got\n%s", diff)
+		}
+	})
+}
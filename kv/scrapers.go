@@ -76,13 +76,18 @@ func InternalScraperServiceError(err error) *influxdb.Error {
 }
 
 var (
-	scrapersBucket = []byte("scraperv2")
+	scrapersBucket      = []byte("scraperv2")
+	scraperStatusBucket = []byte("scraperstatusv1")
 )
 
 var _ influxdb.ScraperTargetStoreService = (*Service)(nil)
+var _ influxdb.ScraperTargetStatusService = (*Service)(nil)
 
 func (s *Service) initializeScraperTargets(ctx context.Context, tx Tx) error {
-	_, err := s.scrapersBucket(tx)
+	if _, err := s.scrapersBucket(tx); err != nil {
+		return err
+	}
+	_, err := s.scraperStatusBucket(tx)
 	return err
 }
 
@@ -95,6 +100,15 @@ func (s *Service) scrapersBucket(tx Tx) (Bucket, error) {
 	return b, nil
 }
 
+func (s *Service) scraperStatusBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(scraperStatusBucket)
+	if err != nil {
+		return nil, UnexpectedScrapersBucketError(err)
+	}
+
+	return b, nil
+}
+
 // ListTargets will list all scrape targets.
 func (s *Service) ListTargets(ctx context.Context, filter influxdb.ScraperTargetFilter) ([]influxdb.ScraperTarget, error) {
 	targets := []influxdb.ScraperTarget{}
@@ -170,6 +184,10 @@ func (s *Service) addTarget(ctx context.Context, tx Tx, target *influxdb.Scraper
 		return ErrInvalidScrapersBucketID
 	}
 
+	if err := target.Valid(); err != nil {
+		return err
+	}
+
 	target.ID = s.IDGenerator.ID()
 	if err := s.putTarget(ctx, tx, target); err != nil {
 		return err
@@ -218,6 +236,14 @@ func (s *Service) removeTarget(ctx context.Context, tx Tx, id influxdb.ID) error
 		return InternalScraperServiceError(err)
 	}
 
+	statuses, err := s.scraperStatusBucket(tx)
+	if err != nil {
+		return err
+	}
+	if err := statuses.Delete(encID); err != nil {
+		return InternalScraperServiceError(err)
+	}
+
 	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID:   id,
 		ResourceType: influxdb.ScraperResourceType,
@@ -253,6 +279,9 @@ func (s *Service) updateTarget(ctx context.Context, tx Tx, update *influxdb.Scra
 	if !update.OrgID.Valid() {
 		update.OrgID = target.OrgID
 	}
+	if err := update.Valid(); err != nil {
+		return nil, err
+	}
 	target = update
 	return target, s.putTarget(ctx, tx, target)
 }
@@ -326,6 +355,86 @@ func (s *Service) putTarget(ctx context.Context, tx Tx, target *influxdb.Scraper
 	return nil
 }
 
+// GetTargetStatus returns the status of the last scrape of a target, the
+// status is unknown if the target was not scraped yet.
+func (s *Service) GetTargetStatus(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	var status *influxdb.ScraperTargetStatus
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		status, err = s.getTargetStatus(ctx, tx, id)
+		return err
+	})
+	return status, err
+}
+
+func (s *Service) getTargetStatus(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	if _, err := s.findTargetByID(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidScraperID
+	}
+
+	bucket, err := s.scraperStatusBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return &influxdb.ScraperTargetStatus{
+			TargetID: id,
+			Status:   influxdb.ScraperTargetUnknown,
+		}, nil
+	}
+	if err != nil {
+		return nil, InternalScraperServiceError(err)
+	}
+
+	status := &influxdb.ScraperTargetStatus{}
+	if err := json.Unmarshal(v, status); err != nil {
+		return nil, CorruptScraperError(err)
+	}
+	return status, nil
+}
+
+// PutTargetStatus records the status of the last scrape of a target.
+func (s *Service) PutTargetStatus(ctx context.Context, status *influxdb.ScraperTargetStatus) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.putTargetStatus(ctx, tx, status)
+	})
+}
+
+func (s *Service) putTargetStatus(ctx context.Context, tx Tx, status *influxdb.ScraperTargetStatus) error {
+	// The target may have been removed while it was scraped.
+	if _, err := s.findTargetByID(ctx, tx, status.TargetID); err != nil {
+		return err
+	}
+
+	encID, err := status.TargetID.Encode()
+	if err != nil {
+		return ErrInvalidScraperID
+	}
+
+	v, err := json.Marshal(status)
+	if err != nil {
+		return ErrUnprocessableScraper(err)
+	}
+
+	bucket, err := s.scraperStatusBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encID, v); err != nil {
+		return UnexpectedScrapersBucketError(err)
+	}
+
+	return nil
+}
+
 // unmarshalScraper turns the stored byte slice in the kv into a *influxdb.ScraperTarget.
 func unmarshalScraper(v []byte) (*influxdb.ScraperTarget, error) {
 	s := &influxdb.ScraperTarget{}
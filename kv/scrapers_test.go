@@ -3,7 +3,9 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
@@ -83,3 +85,65 @@ func initScraperTargetStoreService(s kv.Store, f influxdbtesting.TargetFields, t
 		}
 	}
 }
+
+func TestScraperTargetStatus(t *testing.T) {
+	s, closeFn, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	target := &influxdb.ScraperTarget{
+		ID:       influxdbtesting.MustIDBase16("0000000000000111"),
+		Type:     influxdb.PrometheusScraperType,
+		URL:      "http://localhost:9090/metrics",
+		OrgID:    influxdbtesting.MustIDBase16("0000000000000211"),
+		BucketID: influxdbtesting.MustIDBase16("0000000000000212"),
+	}
+	if err := svc.PutTarget(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := svc.GetTargetStatus(ctx, target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != influxdb.ScraperTargetUnknown {
+		t.Errorf("status of a target not scraped yet is %q", status.Status)
+	}
+
+	want := &influxdb.ScraperTargetStatus{
+		TargetID:   target.ID,
+		Status:     influxdb.ScraperTargetUp,
+		LastScrape: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:   influxdb.Duration{Duration: time.Second},
+		HTTPStatus: 200,
+		Samples:    10,
+	}
+	if err := svc.PutTargetStatus(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	status, err = svc.GetTargetStatus(ctx, target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Errorf("unexpected status -want/+got\n%s", diff)
+	}
+
+	if err := svc.RemoveTarget(ctx, target.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetTargetStatus(ctx, target.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected not found error for a removed target, got %v", err)
+	}
+	if err := svc.PutTargetStatus(ctx, want); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected not found error for a removed target, got %v", err)
+	}
+}
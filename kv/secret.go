@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/secret"
 )
 
 var (
@@ -65,7 +66,7 @@ func (s *Service) loadSecret(ctx context.Context, tx Tx, orgID influxdb.ID, k st
 		return "", err
 	}
 
-	v, err := decodeSecretValue(val)
+	v, err := s.decodeSecretValue(key, val)
 	if err != nil {
 		return "", err
 	}
@@ -162,7 +163,10 @@ func (s *Service) putSecret(ctx context.Context, tx Tx, orgID influxdb.ID, k, v
 		return err
 	}
 
-	val := encodeSecretValue(v)
+	val, err := s.encodeSecretValue(key, v)
+	if err != nil {
+		return err
+	}
 
 	b, err := tx.Bucket(secretBucket)
 	if err != nil {
@@ -205,7 +209,24 @@ func decodeSecretKey(key []byte) (influxdb.ID, string, error) {
 	return id, k, nil
 }
 
-func decodeSecretValue(val []byte) (string, error) {
+// decodeSecretValue decodes the value val of the secret stored at key.
+func (s *Service) decodeSecretValue(key, val []byte) (string, error) {
+	if secret.IsEnvelope(val) {
+		if s.SecretKeyring == nil {
+			return "", &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "secret is encrypted but no secret keys are configured",
+			}
+		}
+		// The key is authenticated so that encrypted values cannot be
+		// swapped between secrets.
+		v, err := s.SecretKeyring.Decrypt(val, key)
+		if err != nil {
+			return "", err
+		}
+		return string(v), nil
+	}
+
 	// store the secret value base64 encoded so that it's marginally better than plaintext
 	v, err := base64.StdEncoding.DecodeString(string(val))
 	if err != nil {
@@ -215,10 +236,74 @@ func decodeSecretValue(val []byte) (string, error) {
 	return string(v), nil
 }
 
-func encodeSecretValue(v string) []byte {
+// encodeSecretValue encodes the value v of the secret stored at key.
+func (s *Service) encodeSecretValue(key []byte, v string) ([]byte, error) {
+	if s.SecretKeyring != nil {
+		return s.SecretKeyring.Encrypt([]byte(v), key)
+	}
+
 	val := make([]byte, base64.StdEncoding.EncodedLen(len(v)))
 	base64.StdEncoding.Encode(val, []byte(v))
-	return val
+	return val, nil
+}
+
+// RotateSecrets encrypts the data keys of the secrets with the primary key of
+// the keyring, and encrypts the secrets stored before the keyring was
+// configured. It returns the number of secrets updated. Once the secrets are
+// rotated, the previous keys can be removed from the keyring.
+func (s *Service) RotateSecrets(ctx context.Context) (int, error) {
+	if s.SecretKeyring == nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "secrets can only be rotated when secret keys are configured",
+		}
+	}
+
+	var n int
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(secretBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		// The cursor is exhausted before the secrets are updated so that
+		// updating the bucket does not invalidate it.
+		updates := make(map[string][]byte)
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			if !secret.IsEnvelope(v) {
+				plaintext, err := base64.StdEncoding.DecodeString(string(v))
+				if err != nil {
+					return err
+				}
+				if updates[string(k)], err = s.SecretKeyring.Encrypt(plaintext, k); err != nil {
+					return err
+				}
+				continue
+			}
+
+			rewrapped, ok, err := s.SecretKeyring.Rewrap(v)
+			if err != nil {
+				return err
+			}
+			if ok {
+				updates[string(k)] = rewrapped
+			}
+		}
+
+		for k, v := range updates {
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		n = len(updates)
+		return nil
+	})
+	return n, err
 }
 
 // PutSecrets puts all provided secrets and overwrites any previous values.
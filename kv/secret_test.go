@@ -1,11 +1,13 @@
 package kv_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/secret"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 )
 
@@ -17,6 +19,100 @@ func TestInmemSecretService(t *testing.T) {
 	influxdbtesting.SecretService(initInmemSecretService, t)
 }
 
+func TestEncryptedSecretService(t *testing.T) {
+	influxdbtesting.SecretService(initEncryptedSecretService, t)
+}
+
+func initEncryptedSecretService(f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initSecretService(s, f, t, mustKeyring(t, "k1"))
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func TestRotateSecrets(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RotateSecrets(ctx); err == nil {
+		t.Fatal("expected rotating secrets without keyring to fail")
+	}
+
+	// A secret stored before encryption was configured.
+	if err := svc.PutSecret(ctx, 1, "plain", "v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	k1 := mustKeyring(t, "k1")
+	svc.SecretKeyring = k1
+	if err := svc.PutSecret(ctx, 1, "encrypted", "v2"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := svc.RotateSecrets(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 secret rotated, got %d: %v", n, err)
+	}
+
+	// The new primary key is first, k1 only decrypts the data keys until the
+	// secrets are rotated.
+	k2, k1Key := mustKey(t, "k2"), mustKey(t, "k1")
+	keyring, err := secret.NewKeyring(k2, k1Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SecretKeyring = keyring
+	if n, err := svc.RotateSecrets(ctx); err != nil || n != 2 {
+		t.Fatalf("expected 2 secrets rotated, got %d: %v", n, err)
+	}
+
+	onlyK2, err := secret.NewKeyring(k2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SecretKeyring = onlyK2
+	for k, want := range map[string]string{"plain": "v1", "encrypted": "v2"} {
+		v, err := svc.LoadSecret(ctx, 1, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != want {
+			t.Errorf("expected secret %s to be %q, got %q", k, want, v)
+		}
+	}
+
+	svc.SecretKeyring = nil
+	if _, err := svc.LoadSecret(ctx, 1, "plain"); err == nil {
+		t.Error("expected loading an encrypted secret without keyring to fail")
+	}
+}
+
+// mustKey returns a key whose bytes are derived from id.
+func mustKey(t *testing.T, id string) secret.Key {
+	key := bytes.Repeat([]byte(id), secret.KeySize)[:secret.KeySize]
+	return secret.Key{ID: id, Key: key}
+}
+
+func mustKeyring(t *testing.T, id string) *secret.Keyring {
+	k, err := secret.NewKeyring(mustKey(t, id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
 func initBoltSecretService(f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
 	s, closeBolt, err := NewTestBoltStore()
 	if err != nil {
@@ -43,8 +139,11 @@ func initInmemSecretService(f influxdbtesting.SecretServiceFields, t *testing.T)
 	}
 }
 
-func initSecretService(s kv.Store, f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+func initSecretService(s kv.Store, f influxdbtesting.SecretServiceFields, t *testing.T, keyring ...*secret.Keyring) (influxdb.SecretService, func()) {
 	svc := kv.NewService(s)
+	if len(keyring) > 0 {
+		svc.SecretKeyring = keyring[0]
+	}
 	ctx := context.Background()
 	if err := svc.Initialize(ctx); err != nil {
 		t.Fatalf("error initializing secret service: %v", err)
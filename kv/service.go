@@ -8,6 +8,7 @@ import (
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/rand"
+	"github.com/influxdata/influxdb/secret"
 	"github.com/influxdata/influxdb/snowflake"
 )
 
@@ -34,6 +35,10 @@ type Service struct {
 	TokenGenerator influxdb.TokenGenerator
 	influxdb.TimeGenerator
 	Hash Crypt
+
+	// SecretKeyring encrypts the secrets stored by the service when it is
+	// set, they are stored base64 encoded otherwise.
+	SecretKeyring *secret.Keyring
 }
 
 // NewService returns an instance of a Service.
@@ -63,13 +68,29 @@ type ServiceConfig struct {
 	SessionLength time.Duration
 }
 
-// Initialize creates Buckets needed.
+// Initialize creates Buckets needed and applies the pending migrations.
 func (s *Service) Initialize(ctx context.Context) error {
+	if err := s.initialize(ctx); err != nil {
+		return err
+	}
+
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
+
+func (s *Service) initialize(ctx context.Context) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		if err := s.initializeAuths(ctx, tx); err != nil {
 			return err
 		}
 
+		if err := s.initializeDBRPMappings(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeDocuments(ctx, tx); err != nil {
 			return err
 		}
@@ -90,6 +111,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeMeasurementSchemas(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeOnboarding(ctx, tx); err != nil {
 			return err
 		}
@@ -106,6 +131,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeRoles(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeScraperTargets(ctx, tx); err != nil {
 			return err
 		}
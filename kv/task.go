@@ -88,11 +88,36 @@ func (s *Service) findTaskByIDWithAuth(ctx context.Context, tx Tx, id influxdb.I
 			return nil, err
 		}
 		t.Authorization.Permissions = ps
+
+		roleIDs, err := s.taskRoleIDs(ctx, tx, t.OwnerID, t.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		t.Authorization.RoleIDs = roleIDs
 	}
 
 	return t, nil
 }
 
+// taskRoleIDs returns the roles of the owner of a task in the organization of
+// the task, which are granted to the runs of the task like to the sessions of
+// the owner.
+func (s *Service) taskRoleIDs(ctx context.Context, tx Tx, ownerID, orgID influxdb.ID) ([]influxdb.ID, error) {
+	ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID:       ownerID,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ids []influxdb.ID
+	for _, m := range ms {
+		ids = append(ids, m.RoleIDs...)
+	}
+	return ids, nil
+}
+
 // findTaskByID is an internal method used to do any action with tasks internally
 // that do not require authorization.
 func (s *Service) findTaskByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Task, error) {
@@ -651,11 +676,13 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 	// populate permissions so the task can be used immediately
 	// if we cant populate here we shouldn't error.
 	ps, _ := s.maxPermissions(ctx, tx, task.OwnerID)
+	roleIDs, _ := s.taskRoleIDs(ctx, tx, task.OwnerID, task.OrganizationID)
 	task.Authorization = &influxdb.Authorization{
 		Status:      influxdb.Active,
 		ID:          influxdb.ID(1),
 		OrgID:       task.OrganizationID,
 		Permissions: ps,
+		RoleIDs:     roleIDs,
 	}
 	return task, nil
 }
@@ -678,6 +705,12 @@ func (s *Service) createTaskURM(ctx context.Context, tx Tx, t *influxdb.Task) er
 func (s *Service) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
 	var t *influxdb.Task
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if !upd.Options.IsZero() || upd.Flux != nil {
+			if err := s.validTaskNotDownsample(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
 		task, err := s.updateTask(ctx, tx, id, upd)
 		if err != nil {
 			return err
@@ -766,6 +799,10 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := s.validTaskNotDownsample(ctx, tx, id); err != nil {
+			return err
+		}
+
 		err := s.deleteTask(ctx, tx, id)
 		if err != nil {
 			return err
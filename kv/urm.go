@@ -165,6 +165,29 @@ func (s *Service) createUserResourceMapping(ctx context.Context, tx Tx, m *influ
 	return nil
 }
 
+// putUserResourceMapping overwrites an existing user resource mapping.
+func (s *Service) putUserResourceMapping(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return ErrUnprocessableMapping(err)
+	}
+
+	key, err := userResourceKey(m)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(urmBucket)
+	if err != nil {
+		return UnavailableURMServiceError(err)
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return UnavailableURMServiceError(err)
+	}
+	return nil
+}
+
 // This method creates the user/resource mappings for resources that belong to an organization.
 func (s *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -15,6 +15,15 @@ var (
 	userIndex  = []byte("userindexv1")
 )
 
+// userNameIndex indexes the users by name.
+var userNameIndex = NewIndex("users by name", userBucket, userIndex, func(v []byte) ([]byte, error) {
+	var u influxdb.User
+	if err := json.Unmarshal(v, &u); err != nil {
+		return nil, err
+	}
+	return userIndexKey(u.Name), nil
+})
+
 var _ influxdb.UserService = (*Service)(nil)
 var _ influxdb.UserOperationLogService = (*Service)(nil)
 
@@ -128,12 +137,7 @@ func (s *Service) FindUserByName(ctx context.Context, n string) (*influxdb.User,
 }
 
 func (s *Service) findUserByName(ctx context.Context, tx Tx, n string) (*influxdb.User, error) {
-	b, err := s.userIndexBucket(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	uid, err := b.Get(userIndexKey(n))
+	uid, err := userNameIndex.Lookup(tx, userIndexKey(n))
 	if err == ErrKeyNotFound {
 		return nil, ErrUserNotFound
 	}
@@ -260,12 +264,7 @@ func (s *Service) putUser(ctx context.Context, tx Tx, u *influxdb.User) error {
 		return InvalidUserIDError(err)
 	}
 
-	idx, err := s.userIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Put(userIndexKey(u.Name), encodedID); err != nil {
+	if err := userNameIndex.Insert(tx, userIndexKey(u.Name), encodedID); err != nil {
 		return ErrInternalUserServiceError(err)
 	}
 
@@ -369,12 +368,7 @@ func (s *Service) updateUser(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 func (s *Service) removeUserFromIndex(ctx context.Context, tx Tx, id influxdb.ID, name string) error {
 	// Users are indexed by name and so the user index must be pruned
 	// when name is modified.
-	idx, err := s.userIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Delete(userIndexKey(name)); err != nil {
+	if err := userNameIndex.Delete(tx, userIndexKey(name)); err != nil {
 		return ErrInternalUserServiceError(err)
 	}
 
@@ -403,12 +397,7 @@ func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return InvalidUserIDError(err)
 	}
 
-	idx, err := s.userIndexBucket(tx)
-	if err != nil {
-		return err
-	}
-
-	if err := idx.Delete(userIndexKey(u.Name)); err != nil {
+	if err := userNameIndex.Delete(tx, userIndexKey(u.Name)); err != nil {
 		return ErrInternalUserServiceError(err)
 	}
 
@@ -0,0 +1,34 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitError is the underlying error of the ETooManyRequests errors returned
+// when an organization exceeds one of its rate limits or quotas.
+type LimitError struct {
+	// RetryAfter is the duration after which the request can be retried.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("retry after %s", e.RetryAfter)
+}
+
+// RetryAfter returns the duration after which the request rejected with err
+// can be retried, if err was caused by a limit.
+func RetryAfter(err error) (time.Duration, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *LimitError:
+			return e.RetryAfter, true
+		case *Error:
+			err = e.Err
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
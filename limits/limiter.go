@@ -0,0 +1,331 @@
+// Package limits enforces the write rate limits and the query quotas of
+// organizations.
+package limits
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/limiter"
+	"golang.org/x/time/rate"
+)
+
+// queryRetryAfter is the duration after which a query rejected because its
+// organization has too many running queries can be retried.
+const queryRetryAfter = time.Second
+
+// orgIdleTimeout is the duration after which the limiter of an organization
+// without requests nor running queries is dropped. Its token buckets are full
+// by then, so a new limiter behaves the same.
+const orgIdleTimeout = time.Minute
+
+// Config are the limits applied to each organization, zero means no limit.
+type Config struct {
+	// WriteBytesPerSecond is the rate of the bytes of the write requests.
+	WriteBytesPerSecond int
+	// WritePointsPerSecond is the rate of the written points.
+	WritePointsPerSecond int
+	// QueryConcurrency is the number of queries allowed to run concurrently.
+	QueryConcurrency int
+	// QueryMemoryBytes is the maximum number of bytes a query is allowed to use.
+	QueryMemoryBytes int
+
+	// Orgs are the limits of the organizations whose limits differ from the
+	// limits of the config. Their own Orgs are ignored.
+	Orgs map[influxdb.ID]Config
+}
+
+// AddOrgLimit overrides a limit of an organization from s, of the form
+// orgID:limit=value where limit is one of write-bytes-per-second,
+// write-points-per-second, query-concurrency or query-memory-bytes. The other
+// limits of the organization are those of c.
+func (c *Config) AddOrgLimit(s string) error {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid organization limit %q, expected orgID:limit=value", s)
+	}
+	orgID, err := influxdb.IDFromString(parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid organization limit %q: %v", s, err)
+	}
+	kv := strings.SplitN(parts[1], "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid organization limit %q, expected orgID:limit=value", s)
+	}
+	v, err := strconv.Atoi(kv[1])
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid value of organization limit %q", s)
+	}
+
+	oc, ok := c.Orgs[*orgID]
+	if !ok {
+		oc = *c
+		oc.Orgs = nil
+	}
+	switch kv[0] {
+	case "write-bytes-per-second":
+		oc.WriteBytesPerSecond = v
+	case "write-points-per-second":
+		oc.WritePointsPerSecond = v
+	case "query-concurrency":
+		oc.QueryConcurrency = v
+	case "query-memory-bytes":
+		oc.QueryMemoryBytes = v
+	default:
+		return fmt.Errorf("unknown organization limit %q", kv[0])
+	}
+
+	if c.Orgs == nil {
+		c.Orgs = make(map[influxdb.ID]Config)
+	}
+	c.Orgs[*orgID] = oc
+	return nil
+}
+
+// limits returns the limits of the organization.
+func (c *Config) limits(orgID influxdb.ID) Config {
+	if oc, ok := c.Orgs[orgID]; ok {
+		return oc
+	}
+	return *c
+}
+
+// Limiter enforces the limits of its config on each organization. Writes are
+// limited with token buckets holding a second of their rate, and requests
+// exceeding the rate are rejected rather than delayed.
+type Limiter struct {
+	config Config
+	now    func() time.Time
+
+	mu   sync.Mutex
+	orgs map[influxdb.ID]*orgLimiter
+
+	// swept is when the idle organizations were last dropped, and the
+	// throttled counts are those of the dropped organizations.
+	swept            time.Time
+	writesThrottled  int64
+	queriesThrottled int64
+}
+
+// orgLimiter holds the limits and counters of an organization.
+type orgLimiter struct {
+	writeBytes  *rate.Limiter
+	writePoints *rate.Limiter
+	queries     limiter.Fixed
+
+	// used is when the organization was last used, guarded by the mutex of
+	// the limiter.
+	used time.Time
+
+	running          int64
+	writesThrottled  int64
+	queriesThrottled int64
+}
+
+// NewLimiter returns a limiter of the limits of c.
+func NewLimiter(c Config) *Limiter {
+	return &Limiter{
+		config: c,
+		now:    time.Now,
+		orgs:   make(map[influxdb.ID]*orgLimiter),
+	}
+}
+
+// org returns the limiter of the organization, creating it on first use.
+func (l *Limiter) org(orgID influxdb.ID) *orgLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.swept) >= orgIdleTimeout {
+		l.dropIdle(now)
+	}
+
+	o, ok := l.orgs[orgID]
+	if ok {
+		o.used = now
+		return o
+	}
+
+	c := l.config.limits(orgID)
+	o = &orgLimiter{used: now}
+	if n := c.WriteBytesPerSecond; n > 0 {
+		o.writeBytes = rate.NewLimiter(rate.Limit(n), n)
+	}
+	if n := c.WritePointsPerSecond; n > 0 {
+		o.writePoints = rate.NewLimiter(rate.Limit(n), n)
+	}
+	if n := c.QueryConcurrency; n > 0 {
+		o.queries = limiter.NewFixed(n)
+	}
+	l.orgs[orgID] = o
+	return o
+}
+
+// dropIdle drops the limiters of the organizations that have been idle for
+// orgIdleTimeout and have no running queries, keeping their throttled counts.
+// It must be called with the mutex held.
+func (l *Limiter) dropIdle(now time.Time) {
+	for orgID, o := range l.orgs {
+		if now.Sub(o.used) < orgIdleTimeout || atomic.LoadInt64(&o.running) > 0 {
+			continue
+		}
+		l.writesThrottled += atomic.LoadInt64(&o.writesThrottled)
+		l.queriesThrottled += atomic.LoadInt64(&o.queriesThrottled)
+		delete(l.orgs, orgID)
+	}
+	l.swept = now
+}
+
+// ReserveWriteBytes reserves n bytes of the write rate of the organization for
+// a write request, before it is parsed. It returns an ETooManyRequests error
+// if they exceed the rate, and otherwise a function giving the bytes back for
+// a request that is rejected once parsed.
+func (l *Limiter) ReserveWriteBytes(orgID influxdb.ID, n int) (func(), error) {
+	o := l.org(orgID)
+	res, err := l.reserveWrite(o, o.writeBytes, n, "bytes", l.now())
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if res != nil {
+				res.CancelAt(l.now())
+			}
+		})
+	}, nil
+}
+
+// AllowWritePoints returns an ETooManyRequests error if writing n points
+// exceeds the write rate of the points of the organization.
+func (l *Limiter) AllowWritePoints(orgID influxdb.ID, n int) error {
+	o := l.org(orgID)
+	_, err := l.reserveWrite(o, o.writePoints, n, "points", l.now())
+	return err
+}
+
+// reserveWrite reserves n tokens of r at now, the reservation is nil if r is
+// nil.
+func (l *Limiter) reserveWrite(o *orgLimiter, r *rate.Limiter, n int, unit string, now time.Time) (*rate.Reservation, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	// Writes larger than the burst could never be admitted, they are once
+	// the bucket is full.
+	if n > r.Burst() {
+		n = r.Burst()
+	}
+	res := r.ReserveN(now, n)
+	if d := res.DelayFrom(now); d > 0 {
+		res.CancelAt(now)
+		atomic.AddInt64(&o.writesThrottled, 1)
+		return nil, &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Op:   "limits/AllowWrite",
+			Msg:  fmt.Sprintf("organization exceeded its write limit of %d %s per second", r.Burst(), unit),
+			Err:  &influxdb.LimitError{RetryAfter: d},
+		}
+	}
+	return res, nil
+}
+
+// AcquireQuery admits a query of the organization if it has fewer running
+// queries than its concurrency quota. The returned function must be called
+// once the query is done.
+func (l *Limiter) AcquireQuery(orgID influxdb.ID) (func(), error) {
+	o := l.org(orgID)
+	if o.queries != nil && !o.queries.TryTake() {
+		atomic.AddInt64(&o.queriesThrottled, 1)
+		return nil, &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Op:   "limits/AcquireQuery",
+			Msg:  fmt.Sprintf("organization exceeded its limit of %d concurrent queries", o.queries.Capacity()),
+			Err:  &influxdb.LimitError{RetryAfter: queryRetryAfter},
+		}
+	}
+
+	atomic.AddInt64(&o.running, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt64(&o.running, -1)
+			if o.queries != nil {
+				o.queries.Release()
+			}
+		})
+	}, nil
+}
+
+// QueryMemoryBytes returns the maximum number of bytes a query of the
+// organization is allowed to use, 0 means no limit.
+func (l *Limiter) QueryMemoryBytes(orgID influxdb.ID) int64 {
+	return int64(l.config.limits(orgID).QueryMemoryBytes)
+}
+
+// GetUsage returns the number of throttled requests and running queries of
+// the organization in the filter, or of all organizations without one, with
+// the limits of the organization, or the default limits without one. Limits do
+// not apply to buckets, so there is no usage of a bucket. The range of the
+// filter is ignored since requests are counted from the start of the server,
+// or for an organization from when it was last active after being idle.
+func (l *Limiter) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	usage := make(map[influxdb.UsageMetric]*influxdb.Usage)
+	if filter.BucketID != nil {
+		return usage, nil
+	}
+
+	var writes, queries, running int64
+	l.mu.Lock()
+	if filter.OrgID == nil {
+		writes, queries = l.writesThrottled, l.queriesThrottled
+	}
+	for orgID, o := range l.orgs {
+		if filter.OrgID != nil && orgID != *filter.OrgID {
+			continue
+		}
+		writes += atomic.LoadInt64(&o.writesThrottled)
+		queries += atomic.LoadInt64(&o.queriesThrottled)
+		running += atomic.LoadInt64(&o.running)
+	}
+	l.mu.Unlock()
+
+	add := func(m influxdb.UsageMetric, v float64) {
+		usage[m] = &influxdb.Usage{
+			OrganizationID: filter.OrgID,
+			Type:           m,
+			Value:          v,
+		}
+	}
+	add(influxdb.UsageWriteRequestsThrottled, float64(writes))
+	add(influxdb.UsageQueryRequestsThrottled, float64(queries))
+	add(influxdb.UsageQueryConcurrency, float64(running))
+
+	c := l.config
+	if filter.OrgID != nil {
+		c = l.config.limits(*filter.OrgID)
+	}
+	limits := []struct {
+		metric influxdb.UsageMetric
+		value  int64
+	}{
+		{influxdb.UsageLimitWriteBytes, int64(c.WriteBytesPerSecond)},
+		{influxdb.UsageLimitWritePoints, int64(c.WritePointsPerSecond)},
+		{influxdb.UsageLimitQueryConcurrency, int64(c.QueryConcurrency)},
+		{influxdb.UsageLimitQueryMemoryBytes, int64(c.QueryMemoryBytes)},
+	}
+	for _, lim := range limits {
+		if lim.value > 0 {
+			add(lim.metric, float64(lim.value))
+		}
+	}
+	return usage, nil
+}
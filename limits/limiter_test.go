@@ -0,0 +1,235 @@
+package limits
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// write reserves the bytes of a write and then checks its points, giving the
+// bytes back if the points are rejected, as the write handlers do.
+func write(l *Limiter, orgID influxdb.ID, bytes, points int) error {
+	release, err := l.ReserveWriteBytes(orgID, bytes)
+	if err != nil {
+		return err
+	}
+	if err := l.AllowWritePoints(orgID, points); err != nil {
+		release()
+		return err
+	}
+	return nil
+}
+
+func TestLimiter_AllowWrite(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Config{WriteBytesPerSecond: 100, WritePointsPerSecond: 10})
+	l.now = func() time.Time { return now }
+
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		orgID      influxdb.ID
+		bytes      int
+		points     int
+		err        bool
+		retryAfter time.Duration
+	}{
+		{name: "within the limits", orgID: 1, bytes: 60, points: 5},
+		{name: "exceeds the bytes", orgID: 1, bytes: 60, err: true, retryAfter: 200 * time.Millisecond},
+		{name: "exceeds the points", orgID: 1, points: 6, err: true, retryAfter: 100 * time.Millisecond},
+		{name: "other organization", orgID: 2, bytes: 100, points: 10},
+		{name: "tokens are refilled", advance: 200 * time.Millisecond, orgID: 1, bytes: 60, points: 6},
+		{name: "larger than the burst", advance: time.Second, orgID: 1, bytes: 1000, points: 100},
+		{name: "bytes within the limit", advance: time.Second, orgID: 1, bytes: 50, points: 10},
+		{name: "points exceed the limit", orgID: 1, bytes: 50, points: 1, err: true, retryAfter: 100 * time.Millisecond},
+		{name: "rejected writes consume no bytes", advance: 100 * time.Millisecond, orgID: 1, bytes: 50, points: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			err := write(l, tt.orgID, tt.bytes, tt.points)
+			if !tt.err {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if code := influxdb.ErrorCode(err); code != influxdb.ETooManyRequests {
+				t.Fatalf("expected a too many requests error, got %v", err)
+			}
+			if d, ok := influxdb.RetryAfter(err); !ok || d != tt.retryAfter {
+				t.Errorf("expected to retry after %s, got %s", tt.retryAfter, d)
+			}
+		})
+	}
+}
+
+func TestLimiter_AcquireQuery(t *testing.T) {
+	l := NewLimiter(Config{QueryConcurrency: 1, QueryMemoryBytes: 1024})
+
+	release, err := l.AcquireQuery(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.AcquireQuery(1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected a too many requests error, got %v", err)
+	}
+	if _, err := l.AcquireQuery(2); err != nil {
+		t.Fatalf("expected the query of another organization to be admitted: %v", err)
+	}
+
+	// Releasing twice must not release the slot of another query.
+	release()
+	release()
+	if _, err := l.AcquireQuery(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.AcquireQuery(1); err == nil {
+		t.Fatal("expected the query to be rejected")
+	}
+
+	if n := l.QueryMemoryBytes(1); n != 1024 {
+		t.Errorf("expected a memory quota of 1024 bytes, got %d", n)
+	}
+}
+
+func TestLimiter_GetUsage(t *testing.T) {
+	l := NewLimiter(Config{WritePointsPerSecond: 1, QueryConcurrency: 1})
+	l.now = func() time.Time { return time.Unix(0, 0) }
+
+	_ = write(l, 1, 0, 1)
+	_ = write(l, 1, 0, 1)
+	_ = write(l, 2, 0, 1)
+	_ = write(l, 2, 0, 1)
+	_, _ = l.AcquireQuery(1)
+	_, _ = l.AcquireQuery(1)
+
+	orgID := influxdb.ID(1)
+	tests := []struct {
+		name   string
+		filter influxdb.UsageFilter
+		want   map[influxdb.UsageMetric]float64
+	}{
+		{
+			name:   "organization",
+			filter: influxdb.UsageFilter{OrgID: &orgID},
+			want: map[influxdb.UsageMetric]float64{
+				influxdb.UsageWriteRequestsThrottled: 1,
+				influxdb.UsageQueryRequestsThrottled: 1,
+				influxdb.UsageQueryConcurrency:       1,
+				influxdb.UsageLimitWritePoints:       1,
+				influxdb.UsageLimitQueryConcurrency:  1,
+			},
+		},
+		{
+			name: "all organizations",
+			want: map[influxdb.UsageMetric]float64{
+				influxdb.UsageWriteRequestsThrottled: 2,
+				influxdb.UsageQueryRequestsThrottled: 1,
+				influxdb.UsageQueryConcurrency:       1,
+				influxdb.UsageLimitWritePoints:       1,
+				influxdb.UsageLimitQueryConcurrency:  1,
+			},
+		},
+		{
+			name:   "bucket",
+			filter: influxdb.UsageFilter{OrgID: &orgID, BucketID: &orgID},
+			want:   map[influxdb.UsageMetric]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := l.GetUsage(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(usage) != len(tt.want) {
+				t.Errorf("expected %d metrics, got %d", len(tt.want), len(usage))
+			}
+			for m, want := range tt.want {
+				if u, ok := usage[m]; !ok || u.Value != want {
+					t.Errorf("expected %s to be %v, got %+v", m, want, u)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiter_DropIdle(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Config{WritePointsPerSecond: 1, QueryConcurrency: 1})
+	l.now = func() time.Time { return now }
+
+	_ = write(l, 1, 0, 1)
+	_ = write(l, 1, 0, 1)
+	if _, err := l.AcquireQuery(2); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(orgIdleTimeout)
+	_ = write(l, 3, 0, 1)
+
+	l.mu.Lock()
+	_, ok1 := l.orgs[1]
+	_, ok2 := l.orgs[2]
+	l.mu.Unlock()
+	if ok1 {
+		t.Error("expected the idle organization to be dropped")
+	}
+	if !ok2 {
+		t.Error("expected the organization with a running query to be kept")
+	}
+
+	// The throttled writes of the dropped organization are still counted.
+	usage, err := l.GetUsage(context.Background(), influxdb.UsageFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := usage[influxdb.UsageWriteRequestsThrottled]; u == nil || u.Value != 1 {
+		t.Errorf("expected 1 throttled write, got %+v", u)
+	}
+}
+
+func TestConfig_AddOrgLimit(t *testing.T) {
+	c := Config{WritePointsPerSecond: 1, QueryMemoryBytes: 1024}
+	for _, s := range []string{
+		"0000000000000001:write-points-per-second=5",
+		"0000000000000001:query-memory-bytes=0",
+	} {
+		if err := c.AddOrgLimit(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, s := range []string{
+		"0000000000000001",
+		"0000000000000001:write-points-per-second",
+		"0000000000000001:unknown=1",
+		"0000000000000001:query-concurrency=-1",
+		"bad:query-concurrency=1",
+	} {
+		if err := c.AddOrgLimit(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+
+	l := NewLimiter(c)
+	l.now = func() time.Time { return time.Unix(0, 0) }
+	if n := l.QueryMemoryBytes(1); n != 0 {
+		t.Errorf("expected no memory quota of the organization, got %d", n)
+	}
+	if n := l.QueryMemoryBytes(2); n != 1024 {
+		t.Errorf("expected the default memory quota, got %d", n)
+	}
+	if err := l.AllowWritePoints(1, 5); err != nil {
+		t.Errorf("expected the points to be allowed by the limit of the organization: %v", err)
+	}
+	if err := l.AllowWritePoints(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AllowWritePoints(2, 1); err == nil {
+		t.Error("expected the points to be rejected by the default limit")
+	}
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.AuditLogger = (*AuditLogger)(nil)
+
+// AuditLogger is a mock implementation of platform.AuditLogger that keeps the
+// events it records.
+type AuditLogger struct {
+	mu     sync.Mutex
+	Events []*platform.AuditEvent
+
+	LogAuditEventsFn func(ctx context.Context, events ...*platform.AuditEvent) error
+}
+
+// NewAuditLogger returns a mock AuditLogger that records events without error.
+func NewAuditLogger() *AuditLogger {
+	return &AuditLogger{
+		LogAuditEventsFn: func(context.Context, ...*platform.AuditEvent) error { return nil },
+	}
+}
+
+// LogAuditEvents keeps events and calls LogAuditEventsFn.
+func (l *AuditLogger) LogAuditEvents(ctx context.Context, events ...*platform.AuditEvent) error {
+	l.mu.Lock()
+	l.Events = append(l.Events, events...)
+	l.mu.Unlock()
+	return l.LogAuditEventsFn(ctx, events...)
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BackupService = (*BackupService)(nil)
+var _ platform.KVBackupService = (*KVBackupService)(nil)
+var _ platform.RestoreService = (*RestoreService)(nil)
+
+// BackupService is a mock backup service.
+type BackupService struct {
+	CreateBackupF       func(ctx context.Context, filter platform.BackupFilter) (*platform.Backup, error)
+	FetchBackupFileF    func(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+	DeleteBackupF       func(ctx context.Context, backupID int) error
+	InternalBackupPathF func(backupID int) string
+}
+
+// NewBackupService returns a mock BackupService where its methods will return
+// zero values.
+func NewBackupService() *BackupService {
+	return &BackupService{
+		CreateBackupF: func(ctx context.Context, filter platform.BackupFilter) (*platform.Backup, error) {
+			return &platform.Backup{}, nil
+		},
+		FetchBackupFileF: func(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+			return nil
+		},
+		DeleteBackupF: func(ctx context.Context, backupID int) error {
+			return nil
+		},
+		InternalBackupPathF: func(backupID int) string {
+			return ""
+		},
+	}
+}
+
+// CreateBackup calls CreateBackupF.
+func (s *BackupService) CreateBackup(ctx context.Context, filter platform.BackupFilter) (*platform.Backup, error) {
+	return s.CreateBackupF(ctx, filter)
+}
+
+// FetchBackupFile calls FetchBackupFileF.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	return s.FetchBackupFileF(ctx, backupID, backupFile, w)
+}
+
+// DeleteBackup calls DeleteBackupF.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	return s.DeleteBackupF(ctx, backupID)
+}
+
+// InternalBackupPath calls InternalBackupPathF.
+func (s *BackupService) InternalBackupPath(backupID int) string {
+	return s.InternalBackupPathF(backupID)
+}
+
+// KVBackupService is a mock kv backup service.
+type KVBackupService struct {
+	BackupF func(ctx context.Context, w io.Writer) error
+}
+
+// NewKVBackupService returns a mock KVBackupService where its methods will
+// return zero values.
+func NewKVBackupService() *KVBackupService {
+	return &KVBackupService{
+		BackupF: func(ctx context.Context, w io.Writer) error {
+			return nil
+		},
+	}
+}
+
+// Backup calls BackupF.
+func (s *KVBackupService) Backup(ctx context.Context, w io.Writer) error {
+	return s.BackupF(ctx, w)
+}
+
+// RestoreService is a mock restore service.
+type RestoreService struct {
+	RestoreBucketF func(ctx context.Context, orgID, bucketID platform.ID, r io.Reader) error
+}
+
+// NewRestoreService returns a mock RestoreService where its methods will
+// return zero values.
+func NewRestoreService() *RestoreService {
+	return &RestoreService{
+		RestoreBucketF: func(ctx context.Context, orgID, bucketID platform.ID, r io.Reader) error {
+			return nil
+		},
+	}
+}
+
+// RestoreBucket calls RestoreBucketF.
+func (s *RestoreService) RestoreBucket(ctx context.Context, orgID, bucketID platform.ID, r io.Reader) error {
+	return s.RestoreBucketF(ctx, orgID, bucketID, r)
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BucketSchemaService = (*BucketSchemaService)(nil)
+var _ platform.MeasurementSchemaReader = (*MeasurementSchemaReader)(nil)
+
+// BucketSchemaService is a mock implementation of a platform.BucketSchemaService.
+type BucketSchemaService struct {
+	FindMeasurementSchemaByIDF func(ctx context.Context, bucketID, id platform.ID) (*platform.MeasurementSchema, error)
+	FindMeasurementSchemasF    func(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error)
+	CreateMeasurementSchemaF   func(ctx context.Context, s *platform.MeasurementSchema) error
+	UpdateMeasurementSchemaF   func(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error)
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where its methods
+// will return zero values.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindMeasurementSchemaByIDF: func(ctx context.Context, bucketID, id platform.ID) (*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		FindMeasurementSchemasF: func(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		CreateMeasurementSchemaF: func(ctx context.Context, s *platform.MeasurementSchema) error {
+			return nil
+		},
+		UpdateMeasurementSchemaF: func(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema by ID.
+func (s *BucketSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id platform.ID) (*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemaByIDF(ctx, bucketID, id)
+}
+
+// FindMeasurementSchemas returns the measurement schemas that match filter.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemasF(ctx, filter)
+}
+
+// CreateMeasurementSchema creates a new measurement schema.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, ms *platform.MeasurementSchema) error {
+	return s.CreateMeasurementSchemaF(ctx, ms)
+}
+
+// UpdateMeasurementSchema adds tags and fields to a measurement schema.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+	return s.UpdateMeasurementSchemaF(ctx, bucketID, id, upd)
+}
+
+// MeasurementSchemaReader is a mock implementation of a platform.MeasurementSchemaReader.
+type MeasurementSchemaReader struct {
+	ReadMeasurementSchemasF func(ctx context.Context, orgID, bucketID platform.ID) ([]*platform.MeasurementSchema, error)
+}
+
+// ReadMeasurementSchemas calls ReadMeasurementSchemasF.
+func (s *MeasurementSchemaReader) ReadMeasurementSchemas(ctx context.Context, orgID, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+	return s.ReadMeasurementSchemasF(ctx, orgID, bucketID)
+}
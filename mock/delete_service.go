@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DeleteService = (*DeleteService)(nil)
+
+// DeleteService is a mock delete service.
+type DeleteService struct {
+	DeleteBucketRangePredicateF func(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error
+}
+
+// NewDeleteService returns a mock DeleteService where its methods will return
+// zero values.
+func NewDeleteService() *DeleteService {
+	return &DeleteService{
+		DeleteBucketRangePredicateF: func(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error {
+			return nil
+		},
+	}
+}
+
+// DeleteBucketRangePredicate calls DeleteBucketRangePredicateF.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error {
+	return s.DeleteBucketRangePredicateF(ctx, orgID, bucketID, min, max, pred)
+}
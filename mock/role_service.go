@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RoleService = (*RoleService)(nil)
+
+// RoleService is a mock implementation of platform.RoleService.
+type RoleService struct {
+	FindRoleByIDFn     func(context.Context, platform.ID) (*platform.Role, error)
+	FindRolesFn        func(context.Context, platform.RoleFilter, ...platform.FindOptions) ([]*platform.Role, int, error)
+	CreateRoleFn       func(context.Context, *platform.Role) error
+	UpdateRoleFn       func(context.Context, platform.ID, platform.RoleUpdate) (*platform.Role, error)
+	DeleteRoleFn       func(context.Context, platform.ID) error
+	AddRoleMemberFn    func(context.Context, platform.ID, platform.ID) error
+	RemoveRoleMemberFn func(context.Context, platform.ID, platform.ID) error
+}
+
+// NewRoleService returns a mock RoleService where its methods will return
+// zero values.
+func NewRoleService() *RoleService {
+	return &RoleService{
+		FindRoleByIDFn: func(context.Context, platform.ID) (*platform.Role, error) { return nil, nil },
+		FindRolesFn: func(context.Context, platform.RoleFilter, ...platform.FindOptions) ([]*platform.Role, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleFn:       func(context.Context, *platform.Role) error { return nil },
+		UpdateRoleFn:       func(context.Context, platform.ID, platform.RoleUpdate) (*platform.Role, error) { return nil, nil },
+		DeleteRoleFn:       func(context.Context, platform.ID) error { return nil },
+		AddRoleMemberFn:    func(context.Context, platform.ID, platform.ID) error { return nil },
+		RemoveRoleMemberFn: func(context.Context, platform.ID, platform.ID) error { return nil },
+	}
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	return s.FindRoleByIDFn(ctx, id)
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *RoleService) FindRoles(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error) {
+	return s.FindRolesFn(ctx, filter, opt...)
+}
+
+// CreateRole creates a new role.
+func (s *RoleService) CreateRole(ctx context.Context, r *platform.Role) error {
+	return s.CreateRoleFn(ctx, r)
+}
+
+// UpdateRole updates a single role with changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	return s.UpdateRoleFn(ctx, id, upd)
+}
+
+// DeleteRole removes a role by ID.
+func (s *RoleService) DeleteRole(ctx context.Context, id platform.ID) error {
+	return s.DeleteRoleFn(ctx, id)
+}
+
+// AddRoleMember grants a role to a user.
+func (s *RoleService) AddRoleMember(ctx context.Context, roleID, userID platform.ID) error {
+	return s.AddRoleMemberFn(ctx, roleID, userID)
+}
+
+// RemoveRoleMember revokes a role from a user.
+func (s *RoleService) RemoveRoleMember(ctx context.Context, roleID, userID platform.ID) error {
+	return s.RemoveRoleMemberFn(ctx, roleID, userID)
+}
@@ -43,3 +43,21 @@ func (s *ScraperTargetStoreService) RemoveTarget(ctx context.Context, id platfor
 func (s *ScraperTargetStoreService) UpdateTarget(ctx context.Context, t *platform.ScraperTarget, userID platform.ID) (*platform.ScraperTarget, error) {
 	return s.UpdateTargetF(ctx, t, userID)
 }
+
+var _ platform.ScraperTargetStatusService = &ScraperTargetStatusService{}
+
+// ScraperTargetStatusService is a mock implementation of a platform.ScraperTargetStatusService.
+type ScraperTargetStatusService struct {
+	GetTargetStatusF func(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error)
+	PutTargetStatusF func(ctx context.Context, status *platform.ScraperTargetStatus) error
+}
+
+// GetTargetStatus returns the status of the last scrape of a target.
+func (s *ScraperTargetStatusService) GetTargetStatus(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error) {
+	return s.GetTargetStatusF(ctx, id)
+}
+
+// PutTargetStatus records the status of the last scrape of a target.
+func (s *ScraperTargetStatusService) PutTargetStatus(ctx context.Context, status *platform.ScraperTargetStatus) error {
+	return s.PutTargetStatusF(ctx, status)
+}
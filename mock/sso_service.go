@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// SSOService is a mock implementation of platform.SSOService.
+type SSOService struct {
+	AuthCodeURLFn func(ctx context.Context, state, nonce string) (string, error)
+	LoginFn       func(ctx context.Context, code, nonce string) (*platform.Session, error)
+}
+
+// NewSSOService returns a mock SSOService where its methods will return
+// errors.
+func NewSSOService() *SSOService {
+	return &SSOService{
+		AuthCodeURLFn: func(context.Context, string, string) (string, error) { return "", fmt.Errorf("mock sso") },
+		LoginFn:       func(context.Context, string, string) (*platform.Session, error) { return nil, fmt.Errorf("mock sso") },
+	}
+}
+
+// AuthCodeURL returns the URL of the login page of the identity provider.
+func (s *SSOService) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	return s.AuthCodeURLFn(ctx, state, nonce)
+}
+
+// Login exchanges code for a session of the user.
+func (s *SSOService) Login(ctx context.Context, code, nonce string) (*platform.Session, error) {
+	return s.LoginFn(ctx, code, nonce)
+}
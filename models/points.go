@@ -379,15 +379,71 @@ func ParsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 
 func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) (_ []Point, err error) {
 	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	var failed []string
+	walkLines(buf, func(_ int, block []byte) {
+		points, err = parsePointsAppend(points, block, mm, defaultTime, precision, rewrite)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block), err))
+		}
+	})
+	if len(failed) > 0 {
+		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+
+	return points, nil
+}
+
+// LineError is a line of line protocol that could not be parsed.
+type LineError struct {
+	Line int    // Line is the 1-based line number of the line.
+	Text string // Text is the line without leading white space and the newline.
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("unable to parse line %d '%s': %v", e.Line, e.Text, e.Err)
+}
+
+// ParseLinesWithPrecision is similar to ParsePointsWithPrecision, but every
+// line is parsed on its own: a line that cannot be parsed does not contribute
+// any point and is returned as a LineError, while the other lines are still
+// parsed. lines holds the 1-based line number of each of the points.
+func ParseLinesWithPrecision(buf []byte, mm []byte, defaultTime time.Time, precision string) (points []Point, lines []int, errs []*LineError) {
+	points = make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	walkLines(buf, func(line int, block []byte) {
+		n := len(points)
+		var err error
+		points, err = parsePointsAppend(points, block, mm, defaultTime, precision, true)
+		if err != nil {
+			points = points[:n]
+			errs = append(errs, &LineError{Line: line, Text: string(block), Err: err})
+			return
+		}
+		for i := n; i < len(points); i++ {
+			lines = append(lines, line)
+		}
+	})
+	return points, lines, errs
+}
+
+// walkLines calls fn with every line of line protocol in buf and its 1-based
+// line number. Empty lines and comments are skipped, and leading white space
+// and the newline are stripped.
+func walkLines(buf []byte, fn func(line int, block []byte)) {
 	var (
-		pos    int
-		block  []byte
-		failed []string
+		pos   int
+		block []byte
+		line  = 1
 	)
 	for pos < len(buf) {
 		pos, block = scanLine(buf, pos)
 		pos++
 
+		// string fields may contain newlines, so a block can span several lines.
+		n := line
+		line += bytes.Count(block, []byte{'\n'}) + 1
+
 		if len(block) == 0 {
 			continue
 		}
@@ -409,23 +465,15 @@ func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 			block = block[:len(block)-1]
 		}
 
-		points, err = parsePointsAppend(points, block[start:], mm, defaultTime, precision, rewrite)
-		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
-		}
+		fn(n, block[start:])
 	}
-	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
-	}
-
-	return points, nil
 }
 
 func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) ([]Point, error) {
 	// scan the first block which is measurement[,tag1=value1,tag2=value=2...]
 	pos, key, err := scanKey(buf, 0)
 	if err != nil {
-		return nil, err
+		return points, err
 	}
 
 	// measurement name is required
@@ -548,6 +596,10 @@ func GetPrecisionMultiplier(precision string) int64 {
 		d = time.Millisecond
 	case "s":
 		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
 	}
 	return int64(d)
 }
@@ -1754,6 +1806,10 @@ func (p *point) SetPrecision(precision string) {
 		p.SetTime(p.Time().Truncate(time.Millisecond))
 	case "s":
 		p.SetTime(p.Time().Truncate(time.Second))
+	case "m":
+		p.SetTime(p.Time().Truncate(time.Minute))
+	case "h":
+		p.SetTime(p.Time().Truncate(time.Hour))
 	}
 }
 
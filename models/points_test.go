@@ -2012,6 +2012,18 @@ func TestParsePointsWithPrecision(t *testing.T) {
 			precision: "s",
 			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730096000000000",
 		},
+		{
+			name:      "minute",
+			line:      `cpu,host=serverA,region=us-east value=1.0 15778834`,
+			precision: "m",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730040000000000",
+		},
+		{
+			name:      "hour",
+			line:      `cpu,host=serverA,region=us-east value=1.0 262980`,
+			precision: "h",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946728000000000000",
+		},
 	}
 	for _, test := range tests {
 		pts, err := models.ParsePointsWithPrecision([]byte(test.line), []byte("mm"), time.Now().UTC(), test.precision)
@@ -2082,6 +2094,42 @@ func TestParsePointsWithPrecisionNoTime(t *testing.T) {
 	}
 }
 
+func TestParseLinesWithPrecision(t *testing.T) {
+	batch := `cpu value=1 1
+# a comment
+cpu value=
+
+cpu,host=a value=2,msg="multi
+line" 2
+	 cpu value=3i 3
+,bad value=1
+`
+	pts, lines, errs := models.ParseLinesWithPrecision([]byte(batch), []byte("mm"), time.Now().UTC(), "n")
+
+	if got, exp := len(pts), 4; got != exp {
+		t.Fatalf("ParseLinesWithPrecision() len mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := lines, []int{1, 5, 5, 7}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("ParseLinesWithPrecision() lines mismatch: got %v, exp %v", got, exp)
+	}
+
+	if got, exp := len(errs), 2; got != exp {
+		t.Fatalf("ParseLinesWithPrecision() errors mismatch: got %v, exp %v", errs, exp)
+	}
+	if got, exp := errs[0].Line, 3; got != exp {
+		t.Errorf("ParseLinesWithPrecision() error line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := errs[0].Text, "cpu value="; got != exp {
+		t.Errorf("ParseLinesWithPrecision() error text mismatch: got %q, exp %q", got, exp)
+	}
+	if got, exp := errs[1].Line, 8; got != exp {
+		t.Errorf("ParseLinesWithPrecision() error line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := errs[1].Text, ",bad value=1"; got != exp {
+		t.Errorf("ParseLinesWithPrecision() error text mismatch: got %q, exp %q", got, exp)
+	}
+}
+
 func TestParsePointsWithPrecisionComments(t *testing.T) {
 	tests := []struct {
 		name      string
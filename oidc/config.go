@@ -0,0 +1,156 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	// DefaultUsernameClaim is the claim of the ID token used as user name when
+	// none is configured.
+	DefaultUsernameClaim = "email"
+	// DefaultGroupsClaim is the claim of the ID token that lists the groups of
+	// the user when none is configured.
+	DefaultGroupsClaim = "groups"
+)
+
+// DefaultScopes are the scopes requested when none are configured.
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// Config configures the login flow with an OpenID Connect provider.
+type Config struct {
+	// Issuer is the URL of the provider, its metadata is discovered from
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of the callback the provider redirects to.
+	RedirectURL string
+	Scopes      []string
+
+	// UsernameClaim is the claim of the ID token used as name of the user.
+	UsernameClaim string
+	// GroupsClaim is the claim of the ID token that lists the groups of the
+	// user, either as a string or as a list of strings.
+	GroupsClaim string
+	// GroupMappings grant the members of groups access to organizations. The
+	// membership of a user to an organization of a mapping is managed by the
+	// provider: it is added and removed on login to match the groups of the
+	// user.
+	GroupMappings []GroupMapping
+
+	// MapExistingUsers allows users to sign in as an existing user of the same
+	// name that was not created by the provider.
+	MapExistingUsers bool
+}
+
+// Valid returns an error if the config is missing required fields.
+func (c Config) Valid() error {
+	if c.Issuer == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "oidc issuer is required",
+		}
+	}
+	if c.ClientID == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "oidc client id is required",
+		}
+	}
+	if c.RedirectURL == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "oidc redirect url is required",
+		}
+	}
+	for _, m := range c.GroupMappings {
+		if err := m.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Config) scopes() []string {
+	if len(c.Scopes) == 0 {
+		return DefaultScopes
+	}
+	return c.Scopes
+}
+
+func (c Config) usernameClaim() string {
+	if c.UsernameClaim == "" {
+		return DefaultUsernameClaim
+	}
+	return c.UsernameClaim
+}
+
+func (c Config) groupsClaim() string {
+	if c.GroupsClaim == "" {
+		return DefaultGroupsClaim
+	}
+	return c.GroupsClaim
+}
+
+// GroupMapping grants the members of a group of the provider access to an
+// organization.
+type GroupMapping struct {
+	Group    string
+	OrgID    influxdb.ID
+	UserType influxdb.UserType
+}
+
+// Valid returns an error if the mapping is invalid.
+func (m GroupMapping) Valid() error {
+	if m.Group == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "oidc group mapping requires a group",
+		}
+	}
+	if !m.OrgID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("oidc group mapping of %q has an invalid org id", m.Group),
+		}
+	}
+	if err := m.UserType.Valid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseGroupMapping parses a mapping of the form group=orgID[:owner|member].
+// Users are members of the organization unless a user type is given.
+func ParseGroupMapping(s string) (GroupMapping, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 0 {
+		return GroupMapping{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("oidc group mapping %q must be of the form group=orgID[:owner|member]", s),
+		}
+	}
+
+	m := GroupMapping{
+		Group:    s[:i],
+		UserType: influxdb.Member,
+	}
+	org := s[i+1:]
+	if j := strings.Index(org, ":"); j >= 0 {
+		org, m.UserType = org[:j], influxdb.UserType(org[j+1:])
+	}
+	if err := m.OrgID.DecodeFromString(org); err != nil {
+		return GroupMapping{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("oidc group mapping %q has an invalid org id", s),
+			Err:  err,
+		}
+	}
+	if err := m.Valid(); err != nil {
+		return GroupMapping{}, err
+	}
+	return m, nil
+}
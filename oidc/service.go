@@ -0,0 +1,272 @@
+// Package oidc implements single sign-on with an OpenID Connect provider.
+//
+// Users sign in with the authorization code flow. The ID token returned by the
+// provider names the user and lists its groups: the user is created on first
+// sign in and its membership of the organizations of the group mappings is
+// synchronized with its groups on every sign in.
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+var _ influxdb.SSOService = (*Service)(nil)
+
+// Identity is the identity of a user asserted by the provider.
+type Identity struct {
+	Subject  string
+	Username string
+	Groups   []string
+}
+
+// Service implements influxdb.SSOService with an OpenID Connect provider.
+type Service struct {
+	Logger *zap.Logger
+
+	UserService                influxdb.UserService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	SessionService             influxdb.SessionService
+
+	config   Config
+	client   *http.Client
+	provider *provider
+}
+
+// NewService returns a Service that signs users in with the provider of c.
+// Requests to the provider are made with client, http.DefaultClient if nil.
+func NewService(c Config, client *http.Client, us influxdb.UserService, urms influxdb.UserResourceMappingService, ss influxdb.SessionService) (*Service, error) {
+	if err := c.Valid(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Service{
+		Logger:                     zap.NewNop(),
+		UserService:                us,
+		UserResourceMappingService: urms,
+		SessionService:             ss,
+		config:                     c,
+		client:                     client,
+		provider: &provider{
+			issuer: c.Issuer,
+			client: client,
+		},
+	}, nil
+}
+
+func (s *Service) oauth2Config(m *metadata) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		RedirectURL:  s.config.RedirectURL,
+		Scopes:       s.config.scopes(),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  m.AuthorizationEndpoint,
+			TokenURL: m.TokenEndpoint,
+		},
+	}
+}
+
+// AuthCodeURL returns the URL of the login page of the provider.
+func (s *Service) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	m, err := s.provider.metadata(ctx)
+	if err != nil {
+		return "", &influxdb.Error{
+			Op:  influxdb.OpSSOAuthCodeURL,
+			Err: err,
+		}
+	}
+	return s.oauth2Config(m).AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// Login exchanges code for the identity of the user, provisions the user and
+// its organizations and returns a new session for the user.
+func (s *Service) Login(ctx context.Context, code, nonce string) (*influxdb.Session, error) {
+	id, err := s.Exchange(ctx, code, nonce)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSSOLogin,
+			Err: err,
+		}
+	}
+
+	u, err := s.user(ctx, id)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSSOLogin,
+			Err: err,
+		}
+	}
+
+	if err := s.syncOrgs(ctx, u.ID, id.Groups); err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSSOLogin,
+			Err: err,
+		}
+	}
+
+	return s.SessionService.CreateSession(ctx, u.Name)
+}
+
+// Exchange exchanges code for the ID token of the user and returns the
+// identity it asserts.
+func (s *Service) Exchange(ctx context.Context, code, nonce string) (*Identity, error) {
+	m, err := s.provider.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := s.oauth2Config(m).Exchange(context.WithValue(ctx, oauth2.HTTPClient, s.client), code)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "oidc code exchange failed",
+			Err:  err,
+		}
+	}
+
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "oidc provider did not return an id token",
+		}
+	}
+
+	claims, err := s.provider.verify(ctx, raw, s.config.ClientID, nonce)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "invalid oidc id token",
+			Err:  err,
+		}
+	}
+
+	return s.identity(claims)
+}
+
+func (s *Service) identity(claims jwt.MapClaims) (*Identity, error) {
+	id := &Identity{}
+	id.Subject, _ = claims["sub"].(string)
+	if id.Subject == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "oidc id token has no subject",
+		}
+	}
+
+	id.Username, _ = claims[s.config.usernameClaim()].(string)
+	if id.Username == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("oidc id token has no %q claim", s.config.usernameClaim()),
+		}
+	}
+
+	switch groups := claims[s.config.groupsClaim()].(type) {
+	case string:
+		id.Groups = []string{groups}
+	case []interface{}:
+		for _, g := range groups {
+			if g, ok := g.(string); ok {
+				id.Groups = append(id.Groups, g)
+			}
+		}
+	}
+	return id, nil
+}
+
+// user returns the user of id, creating it if it does not exist. The OAuthID
+// of users created by the provider is the subject of the identity.
+func (s *Service) user(ctx context.Context, id *Identity) (*influxdb.User, error) {
+	u, err := s.UserService.FindUser(ctx, influxdb.UserFilter{Name: &id.Username})
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return nil, err
+	}
+
+	if u == nil || err != nil {
+		u = &influxdb.User{
+			Name:    id.Username,
+			OAuthID: id.Subject,
+		}
+		if err := s.UserService.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+		s.Logger.Info("Created user signed in with oidc", zap.String("user", u.Name), zap.String("subject", id.Subject))
+		return u, nil
+	}
+
+	if u.OAuthID == id.Subject || (u.OAuthID == "" && s.config.MapExistingUsers) {
+		return u, nil
+	}
+	return nil, &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Msg:  fmt.Sprintf("user %q is not managed by the oidc provider", id.Username),
+	}
+}
+
+// syncOrgs adds and removes the memberships of the user to the organizations
+// of the group mappings to match groups. Owners of an organization through any
+// group are owners, others are members.
+func (s *Service) syncOrgs(ctx context.Context, userID influxdb.ID, groups []string) error {
+	if len(s.config.GroupMappings) == 0 {
+		return nil
+	}
+
+	in := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		in[g] = true
+	}
+
+	managed := make(map[influxdb.ID]bool)
+	want := make(map[influxdb.ID]influxdb.UserType)
+	for _, m := range s.config.GroupMappings {
+		managed[m.OrgID] = true
+		if !in[m.Group] || want[m.OrgID] == influxdb.Owner {
+			continue
+		}
+		want[m.OrgID] = m.UserType
+	}
+
+	urms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range urms {
+		if !managed[m.ResourceID] {
+			continue
+		}
+		if want[m.ResourceID] == m.UserType {
+			delete(want, m.ResourceID)
+			continue
+		}
+		if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, m.ResourceID, userID); err != nil {
+			return err
+		}
+	}
+
+	for orgID, typ := range want {
+		if err := s.UserResourceMappingService.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			UserID:       userID,
+			UserType:     typ,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   orgID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package oidc_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
+)
+
+func newService(t *testing.T, p *oidctest.Provider) (*oidc.Service, *kv.Service) {
+	t.Helper()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := oidc.NewService(oidc.Config{
+		Issuer:       p.Issuer(),
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  "http://localhost:9999/api/v2/signin/sso/callback",
+	}, p.Client(), svc, svc, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, svc
+}
+
+func TestService_AuthCodeURL(t *testing.T) {
+	p := oidctest.NewProvider("influxdb", "secret")
+	defer p.Close()
+
+	s, _ := newService(t, p)
+
+	raw, err := s.AuthCodeURL(context.Background(), "state", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := u.Scheme+"://"+u.Host+u.Path, p.URL+"/authorize"; got != exp {
+		t.Errorf("unexpected authorization endpoint: got %q, exp %q", got, exp)
+	}
+	for k, exp := range map[string]string{
+		"client_id":     "influxdb",
+		"response_type": "code",
+		"state":         "state",
+		"nonce":         "nonce",
+		"scope":         "openid profile email",
+		"redirect_uri":  "http://localhost:9999/api/v2/signin/sso/callback",
+	} {
+		if got := u.Query().Get(k); got != exp {
+			t.Errorf("unexpected %s: got %q, exp %q", k, got, exp)
+		}
+	}
+}
+
+func TestService_Login(t *testing.T) {
+	p := oidctest.NewProvider("influxdb", "secret")
+	defer p.Close()
+
+	ctx := context.Background()
+	s, svc := newService(t, p)
+
+	sess, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane@example.com"), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := svc.FindUserByID(ctx, sess.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jane@example.com" || u.OAuthID != "1234" {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+	if _, err := svc.FindSession(ctx, sess.Key); err != nil {
+		t.Fatalf("session not found: %v", err)
+	}
+
+	// Signing in again maps to the same user.
+	again, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane@example.com"), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.UserID != sess.UserID {
+		t.Fatalf("unexpected user id: got %v, exp %v", again.UserID, sess.UserID)
+	}
+
+	// A user of the same name created by another subject is not mapped.
+	_, err = s.Login(ctx, p.Authorize("nonce", "5678", "jane@example.com"), "nonce")
+	if code := influxdb.ErrorCode(err); code != influxdb.EForbidden {
+		t.Fatalf("unexpected error code: got %q, exp %q (%v)", code, influxdb.EForbidden, err)
+	}
+}
+
+func TestService_Login_ExistingUser(t *testing.T) {
+	p := oidctest.NewProvider("influxdb", "secret")
+	defer p.Close()
+
+	ctx := context.Background()
+	s, svc := newService(t, p)
+
+	u := &influxdb.User{Name: "jane"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane"), "nonce")
+	if code := influxdb.ErrorCode(err); code != influxdb.EForbidden {
+		t.Fatalf("unexpected error code: got %q, exp %q (%v)", code, influxdb.EForbidden, err)
+	}
+
+	s, err = oidc.NewService(oidc.Config{
+		Issuer:           p.Issuer(),
+		ClientID:         p.ClientID,
+		ClientSecret:     p.ClientSecret,
+		RedirectURL:      "http://localhost:9999/api/v2/signin/sso/callback",
+		MapExistingUsers: true,
+	}, p.Client(), svc, svc, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane"), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.UserID != u.ID {
+		t.Fatalf("unexpected user id: got %v, exp %v", sess.UserID, u.ID)
+	}
+}
+
+func TestService_Login_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		nonce   string
+		tokenFn func(jwt.MapClaims)
+	}{
+		{
+			name:  "nonce mismatch",
+			nonce: "other",
+		},
+		{
+			name:    "issuer mismatch",
+			tokenFn: func(c jwt.MapClaims) { c["iss"] = "https://example.com" },
+		},
+		{
+			name:    "audience mismatch",
+			tokenFn: func(c jwt.MapClaims) { c["aud"] = []string{"other"} },
+		},
+		{
+			name:    "expired",
+			tokenFn: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		},
+		{
+			name:    "missing expiry",
+			tokenFn: func(c jwt.MapClaims) { delete(c, "exp") },
+		},
+		{
+			name:    "missing username",
+			tokenFn: func(c jwt.MapClaims) { delete(c, "email") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := oidctest.NewProvider("influxdb", "secret")
+			defer p.Close()
+			p.TokenFn = tt.tokenFn
+
+			s, _ := newService(t, p)
+
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce"
+			}
+			_, err := s.Login(context.Background(), p.Authorize("nonce", "1234", "jane"), nonce)
+			if code := influxdb.ErrorCode(err); code != influxdb.EUnauthorized {
+				t.Fatalf("unexpected error code: got %q, exp %q (%v)", code, influxdb.EUnauthorized, err)
+			}
+		})
+	}
+}
+
+func TestService_Login_GroupMappings(t *testing.T) {
+	p := oidctest.NewProvider("influxdb", "secret")
+	defer p.Close()
+
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var orgs []*influxdb.Organization
+	for _, name := range []string{"eng", "ops", "other"} {
+		o := &influxdb.Organization{Name: name}
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+		orgs = append(orgs, o)
+	}
+	eng, ops, other := orgs[0].ID, orgs[1].ID, orgs[2].ID
+
+	s, err := oidc.NewService(oidc.Config{
+		Issuer:       p.Issuer(),
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  "http://localhost:9999/api/v2/signin/sso/callback",
+		GroupMappings: []oidc.GroupMapping{
+			{Group: "developers", OrgID: eng, UserType: influxdb.Member},
+			{Group: "leads", OrgID: eng, UserType: influxdb.Owner},
+			{Group: "sre", OrgID: ops, UserType: influxdb.Member},
+		},
+	}, p.Client(), svc, svc, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memberships := func(userID influxdb.ID) []string {
+		urms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+			UserID:       userID,
+			ResourceType: influxdb.OrgsResourceType,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ms []string
+		for _, m := range urms {
+			ms = append(ms, m.ResourceID.String()+":"+string(m.UserType))
+		}
+		sort.Strings(ms)
+		return ms
+	}
+	expect := func(userID influxdb.ID, exp ...string) {
+		t.Helper()
+		sort.Strings(exp)
+		if got := memberships(userID); fmt.Sprint(got) != fmt.Sprint(exp) {
+			t.Fatalf("unexpected memberships: got %v, exp %v", got, exp)
+		}
+	}
+
+	sess, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane", "developers", "leads", "unmapped"), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(sess.UserID, eng.String()+":owner")
+
+	// Memberships of organizations that are not mapped are left alone.
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       sess.UserID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   other,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane", "developers", "sre"), "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	expect(sess.UserID, eng.String()+":member", ops.String()+":member", other.String()+":member")
+
+	if _, err := s.Login(ctx, p.Authorize("nonce", "1234", "jane"), "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	expect(sess.UserID, other.String()+":member")
+}
+
+func TestParseGroupMapping(t *testing.T) {
+	tests := []struct {
+		s       string
+		exp     oidc.GroupMapping
+		wantErr bool
+	}{
+		{
+			s:   "developers=020f755c3c082000",
+			exp: oidc.GroupMapping{Group: "developers", OrgID: influxdb.ID(0x020f755c3c082000), UserType: influxdb.Member},
+		},
+		{
+			s:   "cn=admins,ou=groups=020f755c3c082000:owner",
+			exp: oidc.GroupMapping{Group: "cn=admins,ou=groups", OrgID: influxdb.ID(0x020f755c3c082000), UserType: influxdb.Owner},
+		},
+		{s: "developers", wantErr: true},
+		{s: "=020f755c3c082000", wantErr: true},
+		{s: "developers=nothex", wantErr: true},
+		{s: "developers=020f755c3c082000:admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := oidc.ParseGroupMapping(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.exp {
+				t.Fatalf("unexpected mapping: got %+v, exp %+v", got, tt.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package pkger implements packages: declarative descriptions of the
+// resources of an organization, such as its buckets, dashboards and checks.
+// A package can be exported from one organization and applied to another,
+// which creates or updates only the resources that differ.
+package pkger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/influxdb"
+)
+
+// APIVersion is the version of the package format.
+const APIVersion = "0.1.0"
+
+// Kind is the kind of a package or of a resource in a package.
+type Kind string
+
+// Kinds of packages and resources.
+const (
+	KindPackage          Kind = "Package"
+	KindBucket           Kind = "Bucket"
+	KindCheck            Kind = "Check"
+	KindDashboard        Kind = "Dashboard"
+	KindLabel            Kind = "Label"
+	KindNotificationRule Kind = "NotificationRule"
+	KindTelegrafConfig   Kind = "TelegrafConfig"
+	KindVariable         Kind = "Variable"
+)
+
+// resourceKinds are the kinds of resources in the order they are applied.
+// Labels come first so the other resources can be associated with them.
+var resourceKinds = []Kind{
+	KindLabel,
+	KindBucket,
+	KindVariable,
+	KindTelegrafConfig,
+	KindCheck,
+	KindNotificationRule,
+	KindDashboard,
+}
+
+func (k Kind) valid() bool {
+	for _, kind := range resourceKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// order returns the position of the kind in the order resources are applied.
+func (k Kind) order() int {
+	for i, kind := range resourceKinds {
+		if k == kind {
+			return i
+		}
+	}
+	return len(resourceKinds)
+}
+
+// Encoding is the encoding of a package.
+type Encoding int
+
+// Encodings of packages.
+const (
+	EncodingYAML Encoding = iota
+	EncodingJSON
+)
+
+// Pkg is a package of resources.
+type Pkg struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       Kind     `json:"kind"`
+	Metadata   Metadata `json:"meta"`
+	Spec       Spec     `json:"spec"`
+}
+
+// Metadata describes a package.
+type Metadata struct {
+	Name        string `json:"pkgName"`
+	Version     string `json:"pkgVersion"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec holds the resources of a package.
+type Spec struct {
+	Resources []Resource `json:"resources"`
+}
+
+// Resource is a resource of a package. Every resource has a kind and a name
+// that is unique amongst the resources of its kind; the other fields depend on
+// the kind of the resource.
+type Resource map[string]interface{}
+
+// Kind returns the kind of the resource.
+func (r Resource) Kind() Kind {
+	k, _ := r["kind"].(string)
+	return Kind(k)
+}
+
+// Name returns the name of the resource.
+func (r Resource) Name() string {
+	n, _ := r["name"].(string)
+	return n
+}
+
+// decode decodes the resource into v, which must have a field for every
+// field of the resource.
+func (r Resource) decode(v interface{}) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// newResource returns the resource with the JSON encoding of v.
+func newResource(v interface{}) (Resource, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var r Resource
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Parse decodes and validates a package.
+func Parse(enc Encoding, r io.Reader) (*Pkg, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc == EncodingYAML {
+		if b, err = yaml.YAMLToJSON(b); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "unable to decode package yaml",
+				Err:  err,
+			}
+		}
+	}
+
+	var pkg Pkg
+	if err := json.Unmarshal(b, &pkg); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode package json",
+			Err:  err,
+		}
+	}
+
+	if err := pkg.Validate(); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// Encode encodes the package.
+func (p *Pkg) Encode(enc Encoding) ([]byte, error) {
+	switch enc {
+	case EncodingJSON:
+		return json.MarshalIndent(p, "", "\t")
+	default:
+		return yaml.Marshal(p)
+	}
+}
+
+// Validate returns an error if the package or any of its resources is invalid.
+func (p *Pkg) Validate() error {
+	if p.APIVersion != APIVersion {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unsupported package apiVersion %q; expected %q", p.APIVersion, APIVersion),
+		}
+	}
+	if p.Kind != KindPackage {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid package kind %q; expected %q", p.Kind, KindPackage),
+		}
+	}
+
+	names := make(map[Kind]map[string]bool)
+	for i, r := range p.Spec.Resources {
+		k, name := r.Kind(), r.Name()
+		if !k.valid() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("resource %d has invalid kind %q", i, k),
+			}
+		}
+		if name == "" {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("resource %d of kind %s has no name", i, k),
+			}
+		}
+		if names[k] == nil {
+			names[k] = make(map[string]bool)
+		}
+		if names[k][name] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("duplicate %s %q", k, name),
+			}
+		}
+		names[k][name] = true
+
+		if _, err := canonicalResource(r); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid %s %q", k, name),
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+// sortResources sorts resources in the order they are applied, and then by name.
+func sortResources(rs []Resource) {
+	sort.SliceStable(rs, func(i, j int) bool {
+		oi, oj := rs[i].Kind().order(), rs[j].Kind().order()
+		if oi != oj {
+			return oi < oj
+		}
+		return rs[i].Name() < rs[j].Name()
+	})
+}
@@ -0,0 +1,116 @@
+package pkger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  Encoding
+		pkg  string
+		err  string
+	}{
+		{
+			name: "yaml",
+			enc:  EncodingYAML,
+			pkg: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: p
+  pkgVersion: "1"
+spec:
+  resources:
+    - kind: Label
+      name: l
+      color: red
+    - kind: Bucket
+      name: b
+      retentionPeriod: 1d12h
+    - kind: Variable
+      name: v
+      type: map
+      values: {a: x}
+`,
+		},
+		{
+			name: "json",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"0.1.0","kind":"Package","meta":{"pkgName":"p","pkgVersion":"1"},"spec":{"resources":[{"kind":"Variable","name":"v","type":"query","query":"buckets()","language":"flux"}]}}`,
+		},
+		{
+			name: "unsupported api version",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"9","kind":"Package","spec":{"resources":[]}}`,
+			err:  `unsupported package apiVersion "9"; expected "0.1.0"`,
+		},
+		{
+			name: "invalid resource kind",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"0.1.0","kind":"Package","spec":{"resources":[{"kind":"Task","name":"t"}]}}`,
+			err:  `resource 0 has invalid kind "Task"`,
+		},
+		{
+			name: "duplicate resource",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"0.1.0","kind":"Package","spec":{"resources":[{"kind":"Label","name":"l"},{"kind":"Label","name":"l"}]}}`,
+			err:  `duplicate Label "l"`,
+		},
+		{
+			name: "unknown field",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"0.1.0","kind":"Package","spec":{"resources":[{"kind":"Bucket","name":"b","retention":"1d"}]}}`,
+			err:  `invalid Bucket "b"`,
+		},
+		{
+			name: "invalid association",
+			enc:  EncodingJSON,
+			pkg:  `{"apiVersion":"0.1.0","kind":"Package","spec":{"resources":[{"kind":"Bucket","name":"b","associations":[{"kind":"Bucket","name":"c"}]}]}}`,
+			err:  `invalid Bucket "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.enc, strings.NewReader(tt.pkg))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q", tt.err)
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.err {
+				t.Errorf("unexpected error: got %q, exp %q", got, tt.err)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d   time.Duration
+		exp string
+	}{
+		{d: time.Hour, exp: "1h"},
+		{d: 7 * 24 * time.Hour, exp: "1w"},
+		{d: 36 * time.Hour, exp: "1d12h"},
+		{d: 90 * time.Second, exp: "1m30s"},
+	}
+
+	for _, tt := range tests {
+		got := formatDuration(tt.d)
+		if got != tt.exp {
+			t.Errorf("unexpected format of %v: got %q, exp %q", tt.d, got, tt.exp)
+		}
+		if d, err := parseDuration(got); err != nil || d != tt.d {
+			t.Errorf("unexpected parse of %q: got %v, %v", got, d, err)
+		}
+	}
+}
@@ -0,0 +1,636 @@
+package pkger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+// association associates a resource with another resource, currently always a label.
+type association struct {
+	Kind Kind   `json:"kind"`
+	Name string `json:"name"`
+}
+
+// sortAssociations sorts associations by kind and name and removes duplicates.
+func sortAssociations(as []association) []association {
+	sort.Slice(as, func(i, j int) bool {
+		if as[i].Kind != as[j].Kind {
+			return as[i].Kind < as[j].Kind
+		}
+		return as[i].Name < as[j].Name
+	})
+
+	out := as[:0]
+	for i, a := range as {
+		if i > 0 && a == as[i-1] {
+			continue
+		}
+		out = append(out, a)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func validateAssociations(as []association) error {
+	for _, a := range as {
+		if a.Kind != KindLabel {
+			return fmt.Errorf("resources can only be associated with labels, not %q", a.Kind)
+		}
+		if a.Name == "" {
+			return fmt.Errorf("association with %s has no name", a.Kind)
+		}
+	}
+	return nil
+}
+
+// associations returns the associations of the resource.
+func (r Resource) associations() ([]association, error) {
+	v, ok := r["associations"]
+	if !ok {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var as []association
+	if err := json.Unmarshal(b, &as); err != nil {
+		return nil, err
+	}
+	if err := validateAssociations(as); err != nil {
+		return nil, err
+	}
+	return sortAssociations(as), nil
+}
+
+// setAssociations sets the associations of the resource, or removes them if there are none.
+func (r Resource) setAssociations(as []association) error {
+	as = sortAssociations(as)
+	if len(as) == 0 {
+		delete(r, "associations")
+		return nil
+	}
+
+	b, err := json.Marshal(as)
+	if err != nil {
+		return err
+	}
+	var v []interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r["associations"] = v
+	return nil
+}
+
+// canonicalResource decodes the resource and encodes it again, so that it can
+// be compared to the resources exported from the server.
+func canonicalResource(r Resource) (Resource, error) {
+	switch r.Kind() {
+	case KindLabel:
+		l, err := decodeLabel(r)
+		if err != nil {
+			return nil, err
+		}
+		return labelResource(l.influx(0))
+	case KindBucket:
+		b, err := decodeBucket(r)
+		if err != nil {
+			return nil, err
+		}
+		ib, err := b.influx(0)
+		if err != nil {
+			return nil, err
+		}
+		return bucketResource(ib, b.Associations)
+	case KindVariable:
+		v, err := decodeVariable(r)
+		if err != nil {
+			return nil, err
+		}
+		iv, err := v.influx(0)
+		if err != nil {
+			return nil, err
+		}
+		return variableResource(iv, v.Associations)
+	case KindDashboard:
+		d, err := decodeDashboard(r)
+		if err != nil {
+			return nil, err
+		}
+		cells, err := d.cells()
+		if err != nil {
+			return nil, err
+		}
+		return dashboardResource(d.influx(0), cells, d.Associations)
+	case KindCheck:
+		c, as, err := decodeCheck(r)
+		if err != nil {
+			return nil, err
+		}
+		return checkResource(c, as)
+	case KindNotificationRule:
+		nr, endpoint, as, err := decodeNotificationRule(r)
+		if err != nil {
+			return nil, err
+		}
+		return notificationRuleResource(nr, endpoint, as)
+	case KindTelegrafConfig:
+		tc, as, err := decodeTelegrafConfig(r)
+		if err != nil {
+			return nil, err
+		}
+		return telegrafConfigResource(tc, as)
+	default:
+		return nil, fmt.Errorf("invalid kind %q", r.Kind())
+	}
+}
+
+// label is a label resource.
+type label struct {
+	Kind        Kind   `json:"kind"`
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func decodeLabel(r Resource) (*label, error) {
+	var l label
+	if err := r.decode(&l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (l *label) influx(orgID influxdb.ID) *influxdb.Label {
+	props := make(map[string]string)
+	if l.Color != "" {
+		props["color"] = l.Color
+	}
+	if l.Description != "" {
+		props["description"] = l.Description
+	}
+	return &influxdb.Label{
+		OrgID:      orgID,
+		Name:       l.Name,
+		Properties: props,
+	}
+}
+
+func labelResource(l *influxdb.Label) (Resource, error) {
+	return newResource(label{
+		Kind:        KindLabel,
+		Name:        l.Name,
+		Color:       l.Properties["color"],
+		Description: l.Properties["description"],
+	})
+}
+
+// bucket is a bucket resource. A bucket without a retention period keeps its
+// data forever.
+type bucket struct {
+	Kind            Kind          `json:"kind"`
+	Name            string        `json:"name"`
+	Description     string        `json:"description,omitempty"`
+	RetentionPeriod string        `json:"retentionPeriod,omitempty"`
+	Associations    []association `json:"associations,omitempty"`
+}
+
+func decodeBucket(r Resource) (*bucket, error) {
+	var b bucket
+	if err := r.decode(&b); err != nil {
+		return nil, err
+	}
+	if err := validateAssociations(b.Associations); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (b *bucket) influx(orgID influxdb.ID) (*influxdb.Bucket, error) {
+	var rp time.Duration
+	if b.RetentionPeriod != "" {
+		d, err := parseDuration(b.RetentionPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retentionPeriod: %v", err)
+		}
+		rp = d
+	}
+	return &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            b.Name,
+		Description:     b.Description,
+		RetentionPeriod: rp,
+	}, nil
+}
+
+func bucketResource(b *influxdb.Bucket, as []association) (Resource, error) {
+	res := bucket{
+		Kind:         KindBucket,
+		Name:         b.Name,
+		Description:  b.Description,
+		Associations: sortAssociations(as),
+	}
+	if b.RetentionPeriod > 0 {
+		res.RetentionPeriod = formatDuration(b.RetentionPeriod)
+	}
+	return newResource(res)
+}
+
+// variable is a variable resource. The values of a constant variable are a
+// list and the values of a map variable are a map; the values of a query
+// variable are its query and language.
+type variable struct {
+	Kind         Kind          `json:"kind"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Type         string        `json:"type"`
+	Values       interface{}   `json:"values,omitempty"`
+	Query        string        `json:"query,omitempty"`
+	Language     string        `json:"language,omitempty"`
+	Selected     []string      `json:"selected,omitempty"`
+	Associations []association `json:"associations,omitempty"`
+}
+
+func decodeVariable(r Resource) (*variable, error) {
+	var v variable
+	if err := r.decode(&v); err != nil {
+		return nil, err
+	}
+	if err := validateAssociations(v.Associations); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (v *variable) influx(orgID influxdb.ID) (*influxdb.Variable, error) {
+	args := &influxdb.VariableArguments{Type: v.Type}
+	switch v.Type {
+	case "constant":
+		var values influxdb.VariableConstantValues
+		if err := convert(v.Values, &values); err != nil {
+			return nil, fmt.Errorf("values of a constant variable must be a list of strings")
+		}
+		args.Values = values
+	case "map":
+		var values influxdb.VariableMapValues
+		if err := convert(v.Values, &values); err != nil {
+			return nil, fmt.Errorf("values of a map variable must be a map of strings")
+		}
+		args.Values = values
+	case "query":
+		args.Values = influxdb.VariableQueryValues{
+			Query:    v.Query,
+			Language: v.Language,
+		}
+	default:
+		return nil, fmt.Errorf("invalid variable type %q; must be constant, map or query", v.Type)
+	}
+
+	iv := &influxdb.Variable{
+		OrganizationID: orgID,
+		Name:           v.Name,
+		Description:    v.Description,
+		Selected:       v.Selected,
+		Arguments:      args,
+	}
+	if err := iv.Valid(); err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
+
+func variableResource(v *influxdb.Variable, as []association) (Resource, error) {
+	res := variable{
+		Kind:         KindVariable,
+		Name:         v.Name,
+		Description:  v.Description,
+		Selected:     v.Selected,
+		Associations: sortAssociations(as),
+	}
+	if len(res.Selected) == 0 {
+		res.Selected = nil
+	}
+	if v.Arguments != nil {
+		res.Type = v.Arguments.Type
+		switch values := v.Arguments.Values.(type) {
+		case influxdb.VariableQueryValues:
+			res.Query, res.Language = values.Query, values.Language
+		default:
+			res.Values = values
+		}
+	}
+	return newResource(res)
+}
+
+// dashboard is a dashboard resource.
+type dashboard struct {
+	Kind         Kind          `json:"kind"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Cells        []cell        `json:"cells,omitempty"`
+	Associations []association `json:"associations,omitempty"`
+}
+
+// cell is a cell of a dashboard resource. The properties of the cell are the
+// properties of its view.
+type cell struct {
+	Name       string                 `json:"name,omitempty"`
+	X          int32                  `json:"x"`
+	Y          int32                  `json:"y"`
+	Width      int32                  `json:"width"`
+	Height     int32                  `json:"height"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// dashboardCell is a cell of a dashboard and its view.
+type dashboardCell struct {
+	influxdb.CellProperty
+	View *influxdb.View
+}
+
+func decodeDashboard(r Resource) (*dashboard, error) {
+	var d dashboard
+	if err := r.decode(&d); err != nil {
+		return nil, err
+	}
+	if err := validateAssociations(d.Associations); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (d *dashboard) influx(orgID influxdb.ID) *influxdb.Dashboard {
+	return &influxdb.Dashboard{
+		OrganizationID: orgID,
+		Name:           d.Name,
+		Description:    d.Description,
+	}
+}
+
+func (d *dashboard) cells() ([]dashboardCell, error) {
+	cells := make([]dashboardCell, 0, len(d.Cells))
+	for i, c := range d.Cells {
+		b, err := json.Marshal(map[string]interface{}{"properties": c.Properties})
+		if err != nil {
+			return nil, err
+		}
+		props, err := influxdb.UnmarshalViewPropertiesJSON(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid properties of cell %d: %v", i, err)
+		}
+
+		cells = append(cells, dashboardCell{
+			CellProperty: influxdb.CellProperty{X: c.X, Y: c.Y, W: c.Width, H: c.Height},
+			View: &influxdb.View{
+				ViewContents: influxdb.ViewContents{Name: c.Name},
+				Properties:   props,
+			},
+		})
+	}
+	return cells, nil
+}
+
+func dashboardResource(d *influxdb.Dashboard, cells []dashboardCell, as []association) (Resource, error) {
+	res := dashboard{
+		Kind:         KindDashboard,
+		Name:         d.Name,
+		Description:  d.Description,
+		Associations: sortAssociations(as),
+	}
+
+	for _, c := range cells {
+		rc := cell{X: c.X, Y: c.Y, Width: c.W, Height: c.H}
+		if c.View != nil {
+			rc.Name = c.View.Name
+			if _, ok := c.View.Properties.(influxdb.EmptyViewProperties); !ok && c.View.Properties != nil {
+				b, err := influxdb.MarshalViewPropertiesJSON(c.View.Properties)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal(b, &rc.Properties); err != nil {
+					return nil, err
+				}
+			}
+		}
+		res.Cells = append(res.Cells, rc)
+	}
+
+	sort.SliceStable(res.Cells, func(i, j int) bool {
+		ci, cj := res.Cells[i], res.Cells[j]
+		if ci.Y != cj.Y {
+			return ci.Y < cj.Y
+		}
+		return ci.X < cj.X
+	})
+	return newResource(res)
+}
+
+// serverFields are the fields of the JSON encoding of checks, notification
+// rules and telegraf configs that are managed by the server and are not part
+// of their resources.
+var serverFields = []string{"id", "orgID", "organizationID", "ownerID", "taskID", "createdAt", "updatedAt"}
+
+// objectJSON returns the JSON encoding of the resource without the fields
+// that are not part of the encoding of the object it describes.
+func (r Resource) objectJSON(fields ...string) ([]byte, error) {
+	o := make(map[string]interface{}, len(r))
+	for k, v := range r {
+		o[k] = v
+	}
+	delete(o, "kind")
+	delete(o, "associations")
+	for _, f := range fields {
+		delete(o, f)
+	}
+	return json.Marshal(o)
+}
+
+// objectResource returns the resource of kind k for the JSON encoding of v.
+func objectResource(k Kind, v interface{}, as []association) (Resource, error) {
+	r, err := newResource(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range serverFields {
+		delete(r, f)
+	}
+	prune(r)
+	r["kind"] = string(k)
+	if err := r.setAssociations(as); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// prune removes the null, empty string and empty object fields of the object
+// and of the objects nested in it, which decode to the same values as missing fields.
+func prune(o map[string]interface{}) {
+	for k, v := range o {
+		switch v := v.(type) {
+		case nil:
+			delete(o, k)
+		case string:
+			if v == "" {
+				delete(o, k)
+			}
+		case map[string]interface{}:
+			prune(v)
+			if len(v) == 0 {
+				delete(o, k)
+			}
+		case []interface{}:
+			for _, e := range v {
+				if e, ok := e.(map[string]interface{}); ok {
+					prune(e)
+				}
+			}
+		}
+	}
+}
+
+// decodeCheck decodes a check resource, whose fields are those of the JSON
+// encoding of the check.
+func decodeCheck(r Resource) (influxdb.Check, []association, error) {
+	as, err := r.associations()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := r.objectJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := check.UnmarshalJSON(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, as, nil
+}
+
+func checkResource(c influxdb.Check, as []association) (Resource, error) {
+	return objectResource(KindCheck, c, as)
+}
+
+// decodeNotificationRule decodes a notification rule resource, whose fields
+// are those of the JSON encoding of the rule, except that its endpoint is
+// referred to by name.
+func decodeNotificationRule(r Resource) (influxdb.NotificationRule, string, []association, error) {
+	as, err := r.associations()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	endpoint, _ := r["endpointName"].(string)
+	if endpoint == "" {
+		return nil, "", nil, fmt.Errorf("endpointName is required")
+	}
+	b, err := r.objectJSON("endpointName")
+	if err != nil {
+		return nil, "", nil, err
+	}
+	nr, err := rule.UnmarshalJSON(b)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return nr, endpoint, as, nil
+}
+
+func notificationRuleResource(nr influxdb.NotificationRule, endpoint string, as []association) (Resource, error) {
+	r, err := objectResource(KindNotificationRule, nr, as)
+	if err != nil {
+		return nil, err
+	}
+	delete(r, "endpointID")
+	r["endpointName"] = endpoint
+	return r, nil
+}
+
+// decodeTelegrafConfig decodes a telegraf config resource, whose fields are
+// those of the JSON encoding of the config.
+func decodeTelegrafConfig(r Resource) (*influxdb.TelegrafConfig, []association, error) {
+	as, err := r.associations()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := r.objectJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	var tc influxdb.TelegrafConfig
+	if err := json.Unmarshal(b, &tc); err != nil {
+		return nil, nil, err
+	}
+	return &tc, as, nil
+}
+
+func telegrafConfigResource(tc *influxdb.TelegrafConfig, as []association) (Resource, error) {
+	// The JSON encoding of a telegraf config requires valid IDs, even though
+	// they are not part of the resource.
+	c := *tc
+	if !c.ID.Valid() {
+		c.ID = 1
+	}
+	if !c.OrgID.Valid() {
+		c.OrgID = 1
+	}
+	return objectResource(KindTelegrafConfig, &c, as)
+}
+
+// convert converts v to the type of out through its JSON encoding.
+func convert(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// parseDuration parses a flux duration literal such as 1w or 2h30m.
+func parseDuration(s string) (time.Duration, error) {
+	dl, err := parser.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return ast.DurationFrom(dl, time.Time{})
+}
+
+var durationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// formatDuration formats a duration as a flux duration literal.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	var b strings.Builder
+	for _, u := range durationUnits {
+		if n := d / u.d; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.unit)
+			d -= n * u.d
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,711 @@
+package pkger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// SVC exports the resources of an organization as packages and applies
+// packages to organizations.
+type SVC interface {
+	// CreatePkg returns a package of the resources of the organization.
+	CreatePkg(ctx context.Context, orgID influxdb.ID, meta Metadata) (*Pkg, error)
+
+	// DryRun returns the changes that applying the package to the organization would make.
+	DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Diff, error)
+
+	// Apply creates and updates the resources of the organization that differ
+	// from the package and returns the changes it made.
+	Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Diff, error)
+}
+
+// Change is the change applying a package makes to a resource.
+type Change string
+
+// Changes to resources.
+const (
+	ChangeCreate Change = "create"
+	ChangeUpdate Change = "update"
+	ChangeNone   Change = "none"
+)
+
+// DiffEntry is the change applying a package makes to one of its resources.
+type DiffEntry struct {
+	Kind   Kind        `json:"kind"`
+	Name   string      `json:"name"`
+	Change Change      `json:"change"`
+	ID     influxdb.ID `json:"id,omitempty"`
+	// Fields are the fields of an updated resource that changed.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Diff is the changes applying a package makes, in the order they are made.
+type Diff []DiffEntry
+
+// HasChanges returns true if applying the package creates or updates any resource.
+func (d Diff) HasChanges() bool {
+	for _, e := range d {
+		if e.Change != ChangeNone {
+			return true
+		}
+	}
+	return false
+}
+
+// Service implements SVC on top of the resource services.
+type Service struct {
+	Logger *zap.Logger
+
+	BucketService               influxdb.BucketService
+	LabelService                influxdb.LabelService
+	DashboardService            influxdb.DashboardService
+	VariableService             influxdb.VariableService
+	CheckService                influxdb.CheckService
+	NotificationRuleStore       influxdb.NotificationRuleStore
+	NotificationEndpointService influxdb.NotificationEndpointService
+	TelegrafService             influxdb.TelegrafConfigStore
+}
+
+var _ SVC = (*Service)(nil)
+
+// existing is a resource of an organization.
+type existing struct {
+	id       influxdb.ID
+	resource Resource
+	// object is the resource as returned by its service.
+	object interface{}
+}
+
+// orgState is the resources of an organization.
+type orgState struct {
+	orgID     influxdb.ID
+	resources map[Kind]map[string]*existing
+	// endpoints maps notification endpoint names to IDs and IDs to names.
+	endpointIDs   map[string]influxdb.ID
+	endpointNames map[influxdb.ID]string
+}
+
+func (st *orgState) add(k Kind, id influxdb.ID, r Resource, obj interface{}) {
+	if st.resources[k] == nil {
+		st.resources[k] = make(map[string]*existing)
+	}
+	st.resources[k][r.Name()] = &existing{id: id, resource: r, object: obj}
+}
+
+func (st *orgState) get(k Kind, name string) *existing {
+	return st.resources[k][name]
+}
+
+// CreatePkg returns a package of the resources of the organization.
+func (s *Service) CreatePkg(ctx context.Context, orgID influxdb.ID, meta Metadata) (*Pkg, error) {
+	st, err := s.state(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &Pkg{
+		APIVersion: APIVersion,
+		Kind:       KindPackage,
+		Metadata:   meta,
+		Spec:       Spec{Resources: []Resource{}},
+	}
+	for _, k := range resourceKinds {
+		for _, e := range st.resources[k] {
+			pkg.Spec.Resources = append(pkg.Spec.Resources, e.resource)
+		}
+	}
+	sortResources(pkg.Spec.Resources)
+	return pkg, nil
+}
+
+// DryRun returns the changes that applying the package to the organization would make.
+func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Diff, error) {
+	st, err := s.state(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	diff, _, err := s.diff(st, pkg)
+	return diff, err
+}
+
+// Apply creates and updates the resources of the organization that differ
+// from the package and returns the changes it made. Resources of the
+// organization that are not in the package are left untouched.
+func (s *Service) Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Diff, error) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := a.GetUserID()
+
+	st, err := s.state(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	diff, resources, err := s.diff(st, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range diff {
+		e := &diff[i]
+		if e.Change == ChangeNone {
+			continue
+		}
+		if err := s.apply(ctx, st, userID, e, resources[i]); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.ErrorCode(err),
+				Msg:  fmt.Sprintf("unable to %s %s %q", e.Change, e.Kind, e.Name),
+				Err:  err,
+			}
+		}
+	}
+	return diff, nil
+}
+
+// diff compares the resources of the package with those of the organization.
+// It returns the diff and the canonical resources of the package in the same order.
+func (s *Service) diff(st *orgState, pkg *Pkg) (Diff, []Resource, error) {
+	if err := pkg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	resources := make([]Resource, 0, len(pkg.Spec.Resources))
+	for _, r := range pkg.Spec.Resources {
+		cr, err := canonicalResource(r)
+		if err != nil {
+			return nil, nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid %s %q", r.Kind(), r.Name()),
+				Err:  err,
+			}
+		}
+		resources = append(resources, cr)
+	}
+	sortResources(resources)
+
+	labels := make(map[string]bool)
+	for _, r := range resources {
+		if r.Kind() == KindLabel {
+			labels[r.Name()] = true
+		}
+	}
+
+	diff := make(Diff, 0, len(resources))
+	for _, r := range resources {
+		as, err := r.associations()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, a := range as {
+			if !labels[a.Name] && st.get(KindLabel, a.Name) == nil {
+				return nil, nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("%s %q is associated with unknown label %q", r.Kind(), r.Name(), a.Name),
+				}
+			}
+		}
+		if r.Kind() == KindNotificationRule {
+			if endpoint := r["endpointName"].(string); st.endpointIDs[endpoint] == 0 {
+				return nil, nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("notification rule %q refers to unknown notification endpoint %q", r.Name(), endpoint),
+				}
+			}
+		}
+
+		e := DiffEntry{Kind: r.Kind(), Name: r.Name(), Change: ChangeCreate}
+		if cur := st.get(r.Kind(), r.Name()); cur != nil {
+			e.ID = cur.id
+			e.Change = ChangeNone
+			if e.Fields = changedFields(cur.resource, r); len(e.Fields) > 0 {
+				e.Change = ChangeUpdate
+			}
+		}
+		diff = append(diff, e)
+	}
+	return diff, resources, nil
+}
+
+// changedFields returns the sorted names of the fields that differ between the resources.
+func changedFields(from, to Resource) []string {
+	var fields []string
+	for k, v := range to {
+		if !reflect.DeepEqual(from[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// onlyAssociations returns true if the associations are the only changed field.
+func onlyAssociations(fields []string) bool {
+	return len(fields) == 1 && fields[0] == "associations"
+}
+
+func (s *Service) apply(ctx context.Context, st *orgState, userID influxdb.ID, e *DiffEntry, r Resource) error {
+	var (
+		id  influxdb.ID
+		err error
+	)
+	if e.Change == ChangeUpdate && onlyAssociations(e.Fields) {
+		id = e.ID
+	} else {
+		switch e.Kind {
+		case KindLabel:
+			id, err = s.applyLabel(ctx, st, e, r)
+		case KindBucket:
+			id, err = s.applyBucket(ctx, st, e, r)
+		case KindVariable:
+			id, err = s.applyVariable(ctx, st, e, r)
+		case KindTelegrafConfig:
+			id, err = s.applyTelegrafConfig(ctx, st, userID, e, r)
+		case KindCheck:
+			id, err = s.applyCheck(ctx, st, userID, e, r)
+		case KindNotificationRule:
+			id, err = s.applyNotificationRule(ctx, st, userID, e, r)
+		case KindDashboard:
+			id, err = s.applyDashboard(ctx, st, e, r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.ID = id
+
+	var current []association
+	if cur := st.get(e.Kind, e.Name); cur != nil {
+		if current, err = cur.resource.associations(); err != nil {
+			return err
+		}
+	}
+	wanted, err := r.associations()
+	if err != nil {
+		return err
+	}
+	if err := s.syncAssociations(ctx, st, e.Kind, id, current, wanted); err != nil {
+		return err
+	}
+
+	st.add(e.Kind, id, r, nil)
+	return nil
+}
+
+func (s *Service) applyLabel(ctx context.Context, st *orgState, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	l, err := decodeLabel(r)
+	if err != nil {
+		return 0, err
+	}
+	if e.Change == ChangeCreate {
+		il := l.influx(st.orgID)
+		if err := s.LabelService.CreateLabel(ctx, il); err != nil {
+			return 0, err
+		}
+		return il.ID, nil
+	}
+
+	// Empty properties are removed from the label.
+	upd := influxdb.LabelUpdate{
+		Properties: map[string]string{
+			"color":       l.Color,
+			"description": l.Description,
+		},
+	}
+	if _, err := s.LabelService.UpdateLabel(ctx, e.ID, upd); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyBucket(ctx context.Context, st *orgState, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	b, err := decodeBucket(r)
+	if err != nil {
+		return 0, err
+	}
+	ib, err := b.influx(st.orgID)
+	if err != nil {
+		return 0, err
+	}
+	if e.Change == ChangeCreate {
+		if err := s.BucketService.CreateBucket(ctx, ib); err != nil {
+			return 0, err
+		}
+		return ib.ID, nil
+	}
+
+	upd := influxdb.BucketUpdate{
+		Description:     &ib.Description,
+		RetentionPeriod: &ib.RetentionPeriod,
+	}
+	if _, err := s.BucketService.UpdateBucket(ctx, e.ID, upd); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyVariable(ctx context.Context, st *orgState, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	v, err := decodeVariable(r)
+	if err != nil {
+		return 0, err
+	}
+	iv, err := v.influx(st.orgID)
+	if err != nil {
+		return 0, err
+	}
+	if e.Change == ChangeCreate {
+		if err := s.VariableService.CreateVariable(ctx, iv); err != nil {
+			return 0, err
+		}
+		return iv.ID, nil
+	}
+
+	upd := &influxdb.VariableUpdate{
+		Description: iv.Description,
+		Selected:    iv.Selected,
+		Arguments:   iv.Arguments,
+	}
+	if upd.Selected == nil {
+		upd.Selected = []string{}
+	}
+	if _, err := s.VariableService.UpdateVariable(ctx, e.ID, upd); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyTelegrafConfig(ctx context.Context, st *orgState, userID influxdb.ID, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	tc, _, err := decodeTelegrafConfig(r)
+	if err != nil {
+		return 0, err
+	}
+	tc.OrgID = st.orgID
+	if e.Change == ChangeCreate {
+		if err := s.TelegrafService.CreateTelegrafConfig(ctx, tc, userID); err != nil {
+			return 0, err
+		}
+		return tc.ID, nil
+	}
+
+	tc.ID = e.ID
+	if _, err := s.TelegrafService.UpdateTelegrafConfig(ctx, e.ID, tc, userID); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyCheck(ctx context.Context, st *orgState, userID influxdb.ID, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	c, _, err := decodeCheck(r)
+	if err != nil {
+		return 0, err
+	}
+	c.SetOrgID(st.orgID)
+	c.SetOwnerID(userID)
+	if e.Change == ChangeCreate {
+		if err := s.CheckService.CreateCheck(ctx, c, userID); err != nil {
+			return 0, err
+		}
+		return c.GetID(), nil
+	}
+
+	c.SetID(e.ID)
+	if _, err := s.CheckService.UpdateCheck(ctx, e.ID, c); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyNotificationRule(ctx context.Context, st *orgState, userID influxdb.ID, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	// The endpoint of a rule is referred to by name in the package and by ID on the server.
+	obj := make(Resource, len(r)+1)
+	for k, v := range r {
+		obj[k] = v
+	}
+	obj["endpointID"] = st.endpointIDs[r["endpointName"].(string)].String()
+	nr, _, _, err := decodeNotificationRule(obj)
+	if err != nil {
+		return 0, err
+	}
+
+	nr.SetOrgID(st.orgID)
+	nr.SetOwnerID(userID)
+	if e.Change == ChangeCreate {
+		if err := s.NotificationRuleStore.CreateNotificationRule(ctx, nr, userID); err != nil {
+			return 0, err
+		}
+		return nr.GetID(), nil
+	}
+
+	nr.SetID(e.ID)
+	if _, err := s.NotificationRuleStore.UpdateNotificationRule(ctx, e.ID, nr, userID); err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (s *Service) applyDashboard(ctx context.Context, st *orgState, e *DiffEntry, r Resource) (influxdb.ID, error) {
+	d, err := decodeDashboard(r)
+	if err != nil {
+		return 0, err
+	}
+	cells, err := d.cells()
+	if err != nil {
+		return 0, err
+	}
+
+	id := e.ID
+	if e.Change == ChangeCreate {
+		id, err = s.createDashboard(ctx, d.influx(st.orgID))
+	} else {
+		_, err = s.DashboardService.UpdateDashboard(ctx, id, influxdb.DashboardUpdate{Description: &d.Description})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if e.Change == ChangeUpdate && !contains(e.Fields, "cells") {
+		return id, nil
+	}
+
+	// Cells are replaced as a whole, as they have no names to match them by.
+	if cur := st.get(KindDashboard, e.Name); cur != nil {
+		if current, ok := cur.object.(*influxdb.Dashboard); ok {
+			for _, c := range current.Cells {
+				if err := s.DashboardService.RemoveDashboardCell(ctx, id, c.ID); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	for _, c := range cells {
+		cell := &influxdb.Cell{CellProperty: c.CellProperty}
+		if err := s.DashboardService.AddDashboardCell(ctx, id, cell, influxdb.AddDashboardCellOptions{View: c.View}); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+func (s *Service) createDashboard(ctx context.Context, d *influxdb.Dashboard) (influxdb.ID, error) {
+	if err := s.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return 0, err
+	}
+	return d.ID, nil
+}
+
+// syncAssociations maps the resource to the wanted labels and unmaps it from the others.
+func (s *Service) syncAssociations(ctx context.Context, st *orgState, k Kind, id influxdb.ID, current, wanted []association) error {
+	rt := resourceTypes[k]
+	has := make(map[string]bool, len(current))
+	for _, a := range current {
+		has[a.Name] = true
+	}
+	want := make(map[string]bool, len(wanted))
+	for _, a := range wanted {
+		want[a.Name] = true
+	}
+
+	for _, a := range wanted {
+		if has[a.Name] {
+			continue
+		}
+		m := &influxdb.LabelMapping{LabelID: st.get(KindLabel, a.Name).id, ResourceID: id, ResourceType: rt}
+		if err := s.LabelService.CreateLabelMapping(ctx, m); err != nil {
+			return err
+		}
+	}
+	for _, a := range current {
+		if want[a.Name] {
+			continue
+		}
+		m := &influxdb.LabelMapping{LabelID: st.get(KindLabel, a.Name).id, ResourceID: id, ResourceType: rt}
+		if err := s.LabelService.DeleteLabelMapping(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceTypes are the resource types of the kinds of resources.
+var resourceTypes = map[Kind]influxdb.ResourceType{
+	KindBucket:           influxdb.BucketsResourceType,
+	KindCheck:            influxdb.ChecksResourceType,
+	KindDashboard:        influxdb.DashboardsResourceType,
+	KindLabel:            influxdb.LabelsResourceType,
+	KindNotificationRule: influxdb.NotificationRuleResourceType,
+	KindTelegrafConfig:   influxdb.TelegrafsResourceType,
+	KindVariable:         influxdb.VariablesResourceType,
+}
+
+// state returns the resources of the organization.
+func (s *Service) state(ctx context.Context, orgID influxdb.ID) (*orgState, error) {
+	st := &orgState{
+		orgID:         orgID,
+		resources:     make(map[Kind]map[string]*existing),
+		endpointIDs:   make(map[string]influxdb.ID),
+		endpointNames: make(map[influxdb.ID]string),
+	}
+
+	labels, err := s.LabelService.FindLabels(ctx, influxdb.LabelFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		r, err := labelResource(l)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindLabel, l.ID, r, l)
+	}
+
+	buckets, _, err := s.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range buckets {
+		if b.Type == influxdb.BucketTypeSystem {
+			continue
+		}
+		as, err := s.associations(ctx, b.ID, influxdb.BucketsResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := bucketResource(b, as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindBucket, b.ID, r, b)
+	}
+
+	variables, err := s.VariableService.FindVariables(ctx, influxdb.VariableFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variables {
+		as, err := s.associations(ctx, v.ID, influxdb.VariablesResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := variableResource(v, as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindVariable, v.ID, r, v)
+	}
+
+	telegrafs, _, err := s.TelegrafService.FindTelegrafConfigs(ctx, influxdb.TelegrafConfigFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, tc := range telegrafs {
+		as, err := s.associations(ctx, tc.ID, influxdb.TelegrafsResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := telegrafConfigResource(tc, as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindTelegrafConfig, tc.ID, r, tc)
+	}
+
+	checks, _, err := s.CheckService.FindChecks(ctx, influxdb.CheckFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range checks {
+		as, err := s.associations(ctx, c.GetID(), influxdb.ChecksResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := checkResource(c, as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindCheck, c.GetID(), r, c)
+	}
+
+	endpoints, _, err := s.NotificationEndpointService.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, ne := range endpoints {
+		st.endpointIDs[ne.GetName()] = ne.GetID()
+		st.endpointNames[ne.GetID()] = ne.GetName()
+	}
+
+	rules, _, err := s.NotificationRuleStore.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, nr := range rules {
+		as, err := s.associations(ctx, nr.GetID(), influxdb.NotificationRuleResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := notificationRuleResource(nr, st.endpointNames[nr.GetEndpointID()], as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindNotificationRule, nr.GetID(), r, nr)
+	}
+
+	dashboards, _, err := s.DashboardService.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: &orgID}, influxdb.DefaultDashboardFindOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dashboards {
+		cells := make([]dashboardCell, 0, len(d.Cells))
+		for _, c := range d.Cells {
+			v, err := s.DashboardService.GetDashboardCellView(ctx, d.ID, c.ID)
+			if err != nil {
+				return nil, err
+			}
+			cells = append(cells, dashboardCell{CellProperty: c.CellProperty, View: v})
+		}
+		as, err := s.associations(ctx, d.ID, influxdb.DashboardsResourceType)
+		if err != nil {
+			return nil, err
+		}
+		r, err := dashboardResource(d, cells, as)
+		if err != nil {
+			return nil, err
+		}
+		st.add(KindDashboard, d.ID, r, d)
+	}
+
+	return st, nil
+}
+
+// associations returns the associations of the resource with labels.
+func (s *Service) associations(ctx context.Context, id influxdb.ID, rt influxdb.ResourceType) ([]association, error) {
+	labels, err := s.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id, ResourceType: rt})
+	if err != nil {
+		return nil, err
+	}
+	as := make([]association, 0, len(labels))
+	for _, l := range labels {
+		as = append(as, association{Kind: KindLabel, Name: l.Name})
+	}
+	return as, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,458 @@
+package pkger_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	"github.com/influxdata/influxdb/pkger"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"go.uber.org/zap"
+)
+
+func mustDuration(t *testing.T, d string) *notification.Duration {
+	t.Helper()
+	dur, err := parser.ParseDuration(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return (*notification.Duration)(dur)
+}
+
+type testEnv struct {
+	kv     *kv.Service
+	svc    *pkger.Service
+	ctx    context.Context
+	userID influxdb.ID
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	kvSvc := kv.NewService(inmem.NewKVStore())
+	if err := kvSvc.Initialize(ctx); err != nil {
+		t.Fatalf("unable to initialize kv service: %v", err)
+	}
+
+	user := &influxdb.User{Name: "user"}
+	if err := kvSvc.CreateUser(ctx, user); err != nil {
+		t.Fatalf("unable to create user: %v", err)
+	}
+
+	return &testEnv{
+		kv: kvSvc,
+		svc: &pkger.Service{
+			Logger:                      zap.NewNop(),
+			BucketService:               kvSvc,
+			LabelService:                kvSvc,
+			DashboardService:            kvSvc,
+			VariableService:             kvSvc,
+			CheckService:                newCheckService(kvSvc.IDGenerator),
+			NotificationRuleStore:       newNotificationRuleStore(kvSvc.IDGenerator),
+			NotificationEndpointService: kvSvc,
+			TelegrafService:             kvSvc,
+		},
+		ctx:    icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: user.ID}),
+		userID: user.ID,
+	}
+}
+
+// newCheckService returns a check service that keeps checks in memory, as the
+// kv check service needs the flux runtime to create the tasks of checks.
+func newCheckService(ids influxdb.IDGenerator) *mock.CheckService {
+	var checks []influxdb.Check
+	svc := mock.NewCheckService()
+	svc.FindChecksFn = func(ctx context.Context, filter influxdb.CheckFilter, opts ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+		var cs []influxdb.Check
+		for _, c := range checks {
+			if c.GetOrgID() == *filter.OrgID {
+				cs = append(cs, c)
+			}
+		}
+		return cs, len(cs), nil
+	}
+	svc.CreateCheckFn = func(ctx context.Context, c influxdb.Check, userID influxdb.ID) error {
+		c.SetID(ids.ID())
+		checks = append(checks, c)
+		return nil
+	}
+	svc.UpdateCheckFn = func(ctx context.Context, id influxdb.ID, c influxdb.Check) (influxdb.Check, error) {
+		for i := range checks {
+			if checks[i].GetID() == id {
+				checks[i] = c
+				return c, nil
+			}
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "check not found"}
+	}
+	return svc
+}
+
+// newNotificationRuleStore returns a notification rule store that keeps rules
+// in memory, for the same reason as newCheckService.
+func newNotificationRuleStore(ids influxdb.IDGenerator) *mock.NotificationRuleStore {
+	var rules []influxdb.NotificationRule
+	return &mock.NotificationRuleStore{
+		FindNotificationRulesF: func(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+			var nrs []influxdb.NotificationRule
+			for _, nr := range rules {
+				if nr.GetOrgID() == *filter.OrgID {
+					nrs = append(nrs, nr)
+				}
+			}
+			return nrs, len(nrs), nil
+		},
+		CreateNotificationRuleF: func(ctx context.Context, nr influxdb.NotificationRule, userID influxdb.ID) error {
+			nr.SetID(ids.ID())
+			rules = append(rules, nr)
+			return nil
+		},
+		UpdateNotificationRuleF: func(ctx context.Context, id influxdb.ID, nr influxdb.NotificationRule, userID influxdb.ID) (influxdb.NotificationRule, error) {
+			for i := range rules {
+				if rules[i].GetID() == id {
+					rules[i] = nr
+					return nr, nil
+				}
+			}
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification rule not found"}
+		},
+	}
+}
+
+// createOrg creates an organization with a notification endpoint named webhook.
+func (env *testEnv) createOrg(t *testing.T, name string) *influxdb.Organization {
+	t.Helper()
+	org := &influxdb.Organization{Name: name}
+	if err := env.kv.CreateOrganization(env.ctx, org); err != nil {
+		t.Fatalf("unable to create organization: %v", err)
+	}
+
+	ne := &endpoint.HTTP{
+		Base: endpoint.Base{
+			OrgID:  org.ID,
+			Status: influxdb.Active,
+			Name:   "webhook",
+		},
+		URL:        "http://example.com/hook",
+		Method:     http.MethodPost,
+		AuthMethod: "none",
+	}
+	if err := env.kv.CreateNotificationEndpoint(env.ctx, ne, env.userID); err != nil {
+		t.Fatalf("unable to create notification endpoint: %v", err)
+	}
+	return org
+}
+
+// populate creates one resource of every kind in the organization.
+func (env *testEnv) populate(t *testing.T, orgID influxdb.ID) {
+	t.Helper()
+	ctx := env.ctx
+
+	l := &influxdb.Label{OrgID: orgID, Name: "team", Properties: map[string]string{"color": "#326BBA"}}
+	if err := env.kv.CreateLabel(ctx, l); err != nil {
+		t.Fatalf("unable to create label: %v", err)
+	}
+
+	b := &influxdb.Bucket{OrgID: orgID, Name: "metrics", RetentionPeriod: 7 * 24 * time.Hour}
+	if err := env.kv.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unable to create bucket: %v", err)
+	}
+	if err := env.kv.CreateLabelMapping(ctx, &influxdb.LabelMapping{LabelID: l.ID, ResourceID: b.ID, ResourceType: influxdb.BucketsResourceType}); err != nil {
+		t.Fatalf("unable to create label mapping: %v", err)
+	}
+
+	v := &influxdb.Variable{
+		OrganizationID: orgID,
+		Name:           "hosts",
+		Arguments: &influxdb.VariableArguments{
+			Type:   "constant",
+			Values: influxdb.VariableConstantValues{"a", "b"},
+		},
+	}
+	if err := env.kv.CreateVariable(ctx, v); err != nil {
+		t.Fatalf("unable to create variable: %v", err)
+	}
+
+	tc := &influxdb.TelegrafConfig{
+		OrgID: orgID,
+		Name:  "host",
+		Agent: influxdb.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []influxdb.TelegrafPlugin{
+			{Config: &inputs.CPUStats{}},
+		},
+	}
+	if err := env.kv.CreateTelegrafConfig(ctx, tc, env.userID); err != nil {
+		t.Fatalf("unable to create telegraf config: %v", err)
+	}
+
+	c := &check.Deadman{
+		Base: check.Base{
+			OrgID:                 orgID,
+			Name:                  "silence",
+			Status:                influxdb.Active,
+			StatusMessageTemplate: "no data",
+			Every:                 mustDuration(t, "1m"),
+			Query: influxdb.DashboardQuery{
+				Text: `data = from(bucket: "metrics") |> range(start: -1m)`,
+			},
+		},
+		TimeSince: mustDuration(t, "90s"),
+		StaleTime: mustDuration(t, "10m"),
+		Level:     notification.Critical,
+	}
+	if err := env.svc.CheckService.CreateCheck(ctx, c, env.userID); err != nil {
+		t.Fatalf("unable to create check: %v", err)
+	}
+
+	endpoints, _, err := env.kv.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{OrgID: &orgID})
+	if err != nil || len(endpoints) != 1 {
+		t.Fatalf("unable to find notification endpoint: %v", err)
+	}
+	nr := &rule.HTTP{
+		Base: rule.Base{
+			OrgID:      orgID,
+			Name:       "page",
+			Status:     influxdb.Active,
+			EndpointID: endpoints[0].GetID(),
+			Every:      mustDuration(t, "10m"),
+			StatusRules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+			},
+		},
+	}
+	if err := env.svc.NotificationRuleStore.CreateNotificationRule(ctx, nr, env.userID); err != nil {
+		t.Fatalf("unable to create notification rule: %v", err)
+	}
+
+	d := &influxdb.Dashboard{OrganizationID: orgID, Name: "overview"}
+	if err := env.kv.CreateDashboard(ctx, d); err != nil {
+		t.Fatalf("unable to create dashboard: %v", err)
+	}
+	view := &influxdb.View{
+		ViewContents: influxdb.ViewContents{Name: "notes"},
+		Properties:   influxdb.MarkdownViewProperties{Type: "markdown", Note: "hello"},
+	}
+	cell := &influxdb.Cell{CellProperty: influxdb.CellProperty{X: 0, Y: 0, W: 6, H: 3}}
+	if err := env.kv.AddDashboardCell(ctx, d.ID, cell, influxdb.AddDashboardCellOptions{View: view}); err != nil {
+		t.Fatalf("unable to add dashboard cell: %v", err)
+	}
+}
+
+func changes(diff pkger.Diff) map[pkger.Kind]pkger.Change {
+	m := make(map[pkger.Kind]pkger.Change)
+	for _, e := range diff {
+		m[e.Kind] = e.Change
+	}
+	return m
+}
+
+func TestService_CreatePkgAndApply(t *testing.T) {
+	env := newTestEnv(t)
+	org1 := env.createOrg(t, "org1")
+	org2 := env.createOrg(t, "org2")
+	env.populate(t, org1.ID)
+
+	exported, err := env.svc.CreatePkg(env.ctx, org1.ID, pkger.Metadata{Name: "monitoring", Version: "1"})
+	if err != nil {
+		t.Fatalf("unable to create package: %v", err)
+	}
+	if got, exp := len(exported.Spec.Resources), 7; got != exp {
+		t.Fatalf("unexpected number of resources: got %d, exp %d", got, exp)
+	}
+
+	// The package must survive its own encoding.
+	b, err := exported.Encode(pkger.EncodingYAML)
+	if err != nil {
+		t.Fatalf("unable to encode package: %v", err)
+	}
+	pkg, err := pkger.Parse(pkger.EncodingYAML, bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("unable to parse package: %v\n%s", err, b)
+	}
+
+	diff, err := env.svc.DryRun(env.ctx, org1.ID, pkg)
+	if err != nil {
+		t.Fatalf("unable to dry run package: %v", err)
+	}
+	if diff.HasChanges() {
+		t.Fatalf("expected no changes to the exported organization, got %+v", diff)
+	}
+
+	diff, err = env.svc.DryRun(env.ctx, org2.ID, pkg)
+	if err != nil {
+		t.Fatalf("unable to dry run package: %v", err)
+	}
+	for k, c := range changes(diff) {
+		if c != pkger.ChangeCreate {
+			t.Errorf("unexpected change of %s: got %s, exp %s", k, c, pkger.ChangeCreate)
+		}
+	}
+
+	if _, err := env.svc.Apply(env.ctx, org2.ID, pkg); err != nil {
+		t.Fatalf("unable to apply package: %v", err)
+	}
+	diff, err = env.svc.DryRun(env.ctx, org2.ID, pkg)
+	if err != nil {
+		t.Fatalf("unable to dry run package: %v", err)
+	}
+	if diff.HasChanges() {
+		t.Fatalf("expected no changes after applying the package, got %+v", diff)
+	}
+
+	applied, err := env.svc.CreatePkg(env.ctx, org2.ID, exported.Metadata)
+	if err != nil {
+		t.Fatalf("unable to create package: %v", err)
+	}
+	if !reflect.DeepEqual(applied.Spec, exported.Spec) {
+		t.Errorf("unexpected resources after apply:\ngot  %+v\nexp %+v", applied.Spec, exported.Spec)
+	}
+}
+
+func TestService_ApplyUpdates(t *testing.T) {
+	env := newTestEnv(t)
+	org := env.createOrg(t, "org")
+	env.populate(t, org.ID)
+
+	const pkgYAML = `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: buckets
+  pkgVersion: "2"
+spec:
+  resources:
+    - kind: Label
+      name: team
+      color: "#326BBA"
+    - kind: Label
+      name: ops
+    - kind: Bucket
+      name: metrics
+      retentionPeriod: 2w
+      associations:
+        - kind: Label
+          name: ops
+        - kind: Label
+          name: team
+    - kind: Bucket
+      name: logs
+`
+	pkg, err := pkger.Parse(pkger.EncodingYAML, bytes.NewReader([]byte(pkgYAML)))
+	if err != nil {
+		t.Fatalf("unable to parse package: %v", err)
+	}
+
+	diff, err := env.svc.Apply(env.ctx, org.ID, pkg)
+	if err != nil {
+		t.Fatalf("unable to apply package: %v", err)
+	}
+
+	type entry struct {
+		kind   pkger.Kind
+		name   string
+		change pkger.Change
+		fields []string
+	}
+	exp := []entry{
+		{kind: pkger.KindLabel, name: "ops", change: pkger.ChangeCreate},
+		{kind: pkger.KindLabel, name: "team", change: pkger.ChangeNone},
+		{kind: pkger.KindBucket, name: "logs", change: pkger.ChangeCreate},
+		{kind: pkger.KindBucket, name: "metrics", change: pkger.ChangeUpdate, fields: []string{"associations", "retentionPeriod"}},
+	}
+	var got []entry
+	for _, e := range diff {
+		if !e.ID.Valid() {
+			t.Errorf("%s %q has no id", e.Kind, e.Name)
+		}
+		got = append(got, entry{kind: e.Kind, name: e.Name, change: e.Change, fields: e.Fields})
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected diff:\ngot  %+v\nexp %+v", got, exp)
+	}
+
+	name := "metrics"
+	b, err := env.kv.FindBucket(env.ctx, influxdb.BucketFilter{OrganizationID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatalf("unable to find bucket: %v", err)
+	}
+	if got, exp := b.RetentionPeriod, 14*24*time.Hour; got != exp {
+		t.Errorf("unexpected retention period: got %v, exp %v", got, exp)
+	}
+	labels, err := env.kv.FindResourceLabels(env.ctx, influxdb.LabelMappingFilter{ResourceID: b.ID, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatalf("unable to find bucket labels: %v", err)
+	}
+	if got, exp := len(labels), 2; got != exp {
+		t.Errorf("unexpected number of bucket labels: got %d, exp %d", got, exp)
+	}
+
+	// Resources that are not in the package are left untouched.
+	if _, err := env.kv.FindVariables(env.ctx, influxdb.VariableFilter{OrganizationID: &org.ID}); err != nil {
+		t.Errorf("unable to find variables: %v", err)
+	}
+}
+
+func TestService_DryRun_UnknownReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+	}{
+		{
+			name: "unknown label",
+			pkg: `apiVersion: 0.1.0
+kind: Package
+meta: {pkgName: p, pkgVersion: "1"}
+spec:
+  resources:
+    - kind: Bucket
+      name: metrics
+      associations: [{kind: Label, name: missing}]
+`,
+		},
+		{
+			name: "unknown notification endpoint",
+			pkg: `apiVersion: 0.1.0
+kind: Package
+meta: {pkgName: p, pkgVersion: "1"}
+spec:
+  resources:
+    - kind: NotificationRule
+      name: page
+      type: http
+      endpointName: missing
+      status: active
+      every: 10m
+      statusRules: [{currentLevel: CRIT}]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			org := env.createOrg(t, "org")
+
+			pkg, err := pkger.Parse(pkger.EncodingYAML, bytes.NewReader([]byte(tt.pkg)))
+			if err != nil {
+				t.Fatalf("unable to parse package: %v", err)
+			}
+			_, err = env.svc.DryRun(env.ctx, org.ID, pkg)
+			if got, exp := influxdb.ErrorCode(err), influxdb.EInvalid; got != exp {
+				t.Fatalf("unexpected error code: got %q, exp %q: %v", got, exp, err)
+			}
+		})
+	}
+}
@@ -58,6 +58,7 @@ type Controller struct {
 	abort      chan struct{}
 
 	memoryBytesQuotaPerQuery int64
+	orgLimiter               OrgLimiter
 
 	metrics   *controllerMetrics
 	labelKeys []string
@@ -86,6 +87,20 @@ type Config struct {
 	MetricLabelKeys []string
 
 	ExecutorDependencies execute.Dependencies
+
+	// OrgLimiter limits the queries of each organization, queries are not
+	// limited by organization if it is nil.
+	OrgLimiter OrgLimiter
+}
+
+// OrgLimiter admits the queries of an organization within its quotas.
+type OrgLimiter interface {
+	// AcquireQuery admits a query of the organization, the returned function
+	// is called once the query is done.
+	AcquireQuery(orgID influxdb.ID) (func(), error)
+	// QueryMemoryBytes returns the maximum number of bytes a query of the
+	// organization is allowed to use, 0 means no limit.
+	QueryMemoryBytes(orgID influxdb.ID) int64
 }
 
 func (c *Config) Validate() error {
@@ -122,6 +137,7 @@ func New(c Config) (*Controller, error) {
 		done:                     make(chan struct{}),
 		abort:                    make(chan struct{}),
 		memoryBytesQuotaPerQuery: c.MemoryBytesQuotaPerQuery,
+		orgLimiter:               c.OrgLimiter,
 		logger:                   logger,
 		metrics:                  newControllerMetrics(c.MetricLabelKeys),
 		labelKeys:                c.MetricLabelKeys,
@@ -157,7 +173,7 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 // query submits a query for execution returning immediately.
 // Done must be called on any returned Query objects.
 func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
-	q, err := c.createQuery(ctx, compiler.CompilerType())
+	q, err := c.createQuery(ctx, compiler)
 	if err != nil {
 		return nil, handleFluxError(err)
 	}
@@ -177,7 +193,7 @@ func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Qu
 	return q, nil
 }
 
-func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Query, error) {
+func (c *Controller) createQuery(ctx context.Context, compiler flux.Compiler) (*Query, error) {
 	c.queriesMu.RLock()
 	if c.shutdown {
 		c.queriesMu.RUnlock()
@@ -185,6 +201,11 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 	}
 	c.queriesMu.RUnlock()
 
+	release, memoryBytesQuota, err := c.admitQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	id := c.nextID()
 	labelValues := make([]string, len(c.labelKeys))
 	compileLabelValues := make([]string, len(c.labelKeys)+1)
@@ -200,7 +221,7 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		labelValues[i] = str
 		compileLabelValues[i] = str
 	}
-	compileLabelValues[len(compileLabelValues)-1] = string(ct)
+	compileLabelValues[len(compileLabelValues)-1] = string(compiler.CompilerType())
 
 	cctx, cancel := context.WithCancel(ctx)
 	parentSpan, parentCtx := StartSpanFromContext(
@@ -220,6 +241,11 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		parentSpan:         parentSpan,
 		cancel:             cancel,
 		doneCh:             make(chan struct{}),
+		alloc:              &memory.Allocator{Limit: &memoryBytesQuota},
+		release:            release,
+		request:            query.RequestFromContext(ctx),
+		compiler:           compiler,
+		startedAt:          parentSpan.start,
 	}
 
 	// Lock the queries mutex for the rest of this method.
@@ -234,12 +260,33 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 			Msg:  "query controller shutdown",
 		}
 		q.setErr(err)
+		q.release()
 		return nil, err
 	}
 	c.queries[id] = q
 	return q, nil
 }
 
+// admitQuery admits the query of the request on ctx within the quotas of its
+// organization. It returns the function releasing the quotas and the memory
+// quota of the query.
+func (c *Controller) admitQuery(ctx context.Context) (func(), int64, error) {
+	req := query.RequestFromContext(ctx)
+	if c.orgLimiter == nil || req == nil {
+		return func() {}, c.memoryBytesQuotaPerQuery, nil
+	}
+
+	release, err := c.orgLimiter.AcquireQuery(req.OrganizationID)
+	if err != nil {
+		return nil, 0, err
+	}
+	memoryBytesQuota := c.memoryBytesQuotaPerQuery
+	if n := c.orgLimiter.QueryMemoryBytes(req.OrganizationID); n > 0 && n < memoryBytesQuota {
+		memoryBytesQuota = n
+	}
+	return release, memoryBytesQuota, nil
+}
+
 func (c *Controller) nextID() QueryID {
 	nextID := atomic.AddUint64(&c.lastID, 1)
 	return QueryID(nextID)
@@ -353,8 +400,6 @@ func (c *Controller) executeQuery(q *Query) {
 		return
 	}
 
-	q.alloc = new(memory.Allocator)
-	q.alloc.Limit = func(v int64) *int64 { return &v }(c.memoryBytesQuotaPerQuery)
 	exec, err := q.program.Start(ctx, q.alloc)
 	if err != nil {
 		q.setErr(err)
@@ -365,6 +410,8 @@ func (c *Controller) executeQuery(q *Query) {
 }
 
 func (c *Controller) finish(q *Query) {
+	q.release()
+
 	c.queriesMu.Lock()
 	delete(c.queries, q.id)
 	if len(c.queries) == 0 && c.shutdown {
@@ -460,6 +507,15 @@ type Query struct {
 	exec    flux.Query
 	results chan flux.Result
 	alloc   *memory.Allocator
+
+	// release releases the quotas of the organization of the query.
+	release func()
+
+	// request is the request of the query, nil if the query was not
+	// submitted through Controller.Query.
+	request   *query.Request
+	compiler  flux.Compiler
+	startedAt time.Time
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -19,6 +19,8 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/limits"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/prometheus/client_golang/prometheus"
@@ -716,6 +718,171 @@ func TestController_ConcurrencyQuota(t *testing.T) {
 	}
 }
 
+func TestController_OrgLimiter(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.QueueSize = 2
+	config.OrgLimiter = limits.NewLimiter(limits.Config{
+		QueryConcurrency: 1,
+		QueryMemoryBytes: int(config.MemoryBytesQuotaPerQuery / 2),
+	})
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					// The memory quota of the organization is lower than the quota of the controller.
+					if err := alloc.Allocate(int(config.MemoryBytesQuotaPerQuery/2 + 1)); err != nil {
+						q.SetErr(err)
+					}
+				},
+			}, nil
+		},
+	}
+	req := makeRequest(compiler)
+	req.OrganizationID = 1
+
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The organization already has a running query.
+	_, err = ctrl.Query(context.Background(), req)
+	if code := influxdb.ErrorCode(err); code != influxdb.ETooManyRequests {
+		t.Fatalf("expected a too many requests error, got %v", err)
+	}
+	if _, ok := influxdb.RetryAfter(err); !ok {
+		t.Fatalf("expected the error to have a retry after duration")
+	}
+
+	// Queries of other organizations are not limited.
+	other := makeRequest(mockCompiler)
+	other.OrganizationID = 2
+	oq, err := ctrl.Query(context.Background(), other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range oq.Results() {
+		// discard the results
+	}
+	oq.Done()
+	if err := oq.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+	if q.Err() == nil {
+		t.Fatal("expected error about memory limit exceeded")
+	}
+
+	// The quota is released once the query is done.
+	q, err = ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+}
+
+func TestController_RunningQueries(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					if err := alloc.Allocate(64); err != nil {
+						q.SetErr(err)
+						return
+					}
+					close(executing)
+					<-ctx.Done()
+				},
+			}, nil
+		},
+	}
+	req := makeRequest(compiler)
+	req.OrganizationID = 1
+	req.Authorization = &influxdb.Authorization{ID: 2, UserID: 3}
+
+	ctx := context.Background()
+	q, err := ctrl.Query(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+	<-executing
+
+	orgID := influxdb.ID(1)
+	rqs, err := ctrl.FindRunningQueries(ctx, query.RunningQueryFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 1 {
+		t.Fatalf("expected 1 running query, got %d", len(rqs))
+	}
+	rq := rqs[0]
+	if rq.OrganizationID != 1 || rq.AuthorizationID != 2 || rq.UserID != 3 {
+		t.Errorf("unexpected scope of running query %+v", rq)
+	}
+	if rq.State != "executing" {
+		t.Errorf("expected running query to be executing, got %q", rq.State)
+	}
+	if rq.MemoryAllocated != 64 {
+		t.Errorf("expected running query to have allocated 64 bytes, got %d", rq.MemoryAllocated)
+	}
+	if rq.Elapsed <= 0 || rq.StartedAt.IsZero() {
+		t.Errorf("expected running query to have started, got %+v", rq)
+	}
+
+	otherID := influxdb.ID(4)
+	rqs, err = ctrl.FindRunningQueries(ctx, query.RunningQueryFilter{OrganizationID: &otherID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 0 {
+		t.Fatalf("expected no running queries of another organization, got %d", len(rqs))
+	}
+
+	if err := ctrl.CancelRunningQuery(ctx, rq.ID); err != nil {
+		t.Fatal(err)
+	}
+	rq, err = ctrl.FindRunningQueryByID(ctx, rq.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rq.State != "canceled" {
+		t.Fatalf("expected query to be canceled, got %q", rq.State)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if _, err := ctrl.FindRunningQueryByID(ctx, rq.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected finished query to not be found, got %v", err)
+	}
+	if err := ctrl.CancelRunningQuery(ctx, rq.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected canceling a finished query to fail, got %v", err)
+	}
+}
+
 func TestController_QueueSize(t *testing.T) {
 	const (
 		concurrencyQuota = 2
@@ -0,0 +1,94 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/querylog"
+)
+
+var _ query.RunningQueryService = (*Controller)(nil)
+
+// FindRunningQueries returns the active queries matching the filter, ordered
+// by ID.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+	rqs := []*query.RunningQuery{}
+	for _, q := range c.Queries() {
+		rq := q.running()
+		if filter.OrganizationID != nil && rq.OrganizationID != *filter.OrganizationID {
+			continue
+		}
+		rqs = append(rqs, rq)
+	}
+
+	sort.Slice(rqs, func(i, j int) bool {
+		return rqs[i].ID < rqs[j].ID
+	})
+	return rqs, nil
+}
+
+// FindRunningQueryByID returns the active query with the ID.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	return q.running(), nil
+}
+
+// CancelRunningQuery cancels the active query with the ID.
+func (c *Controller) CancelRunningQuery(ctx context.Context, id uint64) error {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return err
+	}
+	q.Cancel()
+	return nil
+}
+
+func (c *Controller) findQuery(id uint64) (*Query, error) {
+	c.queriesMu.RLock()
+	q, ok := c.queries[QueryID(id)]
+	c.queriesMu.RUnlock()
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "query not found",
+		}
+	}
+	return q, nil
+}
+
+// running reports the query as a running query.
+func (q *Query) running() *query.RunningQuery {
+	rq := &query.RunningQuery{
+		ID:              uint64(q.id),
+		CompilerType:    string(q.compiler.CompilerType()),
+		Query:           querylog.QueryText(q.compiler),
+		State:           q.State().String(),
+		StartedAt:       q.startedAt,
+		Elapsed:         time.Since(q.startedAt),
+		MemoryAllocated: q.alloc.Allocated(),
+	}
+	if req := q.request; req != nil {
+		rq.OrganizationID = req.OrganizationID
+		if a := req.Authorization; a != nil {
+			rq.AuthorizationID = a.ID
+			rq.UserID = a.UserID
+		}
+	}
+
+	q.stateMu.RLock()
+	rq.QueueDuration = q.stats.QueueDuration
+	if q.state == Queueing && q.currentSpan != nil {
+		rq.QueueDuration += time.Since(q.currentSpan.start)
+	}
+	if isFinishedState(q.state) && q.stats.TotalDuration > 0 {
+		rq.Elapsed = q.stats.TotalDuration
+	}
+	q.stateMu.RUnlock()
+	return rq
+}
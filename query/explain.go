@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"time"
+)
+
+// Explanation is the plan of a query, annotated with the profile of its
+// execution when the query is analyzed.
+type Explanation struct {
+	// Logical and Physical are the nodes of the logical and physical plans
+	// of the query, ordered from the sources to the results.
+	Logical  []*PlanNode `json:"logical"`
+	Physical []*PlanNode `json:"physical"`
+	// Rules are the rewrite rules applied while planning the query, in order
+	// of application.
+	Rules []*PlanRule `json:"rules"`
+}
+
+// PlanNode is a node of the plan of a query.
+type PlanNode struct {
+	ID           string   `json:"id"`
+	Kind         string   `json:"kind"`
+	Predecessors []string `json:"predecessors,omitempty"`
+	// Details describes the procedure of the node, if it can.
+	Details string `json:"details,omitempty"`
+	// Profile is set on the nodes of the physical plan of analyzed queries.
+	Profile *PlanNodeProfile `json:"profile,omitempty"`
+}
+
+// PlanRule is the application of a rewrite rule to a node of a plan.
+type PlanRule struct {
+	Name string `json:"name"`
+	// Phase is either "logical" or "physical".
+	Phase string `json:"phase"`
+	// Node is the ID of the node the rule rewrote and Result the ID of the
+	// node it was rewritten to.
+	Node   string `json:"node"`
+	Result string `json:"result"`
+}
+
+// PlanNodeProfile is the profile of the execution of a node of a plan.
+type PlanNodeProfile struct {
+	// Tables and Rows are the number of tables and rows produced by the node,
+	// and Bytes the size of their values.
+	Tables int64 `json:"tables"`
+	Rows   int64 `json:"rows"`
+	Bytes  int64 `json:"bytes"`
+	// FirstTable and Finished are the durations from the start of the
+	// execution to the first table produced by the node and to the end of
+	// the node. Both include the time spent in the predecessors of the node.
+	FirstTable time.Duration `json:"firstTable"`
+	Finished   time.Duration `json:"finished"`
+	// ScannedValues and ScannedBytes are the statistics of the storage
+	// cursors read by the node, as reported to the query metadata.
+	ScannedValues int `json:"scannedValues,omitempty"`
+	ScannedBytes  int `json:"scannedBytes,omitempty"`
+}
+
+// ExplainService explains the plans of queries.
+type ExplainService interface {
+	// Explain returns the plan of the query of req. When analyze is true, the
+	// query is executed and its plan annotated with the profile of the
+	// execution.
+	Explain(ctx context.Context, req *Request, analyze bool) (*Explanation, error)
+}
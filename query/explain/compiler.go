@@ -0,0 +1,302 @@
+// Package explain explains the plans of Flux queries and profiles their
+// execution.
+package explain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/query"
+)
+
+// CompilerType is the type of the compilers explaining queries.
+const CompilerType = "explain"
+
+// Compiler wraps the compiler of a Flux query to explain its plan. The
+// program it compiles does not return any result, it executes the plan and
+// profiles the execution only if the query is analyzed.
+type Compiler struct {
+	ast     *ast.Package
+	now     time.Time
+	analyze bool
+
+	// explanation is set once the program has started.
+	explanation *query.Explanation
+	profiles    []*profile
+}
+
+// NewCompiler returns a Compiler explaining the query of c, either a
+// lang.FluxCompiler or a lang.ASTCompiler.
+func NewCompiler(c flux.Compiler, analyze bool) (*Compiler, error) {
+	ec := &Compiler{analyze: analyze}
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		return ec.setFlux(c)
+	case *lang.FluxCompiler:
+		return ec.setFlux(*c)
+	case lang.ASTCompiler:
+		ec.ast, ec.now = c.AST, c.Now
+	case *lang.ASTCompiler:
+		ec.ast, ec.now = c.AST, c.Now
+	default:
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("cannot explain queries of compiler type %q", c.CompilerType()),
+		}
+	}
+	if ec.ast == nil {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "no query to explain",
+		}
+	}
+	return ec, nil
+}
+
+func (c *Compiler) setFlux(fc lang.FluxCompiler) (*Compiler, error) {
+	pkg, err := flux.Parse(fc.Query)
+	if err != nil {
+		return nil, err
+	}
+	if fc.Extern != nil {
+		pkg.Files = append([]*ast.File{fc.Extern}, pkg.Files...)
+	}
+	c.ast, c.now = pkg, fc.Now
+	return c, nil
+}
+
+// Compile returns the program explaining the query.
+func (c *Compiler) Compile(ctx context.Context) (flux.Program, error) {
+	return &program{
+		Program:  &lang.Program{},
+		compiler: c,
+	}, nil
+}
+
+// CompilerType returns CompilerType, so that explained queries are told
+// apart from the queries they explain.
+func (c *Compiler) CompilerType() flux.CompilerType {
+	return CompilerType
+}
+
+// Explanation returns the explanation of the query. The profile of an analyzed
+// query is complete once the query is done.
+func (c *Compiler) Explanation() *query.Explanation {
+	for _, p := range c.profiles {
+		p.finalize()
+	}
+	return c.explanation
+}
+
+// program plans the query and, if it is analyzed, executes it with the
+// lang.Program it wraps.
+type program struct {
+	*lang.Program
+	compiler *Compiler
+}
+
+// Start plans the query and executes the plan if the query is analyzed.
+func (p *program) Start(ctx context.Context, alloc *memory.Allocator) (flux.Query, error) {
+	deps, ok := p.Dependencies[dependencies.InterpreterDepsKey].(dependencies.Interface)
+	if !ok {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "no interpreter dependencies found",
+		}
+	}
+
+	now := p.compiler.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	spec, err := buildSpec(ctx, deps, p.compiler.ast, now)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &query.Explanation{}
+	lp := plan.NewLogicalPlanner(plan.OnlyLogicalRules(traceRules(logicalPhase, &e.Rules, logicalRules)...))
+	ps, err := lp.CreateInitialPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	if ps, err = lp.Plan(ps); err != nil {
+		return nil, err
+	}
+	// The physical planner rewrites the nodes of the logical plan, describe
+	// them beforehand.
+	e.Logical, _, err = planNodes(ps)
+	if err != nil {
+		return nil, err
+	}
+
+	pp := plan.NewPhysicalPlanner(plan.OnlyPhysicalRules(traceRules(physicalPhase, &e.Rules, physicalRules)...))
+	if ps, err = pp.Plan(ps); err != nil {
+		return nil, err
+	}
+	physical, nodes, err := planNodes(ps)
+	if err != nil {
+		return nil, err
+	}
+	e.Physical = physical
+	p.compiler.explanation = e
+
+	if !p.compiler.analyze {
+		return newEmptyQuery(), nil
+	}
+	p.compiler.profiles = profilePlan(ps, nodes, time.Now())
+	p.PlanSpec = ps
+	return p.Program.Start(ctx, alloc)
+}
+
+// planNodes describes the nodes of ps, ordered from the sources to the
+// results, and returns the descriptions by node.
+func planNodes(ps *plan.Spec) ([]*query.PlanNode, map[plan.Node]*query.PlanNode, error) {
+	var nodes []*query.PlanNode
+	byNode := make(map[plan.Node]*query.PlanNode)
+	err := ps.BottomUpWalk(func(n plan.Node) error {
+		pn := &query.PlanNode{
+			ID:   string(n.ID()),
+			Kind: string(n.Kind()),
+		}
+		for _, pred := range n.Predecessors() {
+			pn.Predecessors = append(pn.Predecessors, string(pred.ID()))
+		}
+		if s, ok := n.ProcedureSpec().(fmt.Stringer); ok {
+			pn.Details = s.String()
+		}
+		nodes = append(nodes, pn)
+		byNode[n] = pn
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, byNode, nil
+}
+
+// buildSpec evaluates pkg and returns the spec of the table objects it yields,
+// like the lang package does when starting a program.
+func buildSpec(ctx context.Context, deps dependencies.Interface, pkg *ast.Package, now time.Time) (*flux.Spec, error) {
+	sideEffects, scope, err := flux.EvalAST(ctx, deps, pkg, flux.SetOption("universe", "now", nowFunc(now)))
+	if err != nil {
+		return nil, err
+	}
+
+	nowOpt, ok := scope.Lookup("now")
+	if !ok {
+		return nil, errors.New(`"now" option not set`)
+	}
+	nowTime, err := nowOpt.Function().Call(ctx, deps, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &flux.Spec{Now: nowTime.Time().Time()}
+	ider := &ider{lookup: make(map[*flux.TableObject]flux.OperationID)}
+	visited := make(map[*flux.TableObject]bool)
+	var objs []*flux.TableObject
+	for _, se := range sideEffects {
+		to, ok := se.Value.(*flux.TableObject)
+		if !ok || containsTableObject(objs, to) {
+			continue
+		}
+		addTableObject(spec, ider, to, visited)
+		objs = append(objs, to)
+	}
+
+	if len(spec.Operations) == 0 {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "this Flux script returns no streaming data",
+		}
+	}
+	return spec, nil
+}
+
+func nowFunc(now time.Time) values.Function {
+	v := values.NewTime(values.ConvertTime(now))
+	typ := semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Return: semantic.Time,
+	})
+	return values.NewFunction("now", typ, func(ctx context.Context, deps dependencies.Interface, args values.Object) (values.Value, error) {
+		return v, nil
+	}, false)
+}
+
+func containsTableObject(objs []*flux.TableObject, to *flux.TableObject) bool {
+	for _, o := range objs {
+		if o.Equal(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// addTableObject adds the operations of to and its unvisited ancestors to
+// spec, the ancestors first.
+func addTableObject(spec *flux.Spec, ider *ider, to *flux.TableObject, visited map[*flux.TableObject]bool) {
+	to.Parents.Range(func(i int, v values.Value) {
+		if p := v.(*flux.TableObject); !visited[p] {
+			addTableObject(spec, ider, p, visited)
+		}
+	})
+
+	id := ider.ID(to)
+	to.Parents.Range(func(i int, v values.Value) {
+		spec.Edges = append(spec.Edges, flux.Edge{
+			Parent: ider.ID(v.(*flux.TableObject)),
+			Child:  id,
+		})
+	})
+
+	visited[to] = true
+	spec.Operations = append(spec.Operations, to.Operation(ider))
+}
+
+// ider assigns sequential operation IDs to table objects.
+type ider struct {
+	next   int
+	lookup map[*flux.TableObject]flux.OperationID
+}
+
+func (i *ider) ID(to *flux.TableObject) flux.OperationID {
+	id, ok := i.lookup[to]
+	if !ok {
+		id = flux.OperationID(fmt.Sprintf("%s%d", to.Kind, i.next))
+		i.next++
+		i.lookup[to] = id
+	}
+	return id
+}
+
+// emptyQuery is a query without results.
+type emptyQuery struct {
+	results chan flux.Result
+}
+
+func newEmptyQuery() *emptyQuery {
+	results := make(chan flux.Result)
+	close(results)
+	return &emptyQuery{results: results}
+}
+
+func (q *emptyQuery) Results() <-chan flux.Result { return q.results }
+func (q *emptyQuery) Done()                       {}
+func (q *emptyQuery) Cancel()                     {}
+func (q *emptyQuery) Err() error                  { return nil }
+
+func (q *emptyQuery) Statistics() flux.Statistics {
+	return flux.Statistics{}
+}
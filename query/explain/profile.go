@@ -0,0 +1,289 @@
+package explain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// ProfileKind is the kind of the procedures profiling the tables produced by
+// the nodes of analyzed plans.
+const ProfileKind = "influxdata/influxdb/explain.profile"
+
+func init() {
+	execute.RegisterTransformation(ProfileKind, createProfileTransformation)
+}
+
+// profile accumulates the profile of a plan node while its plan executes.
+type profile struct {
+	start time.Time
+
+	mu    sync.Mutex
+	p     *query.PlanNodeProfile
+	first time.Time
+	end   time.Time
+	stats cursors.CursorStats
+}
+
+func (p *profile) addTable() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.first.IsZero() {
+		p.first = time.Now()
+	}
+	p.p.Tables++
+}
+
+func (p *profile) finished() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.end = time.Now()
+}
+
+func (p *profile) add(rows, bytes int64, stats cursors.CursorStats) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.p.Rows += rows
+	p.p.Bytes += bytes
+	p.stats.Add(stats)
+}
+
+// finalize sets the durations and the cursor statistics of the profile.
+func (p *profile) finalize() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.first.IsZero() {
+		p.p.FirstTable = p.first.Sub(p.start)
+	}
+	if !p.end.IsZero() {
+		p.p.Finished = p.end.Sub(p.start)
+	}
+	p.p.ScannedValues = p.stats.ScannedValues
+	p.p.ScannedBytes = p.stats.ScannedBytes
+}
+
+// profilePlan inserts a profile node after each node of ps whose tables are
+// consumed by other nodes, and sets the profile of the described nodes. The
+// durations of the profiles are relative to start.
+func profilePlan(ps *plan.Spec, described map[plan.Node]*query.PlanNode, start time.Time) []*profile {
+	var nodes []plan.Node
+	_ = ps.BottomUpWalk(func(n plan.Node) error {
+		if _, ok := n.ProcedureSpec().(plan.YieldProcedureSpec); !ok && len(n.Successors()) > 0 {
+			nodes = append(nodes, n)
+		}
+		return nil
+	})
+
+	profiles := make([]*profile, 0, len(nodes))
+	for _, n := range nodes {
+		p := &profile{
+			start: start,
+			p:     &query.PlanNodeProfile{},
+		}
+		if pn, ok := described[n]; ok {
+			pn.Profile = p.p
+		}
+		insertProfileNode(n, p)
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// insertProfileNode inserts a profile node between n and its successors.
+func insertProfileNode(n plan.Node, p *profile) {
+	pn := plan.CreatePhysicalNode(n.ID()+"_profile", &ProfileProcedureSpec{profile: p})
+	pn.SetBounds(n.Bounds())
+
+	// ClearSuccessors reuses the slice of the successors.
+	succs := append([]plan.Node(nil), n.Successors()...)
+	n.ClearSuccessors()
+	n.AddSuccessors(pn)
+	pn.AddPredecessors(n)
+	for _, s := range succs {
+		preds := s.Predecessors()
+		for i := range preds {
+			if preds[i] == n {
+				preds[i] = pn
+			}
+		}
+		pn.AddSuccessors(s)
+	}
+}
+
+// ProfileProcedureSpec is the procedure profiling the tables of its
+// predecessor.
+type ProfileProcedureSpec struct {
+	plan.DefaultCost
+	profile *profile
+}
+
+func (s *ProfileProcedureSpec) Kind() plan.ProcedureKind {
+	return ProfileKind
+}
+
+func (s *ProfileProcedureSpec) Copy() plan.ProcedureSpec {
+	return &ProfileProcedureSpec{profile: s.profile}
+}
+
+func createProfileTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ProfileProcedureSpec)
+	if !ok {
+		return nil, nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("invalid spec type %T", spec),
+		}
+	}
+	d := &profileDataset{
+		id:      id,
+		profile: s.profile,
+	}
+	return &profileTransformation{d: d}, d, nil
+}
+
+// profileTransformation passes the tables of its predecessor to its dataset.
+type profileTransformation struct {
+	d *profileDataset
+}
+
+func (t *profileTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *profileTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	return t.d.process(tbl)
+}
+
+func (t *profileTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *profileTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *profileTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// profileDataset forwards the tables of its predecessor to its successors
+// and profiles them when they are read.
+type profileDataset struct {
+	id      execute.DatasetID
+	profile *profile
+	ts      []execute.Transformation
+}
+
+func (d *profileDataset) AddTransformation(t execute.Transformation) {
+	d.ts = append(d.ts, t)
+}
+
+func (d *profileDataset) SetTriggerSpec(plan.TriggerSpec) {}
+
+func (d *profileDataset) process(tbl flux.Table) error {
+	d.profile.addTable()
+	pt := &profiledTable{Table: tbl, profile: d.profile}
+	if len(d.ts) == 1 {
+		return d.ts[0].Process(d.id, pt)
+	}
+
+	// Copy the table for each successor, the table is read once.
+	buf, err := execute.CopyTable(pt)
+	if err != nil {
+		return err
+	}
+	defer buf.Done()
+	for _, t := range d.ts {
+		if err := t.Process(d.id, buf.Copy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *profileDataset) RetractTable(key flux.GroupKey) error {
+	for _, t := range d.ts {
+		if err := t.RetractTable(d.id, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *profileDataset) UpdateWatermark(mark execute.Time) error {
+	for _, t := range d.ts {
+		if err := t.UpdateWatermark(d.id, mark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *profileDataset) UpdateProcessingTime(pt execute.Time) error {
+	for _, t := range d.ts {
+		if err := t.UpdateProcessingTime(d.id, pt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *profileDataset) Finish(err error) {
+	d.profile.finished()
+	for _, t := range d.ts {
+		t.Finish(d.id, err)
+	}
+}
+
+// statisticsTable is a table read from storage cursors.
+type statisticsTable interface {
+	Statistics() cursors.CursorStats
+}
+
+// profiledTable counts the rows and the bytes of the table it wraps when it
+// is read.
+type profiledTable struct {
+	flux.Table
+	profile *profile
+}
+
+func (t *profiledTable) Do(f func(flux.ColReader) error) error {
+	var (
+		rows, bytes int64
+		stats       cursors.CursorStats
+	)
+	st, hasStats := t.Table.(statisticsTable)
+	err := t.Table.Do(func(cr flux.ColReader) error {
+		rows += int64(cr.Len())
+		bytes += colReaderBytes(cr)
+		// The statistics of a table are only available while it is read.
+		if hasStats {
+			stats = st.Statistics()
+		}
+		return f(cr)
+	})
+	t.profile.add(rows, bytes, stats)
+	return err
+}
+
+// colReaderBytes returns the size of the values of cr.
+func colReaderBytes(cr flux.ColReader) int64 {
+	var n int64
+	for j, c := range cr.Cols() {
+		switch c.Type {
+		case flux.TBool:
+			n += int64(cr.Len())
+		case flux.TString:
+			n += int64(len(cr.Strings(j).ValueBytes()))
+		default:
+			n += 8 * int64(cr.Len())
+		}
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package explain
+
+import (
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/experimental/bigtable"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+// The registered rules of the planner cannot be listed, these are the rules
+// registered by the packages of the standard library and must be kept in sync
+// with them.
+var (
+	logicalRules = []plan.Rule{
+		universe.MergeGroupRule{},
+	}
+	physicalRules = []plan.Rule{
+		universe.RemoveTrivialFilterRule{},
+		universe.WindowTriggerPhysicalRule{},
+		bigtable.BigtableFilterRewriteRule{},
+		bigtable.BigtableLimitRewriteRule{},
+		influxdb.PushDownRangeRule{},
+		influxdb.PushDownFilterRule{},
+		influxdb.PushDownGroupRule{},
+		influxdb.PushDownReadTagKeysRule{},
+		influxdb.PushDownReadTagValuesRule{},
+		influxdb.PushDownWindowAggregateRule{},
+	}
+)
+
+const (
+	logicalPhase  = "logical"
+	physicalPhase = "physical"
+)
+
+// tracedRule wraps a plan.Rule and records its applications.
+type tracedRule struct {
+	plan.Rule
+	phase   string
+	applied *[]*query.PlanRule
+}
+
+// traceRules wraps rules so that their applications during the phase are
+// appended to applied.
+func traceRules(phase string, applied *[]*query.PlanRule, rules []plan.Rule) []plan.Rule {
+	traced := make([]plan.Rule, len(rules))
+	for i, r := range rules {
+		traced[i] = &tracedRule{
+			Rule:    r,
+			phase:   phase,
+			applied: applied,
+		}
+	}
+	return traced
+}
+
+// Rewrite rewrites node with the wrapped rule and records it if node changed.
+func (r *tracedRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	id := node.ID()
+	n, changed, err := r.Rule.Rewrite(node)
+	if err != nil || !changed {
+		return n, changed, err
+	}
+
+	*r.applied = append(*r.applied, &query.PlanRule{
+		Name:   r.Name(),
+		Phase:  r.phase,
+		Node:   string(id),
+		Result: string(n.ID()),
+	})
+	return n, changed, nil
+}
@@ -0,0 +1,156 @@
+package explain
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query"
+)
+
+// TestRules_Registered checks that the rules of the explained plans are the
+// registered rules by comparing the plans of queries to those of the default
+// planner.
+func TestRules_Registered(t *testing.T) {
+	queries := []string{
+		`from(bucket: "b") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "m")`,
+		`from(bucket: "b") |> range(start: -1h) |> group(columns: ["t"]) |> group(columns: ["u"])`,
+		`from(bucket: "b") |> range(start: -1h) |> window(every: 1m) |> count()`,
+		`from(bucket: "b") |> range(start: -1h) |> keys()`,
+	}
+	for _, q := range queries {
+		t.Run(q, func(t *testing.T) {
+			pkg, err := flux.Parse(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec, err := buildSpec(context.Background(), dependenciestest.Default(), pkg, time.Unix(0, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			registered, err := plan.PlannerBuilder{}.Build().Plan(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _, err := planNodes(registered)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var applied []*query.PlanRule
+			lp := plan.NewLogicalPlanner(plan.OnlyLogicalRules(traceRules(logicalPhase, &applied, logicalRules)...))
+			ps, err := lp.CreateInitialPlan(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ps, err = lp.Plan(ps); err != nil {
+				t.Fatal(err)
+			}
+			pp := plan.NewPhysicalPlanner(plan.OnlyPhysicalRules(traceRules(physicalPhase, &applied, physicalRules)...))
+			if ps, err = pp.Plan(ps); err != nil {
+				t.Fatal(err)
+			}
+			nodes, _, err := planNodes(ps)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(nodeKinds(want), nodeKinds(nodes)); diff != "" {
+				t.Errorf("unexpected plan -registered/+explained\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestRules_PushDowns checks that the push down rules registered by the
+// influxdb package are listed, by parsing the calls registering them.
+func TestRules_PushDowns(t *testing.T) {
+	const pkgPath = "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "../stdlib/influxdata/influxdb", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var registered []string
+	for _, pkg := range pkgs {
+		ast.Inspect(pkg, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "RegisterPhysicalRules" && sel.Sel.Name != "RegisterLogicalRules") {
+				return true
+			}
+			for _, arg := range call.Args {
+				lit, ok := arg.(*ast.CompositeLit)
+				if !ok {
+					t.Fatalf("cannot find the type of the rule registered at %s", fset.Position(arg.Pos()))
+				}
+				id, ok := lit.Type.(*ast.Ident)
+				if !ok {
+					t.Fatalf("cannot find the type of the rule registered at %s", fset.Position(arg.Pos()))
+				}
+				registered = append(registered, id.Name)
+			}
+			return false
+		})
+	}
+	if len(registered) == 0 {
+		t.Fatal("found no registered rules")
+	}
+
+	listed := make(map[string]bool)
+	for _, r := range append(logicalRules, physicalRules...) {
+		if typ := reflect.TypeOf(r); typ.PkgPath() == pkgPath {
+			listed[typ.Name()] = true
+		}
+	}
+	for _, name := range registered {
+		if !listed[name] {
+			t.Errorf("registered rule %s is not listed", name)
+		}
+	}
+}
+
+// TestRules_Listed checks that the listed rules are registered, registering
+// them again panics.
+func TestRules_Listed(t *testing.T) {
+	check := func(phase string, register func(...plan.Rule), rules []plan.Rule) {
+		for _, r := range rules {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("listed %s rule %s is not registered", phase, r.Name())
+					}
+				}()
+				register(r)
+			}()
+		}
+	}
+	check(logicalPhase, plan.RegisterLogicalRules, logicalRules)
+	check(physicalPhase, plan.RegisterPhysicalRules, physicalRules)
+}
+
+func nodeKinds(nodes []*query.PlanNode) []string {
+	kinds := make([]string, len(nodes))
+	for i, n := range nodes {
+		kinds[i] = n.Kind
+	}
+	return kinds
+}
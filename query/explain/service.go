@@ -0,0 +1,57 @@
+package explain
+
+import (
+	"context"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+)
+
+var _ query.ExplainService = (*Service)(nil)
+
+// Service explains queries by running an explaining compiler with the query
+// service it wraps, so that the explained queries are subject to the same
+// queueing and limits as the other queries.
+type Service struct {
+	QueryService query.AsyncQueryService
+}
+
+// NewService returns a Service explaining queries with qs.
+func NewService(qs query.AsyncQueryService) *Service {
+	return &Service{QueryService: qs}
+}
+
+// Explain returns the plan of the query of req. When analyze is true, the
+// query is executed and its results discarded.
+func (s *Service) Explain(ctx context.Context, req *query.Request, analyze bool) (*query.Explanation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	c, err := NewCompiler(req.Compiler, analyze)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	ereq := *req
+	ereq.Compiler = c
+
+	q, err := s.QueryService.Query(ctx, &ereq)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	results := flux.NewResultIteratorFromQuery(q)
+	defer results.Release()
+	for results.More() {
+		err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(flux.ColReader) error { return nil })
+		})
+		if err != nil {
+			return nil, tracing.LogError(span, err)
+		}
+	}
+	results.Release()
+	if err := results.Err(); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return c.Explanation(), nil
+}
@@ -0,0 +1,174 @@
+package explain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/query/explain"
+	"github.com/influxdata/influxdb/query/influxql"
+	"go.uber.org/zap/zaptest"
+)
+
+const csvData = `
+#datatype,string,long,dateTime:RFC3339,double,string,string
+#group,false,false,false,false,true,true
+#default,_result,,,,,
+,result,table,_time,_value,_field,_measurement
+,,0,2019-01-01T00:00:00Z,1,f,m
+,,0,2019-01-01T00:00:10Z,2,f,m
+,,0,2019-01-01T00:00:20Z,3,f,m
+`
+
+func newService(t *testing.T) (*explain.Service, func()) {
+	t.Helper()
+	ctrl, err := control.New(control.Config{
+		ConcurrencyQuota:         1,
+		MemoryBytesQuotaPerQuery: 1 << 20,
+		QueueSize:                1,
+		ExecutorDependencies:     executetest.NewTestExecuteDependencies(),
+		Logger:                   zaptest.NewLogger(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return explain.NewService(ctrl), func() {
+		if err := ctrl.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func explainQuery(t *testing.T, q string, analyze bool) *query.Explanation {
+	t.Helper()
+	s, shutdown := newService(t)
+	defer shutdown()
+
+	e, err := s.Explain(context.Background(), &query.Request{
+		OrganizationID: influxdb.ID(1),
+		Compiler:       lang.FluxCompiler{Query: q},
+	}, analyze)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func kinds(nodes []*query.PlanNode) []string {
+	ks := make([]string, len(nodes))
+	for i, n := range nodes {
+		ks[i] = n.Kind
+	}
+	return ks
+}
+
+func ruleNames(rules []*query.PlanRule) []string {
+	var names []string
+	for _, r := range rules {
+		names = append(names, r.Phase+":"+r.Name)
+	}
+	return names
+}
+
+func TestService_Explain(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		logical  []string
+		physical []string
+		rules    []string
+	}{
+		{
+			name:     "merged groups",
+			query:    `csv.from(csv: data) |> group(columns: ["_field"]) |> group(columns: ["_measurement"])`,
+			logical:  []string{"fromCSV", "group", "generatedYield"},
+			physical: []string{"fromCSV", "group", "generatedYield"},
+			rules:    []string{"logical:MergeGroupRule"},
+		},
+		{
+			name:     "storage push downs",
+			query:    `from(bucket: "b") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "m")`,
+			logical:  []string{"influxDBFrom", "range", "filter", "generatedYield"},
+			physical: []string{"ReadRangePhysKind", "generatedYield"},
+			rules:    []string{"physical:PushDownRangeRule", "physical:PushDownFilterRule"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := explainQuery(t, `import "csv"
+data = "`+csvData+`"
+`+tt.query, false)
+			if diff := cmp.Diff(tt.logical, kinds(e.Logical)); diff != "" {
+				t.Errorf("unexpected logical plan -want/+got\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.physical, kinds(e.Physical)); diff != "" {
+				t.Errorf("unexpected physical plan -want/+got\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.rules, ruleNames(e.Rules)); diff != "" {
+				t.Errorf("unexpected rules -want/+got\n%s", diff)
+			}
+			for _, n := range e.Physical {
+				if n.Profile != nil {
+					t.Errorf("unexpected profile of node %s", n.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ExplainAnalyze(t *testing.T) {
+	e := explainQuery(t, `import "csv"
+data = "`+csvData+`"
+csv.from(csv: data) |> filter(fn: (r) => r._value > 1.0)`, true)
+
+	want := map[string]query.PlanNodeProfile{
+		"fromCSV": {Tables: 1, Rows: 3},
+		"filter":  {Tables: 1, Rows: 2},
+	}
+	for _, n := range e.Physical {
+		w, ok := want[n.Kind]
+		if !ok {
+			if n.Profile != nil {
+				t.Errorf("unexpected profile of node %s", n.ID)
+			}
+			continue
+		}
+		p := n.Profile
+		if p == nil {
+			t.Fatalf("missing profile of node %s", n.ID)
+		}
+		if p.Tables != w.Tables || p.Rows != w.Rows {
+			t.Errorf("unexpected profile of node %s: got %d tables and %d rows, want %d and %d", n.ID, p.Tables, p.Rows, w.Tables, w.Rows)
+		}
+		if p.Bytes == 0 {
+			t.Errorf("expected bytes of node %s", n.ID)
+		}
+		if p.Finished < p.FirstTable {
+			t.Errorf("node %s finished before its first table", n.ID)
+		}
+	}
+}
+
+func TestNewCompiler_CompilerType(t *testing.T) {
+	c, err := explain.NewCompiler(lang.FluxCompiler{Query: `from(bucket: "b")`}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.CompilerType(), flux.CompilerType(explain.CompilerType); got != want {
+		t.Errorf("unexpected compiler type: got %q, want %q", got, want)
+	}
+}
+
+func TestNewCompiler_Unsupported(t *testing.T) {
+	_, err := explain.NewCompiler(&influxql.Compiler{Query: "SELECT * FROM m"}, false)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -13,7 +13,15 @@ import (
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format of the timestamps; defaults to RFC3339Nano.
+	TimeFormat TimeFormat
+
+	// ChunkSize is the maximum number of values of a series in each chunk.
+	// If it is greater than zero, the results are written as a stream of JSON
+	// objects, one per chunk, instead of a single JSON object.
+	ChunkSize int
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -28,163 +36,304 @@ type MultiResultEncoder struct{}
 //      TODO(jsternberg): This function currently requires the first column to be a time field, but this isn't
 //      a strict requirement and will be lifted when we begin to work on transpiling meta queries.
 func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	if e.ChunkSize > 0 {
+		return e.encodeChunked(w, results)
+	}
+
 	resp := Response{}
 	wc := &iocounter.Writer{Writer: w}
 
 	for results.More() {
 		res := results.Next()
-		name := res.Name()
-		id, err := strconv.Atoi(name)
+		id, err := statementID(res)
 		if err != nil {
-			resp.error(fmt.Errorf("unable to parse statement id from result name: %s", err))
+			resp.error(err)
 			results.Release()
 			break
 		}
 
-		tables := res.Tables()
-
 		result := Result{StatementID: id}
-		if err := tables.Do(func(tbl flux.Table) error {
-			var row Row
-
-			for j, c := range tbl.Key().Cols() {
-				if c.Type != flux.TString {
-					// Skip any columns that aren't strings. They are extra ones that
-					// flux includes by default like the start and end times that we do not
-					// care about.
-					continue
-				}
-				v := tbl.Key().Value(j).Str()
-				if c.Label == "_measurement" {
-					row.Name = v
-				} else if c.Label == "_field" {
-					// If the field key was not removed by a previous operation, we explicitly
-					// ignore it here when encoding the result back.
-				} else {
-					if row.Tags == nil {
-						row.Tags = make(map[string]string)
-					}
-					row.Tags[c.Label] = v
-				}
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			row, err := e.encodeTable(tbl)
+			if err != nil {
+				return err
 			}
+			result.Series = append(result.Series, row)
+			return nil
+		}); err != nil {
+			resp.error(err)
+			results.Release()
+			break
+		}
+		resp.Results = append(resp.Results, result)
+	}
 
-			// TODO: resultColMap should be constructed from query metadata once it is provided.
-			// for now we know that an influxql query ALWAYS has time first, so we put this placeholder
-			// here to catch this most obvious requirement.  Column orderings should be explicitly determined
-			// from the ordering given in the original flux.
-			resultColMap := map[string]int{}
-			j := 1
-			for _, c := range tbl.Cols() {
-				if c.Label == execute.DefaultTimeColLabel {
-					resultColMap[c.Label] = 0
-				} else if !tbl.Key().HasCol(c.Label) {
-					resultColMap[c.Label] = j
-					j++
-				}
+	if err := results.Err(); err != nil && resp.Err == "" {
+		resp.error(err)
+	}
+
+	err := json.NewEncoder(wc).Encode(resp)
+	return wc.Count(), err
+}
+
+// encodeChunked writes each chunk of at most ChunkSize values of a series as
+// a separate response. Every chunk except the last one of a statement is
+// marked as partial, as is every chunk except the last one of a series.
+func (e *MultiResultEncoder) encodeChunked(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	enc := &chunkEncoder{enc: json.NewEncoder(wc)}
+
+	for results.More() {
+		res := results.Next()
+		id, err := statementID(res)
+		if err != nil {
+			results.Release()
+			enc.writeError(err)
+			return wc.Count(), enc.err
+		}
+
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			row, err := e.encodeTable(tbl)
+			if err != nil {
+				return err
 			}
 
-			if _, ok := resultColMap[execute.DefaultTimeColLabel]; !ok {
-				for k, v := range resultColMap {
-					resultColMap[k] = v - 1
+			values := row.Values
+			for i := 0; i == 0 || i < len(values); i += e.ChunkSize {
+				j := i + e.ChunkSize
+				if j > len(values) {
+					j = len(values)
 				}
-			}
 
-			row.Columns = make([]string, len(resultColMap))
-			for k, v := range resultColMap {
-				if k == execute.DefaultTimeColLabel {
-					k = "time"
+				chunk := *row
+				chunk.Values = values[i:j]
+				chunk.Partial = j < len(values)
+				if err := enc.write(Result{StatementID: id, Series: []*Row{&chunk}}); err != nil {
+					return err
 				}
-				row.Columns[v] = k
 			}
+			return nil
+		}); err != nil {
+			results.Release()
+			if enc.err == nil {
+				enc.write(Result{StatementID: id, Err: err.Error()})
+				enc.flush()
+			}
+			return wc.Count(), enc.err
+		}
 
-			if err := tbl.Do(func(cr flux.ColReader) error {
-				// Preallocate the number of rows for the response to make this section
-				// of code easier to read. Find a time column which should exist
-				// in the output.
-				values := make([][]interface{}, cr.Len())
-				for j := range values {
-					values[j] = make([]interface{}, len(row.Columns))
-				}
+		// Write the last chunk of the statement, or an empty result if the
+		// statement produced no series.
+		if enc.pending == nil {
+			enc.write(Result{StatementID: id})
+		}
+		if err := enc.flush(); err != nil {
+			results.Release()
+			return wc.Count(), err
+		}
+	}
 
-				j := 0
-				for idx, c := range tbl.Cols() {
-					if cr.Key().HasCol(c.Label) {
-						continue
-					}
+	if err := results.Err(); err != nil {
+		enc.writeError(err)
+	}
+	return wc.Count(), enc.err
+}
 
-					j = resultColMap[c.Label]
-					// Fill in the values for each column.
-					switch c.Type {
-					case flux.TFloat:
-						vs := cr.Floats(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TInt:
-						vs := cr.Ints(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TString:
-						vs := cr.Strings(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.ValueString(i)
-							}
-						}
-					case flux.TUInt:
-						vs := cr.UInts(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TBool:
-						vs := cr.Bools(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = vs.Value(i)
-							}
-						}
-					case flux.TTime:
-						vs := cr.Times(idx)
-						for i := 0; i < vs.Len(); i++ {
-							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
-							}
-						}
-					default:
-						return fmt.Errorf("unsupported column type: %s", c.Type)
-					}
+// chunkEncoder writes the results of a chunked response. The most recent
+// result is held back until the next one is written, so that it can be marked
+// as partial if more results of the same statement follow it.
+type chunkEncoder struct {
+	enc     *json.Encoder
+	pending *Result
+	err     error
+}
 
-				}
-				row.Values = append(row.Values, values...)
-				return nil
-			}); err != nil {
-				return err
+func (c *chunkEncoder) write(r Result) error {
+	if c.pending != nil {
+		c.pending.Partial = true
+		if err := c.flush(); err != nil {
+			return err
+		}
+	}
+	c.pending = &r
+	return nil
+}
+
+func (c *chunkEncoder) flush() error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.pending == nil {
+		return nil
+	}
+	c.err = c.enc.Encode(Response{Results: []Result{*c.pending}})
+	c.pending = nil
+	return c.err
+}
+
+func (c *chunkEncoder) writeError(err error) {
+	if c.flush() != nil {
+		return
+	}
+	c.err = c.enc.Encode(Response{Err: err.Error()})
+}
+
+func statementID(res flux.Result) (int, error) {
+	id, err := strconv.Atoi(res.Name())
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse statement id from result name: %s", err)
+	}
+	return id, nil
+}
+
+// encodeTable converts a table into a series of the response.
+func (e *MultiResultEncoder) encodeTable(tbl flux.Table) (*Row, error) {
+	var row Row
+
+	for j, c := range tbl.Key().Cols() {
+		if c.Type != flux.TString {
+			// Skip any columns that aren't strings. They are extra ones that
+			// flux includes by default like the start and end times that we do not
+			// care about.
+			continue
+		}
+		v := tbl.Key().Value(j).Str()
+		if c.Label == "_measurement" {
+			row.Name = v
+		} else if c.Label == "_field" {
+			// If the field key was not removed by a previous operation, we explicitly
+			// ignore it here when encoding the result back.
+		} else {
+			if row.Tags == nil {
+				row.Tags = make(map[string]string)
 			}
+			row.Tags[c.Label] = v
+		}
+	}
 
-			result.Series = append(result.Series, &row)
-			return nil
-		}); err != nil {
-			resp.error(err)
-			results.Release()
-			break
+	// TODO: resultColMap should be constructed from query metadata once it is provided.
+	// for now we know that an influxql query ALWAYS has time first, so we put this placeholder
+	// here to catch this most obvious requirement.  Column orderings should be explicitly determined
+	// from the ordering given in the original flux.
+	resultColMap := map[string]int{}
+	j := 1
+	for _, c := range tbl.Cols() {
+		if c.Label == execute.DefaultTimeColLabel {
+			resultColMap[c.Label] = 0
+		} else if !tbl.Key().HasCol(c.Label) {
+			resultColMap[c.Label] = j
+			j++
 		}
-		resp.Results = append(resp.Results, result)
 	}
 
-	if err := results.Err(); err != nil && resp.Err == "" {
-		resp.error(err)
+	if _, ok := resultColMap[execute.DefaultTimeColLabel]; !ok {
+		for k, v := range resultColMap {
+			resultColMap[k] = v - 1
+		}
 	}
 
-	err := json.NewEncoder(wc).Encode(resp)
-	return wc.Count(), err
+	row.Columns = make([]string, len(resultColMap))
+	for k, v := range resultColMap {
+		if k == execute.DefaultTimeColLabel {
+			k = "time"
+		}
+		row.Columns[v] = k
+	}
+
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		// Preallocate the number of rows for the response to make this section
+		// of code easier to read. Find a time column which should exist
+		// in the output.
+		values := make([][]interface{}, cr.Len())
+		for j := range values {
+			values[j] = make([]interface{}, len(row.Columns))
+		}
+
+		j := 0
+		for idx, c := range tbl.Cols() {
+			if cr.Key().HasCol(c.Label) {
+				continue
+			}
+
+			j = resultColMap[c.Label]
+			// Fill in the values for each column.
+			switch c.Type {
+			case flux.TFloat:
+				vs := cr.Floats(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TInt:
+				vs := cr.Ints(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TString:
+				vs := cr.Strings(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.ValueString(i)
+					}
+				}
+			case flux.TUInt:
+				vs := cr.UInts(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TBool:
+				vs := cr.Bools(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = vs.Value(i)
+					}
+				}
+			case flux.TTime:
+				vs := cr.Times(idx)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values[i][j] = e.formatTime(vs.Value(i))
+					}
+				}
+			default:
+				return fmt.Errorf("unsupported column type: %s", c.Type)
+			}
+
+		}
+		row.Values = append(row.Values, values...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// formatTime formats a timestamp with the TimeFormat of the encoder.
+func (e *MultiResultEncoder) formatTime(t int64) interface{} {
+	switch e.TimeFormat {
+	case Hour:
+		return t / int64(time.Hour)
+	case Minute:
+		return t / int64(time.Minute)
+	case Second:
+		return t / int64(time.Second)
+	case Millisecond:
+		return t / int64(time.Millisecond)
+	case Microsecond:
+		return t / int64(time.Microsecond)
+	case Nanosecond:
+		return t
+	default:
+		return execute.Time(t).Time().Format(time.RFC3339Nano)
+	}
 }
+
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
 }
@@ -106,6 +106,107 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 	}
 }
 
+func TestMultiResultEncoder_EncodeTimeFormat(t *testing.T) {
+	in := func() flux.ResultIterator {
+		return flux.NewSliceResultIterator(
+			[]flux.Result{&executetest.Result{
+				Nm: "0",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{ts("2018-05-24T09:00:00Z"), "m0", float64(2)},
+					},
+				}},
+			}},
+		)
+	}
+
+	for _, tt := range []struct {
+		format influxql.TimeFormat
+		time   string
+	}{
+		{format: influxql.RFC3339Nano, time: `"2018-05-24T09:00:00Z"`},
+		{format: influxql.Hour, time: "424209"},
+		{format: influxql.Minute, time: "25452540"},
+		{format: influxql.Second, time: "1527152400"},
+		{format: influxql.Millisecond, time: "1527152400000"},
+		{format: influxql.Microsecond, time: "1527152400000000"},
+		{format: influxql.Nanosecond, time: "1527152400000000000"},
+	} {
+		var buf bytes.Buffer
+		enc := &influxql.MultiResultEncoder{TimeFormat: tt.format}
+		if _, err := enc.Encode(&buf, in()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		exp := `{"results":[{"statement_id":0,"series":[{"name":"m0","columns":["time","value"],"values":[[` + tt.time + `,2]]}]}]}` + "\n"
+		if got := buf.String(); got != exp {
+			t.Errorf("unexpected output for format %d:\nexp=%s\ngot=%s", tt.format, exp, got)
+		}
+	}
+}
+
+func TestMultiResultEncoder_EncodeChunked(t *testing.T) {
+	in := flux.NewSliceResultIterator(
+		[]flux.Result{
+			&executetest.Result{
+				Nm: "0",
+				Tbls: []*executetest.Table{
+					{
+						KeyCols: []string{"_measurement"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{ts("2018-05-24T09:00:00Z"), "m0", float64(1)},
+							{ts("2018-05-24T09:00:01Z"), "m0", float64(2)},
+							{ts("2018-05-24T09:00:02Z"), "m0", float64(3)},
+						},
+					},
+					{
+						KeyCols: []string{"_measurement"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{ts("2018-05-24T09:00:00Z"), "m1", float64(4)},
+						},
+					},
+				},
+			},
+			&executetest.Result{Nm: "1"},
+		},
+	)
+
+	var buf bytes.Buffer
+	enc := &influxql.MultiResultEncoder{TimeFormat: influxql.Second, ChunkSize: 2}
+	n, err := enc.Encode(&buf, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	exp := `{"results":[{"statement_id":0,"series":[{"name":"m0","columns":["time","value"],"values":[[1527152400,1],[1527152401,2]],"partial":true}],"partial":true}]}
+{"results":[{"statement_id":0,"series":[{"name":"m0","columns":["time","value"],"values":[[1527152402,3]]}],"partial":true}]}
+{"results":[{"statement_id":0,"series":[{"name":"m1","columns":["time","value"],"values":[[1527152400,4]]}]}]}
+{"results":[{"statement_id":1}]}
+`
+	if got := buf.String(); got != exp {
+		t.Fatalf("unexpected output:\nexp=%s\ngot=%s", exp, got)
+	}
+	if g, w := n, int64(len(exp)); g != w {
+		t.Errorf("unexpected encoding count -want/+got:\n%s", cmp.Diff(w, g))
+	}
+}
+
 type resultErrorIterator struct {
 	Error string
 }
@@ -141,7 +141,7 @@ func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *infl
 		stmt.Database = t.config.DefaultDatabase
 	}
 
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
+	expr, err := t.from(ctx, &influxql.Measurement{Database: stmt.Database})
 	if err != nil {
 		return nil, err
 	}
@@ -628,7 +628,7 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 
 	cursors := make([]cursor, 0, len(groups))
 	for _, gr := range groups {
-		cur, err := gr.createCursor(t)
+		cur, err := gr.createCursor(ctx, t)
 		if err != nil {
 			return nil, err
 		}
@@ -652,7 +652,7 @@ func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
 	return influxql.Tag
 }
 
-func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error) {
+func (t *transpilerState) from(ctx context.Context, m *influxql.Measurement) (ast.Expression, error) {
 	db, rp := m.Database, m.RetentionPolicy
 	if db == "" {
 		if t.config.DefaultDatabase == "" {
@@ -676,7 +676,7 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	}
 	defaultRP := rp == ""
 	filter.Default = &defaultRP
-	mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
+	mapping, err := t.dbrpMappingSvc.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
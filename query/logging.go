@@ -51,7 +51,9 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 			Statistics:     stats,
 			Error:          err,
 		}
-		s.QueryLogger.Log(log)
+		if err := s.QueryLogger.Log(log); err != nil {
+			s.Logger.Info("Failed to log query", zap.Error(err))
+		}
 	}()
 
 	wc := &iocounter.Writer{Writer: w}
@@ -121,3 +121,35 @@ func (q *Query) Statistics() flux.Statistics {
 		Metadata: q.Metadata,
 	}
 }
+
+// RunningQueryService mocks the RunningQueryService for testing.
+type RunningQueryService struct {
+	FindRunningQueriesF   func(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error)
+	FindRunningQueryByIDF func(ctx context.Context, id uint64) (*query.RunningQuery, error)
+	CancelRunningQueryF   func(ctx context.Context, id uint64) error
+}
+
+// FindRunningQueries returns the running queries matching the filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter query.RunningQueryFilter) ([]*query.RunningQuery, error) {
+	return s.FindRunningQueriesF(ctx, filter)
+}
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id uint64) (*query.RunningQuery, error) {
+	return s.FindRunningQueryByIDF(ctx, id)
+}
+
+// CancelRunningQuery cancels a running query by ID.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id uint64) error {
+	return s.CancelRunningQueryF(ctx, id)
+}
+
+// ExplainService mocks the ExplainService for testing.
+type ExplainService struct {
+	ExplainF func(ctx context.Context, req *query.Request, analyze bool) (*query.Explanation, error)
+}
+
+// Explain returns the plan of the query of req.
+func (s *ExplainService) Explain(ctx context.Context, req *query.Request, analyze bool) (*query.Explanation, error) {
+	return s.ExplainF(ctx, req, analyze)
+}
@@ -18,13 +18,27 @@ type PreAuthorizer interface {
 	RequiredPermissions(ctx context.Context, ast *ast.Package, orgID *platform.ID) ([]platform.Permission, error)
 }
 
-// NewPreAuthorizer creates a new PreAuthorizer
-func NewPreAuthorizer(bucketService platform.BucketService) PreAuthorizer {
-	return &preAuthorizer{bucketService: bucketService}
+// AllowedFunc returns whether the authorizer a is allowed p in ctx.
+type AllowedFunc func(ctx context.Context, a platform.Authorizer, p platform.Permission) bool
+
+// NewPreAuthorizer creates a new PreAuthorizer checking the permissions of the
+// authorizers with allowed. A nil allowed only checks the permissions of the
+// authorizers themselves.
+func NewPreAuthorizer(bucketService platform.BucketService, allowed AllowedFunc) PreAuthorizer {
+	if allowed == nil {
+		allowed = func(_ context.Context, a platform.Authorizer, p platform.Permission) bool {
+			return a.Allowed(p)
+		}
+	}
+	return &preAuthorizer{
+		bucketService: bucketService,
+		allowed:       allowed,
+	}
 }
 
 type preAuthorizer struct {
 	bucketService platform.BucketService
+	allowed       AllowedFunc
 }
 
 // PreAuthorize finds all the buckets read and written by the given spec, and ensures that execution is allowed
@@ -53,7 +67,7 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, ast *ast.Package, auth
 			return errors.Wrapf(err, "could not create read bucket permission")
 		}
 
-		if !auth.Allowed(*reqPerm) {
+		if !a.allowed(ctx, auth, *reqPerm) {
 			return errors.New("no read permission for bucket: \"" + bucket.Name + "\"")
 		}
 	}
@@ -68,7 +82,7 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, ast *ast.Package, auth
 		if err != nil {
 			return errors.Wrapf(err, "could not create write bucket permission")
 		}
-		if !auth.Allowed(*reqPerm) {
+		if !a.allowed(ctx, auth, *reqPerm) {
 			return errors.New("no write permission for bucket: \"" + bucket.Name + "\"")
 		}
 	}
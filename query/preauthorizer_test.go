@@ -7,6 +7,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kit/errors"
 	"github.com/influxdata/influxdb/mock"
@@ -32,7 +33,7 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 	// fresh pre-authorizer
 	auth := &platform.Authorization{Status: platform.Active}
 	emptyBucketService := mock.NewBucketService()
-	preAuthorizer := query.NewPreAuthorizer(emptyBucketService)
+	preAuthorizer := query.NewPreAuthorizer(emptyBucketService, nil)
 
 	// Try to pre-authorize invalid bucketID
 	q := `from(bucketID:"invalid") |> range(start:-2h) |> yield()`
@@ -70,7 +71,7 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 		OrgID: orgID,
 	})
 
-	preAuthorizer = query.NewPreAuthorizer(bucketService)
+	preAuthorizer = query.NewPreAuthorizer(bucketService, nil)
 	err = preAuthorizer.PreAuthorize(ctx, ast, auth, &orgID)
 	if diagnostic := cmp.Diff(`no read permission for bucket: "my_bucket"`, err.Error()); diagnostic != "" {
 		t.Errorf("Authorize message mismatch: -want/+got:\n%v", diagnostic)
@@ -116,7 +117,7 @@ func TestPreAuthorizer_RequiredPermissions(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	preAuthorizer := query.NewPreAuthorizer(i)
+	preAuthorizer := query.NewPreAuthorizer(i, nil)
 	perms, err := preAuthorizer.RequiredPermissions(ctx, ast, &o.ID)
 	if err != nil {
 		t.Fatal(err)
@@ -143,7 +144,7 @@ func TestPreAuthorizer_PreAuthorize_FailToParse(t *testing.T) {
 		ctx                = context.Background()
 		auth               = &platform.Authorization{Status: platform.Active}
 		emptyBucketService = mock.NewBucketService()
-		authorizer         = query.NewPreAuthorizer(emptyBucketService)
+		authorizer         = query.NewPreAuthorizer(emptyBucketService, nil)
 		errfmt             = "Expected %q, Got %q"
 		// inputs
 		q = `from(bucket:"foo") |> range(start:-2h) |> to(org: "bar")`
@@ -175,3 +176,46 @@ func TestPreAuthorizer_PreAuthorize_FailToParse(t *testing.T) {
 		t.Fatalf(errfmt, code, perr.Code)
 	}
 }
+
+func TestPreAuthorizer_PreAuthorize_Roles(t *testing.T) {
+	ctx := context.Background()
+	orgID := platform.ID(1)
+	bucketID := platform.ID(2)
+	bucketService := newBucketServiceWithOneBucket(platform.Bucket{
+		Name:  "my_bucket",
+		ID:    bucketID,
+		OrgID: orgID,
+	})
+
+	ast, err := flux.Parse(`from(bucket:"my_bucket") |> range(start:-2h) |> yield()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The token has no permissions, only a role granting the buckets of the org.
+	role := &platform.Role{
+		ID:    3,
+		OrgID: orgID,
+		Name:  "bucket-reader",
+		Permissions: []platform.RolePermission{
+			{Action: platform.ReadAction, Resource: platform.RoleResource{Type: platform.BucketsResourceType}},
+		},
+	}
+	auth := &platform.Authorization{ID: 4, OrgID: orgID, RoleIDs: []platform.ID{role.ID}, Status: platform.Active}
+
+	rs := mock.NewRoleService()
+	rs.FindRoleByIDFn = func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+		return role, nil
+	}
+	ctx, err = (&authorizer.RoleResolver{RoleService: rs}).Resolve(ctx, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := query.NewPreAuthorizer(bucketService, nil).PreAuthorize(ctx, ast, auth, &orgID); err == nil {
+		t.Error("expected error without checking the roles of the token")
+	}
+	if err := query.NewPreAuthorizer(bucketService, authorizer.Allowed).PreAuthorize(ctx, ast, auth, &orgID); err != nil {
+		t.Errorf("unexpected error with the roles of the token: %v", err)
+	}
+}
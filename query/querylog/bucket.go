@@ -0,0 +1,186 @@
+// Package querylog records the completed queries in the queries system bucket
+// of their organization.
+package querylog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+const (
+	measurement = "queries"
+
+	compilerTypeTag = "compilerType"
+	statusTag       = "status"
+	slowTag         = "slow"
+
+	hashField            = "hash"
+	queryField           = "query"
+	bucketsField         = "buckets"
+	authorizationIDField = "authorizationID"
+	userIDField          = "userID"
+	responseSizeField    = "responseSize"
+	errorField           = "error"
+	totalDurationField   = "totalDuration"
+	compileDurationField = "compileDuration"
+	queueDurationField   = "queueDuration"
+	planDurationField    = "planDuration"
+	requeueDurationField = "requeueDuration"
+	executeDurationField = "executeDuration"
+	concurrencyField     = "concurrency"
+	maxAllocatedField    = "maxAllocated"
+	runtimeErrorsField   = "runtimeErrors"
+	profileField         = "profile"
+
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+// DefaultMaxQueryLength is the default length the text of queries that are not
+// slow is truncated to.
+const DefaultMaxQueryLength = 256
+
+var _ query.Logger = (*BucketLogger)(nil)
+
+// BucketLogger writes the logs of completed queries to the queries system
+// bucket of the organization of the queries. The text of the queries is
+// truncated, except for the slow queries whose full text and profile are
+// recorded.
+type BucketLogger struct {
+	PointsWriter storage.PointsWriter
+
+	// SlowQueryThreshold is the total duration from which a query is slow.
+	// No query is slow if it is zero.
+	SlowQueryThreshold time.Duration
+	// MaxQueryLength is the number of bytes the text of queries that are not
+	// slow is truncated to.
+	MaxQueryLength int
+}
+
+// NewBucketLogger returns a BucketLogger writing to pw.
+func NewBucketLogger(pw storage.PointsWriter, slowQueryThreshold time.Duration) *BucketLogger {
+	return &BucketLogger{
+		PointsWriter:       pw,
+		SlowQueryThreshold: slowQueryThreshold,
+		MaxQueryLength:     DefaultMaxQueryLength,
+	}
+}
+
+// Log writes log to the queries bucket. Queries without an organization are
+// not recorded.
+func (l *BucketLogger) Log(log query.Log) error {
+	if !log.OrganizationID.Valid() {
+		return nil
+	}
+
+	p, err := l.point(log)
+	if err != nil {
+		return err
+	}
+	points, err := tsdb.ExplodePoints(log.OrganizationID, influxdb.QueriesSystemBucketID, models.Points{p})
+	if err != nil {
+		return err
+	}
+	return l.PointsWriter.WritePoints(context.Background(), points)
+}
+
+// slow returns whether stats are those of a slow query.
+func (l *BucketLogger) slow(stats flux.Statistics) bool {
+	return l.SlowQueryThreshold > 0 && stats.TotalDuration >= l.SlowQueryThreshold
+}
+
+// point returns the point of log, the compiler type, the status and the
+// slowness of the query are tags and the remaining attributes fields.
+func (l *BucketLogger) point(log query.Log) (models.Point, error) {
+	stats := log.Statistics
+	slow := l.slow(stats)
+
+	tags := map[string]string{
+		statusTag: statusSuccess,
+		slowTag:   "false",
+	}
+	if log.Error != nil {
+		tags[statusTag] = statusError
+	}
+	if slow {
+		tags[slowTag] = "true"
+	}
+
+	fields := map[string]interface{}{
+		responseSizeField:    log.ResponseSize,
+		totalDurationField:   int64(stats.TotalDuration),
+		compileDurationField: int64(stats.CompileDuration),
+		queueDurationField:   int64(stats.QueueDuration),
+		planDurationField:    int64(stats.PlanDuration),
+		requeueDurationField: int64(stats.RequeueDuration),
+		executeDurationField: int64(stats.ExecuteDuration),
+		concurrencyField:     int64(stats.Concurrency),
+		maxAllocatedField:    stats.MaxAllocated,
+	}
+	if log.Error != nil {
+		fields[errorField] = log.Error.Error()
+	}
+	if len(stats.RuntimeErrors) > 0 {
+		fields[runtimeErrorsField] = strings.Join(stats.RuntimeErrors, "\n")
+	}
+
+	if req := log.ProxyRequest; req != nil {
+		if c := req.Request.Compiler; c != nil {
+			tags[compilerTypeTag] = string(c.CompilerType())
+
+			if text := QueryText(c); text != "" {
+				sum := sha256.Sum256([]byte(text))
+				fields[hashField] = hex.EncodeToString(sum[:])
+				if !slow {
+					text = truncate(text, l.MaxQueryLength)
+				}
+				fields[queryField] = text
+			}
+			if buckets := bucketsRead(c); len(buckets) > 0 {
+				fields[bucketsField] = strings.Join(buckets, ",")
+			}
+		}
+		if a := req.Request.Authorization; a != nil {
+			if a.ID.Valid() {
+				fields[authorizationIDField] = a.ID.String()
+			}
+			if a.UserID.Valid() {
+				fields[userIDField] = a.UserID.String()
+			}
+		}
+	}
+
+	if slow {
+		profile, err := json.Marshal(stats.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		fields[profileField] = string(profile)
+	}
+
+	return models.NewPoint(measurement, models.NewTags(tags), fields, log.Time)
+}
+
+// truncate returns the first n bytes of s, shortened to the last complete
+// rune. s is not truncated if n is not positive.
+func truncate(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,205 @@
+package querylog_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/query/querylog"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestBucketLogger(t *testing.T) {
+	longQuery := `from(bucket: "b") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "` + strings.Repeat("m", 300) + `")`
+
+	tests := []struct {
+		name   string
+		log    query.Log
+		tags   map[string]string
+		fields map[string]interface{}
+	}{
+		{
+			name: "successful flux query",
+			log: query.Log{
+				OrganizationID: 10,
+				ProxyRequest: &query.ProxyRequest{
+					Request: query.Request{
+						Authorization: &influxdb.Authorization{ID: 20, UserID: 30},
+						Compiler:      lang.FluxCompiler{Query: `from(bucket: "a") |> range(start: -1h) |> yield()` + "\n" + `from(bucketID: "000000000000000b") |> range(start: -1h) |> yield()` + "\n" + `from(bucket: "a") |> range(start: -2h)`},
+					},
+				},
+				ResponseSize: 100,
+				Statistics: flux.Statistics{
+					TotalDuration: time.Second,
+					QueueDuration: time.Millisecond,
+					Concurrency:   1,
+					MaxAllocated:  1024,
+				},
+			},
+			tags: map[string]string{
+				"compilerType": "flux",
+				"status":       "success",
+				"slow":         "false",
+			},
+			fields: map[string]interface{}{
+				"buckets":         "a,000000000000000b",
+				"authorizationID": "0000000000000014",
+				"userID":          "000000000000001e",
+				"responseSize":    int64(100),
+				"totalDuration":   int64(time.Second),
+				"queueDuration":   int64(time.Millisecond),
+				"concurrency":     int64(1),
+				"maxAllocated":    int64(1024),
+			},
+		},
+		{
+			name: "failed influxql query",
+			log: query.Log{
+				OrganizationID: 10,
+				ProxyRequest: &query.ProxyRequest{
+					Request: query.Request{
+						Compiler: &influxql.Compiler{Query: "SELECT * FROM m"},
+					},
+				},
+				Error: errors.New("bad query"),
+				Statistics: flux.Statistics{
+					RuntimeErrors: []string{"e1", "e2"},
+				},
+			},
+			tags: map[string]string{
+				"compilerType": "influxql",
+				"status":       "error",
+				"slow":         "false",
+			},
+			fields: map[string]interface{}{
+				"error":         "bad query",
+				"runtimeErrors": "e1\ne2",
+			},
+		},
+		{
+			name: "slow query",
+			log: query.Log{
+				OrganizationID: 10,
+				ProxyRequest: &query.ProxyRequest{
+					Request: query.Request{
+						Compiler: lang.FluxCompiler{Query: longQuery},
+					},
+				},
+				Statistics: flux.Statistics{
+					TotalDuration: time.Minute,
+					Metadata:      flux.Metadata{"k": []interface{}{"v"}},
+				},
+			},
+			tags: map[string]string{
+				"compilerType": "flux",
+				"status":       "success",
+				"slow":         "true",
+			},
+			fields: map[string]interface{}{
+				"buckets":       "b",
+				"query":         longQuery,
+				"profile":       `{"k":["v"]}`,
+				"totalDuration": int64(time.Minute),
+			},
+		},
+		{
+			name: "query that is not slow is truncated",
+			log: query.Log{
+				OrganizationID: 10,
+				ProxyRequest: &query.ProxyRequest{
+					Request: query.Request{
+						Compiler: lang.FluxCompiler{Query: longQuery},
+					},
+				},
+				Statistics: flux.Statistics{
+					TotalDuration: time.Second,
+				},
+			},
+			tags: map[string]string{
+				"compilerType": "flux",
+				"status":       "success",
+				"slow":         "false",
+			},
+			fields: map[string]interface{}{
+				"buckets":       "b",
+				"query":         longQuery[:querylog.DefaultMaxQueryLength],
+				"totalDuration": int64(time.Second),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			tt.log.Time = time.Unix(0, 1000)
+			if err := querylog.NewBucketLogger(pw, 10*time.Second).Log(tt.log); err != nil {
+				t.Fatal(err)
+			}
+
+			tags := make(map[string]string)
+			fields := make(map[string]interface{})
+			for _, p := range pw.Points {
+				org, bucket := tsdb.DecodeNameSlice(p.Name())
+				if org != tt.log.OrganizationID || bucket != influxdb.QueriesSystemBucketID {
+					t.Errorf("point written to org %s and bucket %s", org, bucket)
+				}
+				if !p.Time().Equal(tt.log.Time) {
+					t.Errorf("unexpected time of point: %v", p.Time())
+				}
+
+				for _, tag := range p.Tags() {
+					if k := string(tag.Key); k != models.MeasurementTagKey && k != models.FieldKeyTagKey {
+						tags[k] = string(tag.Value)
+					}
+				}
+				fs, err := p.Fields()
+				if err != nil {
+					t.Fatal(err)
+				}
+				for k, v := range fs {
+					// Only check the fields that are not zero.
+					if v == int64(0) {
+						continue
+					}
+					if k == "hash" {
+						if s, _ := v.(string); len(s) != 64 {
+							t.Errorf("unexpected hash %v", v)
+						}
+						continue
+					}
+					if k == "query" {
+						if _, ok := tt.fields[k]; !ok {
+							continue
+						}
+					}
+					fields[k] = v
+				}
+			}
+			if diff := cmp.Diff(tt.tags, tags); diff != "" {
+				t.Errorf("unexpected tags -want/+got\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.fields, fields); diff != "" {
+				t.Errorf("unexpected fields -want/+got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBucketLogger_NoOrganization(t *testing.T) {
+	pw := &mock.PointsWriter{}
+	if err := querylog.NewBucketLogger(pw, 0).Log(query.Log{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(pw.Points) != 0 {
+		t.Fatalf("expected no point, got %d", len(pw.Points))
+	}
+}
@@ -0,0 +1,80 @@
+package querylog
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query/influxql"
+)
+
+// QueryText returns the text of the query of the compiler, or an empty string
+// if the compiler has none.
+func QueryText(c flux.Compiler) string {
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case *lang.FluxCompiler:
+		return c.Query
+	case lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	case *influxql.Compiler:
+		return c.Query
+	}
+	return ""
+}
+
+// bucketsRead returns the names or IDs of the buckets passed to the from
+// calls of the Flux query of the compiler, in order of appearance and
+// without duplicates. The query is parsed but not evaluated, so buckets
+// computed at runtime are not found.
+func bucketsRead(c flux.Compiler) []string {
+	var pkg *ast.Package
+	switch c := c.(type) {
+	case lang.ASTCompiler:
+		pkg = c.AST
+	case *lang.ASTCompiler:
+		pkg = c.AST
+	case *influxql.Compiler:
+		return nil
+	default:
+		text := QueryText(c)
+		if text == "" {
+			return nil
+		}
+		pkg = parser.ParseSource(text)
+	}
+	if pkg == nil {
+		return nil
+	}
+
+	var buckets []string
+	seen := make(map[string]bool)
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || len(call.Arguments) != 1 {
+			return
+		}
+		if id, ok := call.Callee.(*ast.Identifier); !ok || id.Name != "from" {
+			return
+		}
+		obj, ok := call.Arguments[0].(*ast.ObjectExpression)
+		if !ok {
+			return
+		}
+		for _, p := range obj.Properties {
+			if p.Key == nil || (p.Key.Key() != "bucket" && p.Key.Key() != "bucketID") {
+				continue
+			}
+			s, ok := p.Value.(*ast.StringLiteral)
+			if !ok || seen[s.Value] {
+				continue
+			}
+			seen[s.Value] = true
+			buckets = append(buckets, s.Value)
+		}
+	}), pkg)
+	return buckets
+}
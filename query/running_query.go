@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// RunningQuery is a query being compiled, queued or executed.
+type RunningQuery struct {
+	// ID is an ephemeral ID of the query, unique within the server running it.
+	ID             uint64      `json:"id"`
+	OrganizationID platform.ID `json:"orgID,omitempty"`
+	// AuthorizationID and UserID are not set for queries without an
+	// authorization, such as the queries of sessions.
+	AuthorizationID platform.ID `json:"authorizationID,omitempty"`
+	UserID          platform.ID `json:"userID,omitempty"`
+	// Query is the text of the query, if its compiler has one.
+	Query        string    `json:"query"`
+	CompilerType string    `json:"compilerType"`
+	State        string    `json:"state"`
+	StartedAt    time.Time `json:"startedAt"`
+	// Elapsed is the duration since the query was received.
+	Elapsed time.Duration `json:"elapsed"`
+	// QueueDuration is the duration the query waited for execution.
+	QueueDuration time.Duration `json:"queueDuration"`
+	// MemoryAllocated is the number of bytes currently allocated by the query.
+	MemoryAllocated int64 `json:"memoryAllocated"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the returned
+// running queries.
+type RunningQueryFilter struct {
+	OrganizationID *platform.ID
+}
+
+// RunningQueryService lists and cancels the running queries.
+type RunningQueryService interface {
+	// FindRunningQueries returns the running queries matching the filter,
+	// ordered by ID.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// FindRunningQueryByID returns a single running query by ID.
+	FindRunningQueryByID(ctx context.Context, id uint64) (*RunningQuery, error)
+
+	// CancelRunningQuery cancels a running query by ID.
+	CancelRunningQuery(ctx context.Context, id uint64) error
+}
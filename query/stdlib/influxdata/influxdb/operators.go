@@ -3,6 +3,8 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -17,6 +19,8 @@ const (
 	ReadGroupPhysKind     = "ReadGroupPhysKind"
 	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
 	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+
+	ReadWindowAggregatePhysKind = "ReadWindowAggregatePhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -44,6 +48,56 @@ func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// String describes the read, the grouping and the aggregate pushed down to
+// storage.
+func (s *ReadGroupPhysSpec) String() string {
+	var mode string
+	switch s.GroupMode {
+	case flux.GroupModeBy:
+		mode = "by"
+	case flux.GroupModeExcept:
+		mode = "except"
+	default:
+		mode = "none"
+	}
+	desc := fmt.Sprintf("%s, group %s [%s]", s.ReadRangePhysSpec.String(), mode, strings.Join(s.GroupKeys, ", "))
+	if s.AggregateMethod != "" {
+		desc += ", aggregate " + s.AggregateMethod
+	}
+	return desc
+}
+
+type ReadWindowAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	WindowEvery int64
+	Aggregates  []plan.ProcedureKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadWindowAggregatePhysKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadWindowAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.WindowEvery = s.WindowEvery
+	ns.Aggregates = make([]plan.ProcedureKind, len(s.Aggregates))
+	copy(ns.Aggregates, s.Aggregates)
+	return ns
+}
+
+// String describes the read and the window aggregates pushed down to storage.
+func (s *ReadWindowAggregatePhysSpec) String() string {
+	aggregates := make([]string, len(s.Aggregates))
+	for i, a := range s.Aggregates {
+		aggregates[i] = string(a)
+	}
+	return fmt.Sprintf("%s, window every %v, aggregates [%s]", s.ReadRangePhysSpec.String(), time.Duration(s.WindowEvery), strings.Join(aggregates, ", "))
+}
+
 type ReadRangePhysSpec struct {
 	plan.DefaultCost
 
@@ -79,6 +133,20 @@ func (s *ReadRangePhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// String describes the bucket, the bounds and whether a filter is pushed
+// down to storage.
+func (s *ReadRangePhysSpec) String() string {
+	bucket := s.Bucket
+	if bucket == "" {
+		bucket = s.BucketID
+	}
+	desc := fmt.Sprintf("bucket %q, range [%v, %v)", bucket, s.Bounds.Start.Time(s.Bounds.Now), s.Bounds.Stop.Time(s.Bounds.Now))
+	if s.FilterSet {
+		desc += ", filter pushed down"
+	}
+	return desc
+}
+
 func (s *ReadRangePhysSpec) LookupBucketID(ctx context.Context, orgID influxdb.ID, buckets BucketLookup) (influxdb.ID, error) {
 	// Determine bucketID
 	switch {
@@ -146,3 +214,8 @@ func (s *ReadTagValuesPhysSpec) Copy() plan.ProcedureSpec {
 	ns.TagKey = s.TagKey
 	return ns
 }
+
+// String describes the read and the tag key whose values are read.
+func (s *ReadTagValuesPhysSpec) String() string {
+	return fmt.Sprintf("%s, tag key %q", s.ReadRangePhysSpec.String(), s.TagKey)
+}
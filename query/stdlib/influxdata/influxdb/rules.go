@@ -1,6 +1,8 @@
 package influxdb
 
 import (
+	"math"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -16,6 +18,7 @@ func init() {
 		PushDownGroupRule{},
 		PushDownReadTagKeysRule{},
 		PushDownReadTagValuesRule{},
+		PushDownWindowAggregateRule{},
 	)
 }
 
@@ -528,3 +531,75 @@ func isPushableFieldOperator(kind ast.OperatorKind) bool {
 
 	return false
 }
+
+// PushDownWindowAggregateRule pushes down a window followed by a single
+// aggregate to storage
+type PushDownWindowAggregateRule struct{}
+
+func (PushDownWindowAggregateRule) Name() string {
+	return "PushDownWindowAggregateRule"
+}
+
+// Pattern matches any node since the rule applies to several aggregates.
+// Rewrite matches 'ReadRange |> window |> agg'.
+func (PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Any()
+}
+
+// Rewrite converts 'ReadRange |> window |> agg' into 'ReadWindowAggregate'
+// if the window has fixed bounds aligned to the epoch and the aggregate is
+// computed on the _value column.
+func (PushDownWindowAggregateRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	if !isPushableWindowAggregate(pn.ProcedureSpec()) {
+		return pn, false, nil
+	}
+	if !plan.Pat(pn.Kind(), plan.Pat(universe.WindowKind, plan.Pat(ReadRangePhysKind))).Match(pn) {
+		return pn, false, nil
+	}
+
+	windowNode := pn.Predecessors()[0]
+	window := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	if window.Window.Every != window.Window.Period ||
+		window.Window.Every <= 0 ||
+		window.Window.Every == flux.Duration(math.MaxInt64) ||
+		window.Window.Offset != 0 ||
+		window.TimeColumn != execute.DefaultTimeColLabel ||
+		window.StartColumn != execute.DefaultStartColLabel ||
+		window.StopColumn != execute.DefaultStopColLabel ||
+		window.CreateEmpty {
+		return pn, false, nil
+	}
+
+	src := windowNode.Predecessors()[0].ProcedureSpec().(*ReadRangePhysSpec)
+	return plan.CreatePhysicalNode("ReadWindowAggregate", &ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: *src.Copy().(*ReadRangePhysSpec),
+		WindowEvery:       int64(window.Window.Every),
+		Aggregates:        []plan.ProcedureKind{pn.Kind()},
+	}), true, nil
+}
+
+// isPushableWindowAggregate reports whether spec is an aggregate that storage
+// can compute over the _value column of each window.
+func isPushableWindowAggregate(spec plan.ProcedureSpec) bool {
+	switch spec := spec.(type) {
+	case *universe.MinProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.MaxProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.FirstProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.LastProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.MeanProcedureSpec:
+		return isValueColumn(spec.Columns)
+	case *universe.CountProcedureSpec:
+		return isValueColumn(spec.Columns)
+	case *universe.SumProcedureSpec:
+		return isValueColumn(spec.Columns)
+	}
+	return false
+}
+
+func isValueColumn(columns []string) bool {
+	return len(columns) == 1 && columns[0] == execute.DefaultValueColLabel
+}
@@ -1217,3 +1217,144 @@ func TestReadTagValuesRule(t *testing.T) {
 		})
 	}
 }
+
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	window := func(every, period, offset time.Duration, createEmpty bool) *universe.WindowProcedureSpec {
+		return &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  flux.Duration(every),
+				Period: flux.Duration(period),
+				Offset: flux.Duration(offset),
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+			CreateEmpty: createEmpty,
+		}
+	}
+
+	readWindowAggregate := func(agg plan.ProcedureKind) *influxdb.ReadWindowAggregatePhysSpec {
+		return &influxdb.ReadWindowAggregatePhysSpec{
+			ReadRangePhysSpec: readRange,
+			WindowEvery:       int64(time.Minute),
+			Aggregates:        []plan.ProcedureKind{agg},
+		}
+	}
+
+	// ReadRange -> window -> agg  =>  ReadWindowAggregate
+	simple := func(name string, agg plan.PhysicalProcedureSpec) plantest.RuleTestCase {
+		return plantest.RuleTestCase{
+			Name:  name,
+			Rules: []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0, false)),
+					plan.CreatePhysicalNode(plan.NodeID(agg.Kind()), agg),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", readWindowAggregate(agg.Kind())),
+				},
+			},
+		}
+	}
+
+	// ReadRange -> window -> agg  =>  ReadRange -> window -> agg  (no change)
+	//
+	// The plan is declared as the expected result rather than with NoChange
+	// because the copy of a window procedure spec drops its columns.
+	noChange := func(name string, w *universe.WindowProcedureSpec, agg plan.PhysicalProcedureSpec) plantest.RuleTestCase {
+		spec := func() *plantest.PlanSpec {
+			return &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", w),
+					plan.CreatePhysicalNode(plan.NodeID(agg.Kind()), agg),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			}
+		}
+		return plantest.RuleTestCase{
+			Name:   name,
+			Rules:  []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: spec(),
+			After:  spec(),
+		}
+	}
+
+	minute := window(time.Minute, time.Minute, 0, false)
+	selector := execute.DefaultSelectorConfig
+	aggregate := execute.DefaultAggregateConfig
+
+	tests := []plantest.RuleTestCase{
+		simple("min", &universe.MinProcedureSpec{SelectorConfig: selector}),
+		simple("max", &universe.MaxProcedureSpec{SelectorConfig: selector}),
+		simple("first", &universe.FirstProcedureSpec{SelectorConfig: selector}),
+		simple("last", &universe.LastProcedureSpec{SelectorConfig: selector}),
+		simple("mean", &universe.MeanProcedureSpec{AggregateConfig: aggregate}),
+		simple("count", &universe.CountProcedureSpec{AggregateConfig: aggregate}),
+		simple("sum", &universe.SumProcedureSpec{AggregateConfig: aggregate}),
+		noChange("unsupported aggregate", minute, &universe.SpreadProcedureSpec{AggregateConfig: aggregate}),
+		noChange("other column", minute, &universe.MaxProcedureSpec{SelectorConfig: execute.SelectorConfig{Column: "other"}}),
+		noChange("sliding window", window(time.Minute, 2*time.Minute, 0, false), &universe.MaxProcedureSpec{SelectorConfig: selector}),
+		noChange("window offset", window(time.Minute, time.Minute, time.Second, false), &universe.MaxProcedureSpec{SelectorConfig: selector}),
+		noChange("create empty", window(time.Minute, time.Minute, 0, true), &universe.CountProcedureSpec{AggregateConfig: aggregate}),
+		{
+			Name: "window with multiple successors",
+			// ReadRange -> window -> count
+			//                    \-> mean   (no change)
+			Rules: []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", minute),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{AggregateConfig: aggregate}),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{AggregateConfig: aggregate}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", minute),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{AggregateConfig: aggregate}),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{AggregateConfig: aggregate}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/semantic"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -114,6 +115,14 @@ type ReadGroupSpec struct {
 	AggregateMethod string
 }
 
+// ReadWindowAggregateSpec reads a single aggregate per window of WindowEvery
+// nanoseconds of each series.
+type ReadWindowAggregateSpec struct {
+	ReadFilterSpec
+	WindowEvery int64
+	Aggregates  []plan.ProcedureKind
+}
+
 type ReadTagKeysSpec struct {
 	ReadFilterSpec
 }
@@ -126,6 +135,7 @@ type ReadTagValuesSpec struct {
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadWindowAggregate(ctx context.Context, spec ReadWindowAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
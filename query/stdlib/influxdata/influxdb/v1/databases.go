@@ -94,6 +94,9 @@ func (bd *DatabasesDecoder) Decode(ctx context.Context) (flux.Table, error) {
 
 	databases := make([]databaseInfo, 0, len(bd.databases))
 	for _, db := range bd.databases {
+		if db.OrganizationID != bd.orgID {
+			continue
+		}
 		bucket, err := bd.deps.BucketLookup.FindBucketByID(ctx, db.BucketID)
 		if err != nil {
 			code := platform.ErrorCode(err)
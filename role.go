@@ -0,0 +1,189 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrRoleNotFound is the error message for a missing role.
+const ErrRoleNotFound = "role not found"
+
+// ops for roles.
+const (
+	OpFindRoleByID     = "FindRoleByID"
+	OpFindRoles        = "FindRoles"
+	OpCreateRole       = "CreateRole"
+	OpUpdateRole       = "UpdateRole"
+	OpDeleteRole       = "DeleteRole"
+	OpAddRoleMember    = "AddRoleMember"
+	OpRemoveRoleMember = "RemoveRoleMember"
+)
+
+// Role is a named set of permissions on the resources of an organization.
+// Authorizations and members of the organization reference roles instead of
+// listing permissions, the permissions of roles are resolved when a request is
+// authorized.
+type Role struct {
+	ID          ID               `json:"id,omitempty"`
+	OrgID       ID               `json:"orgID"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Permissions []RolePermission `json:"permissions"`
+}
+
+// RolePermission grants an action on the resources of a type of the
+// organization of a role: all of them, the resource of an ID, or the resources
+// that have all the labels of a selector.
+type RolePermission struct {
+	Action   Action       `json:"action"`
+	Resource RoleResource `json:"resource"`
+}
+
+// RoleResource selects the resources of a role permission.
+type RoleResource struct {
+	Type ResourceType `json:"type"`
+	ID   *ID          `json:"id,omitempty"`
+	// Labels are the names of the labels a resource must have.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// String stringifies a role permission.
+func (p RolePermission) String() string {
+	s := fmt.Sprintf("%s:%s", p.Action, p.Resource.Type)
+	if p.Resource.ID != nil {
+		s += "/" + p.Resource.ID.String()
+	}
+	if len(p.Resource.Labels) > 0 {
+		s += fmt.Sprintf("%q", p.Resource.Labels)
+	}
+	return s
+}
+
+// Valid returns an error if the action or the resource of the permission is
+// invalid.
+func (p RolePermission) Valid() error {
+	if err := p.Action.Valid(); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid action %q for role permission", p.Action),
+			Err:  err,
+		}
+	}
+	if err := p.Resource.Type.Valid(); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid resource type %q for role permission", p.Resource.Type),
+			Err:  err,
+		}
+	}
+	if p.Resource.ID != nil && len(p.Resource.Labels) > 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role permission selects resources either by id or by labels",
+		}
+	}
+	if p.Resource.ID != nil && !p.Resource.ID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "invalid resource id for role permission",
+			Err:  ErrInvalidID,
+		}
+	}
+	for _, l := range p.Resource.Labels {
+		if l == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "role permission label selector has an empty label name",
+			}
+		}
+	}
+	return nil
+}
+
+// Valid returns an error if the role is invalid.
+func (r *Role) Valid() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role name is required",
+		}
+	}
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role org id is required",
+			Err:  ErrInvalidID,
+		}
+	}
+	for _, p := range r.Permissions {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RoleService represents a service for managing roles and their members.
+type RoleService interface {
+	// FindRoleByID returns a single role by ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns a list of roles that match filter and the total count of matching roles.
+	FindRoles(ctx context.Context, filter RoleFilter, opt ...FindOptions) ([]*Role, int, error)
+
+	// CreateRole creates a new role and sets r.ID with the new identifier.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a single role with changeset.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole removes a role by ID, and its references from members.
+	DeleteRole(ctx context.Context, id ID) error
+
+	// AddRoleMember grants a role to a user. The user must be a member or an
+	// owner of the organization of the role.
+	AddRoleMember(ctx context.Context, roleID, userID ID) error
+
+	// RemoveRoleMember revokes a role from a user.
+	RemoveRoleMember(ctx context.Context, roleID, userID ID) error
+}
+
+// RoleFilter represents a set of filters that restrict the returned roles.
+type RoleFilter struct {
+	ID    *ID
+	OrgID *ID
+	Org   *string
+	Name  *string
+}
+
+// RoleUpdate represents updates to a role.
+// Only fields which are set are updated.
+type RoleUpdate struct {
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Permissions *[]RolePermission `json:"permissions,omitempty"`
+}
+
+// Apply applies the update to a role.
+func (u RoleUpdate) Apply(r *Role) error {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = *u.Permissions
+	}
+	return r.Valid()
+}
+
+// HasRole returns whether id is among ids.
+func HasRole(ids []ID, id ID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
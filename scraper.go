@@ -2,6 +2,9 @@ package influxdb
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"time"
 )
 
 // ErrScraperTargetNotFound is the error msg for a missing scraper target.
@@ -24,6 +27,157 @@ type ScraperTarget struct {
 	URL      string      `json:"url"`
 	OrgID    ID          `json:"orgID,omitempty"`
 	BucketID ID          `json:"bucketID,omitempty"`
+
+	// JSON maps the documents of json targets to metrics.
+	JSON *JSONScraperConfig `json:"json,omitempty"`
+	// Graphite maps the paths of the metrics of graphite targets to
+	// measurements, tags and fields.
+	Graphite *GraphiteScraperConfig `json:"graphite,omitempty"`
+	// StatsD configures the aggregation of the metrics pushed to statsd
+	// targets.
+	StatsD *StatsDScraperConfig `json:"statsd,omitempty"`
+
+	// Interval is the time between two scrapes of the target. The interval
+	// of the scheduler is used when it is not set.
+	Interval *Duration `json:"interval,omitempty"`
+	// Timeout is the maximum duration of a scrape of the target.
+	Timeout *Duration `json:"timeout,omitempty"`
+}
+
+// MinScraperTargetInterval is the shortest interval between two scrapes of a
+// target.
+const MinScraperTargetInterval = time.Second
+
+// Valid returns an error if the configuration of the target does not match its
+// type.
+func (t *ScraperTarget) Valid() error {
+	if t.Interval != nil && t.Interval.Duration < MinScraperTargetInterval {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("scraper target interval must be at least %s", MinScraperTargetInterval),
+		}
+	}
+	if t.Timeout != nil && t.Timeout.Duration <= 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "scraper target timeout must be positive",
+		}
+	}
+
+	switch t.Type {
+	case JSONScraperType:
+		if t.JSON == nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "json scraper target requires a json configuration",
+			}
+		}
+		return t.JSON.Valid()
+	case GraphiteScraperType:
+		return validScraperURL(t.URL, "tcp")
+	case StatsDScraperType:
+		if err := validScraperURL(t.URL, "udp"); err != nil {
+			return err
+		}
+		if t.StatsD != nil {
+			return t.StatsD.Valid()
+		}
+	}
+	return nil
+}
+
+// validScraperURL returns an error if u is not an URL of the scheme with a port,
+// such as tcp://localhost:2003.
+func validScraperURL(u, scheme string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Scheme != scheme || parsed.Port() == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("scraper target url must be of the form %s://host:port", scheme),
+		}
+	}
+	return nil
+}
+
+// JSONScraperConfig maps the documents returned by a json target to metrics
+// with JSONPath expressions such as $.servers[*].cpu.
+type JSONScraperConfig struct {
+	// Measurement is the measurement of the metrics.
+	Measurement string `json:"measurement"`
+	// Root is the path of the objects mapped to metrics, one metric per
+	// object. The document is the only object when it is empty.
+	Root string `json:"root,omitempty"`
+	// Fields and Tags are paths relative to the objects of Root.
+	Fields []JSONPathMapping `json:"fields"`
+	Tags   []JSONPathMapping `json:"tags,omitempty"`
+	// TimePath is the path of the time of the metrics, either RFC3339 or in
+	// seconds since the epoch. The time of the scrape is used when it is empty.
+	TimePath string `json:"timePath,omitempty"`
+}
+
+// JSONPathMapping maps the value at Path to the field or tag Name.
+type JSONPathMapping struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Valid returns an error if the configuration is missing the measurement or
+// the fields.
+func (c *JSONScraperConfig) Valid() error {
+	if c.Measurement == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "json scraper target requires a measurement",
+		}
+	}
+	if len(c.Fields) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "json scraper target requires at least one field",
+		}
+	}
+	for _, m := range append(c.Fields, c.Tags...) {
+		if m.Name == "" || m.Path == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "json scraper target fields and tags require a name and a path",
+			}
+		}
+	}
+	return nil
+}
+
+// GraphiteScraperConfig maps the paths of graphite metrics to measurements,
+// tags and fields.
+type GraphiteScraperConfig struct {
+	// Template names the parts of the paths, such as
+	// "region.host.measurement.field*". The parts can be named measurement,
+	// field, a tag or left empty to be skipped, the last part can end with a
+	// * to match the remaining parts. Defaults to "measurement*".
+	Template string `json:"template,omitempty"`
+	// Separator joins the parts of measurements, fields and tags that match
+	// several parts of the paths. Defaults to ".".
+	Separator string `json:"separator,omitempty"`
+}
+
+// StatsDScraperConfig configures the aggregation of the metrics pushed to a
+// statsd target between two scrapes.
+type StatsDScraperConfig struct {
+	// Percentiles are the percentiles of timings reported. Defaults to 90.
+	Percentiles []float64 `json:"percentiles,omitempty"`
+}
+
+// Valid returns an error if a percentile is out of range.
+func (c *StatsDScraperConfig) Valid() error {
+	for _, p := range c.Percentiles {
+		if p <= 0 || p > 100 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("statsd percentile %v must be in (0, 100]", p),
+			}
+		}
+	}
+	return nil
 }
 
 // ScraperTargetStoreService defines the crud service for ScraperTarget.
@@ -37,6 +191,37 @@ type ScraperTargetStoreService interface {
 	UpdateTarget(ctx context.Context, t *ScraperTarget, userID ID) (*ScraperTarget, error)
 }
 
+// Scraper target statuses.
+const (
+	ScraperTargetUp      = "up"
+	ScraperTargetDown    = "down"
+	ScraperTargetUnknown = "unknown"
+)
+
+// ScraperTargetStatus is the outcome of the last scrape of a target.
+type ScraperTargetStatus struct {
+	TargetID ID `json:"targetID"`
+	// Status is up if the last scrape succeeded, down if it failed and
+	// unknown if the target was not scraped yet.
+	Status     string    `json:"status"`
+	LastScrape time.Time `json:"lastScrape,omitempty"`
+	Duration   Duration  `json:"duration"`
+	// HTTPStatus is the status code of the response of http targets.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+	// Samples is the number of metrics gathered.
+	Samples int    `json:"samples"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ScraperTargetStatusService stores the outcome of the last scrape of the
+// targets.
+type ScraperTargetStatusService interface {
+	// GetTargetStatus returns the status of the last scrape of a target.
+	GetTargetStatus(ctx context.Context, id ID) (*ScraperTargetStatus, error)
+	// PutTargetStatus records the status of the last scrape of a target.
+	PutTargetStatus(ctx context.Context, status *ScraperTargetStatus) error
+}
+
 // ScraperTargetFilter represents a set of filter that restrict the returned results.
 type ScraperTargetFilter struct {
 	IDs   map[ID]bool `json:"ids"`
@@ -52,12 +237,18 @@ type ScraperType string
 const (
 	// PrometheusScraperType parses metrics from a prometheus endpoint.
 	PrometheusScraperType = "prometheus"
+	// JSONScraperType maps the documents of a json endpoint to metrics.
+	JSONScraperType = "json"
+	// GraphiteScraperType reads graphite plaintext metrics from a socket.
+	GraphiteScraperType = "graphite"
+	// StatsDScraperType listens for metrics pushed by statsd clients.
+	StatsDScraperType = "statsd"
 )
 
 // ValidScraperType returns true is the type string is valid
 func ValidScraperType(s string) bool {
 	switch s {
-	case PrometheusScraperType:
+	case PrometheusScraperType, JSONScraperType, GraphiteScraperType, StatsDScraperType:
 		return true
 	default:
 		return false
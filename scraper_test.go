@@ -0,0 +1,98 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestScraperTargetValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  influxdb.ScraperTarget
+		wantErr bool
+	}{
+		{
+			name: "prometheus target",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.PrometheusScraperType,
+				URL:  "http://localhost:9090/metrics",
+			},
+		},
+		{
+			name: "json target",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.JSONScraperType,
+				URL:  "http://localhost:8080/stats",
+				JSON: &influxdb.JSONScraperConfig{
+					Measurement: "stats",
+					Fields:      []influxdb.JSONPathMapping{{Name: "hits", Path: "$.hits"}},
+				},
+			},
+		},
+		{
+			name: "json target requires a configuration",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.JSONScraperType,
+				URL:  "http://localhost:8080/stats",
+			},
+			wantErr: true,
+		},
+		{
+			name: "json target requires fields",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.JSONScraperType,
+				URL:  "http://localhost:8080/stats",
+				JSON: &influxdb.JSONScraperConfig{Measurement: "stats"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "graphite target",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.GraphiteScraperType,
+				URL:  "tcp://localhost:2003",
+			},
+		},
+		{
+			name: "graphite target requires a tcp url",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.GraphiteScraperType,
+				URL:  "http://localhost:2003",
+			},
+			wantErr: true,
+		},
+		{
+			name: "statsd target",
+			target: influxdb.ScraperTarget{
+				Type:   influxdb.StatsDScraperType,
+				URL:    "udp://:8125",
+				StatsD: &influxdb.StatsDScraperConfig{Percentiles: []float64{50, 99.9}},
+			},
+		},
+		{
+			name: "statsd target requires a port",
+			target: influxdb.ScraperTarget{
+				Type: influxdb.StatsDScraperType,
+				URL:  "udp://localhost",
+			},
+			wantErr: true,
+		},
+		{
+			name: "statsd percentiles are at most 100",
+			target: influxdb.ScraperTarget{
+				Type:   influxdb.StatsDScraperType,
+				URL:    "udp://:8125",
+				StatsD: &influxdb.StatsDScraperConfig{Percentiles: []float64{101}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.target.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("ScraperTarget.Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
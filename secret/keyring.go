@@ -0,0 +1,256 @@
+// Package secret provides the encryption of secrets at rest and the read-only
+// secret services of container deployments.
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+// KeySize is the size in bytes of the keys of a keyring, they are AES-256 keys.
+const KeySize = 32
+
+// Key is a key encryption key of a Keyring.
+type Key struct {
+	ID  string
+	Key []byte
+}
+
+// Keyring encrypts secrets with envelope encryption: each secret is encrypted
+// with its own data key, and the data key is encrypted with the primary key of
+// the keyring. The other keys of the keyring only decrypt the data keys
+// encrypted before the primary key was rotated.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring returns a keyring of keys, the first key being the primary key.
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "secret keyring requires at least one key",
+		}
+	}
+
+	k := &Keyring{
+		primary: keys[0].ID,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, key := range keys {
+		if key.ID == "" || strings.ContainsAny(key.ID, ":,\n") {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid secret key id %q", key.ID),
+			}
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("duplicate secret key id %q", key.ID),
+			}
+		}
+		if len(key.Key) != KeySize {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("secret key %q must be %d bytes long", key.ID, KeySize),
+			}
+		}
+		aead, err := newAEAD(key.Key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[key.ID] = aead
+	}
+	return k, nil
+}
+
+// ParseKeyring parses a keyring of keys in the form id:base64key, separated
+// by commas or new lines. The first key is the primary key. Empty lines and
+// lines starting with # are ignored.
+func ParseKeyring(s string) (*Keyring, error) {
+	var keys []Key
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "secret keys must be of the form id:base64key",
+			}
+		}
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("secret key %q is not base64 encoded", parts[0]),
+				Err:  err,
+			}
+		}
+		keys = append(keys, Key{ID: parts[0], Key: key})
+	}
+	return NewKeyring(keys...)
+}
+
+// ReadKeyringFile parses the keyring in the file at path.
+func ReadKeyringFile(path string) (*Keyring, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(string(b))
+}
+
+// PrimaryKeyID returns the ID of the key that encrypts the data keys.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// envelope is an encrypted secret. The nonces are prepended to the encrypted
+// data key and to the ciphertext.
+type envelope struct {
+	KeyID      string `json:"kid"`
+	DataKey    []byte `json:"dk"`
+	Ciphertext []byte `json:"ct"`
+}
+
+// IsEnvelope returns whether v is a secret encrypted by a keyring.
+func IsEnvelope(v []byte) bool {
+	return bytes.HasPrefix(v, []byte("{"))
+}
+
+// Encrypt encrypts plaintext with a new data key. The additional data is
+// authenticated and must be given to decrypt the secret, it binds the
+// ciphertext to where it is stored.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	dk := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dk); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dk)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := seal(aead, plaintext, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(k.keys[k.primary], dk, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{
+		KeyID:      k.primary,
+		DataKey:    wrapped,
+		Ciphertext: ct,
+	})
+}
+
+// Decrypt returns the plaintext of the secret v encrypted with the additional
+// data.
+func (k *Keyring) Decrypt(v, additionalData []byte) ([]byte, error) {
+	env, dk, err := k.open(v)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dk)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := unseal(aead, env.Ciphertext, additionalData)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to decrypt secret",
+			Err:  err,
+		}
+	}
+	return plaintext, nil
+}
+
+// Rewrap encrypts the data key of the secret v with the primary key. It
+// returns false if the data key already was encrypted with the primary key.
+func (k *Keyring) Rewrap(v []byte) ([]byte, bool, error) {
+	env, dk, err := k.open(v)
+	if err != nil {
+		return nil, false, err
+	}
+	if env.KeyID == k.primary {
+		return v, false, nil
+	}
+
+	if env.DataKey, err = seal(k.keys[k.primary], dk, nil); err != nil {
+		return nil, false, err
+	}
+	env.KeyID = k.primary
+	v, err = json.Marshal(env)
+	return v, true, err
+}
+
+// open returns the envelope v and its decrypted data key.
+func (k *Keyring) open(v []byte) (*envelope, []byte, error) {
+	env := &envelope{}
+	if err := json.Unmarshal(v, env); err != nil {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "encrypted secret is corrupt",
+			Err:  err,
+		}
+	}
+	kek, ok := k.keys[env.KeyID]
+	if !ok {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("secret is encrypted with unknown key %q", env.KeyID),
+		}
+	}
+	dk, err := unseal(kek, env.DataKey, nil)
+	if err != nil {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to decrypt data key with key %q", env.KeyID),
+			Err:  err,
+		}
+	}
+	return env, dk, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and prepends the random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func unseal(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,115 @@
+package secret_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/influxdata/influxdb/secret"
+)
+
+func key(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, secret.KeySize))
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		primary string
+		wantErr bool
+	}{
+		{
+			name:    "one key",
+			s:       "k1:" + key(1),
+			primary: "k1",
+		},
+		{
+			name:    "keys separated by new lines with comments",
+			s:       "# rotated on 2019-10-01\nk2:" + key(2) + "\n\nk1:" + key(1) + "\n",
+			primary: "k2",
+		},
+		{
+			name:    "keys separated by commas",
+			s:       "k2:" + key(2) + ",k1:" + key(1),
+			primary: "k2",
+		},
+		{
+			name:    "no keys",
+			s:       "# empty",
+			wantErr: true,
+		},
+		{
+			name:    "key without id",
+			s:       key(1),
+			wantErr: true,
+		},
+		{
+			name:    "short key",
+			s:       "k1:" + base64.StdEncoding.EncodeToString([]byte("short")),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate ids",
+			s:       "k1:" + key(1) + ",k1:" + key(2),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := secret.ParseKeyring(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && k.PrimaryKeyID() != tt.primary {
+				t.Errorf("expected primary key %q, got %q", tt.primary, k.PrimaryKeyID())
+			}
+		})
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	k1, err := secret.ParseKeyring("k1:" + key(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := k1.Encrypt([]byte("abc123"), []byte("org/api_key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secret.IsEnvelope(v) || bytes.Contains(v, []byte("abc123")) {
+		t.Fatalf("unexpected encrypted secret %s", v)
+	}
+
+	if _, err := k1.Decrypt(v, []byte("org/other_key")); err == nil {
+		t.Error("expected decrypting with other additional data to fail")
+	}
+
+	k2, err := secret.ParseKeyring("k2:" + key(2) + ",k1:" + key(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrapped, ok, err := k2.Rewrap(v)
+	if err != nil || !ok {
+		t.Fatalf("expected secret to be rewrapped: %v", err)
+	}
+	if _, ok, _ := k2.Rewrap(rewrapped); ok {
+		t.Error("expected secret encrypted with the primary key not to be rewrapped")
+	}
+	if _, err := k1.Decrypt(rewrapped, []byte("org/api_key")); err == nil {
+		t.Error("expected decrypting with a keyring without the primary key to fail")
+	}
+
+	onlyK2, err := secret.ParseKeyring("k2:" + key(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := onlyK2.Decrypt(rewrapped, []byte("org/api_key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "abc123" {
+		t.Errorf("expected abc123, got %q", plaintext)
+	}
+}
@@ -0,0 +1,193 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+// ErrReadOnly is returned when writing secrets to a read-only secret service.
+var ErrReadOnly = &influxdb.Error{
+	Code: influxdb.EMethodNotAllowed,
+	Msg:  "secret store is read-only",
+}
+
+var errSecretNotFound = &influxdb.Error{
+	Code: influxdb.ENotFound,
+	Msg:  influxdb.ErrSecretNotFound,
+}
+
+// readOnly implements the write methods of influxdb.SecretService for the
+// read-only services.
+type readOnly struct{}
+
+// PutSecret returns ErrReadOnly.
+func (readOnly) PutSecret(ctx context.Context, orgID influxdb.ID, k string, v string) error {
+	return ErrReadOnly
+}
+
+// PutSecrets returns ErrReadOnly.
+func (readOnly) PutSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	return ErrReadOnly
+}
+
+// PatchSecrets returns ErrReadOnly.
+func (readOnly) PatchSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	return ErrReadOnly
+}
+
+// DeleteSecret returns ErrReadOnly.
+func (readOnly) DeleteSecret(ctx context.Context, orgID influxdb.ID, ks ...string) error {
+	return ErrReadOnly
+}
+
+// DefaultEnvPrefix is the prefix of the environment variables of the secrets
+// of EnvService.
+const DefaultEnvPrefix = "INFLUXD_SECRETS_"
+
+var _ influxdb.SecretService = (*EnvService)(nil)
+
+// EnvService is a read-only secret service of the secrets set as environment
+// variables of the form <prefix><orgID>_<key>=<value>, as in
+// INFLUXD_SECRETS_0000000000000001_api_key=abc123.
+type EnvService struct {
+	readOnly
+	secrets map[influxdb.ID]map[string]string
+}
+
+// NewEnvService returns an EnvService of the secrets of environ, a list of
+// key=value pairs as returned by os.Environ.
+func NewEnvService(prefix string, environ []string) *EnvService {
+	s := &EnvService{
+		secrets: make(map[influxdb.ID]map[string]string),
+	}
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, prefix) {
+			continue
+		}
+		parts := strings.SplitN(kv[len(prefix):], "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		orgID, key, ok := parseOrgKey(parts[0])
+		if !ok {
+			continue
+		}
+		if s.secrets[orgID] == nil {
+			s.secrets[orgID] = make(map[string]string)
+		}
+		s.secrets[orgID][key] = parts[1]
+	}
+	return s
+}
+
+// parseOrgKey parses <orgID>_<key>.
+func parseOrgKey(s string) (influxdb.ID, string, bool) {
+	parts := strings.SplitN(s, "_", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", false
+	}
+	var orgID influxdb.ID
+	if err := orgID.DecodeFromString(parts[0]); err != nil {
+		return 0, "", false
+	}
+	return orgID, parts[1], true
+}
+
+// LoadSecret retrieves the secret value v found at key k for organization orgID.
+func (s *EnvService) LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+	v, ok := s.secrets[orgID][k]
+	if !ok {
+		return "", errSecretNotFound
+	}
+	return v, nil
+}
+
+// GetSecretKeys retrieves all secret keys that are stored for the organization orgID.
+func (s *EnvService) GetSecretKeys(ctx context.Context, orgID influxdb.ID) ([]string, error) {
+	keys := make([]string, 0, len(s.secrets[orgID]))
+	for k := range s.secrets[orgID] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+var _ influxdb.SecretService = (*FileService)(nil)
+
+// FileService is a read-only secret service of the secrets stored in files
+// at <dir>/<orgID>/<key>, such as the secrets mounted as volumes in
+// containers. The files are read on each call so that updates of the mounted
+// secrets are seen.
+type FileService struct {
+	readOnly
+	dir string
+}
+
+// NewFileService returns a FileService of the secrets in dir.
+func NewFileService(dir string) (*FileService, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("secret path %q is not a directory", dir)
+	}
+	return &FileService{dir: dir}, nil
+}
+
+// LoadSecret retrieves the secret value v found at key k for organization orgID.
+func (s *FileService) LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+	if !validFileKey(k) {
+		return "", errSecretNotFound
+	}
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, orgID.String(), k))
+	if os.IsNotExist(err) {
+		return "", errSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	// Files written by editors or echo end with a new line that is not part
+	// of the secret.
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// GetSecretKeys retrieves all secret keys that are stored for the organization orgID.
+func (s *FileService) GetSecretKeys(ctx context.Context, orgID influxdb.ID) ([]string, error) {
+	dir := filepath.Join(s.dir, orgID.String())
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fis))
+	for _, fi := range fis {
+		if !validFileKey(fi.Name()) {
+			continue
+		}
+		// Kubernetes mounts secrets as symbolic links to files.
+		fi, err := os.Stat(filepath.Join(dir, fi.Name()))
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		keys = append(keys, fi.Name())
+	}
+	return keys, nil
+}
+
+// validFileKey returns whether k names a secret file of an organization
+// directory. Hidden files are not secrets, Kubernetes stores the data of
+// mounted secrets in them.
+func validFileKey(k string) bool {
+	return k != "" && !strings.HasPrefix(k, ".") && !strings.ContainsAny(k, `/\`)
+}
@@ -0,0 +1,78 @@
+package secret_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/secret"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestEnvService(t *testing.T) {
+	influxdbtesting.ReadOnlySecretService(initEnvService, t)
+}
+
+func initEnvService(f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	environ := []string{"PATH=/bin", secret.DefaultEnvPrefix + "STORE=bolt"}
+	for _, s := range f.Secrets {
+		for k, v := range s.Env {
+			environ = append(environ, secret.DefaultEnvPrefix+s.OrganizationID.String()+"_"+k+"="+v)
+		}
+	}
+	return secret.NewEnvService(secret.DefaultEnvPrefix, environ), func() {}
+}
+
+func TestFileService(t *testing.T) {
+	influxdbtesting.ReadOnlySecretService(initFileService, t)
+}
+
+func initFileService(f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range f.Secrets {
+		orgDir := filepath.Join(dir, s.OrganizationID.String())
+		if err := os.MkdirAll(filepath.Join(orgDir, "..data"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range s.Env {
+			if err := ioutil.WriteFile(filepath.Join(orgDir, k), []byte(v+"\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	svc, err := secret.NewFileService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestReadOnlyServices(t *testing.T) {
+	ctx := context.Background()
+	svc, done := initFileService(influxdbtesting.SecretServiceFields{}, t)
+	defer done()
+
+	for _, s := range []influxdb.SecretService{svc, secret.NewEnvService(secret.DefaultEnvPrefix, nil)} {
+		if _, err := s.LoadSecret(ctx, 1, "missing"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("expected not found error, got %v", err)
+		}
+		if _, err := s.LoadSecret(ctx, 1, "../../etc/passwd"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("expected not found error, got %v", err)
+		}
+		if err := s.PutSecret(ctx, 1, "k", "v"); err != secret.ErrReadOnly {
+			t.Errorf("expected read-only error, got %v", err)
+		}
+		if err := s.DeleteSecret(ctx, 1, "k"); err != secret.ErrReadOnly {
+			t.Errorf("expected read-only error, got %v", err)
+		}
+	}
+}
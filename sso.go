@@ -0,0 +1,22 @@
+package influxdb
+
+import "context"
+
+// Ops for sso errors.
+const (
+	OpSSOAuthCodeURL = "SSOAuthCodeURL"
+	OpSSOLogin       = "SSOLogin"
+)
+
+// SSOService authenticates users with an external identity provider.
+type SSOService interface {
+	// AuthCodeURL returns the URL of the login page of the identity provider.
+	// The provider redirects back with a code and state once the user is
+	// authenticated, nonce is bound to the identity it returns.
+	AuthCodeURL(ctx context.Context, state, nonce string) (string, error)
+
+	// Login exchanges a code returned by the identity provider for the identity
+	// of the user, creating the user if it does not exist, and returns a new
+	// session for the user.
+	Login(ctx context.Context, code, nonce string) (*Session, error)
+}
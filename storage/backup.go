@@ -0,0 +1,391 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+var _ platform.BackupService = (*Engine)(nil)
+var _ platform.RestoreService = (*Engine)(nil)
+
+// restoreBatchSize is the number of series added to the index at a time when
+// restoring a TSM file.
+const restoreBatchSize = 10000
+
+// CreateBackup snapshots the cache and creates hard links to every TSM file in a
+// new backup directory. If filter selects a subset of the data, each file is
+// rewritten to contain only that subset, with any deleted data removed.
+//
+// Backups are stored in the engine's temporary directory space, so they are
+// removed when the engine is next opened.
+func (e *Engine) CreateBackup(ctx context.Context, filter platform.BackupFilter) (*platform.Backup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	opts, err := backupCopyOptions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// The engine lock must not be held while the cache is snapshotted, as the
+	// snapshot acquires it when committing WAL segments.
+	e.mu.RLock()
+	closed := e.closing == nil
+	e.mu.RUnlock()
+	if closed {
+		return nil, ErrEngineClosed
+	}
+
+	path, err := e.engine.CreateBackup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), "."+tsm1.TmpTSMFileExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts != nil {
+		if err := filterBackup(path, *opts); err != nil {
+			os.RemoveAll(path)
+			return nil, err
+		}
+	}
+
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &platform.Backup{ID: id, Files: make([]string, 0, len(fis))}
+	for _, fi := range fis {
+		backup.Files = append(backup.Files, fi.Name())
+	}
+	return backup, nil
+}
+
+// backupCopyOptions returns the options needed to rewrite TSM files for filter,
+// or nil if filter selects all data.
+func backupCopyOptions(filter platform.BackupFilter) (*tsm1.CopyOptions, error) {
+	if filter.OrgID == nil && filter.BucketID == nil && filter.Start == nil && filter.Stop == nil {
+		return nil, nil
+	}
+
+	var prefix []byte
+	switch {
+	case filter.BucketID != nil && filter.OrgID == nil:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "an organization is required to back up a bucket",
+		}
+	case filter.BucketID != nil:
+		name := tsdb.EncodeName(*filter.OrgID, *filter.BucketID)
+		prefix = append(models.EscapeMeasurement(name[:]), ',')
+	case filter.OrgID != nil:
+		name := tsdb.EncodeOrgName(*filter.OrgID)
+		prefix = models.EscapeMeasurement(name[:])
+	}
+
+	opts := tsm1.NewCopyOptions(prefix)
+	if filter.Start != nil {
+		opts.MinTime = filter.Start.UnixNano()
+	}
+	if filter.Stop != nil {
+		opts.MaxTime = filter.Stop.UnixNano()
+	}
+	if opts.MinTime > opts.MaxTime {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "start must not be after stop",
+		}
+	}
+	return &opts, nil
+}
+
+// BucketCopyOptions returns options for tsm1.Copy that select all of the data of
+// the source bucket, rewriting each key to belong to the destination bucket.
+func BucketCopyOptions(srcOrgID, srcBucketID, orgID, bucketID platform.ID) tsm1.CopyOptions {
+	src := tsdb.EncodeName(srcOrgID, srcBucketID)
+	dst := tsdb.EncodeName(orgID, bucketID)
+
+	opts := tsm1.NewCopyOptions(append(models.EscapeMeasurement(src[:]), ','))
+	opts.ReplacePrefix = append(models.EscapeMeasurement(dst[:]), ',')
+	return opts
+}
+
+// filterBackup rewrites every TSM file in the backup directory at path so that
+// it only contains data selected by opts. Tombstones are applied while the
+// files are rewritten and then removed.
+func filterBackup(path string, opts tsm1.CopyOptions) error {
+	files, err := filepath.Glob(filepath.Join(path, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := filterBackupFile(file, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func filterBackupFile(file string, opts tsm1.CopyOptions) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	tmp := file + "." + tsm1.TmpTSMFileExtension
+	out, err := os.Create(tmp)
+	if err != nil {
+		r.Close()
+		return err
+	}
+
+	w, err := tsm1.NewTSMWriter(out)
+	if err != nil {
+		out.Close()
+		r.Close()
+		return err
+	}
+
+	n, err := tsm1.Copy(r, w, opts)
+	if err == nil && n > 0 {
+		err = w.WriteIndex()
+	}
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		r.Close()
+		return err
+	}
+
+	// Removing the reader removes the hard links to the TSM file and its
+	// tombstones. The files in the engine are unaffected. Statistics are not
+	// part of a backup, so the file written by the TSM writer is removed too.
+	if err := r.Close(); err != nil {
+		return err
+	}
+	if err := r.Remove(); err != nil {
+		return err
+	}
+	if err := os.Remove(tsm1.StatsFilename(tmp)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if n == 0 {
+		return os.Remove(tmp)
+	}
+	return os.Rename(tmp, file)
+}
+
+// FetchBackupFile writes the named file from the backup to w.
+func (e *Engine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if backupFile != filepath.Base(backupFile) || strings.HasPrefix(backupFile, ".") {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup file %q", backupFile),
+		}
+	}
+
+	f, err := os.Open(filepath.Join(e.InternalBackupPath(backupID), backupFile))
+	if os.IsNotExist(err) {
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("backup file %q not found", backupFile),
+		}
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// DeleteBackup removes the backup and all of its files.
+func (e *Engine) DeleteBackup(ctx context.Context, backupID int) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	path := e.InternalBackupPath(backupID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("backup %d not found", backupID),
+		}
+	} else if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// InternalBackupPath returns the path of the directory containing the backup.
+func (e *Engine) InternalBackupPath(backupID int) string {
+	return filepath.Join(e.engine.Path(), fmt.Sprintf("%d.%s", backupID, tsm1.TmpTSMFileExtension))
+}
+
+// RestoreBucket imports the TSM file read from r into the bucket. Every key in
+// the file must belong to the bucket. Series in the file are added to the index
+// before the file is made available to queries; a series whose field type
+// conflicts with existing data fails the restore.
+func (e *Engine) RestoreBucket(ctx context.Context, orgID, bucketID platform.ID, r io.Reader) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	// Temporary TSM files are removed by the engine when it is opened, so a
+	// failed restore never leaves partial data behind.
+	f, err := ioutil.TempFile(e.engine.Path(), "restore-*."+tsm1.CompactionTempExtension)
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	tsm, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid TSM file",
+			Err:  err,
+		}
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	prefix := append(models.EscapeMeasurement(encoded[:]), ',')
+	if err := e.indexTSM(tsm, prefix); err != nil {
+		tsm.Close()
+		return err
+	}
+	if err := tsm.Close(); err != nil {
+		return err
+	}
+
+	return e.engine.ImportFile(path)
+}
+
+// indexTSM adds every series in the TSM file to the index, ensuring each key
+// begins with prefix.
+func (e *Engine) indexTSM(r *tsm1.TSMReader, prefix []byte) error {
+	collection := &tsdb.SeriesCollection{
+		Keys:  make([][]byte, 0, restoreBatchSize),
+		Names: make([][]byte, 0, restoreBatchSize),
+		Tags:  make([]models.Tags, restoreBatchSize),
+		Types: make([]models.FieldType, 0, restoreBatchSize),
+	}
+
+	flush := func() error {
+		if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
+			return err
+		}
+		if err := collection.PartialWriteError(); err != nil {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  "unable to restore series",
+				Err:  err,
+			}
+		}
+		collection.Truncate(0)
+		collection.Tags = collection.Tags[:restoreBatchSize]
+		return nil
+	}
+
+	var ti int
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "TSM file contains data for another bucket",
+			}
+		}
+
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		var name []byte
+		name, collection.Tags[ti] = models.ParseKeyBytesWithTags(seriesKey, collection.Tags[ti])
+
+		collection.Keys = append(collection.Keys, seriesKey)
+		collection.Names = append(collection.Names, name)
+		collection.Types = append(collection.Types, blockTypeToFieldType(iter.Type()))
+		ti++
+
+		if len(collection.Keys) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			ti = 0
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(collection.Keys) > 0 {
+		collection.Truncate(ti)
+		return flush()
+	}
+	return nil
+}
+
+func blockTypeToFieldType(typ byte) models.FieldType {
+	switch typ {
+	case tsm1.BlockFloat64:
+		return models.Float
+	case tsm1.BlockInteger:
+		return models.Integer
+	case tsm1.BlockBoolean:
+		return models.Boolean
+	case tsm1.BlockString:
+		return models.String
+	case tsm1.BlockUnsigned:
+		return models.Unsigned
+	default:
+		return models.Empty
+	}
+}
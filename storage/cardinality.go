@@ -0,0 +1,304 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// encodedNameLength is the length of a measurement name that encodes an
+// organization and bucket ID.
+const encodedNameLength = 16
+
+// seriesLimiter tracks the number of series of every organization and bucket
+// and enforces the maximum number of series of each. A limit of zero means
+// there is no limit.
+//
+// A new series is reserved when it is accepted and released again if none of
+// its points are written, so only new series written by concurrent writes may
+// be over-counted until the bucket is counted again from the index, on open or
+// after a delete.
+type seriesLimiter struct {
+	maxPerOrg    int
+	maxPerBucket int
+
+	mu      sync.Mutex
+	orgs    map[platform.ID]int
+	buckets map[platform.ID]*bucketSeries
+
+	tracker *seriesLimitTracker
+}
+
+// bucketSeries is the number of series of a bucket.
+type bucketSeries struct {
+	orgID platform.ID
+	n     int
+}
+
+func newSeriesLimiter(maxPerOrg, maxPerBucket int) *seriesLimiter {
+	return &seriesLimiter{
+		maxPerOrg:    maxPerOrg,
+		maxPerBucket: maxPerBucket,
+		orgs:         make(map[platform.ID]int),
+		buckets:      make(map[platform.ID]*bucketSeries),
+		tracker:      newSeriesLimitTracker(newSeriesLimitMetrics(nil), nil),
+	}
+}
+
+// SetDefaultMetricLabels sets the default labels for the series limit metrics.
+func (l *seriesLimiter) SetDefaultMetricLabels(defaultLabels prometheus.Labels) {
+	mmu.Lock()
+	defer mmu.Unlock()
+	if slms == nil {
+		slms = newSeriesLimitMetrics(defaultLabels)
+	}
+	l.tracker = newSeriesLimitTracker(slms, defaultLabels)
+}
+
+// load replaces the tracked number of series with the cardinality of every
+// bucket in the index.
+func (l *seriesLimiter) load(stats tsi1.MeasurementCardinalityStats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.orgs = make(map[platform.ID]int)
+	l.buckets = make(map[platform.ID]*bucketSeries)
+	for name, n := range stats {
+		if len(name) != encodedNameLength {
+			continue
+		}
+		orgID, bucketID := tsdb.DecodeNameSlice([]byte(name))
+		l.orgs[orgID] += n
+		l.buckets[bucketID] = &bucketSeries{orgID: orgID, n: n}
+	}
+}
+
+// set sets the number of series of a bucket.
+func (l *seriesLimiter) set(orgID, bucketID platform.ID, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.buckets[bucketID]
+	if b == nil {
+		b = &bucketSeries{orgID: orgID}
+		l.buckets[bucketID] = b
+	}
+	l.orgs[orgID] += n - b.n
+	b.n = n
+	if n == 0 {
+		delete(l.buckets, bucketID)
+	}
+	if l.orgs[orgID] <= 0 {
+		delete(l.orgs, orgID)
+	}
+}
+
+// reserve accounts for a new series in the bucket named name, the encoded
+// organization and bucket ID. It returns a reason to drop the series if it
+// would exceed the limit of the organization or bucket.
+func (l *seriesLimiter) reserve(name []byte) string {
+	if len(name) != encodedNameLength {
+		return ""
+	}
+	orgID, bucketID := tsdb.DecodeNameSlice(name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.buckets[bucketID]
+	if b == nil {
+		b = &bucketSeries{orgID: orgID}
+		l.buckets[bucketID] = b
+	}
+
+	if l.maxPerBucket > 0 && b.n >= l.maxPerBucket {
+		l.tracker.IncRejections("bucket")
+		return fmt.Sprintf("series limit exceeded: bucket %s has reached the maximum of %d series", bucketID, l.maxPerBucket)
+	}
+	if l.maxPerOrg > 0 && l.orgs[orgID] >= l.maxPerOrg {
+		l.tracker.IncRejections("org")
+		return fmt.Sprintf("series limit exceeded: organization %s has reached the maximum of %d series", orgID, l.maxPerOrg)
+	}
+
+	b.n++
+	l.orgs[orgID]++
+	return ""
+}
+
+// release gives back a series reserved in the bucket named name.
+func (l *seriesLimiter) release(name []byte) {
+	if len(name) != encodedNameLength {
+		return
+	}
+	orgID, bucketID := tsdb.DecodeNameSlice(name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b := l.buckets[bucketID]; b != nil {
+		if b.n--; b.n <= 0 {
+			delete(l.buckets, bucketID)
+		}
+	}
+	if l.orgs[orgID]--; l.orgs[orgID] <= 0 {
+		delete(l.orgs, orgID)
+	}
+}
+
+// limitSeries drops the points of collection that would create a new series in
+// an organization or bucket that has reached its limit. It returns the number
+// of points dropped and the names of the new series reserved, by key.
+func (e *Engine) limitSeries(collection *tsdb.SeriesCollection) (int, map[string][]byte) {
+	var (
+		buf      []byte
+		dropped  int
+		j        int
+		reserved = make(map[string][]byte)
+	)
+
+	// A batch can contain several points of the same new series, which must
+	// be counted once. The reason is empty for the series that were created.
+	created := make(map[string]string)
+	for iter := collection.Iterator(); iter.Next(); {
+		reason, seen := created[string(iter.Key())]
+		if !seen {
+			id := e.sfile.SeriesID(iter.Name(), iter.Tags(), buf)
+			if id.IsZero() || e.sfile.IsDeleted(id) {
+				reason = e.seriesLimiter.reserve(iter.Name())
+				created[string(iter.Key())] = reason
+				if reason == "" {
+					reserved[string(iter.Key())] = iter.Name()
+				}
+			}
+		}
+
+		if reason != "" {
+			collection.Drop(iter.Index(), reason)
+			dropped++
+			continue
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+
+	return dropped, reserved
+}
+
+// releaseSeries gives back the reserved series that were not created by the
+// write of collection: their points were dropped while creating the series,
+// e.g. for a field type conflict, or the write failed before creating them.
+func (e *Engine) releaseSeries(collection *tsdb.SeriesCollection, reserved map[string][]byte) {
+	for i, id := range collection.SeriesIDs {
+		if !id.IsZero() && i < len(collection.Keys) {
+			delete(reserved, string(collection.Keys[i]))
+		}
+	}
+	for _, name := range reserved {
+		e.seriesLimiter.release(name)
+	}
+}
+
+// countBucketSeries resets the tracked number of series of a bucket to the
+// number of series in the index.
+func (e *Engine) countBucketSeries(orgID, bucketID platform.ID) error {
+	name := tsdb.EncodeName(orgID, bucketID)
+	itr, err := e.index.MeasurementSeriesIDIterator(name[:])
+	if err != nil {
+		return err
+	}
+
+	var n int
+	if itr != nil {
+		defer itr.Close()
+		for {
+			elem, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if elem.SeriesID.IsZero() {
+				break
+			}
+			n++
+		}
+	}
+
+	e.seriesLimiter.set(orgID, bucketID, n)
+	return nil
+}
+
+// GetUsage returns the number of series of the organization or bucket in the
+// filter, or of the whole engine without either. The range of the filter is
+// ignored since series are counted as they currently are.
+func (e *Engine) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	stats, err := e.index.MeasurementCardinalityStats()
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	for name, sn := range stats {
+		if len(name) != encodedNameLength {
+			continue
+		}
+		orgID, bucketID := tsdb.DecodeNameSlice([]byte(name))
+		if filter.OrgID != nil && orgID != *filter.OrgID {
+			continue
+		}
+		if filter.BucketID != nil && bucketID != *filter.BucketID {
+			continue
+		}
+		n += sn
+	}
+
+	return map[platform.UsageMetric]*platform.Usage{
+		platform.UsageSeries: {
+			OrganizationID: filter.OrgID,
+			BucketID:       filter.BucketID,
+			Type:           platform.UsageSeries,
+			Value:          float64(n),
+		},
+	}, nil
+}
+
+//
+// metrics tracker
+//
+
+type seriesLimitTracker struct {
+	metrics *seriesLimitMetrics
+	labels  prometheus.Labels
+}
+
+func newSeriesLimitTracker(metrics *seriesLimitMetrics, defaultLabels prometheus.Labels) *seriesLimitTracker {
+	return &seriesLimitTracker{metrics: metrics, labels: defaultLabels}
+}
+
+// Labels returns a copy of labels for use with series limit metrics.
+func (t *seriesLimitTracker) Labels() prometheus.Labels {
+	l := make(map[string]string, len(t.labels))
+	for k, v := range t.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// IncRejections signals that a new series was rejected by the limit of an
+// organization or bucket.
+func (t *seriesLimitTracker) IncRejections(limit string) {
+	labels := t.Labels()
+	labels["limit"] = limit
+	t.metrics.Rejections.With(labels).Inc()
+}
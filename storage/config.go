@@ -24,6 +24,11 @@ type Config struct {
 	// Frequency of retention in seconds.
 	RetentionInterval toml.Duration `toml:"retention-interval"`
 
+	// Maximum number of series of an organization and of a bucket. New series
+	// beyond these limits are rejected on write. Zero means no limit.
+	MaxSeriesPerOrg    int `toml:"max-series-per-org"`
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
@@ -43,6 +43,9 @@ type Engine struct {
 	engine            *tsm1.Engine
 	wal               *wal.WAL
 	retentionEnforcer *retentionEnforcer
+	schemaFinder      SchemaFinder
+	taskFinder        TaskFinder
+	seriesLimiter     *seriesLimiter
 
 	defaultMetricLabels prometheus.Labels
 
@@ -97,6 +100,22 @@ func WithRetentionEnforcer(finder BucketFinder) Option {
 	}
 }
 
+// WithDownsampleTaskFinder makes the retention enforcer keep the data of
+// buckets with downsample tiers until their tasks have downsampled it.
+func WithDownsampleTaskFinder(finder TaskFinder) Option {
+	return func(e *Engine) {
+		e.taskFinder = finder
+	}
+}
+
+// WithSchemaEnforcer makes the engine reject points written to buckets with an
+// explicit schema that do not match the measurement schemas of the bucket.
+func WithSchemaEnforcer(finder SchemaFinder) Option {
+	return func(e *Engine) {
+		e.schemaFinder = finder
+	}
+}
+
 // WithFileStoreObserver makes the engine have the provided file store observer.
 func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
 	return func(e *Engine) {
@@ -138,17 +157,29 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine,
 		tsm1.WithSnapshotter(e))
 
+	// Initialise series limits.
+	if c.MaxSeriesPerOrg > 0 || c.MaxSeriesPerBucket > 0 {
+		e.seriesLimiter = newSeriesLimiter(c.MaxSeriesPerOrg, c.MaxSeriesPerBucket)
+	}
+
 	// Apply options.
 	for _, option := range options {
 		option(e)
 	}
 
+	if e.retentionEnforcer != nil && e.taskFinder != nil {
+		e.retentionEnforcer.TaskService = e.taskFinder
+	}
+
 	// Set default metrics labels.
 	e.engine.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.sfile.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.index.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.retentionEnforcer.SetDefaultMetricLabels(e.defaultMetricLabels)
+	if e.seriesLimiter != nil {
+		e.seriesLimiter.SetDefaultMetricLabels(e.defaultMetricLabels)
+	}
 
 	return e
 }
@@ -182,6 +213,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, RetentionPrometheusCollectors()...)
+	metrics = append(metrics, SeriesLimitPrometheusCollectors()...)
 	return metrics
 }
 
@@ -212,6 +244,14 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		return err
 	}
 
+	if e.seriesLimiter != nil {
+		stats, err := e.index.MeasurementCardinalityStats()
+		if err != nil {
+			return err
+		}
+		e.seriesLimiter.load(stats)
+	}
+
 	e.closing = make(chan struct{})
 
 	// TODO(edd) background tasks will be run in priority order via a scheduler.
@@ -370,38 +410,39 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 // WritePoints writes the provided points to the engine.
 //
 // The Engine expects all points to have been correctly validated by the caller.
-// However, WritePoints will determine if any tag key-pairs are missing, or if
-// there are any field type conflicts.
+// However, WritePoints will determine if any tag key-pairs are missing, if
+// there are any field type conflicts, if points written to a bucket with an
+// explicit schema do not match the schema, or if points would create a series
+// beyond the series limit of their organization or bucket.
 //
-// Appropriate errors are returned in those cases.
+// Appropriate errors are returned in those cases. If any series were rejected
+// by a series limit, the returned error has the code EUnprocessableEntity and
+// wraps the tsdb.PartialWriteError.
 func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
 	collection, j := tsdb.NewSeriesCollection(points), 0
 
-	// dropPoint should be called whenever there is reason to drop a point from
-	// the batch.
-	dropPoint := func(key []byte, reason string) {
-		if collection.Reason == "" {
-			collection.Reason = reason
-		}
-		collection.Dropped++
-		collection.DroppedKeys = append(collection.DroppedKeys, key)
+	var schemas *schemaCache
+	if e.schemaFinder != nil {
+		schemas = newSchemaCache(e.schemaFinder)
 	}
 
+	// collection.Drop should be called whenever there is reason to drop a point
+	// from the batch.
 	for iter := collection.Iterator(); iter.Next(); {
 		tags := iter.Tags()
 
 		// Not enough tags present.
 		if tags.Len() < 2 {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
 			continue
 		}
 
 		// First tag key is not measurement tag.
 		if !bytes.Equal(tags[0].Key, models.MeasurementTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
 			continue
 		}
 
@@ -409,29 +450,41 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 
 		// Last tag key is not field tag.
 		if !bytes.Equal(fkey, models.FieldKeyTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
 			continue
 		}
 
 		// The value representing the underlying field key is invalid if it's "time".
 		if bytes.Equal(fval, timeBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
+			collection.Drop(iter.Index(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
 			continue
 		}
 
 		// Filter out any tags with key equal to "time": they are invalid.
 		if tags.Get(timeBytes) != nil {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
+			collection.Drop(iter.Index(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
 			continue
 		}
 
 		// Drop any point with invalid unicode characters in any of the tag keys or values.
 		// This will also cover validating the value used to represent the field key.
 		if !models.ValidTagTokens(tags) {
-			dropPoint(iter.Key(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
+			collection.Drop(iter.Index(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
 			continue
 		}
 
+		// Drop any point that does not match the explicit schema of its bucket.
+		if schemas != nil {
+			schema, err := schemas.lookup(ctx, iter.Name())
+			if err != nil {
+				return err
+			}
+			if reason := schema.validate(tags, iter.Type()); reason != "" {
+				collection.Drop(iter.Index(), reason)
+				continue
+			}
+		}
+
 		collection.Copy(j, iter.Index())
 		j++
 	}
@@ -444,6 +497,14 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop any point that would create a series beyond the series limits.
+	var limited int
+	if e.seriesLimiter != nil {
+		var reserved map[string][]byte
+		limited, reserved = e.limitSeries(collection)
+		defer e.releaseSeries(collection, reserved)
+	}
+
 	// Convert the collection to values for adding to the WAL/Cache.
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
@@ -455,7 +516,16 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return err
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	err = e.writePointsLocked(ctx, collection, values)
+	if _, ok := err.(tsdb.PartialWriteError); ok && limited > 0 {
+		return &platform.Error{
+			Code: platform.EUnprocessableEntity,
+			Op:   "storage/WritePoints",
+			Msg:  fmt.Sprintf("series limit exceeded: %d points of new series were rejected", limited),
+			Err:  err,
+		}
+	}
+	return err
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
@@ -552,7 +622,7 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID platform
 
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
 // deleted must be in [min, max], and the key must match the predicate if provided.
-func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred tsm1.Predicate) error {
+func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -563,9 +633,12 @@ func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID
 	}
 
 	// Marshal the predicate to add it to the WAL.
-	predData, err := pred.Marshal()
-	if err != nil {
-		return err
+	var predData []byte
+	if pred != nil {
+		var err error
+		if predData, err = pred.Marshal(); err != nil {
+			return err
+		}
 	}
 
 	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
@@ -584,7 +657,15 @@ func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID pl
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	name := models.EscapeMeasurement(encoded[:])
 
-	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
+	if err := e.engine.DeletePrefixRange(ctx, name, min, max, pred); err != nil {
+		return err
+	}
+
+	// Deleted series no longer count towards the series limits.
+	if e.seriesLimiter != nil {
+		return e.countBucketSeries(orgID, bucketID)
+	}
+	return nil
 }
 
 // SeriesCardinality returns the number of series in the engine.
@@ -6,11 +6,14 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/prom/promtest"
+	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
@@ -476,9 +479,224 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 			time.Unix(1, 2),
 		),
 	})
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+	if got, exp := len(pwe.DroppedPoints), 1; got != exp {
+		t.Fatalf("unexpected number of dropped points: got %d, exp %d", got, exp)
+	}
+	if fields, _ := pwe.DroppedPoints[0].Point.Fields(); fields["value"] != int64(2) {
+		t.Errorf("unexpected dropped point: %s", pwe.DroppedPoints[0].Point)
+	}
+	if got, exp := pwe.DroppedPoints[0].Reason, "conflicting field type"; !strings.HasPrefix(got, exp) {
+		t.Errorf("unexpected reason: got %q, exp prefix %q", got, exp)
+	}
+}
+
+type schemaFinder struct {
+	*mock.BucketService
+	*mock.BucketSchemaService
+}
+
+func TestEngine_WriteExplicitSchema(t *testing.T) {
+	finder := schemaFinder{mock.NewBucketService(), mock.NewBucketSchemaService()}
+	finder.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, SchemaType: influxdb.SchemaTypeExplicit}, nil
+	}
+	finder.FindMeasurementSchemasF = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+		return []*influxdb.MeasurementSchema{{
+			BucketID: filter.BucketID,
+			Name:     "cpu",
+			Tags:     []string{"host"},
+			Fields: []influxdb.MeasurementSchemaField{
+				{Name: "value", Type: influxdb.SchemaFieldTypeFloat},
+			},
+		}}, nil
+	}
+
+	engine := NewEngine(storage.NewConfig(), storage.WithSchemaEnforcer(finder))
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(measurement string, tags map[string]string, field string, value interface{}) models.Point {
+		tags[models.MeasurementTagKey] = measurement
+		tags[models.FieldKeyTagKey] = field
+		return models.MustNewPoint(name, models.NewTags(tags), map[string]interface{}{field: value}, time.Unix(1, 2))
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		point("cpu", map[string]string{"host": "a"}, "value", 1.0),
+		point("cpu", map[string]string{"host": "a"}, "value", "1"),
+		point("cpu", map[string]string{"region": "west"}, "value", 1.0),
+		point("cpu", map[string]string{"host": "a"}, "usage", 1.0),
+		point("mem", map[string]string{}, "value", 1.0),
+	})
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+
+	var reasons []string
+	for _, p := range pwe.DroppedPoints {
+		reasons = append(reasons, p.Reason)
+	}
+	exp := []string{
+		`schema violation: field "value" on measurement "cpu" is of type float, got string`,
+		`schema violation: tag "region" is not declared on measurement "cpu"`,
+		`schema violation: field "usage" is not declared on measurement "cpu"`,
+		`schema violation: measurement "mem" is not declared`,
+	}
+	if !cmp.Equal(reasons, exp) {
+		t.Errorf("unexpected reasons -got/+exp\n%s", cmp.Diff(reasons, exp))
+	}
+	if got, exp := engine.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %v series, exp %v series in index", got, exp)
+	}
+}
+
+func TestEngine_ReadMeasurementSchemas(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	schemas, err := engine.ReadMeasurementSchemas(context.TODO(), engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 0 {
+		t.Fatalf("unexpected schemas of empty bucket: %v", schemas)
+	}
+
+	name := tsdb.EncodeName(engine.org, engine.bucket)
+	points, err := models.ParsePointsWithPrecision([]byte(
+		"cpu,host=a,region=west usage=1,cores=4i\n"+
+			"cpu,host=b up=true\n"+
+			"mem free=10i,name=\"x\"\n"), name[:], time.Now(), "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err = engine.ReadMeasurementSchemas(context.TODO(), engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*influxdb.MeasurementSchema{
+		{
+			OrgID:    engine.org,
+			BucketID: engine.bucket,
+			Name:     "cpu",
+			Tags:     []string{"host", "region"},
+			Fields: []influxdb.MeasurementSchemaField{
+				{Name: "cores", Type: influxdb.SchemaFieldTypeInteger},
+				{Name: "up", Type: influxdb.SchemaFieldTypeBoolean},
+				{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+			},
+		},
+		{
+			OrgID:    engine.org,
+			BucketID: engine.bucket,
+			Name:     "mem",
+			Tags:     []string{},
+			Fields: []influxdb.MeasurementSchemaField{
+				{Name: "free", Type: influxdb.SchemaFieldTypeInteger},
+				{Name: "name", Type: influxdb.SchemaFieldTypeString},
+			},
+		},
+	}
+	if !cmp.Equal(schemas, exp) {
+		t.Errorf("unexpected schemas -got/+exp\n%s", cmp.Diff(schemas, exp))
+	}
+}
+
+func TestEngine_WriteSeriesLimits(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxSeriesPerOrg = 3
+	c.MaxSeriesPerBucket = 2
+
+	engine := NewEngine(c)
+	defer engine.Close()
+	engine.MustOpen()
+
+	other := engine.bucket + 1
+	write := func(bucket influxdb.ID, lines string) error {
+		name := tsdb.EncodeName(engine.org, bucket)
+		points, err := models.ParsePointsWithPrecision([]byte(lines), name[:], time.Now(), "ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return engine.Engine.WritePoints(context.TODO(), points)
+	}
+	usage := func(filter influxdb.UsageFilter) float64 {
+		u, err := engine.GetUsage(context.TODO(), filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u[influxdb.UsageSeries].Value
+	}
+	limited := func(err error, exp ...string) {
+		t.Helper()
+		if code := influxdb.ErrorCode(err); code != influxdb.EUnprocessableEntity {
+			t.Fatalf("got error code %q, exp %q: %v", code, influxdb.EUnprocessableEntity, err)
+		}
+		pwe, ok := err.(*influxdb.Error).Err.(tsdb.PartialWriteError)
+		if !ok {
+			t.Fatal("expected wrapped partial write error. got:", err)
+		}
+		var reasons []string
+		for _, p := range pwe.DroppedPoints {
+			reasons = append(reasons, p.Reason)
+		}
+		if !cmp.Equal(reasons, exp) {
+			t.Errorf("unexpected reasons -got/+exp\n%s", cmp.Diff(reasons, exp))
+		}
+	}
+
+	// The third series of the bucket is rejected, also when it is repeated.
+	err := write(engine.bucket, "cpu,host=a v=1 1\ncpu,host=b v=1 1\ncpu,host=c v=1 1\ncpu,host=c v=2 2\n")
+	bucketLimit := fmt.Sprintf("series limit exceeded: bucket %s has reached the maximum of 2 series", engine.bucket)
+	limited(err, bucketLimit, bucketLimit)
+
+	// Existing series can still be written to.
+	if err := write(engine.bucket, "cpu,host=a v=2 2\ncpu,host=b v=2 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The organization has room for one more series in another bucket.
+	err = write(other, "cpu,host=a v=1 1\ncpu,host=b v=1 1\n")
+	limited(err, fmt.Sprintf("series limit exceeded: organization %s has reached the maximum of 3 series", engine.org))
+
+	if got, exp := usage(influxdb.UsageFilter{OrgID: &engine.org}), 3.0; got != exp {
+		t.Fatalf("got %v series in org, exp %v", got, exp)
+	}
+	if got, exp := usage(influxdb.UsageFilter{OrgID: &engine.org, BucketID: &engine.bucket}), 2.0; got != exp {
+		t.Fatalf("got %v series in bucket, exp %v", got, exp)
+	}
+
+	// Deleted series no longer count towards the limits.
+	if err := engine.DeleteBucket(context.TODO(), engine.org, engine.bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(other, "cpu,host=b v=1 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := usage(influxdb.UsageFilter{}), 2.0; got != exp {
+		t.Fatalf("got %v series, exp %v", got, exp)
+	}
+
+	// A new series whose points are dropped for a field type conflict with the
+	// deleted series does not count towards the limits.
+	err = write(engine.bucket, "cpu,host=a v=1i 1\n")
 	if _, ok := err.(tsdb.PartialWriteError); !ok {
 		t.Fatal("expected partial write error. got:", err)
 	}
+	if err := write(engine.bucket, "cpu,host=c v=1 1\n"); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func BenchmarkDeleteBucket(b *testing.B) {
@@ -532,10 +750,10 @@ type Engine struct {
 }
 
 // NewEngine create a new wrapper around a storage engine.
-func NewEngine(c storage.Config) *Engine {
+func NewEngine(c storage.Config, options ...storage.Option) *Engine {
 	path, _ := ioutil.TempDir("", "storage_engine_test")
 
-	engine := storage.NewEngine(path, c)
+	engine := storage.NewEngine(path, c, options...)
 
 	org, err := influxdb.IDFromString("3131313131313131")
 	if err != nil {
@@ -11,8 +11,9 @@ import (
 // storage.Engine instantiations. This allows multiple Engines to be
 // monitored within the same process.
 var (
-	rms *retentionMetrics
-	mmu sync.RWMutex
+	rms  *retentionMetrics
+	slms *seriesLimitMetrics
+	mmu  sync.RWMutex
 )
 
 // RetentionPrometheusCollectors returns all prometheus metrics for retention.
@@ -27,6 +28,18 @@ func RetentionPrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
+// SeriesLimitPrometheusCollectors returns all prometheus metrics for series limits.
+func SeriesLimitPrometheusCollectors() []prometheus.Collector {
+	mmu.RLock()
+	defer mmu.RUnlock()
+
+	var collectors []prometheus.Collector
+	if slms != nil {
+		collectors = append(collectors, slms.PrometheusCollectors()...)
+	}
+	return collectors
+}
+
 // namespace is the leading part of all published metrics for the Storage service.
 const namespace = "storage"
 
@@ -34,9 +47,10 @@ const retentionSubsystem = "retention" // sub-system associated with metrics for
 
 // retentionMetrics is a set of metrics concerned with tracking data about retention policies.
 type retentionMetrics struct {
-	labels        prometheus.Labels
-	Checks        *prometheus.CounterVec
-	CheckDuration *prometheus.HistogramVec
+	labels            prometheus.Labels
+	Checks            *prometheus.CounterVec
+	CheckDuration     *prometheus.HistogramVec
+	DownsampleBlocked *prometheus.CounterVec
 }
 
 func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
@@ -69,6 +83,13 @@ func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
 			// 25 buckets spaced exponentially between 10s and ~2h
 			Buckets: prometheus.ExponentialBuckets(10, 1.32, 25),
 		}, checkDurationNames),
+
+		DownsampleBlocked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: retentionSubsystem,
+			Name:      "downsample_blocked_total",
+			Help:      "Number of bucket retention checks skipped because the progress of a downsample tier could not be determined.",
+		}, names),
 	}
 }
 
@@ -86,5 +107,40 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		rm.Checks,
 		rm.CheckDuration,
+		rm.DownsampleBlocked,
+	}
+}
+
+const seriesLimitSubsystem = "series_limit" // sub-system associated with metrics for series limits.
+
+// seriesLimitMetrics is a set of metrics concerned with tracking data about series limits.
+type seriesLimitMetrics struct {
+	labels     prometheus.Labels
+	Rejections *prometheus.CounterVec
+}
+
+func newSeriesLimitMetrics(labels prometheus.Labels) *seriesLimitMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	names = append(names, "limit")
+	sort.Strings(names)
+
+	return &seriesLimitMetrics{
+		labels: labels,
+		Rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: seriesLimitSubsystem,
+			Name:      "rejections_total",
+			Help:      "Number of new series rejected because an organization or bucket reached its series limit.",
+		}, names),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *seriesLimitMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Rejections,
 	}
 }
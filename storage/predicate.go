@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxql"
+)
+
+// ParsePredicate parses an InfluxQL style predicate such as
+// `_measurement="cpu" AND host="a"` into a predicate matching series keys in
+// the engine. The special keys _measurement and _field refer to the measurement
+// and field of a series. As predicates always compare a tag key to a value,
+// the right hand side of a comparison may be either single or double quoted.
+//
+// An empty string returns a nil predicate, which matches every series.
+func ParsePredicate(s string) (influxdb.Predicate, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	expr, err := influxql.ParseExpr(s)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid predicate: %v", err),
+		}
+	}
+
+	root, err := exprToNode(expr)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid predicate: %v", err),
+		}
+	}
+
+	pred, err := tsm1.NewProtobufPredicate(&datatypes.Predicate{Root: root})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid predicate: %v", err),
+		}
+	}
+	return pred, nil
+}
+
+// exprToNode converts an influxql expression into a predicate node that
+// tsm1.NewProtobufPredicate understands.
+func exprToNode(expr influxql.Expr) (*datatypes.Node, error) {
+	switch expr := expr.(type) {
+	case *influxql.ParenExpr:
+		return exprToNode(expr.Expr)
+
+	case *influxql.BinaryExpr:
+		switch expr.Op {
+		case influxql.AND, influxql.OR:
+			left, err := exprToNode(expr.LHS)
+			if err != nil {
+				return nil, err
+			}
+			right, err := exprToNode(expr.RHS)
+			if err != nil {
+				return nil, err
+			}
+
+			logical := datatypes.LogicalAnd
+			if expr.Op == influxql.OR {
+				logical = datatypes.LogicalOr
+			}
+			return &datatypes.Node{
+				NodeType: datatypes.NodeTypeLogicalExpression,
+				Value:    &datatypes.Node_Logical_{Logical: logical},
+				Children: []*datatypes.Node{left, right},
+			}, nil
+
+		case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
+			return comparisonToNode(expr)
+
+		default:
+			return nil, fmt.Errorf("unsupported operator %s", expr.Op)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported expression %s", expr)
+	}
+}
+
+func comparisonToNode(expr *influxql.BinaryExpr) (*datatypes.Node, error) {
+	ref, ok := expr.LHS.(*influxql.VarRef)
+	if !ok {
+		return nil, fmt.Errorf("left side of %s must be a tag key", expr)
+	}
+
+	key := ref.Val
+	switch key {
+	case "_measurement":
+		key = models.MeasurementTagKey
+	case "_field":
+		key = models.FieldKeyTagKey
+	}
+
+	var (
+		comparison datatypes.Node_Comparison
+		value      *datatypes.Node
+	)
+	switch expr.Op {
+	case influxql.EQ, influxql.NEQ:
+		comparison = datatypes.ComparisonEqual
+		if expr.Op == influxql.NEQ {
+			comparison = datatypes.ComparisonNotEqual
+		}
+
+		var s string
+		switch rhs := expr.RHS.(type) {
+		case *influxql.StringLiteral:
+			s = rhs.Val
+		case *influxql.VarRef:
+			s = rhs.Val
+		default:
+			return nil, fmt.Errorf("right side of %s must be a string", expr)
+		}
+		value = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value:    &datatypes.Node_StringValue{StringValue: s},
+		}
+
+	case influxql.EQREGEX, influxql.NEQREGEX:
+		comparison = datatypes.ComparisonRegex
+		if expr.Op == influxql.NEQREGEX {
+			comparison = datatypes.ComparisonNotRegex
+		}
+
+		rhs, ok := expr.RHS.(*influxql.RegexLiteral)
+		if !ok {
+			return nil, fmt.Errorf("right side of %s must be a regular expression", expr)
+		}
+		value = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value:    &datatypes.Node_RegexValue{RegexValue: rhs.Val.String()},
+		}
+	}
+
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: comparison},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			value,
+		},
+	}, nil
+}
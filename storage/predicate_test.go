@@ -0,0 +1,99 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+)
+
+func TestParsePredicate(t *testing.T) {
+	key := func(m, f string, kvs ...string) []byte {
+		tags := map[string]string{models.MeasurementTagKey: m, models.FieldKeyTagKey: f}
+		for i := 0; i < len(kvs)-1; i += 2 {
+			tags[kvs[i]] = kvs[i+1]
+		}
+		return models.MakeKey([]byte("orgbucket"), models.NewTags(tags))
+	}
+
+	tests := []struct {
+		name      string
+		predicate string
+		matches   [][]byte
+		misses    [][]byte
+		wantErr   bool
+	}{
+		{
+			name:      "measurement and tag",
+			predicate: `_measurement="cpu" AND host="a"`,
+			matches:   [][]byte{key("cpu", "value", "host", "a")},
+			misses:    [][]byte{key("cpu", "value", "host", "b"), key("mem", "value", "host", "a")},
+		},
+		{
+			name:      "single quoted values",
+			predicate: `_measurement='cpu' AND _field='usage'`,
+			matches:   [][]byte{key("cpu", "usage", "host", "a")},
+			misses:    [][]byte{key("cpu", "value", "host", "a")},
+		},
+		{
+			name:      "or with parens",
+			predicate: `(host="a" OR host="b") AND region!="west"`,
+			matches:   [][]byte{key("cpu", "value", "host", "b", "region", "east")},
+			misses:    [][]byte{key("cpu", "value", "host", "a", "region", "west"), key("cpu", "value", "host", "c", "region", "east")},
+		},
+		{
+			name:      "regex",
+			predicate: `host=~/^server-\d+$/`,
+			matches:   [][]byte{key("cpu", "value", "host", "server-01")},
+			misses:    [][]byte{key("cpu", "value", "host", "client-01")},
+		},
+		{
+			name:      "numeric comparison",
+			predicate: `host > 1`,
+			wantErr:   true,
+		},
+		{
+			name:      "literal on left",
+			predicate: `'a' = host`,
+			wantErr:   true,
+		},
+		{
+			name:      "syntax error",
+			predicate: `host = `,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred, err := storage.ParsePredicate(tt.predicate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePredicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, k := range tt.matches {
+				if !pred.Matches(k) {
+					t.Errorf("expected %q to match", k)
+				}
+			}
+			for _, k := range tt.misses {
+				if pred.Matches(k) {
+					t.Errorf("expected %q not to match", k)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePredicate_Empty(t *testing.T) {
+	pred, err := storage.ParsePredicate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pred != nil {
+		t.Fatalf("expected nil predicate, got %v", pred)
+	}
+}
@@ -91,7 +91,7 @@ type floatMultiShardArrayCursor struct {
 	filter *floatArrayFilterCursor
 }
 
-func (c *floatMultiShardArrayCursor) reset(cur cursors.FloatArrayCursor, itrs cursors.CursorIterators, cond expression) {
+func (c *floatMultiShardArrayCursor) reset(cur cursors.FloatArrayCursor, itrs cursors.CursorIterators, agg *cursors.Aggregate, cond expression) {
 	if cond != nil {
 		if c.filter == nil {
 			c.filter = newFloatFilterArrayCursor(cond)
@@ -102,6 +102,7 @@ func (c *floatMultiShardArrayCursor) reset(cur cursors.FloatArrayCursor, itrs cu
 
 	c.FloatArrayCursor = cur
 	c.itrs = itrs
+	c.agg = agg
 	c.err = nil
 	c.count = 0
 }
@@ -143,7 +144,7 @@ func (c *floatMultiShardArrayCursor) nextArrayCursor() bool {
 	var cur cursors.Cursor
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		cur, _ = c.next(itr)
 	}
 
 	var ok bool
@@ -237,6 +238,331 @@ func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// floatWindowSelectorArrayCursor selects a single point of each window of
+// the underlying cursor. The point of a window is replaced by a later point of
+// the window whenever pick returns true.
+type floatWindowSelectorArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	pick  func(v, acc float64) bool
+	res   *cursors.FloatArray
+	tmp   *cursors.FloatArray // points that did not fit in res
+}
+
+func newFloatWindowSelectorArrayCursor(cur cursors.FloatArrayCursor, every int64, pick func(v, acc float64) bool) *floatWindowSelectorArrayCursor {
+	return &floatWindowSelectorArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		pick:             pick,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *floatWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowSelectorArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.FloatArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  float64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				if c.pick(v, acc) {
+					ts, acc = t, v
+				}
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.FloatArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.FloatArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// floatWindowCountArrayCursor counts the points of each window of the
+// underlying cursor. The timestamp of each count is that of the first point
+// of its window.
+type floatWindowCountArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.FloatArray // points that did not fit in res
+}
+
+func newFloatWindowCountArrayCursor(cur cursors.FloatArrayCursor, every int64) *floatWindowCountArrayCursor {
+	return &floatWindowCountArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		res:              cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *floatWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.FloatArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			if open && t < stop {
+				acc++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.FloatArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, 1
+		}
+		a = c.FloatArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// floatWindowSumArrayCursor sums the values of each window of the
+// underlying cursor. The timestamp of each sum is that of the first point of
+// its window.
+type floatWindowSumArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.FloatArray // points that did not fit in res
+}
+
+func newFloatWindowSumArrayCursor(cur cursors.FloatArrayCursor, every int64) *floatWindowSumArrayCursor {
+	return &floatWindowSumArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *floatWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowSumArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.FloatArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  float64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				acc += v
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.FloatArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.FloatArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// floatWindowMeanArrayCursor averages the values of each window of the
+// underlying cursor. The timestamp of each mean is that of the first point of
+// its window.
+type floatWindowMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.FloatArray // points that did not fit in res
+}
+
+func newFloatWindowMeanArrayCursor(cur cursors.FloatArrayCursor, every int64) *floatWindowMeanArrayCursor {
+	return &floatWindowMeanArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *floatWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.FloatArrayCursor.Next()
+	}
+
+	var (
+		open  bool
+		stop  int64
+		ts    int64
+		sum   float64
+		count int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := float64(a.Values[i])
+			if open && t < stop {
+				sum += v
+				count++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = sum / float64(count)
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.FloatArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, sum, count = true, windowStop(t, c.every), t, v, 1
+		}
+		a = c.FloatArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(count)
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type floatEmptyArrayCursor struct {
 	res cursors.FloatArray
 }
@@ -320,7 +646,7 @@ type integerMultiShardArrayCursor struct {
 	filter *integerArrayFilterCursor
 }
 
-func (c *integerMultiShardArrayCursor) reset(cur cursors.IntegerArrayCursor, itrs cursors.CursorIterators, cond expression) {
+func (c *integerMultiShardArrayCursor) reset(cur cursors.IntegerArrayCursor, itrs cursors.CursorIterators, agg *cursors.Aggregate, cond expression) {
 	if cond != nil {
 		if c.filter == nil {
 			c.filter = newIntegerFilterArrayCursor(cond)
@@ -331,6 +657,7 @@ func (c *integerMultiShardArrayCursor) reset(cur cursors.IntegerArrayCursor, itr
 
 	c.IntegerArrayCursor = cur
 	c.itrs = itrs
+	c.agg = agg
 	c.err = nil
 	c.count = 0
 }
@@ -372,7 +699,7 @@ func (c *integerMultiShardArrayCursor) nextArrayCursor() bool {
 	var cur cursors.Cursor
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		cur, _ = c.next(itr)
 	}
 
 	var ok bool
@@ -466,6 +793,331 @@ func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// integerWindowSelectorArrayCursor selects a single point of each window of
+// the underlying cursor. The point of a window is replaced by a later point of
+// the window whenever pick returns true.
+type integerWindowSelectorArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	pick  func(v, acc int64) bool
+	res   *cursors.IntegerArray
+	tmp   *cursors.IntegerArray // points that did not fit in res
+}
+
+func newIntegerWindowSelectorArrayCursor(cur cursors.IntegerArrayCursor, every int64, pick func(v, acc int64) bool) *integerWindowSelectorArrayCursor {
+	return &integerWindowSelectorArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		pick:               pick,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *integerWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSelectorArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				if c.pick(v, acc) {
+					ts, acc = t, v
+				}
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.IntegerArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// integerWindowCountArrayCursor counts the points of each window of the
+// underlying cursor. The timestamp of each count is that of the first point
+// of its window.
+type integerWindowCountArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.IntegerArray // points that did not fit in res
+}
+
+func newIntegerWindowCountArrayCursor(cur cursors.IntegerArrayCursor, every int64) *integerWindowCountArrayCursor {
+	return &integerWindowCountArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *integerWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			if open && t < stop {
+				acc++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.IntegerArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, 1
+		}
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// integerWindowSumArrayCursor sums the values of each window of the
+// underlying cursor. The timestamp of each sum is that of the first point of
+// its window.
+type integerWindowSumArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.IntegerArray // points that did not fit in res
+}
+
+func newIntegerWindowSumArrayCursor(cur cursors.IntegerArrayCursor, every int64) *integerWindowSumArrayCursor {
+	return &integerWindowSumArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *integerWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSumArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				acc += v
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.IntegerArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// integerWindowMeanArrayCursor averages the values of each window of the
+// underlying cursor. The timestamp of each mean is that of the first point of
+// its window.
+type integerWindowMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.IntegerArray // points that did not fit in res
+}
+
+func newIntegerWindowMeanArrayCursor(cur cursors.IntegerArrayCursor, every int64) *integerWindowMeanArrayCursor {
+	return &integerWindowMeanArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *integerWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	var (
+		open  bool
+		stop  int64
+		ts    int64
+		sum   float64
+		count int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := float64(a.Values[i])
+			if open && t < stop {
+				sum += v
+				count++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = sum / float64(count)
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.IntegerArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, sum, count = true, windowStop(t, c.every), t, v, 1
+		}
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(count)
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type integerEmptyArrayCursor struct {
 	res cursors.IntegerArray
 }
@@ -549,7 +1201,7 @@ type unsignedMultiShardArrayCursor struct {
 	filter *unsignedArrayFilterCursor
 }
 
-func (c *unsignedMultiShardArrayCursor) reset(cur cursors.UnsignedArrayCursor, itrs cursors.CursorIterators, cond expression) {
+func (c *unsignedMultiShardArrayCursor) reset(cur cursors.UnsignedArrayCursor, itrs cursors.CursorIterators, agg *cursors.Aggregate, cond expression) {
 	if cond != nil {
 		if c.filter == nil {
 			c.filter = newUnsignedFilterArrayCursor(cond)
@@ -560,6 +1212,7 @@ func (c *unsignedMultiShardArrayCursor) reset(cur cursors.UnsignedArrayCursor, i
 
 	c.UnsignedArrayCursor = cur
 	c.itrs = itrs
+	c.agg = agg
 	c.err = nil
 	c.count = 0
 }
@@ -601,7 +1254,7 @@ func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
 	var cur cursors.Cursor
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		cur, _ = c.next(itr)
 	}
 
 	var ok bool
@@ -624,75 +1277,400 @@ func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
 		c.UnsignedArrayCursor = UnsignedEmptyArrayCursor
 	}
 
-	return ok
+	return ok
+}
+
+type unsignedArraySumCursor struct {
+	cursors.UnsignedArrayCursor
+	ts  [1]int64
+	vs  [1]uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySumCursor {
+	return &unsignedArraySumCursor{
+		UnsignedArrayCursor: cur,
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return a
+	}
+
+	ts := a.Timestamps[0]
+	var acc uint64
+
+	for {
+		for _, v := range a.Values {
+			acc += v
+		}
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			c.ts[0] = ts
+			c.vs[0] = acc
+			c.res.Timestamps = c.ts[:]
+			c.res.Values = c.vs[:]
+			return c.res
+		}
+	}
+}
+
+type integerUnsignedCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+}
+
+func (c *integerUnsignedCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
+	a := c.UnsignedArrayCursor.Next()
+	if len(a.Timestamps) == 0 {
+		return &cursors.IntegerArray{}
+	}
+
+	ts := a.Timestamps[0]
+	var acc int64
+	for {
+		acc += int64(len(a.Timestamps))
+		a = c.UnsignedArrayCursor.Next()
+		if len(a.Timestamps) == 0 {
+			res := cursors.NewIntegerArrayLen(1)
+			res.Timestamps[0] = ts
+			res.Values[0] = acc
+			return res
+		}
+	}
+}
+
+// unsignedWindowSelectorArrayCursor selects a single point of each window of
+// the underlying cursor. The point of a window is replaced by a later point of
+// the window whenever pick returns true.
+type unsignedWindowSelectorArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every int64
+	pick  func(v, acc uint64) bool
+	res   *cursors.UnsignedArray
+	tmp   *cursors.UnsignedArray // points that did not fit in res
+}
+
+func newUnsignedWindowSelectorArrayCursor(cur cursors.UnsignedArrayCursor, every int64, pick func(v, acc uint64) bool) *unsignedWindowSelectorArrayCursor {
+	return &unsignedWindowSelectorArrayCursor{
+		UnsignedArrayCursor: cur,
+		every:               every,
+		pick:                pick,
+		res:                 cursors.NewUnsignedArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Next() *cursors.UnsignedArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  uint64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				if c.pick(v, acc) {
+					ts, acc = t, v
+				}
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.UnsignedArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// unsignedWindowCountArrayCursor counts the points of each window of the
+// underlying cursor. The timestamp of each count is that of the first point
+// of its window.
+type unsignedWindowCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.UnsignedArray // points that did not fit in res
+}
+
+func newUnsignedWindowCountArrayCursor(cur cursors.UnsignedArrayCursor, every int64) *unsignedWindowCountArrayCursor {
+	return &unsignedWindowCountArrayCursor{
+		UnsignedArrayCursor: cur,
+		every:               every,
+		res:                 cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *unsignedWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			if open && t < stop {
+				acc++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.UnsignedArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, 1
+		}
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
 }
 
-type unsignedArraySumCursor struct {
+// unsignedWindowSumArrayCursor sums the values of each window of the
+// underlying cursor. The timestamp of each sum is that of the first point of
+// its window.
+type unsignedWindowSumArrayCursor struct {
 	cursors.UnsignedArrayCursor
-	ts  [1]int64
-	vs  [1]uint64
-	res *cursors.UnsignedArray
+	every int64
+	res   *cursors.UnsignedArray
+	tmp   *cursors.UnsignedArray // points that did not fit in res
 }
 
-func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySumCursor {
-	return &unsignedArraySumCursor{
+func newUnsignedWindowSumArrayCursor(cur cursors.UnsignedArrayCursor, every int64) *unsignedWindowSumArrayCursor {
+	return &unsignedWindowSumArrayCursor{
 		UnsignedArrayCursor: cur,
-		res:                 &cursors.UnsignedArray{},
+		every:               every,
+		res:                 cursors.NewUnsignedArrayLen(MaxPointsPerBlock),
 	}
 }
 
-func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+func (c *unsignedWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
 
-func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
-	a := c.UnsignedArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return a
+func (c *unsignedWindowSumArrayCursor) Next() *cursors.UnsignedArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.UnsignedArrayCursor.Next()
 	}
 
-	ts := a.Timestamps[0]
-	var acc uint64
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  uint64
+	)
 
-	for {
-		for _, v := range a.Values {
-			acc += v
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				acc += v
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.UnsignedArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
 		}
 		a = c.UnsignedArrayCursor.Next()
-		if len(a.Timestamps) == 0 {
-			c.ts[0] = ts
-			c.vs[0] = acc
-			c.res.Timestamps = c.ts[:]
-			c.res.Values = c.vs[:]
-			return c.res
-		}
 	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
 }
 
-type integerUnsignedCountArrayCursor struct {
+// unsignedWindowMeanArrayCursor averages the values of each window of the
+// underlying cursor. The timestamp of each mean is that of the first point of
+// its window.
+type unsignedWindowMeanArrayCursor struct {
 	cursors.UnsignedArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.UnsignedArray // points that did not fit in res
 }
 
-func (c *integerUnsignedCountArrayCursor) Stats() cursors.CursorStats {
+func newUnsignedWindowMeanArrayCursor(cur cursors.UnsignedArrayCursor, every int64) *unsignedWindowMeanArrayCursor {
+	return &unsignedWindowMeanArrayCursor{
+		UnsignedArrayCursor: cur,
+		every:               every,
+		res:                 cursors.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *unsignedWindowMeanArrayCursor) Stats() cursors.CursorStats {
 	return c.UnsignedArrayCursor.Stats()
 }
 
-func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.UnsignedArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+func (c *unsignedWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
 
-	ts := a.Timestamps[0]
-	var acc int64
-	for {
-		acc += int64(len(a.Timestamps))
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
 		a = c.UnsignedArrayCursor.Next()
-		if len(a.Timestamps) == 0 {
-			res := cursors.NewIntegerArrayLen(1)
-			res.Timestamps[0] = ts
-			res.Values[0] = acc
-			return res
+	}
+
+	var (
+		open  bool
+		stop  int64
+		ts    int64
+		sum   float64
+		count int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := float64(a.Values[i])
+			if open && t < stop {
+				sum += v
+				count++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = sum / float64(count)
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.UnsignedArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, sum, count = true, windowStop(t, c.every), t, v, 1
 		}
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(count)
+		pos++
 	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
 }
 
 type unsignedEmptyArrayCursor struct {
@@ -778,7 +1756,7 @@ type stringMultiShardArrayCursor struct {
 	filter *stringArrayFilterCursor
 }
 
-func (c *stringMultiShardArrayCursor) reset(cur cursors.StringArrayCursor, itrs cursors.CursorIterators, cond expression) {
+func (c *stringMultiShardArrayCursor) reset(cur cursors.StringArrayCursor, itrs cursors.CursorIterators, agg *cursors.Aggregate, cond expression) {
 	if cond != nil {
 		if c.filter == nil {
 			c.filter = newStringFilterArrayCursor(cond)
@@ -789,6 +1767,7 @@ func (c *stringMultiShardArrayCursor) reset(cur cursors.StringArrayCursor, itrs
 
 	c.StringArrayCursor = cur
 	c.itrs = itrs
+	c.agg = agg
 	c.err = nil
 	c.count = 0
 }
@@ -830,7 +1809,7 @@ func (c *stringMultiShardArrayCursor) nextArrayCursor() bool {
 	var cur cursors.Cursor
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		cur, _ = c.next(itr)
 	}
 
 	var ok bool
@@ -884,6 +1863,169 @@ func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// stringWindowSelectorArrayCursor selects a single point of each window of
+// the underlying cursor. The point of a window is replaced by a later point of
+// the window whenever pick returns true.
+type stringWindowSelectorArrayCursor struct {
+	cursors.StringArrayCursor
+	every int64
+	pick  func(v, acc string) bool
+	res   *cursors.StringArray
+	tmp   *cursors.StringArray // points that did not fit in res
+}
+
+func newStringWindowSelectorArrayCursor(cur cursors.StringArrayCursor, every int64, pick func(v, acc string) bool) *stringWindowSelectorArrayCursor {
+	return &stringWindowSelectorArrayCursor{
+		StringArrayCursor: cur,
+		every:             every,
+		pick:              pick,
+		res:               cursors.NewStringArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *stringWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowSelectorArrayCursor) Next() *cursors.StringArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.StringArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  string
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				if c.pick(v, acc) {
+					ts, acc = t, v
+				}
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.StringArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.StringArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// stringWindowCountArrayCursor counts the points of each window of the
+// underlying cursor. The timestamp of each count is that of the first point
+// of its window.
+type stringWindowCountArrayCursor struct {
+	cursors.StringArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.StringArray // points that did not fit in res
+}
+
+func newStringWindowCountArrayCursor(cur cursors.StringArrayCursor, every int64) *stringWindowCountArrayCursor {
+	return &stringWindowCountArrayCursor{
+		StringArrayCursor: cur,
+		every:             every,
+		res:               cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *stringWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.StringArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			if open && t < stop {
+				acc++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.StringArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, 1
+		}
+		a = c.StringArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type stringEmptyArrayCursor struct {
 	res cursors.StringArray
 }
@@ -967,7 +2109,7 @@ type booleanMultiShardArrayCursor struct {
 	filter *booleanArrayFilterCursor
 }
 
-func (c *booleanMultiShardArrayCursor) reset(cur cursors.BooleanArrayCursor, itrs cursors.CursorIterators, cond expression) {
+func (c *booleanMultiShardArrayCursor) reset(cur cursors.BooleanArrayCursor, itrs cursors.CursorIterators, agg *cursors.Aggregate, cond expression) {
 	if cond != nil {
 		if c.filter == nil {
 			c.filter = newBooleanFilterArrayCursor(cond)
@@ -978,6 +2120,7 @@ func (c *booleanMultiShardArrayCursor) reset(cur cursors.BooleanArrayCursor, itr
 
 	c.BooleanArrayCursor = cur
 	c.itrs = itrs
+	c.agg = agg
 	c.err = nil
 	c.count = 0
 }
@@ -1019,7 +2162,7 @@ func (c *booleanMultiShardArrayCursor) nextArrayCursor() bool {
 	var cur cursors.Cursor
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		cur, _ = c.next(itr)
 	}
 
 	var ok bool
@@ -1073,6 +2216,169 @@ func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// booleanWindowSelectorArrayCursor selects a single point of each window of
+// the underlying cursor. The point of a window is replaced by a later point of
+// the window whenever pick returns true.
+type booleanWindowSelectorArrayCursor struct {
+	cursors.BooleanArrayCursor
+	every int64
+	pick  func(v, acc bool) bool
+	res   *cursors.BooleanArray
+	tmp   *cursors.BooleanArray // points that did not fit in res
+}
+
+func newBooleanWindowSelectorArrayCursor(cur cursors.BooleanArrayCursor, every int64, pick func(v, acc bool) bool) *booleanWindowSelectorArrayCursor {
+	return &booleanWindowSelectorArrayCursor{
+		BooleanArrayCursor: cur,
+		every:              every,
+		pick:               pick,
+		res:                cursors.NewBooleanArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *booleanWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowSelectorArrayCursor) Next() *cursors.BooleanArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.BooleanArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  bool
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if open && t < stop {
+				if c.pick(v, acc) {
+					ts, acc = t, v
+				}
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.BooleanArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, v
+		}
+		a = c.BooleanArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+// booleanWindowCountArrayCursor counts the points of each window of the
+// underlying cursor. The timestamp of each count is that of the first point
+// of its window.
+type booleanWindowCountArrayCursor struct {
+	cursors.BooleanArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.BooleanArray // points that did not fit in res
+}
+
+func newBooleanWindowCountArrayCursor(cur cursors.BooleanArrayCursor, every int64) *booleanWindowCountArrayCursor {
+	return &booleanWindowCountArrayCursor{
+		BooleanArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+}
+
+func (c *booleanWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	pos := 0
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	a := c.tmp
+	if a != nil {
+		c.tmp = nil
+	} else {
+		a = c.BooleanArrayCursor.Next()
+	}
+
+	var (
+		open bool
+		stop int64
+		ts   int64
+		acc  int64
+	)
+
+LOOP:
+	for len(a.Timestamps) > 0 {
+		for i, t := range a.Timestamps {
+			if open && t < stop {
+				acc++
+				continue
+			}
+
+			if open {
+				c.res.Timestamps[pos] = ts
+				c.res.Values[pos] = acc
+				pos++
+				if pos >= MaxPointsPerBlock {
+					open = false
+					c.tmp = &cursors.BooleanArray{
+						Timestamps: a.Timestamps[i:],
+						Values:     a.Values[i:],
+					}
+					break LOOP
+				}
+			}
+			open, stop, ts, acc = true, windowStop(t, c.every), t, 1
+		}
+		a = c.BooleanArrayCursor.Next()
+	}
+
+	if open {
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type booleanEmptyArrayCursor struct {
 	res cursors.BooleanArray
 }
@@ -3,6 +3,7 @@ package reads
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -26,6 +27,12 @@ func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, curs
 		return newSumArrayCursor(cursor)
 	case datatypes.AggregateTypeCount:
 		return newCountArrayCursor(cursor)
+	case datatypes.AggregateTypeMin,
+		datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeMean,
+		datatypes.AggregateTypeFirst,
+		datatypes.AggregateTypeLast:
+		return newWindowAggregateArrayCursor(ctx, agg, 0, cursor)
 	default:
 		// TODO(sgc): should be validated higher up
 		panic("invalid aggregate")
@@ -63,15 +70,180 @@ func newCountArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	}
 }
 
+// windowStop returns the exclusive upper bound of the window of every
+// nanoseconds that contains ts. Windows are aligned to the Unix epoch the same
+// way as the windows of Flux. A zero every is a single window that contains
+// every timestamp.
+func windowStop(ts, every int64) int64 {
+	if every == 0 {
+		return math.MaxInt64
+	}
+	return ts - ts%every + every
+}
+
+// newWindowAggregateArrayCursor returns a cursor that produces one point per
+// window of every nanoseconds of cursor. Selectors produce the selected point
+// of each window. Other aggregates produce a point with the timestamp of the
+// first point of each window. It returns nil if the aggregate does not apply
+// to the type of cursor.
+func newWindowAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, every int64, cursor cursors.Cursor) cursors.Cursor {
+	if cursor == nil {
+		return nil
+	}
+
+	switch agg.Type {
+	case datatypes.AggregateTypeFirst:
+		return newWindowSelectorArrayCursor(cursor, every, selectFirst)
+	case datatypes.AggregateTypeLast:
+		return newWindowSelectorArrayCursor(cursor, every, selectLast)
+	case datatypes.AggregateTypeMin:
+		return newWindowSelectorArrayCursor(cursor, every, selectMin)
+	case datatypes.AggregateTypeMax:
+		return newWindowSelectorArrayCursor(cursor, every, selectMax)
+	case datatypes.AggregateTypeCount:
+		return newWindowCountArrayCursor(cursor, every)
+	case datatypes.AggregateTypeSum:
+		return newWindowSumArrayCursor(cursor, every)
+	case datatypes.AggregateTypeMean:
+		return newWindowMeanArrayCursor(cursor, every)
+	default:
+		// TODO(sgc): should be validated higher up
+		panic("invalid aggregate")
+	}
+}
+
+type selector int
+
+const (
+	selectFirst selector = iota
+	selectLast
+	selectMin
+	selectMax
+)
+
+func newWindowSelectorArrayCursor(cur cursors.Cursor, every int64, sel selector) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		var pick func(v, acc float64) bool
+		switch sel {
+		case selectFirst:
+			pick = func(v, acc float64) bool { return false }
+		case selectLast:
+			pick = func(v, acc float64) bool { return true }
+		case selectMin:
+			pick = func(v, acc float64) bool { return v < acc }
+		case selectMax:
+			pick = func(v, acc float64) bool { return v > acc }
+		}
+		return newFloatWindowSelectorArrayCursor(cur, every, pick)
+	case cursors.IntegerArrayCursor:
+		var pick func(v, acc int64) bool
+		switch sel {
+		case selectFirst:
+			pick = func(v, acc int64) bool { return false }
+		case selectLast:
+			pick = func(v, acc int64) bool { return true }
+		case selectMin:
+			pick = func(v, acc int64) bool { return v < acc }
+		case selectMax:
+			pick = func(v, acc int64) bool { return v > acc }
+		}
+		return newIntegerWindowSelectorArrayCursor(cur, every, pick)
+	case cursors.UnsignedArrayCursor:
+		var pick func(v, acc uint64) bool
+		switch sel {
+		case selectFirst:
+			pick = func(v, acc uint64) bool { return false }
+		case selectLast:
+			pick = func(v, acc uint64) bool { return true }
+		case selectMin:
+			pick = func(v, acc uint64) bool { return v < acc }
+		case selectMax:
+			pick = func(v, acc uint64) bool { return v > acc }
+		}
+		return newUnsignedWindowSelectorArrayCursor(cur, every, pick)
+	case cursors.StringArrayCursor:
+		switch sel {
+		case selectFirst:
+			return newStringWindowSelectorArrayCursor(cur, every, func(v, acc string) bool { return false })
+		case selectLast:
+			return newStringWindowSelectorArrayCursor(cur, every, func(v, acc string) bool { return true })
+		}
+		return nil
+	case cursors.BooleanArrayCursor:
+		switch sel {
+		case selectFirst:
+			return newBooleanWindowSelectorArrayCursor(cur, every, func(v, acc bool) bool { return false })
+		case selectLast:
+			return newBooleanWindowSelectorArrayCursor(cur, every, func(v, acc bool) bool { return true })
+		}
+		return nil
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowCountArrayCursor(cur cursors.Cursor, every int64) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowCountArrayCursor(cur, every)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowCountArrayCursor(cur, every)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowCountArrayCursor(cur, every)
+	case cursors.StringArrayCursor:
+		return newStringWindowCountArrayCursor(cur, every)
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowCountArrayCursor(cur, every)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowSumArrayCursor(cur cursors.Cursor, every int64) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowSumArrayCursor(cur, every)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowSumArrayCursor(cur, every)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowSumArrayCursor(cur, every)
+	default:
+		return nil
+	}
+}
+
+func newWindowMeanArrayCursor(cur cursors.Cursor, every int64) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowMeanArrayCursor(cur, every)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowMeanArrayCursor(cur, every)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowMeanArrayCursor(cur, every)
+	default:
+		return nil
+	}
+}
+
 type cursorContext struct {
 	ctx   context.Context
 	req   *cursors.CursorRequest
 	itrs  cursors.CursorIterators
+	agg   *cursors.Aggregate // partial aggregate requested from itrs, if any
 	limit int64
 	count int64
 	err   error
 }
 
+// next returns the cursor of itr for the request of the context.
+func (c *cursorContext) next(itr cursors.CursorIterator) (cursors.Cursor, error) {
+	if c.agg != nil {
+		return itr.(cursors.AggregateCursorIterator).NextAggregate(c.ctx, c.req, *c.agg)
+	}
+	return itr.Next(c.ctx, c.req)
+}
+
 type multiShardArrayCursors struct {
 	ctx   context.Context
 	limit int64
@@ -117,6 +289,82 @@ func newMultiShardArrayCursors(ctx context.Context, start, end int64, asc bool,
 }
 
 func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
+	return m.newCursor(row, nil)
+}
+
+// createAggregateCursor returns a cursor of the aggregate of each window of
+// every nanoseconds of row, or of the whole range if every is zero. The cursor
+// iterators of row compute partial aggregates when they are able to, so that
+// the points of the series need not all be read. It returns nil if agg does
+// not apply to the type of the field.
+func (m *multiShardArrayCursors) createAggregateCursor(ctx context.Context, row SeriesRow, agg *datatypes.Aggregate, every int64) cursors.Cursor {
+	var req *cursors.Aggregate
+	partial, ok := partialAggregate(row, agg, every)
+	if ok {
+		req = &partial
+	}
+
+	cur := m.newCursor(row, req)
+	if cur == nil {
+		return nil
+	}
+
+	var acur cursors.Cursor
+	switch {
+	case ok && agg.Type == datatypes.AggregateTypeCount && every > 0:
+		// the partial aggregates of count are the counts of their points
+		acur = newWindowSumArrayCursor(cur, every)
+	case ok && agg.Type == datatypes.AggregateTypeCount:
+		acur = newSumArrayCursor(cur)
+	case every > 0:
+		acur = newWindowAggregateArrayCursor(ctx, agg, every, cur)
+	default:
+		acur = newAggregateArrayCursor(ctx, agg, cur)
+	}
+	if acur == nil {
+		// the aggregate does not apply to the type of the field
+		cur.Close()
+	}
+	return acur
+}
+
+// partialAggregate returns the partial aggregate to request from the cursor
+// iterators of row for agg. Partial aggregates are only requested if every
+// iterator computes them and the points are not filtered by a condition on
+// their values.
+func partialAggregate(row SeriesRow, agg *datatypes.Aggregate, every int64) (cursors.Aggregate, bool) {
+	if row.ValueCond != nil || len(row.Query) == 0 {
+		return cursors.Aggregate{}, false
+	}
+	for _, itr := range row.Query {
+		if _, ok := itr.(cursors.AggregateCursorIterator); !ok {
+			return cursors.Aggregate{}, false
+		}
+	}
+
+	partial := cursors.Aggregate{Every: every}
+	switch agg.Type {
+	case datatypes.AggregateTypeCount:
+		partial.Type = cursors.AggregateTypeCount
+	case datatypes.AggregateTypeSum:
+		partial.Type = cursors.AggregateTypeSum
+	case datatypes.AggregateTypeFirst:
+		partial.Type = cursors.AggregateTypeFirst
+	case datatypes.AggregateTypeLast:
+		partial.Type = cursors.AggregateTypeLast
+	case datatypes.AggregateTypeMin:
+		partial.Type = cursors.AggregateTypeMin
+	case datatypes.AggregateTypeMax:
+		partial.Type = cursors.AggregateTypeMax
+	default:
+		return cursors.Aggregate{}, false
+	}
+	return partial, true
+}
+
+// newCursor returns a cursor of row, requesting the partial aggregate agg
+// from the cursor iterators if it is not nil.
+func (m *multiShardArrayCursors) newCursor(row SeriesRow, agg *cursors.Aggregate) cursors.Cursor {
 	m.req.Name = row.Name
 	m.req.Tags = row.SeriesTags
 	m.req.Field = row.Field
@@ -126,11 +374,12 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 		cond = &astExpr{row.ValueCond}
 	}
 
+	cc := cursorContext{ctx: m.ctx, req: &m.req, agg: agg}
 	var shard cursors.CursorIterator
 	var cur cursors.Cursor
 	for cur == nil && len(row.Query) > 0 {
 		shard, row.Query = row.Query[0], row.Query[1:]
-		cur, _ = shard.Next(m.ctx, &m.req)
+		cur, _ = cc.next(shard)
 	}
 
 	if cur == nil {
@@ -139,25 +388,21 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 
 	switch c := cur.(type) {
 	case cursors.IntegerArrayCursor:
-		m.cursors.i.reset(c, row.Query, cond)
+		m.cursors.i.reset(c, row.Query, agg, cond)
 		return &m.cursors.i
 	case cursors.FloatArrayCursor:
-		m.cursors.f.reset(c, row.Query, cond)
+		m.cursors.f.reset(c, row.Query, agg, cond)
 		return &m.cursors.f
 	case cursors.UnsignedArrayCursor:
-		m.cursors.u.reset(c, row.Query, cond)
+		m.cursors.u.reset(c, row.Query, agg, cond)
 		return &m.cursors.u
 	case cursors.StringArrayCursor:
-		m.cursors.s.reset(c, row.Query, cond)
+		m.cursors.s.reset(c, row.Query, agg, cond)
 		return &m.cursors.s
 	case cursors.BooleanArrayCursor:
-		m.cursors.b.reset(c, row.Query, cond)
+		m.cursors.b.reset(c, row.Query, agg, cond)
 		return &m.cursors.b
 	default:
 		panic(fmt.Sprintf("unreachable: %T", cur))
 	}
 }
-
-func (m *multiShardArrayCursors) newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor {
-	return newAggregateArrayCursor(ctx, agg, cursor)
-}
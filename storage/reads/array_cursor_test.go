@@ -0,0 +1,210 @@
+package reads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+type mockFloatArrayCursor struct {
+	arrays []*cursors.FloatArray
+}
+
+func (c *mockFloatArrayCursor) Close()                     {}
+func (c *mockFloatArrayCursor) Err() error                 { return nil }
+func (c *mockFloatArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *mockFloatArrayCursor) Next() *cursors.FloatArray {
+	if len(c.arrays) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+type mockStringArrayCursor struct {
+	arrays []*cursors.StringArray
+}
+
+func (c *mockStringArrayCursor) Close()                     {}
+func (c *mockStringArrayCursor) Err() error                 { return nil }
+func (c *mockStringArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *mockStringArrayCursor) Next() *cursors.StringArray {
+	if len(c.arrays) == 0 {
+		return &cursors.StringArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+// newFloatArrays splits the points into arrays of at most n points.
+func newFloatArrays(n int, ts []int64, vs []float64) []*cursors.FloatArray {
+	var arrays []*cursors.FloatArray
+	for len(ts) > 0 {
+		m := n
+		if m > len(ts) {
+			m = len(ts)
+		}
+		arrays = append(arrays, &cursors.FloatArray{Timestamps: ts[:m], Values: vs[:m]})
+		ts, vs = ts[m:], vs[m:]
+	}
+	return arrays
+}
+
+type windowPoints struct {
+	Timestamps []int64
+	Values     interface{}
+}
+
+func readWindowPoints(cur cursors.Cursor) windowPoints {
+	var p windowPoints
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		var vs []float64
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.Timestamps = append(p.Timestamps, a.Timestamps...)
+			vs = append(vs, a.Values...)
+		}
+		p.Values = vs
+	case cursors.IntegerArrayCursor:
+		var vs []int64
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.Timestamps = append(p.Timestamps, a.Timestamps...)
+			vs = append(vs, a.Values...)
+		}
+		p.Values = vs
+	case cursors.StringArrayCursor:
+		var vs []string
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.Timestamps = append(p.Timestamps, a.Timestamps...)
+			vs = append(vs, a.Values...)
+		}
+		p.Values = vs
+	}
+	return p
+}
+
+func TestNewWindowAggregateArrayCursor(t *testing.T) {
+	// windows of 10: [0, 10) has 3 points spread over two arrays, [10, 20) is
+	// empty and [20, 30) has 2 points.
+	ts := []int64{1, 4, 9, 20, 25}
+	vs := []float64{3, 1, 2, 5, 4}
+
+	tests := []struct {
+		name  string
+		agg   datatypes.Aggregate_AggregateType
+		every int64
+		exp   windowPoints
+	}{
+		{
+			name:  "first",
+			agg:   datatypes.AggregateTypeFirst,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{1, 20}, Values: []float64{3, 5}},
+		},
+		{
+			name:  "last",
+			agg:   datatypes.AggregateTypeLast,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{9, 25}, Values: []float64{2, 4}},
+		},
+		{
+			name:  "min",
+			agg:   datatypes.AggregateTypeMin,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{4, 25}, Values: []float64{1, 4}},
+		},
+		{
+			name:  "max",
+			agg:   datatypes.AggregateTypeMax,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{1, 20}, Values: []float64{3, 5}},
+		},
+		{
+			name:  "count",
+			agg:   datatypes.AggregateTypeCount,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{1, 20}, Values: []int64{3, 2}},
+		},
+		{
+			name:  "sum",
+			agg:   datatypes.AggregateTypeSum,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{1, 20}, Values: []float64{6, 9}},
+		},
+		{
+			name:  "mean",
+			agg:   datatypes.AggregateTypeMean,
+			every: 10,
+			exp:   windowPoints{Timestamps: []int64{1, 20}, Values: []float64{2, 4.5}},
+		},
+		{
+			name:  "single window",
+			agg:   datatypes.AggregateTypeMean,
+			every: 0,
+			exp:   windowPoints{Timestamps: []int64{1}, Values: []float64{3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur := &mockFloatArrayCursor{arrays: newFloatArrays(2, ts, vs)}
+			got := readWindowPoints(newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: tt.agg}, tt.every, cur))
+			if !cmp.Equal(got, tt.exp) {
+				t.Errorf("unexpected points -want/+got:\n%s", cmp.Diff(tt.exp, got))
+			}
+		})
+	}
+}
+
+func TestNewWindowAggregateArrayCursor_MaxPointsPerBlock(t *testing.T) {
+	// every point is its own window, so the windows must be split across
+	// several arrays.
+	n := 2*MaxPointsPerBlock + 10
+	ts := make([]int64, n)
+	vs := make([]float64, n)
+	for i := range ts {
+		ts[i] = int64(i)
+		vs[i] = float64(i)
+	}
+
+	cur := newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: datatypes.AggregateTypeSum}, 1, &mockFloatArrayCursor{
+		arrays: newFloatArrays(700, ts, vs),
+	}).(cursors.FloatArrayCursor)
+
+	var lens []int
+	var got []float64
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		lens = append(lens, a.Len())
+		got = append(got, a.Values...)
+	}
+	if exp := []int{MaxPointsPerBlock, MaxPointsPerBlock, 10}; !cmp.Equal(lens, exp) {
+		t.Errorf("unexpected array lengths -want/+got:\n%s", cmp.Diff(exp, lens))
+	}
+	if !cmp.Equal(got, vs) {
+		t.Errorf("unexpected values -want/+got:\n%s", cmp.Diff(vs, got))
+	}
+}
+
+func TestNewWindowAggregateArrayCursor_String(t *testing.T) {
+	newCursor := func() *mockStringArrayCursor {
+		return &mockStringArrayCursor{arrays: []*cursors.StringArray{
+			{Timestamps: []int64{1, 2, 11}, Values: []string{"a", "b", "c"}},
+		}}
+	}
+
+	got := readWindowPoints(newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: datatypes.AggregateTypeLast}, 10, newCursor()))
+	if exp := (windowPoints{Timestamps: []int64{2, 11}, Values: []string{"b", "c"}}); !cmp.Equal(got, exp) {
+		t.Errorf("unexpected points -want/+got:\n%s", cmp.Diff(exp, got))
+	}
+
+	if cur := newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: datatypes.AggregateTypeMean}, 10, newCursor()); cur != nil {
+		t.Errorf("expected no cursor for the mean of strings, got %T", cur)
+	}
+}
@@ -302,11 +302,10 @@ func (c *groupNoneCursor) Next() bool {
 }
 
 func (c *groupNoneCursor) Cursor() cursors.Cursor {
-	cur := c.mb.createCursor(c.row)
 	if c.agg != nil {
-		cur = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
+		return c.mb.createAggregateCursor(c.ctx, c.row, c.agg, 0)
 	}
-	return cur
+	return c.mb.createCursor(c.row)
 }
 
 type groupByCursor struct {
@@ -339,11 +338,10 @@ func (c *groupByCursor) Next() bool {
 }
 
 func (c *groupByCursor) Cursor() cursors.Cursor {
-	cur := c.mb.createCursor(*c.rows[c.i-1])
 	if c.agg != nil {
-		cur = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
+		return c.mb.createAggregateCursor(c.ctx, *c.rows[c.i-1], c.agg, 0)
 	}
-	return cur
+	return c.mb.createCursor(*c.rows[c.i-1])
 }
 
 func (c *groupByCursor) Stats() cursors.CursorStats {
@@ -49,6 +49,15 @@ func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec
 	}, nil
 }
 
+func (r *storeReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &windowAggregateIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if spec.Predicate != nil {
@@ -357,6 +366,214 @@ READ:
 	return rs.Err()
 }
 
+type windowAggregateIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadWindowAggregateSpec
+	stats cursors.CursorStats
+	alloc *memory.Allocator
+}
+
+func (wai *windowAggregateIterator) Statistics() cursors.CursorStats { return wai.stats }
+
+func (wai *windowAggregateIterator) Do(f func(flux.Table) error) error {
+	src := wai.s.GetSource(
+		uint64(wai.spec.OrganizationID),
+		uint64(wai.spec.BucketID),
+	)
+
+	// Setup read request
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return err
+	}
+
+	var predicate *datatypes.Predicate
+	if wai.spec.Predicate != nil {
+		p, err := toStoragePredicate(wai.spec.Predicate)
+		if err != nil {
+			return err
+		}
+		predicate = p
+	}
+
+	var req datatypes.ReadWindowAggregateRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = int64(wai.spec.Bounds.Start)
+	req.Range.End = int64(wai.spec.Bounds.Stop)
+	req.WindowEvery = wai.spec.WindowEvery
+
+	req.Aggregate = make([]*datatypes.Aggregate, len(wai.spec.Aggregates))
+	for i, kind := range wai.spec.Aggregates {
+		agg, err := determineAggregateMethod(string(kind))
+		if err != nil {
+			return err
+		}
+		req.Aggregate[i] = &datatypes.Aggregate{Type: agg}
+	}
+
+	rs, err := wai.s.ReadWindowAggregate(wai.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+	return wai.handleRead(filterDuplicateTables(f), rs, req.Aggregate[0].Type)
+}
+
+func (wai *windowAggregateIterator) handleRead(f func(flux.Table) error, rs ResultSet, agg datatypes.Aggregate_AggregateType) error {
+	defer rs.Close()
+
+	// selectors keep the time of the selected point like the Flux selectors
+	// do, while the other aggregates drop the _time column.
+	var selector bool
+	switch agg {
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		selector = true
+	}
+
+READ:
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		err := wai.readCursor(f, cur, rs.Tags(), selector)
+		stats := cur.Stats()
+		wai.stats.ScannedValues += stats.ScannedValues
+		wai.stats.ScannedBytes += stats.ScannedBytes
+		cur.Close()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-wai.ctx.Done():
+			break READ
+		default:
+		}
+	}
+	return rs.Err()
+}
+
+// readCursor produces a table for every point of cur, since each point is the
+// aggregate of a different window.
+func (wai *windowAggregateIterator) readCursor(f func(flux.Table) error, cur cursors.Cursor, tags models.Tags, selector bool) error {
+	switch cur := cur.(type) {
+	case cursors.IntegerArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wai.produceTable(f, tags, ts, values.NewInt(a.Values[i]), selector); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.FloatArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wai.produceTable(f, tags, ts, values.NewFloat(a.Values[i]), selector); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wai.produceTable(f, tags, ts, values.NewUInt(a.Values[i]), selector); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.BooleanArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wai.produceTable(f, tags, ts, values.NewBool(a.Values[i]), selector); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.StringArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wai.produceTable(f, tags, ts, values.NewString(a.Values[i]), selector); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+	return nil
+}
+
+// produceTable produces the table of the window that contains ts. Its columns
+// match those that Flux produces for 'window |> agg'.
+func (wai *windowAggregateIterator) produceTable(f func(flux.Table) error, tags models.Tags, ts int64, v values.Value, selector bool) error {
+	every := execute.Duration(wai.spec.WindowEvery)
+	stop := execute.Time(ts).Truncate(every).Add(every)
+	bnds := wai.spec.Bounds.Intersect(execute.Bounds{Start: stop.Add(-every), Stop: stop})
+
+	key := defaultGroupKeyForSeries(tags, bnds)
+	builder := execute.NewColListTableBuilder(key, wai.alloc)
+	defer builder.ClearData()
+
+	if selector {
+		cols, _ := determineTableColsForSeries(tags, flux.ColumnType(v.Type()))
+		for _, c := range cols {
+			if _, err := builder.AddCol(c); err != nil {
+				return err
+			}
+		}
+		if err := builder.AppendTime(startColIdx, bnds.Start); err != nil {
+			return err
+		}
+		if err := builder.AppendTime(stopColIdx, bnds.Stop); err != nil {
+			return err
+		}
+		if err := builder.AppendTime(timeColIdx, execute.Time(ts)); err != nil {
+			return err
+		}
+		if err := builder.AppendValue(valueColIdx, v); err != nil {
+			return err
+		}
+		for j, tag := range tags {
+			if err := builder.AppendString(4+j, string(tag.Value)); err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := execute.AddTableKeyCols(key, builder); err != nil {
+			return err
+		}
+		valueIdx, err := builder.AddCol(flux.ColMeta{
+			Label: execute.DefaultValueColLabel,
+			Type:  flux.ColumnType(v.Type()),
+		})
+		if err != nil {
+			return err
+		}
+		if err := execute.AppendKeyValues(key, builder); err != nil {
+			return err
+		}
+		if err := builder.AppendValue(valueIdx, v); err != nil {
+			return err
+		}
+	}
+
+	// Construct the table and add to the reference count
+	// so we can free the table later.
+	tbl, err := builder.Table()
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
+
 func determineAggregateMethod(agg string) (datatypes.Aggregate_AggregateType, error) {
 	if agg == "" {
 		return datatypes.AggregateTypeNone, nil
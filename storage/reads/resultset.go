@@ -2,6 +2,8 @@ package reads
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 
 	"github.com/influxdata/influxdb/models"
@@ -11,15 +13,16 @@ import (
 
 type multiShardCursors interface {
 	createCursor(row SeriesRow) cursors.Cursor
-	newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor
+	createAggregateCursor(ctx context.Context, row SeriesRow, agg *datatypes.Aggregate, every int64) cursors.Cursor
 }
 
 type resultSet struct {
-	ctx context.Context
-	agg *datatypes.Aggregate
-	cur SeriesCursor
-	row SeriesRow
-	mb  multiShardCursors
+	ctx   context.Context
+	agg   *datatypes.Aggregate
+	every int64
+	cur   SeriesCursor
+	row   SeriesRow
+	mb    multiShardCursors
 }
 
 func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest, cur SeriesCursor) ResultSet {
@@ -30,6 +33,36 @@ func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest,
 	}
 }
 
+// NewWindowAggregateResultSet returns a ResultSet whose cursors produce the
+// aggregate of each window of req.WindowEvery nanoseconds of a series.
+func NewWindowAggregateResultSet(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, cur SeriesCursor) (ResultSet, error) {
+	if req.WindowEvery <= 0 {
+		return nil, errors.New("window every must be greater than zero")
+	}
+	if len(req.Aggregate) != 1 {
+		return nil, errors.New("window aggregate requires exactly one aggregate")
+	}
+	switch req.Aggregate[0].Type {
+	case datatypes.AggregateTypeCount,
+		datatypes.AggregateTypeSum,
+		datatypes.AggregateTypeMin,
+		datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeMean,
+		datatypes.AggregateTypeFirst,
+		datatypes.AggregateTypeLast:
+	default:
+		return nil, fmt.Errorf("unsupported window aggregate %s", req.Aggregate[0].Type)
+	}
+
+	return &resultSet{
+		ctx:   ctx,
+		agg:   req.Aggregate[0],
+		every: req.WindowEvery,
+		cur:   cur,
+		mb:    newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
+	}, nil
+}
+
 func (r *resultSet) Err() error { return nil }
 
 // Close closes the result set. Close is idempotent.
@@ -58,11 +91,10 @@ func (r *resultSet) Next() bool {
 }
 
 func (r *resultSet) Cursor() cursors.Cursor {
-	cur := r.mb.createCursor(r.row)
 	if r.agg != nil {
-		cur = r.mb.newAggregateCursor(r.ctx, r.agg, cur)
+		return r.mb.createAggregateCursor(r.ctx, r.row, r.agg, r.every)
 	}
-	return cur
+	return r.mb.createCursor(r.row)
 }
 
 func (r *resultSet) Tags() models.Tags {
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
@@ -34,6 +35,12 @@ type BucketFinder interface {
 	FindBuckets(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error)
 }
 
+// A TaskFinder is responsible for providing access to the tasks that downsample
+// the data of buckets.
+type TaskFinder interface {
+	FindTaskByID(context.Context, influxdb.ID) (*influxdb.Task, error)
+}
+
 // ErrServiceClosed is returned when the service is unavailable.
 var ErrServiceClosed = errors.New("service is currently closed")
 
@@ -49,6 +56,11 @@ type retentionEnforcer struct {
 	// organisations.
 	BucketService BucketFinder
 
+	// TaskService provides access to the tasks of the downsample tiers of
+	// buckets. Without it, data of buckets with downsample tiers is never
+	// deleted.
+	TaskService TaskFinder
+
 	logger *zap.Logger
 
 	tracker *retentionTracker
@@ -138,8 +150,20 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 			"retention_period", b.RetentionPeriod,
 			"retention_policy", b.RetentionPolicyName)
 
-		max := now.Add(-b.RetentionPeriod).UnixNano()
-		err := s.Engine.DeleteBucketRange(ctx, b.OrgID, b.ID, math.MinInt64, max)
+		max, err := s.retentionHorizon(ctx, b, now)
+		if err != nil {
+			logger.Warn("Retention blocked by downsample tiers",
+				zap.String("bucket id", b.ID.String()),
+				zap.String("org id", b.OrgID.String()),
+				zap.Error(err))
+			tracing.LogError(span, err)
+			s.tracker.IncChecks(false)
+			s.tracker.IncDownsampleBlocked()
+			span.Finish()
+			continue
+		}
+
+		err = s.Engine.DeleteBucketRange(ctx, b.OrgID, b.ID, math.MinInt64, max.UnixNano())
 		if err != nil {
 			logger.Info("unable to delete bucket range",
 				zap.String("bucket id", b.ID.String()),
@@ -153,6 +177,41 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 	}
 }
 
+// retentionHorizon returns the time before which the data of b can be deleted.
+// Data outside of the retention period is only deleted once every downsample
+// tier has rolled it up, i.e. once the latest completed run of the tier task
+// is past the age threshold of the tier.
+func (s *retentionEnforcer) retentionHorizon(ctx context.Context, b *influxdb.Bucket, now time.Time) (time.Time, error) {
+	max := now.Add(-b.RetentionPeriod)
+	if len(b.DownsampleTiers) == 0 {
+		return max, nil
+	}
+	if s.TaskService == nil {
+		return time.Time{}, errors.New("no task service to find the downsample tasks of the bucket")
+	}
+
+	for _, tier := range b.DownsampleTiers {
+		t, err := s.TaskService.FindTaskByID(ctx, tier.TaskID)
+		if err != nil {
+			return time.Time{}, &influxdb.Error{
+				Msg: fmt.Sprintf("unable to find task %s of the downsample tier into bucket %s", tier.TaskID, tier.TargetBucketID),
+				Err: err,
+			}
+		}
+		latest, err := time.Parse(time.RFC3339, t.LatestCompleted)
+		if err != nil {
+			return time.Time{}, &influxdb.Error{
+				Msg: fmt.Sprintf("task %s of the downsample tier into bucket %s has no completed run", tier.TaskID, tier.TargetBucketID),
+				Err: err,
+			}
+		}
+		if h := latest.Add(-tier.After); h.Before(max) {
+			max = h
+		}
+	}
+	return max, nil
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation(ctx context.Context) ([]*influxdb.Bucket, error) {
 	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
@@ -197,6 +256,12 @@ func (t *retentionTracker) IncChecks(success bool) {
 	t.metrics.Checks.With(labels).Inc()
 }
 
+// IncDownsampleBlocked signals that the data of some bucket was not deleted
+// because the progress of one of its downsample tiers is unknown.
+func (t *retentionTracker) IncDownsampleBlocked() {
+	t.metrics.DownsampleBlocked.With(t.Labels()).Inc()
+}
+
 // CheckDuration records the overall duration of a full retention check.
 func (t *retentionTracker) CheckDuration(dur time.Duration, success bool) {
 	labels := t.Labels()
@@ -83,6 +83,79 @@ func TestRetentionService(t *testing.T) {
 	})
 }
 
+func TestRetentionService_DownsampleTiers(t *testing.T) {
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+	tasks := map[influxdb.ID]*influxdb.Task{
+		1: {ID: 1, LatestCompleted: now.Add(-time.Hour).Format(time.RFC3339)},
+		2: {ID: 2, LatestCompleted: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+	}
+
+	tests := []struct {
+		name   string
+		tiers  []influxdb.DownsampleTier
+		finder bool
+		want   time.Time // zero if the bucket must not be deleted from
+	}{
+		{
+			name:   "downsampled past retention",
+			tiers:  []influxdb.DownsampleTier{{TaskID: 2, After: 5 * time.Minute}},
+			finder: true,
+			want:   now.Add(-3 * time.Hour),
+		},
+		{
+			name:   "downsampled within retention",
+			tiers:  []influxdb.DownsampleTier{{TaskID: 1, After: 4 * time.Hour}},
+			finder: true,
+			want:   now.Add(-5 * time.Hour),
+		},
+		{
+			name: "slowest tier",
+			tiers: []influxdb.DownsampleTier{
+				{TaskID: 2, After: 5 * time.Hour},
+				{TaskID: 1, After: 3 * time.Hour},
+			},
+			finder: true,
+			want:   now.Add(-5*time.Hour - 10*time.Minute),
+		},
+		{
+			name:   "task not found",
+			tiers:  []influxdb.DownsampleTier{{TaskID: 3}},
+			finder: true,
+		},
+		{
+			name:  "no task finder",
+			tiers: []influxdb.DownsampleTier{{TaskID: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewTestEngine()
+			service := newRetentionEnforcer(engine, &TestSnapshotter{}, NewTestBucketFinder())
+			if tt.finder {
+				service.TaskService = &TestTaskFinder{Tasks: tasks}
+			}
+
+			var got time.Time
+			engine.DeleteBucketRangeFn = func(ctx context.Context, orgID, bucketID influxdb.ID, from, to int64) error {
+				got = time.Unix(0, to).UTC()
+				return nil
+			}
+
+			service.expireData(context.Background(), []*influxdb.Bucket{{
+				OrgID:           1,
+				ID:              2,
+				RetentionPeriod: 3 * time.Hour,
+				DownsampleTiers: tt.tiers,
+			}}, now)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("got delete up to %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMetrics_Retention(t *testing.T) {
 	// metrics to be shared by multiple file stores.
 	metrics := newRetentionMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
@@ -101,6 +174,7 @@ func TestMetrics_Retention(t *testing.T) {
 		tracker.IncChecks(false)
 		tracker.CheckDuration(time.Second, true)
 		tracker.CheckDuration(time.Second, false)
+		tracker.IncDownsampleBlocked()
 	}
 
 	// Test that all the correct metrics are present.
@@ -116,6 +190,12 @@ func TestMetrics_Retention(t *testing.T) {
 	}
 
 	for i, labels := range labelVariants {
+		name := base + "downsample_blocked_total"
+		metric := promtest.MustFindMetric(t, mfs, name, labels)
+		if got, exp := metric.GetCounter().GetValue(), float64(1); got != exp {
+			t.Errorf("[%s %d %v] got %v, expected %v", name, i, labels, got, exp)
+		}
+
 		for _, status := range []string{"ok", "error"} {
 			labels["status"] = status
 
@@ -179,3 +259,15 @@ func NewTestBucketFinder() *TestBucketFinder {
 func (f *TestBucketFinder) FindBuckets(ctx context.Context, filter influxdb.BucketFilter, opts ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
 	return f.FindBucketsFn(ctx, filter, opts...)
 }
+
+type TestTaskFinder struct {
+	Tasks map[influxdb.ID]*influxdb.Task
+}
+
+func (f *TestTaskFinder) FindTaskByID(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	t, ok := f.Tasks[id]
+	if !ok {
+		return nil, influxdb.ErrTaskNotFound
+	}
+	return t, nil
+}
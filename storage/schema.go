@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// A SchemaFinder is responsible for providing access to the schema type of
+// buckets and to the measurement schemas of buckets with an explicit schema.
+type SchemaFinder interface {
+	FindBucketByID(ctx context.Context, id platform.ID) (*platform.Bucket, error)
+	FindMeasurementSchemas(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error)
+}
+
+// bucketSchema is the schema of a single bucket. A nil bucketSchema accepts
+// every point.
+type bucketSchema struct {
+	measurements map[string]*platform.MeasurementSchema
+}
+
+// schemaCache resolves the schemas of the buckets of a single batch of points,
+// so the finder is queried at most once per bucket and batch.
+type schemaCache struct {
+	finder  SchemaFinder
+	schemas map[string]*bucketSchema
+}
+
+func newSchemaCache(finder SchemaFinder) *schemaCache {
+	return &schemaCache{
+		finder:  finder,
+		schemas: make(map[string]*bucketSchema),
+	}
+}
+
+// lookup returns the schema of the bucket of the tsdb encoded name.
+func (c *schemaCache) lookup(ctx context.Context, name []byte) (*bucketSchema, error) {
+	if s, ok := c.schemas[string(name)]; ok {
+		return s, nil
+	}
+
+	_, bucketID := tsdb.DecodeNameSlice(name)
+	b, err := c.finder.FindBucketByID(ctx, bucketID)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		err, b = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s *bucketSchema
+	if b != nil && b.SchemaType == platform.SchemaTypeExplicit {
+		schemas, err := c.finder.FindMeasurementSchemas(ctx, platform.MeasurementSchemaFilter{BucketID: bucketID})
+		if err != nil {
+			return nil, err
+		}
+		s = &bucketSchema{measurements: make(map[string]*platform.MeasurementSchema, len(schemas))}
+		for _, ms := range schemas {
+			s.measurements[ms.Name] = ms
+		}
+	}
+	c.schemas[string(name)] = s
+	return s, nil
+}
+
+// validate returns the reason a point with the tags and field type does not
+// match the schema, or the empty string if it does. The tags must start with
+// the measurement tag and end with the field key tag.
+func (s *bucketSchema) validate(tags models.Tags, typ models.FieldType) string {
+	if s == nil {
+		return ""
+	}
+
+	measurement := string(tags[0].Value)
+	ms, ok := s.measurements[measurement]
+	if !ok {
+		return fmt.Sprintf("schema violation: measurement %q is not declared", measurement)
+	}
+
+	for _, tag := range tags[1 : len(tags)-1] {
+		if !ms.HasTag(string(tag.Key)) {
+			return fmt.Sprintf("schema violation: tag %q is not declared on measurement %q", tag.Key, measurement)
+		}
+	}
+
+	fieldName := string(tags[len(tags)-1].Value)
+	field, ok := ms.Field(fieldName)
+	if !ok {
+		return fmt.Sprintf("schema violation: field %q is not declared on measurement %q", fieldName, measurement)
+	}
+	if want := fieldTypeFromSchema(field.Type); want != typ {
+		return fmt.Sprintf("schema violation: field %q on measurement %q is of type %s, got %s", fieldName, measurement, field.Type, schemaFieldType(typ))
+	}
+	return ""
+}
+
+// fieldTypeFromSchema returns the storage type of a schema field type.
+func fieldTypeFromSchema(t platform.SchemaFieldType) models.FieldType {
+	switch t {
+	case platform.SchemaFieldTypeFloat:
+		return models.Float
+	case platform.SchemaFieldTypeInteger:
+		return models.Integer
+	case platform.SchemaFieldTypeUnsigned:
+		return models.Unsigned
+	case platform.SchemaFieldTypeString:
+		return models.String
+	case platform.SchemaFieldTypeBoolean:
+		return models.Boolean
+	}
+	return models.Empty
+}
+
+// schemaFieldType returns the schema field type of a storage type.
+func schemaFieldType(t models.FieldType) platform.SchemaFieldType {
+	switch t {
+	case models.Float:
+		return platform.SchemaFieldTypeFloat
+	case models.Integer:
+		return platform.SchemaFieldTypeInteger
+	case models.Unsigned:
+		return platform.SchemaFieldTypeUnsigned
+	case models.String:
+		return platform.SchemaFieldTypeString
+	case models.Boolean:
+		return platform.SchemaFieldTypeBoolean
+	}
+	return ""
+}
+
+// ReadMeasurementSchemas returns a schema for every measurement of the bucket,
+// with the tag keys and fields of the series that have been written to it.
+// The measurement stats of the TSM files are consulted first, so that buckets
+// without any data are answered without walking the index.
+func (e *Engine) ReadMeasurementSchemas(ctx context.Context, orgID, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	name := tsdb.EncodeName(orgID, bucketID)
+
+	stats, err := e.engine.MeasurementStats()
+	if err != nil {
+		return nil, err
+	}
+	if stats[string(name[:])] == 0 {
+		// Data that has not been snapshotted yet is only known to the index.
+		if exists, err := e.index.MeasurementExists(name[:]); err != nil {
+			return nil, err
+		} else if !exists {
+			return []*platform.MeasurementSchema{}, nil
+		}
+	}
+
+	sitr, err := e.index.MeasurementSeriesIDIterator(name[:])
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*platform.MeasurementSchema)
+	if sitr != nil {
+		defer sitr.Close()
+
+		var tags models.Tags
+		for {
+			elem, err := sitr.Next()
+			if err != nil {
+				return nil, err
+			} else if elem.SeriesID.IsZero() {
+				break
+			}
+
+			key := e.sfile.SeriesKey(elem.SeriesID)
+			if len(key) == 0 {
+				continue
+			}
+			_, tags = tsdb.ParseSeriesKeyInto(key, tags)
+			if len(tags) < 2 {
+				continue
+			}
+
+			measurement := string(tags[0].Value)
+			ms, ok := byName[measurement]
+			if !ok {
+				ms = &platform.MeasurementSchema{
+					OrgID:    orgID,
+					BucketID: bucketID,
+					Name:     measurement,
+					Tags:     []string{},
+				}
+				byName[measurement] = ms
+			}
+
+			for _, tag := range tags[1 : len(tags)-1] {
+				if !ms.HasTag(string(tag.Key)) {
+					ms.Tags = append(ms.Tags, string(tag.Key))
+				}
+			}
+
+			field := string(tags[len(tags)-1].Value)
+			if _, ok := ms.Field(field); ok {
+				continue
+			}
+			typ := e.sfile.SeriesIDTypedBySeriesKey(key)
+			if !typ.HasType() {
+				continue
+			}
+			ms.Fields = append(ms.Fields, platform.MeasurementSchemaField{
+				Name: field,
+				Type: schemaFieldType(typ.Type()),
+			})
+		}
+	}
+
+	schemas := make([]*platform.MeasurementSchema, 0, len(byName))
+	for _, ms := range byName {
+		sort.Strings(ms.Tags)
+		sort.Slice(ms.Fields, func(i, j int) bool { return ms.Fields[i].Name < ms.Fields[j].Name })
+		schemas = append(schemas, ms)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Name < schemas[j].Name })
+	return schemas, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
@@ -23,6 +24,7 @@ type queryServiceExecutor struct {
 	qs     query.QueryService
 	as     influxdb.AuthorizationService
 	ts     influxdb.TaskService
+	rr     *authorizer.RoleResolver
 	logger *zap.Logger
 	wg     sync.WaitGroup
 }
@@ -32,8 +34,22 @@ var _ backend.Executor = (*queryServiceExecutor)(nil)
 // NewQueryServiceExecutor returns a new executor based on the given QueryService.
 // In general, you should prefer NewAsyncQueryServiceExecutor, as that code is smaller and simpler,
 // because asynchronous queries are more in line with the Executor interface.
-func NewQueryServiceExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService) *queryServiceExecutor {
-	return &queryServiceExecutor{logger: logger, qs: qs, as: as, ts: ts}
+// The roles of the authorizations of the tasks are resolved with rr, unless
+// it is nil.
+func NewQueryServiceExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, rr *authorizer.RoleResolver) *queryServiceExecutor {
+	return &queryServiceExecutor{logger: logger, qs: qs, as: as, ts: ts, rr: rr}
+}
+
+// taskContext returns ctx with the authorization of a task set on it along
+// with its roles.
+func taskContext(ctx context.Context, rr *authorizer.RoleResolver, a *influxdb.Authorization) (context.Context, error) {
+	if rr != nil && a != nil {
+		var err error
+		if ctx, err = rr.Resolve(ctx, a); err != nil {
+			return ctx, err
+		}
+	}
+	return icontext.SetAuthorizer(ctx, a), nil
 }
 
 // AddTaskService is a temporary solution to a chicken and egg problem. It takes a executor and sets the task service.
@@ -57,7 +73,11 @@ func (e *queryServiceExecutor) Execute(ctx context.Context, run backend.QueuedRu
 	}
 
 	// TODO(goller): remove need for context authorization.
-	return newSyncRunPromise(icontext.SetAuthorizer(ctx, t.Authorization), t.Authorization, run, e, t), nil
+	ctx, err = taskContext(ctx, e.rr, t.Authorization)
+	if err != nil {
+		return nil, err
+	}
+	return newSyncRunPromise(ctx, t.Authorization, run, e, t), nil
 }
 
 func (e *queryServiceExecutor) Wait() {
@@ -211,6 +231,7 @@ type asyncQueryServiceExecutor struct {
 	qs     query.AsyncQueryService
 	as     influxdb.AuthorizationService
 	ts     influxdb.TaskService
+	rr     *authorizer.RoleResolver
 	logger *zap.Logger
 	wg     sync.WaitGroup
 }
@@ -218,8 +239,10 @@ type asyncQueryServiceExecutor struct {
 var _ backend.Executor = (*asyncQueryServiceExecutor)(nil)
 
 // NewAsyncQueryServiceExecutor returns a new executor based on the given AsyncQueryService.
-func NewAsyncQueryServiceExecutor(logger *zap.Logger, qs query.AsyncQueryService, as influxdb.AuthorizationService, ts influxdb.TaskService) backend.Executor {
-	return &asyncQueryServiceExecutor{logger: logger, qs: qs, as: as, ts: ts}
+// The roles of the authorizations of the tasks are resolved with rr, unless
+// it is nil.
+func NewAsyncQueryServiceExecutor(logger *zap.Logger, qs query.AsyncQueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, rr *authorizer.RoleResolver) backend.Executor {
+	return &asyncQueryServiceExecutor{logger: logger, qs: qs, as: as, ts: ts, rr: rr}
 }
 
 func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.QueuedRun) (backend.RunPromise, error) {
@@ -242,7 +265,11 @@ func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.Que
 		},
 	}
 	// Only set the authorizer on the context where we need it here.
-	q, err := e.qs.Query(icontext.SetAuthorizer(ctx, t.Authorization), req)
+	qctx, err := taskContext(ctx, e.rr, t.Authorization)
+	if err != nil {
+		return nil, err
+	}
+	q, err := e.qs.Query(qctx, req)
 	if err != nil {
 		return nil, err
 	}
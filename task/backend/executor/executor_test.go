@@ -16,6 +16,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
@@ -277,7 +278,7 @@ func createAsyncSystem() *system {
 		name: "AsyncExecutor",
 		svc:  svc,
 		ts:   i,
-		ex:   NewAsyncQueryServiceExecutor(zap.NewNop(), svc, i, i),
+		ex:   NewAsyncQueryServiceExecutor(zap.NewNop(), svc, i, i, nil),
 		i:    i,
 	}
 }
@@ -300,6 +301,7 @@ func createSyncSystem() *system {
 			},
 			i,
 			i,
+			nil,
 		),
 		i: i,
 	}
@@ -708,3 +710,81 @@ func createCreds(t *testing.T, i *kv.Service) testCreds {
 
 	return testCreds{OrgID: org.ID, Auth: auth}
 }
+
+func TestAsyncExecutor_Roles(t *testing.T) {
+	svc := newFakeQueryService()
+	i := kv.NewService(inmem.NewKVStore())
+	if err := i.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rr := &authorizer.RoleResolver{
+		RoleService:                i,
+		UserResourceMappingService: i,
+		LabelService:               i,
+	}
+	ex := NewAsyncQueryServiceExecutor(zap.NewNop(), svc, i, i, rr)
+	tc := createCreds(t, i)
+
+	// The owner of the task is granted writing the buckets of the org by a
+	// role only.
+	owner := &platform.User{Name: t.Name() + "-owner"}
+	if err := i.CreateUser(context.Background(), owner); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.CreateUserResourceMapping(context.Background(), &platform.UserResourceMapping{
+		UserID:       owner.ID,
+		UserType:     platform.Member,
+		ResourceType: platform.OrgsResourceType,
+		ResourceID:   tc.OrgID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	role := &platform.Role{
+		OrgID: tc.OrgID,
+		Name:  "bucket-writer",
+		Permissions: []platform.RolePermission{
+			{Action: platform.WriteAction, Resource: platform.RoleResource{Type: platform.BucketsResourceType}},
+		},
+	}
+	if err := i.CreateRole(context.Background(), role); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.AddRoleMember(context.Background(), role.ID, owner.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tc.Auth)
+	task, err := i.CreateTask(ctx, platform.TaskCreate{OrganizationID: tc.OrgID, OwnerID: owner.ID, Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp, err := ex.Execute(context.Background(), backend.QueuedRun{TaskID: task.ID, RunID: platform.ID(1), Now: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.WaitForQueryLive(t, script)
+
+	svc.mu.Lock()
+	qctx := svc.mostRecentCtx
+	svc.mu.Unlock()
+	a, err := icontext.GetAuthorizer(qctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := platform.NewPermission(platform.WriteAction, platform.BucketsResourceType, tc.OrgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Allowed(*p) {
+		t.Fatal("expected the owner to be granted writing buckets by its role only")
+	}
+	if !authorizer.Allowed(qctx, a, *p) {
+		t.Error("expected the role of the owner to be granted to the query of the task")
+	}
+
+	svc.SucceedQuery(script)
+	if _, err := rp.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
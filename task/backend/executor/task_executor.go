@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
@@ -32,14 +33,16 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 // LimitFunc is a function the executor will use to
 type LimitFunc func(*influxdb.Run) error
 
-// NewExecutor creates a new task executor
-func NewExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService) (*TaskExecutor, *ExecutorMetrics) {
+// NewExecutor creates a new task executor, the roles of the authorizations of
+// the tasks are resolved with rr unless it is nil.
+func NewExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService, rr *authorizer.RoleResolver) (*TaskExecutor, *ExecutorMetrics) {
 	te := &TaskExecutor{
 		logger: logger,
 		ts:     ts,
 		tcs:    tcs,
 		qs:     qs,
 		as:     as,
+		rr:     rr,
 
 		currentPromises: sync.Map{},
 		promiseQueue:    make(chan *Promise, 1000),                //TODO(lh): make this configurable
@@ -65,6 +68,7 @@ type TaskExecutor struct {
 
 	qs query.QueryService
 	as influxdb.AuthorizationService
+	rr *authorizer.RoleResolver
 
 	metrics *ExecutorMetrics
 
@@ -213,6 +217,10 @@ func (e *TaskExecutor) createPromise(ctx context.Context, run *influxdb.Run) (*P
 		return nil, err
 	}
 
+	ctx, err = taskContext(ctx, e.rr, t.Authorization)
+	if err != nil {
+		return nil, err
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	// create promise
 	p := &Promise{
@@ -36,7 +36,7 @@ func taskExecutorSystem(t *testing.T) tes {
 
 	i := kv.NewService(inmem.NewKVStore())
 
-	ex, metrics := NewExecutor(zaptest.NewLogger(t), qs, i, i, i)
+	ex, metrics := NewExecutor(zaptest.NewLogger(t), qs, i, i, i, nil)
 	return tes{
 		svc:     aqs,
 		ex:      ex,
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+// CoordinatingBucketService acts as a BucketService decorator that handles coordinating the api request
+// with the required task control actions of the downsample tiers of the bucket
+type CoordinatingBucketService struct {
+	influxdb.BucketService
+	coordinator Coordinator
+	taskService influxdb.TaskService
+}
+
+// NewBucketService constructs a new coordinating bucket service
+func NewBucketService(bs influxdb.BucketService, ts influxdb.TaskService, coordinator Coordinator) *CoordinatingBucketService {
+	return &CoordinatingBucketService{
+		BucketService: bs,
+		taskService:   ts,
+		coordinator:   coordinator,
+	}
+}
+
+// CreateBucket Creates a bucket and Publishes the tasks of its downsample tiers so they can be scheduled.
+func (bs *CoordinatingBucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	if err := bs.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	for _, tier := range b.DownsampleTiers {
+		t, err := bs.taskService.FindTaskByID(ctx, tier.TaskID)
+		if err == nil {
+			err = bs.coordinator.TaskCreated(ctx, t)
+		}
+		if err != nil {
+			if derr := bs.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+				return fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, derr)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateBucket Updates a bucket and publishes the changes to the tasks of its downsample tiers
+func (bs *CoordinatingBucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	from, err := bs.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTasks := make(map[influxdb.ID]*influxdb.Task, len(from.DownsampleTiers))
+	for _, tier := range from.DownsampleTiers {
+		t, err := bs.taskService.FindTaskByID(ctx, tier.TaskID)
+		if err == influxdb.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		fromTasks[t.ID] = t
+	}
+
+	to, err := bs.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return to, err
+	}
+
+	for _, tier := range to.DownsampleTiers {
+		toTask, err := bs.taskService.FindTaskByID(ctx, tier.TaskID)
+		if err != nil {
+			return nil, err
+		}
+
+		fromTask, ok := fromTasks[toTask.ID]
+		if !ok {
+			if err := bs.coordinator.TaskCreated(ctx, toTask); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		delete(fromTasks, toTask.ID)
+		if err := bs.coordinator.TaskUpdated(ctx, fromTask, toTask); err != nil {
+			return nil, err
+		}
+	}
+
+	for id := range fromTasks {
+		if err := bs.coordinator.TaskDeleted(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return to, nil
+}
+
+// DeleteBucket delete the bucket and publishes the deletion of the tasks of its downsample tiers.
+func (bs *CoordinatingBucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	b, err := bs.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+
+	for _, tier := range b.DownsampleTiers {
+		if err := bs.coordinator.TaskDeleted(ctx, tier.TaskID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
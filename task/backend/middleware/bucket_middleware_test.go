@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend/middleware"
+)
+
+func newBucketSvcStack() (mockedSvc, *mock.BucketService, *middleware.CoordinatingBucketService) {
+	msvcs := newMockServices()
+	bucketSvc := mock.NewBucketService()
+	return msvcs, bucketSvc, middleware.NewBucketService(bucketSvc, msvcs.taskSvc, msvcs.pipingCoordinator)
+}
+
+func TestBucketCreate(t *testing.T) {
+	mocks, bucketSvc, bucketService := newBucketSvcStack()
+	ch := mocks.pipingCoordinator.taskCreatedChan()
+
+	bucketSvc.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+		b.DownsampleTiers[0].TaskID = 4
+		return nil
+	}
+
+	b := &influxdb.Bucket{DownsampleTiers: []influxdb.DownsampleTier{{TargetBucketID: 2}}}
+	if err := bucketService.CreateBucket(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case task := <-ch:
+		if task.ID != 4 {
+			t.Fatalf("task sent to coordinator doesn't match expected")
+		}
+	default:
+		t.Fatal("didn't receive task")
+	}
+
+	mocks.pipingCoordinator.err = fmt.Errorf("bad")
+	bucketSvc.DeleteBucketFn = func(context.Context, influxdb.ID) error { return fmt.Errorf("AARGH") }
+
+	err := bucketService.CreateBucket(context.Background(), b)
+	if err.Error() != "schedule task failed: bad\n\tcleanup also failed: AARGH" {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketUpdate(t *testing.T) {
+	mocks, bucketSvc, bucketService := newBucketSvcStack()
+	mocks.pipingCoordinator.taskCreatedChan()
+	mocks.pipingCoordinator.taskUpdatedChan()
+	mocks.pipingCoordinator.taskDeletedChan()
+
+	bucketSvc.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, DownsampleTiers: []influxdb.DownsampleTier{
+			{TargetBucketID: 2, TaskID: 10},
+			{TargetBucketID: 3, TaskID: 11},
+		}}, nil
+	}
+	bucketSvc.UpdateBucketFn = func(_ context.Context, id influxdb.ID, _ influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, DownsampleTiers: []influxdb.DownsampleTier{
+			{TargetBucketID: 2, TaskID: 10},
+			{TargetBucketID: 4, TaskID: 12},
+		}}, nil
+	}
+
+	if _, err := bucketService.UpdateBucket(context.Background(), 1, influxdb.BucketUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  func() (influxdb.ID, bool)
+		want influxdb.ID
+	}{
+		{name: "created", want: 12, got: func() (influxdb.ID, bool) {
+			select {
+			case task := <-mocks.pipingCoordinator.taskCreatedPipe:
+				return task.ID, true
+			default:
+				return 0, false
+			}
+		}},
+		{name: "updated", want: 10, got: func() (influxdb.ID, bool) {
+			select {
+			case task := <-mocks.pipingCoordinator.taskUpdatedPipe:
+				return task.ID, true
+			default:
+				return 0, false
+			}
+		}},
+		{name: "deleted", want: 11, got: func() (influxdb.ID, bool) {
+			select {
+			case id := <-mocks.pipingCoordinator.taskDeletedPipe:
+				return id, true
+			default:
+				return 0, false
+			}
+		}},
+	} {
+		id, ok := tt.got()
+		if !ok {
+			t.Errorf("didn't receive %s task", tt.name)
+			continue
+		}
+		if id != tt.want {
+			t.Errorf("%s task sent to coordinator is %s, expected %s", tt.name, id, tt.want)
+		}
+	}
+}
+
+func TestBucketDelete(t *testing.T) {
+	mocks, bucketSvc, bucketService := newBucketSvcStack()
+	ch := mocks.pipingCoordinator.taskDeletedChan()
+
+	bucketSvc.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, DownsampleTiers: []influxdb.DownsampleTier{{TargetBucketID: 2, TaskID: 10}}}, nil
+	}
+
+	if err := bucketService.DeleteBucket(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case id := <-ch:
+		if id != 10 {
+			t.Fatalf("task sent to coordinator doesn't match expected")
+		}
+	default:
+		t.Fatal("didn't receive task")
+	}
+}
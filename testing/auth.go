@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
@@ -147,7 +148,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -155,7 +155,6 @@ func CreateAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 						Description: "new auth",
@@ -209,14 +208,12 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -270,7 +267,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -325,7 +321,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -347,6 +342,11 @@ func CreateAuthorization(
 
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
 
+			// The token is only returned by the creation of the authorization.
+			if err == nil && tt.args.authorization.Token == "" {
+				t.Errorf("expected the token of the created authorization")
+			}
+
 			defer s.DeleteAuthorization(ctx, tt.args.authorization.ID)
 
 			authorizations, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{})
@@ -424,7 +424,6 @@ func FindAuthorizationByID(
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
 					Status:      platform.Active,
-					Token:       "rand2",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 				},
 			},
@@ -537,7 +536,6 @@ func UpdateAuthorization(
 					ID:          MustIDBase16(authTwoID),
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
-					Token:       "rand2",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					Status:      platform.Inactive,
 					Description: "desc1",
@@ -714,6 +712,7 @@ func FindAuthorizationByToken(
 		authorization *platform.Authorization
 	}
 
+	expiredAt := time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
 	tests := []struct {
 		name   string
 		fields AuthorizationFields
@@ -789,6 +788,39 @@ func FindAuthorizationByToken(
 				},
 			},
 		},
+		{
+			name: "expired authorization",
+			fields: AuthorizationFields{
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand1",
+						ExpiresAt:   &expiredAt,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				err: platform.ErrAuthorizationExpired,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -872,7 +904,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -880,7 +911,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -941,7 +971,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -949,7 +978,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand3",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1011,7 +1039,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -1019,7 +1046,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1094,7 +1120,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgTwoID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: allUsersPermission(MustIDBase16(orgTwoID)),
 					},
 				},
@@ -1262,7 +1287,6 @@ func DeleteAuthorization(
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1317,7 +1341,6 @@ func DeleteAuthorization(
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						OrgID:       MustIDBase16(orgOneID),
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
@@ -1326,7 +1349,6 @@ func DeleteAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
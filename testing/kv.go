@@ -928,3 +928,57 @@ func KVConcurrentUpdate(
 		})
 	}
 }
+
+// KVMigrations tests the migrations of the kv service against the stores
+// returned by init. Each migration is applied to a new store after the
+// migrations before it, then reverted and applied again if it can be
+// reverted.
+func KVMigrations(
+	init func(*testing.T) (kv.Store, func()),
+	t *testing.T,
+) {
+	s, done := init(t)
+	migrations := kv.NewService(s).Migrations()
+	done()
+
+	for i, m := range migrations {
+		var previous int
+		if i > 0 {
+			previous = migrations[i-1].Version
+		}
+
+		m := m
+		t.Run(fmt.Sprintf("%d %s", m.Version, m.Name), func(t *testing.T) {
+			s, done := init(t)
+			defer done()
+
+			ctx := context.Background()
+			migrator, err := kv.NewService(s).Migrator()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			steps := []int{previous, m.Version}
+			if m.Down != nil {
+				steps = append(steps, previous, m.Version)
+			}
+			for _, version := range steps {
+				if err := migrator.MigrateTo(ctx, version); err != nil {
+					t.Fatalf("failed to migrate to version %d: %v", version, err)
+				}
+
+				applied, err := migrator.Applied(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var last int
+				if len(applied) > 0 {
+					last = applied[len(applied)-1].Version
+				}
+				if last != version {
+					t.Fatalf("expected store to be at version %d, got %d", version, last)
+				}
+			}
+		})
+	}
+}
@@ -77,6 +77,35 @@ func SecretService(
 	}
 }
 
+// ReadOnlySecretService will test the read methods of a secret service that
+// does not support writes.
+func ReadOnlySecretService(
+	init func(SecretServiceFields, *testing.T) (platform.SecretService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(
+			init func(SecretServiceFields, *testing.T) (platform.SecretService, func()),
+			t *testing.T,
+		)
+	}{
+		{
+			name: "LoadSecret",
+			fn:   LoadSecret,
+		},
+		{
+			name: "GetSecretKeys",
+			fn:   GetSecretKeys,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
 // LoadSecret tests the LoadSecret method for the SecretService interface.
 func LoadSecret(
 	init func(f SecretServiceFields, t *testing.T) (platform.SecretService, func()),
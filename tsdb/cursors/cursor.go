@@ -53,6 +53,41 @@ type CursorIterator interface {
 	Stats() CursorStats
 }
 
+// AggregateType is the type of the partial aggregates of an Aggregate.
+type AggregateType int
+
+const (
+	AggregateTypeCount AggregateType = iota
+	AggregateTypeSum
+	AggregateTypeFirst
+	AggregateTypeLast
+	AggregateTypeMin
+	AggregateTypeMax
+)
+
+// Aggregate describes the partial aggregates returned by the cursors of an
+// AggregateCursorIterator. A partial aggregate summarizes points of a single
+// window of Every nanoseconds, windows being aligned to the Unix epoch, or
+// points of the whole range if Every is zero.
+type Aggregate struct {
+	Type  AggregateType
+	Every int64
+}
+
+// AggregateCursorIterator is a CursorIterator that can return cursors of
+// partial aggregates, so that the aggregates can be computed without reading
+// every point. The aggregate of a window is the sum of the partial counts of
+// the window for AggregateTypeCount, and the same aggregate of the partial
+// aggregates of the window otherwise.
+//
+// The cursors of AggregateTypeCount return integer arrays whatever the type
+// of the field. Cursors of the other types return the type of the field, and
+// a point of the field is a valid partial aggregate of itself.
+type AggregateCursorIterator interface {
+	CursorIterator
+	NextAggregate(ctx context.Context, r *CursorRequest, agg Aggregate) (Cursor, error)
+}
+
 type CursorIterators []CursorIterator
 
 // Stats returns the aggregate stats of all cursor iterators.
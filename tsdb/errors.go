@@ -3,6 +3,8 @@ package tsdb
 import (
 	"errors"
 	"fmt"
+
+	"github.com/influxdata/influxdb/models"
 )
 
 var (
@@ -21,6 +23,15 @@ type PartialWriteError struct {
 
 	// A sorted slice of series keys that were dropped.
 	DroppedKeys [][]byte
+
+	// The points that were dropped, in the order they were dropped, and why.
+	DroppedPoints []DroppedPoint
+}
+
+// DroppedPoint is a point that was dropped from a write.
+type DroppedPoint struct {
+	Point  models.Point
+	Reason string
 }
 
 func (e PartialWriteError) Error() string {
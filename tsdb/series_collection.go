@@ -21,9 +21,10 @@ type SeriesCollection struct {
 	SeriesIDs  []SeriesID
 
 	// Keeps track of invalid entries.
-	Dropped     uint64
-	DroppedKeys [][]byte
-	Reason      string
+	Dropped       uint64
+	DroppedKeys   [][]byte
+	DroppedPoints []DroppedPoint
+	Reason        string
 
 	// Used by the concurrent iterators to stage drops. Inefficient, but should be
 	// very infrequently used.
@@ -34,7 +35,7 @@ type SeriesCollection struct {
 type seriesCollectionState struct {
 	mu     sync.Mutex
 	reason string
-	index  map[int]struct{}
+	index  map[int]string
 }
 
 // NewSeriesCollection builds a SeriesCollection from a slice of points. It does some filtering
@@ -200,12 +201,26 @@ func (s *SeriesCollection) Advance(length int) {
 
 // InvalidateAll causes all of the entries to become invalid.
 func (s *SeriesCollection) InvalidateAll(reason string) {
+	for i, length := 0, s.Length(); i < length; i++ {
+		s.Drop(i, reason)
+	}
+	s.Truncate(0)
+}
+
+// Drop records the entry at index as dropped for the reason. Only the first
+// reason is kept as the Reason of the collection. It does not remove the
+// entry; callers remove dropped entries with Copy and Truncate.
+func (s *SeriesCollection) Drop(index int, reason string) {
 	if s.Reason == "" {
 		s.Reason = reason
 	}
-	s.Dropped += uint64(len(s.Keys))
-	s.DroppedKeys = append(s.DroppedKeys, s.Keys...)
-	s.Truncate(0)
+	s.Dropped++
+	if index < len(s.Keys) {
+		s.DroppedKeys = append(s.DroppedKeys, s.Keys[index])
+	}
+	if index < len(s.Points) {
+		s.DroppedPoints = append(s.DroppedPoints, DroppedPoint{Point: s.Points[index], Reason: reason})
+	}
 }
 
 // ApplyConcurrentDrops will remove all of the dropped values during concurrent iteration. It should
@@ -216,15 +231,15 @@ func (s *SeriesCollection) ApplyConcurrentDrops() {
 		return
 	}
 
+	// keep the first reason that was staged, rather than the one of the first index.
+	if s.Reason == "" {
+		s.Reason = state.reason
+	}
+
 	length, j := s.Length(), 0
 	for i := 0; i < length; i++ {
-		if _, ok := state.index[i]; ok {
-			s.Dropped++
-
-			if i < len(s.Keys) {
-				s.DroppedKeys = append(s.DroppedKeys, s.Keys[i])
-			}
-
+		if reason, ok := state.index[i]; ok {
+			s.Drop(i, reason)
 			continue
 		}
 
@@ -233,10 +248,6 @@ func (s *SeriesCollection) ApplyConcurrentDrops() {
 	}
 	s.Truncate(j)
 
-	if s.Reason == "" {
-		s.Reason = state.reason
-	}
-
 	// clear concurrent state
 	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&s.state)), nil)
 }
@@ -266,9 +277,9 @@ func (s *SeriesCollection) invalidIndex(index int, reason string) {
 
 	state.mu.Lock()
 	if state.index == nil {
-		state.index = make(map[int]struct{})
+		state.index = make(map[int]string)
 	}
-	state.index[index] = struct{}{}
+	state.index[index] = reason
 	if state.reason == "" {
 		state.reason = reason
 	}
@@ -283,9 +294,10 @@ func (s *SeriesCollection) PartialWriteError() error {
 	}
 	droppedKeys := bytesutil.SortDedup(s.DroppedKeys)
 	return PartialWriteError{
-		Reason:      s.Reason,
-		Dropped:     len(droppedKeys),
-		DroppedKeys: droppedKeys,
+		Reason:        s.Reason,
+		Dropped:       len(droppedKeys),
+		DroppedKeys:   droppedKeys,
+		DroppedPoints: s.DroppedPoints,
 	}
 }
 
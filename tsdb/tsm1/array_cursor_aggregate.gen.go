@@ -0,0 +1,1282 @@
+// Generated by tmpl
+// https://github.com/benbjohnson/tmpl
+//
+// DO NOT EDIT!
+// Source: array_cursor_aggregate.gen.go.tmpl
+
+package tsm1
+
+import (
+	"math"
+	"sort"
+
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// floatArrayAggregateCursor returns the partial aggregates of a float field in ascending
+// order. Blocks are summarized without being decoded whenever possible, other
+// points are returned as they are.
+type floatArrayAggregateCursor struct {
+	agg cursors.Aggregate
+
+	cache struct {
+		values Values
+		pos    int
+	}
+
+	tsm struct {
+		buf       *tsdb.FloatArray
+		values    *tsdb.FloatArray
+		pos       int
+		keyCursor *KeyCursor
+		next      bool // keyCursor must move to the next block before it is read
+	}
+
+	seek, end int64
+	done      bool
+
+	// stop is the end of the window of the last partial aggregate.
+	stop int64
+
+	res    *tsdb.FloatArray
+	counts []int64 // partial counts of the points of res for AggregateTypeCount
+	stats  cursors.CursorStats
+}
+
+func newFloatArrayAggregateCursor() *floatArrayAggregateCursor {
+	c := &floatArrayAggregateCursor{
+		res: tsdb.NewFloatArrayLen(MaxPointsPerBlock),
+	}
+	c.tsm.buf = tsdb.NewFloatArrayLen(MaxPointsPerBlock)
+	return c
+}
+
+func (c *floatArrayAggregateCursor) reset(agg cursors.Aggregate, seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.agg = agg
+	c.seek, c.end = seek, end
+	c.done = false
+	c.stop = math.MinInt64
+	if agg.Type == cursors.AggregateTypeCount && c.counts == nil {
+		c.counts = make([]int64, MaxPointsPerBlock)
+	}
+
+	c.cache.values = cacheValues
+	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
+		return c.cache.values[i].UnixNano() >= seek
+	})
+
+	c.tsm.keyCursor = tsmKeyCursor
+	c.tsm.values = c.tsm.buf
+	c.tsm.values.Timestamps = c.tsm.values.Timestamps[:0]
+	c.tsm.values.Values = c.tsm.values.Values[:0]
+	c.tsm.pos = 0
+	c.tsm.next = false
+	c.stats = cursors.CursorStats{}
+}
+
+func (c *floatArrayAggregateCursor) Err() error { return nil }
+
+// Close closes the cursor and any dependent cursors.
+func (c *floatArrayAggregateCursor) Close() {
+	if c.tsm.keyCursor != nil {
+		c.tsm.keyCursor.Close()
+		c.tsm.keyCursor = nil
+	}
+	c.cache.values = nil
+	c.tsm.values = nil
+}
+
+func (c *floatArrayAggregateCursor) Stats() cursors.CursorStats { return c.stats }
+
+// Next returns the next partial aggregates.
+func (c *floatArrayAggregateCursor) Next() *tsdb.FloatArray {
+	n := c.read()
+	c.res.Timestamps = c.res.Timestamps[:n]
+	c.res.Values = c.res.Values[:n]
+	return c.res
+}
+
+// read fills res with the next partial aggregates and returns their number.
+func (c *floatArrayAggregateCursor) read() int {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	pos := 0
+	for pos < len(c.res.Timestamps) && !c.done {
+		// Merge the points of the decoded block with the cache.
+		if tvals := c.tsm.values; c.tsm.pos < len(tvals.Timestamps) {
+			tkey := tvals.Timestamps[c.tsm.pos]
+			if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= tkey {
+				if c.cache.values[c.cache.pos].UnixNano() == tkey {
+					c.tsm.pos++
+				}
+				pos += c.readCache(pos)
+				continue
+			}
+
+			if tkey >= c.end {
+				c.done = true
+				break
+			}
+			c.put(pos, tkey, tvals.Values[c.tsm.pos], 1)
+			c.scan(tvals.Values[c.tsm.pos])
+			c.tsm.pos++
+			pos++
+			continue
+		}
+
+		if c.tsm.keyCursor == nil {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if c.tsm.next {
+			c.tsm.keyCursor.Next()
+			c.tsm.next = false
+		}
+
+		// Points of the cache that precede the next block are returned first.
+		if len(c.tsm.keyCursor.current) == 0 {
+			pos += c.readCache(pos)
+			continue
+		} else if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() < c.tsm.keyCursor.current[0].entry.MinTime {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if l := c.tsm.keyCursor.wholeBlock(); l != nil {
+			if n, ok := c.summarize(pos, l); ok {
+				c.tsm.keyCursor.skipBlock(l)
+				c.tsm.next = true
+				pos += n
+				continue
+			}
+		}
+
+		c.tsm.values, _ = c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
+		c.tsm.pos = 0
+		c.tsm.next = true
+	}
+	return pos
+}
+
+// readCache returns the next point of the cache at pos. It returns the number
+// of points returned.
+func (c *floatArrayAggregateCursor) readCache(pos int) int {
+	if c.cache.pos >= len(c.cache.values) {
+		c.done = true
+		return 0
+	}
+
+	v := c.cache.values[c.cache.pos]
+	if v.UnixNano() >= c.end {
+		c.done = true
+		return 0
+	}
+	c.put(pos, v.UnixNano(), v.(FloatValue).RawValue(), 1)
+	c.scan(v.(FloatValue).RawValue())
+	c.cache.pos++
+	return 1
+}
+
+// put sets the partial aggregate at pos to v at ts, summarizing n points.
+func (c *floatArrayAggregateCursor) put(pos int, ts int64, v float64, n int64) {
+	c.res.Timestamps[pos] = ts
+	c.res.Values[pos] = v
+	if c.counts != nil {
+		c.counts[pos] = n
+	}
+	c.stop = windowStop(ts, c.agg.Every)
+}
+
+// scan records that v has been read. The points of summarized blocks are not
+// read.
+func (c *floatArrayAggregateCursor) scan(v float64) {
+	c.stats.ScannedValues++
+	c.stats.ScannedBytes += 8
+}
+
+// summarize sets the partial aggregate at pos to the summary of the block of
+// l, if the block lies within a single window and the range of the cursor and
+// can be summarized without being decoded. It returns the number of partial
+// aggregates, which is zero if the block does not contribute to the aggregate.
+func (c *floatArrayAggregateCursor) summarize(pos int, l *location) (int, bool) {
+	e := &l.entry
+	if e.MinTime < c.seek || e.MaxTime >= c.end {
+		return 0, false
+	}
+
+	stop := windowStop(e.MinTime, c.agg.Every)
+	if e.MaxTime >= stop {
+		return 0, false
+	}
+
+	// next is the time of the first point of the cache after the block.
+	next := int64(math.MaxInt64)
+	if c.cache.pos < len(c.cache.values) {
+		if next = c.cache.values[c.cache.pos].UnixNano(); next <= e.MaxTime {
+			return 0, false
+		}
+	}
+
+	switch c.agg.Type {
+	case cursors.AggregateTypeCount:
+		_, b, err := l.r.ReadBytes(e, nil)
+		if err != nil {
+			return 0, false
+		}
+		c.put(pos, e.MinTime, 0, int64(BlockCount(b)))
+		return 1, true
+	case cursors.AggregateTypeFirst:
+		// The first point of the window has already been returned.
+		if c.stop > e.MinTime {
+			return 0, true
+		}
+	case cursors.AggregateTypeLast:
+		// A later point of the window will be returned.
+		if ts, ok := c.tsm.keyCursor.nextBlockMinTime(); ok && ts < next {
+			next = ts
+		}
+		if next < stop && next < c.end {
+			return 0, true
+		}
+	case cursors.AggregateTypeSum, cursors.AggregateTypeMin, cursors.AggregateTypeMax:
+		s, ok := l.r.BlockStats(e)
+		if !ok {
+			return 0, false
+		}
+		switch c.agg.Type {
+		case cursors.AggregateTypeSum:
+			c.put(pos, e.MinTime, s.FloatSum(), 0)
+		case cursors.AggregateTypeMin:
+			c.put(pos, s.MinAt, s.FloatMin(), 0)
+		case cursors.AggregateTypeMax:
+			c.put(pos, s.MaxAt, s.FloatMax(), 0)
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// floatArrayCountCursor returns the partial counts of a floatArrayAggregateCursor.
+type floatArrayCountCursor struct {
+	*floatArrayAggregateCursor
+	arr tsdb.IntegerArray
+}
+
+// Next returns the next partial counts.
+func (c *floatArrayCountCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.arr.Timestamps = c.res.Timestamps[:n]
+	c.arr.Values = c.counts[:n]
+	return &c.arr
+}
+
+// integerArrayAggregateCursor returns the partial aggregates of a integer field in ascending
+// order. Blocks are summarized without being decoded whenever possible, other
+// points are returned as they are.
+type integerArrayAggregateCursor struct {
+	agg cursors.Aggregate
+
+	cache struct {
+		values Values
+		pos    int
+	}
+
+	tsm struct {
+		buf       *tsdb.IntegerArray
+		values    *tsdb.IntegerArray
+		pos       int
+		keyCursor *KeyCursor
+		next      bool // keyCursor must move to the next block before it is read
+	}
+
+	seek, end int64
+	done      bool
+
+	// stop is the end of the window of the last partial aggregate.
+	stop int64
+
+	res    *tsdb.IntegerArray
+	counts []int64 // partial counts of the points of res for AggregateTypeCount
+	stats  cursors.CursorStats
+}
+
+func newIntegerArrayAggregateCursor() *integerArrayAggregateCursor {
+	c := &integerArrayAggregateCursor{
+		res: tsdb.NewIntegerArrayLen(MaxPointsPerBlock),
+	}
+	c.tsm.buf = tsdb.NewIntegerArrayLen(MaxPointsPerBlock)
+	return c
+}
+
+func (c *integerArrayAggregateCursor) reset(agg cursors.Aggregate, seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.agg = agg
+	c.seek, c.end = seek, end
+	c.done = false
+	c.stop = math.MinInt64
+	if agg.Type == cursors.AggregateTypeCount && c.counts == nil {
+		c.counts = make([]int64, MaxPointsPerBlock)
+	}
+
+	c.cache.values = cacheValues
+	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
+		return c.cache.values[i].UnixNano() >= seek
+	})
+
+	c.tsm.keyCursor = tsmKeyCursor
+	c.tsm.values = c.tsm.buf
+	c.tsm.values.Timestamps = c.tsm.values.Timestamps[:0]
+	c.tsm.values.Values = c.tsm.values.Values[:0]
+	c.tsm.pos = 0
+	c.tsm.next = false
+	c.stats = cursors.CursorStats{}
+}
+
+func (c *integerArrayAggregateCursor) Err() error { return nil }
+
+// Close closes the cursor and any dependent cursors.
+func (c *integerArrayAggregateCursor) Close() {
+	if c.tsm.keyCursor != nil {
+		c.tsm.keyCursor.Close()
+		c.tsm.keyCursor = nil
+	}
+	c.cache.values = nil
+	c.tsm.values = nil
+}
+
+func (c *integerArrayAggregateCursor) Stats() cursors.CursorStats { return c.stats }
+
+// Next returns the next partial aggregates.
+func (c *integerArrayAggregateCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.res.Timestamps = c.res.Timestamps[:n]
+	c.res.Values = c.res.Values[:n]
+	return c.res
+}
+
+// read fills res with the next partial aggregates and returns their number.
+func (c *integerArrayAggregateCursor) read() int {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	pos := 0
+	for pos < len(c.res.Timestamps) && !c.done {
+		// Merge the points of the decoded block with the cache.
+		if tvals := c.tsm.values; c.tsm.pos < len(tvals.Timestamps) {
+			tkey := tvals.Timestamps[c.tsm.pos]
+			if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= tkey {
+				if c.cache.values[c.cache.pos].UnixNano() == tkey {
+					c.tsm.pos++
+				}
+				pos += c.readCache(pos)
+				continue
+			}
+
+			if tkey >= c.end {
+				c.done = true
+				break
+			}
+			c.put(pos, tkey, tvals.Values[c.tsm.pos], 1)
+			c.scan(tvals.Values[c.tsm.pos])
+			c.tsm.pos++
+			pos++
+			continue
+		}
+
+		if c.tsm.keyCursor == nil {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if c.tsm.next {
+			c.tsm.keyCursor.Next()
+			c.tsm.next = false
+		}
+
+		// Points of the cache that precede the next block are returned first.
+		if len(c.tsm.keyCursor.current) == 0 {
+			pos += c.readCache(pos)
+			continue
+		} else if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() < c.tsm.keyCursor.current[0].entry.MinTime {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if l := c.tsm.keyCursor.wholeBlock(); l != nil {
+			if n, ok := c.summarize(pos, l); ok {
+				c.tsm.keyCursor.skipBlock(l)
+				c.tsm.next = true
+				pos += n
+				continue
+			}
+		}
+
+		c.tsm.values, _ = c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
+		c.tsm.pos = 0
+		c.tsm.next = true
+	}
+	return pos
+}
+
+// readCache returns the next point of the cache at pos. It returns the number
+// of points returned.
+func (c *integerArrayAggregateCursor) readCache(pos int) int {
+	if c.cache.pos >= len(c.cache.values) {
+		c.done = true
+		return 0
+	}
+
+	v := c.cache.values[c.cache.pos]
+	if v.UnixNano() >= c.end {
+		c.done = true
+		return 0
+	}
+	c.put(pos, v.UnixNano(), v.(IntegerValue).RawValue(), 1)
+	c.scan(v.(IntegerValue).RawValue())
+	c.cache.pos++
+	return 1
+}
+
+// put sets the partial aggregate at pos to v at ts, summarizing n points.
+func (c *integerArrayAggregateCursor) put(pos int, ts int64, v int64, n int64) {
+	c.res.Timestamps[pos] = ts
+	c.res.Values[pos] = v
+	if c.counts != nil {
+		c.counts[pos] = n
+	}
+	c.stop = windowStop(ts, c.agg.Every)
+}
+
+// scan records that v has been read. The points of summarized blocks are not
+// read.
+func (c *integerArrayAggregateCursor) scan(v int64) {
+	c.stats.ScannedValues++
+	c.stats.ScannedBytes += 8
+}
+
+// summarize sets the partial aggregate at pos to the summary of the block of
+// l, if the block lies within a single window and the range of the cursor and
+// can be summarized without being decoded. It returns the number of partial
+// aggregates, which is zero if the block does not contribute to the aggregate.
+func (c *integerArrayAggregateCursor) summarize(pos int, l *location) (int, bool) {
+	e := &l.entry
+	if e.MinTime < c.seek || e.MaxTime >= c.end {
+		return 0, false
+	}
+
+	stop := windowStop(e.MinTime, c.agg.Every)
+	if e.MaxTime >= stop {
+		return 0, false
+	}
+
+	// next is the time of the first point of the cache after the block.
+	next := int64(math.MaxInt64)
+	if c.cache.pos < len(c.cache.values) {
+		if next = c.cache.values[c.cache.pos].UnixNano(); next <= e.MaxTime {
+			return 0, false
+		}
+	}
+
+	switch c.agg.Type {
+	case cursors.AggregateTypeCount:
+		_, b, err := l.r.ReadBytes(e, nil)
+		if err != nil {
+			return 0, false
+		}
+		c.put(pos, e.MinTime, 0, int64(BlockCount(b)))
+		return 1, true
+	case cursors.AggregateTypeFirst:
+		// The first point of the window has already been returned.
+		if c.stop > e.MinTime {
+			return 0, true
+		}
+	case cursors.AggregateTypeLast:
+		// A later point of the window will be returned.
+		if ts, ok := c.tsm.keyCursor.nextBlockMinTime(); ok && ts < next {
+			next = ts
+		}
+		if next < stop && next < c.end {
+			return 0, true
+		}
+	case cursors.AggregateTypeSum, cursors.AggregateTypeMin, cursors.AggregateTypeMax:
+		s, ok := l.r.BlockStats(e)
+		if !ok {
+			return 0, false
+		}
+		switch c.agg.Type {
+		case cursors.AggregateTypeSum:
+			c.put(pos, e.MinTime, s.IntegerSum(), 0)
+		case cursors.AggregateTypeMin:
+			c.put(pos, s.MinAt, s.IntegerMin(), 0)
+		case cursors.AggregateTypeMax:
+			c.put(pos, s.MaxAt, s.IntegerMax(), 0)
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// integerArrayCountCursor returns the partial counts of a integerArrayAggregateCursor.
+type integerArrayCountCursor struct {
+	*integerArrayAggregateCursor
+	arr tsdb.IntegerArray
+}
+
+// Next returns the next partial counts.
+func (c *integerArrayCountCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.arr.Timestamps = c.res.Timestamps[:n]
+	c.arr.Values = c.counts[:n]
+	return &c.arr
+}
+
+// unsignedArrayAggregateCursor returns the partial aggregates of a unsigned field in ascending
+// order. Blocks are summarized without being decoded whenever possible, other
+// points are returned as they are.
+type unsignedArrayAggregateCursor struct {
+	agg cursors.Aggregate
+
+	cache struct {
+		values Values
+		pos    int
+	}
+
+	tsm struct {
+		buf       *tsdb.UnsignedArray
+		values    *tsdb.UnsignedArray
+		pos       int
+		keyCursor *KeyCursor
+		next      bool // keyCursor must move to the next block before it is read
+	}
+
+	seek, end int64
+	done      bool
+
+	// stop is the end of the window of the last partial aggregate.
+	stop int64
+
+	res    *tsdb.UnsignedArray
+	counts []int64 // partial counts of the points of res for AggregateTypeCount
+	stats  cursors.CursorStats
+}
+
+func newUnsignedArrayAggregateCursor() *unsignedArrayAggregateCursor {
+	c := &unsignedArrayAggregateCursor{
+		res: tsdb.NewUnsignedArrayLen(MaxPointsPerBlock),
+	}
+	c.tsm.buf = tsdb.NewUnsignedArrayLen(MaxPointsPerBlock)
+	return c
+}
+
+func (c *unsignedArrayAggregateCursor) reset(agg cursors.Aggregate, seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.agg = agg
+	c.seek, c.end = seek, end
+	c.done = false
+	c.stop = math.MinInt64
+	if agg.Type == cursors.AggregateTypeCount && c.counts == nil {
+		c.counts = make([]int64, MaxPointsPerBlock)
+	}
+
+	c.cache.values = cacheValues
+	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
+		return c.cache.values[i].UnixNano() >= seek
+	})
+
+	c.tsm.keyCursor = tsmKeyCursor
+	c.tsm.values = c.tsm.buf
+	c.tsm.values.Timestamps = c.tsm.values.Timestamps[:0]
+	c.tsm.values.Values = c.tsm.values.Values[:0]
+	c.tsm.pos = 0
+	c.tsm.next = false
+	c.stats = cursors.CursorStats{}
+}
+
+func (c *unsignedArrayAggregateCursor) Err() error { return nil }
+
+// Close closes the cursor and any dependent cursors.
+func (c *unsignedArrayAggregateCursor) Close() {
+	if c.tsm.keyCursor != nil {
+		c.tsm.keyCursor.Close()
+		c.tsm.keyCursor = nil
+	}
+	c.cache.values = nil
+	c.tsm.values = nil
+}
+
+func (c *unsignedArrayAggregateCursor) Stats() cursors.CursorStats { return c.stats }
+
+// Next returns the next partial aggregates.
+func (c *unsignedArrayAggregateCursor) Next() *tsdb.UnsignedArray {
+	n := c.read()
+	c.res.Timestamps = c.res.Timestamps[:n]
+	c.res.Values = c.res.Values[:n]
+	return c.res
+}
+
+// read fills res with the next partial aggregates and returns their number.
+func (c *unsignedArrayAggregateCursor) read() int {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	pos := 0
+	for pos < len(c.res.Timestamps) && !c.done {
+		// Merge the points of the decoded block with the cache.
+		if tvals := c.tsm.values; c.tsm.pos < len(tvals.Timestamps) {
+			tkey := tvals.Timestamps[c.tsm.pos]
+			if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= tkey {
+				if c.cache.values[c.cache.pos].UnixNano() == tkey {
+					c.tsm.pos++
+				}
+				pos += c.readCache(pos)
+				continue
+			}
+
+			if tkey >= c.end {
+				c.done = true
+				break
+			}
+			c.put(pos, tkey, tvals.Values[c.tsm.pos], 1)
+			c.scan(tvals.Values[c.tsm.pos])
+			c.tsm.pos++
+			pos++
+			continue
+		}
+
+		if c.tsm.keyCursor == nil {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if c.tsm.next {
+			c.tsm.keyCursor.Next()
+			c.tsm.next = false
+		}
+
+		// Points of the cache that precede the next block are returned first.
+		if len(c.tsm.keyCursor.current) == 0 {
+			pos += c.readCache(pos)
+			continue
+		} else if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() < c.tsm.keyCursor.current[0].entry.MinTime {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if l := c.tsm.keyCursor.wholeBlock(); l != nil {
+			if n, ok := c.summarize(pos, l); ok {
+				c.tsm.keyCursor.skipBlock(l)
+				c.tsm.next = true
+				pos += n
+				continue
+			}
+		}
+
+		c.tsm.values, _ = c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
+		c.tsm.pos = 0
+		c.tsm.next = true
+	}
+	return pos
+}
+
+// readCache returns the next point of the cache at pos. It returns the number
+// of points returned.
+func (c *unsignedArrayAggregateCursor) readCache(pos int) int {
+	if c.cache.pos >= len(c.cache.values) {
+		c.done = true
+		return 0
+	}
+
+	v := c.cache.values[c.cache.pos]
+	if v.UnixNano() >= c.end {
+		c.done = true
+		return 0
+	}
+	c.put(pos, v.UnixNano(), v.(UnsignedValue).RawValue(), 1)
+	c.scan(v.(UnsignedValue).RawValue())
+	c.cache.pos++
+	return 1
+}
+
+// put sets the partial aggregate at pos to v at ts, summarizing n points.
+func (c *unsignedArrayAggregateCursor) put(pos int, ts int64, v uint64, n int64) {
+	c.res.Timestamps[pos] = ts
+	c.res.Values[pos] = v
+	if c.counts != nil {
+		c.counts[pos] = n
+	}
+	c.stop = windowStop(ts, c.agg.Every)
+}
+
+// scan records that v has been read. The points of summarized blocks are not
+// read.
+func (c *unsignedArrayAggregateCursor) scan(v uint64) {
+	c.stats.ScannedValues++
+	c.stats.ScannedBytes += 8
+}
+
+// summarize sets the partial aggregate at pos to the summary of the block of
+// l, if the block lies within a single window and the range of the cursor and
+// can be summarized without being decoded. It returns the number of partial
+// aggregates, which is zero if the block does not contribute to the aggregate.
+func (c *unsignedArrayAggregateCursor) summarize(pos int, l *location) (int, bool) {
+	e := &l.entry
+	if e.MinTime < c.seek || e.MaxTime >= c.end {
+		return 0, false
+	}
+
+	stop := windowStop(e.MinTime, c.agg.Every)
+	if e.MaxTime >= stop {
+		return 0, false
+	}
+
+	// next is the time of the first point of the cache after the block.
+	next := int64(math.MaxInt64)
+	if c.cache.pos < len(c.cache.values) {
+		if next = c.cache.values[c.cache.pos].UnixNano(); next <= e.MaxTime {
+			return 0, false
+		}
+	}
+
+	switch c.agg.Type {
+	case cursors.AggregateTypeCount:
+		_, b, err := l.r.ReadBytes(e, nil)
+		if err != nil {
+			return 0, false
+		}
+		c.put(pos, e.MinTime, 0, int64(BlockCount(b)))
+		return 1, true
+	case cursors.AggregateTypeFirst:
+		// The first point of the window has already been returned.
+		if c.stop > e.MinTime {
+			return 0, true
+		}
+	case cursors.AggregateTypeLast:
+		// A later point of the window will be returned.
+		if ts, ok := c.tsm.keyCursor.nextBlockMinTime(); ok && ts < next {
+			next = ts
+		}
+		if next < stop && next < c.end {
+			return 0, true
+		}
+	case cursors.AggregateTypeSum, cursors.AggregateTypeMin, cursors.AggregateTypeMax:
+		s, ok := l.r.BlockStats(e)
+		if !ok {
+			return 0, false
+		}
+		switch c.agg.Type {
+		case cursors.AggregateTypeSum:
+			c.put(pos, e.MinTime, s.UnsignedSum(), 0)
+		case cursors.AggregateTypeMin:
+			c.put(pos, s.MinAt, s.UnsignedMin(), 0)
+		case cursors.AggregateTypeMax:
+			c.put(pos, s.MaxAt, s.UnsignedMax(), 0)
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// unsignedArrayCountCursor returns the partial counts of a unsignedArrayAggregateCursor.
+type unsignedArrayCountCursor struct {
+	*unsignedArrayAggregateCursor
+	arr tsdb.IntegerArray
+}
+
+// Next returns the next partial counts.
+func (c *unsignedArrayCountCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.arr.Timestamps = c.res.Timestamps[:n]
+	c.arr.Values = c.counts[:n]
+	return &c.arr
+}
+
+// stringArrayAggregateCursor returns the partial aggregates of a string field in ascending
+// order. Blocks are summarized without being decoded whenever possible, other
+// points are returned as they are.
+type stringArrayAggregateCursor struct {
+	agg cursors.Aggregate
+
+	cache struct {
+		values Values
+		pos    int
+	}
+
+	tsm struct {
+		buf       *tsdb.StringArray
+		values    *tsdb.StringArray
+		pos       int
+		keyCursor *KeyCursor
+		next      bool // keyCursor must move to the next block before it is read
+	}
+
+	seek, end int64
+	done      bool
+
+	// stop is the end of the window of the last partial aggregate.
+	stop int64
+
+	res    *tsdb.StringArray
+	counts []int64 // partial counts of the points of res for AggregateTypeCount
+	stats  cursors.CursorStats
+}
+
+func newStringArrayAggregateCursor() *stringArrayAggregateCursor {
+	c := &stringArrayAggregateCursor{
+		res: tsdb.NewStringArrayLen(MaxPointsPerBlock),
+	}
+	c.tsm.buf = tsdb.NewStringArrayLen(MaxPointsPerBlock)
+	return c
+}
+
+func (c *stringArrayAggregateCursor) reset(agg cursors.Aggregate, seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.agg = agg
+	c.seek, c.end = seek, end
+	c.done = false
+	c.stop = math.MinInt64
+	if agg.Type == cursors.AggregateTypeCount && c.counts == nil {
+		c.counts = make([]int64, MaxPointsPerBlock)
+	}
+
+	c.cache.values = cacheValues
+	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
+		return c.cache.values[i].UnixNano() >= seek
+	})
+
+	c.tsm.keyCursor = tsmKeyCursor
+	c.tsm.values = c.tsm.buf
+	c.tsm.values.Timestamps = c.tsm.values.Timestamps[:0]
+	c.tsm.values.Values = c.tsm.values.Values[:0]
+	c.tsm.pos = 0
+	c.tsm.next = false
+	c.stats = cursors.CursorStats{}
+}
+
+func (c *stringArrayAggregateCursor) Err() error { return nil }
+
+// Close closes the cursor and any dependent cursors.
+func (c *stringArrayAggregateCursor) Close() {
+	if c.tsm.keyCursor != nil {
+		c.tsm.keyCursor.Close()
+		c.tsm.keyCursor = nil
+	}
+	c.cache.values = nil
+	c.tsm.values = nil
+}
+
+func (c *stringArrayAggregateCursor) Stats() cursors.CursorStats { return c.stats }
+
+// Next returns the next partial aggregates.
+func (c *stringArrayAggregateCursor) Next() *tsdb.StringArray {
+	n := c.read()
+	c.res.Timestamps = c.res.Timestamps[:n]
+	c.res.Values = c.res.Values[:n]
+	return c.res
+}
+
+// read fills res with the next partial aggregates and returns their number.
+func (c *stringArrayAggregateCursor) read() int {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	pos := 0
+	for pos < len(c.res.Timestamps) && !c.done {
+		// Merge the points of the decoded block with the cache.
+		if tvals := c.tsm.values; c.tsm.pos < len(tvals.Timestamps) {
+			tkey := tvals.Timestamps[c.tsm.pos]
+			if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= tkey {
+				if c.cache.values[c.cache.pos].UnixNano() == tkey {
+					c.tsm.pos++
+				}
+				pos += c.readCache(pos)
+				continue
+			}
+
+			if tkey >= c.end {
+				c.done = true
+				break
+			}
+			c.put(pos, tkey, tvals.Values[c.tsm.pos], 1)
+			c.scan(tvals.Values[c.tsm.pos])
+			c.tsm.pos++
+			pos++
+			continue
+		}
+
+		if c.tsm.keyCursor == nil {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if c.tsm.next {
+			c.tsm.keyCursor.Next()
+			c.tsm.next = false
+		}
+
+		// Points of the cache that precede the next block are returned first.
+		if len(c.tsm.keyCursor.current) == 0 {
+			pos += c.readCache(pos)
+			continue
+		} else if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() < c.tsm.keyCursor.current[0].entry.MinTime {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if l := c.tsm.keyCursor.wholeBlock(); l != nil {
+			if n, ok := c.summarize(pos, l); ok {
+				c.tsm.keyCursor.skipBlock(l)
+				c.tsm.next = true
+				pos += n
+				continue
+			}
+		}
+
+		c.tsm.values, _ = c.tsm.keyCursor.ReadStringArrayBlock(c.tsm.buf)
+		c.tsm.pos = 0
+		c.tsm.next = true
+	}
+	return pos
+}
+
+// readCache returns the next point of the cache at pos. It returns the number
+// of points returned.
+func (c *stringArrayAggregateCursor) readCache(pos int) int {
+	if c.cache.pos >= len(c.cache.values) {
+		c.done = true
+		return 0
+	}
+
+	v := c.cache.values[c.cache.pos]
+	if v.UnixNano() >= c.end {
+		c.done = true
+		return 0
+	}
+	c.put(pos, v.UnixNano(), v.(StringValue).RawValue(), 1)
+	c.scan(v.(StringValue).RawValue())
+	c.cache.pos++
+	return 1
+}
+
+// put sets the partial aggregate at pos to v at ts, summarizing n points.
+func (c *stringArrayAggregateCursor) put(pos int, ts int64, v string, n int64) {
+	c.res.Timestamps[pos] = ts
+	c.res.Values[pos] = v
+	if c.counts != nil {
+		c.counts[pos] = n
+	}
+	c.stop = windowStop(ts, c.agg.Every)
+}
+
+// scan records that v has been read. The points of summarized blocks are not
+// read.
+func (c *stringArrayAggregateCursor) scan(v string) {
+	c.stats.ScannedValues++
+	c.stats.ScannedBytes += len(v)
+}
+
+// summarize sets the partial aggregate at pos to the summary of the block of
+// l, if the block lies within a single window and the range of the cursor and
+// can be summarized without being decoded. It returns the number of partial
+// aggregates, which is zero if the block does not contribute to the aggregate.
+func (c *stringArrayAggregateCursor) summarize(pos int, l *location) (int, bool) {
+	e := &l.entry
+	if e.MinTime < c.seek || e.MaxTime >= c.end {
+		return 0, false
+	}
+
+	stop := windowStop(e.MinTime, c.agg.Every)
+	if e.MaxTime >= stop {
+		return 0, false
+	}
+
+	// next is the time of the first point of the cache after the block.
+	next := int64(math.MaxInt64)
+	if c.cache.pos < len(c.cache.values) {
+		if next = c.cache.values[c.cache.pos].UnixNano(); next <= e.MaxTime {
+			return 0, false
+		}
+	}
+
+	switch c.agg.Type {
+	case cursors.AggregateTypeCount:
+		_, b, err := l.r.ReadBytes(e, nil)
+		if err != nil {
+			return 0, false
+		}
+		c.put(pos, e.MinTime, "", int64(BlockCount(b)))
+		return 1, true
+	case cursors.AggregateTypeFirst:
+		// The first point of the window has already been returned.
+		if c.stop > e.MinTime {
+			return 0, true
+		}
+	case cursors.AggregateTypeLast:
+		// A later point of the window will be returned.
+		if ts, ok := c.tsm.keyCursor.nextBlockMinTime(); ok && ts < next {
+			next = ts
+		}
+		if next < stop && next < c.end {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// stringArrayCountCursor returns the partial counts of a stringArrayAggregateCursor.
+type stringArrayCountCursor struct {
+	*stringArrayAggregateCursor
+	arr tsdb.IntegerArray
+}
+
+// Next returns the next partial counts.
+func (c *stringArrayCountCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.arr.Timestamps = c.res.Timestamps[:n]
+	c.arr.Values = c.counts[:n]
+	return &c.arr
+}
+
+// booleanArrayAggregateCursor returns the partial aggregates of a boolean field in ascending
+// order. Blocks are summarized without being decoded whenever possible, other
+// points are returned as they are.
+type booleanArrayAggregateCursor struct {
+	agg cursors.Aggregate
+
+	cache struct {
+		values Values
+		pos    int
+	}
+
+	tsm struct {
+		buf       *tsdb.BooleanArray
+		values    *tsdb.BooleanArray
+		pos       int
+		keyCursor *KeyCursor
+		next      bool // keyCursor must move to the next block before it is read
+	}
+
+	seek, end int64
+	done      bool
+
+	// stop is the end of the window of the last partial aggregate.
+	stop int64
+
+	res    *tsdb.BooleanArray
+	counts []int64 // partial counts of the points of res for AggregateTypeCount
+	stats  cursors.CursorStats
+}
+
+func newBooleanArrayAggregateCursor() *booleanArrayAggregateCursor {
+	c := &booleanArrayAggregateCursor{
+		res: tsdb.NewBooleanArrayLen(MaxPointsPerBlock),
+	}
+	c.tsm.buf = tsdb.NewBooleanArrayLen(MaxPointsPerBlock)
+	return c
+}
+
+func (c *booleanArrayAggregateCursor) reset(agg cursors.Aggregate, seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.agg = agg
+	c.seek, c.end = seek, end
+	c.done = false
+	c.stop = math.MinInt64
+	if agg.Type == cursors.AggregateTypeCount && c.counts == nil {
+		c.counts = make([]int64, MaxPointsPerBlock)
+	}
+
+	c.cache.values = cacheValues
+	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
+		return c.cache.values[i].UnixNano() >= seek
+	})
+
+	c.tsm.keyCursor = tsmKeyCursor
+	c.tsm.values = c.tsm.buf
+	c.tsm.values.Timestamps = c.tsm.values.Timestamps[:0]
+	c.tsm.values.Values = c.tsm.values.Values[:0]
+	c.tsm.pos = 0
+	c.tsm.next = false
+	c.stats = cursors.CursorStats{}
+}
+
+func (c *booleanArrayAggregateCursor) Err() error { return nil }
+
+// Close closes the cursor and any dependent cursors.
+func (c *booleanArrayAggregateCursor) Close() {
+	if c.tsm.keyCursor != nil {
+		c.tsm.keyCursor.Close()
+		c.tsm.keyCursor = nil
+	}
+	c.cache.values = nil
+	c.tsm.values = nil
+}
+
+func (c *booleanArrayAggregateCursor) Stats() cursors.CursorStats { return c.stats }
+
+// Next returns the next partial aggregates.
+func (c *booleanArrayAggregateCursor) Next() *tsdb.BooleanArray {
+	n := c.read()
+	c.res.Timestamps = c.res.Timestamps[:n]
+	c.res.Values = c.res.Values[:n]
+	return c.res
+}
+
+// read fills res with the next partial aggregates and returns their number.
+func (c *booleanArrayAggregateCursor) read() int {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	pos := 0
+	for pos < len(c.res.Timestamps) && !c.done {
+		// Merge the points of the decoded block with the cache.
+		if tvals := c.tsm.values; c.tsm.pos < len(tvals.Timestamps) {
+			tkey := tvals.Timestamps[c.tsm.pos]
+			if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= tkey {
+				if c.cache.values[c.cache.pos].UnixNano() == tkey {
+					c.tsm.pos++
+				}
+				pos += c.readCache(pos)
+				continue
+			}
+
+			if tkey >= c.end {
+				c.done = true
+				break
+			}
+			c.put(pos, tkey, tvals.Values[c.tsm.pos], 1)
+			c.scan(tvals.Values[c.tsm.pos])
+			c.tsm.pos++
+			pos++
+			continue
+		}
+
+		if c.tsm.keyCursor == nil {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if c.tsm.next {
+			c.tsm.keyCursor.Next()
+			c.tsm.next = false
+		}
+
+		// Points of the cache that precede the next block are returned first.
+		if len(c.tsm.keyCursor.current) == 0 {
+			pos += c.readCache(pos)
+			continue
+		} else if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() < c.tsm.keyCursor.current[0].entry.MinTime {
+			pos += c.readCache(pos)
+			continue
+		}
+
+		if l := c.tsm.keyCursor.wholeBlock(); l != nil {
+			if n, ok := c.summarize(pos, l); ok {
+				c.tsm.keyCursor.skipBlock(l)
+				c.tsm.next = true
+				pos += n
+				continue
+			}
+		}
+
+		c.tsm.values, _ = c.tsm.keyCursor.ReadBooleanArrayBlock(c.tsm.buf)
+		c.tsm.pos = 0
+		c.tsm.next = true
+	}
+	return pos
+}
+
+// readCache returns the next point of the cache at pos. It returns the number
+// of points returned.
+func (c *booleanArrayAggregateCursor) readCache(pos int) int {
+	if c.cache.pos >= len(c.cache.values) {
+		c.done = true
+		return 0
+	}
+
+	v := c.cache.values[c.cache.pos]
+	if v.UnixNano() >= c.end {
+		c.done = true
+		return 0
+	}
+	c.put(pos, v.UnixNano(), v.(BooleanValue).RawValue(), 1)
+	c.scan(v.(BooleanValue).RawValue())
+	c.cache.pos++
+	return 1
+}
+
+// put sets the partial aggregate at pos to v at ts, summarizing n points.
+func (c *booleanArrayAggregateCursor) put(pos int, ts int64, v bool, n int64) {
+	c.res.Timestamps[pos] = ts
+	c.res.Values[pos] = v
+	if c.counts != nil {
+		c.counts[pos] = n
+	}
+	c.stop = windowStop(ts, c.agg.Every)
+}
+
+// scan records that v has been read. The points of summarized blocks are not
+// read.
+func (c *booleanArrayAggregateCursor) scan(v bool) {
+	c.stats.ScannedValues++
+	c.stats.ScannedBytes += 1
+}
+
+// summarize sets the partial aggregate at pos to the summary of the block of
+// l, if the block lies within a single window and the range of the cursor and
+// can be summarized without being decoded. It returns the number of partial
+// aggregates, which is zero if the block does not contribute to the aggregate.
+func (c *booleanArrayAggregateCursor) summarize(pos int, l *location) (int, bool) {
+	e := &l.entry
+	if e.MinTime < c.seek || e.MaxTime >= c.end {
+		return 0, false
+	}
+
+	stop := windowStop(e.MinTime, c.agg.Every)
+	if e.MaxTime >= stop {
+		return 0, false
+	}
+
+	// next is the time of the first point of the cache after the block.
+	next := int64(math.MaxInt64)
+	if c.cache.pos < len(c.cache.values) {
+		if next = c.cache.values[c.cache.pos].UnixNano(); next <= e.MaxTime {
+			return 0, false
+		}
+	}
+
+	switch c.agg.Type {
+	case cursors.AggregateTypeCount:
+		_, b, err := l.r.ReadBytes(e, nil)
+		if err != nil {
+			return 0, false
+		}
+		c.put(pos, e.MinTime, false, int64(BlockCount(b)))
+		return 1, true
+	case cursors.AggregateTypeFirst:
+		// The first point of the window has already been returned.
+		if c.stop > e.MinTime {
+			return 0, true
+		}
+	case cursors.AggregateTypeLast:
+		// A later point of the window will be returned.
+		if ts, ok := c.tsm.keyCursor.nextBlockMinTime(); ok && ts < next {
+			next = ts
+		}
+		if next < stop && next < c.end {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// booleanArrayCountCursor returns the partial counts of a booleanArrayAggregateCursor.
+type booleanArrayCountCursor struct {
+	*booleanArrayAggregateCursor
+	arr tsdb.IntegerArray
+}
+
+// Next returns the next partial counts.
+func (c *booleanArrayCountCursor) Next() *tsdb.IntegerArray {
+	n := c.read()
+	c.arr.Timestamps = c.res.Timestamps[:n]
+	c.arr.Values = c.counts[:n]
+	return &c.arr
+}
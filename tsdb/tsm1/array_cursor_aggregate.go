@@ -0,0 +1,183 @@
+package tsm1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/metrics"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// NextAggregate returns a cursor of partial aggregates of the series of r.
+// Blocks that lie within a single window are summarized from the index and
+// the block statistics, only blocks that straddle windows or the bounds of
+// the range are decoded. Descending requests return the raw points.
+func (q *arrayCursorIterator) NextAggregate(ctx context.Context, r *tsdb.CursorRequest, agg cursors.Aggregate) (tsdb.Cursor, error) {
+	if !r.Ascending {
+		return q.Next(ctx, r)
+	}
+
+	q.key = tsdb.AppendSeriesKey(q.key[:0], r.Name, r.Tags)
+	id := q.e.sfile.SeriesIDTypedBySeriesKey(q.key)
+	if id.IsZero() {
+		return nil, nil
+	}
+
+	q.e.readTracker.AddCursors(1)
+
+	if grp := metrics.GroupFromContext(ctx); grp != nil {
+		grp.GetCounter(numberOfRefCursorsCounter).Add(1)
+	}
+
+	key := q.seriesFieldKeyBytes(r.Name, r.Tags, r.Field)
+	cacheValues := q.e.Cache.Values(key)
+	keyCursor := q.e.KeyCursor(ctx, key, r.StartTime, true)
+
+	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
+
+	switch typ := id.Type(); typ {
+	case models.Float:
+		if q.aggs.Float == nil {
+			q.aggs.Float = newFloatArrayAggregateCursor()
+		}
+		q.aggs.Float.reset(agg, r.StartTime, r.EndTime, cacheValues, keyCursor)
+		if agg.Type == cursors.AggregateTypeCount {
+			return &floatArrayCountCursor{floatArrayAggregateCursor: q.aggs.Float}, nil
+		}
+		return q.aggs.Float, nil
+	case models.Integer:
+		if q.aggs.Integer == nil {
+			q.aggs.Integer = newIntegerArrayAggregateCursor()
+		}
+		q.aggs.Integer.reset(agg, r.StartTime, r.EndTime, cacheValues, keyCursor)
+		if agg.Type == cursors.AggregateTypeCount {
+			return &integerArrayCountCursor{integerArrayAggregateCursor: q.aggs.Integer}, nil
+		}
+		return q.aggs.Integer, nil
+	case models.Unsigned:
+		if q.aggs.Unsigned == nil {
+			q.aggs.Unsigned = newUnsignedArrayAggregateCursor()
+		}
+		q.aggs.Unsigned.reset(agg, r.StartTime, r.EndTime, cacheValues, keyCursor)
+		if agg.Type == cursors.AggregateTypeCount {
+			return &unsignedArrayCountCursor{unsignedArrayAggregateCursor: q.aggs.Unsigned}, nil
+		}
+		return q.aggs.Unsigned, nil
+	case models.String:
+		if q.aggs.String == nil {
+			q.aggs.String = newStringArrayAggregateCursor()
+		}
+		q.aggs.String.reset(agg, r.StartTime, r.EndTime, cacheValues, keyCursor)
+		if agg.Type == cursors.AggregateTypeCount {
+			return &stringArrayCountCursor{stringArrayAggregateCursor: q.aggs.String}, nil
+		}
+		return q.aggs.String, nil
+	case models.Boolean:
+		if q.aggs.Boolean == nil {
+			q.aggs.Boolean = newBooleanArrayAggregateCursor()
+		}
+		q.aggs.Boolean.reset(agg, r.StartTime, r.EndTime, cacheValues, keyCursor)
+		if agg.Type == cursors.AggregateTypeCount {
+			return &booleanArrayCountCursor{booleanArrayAggregateCursor: q.aggs.Boolean}, nil
+		}
+		return q.aggs.Boolean, nil
+	default:
+		panic(fmt.Sprintf("unreachable: %v", typ))
+	}
+}
+
+// windowStop returns the exclusive upper bound of the window of every
+// nanoseconds that contains ts. A zero every is a single window that contains
+// every timestamp.
+func windowStop(ts, every int64) int64 {
+	if every == 0 {
+		return math.MaxInt64
+	}
+	return ts - ts%every + every
+}
+
+// wholeBlock returns the location of the next block of an ascending cursor if
+// the block can be summarized without being read: it does not overlap any
+// other block, none of its points have been read and none have been deleted.
+// It returns nil otherwise.
+func (c *KeyCursor) wholeBlock() *location {
+	if !c.ascending || len(c.current) != 1 {
+		return nil
+	}
+
+	l := c.current[0]
+	if l.readMax >= l.entry.MinTime || c.overlapping()[c.pos] {
+		return nil
+	}
+
+	c.trbuf = l.r.TombstoneRange(c.key, c.trbuf[:0])
+	for _, t := range c.trbuf {
+		if t.Min <= l.entry.MaxTime && t.Max >= l.entry.MinTime {
+			return nil
+		}
+	}
+	return l
+}
+
+// skipBlock marks the block of l as read without reading it. Next must be
+// called to move the cursor to the following block.
+func (c *KeyCursor) skipBlock(l *location) {
+	l.markRead(l.entry.MinTime, l.entry.MaxTime)
+}
+
+// nextBlockMinTime returns the min time of the block that follows the current
+// block of an ascending cursor, if the point at that time has been neither read
+// nor deleted.
+func (c *KeyCursor) nextBlockMinTime() (int64, bool) {
+	if !c.ascending || c.pos+1 >= len(c.seeks) {
+		return 0, false
+	}
+
+	l := c.seeks[c.pos+1]
+	if l.readMax >= l.entry.MinTime {
+		return 0, false
+	}
+
+	c.trbuf = l.r.TombstoneRange(c.key, c.trbuf[:0])
+	for _, t := range c.trbuf {
+		if t.Min <= l.entry.MinTime && t.Max >= l.entry.MinTime {
+			return 0, false
+		}
+	}
+	return l.entry.MinTime, true
+}
+
+// overlapping returns whether the block of each location of seeks overlaps
+// the block of another location.
+func (c *KeyCursor) overlapping() []bool {
+	if c.overlaps != nil {
+		return c.overlaps
+	}
+
+	c.overlaps = make([]bool, len(c.seeks))
+	idx := make([]int, len(c.seeks))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		return c.seeks[idx[i]].entry.MinTime < c.seeks[idx[j]].entry.MinTime
+	})
+
+	// last is the location with the greatest max time seen so far.
+	last := -1
+	for _, i := range idx {
+		e := &c.seeks[i].entry
+		if last >= 0 && e.MinTime <= c.seeks[last].entry.MaxTime {
+			c.overlaps[i] = true
+			c.overlaps[last] = true
+		}
+		if last < 0 || e.MaxTime > c.seeks[last].entry.MaxTime {
+			last = i
+		}
+	}
+	return c.overlaps
+}
@@ -0,0 +1,179 @@
+package tsm1_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// aggregatePoint is the aggregate of a window.
+type aggregatePoint struct {
+	ts int64
+	v  int64
+}
+
+// TestEngine_NextAggregate verifies that combining the partial aggregates of
+// blocks, cache values and points gives the aggregates of the points.
+func TestEngine_NextAggregate(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	value := func(ts int64) int64 { return ts * 7919 % 1009 }
+	write := func(min, max int64) {
+		var lines []string
+		for ts := min; ts < max; ts++ {
+			lines = append(lines, fmt.Sprintf("cpu,host=a f=%di %d", value(ts), ts))
+		}
+		if err := e.WritePointsString("mm", strings.Join(lines, "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Non overlapping blocks, blocks that overlap, deleted points and points
+	// of the cache that fall inside blocks and after them.
+	write(0, 3000)
+	e.MustWriteSnapshot()
+	write(2500, 3500)
+	e.MustWriteSnapshot()
+	write(5000, 8000)
+	e.MustWriteSnapshot()
+	if err := e.DeletePrefixRange(context.Background(), []byte("mm"), 6200, 6300, nil); err != nil {
+		t.Fatal(err)
+	}
+	write(7500, 7510)
+	write(9000, 9010)
+
+	req := &tsdb.CursorRequest{
+		Name: []byte("mm"),
+		Tags: models.NewTags(map[string]string{
+			models.MeasurementTagKey: "cpu",
+			"host":                   "a",
+			models.FieldKeyTagKey:    "f",
+		}),
+		Field:     "f",
+		Ascending: true,
+		StartTime: 100,
+		EndTime:   9005,
+	}
+
+	itr, err := e.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := itr.Next(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ts, vs []int64
+	for a := cur.(tsdb.IntegerArrayCursor).Next(); a.Len() > 0; a = cur.(tsdb.IntegerArrayCursor).Next() {
+		ts = append(ts, a.Timestamps...)
+		vs = append(vs, a.Values...)
+	}
+	cur.Close()
+	if len(ts) == 0 {
+		t.Fatal("expected points")
+	}
+
+	for _, every := range []int64{0, 700, 1000, 3000} {
+		for _, typ := range []cursors.AggregateType{
+			cursors.AggregateTypeCount,
+			cursors.AggregateTypeSum,
+			cursors.AggregateTypeFirst,
+			cursors.AggregateTypeLast,
+			cursors.AggregateTypeMin,
+			cursors.AggregateTypeMax,
+		} {
+			t.Run(fmt.Sprintf("%v/%d", typ, every), func(t *testing.T) {
+				itr, err := e.CreateCursorIterator(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				cur, err := itr.(cursors.AggregateCursorIterator).NextAggregate(context.Background(), req, cursors.Aggregate{Type: typ, Every: every})
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer cur.Close()
+
+				var pts, pvs []int64
+				for a := cur.(tsdb.IntegerArrayCursor).Next(); a.Len() > 0; a = cur.(tsdb.IntegerArrayCursor).Next() {
+					pts = append(pts, a.Timestamps...)
+					pvs = append(pvs, a.Values...)
+				}
+
+				// Counts are combined by sum, points are their own count.
+				exp, ctyp, cvs := aggregateWindows(typ, every, ts, vs), typ, pvs
+				if typ == cursors.AggregateTypeCount {
+					ctyp = cursors.AggregateTypeSum
+				}
+				if got := aggregateWindows(ctyp, every, pts, cvs); fmt.Sprint(got) != fmt.Sprint(exp) {
+					t.Fatalf("unexpected aggregates: got %v, exp %v", got, exp)
+				}
+
+				if every == 0 && typ != cursors.AggregateTypeFirst {
+					if got := cur.Stats().ScannedValues; got >= len(ts) {
+						t.Fatalf("expected summarized blocks: scanned %d of %d values", got, len(ts))
+					}
+				}
+			})
+		}
+	}
+}
+
+// aggregateWindows aggregates the points of ts and vs by windows of every
+// nanoseconds. Ties of min and max are broken by the earliest point.
+func aggregateWindows(typ cursors.AggregateType, every int64, ts, vs []int64) map[int64]aggregatePoint {
+	m := make(map[int64]aggregatePoint)
+	for i := range ts {
+		w := int64(0)
+		if every > 0 {
+			w = ts[i] - ts[i]%every
+		}
+
+		p, ok := m[w]
+		if !ok {
+			if typ == cursors.AggregateTypeCount {
+				m[w] = aggregatePoint{ts: w, v: 1}
+			} else {
+				m[w] = aggregatePoint{ts: ts[i], v: vs[i]}
+			}
+			continue
+		}
+
+		switch typ {
+		case cursors.AggregateTypeCount:
+			p.v++
+		case cursors.AggregateTypeSum:
+			p.v += vs[i]
+		case cursors.AggregateTypeFirst:
+			if ts[i] < p.ts {
+				p = aggregatePoint{ts: ts[i], v: vs[i]}
+			}
+		case cursors.AggregateTypeLast:
+			if ts[i] > p.ts {
+				p = aggregatePoint{ts: ts[i], v: vs[i]}
+			}
+		case cursors.AggregateTypeMin:
+			if vs[i] < p.v || vs[i] == p.v && ts[i] < p.ts {
+				p = aggregatePoint{ts: ts[i], v: vs[i]}
+			}
+		case cursors.AggregateTypeMax:
+			if vs[i] > p.v || vs[i] == p.v && ts[i] < p.ts {
+				p = aggregatePoint{ts: ts[i], v: vs[i]}
+			}
+		}
+		m[w] = p
+	}
+
+	// The time of sums and counts is not meaningful.
+	if typ == cursors.AggregateTypeCount || typ == cursors.AggregateTypeSum {
+		for w, p := range m {
+			m[w] = aggregatePoint{ts: w, v: p.v}
+		}
+	}
+	return m
+}
@@ -30,6 +30,14 @@ type arrayCursorIterator struct {
 		Boolean  *booleanArrayDescendingCursor
 		String   *stringArrayDescendingCursor
 	}
+
+	aggs struct {
+		Float    *floatArrayAggregateCursor
+		Integer  *integerArrayAggregateCursor
+		Unsigned *unsignedArrayAggregateCursor
+		Boolean  *booleanArrayAggregateCursor
+		String   *stringArrayAggregateCursor
+	}
 }
 
 func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (tsdb.Cursor, error) {
@@ -99,5 +107,16 @@ func (q *arrayCursorIterator) Stats() cursors.CursorStats {
 	} else if cur := q.desc.String; cur != nil {
 		stats.Add(cur.Stats())
 	}
+	if cur := q.aggs.Float; cur != nil {
+		stats.Add(cur.Stats())
+	} else if cur := q.aggs.Integer; cur != nil {
+		stats.Add(cur.Stats())
+	} else if cur := q.aggs.Unsigned; cur != nil {
+		stats.Add(cur.Stats())
+	} else if cur := q.aggs.Boolean; cur != nil {
+		stats.Add(cur.Stats())
+	} else if cur := q.aggs.String; cur != nil {
+		stats.Add(cur.Stats())
+	}
 	return stats
 }
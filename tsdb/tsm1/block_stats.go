@@ -0,0 +1,181 @@
+package tsm1
+
+/*
+TSM files may record statistics of the values of their float, integer and
+unsigned blocks, so that min, max and sum can be computed without decoding the
+blocks. The statistics of a block immediately follow the block, outside of the
+size recorded in its index entry, and a magic number that follows the last
+block tells that the blocks of the file are followed by their statistics. Files
+written before the statistics were introduced do not have the magic number, and
+readers that do not know about the statistics never read them.
+
+Blocks that are written as is, without being decoded, do not have statistics.
+Such a block is followed by an empty record, whose checksum never matches, when
+the blocks before it have statistics, and otherwise the file has none.
+
+┌───────────────────────────────────────────────────────────┐
+│                     Block Statistics                      │
+├─────────┬─────────┬─────────┬─────────┬─────────┬─────────┤
+│ Min At  │   Min   │ Max At  │   Max   │   Sum   │   CRC   │
+│ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes │ 4 bytes │
+└─────────┴─────────┴─────────┴─────────┴─────────┴─────────┘
+
+Min and Max are the first occurrences of the minimum and maximum values of the
+block, at timestamps Min At and Max At. Values are stored as the bits of a
+float64, an int64 or a uint64 depending on the type of the block.
+*/
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+)
+
+const (
+	// BlockStatsMagicNumber is written after the last block of a TSM file whose
+	// blocks are followed by their statistics.
+	BlockStatsMagicNumber uint32 = 0x16D1B57A
+
+	// Size in bytes of the statistics of a block
+	blockStatsSize = 44
+
+	// Size in bytes of the block statistics magic number
+	blockStatsMagicSize = 4
+)
+
+// BlockStats are the statistics of the values of a float, integer or unsigned
+// block. Min, Max and Sum hold the bits of the values, which are read with the
+// accessors of the type of the block.
+type BlockStats struct {
+	MinAt, MaxAt int64
+	Min, Max     uint64
+	Sum          uint64
+}
+
+// FloatMin returns the minimum value of a float block.
+func (s *BlockStats) FloatMin() float64 { return math.Float64frombits(s.Min) }
+
+// FloatMax returns the maximum value of a float block.
+func (s *BlockStats) FloatMax() float64 { return math.Float64frombits(s.Max) }
+
+// FloatSum returns the sum of the values of a float block.
+func (s *BlockStats) FloatSum() float64 { return math.Float64frombits(s.Sum) }
+
+// IntegerMin returns the minimum value of an integer block.
+func (s *BlockStats) IntegerMin() int64 { return int64(s.Min) }
+
+// IntegerMax returns the maximum value of an integer block.
+func (s *BlockStats) IntegerMax() int64 { return int64(s.Max) }
+
+// IntegerSum returns the sum of the values of an integer block.
+func (s *BlockStats) IntegerSum() int64 { return int64(s.Sum) }
+
+// UnsignedMin returns the minimum value of an unsigned block.
+func (s *BlockStats) UnsignedMin() uint64 { return s.Min }
+
+// UnsignedMax returns the maximum value of an unsigned block.
+func (s *BlockStats) UnsignedMax() uint64 { return s.Max }
+
+// UnsignedSum returns the sum of the values of an unsigned block.
+func (s *BlockStats) UnsignedSum() uint64 { return s.Sum }
+
+// hasBlockStats returns true if blocks of type typ are followed by their
+// statistics.
+func hasBlockStats(typ byte) bool {
+	return typ == BlockFloat64 || typ == BlockInteger || typ == BlockUnsigned
+}
+
+// NewValuesStats returns the statistics of the values of the block values are
+// encoded to, so that they are computed without decoding the block. It returns
+// false if statistics are not recorded for the type of the values.
+func NewValuesStats(values []Value) (BlockStats, bool) {
+	var s BlockStats
+	if len(values) == 0 {
+		return s, false
+	}
+
+	s.MinAt, s.MaxAt = values[0].UnixNano(), values[0].UnixNano()
+	switch values[0].(type) {
+	case FloatValue:
+		min, max, sum := values[0].(FloatValue).RawValue(), values[0].(FloatValue).RawValue(), float64(0)
+		for _, v := range values {
+			fv, ok := v.(FloatValue)
+			if !ok {
+				return s, false
+			}
+			if v := fv.RawValue(); v < min {
+				min, s.MinAt = v, fv.UnixNano()
+			}
+			if v := fv.RawValue(); v > max {
+				max, s.MaxAt = v, fv.UnixNano()
+			}
+			sum += fv.RawValue()
+		}
+		s.Min, s.Max, s.Sum = math.Float64bits(min), math.Float64bits(max), math.Float64bits(sum)
+	case IntegerValue:
+		min, max, sum := values[0].(IntegerValue).RawValue(), values[0].(IntegerValue).RawValue(), int64(0)
+		for _, v := range values {
+			iv, ok := v.(IntegerValue)
+			if !ok {
+				return s, false
+			}
+			if v := iv.RawValue(); v < min {
+				min, s.MinAt = v, iv.UnixNano()
+			}
+			if v := iv.RawValue(); v > max {
+				max, s.MaxAt = v, iv.UnixNano()
+			}
+			sum += iv.RawValue()
+		}
+		s.Min, s.Max, s.Sum = uint64(min), uint64(max), uint64(sum)
+	case UnsignedValue:
+		min, max, sum := values[0].(UnsignedValue).RawValue(), values[0].(UnsignedValue).RawValue(), uint64(0)
+		for _, v := range values {
+			uv, ok := v.(UnsignedValue)
+			if !ok {
+				return s, false
+			}
+			if v := uv.RawValue(); v < min {
+				min, s.MinAt = v, uv.UnixNano()
+			}
+			if v := uv.RawValue(); v > max {
+				max, s.MaxAt = v, uv.UnixNano()
+			}
+			sum += uv.RawValue()
+		}
+		s.Min, s.Max, s.Sum = min, max, sum
+	default:
+		return s, false
+	}
+	return s, true
+}
+
+// AppendTo appends the binary encoding of s to b.
+func (s *BlockStats) AppendTo(b []byte) []byte {
+	var buf [blockStatsSize]byte
+	binary.BigEndian.PutUint64(buf[0:8], uint64(s.MinAt))
+	binary.BigEndian.PutUint64(buf[8:16], s.Min)
+	binary.BigEndian.PutUint64(buf[16:24], uint64(s.MaxAt))
+	binary.BigEndian.PutUint64(buf[24:32], s.Max)
+	binary.BigEndian.PutUint64(buf[32:40], s.Sum)
+	binary.BigEndian.PutUint32(buf[40:44], crc32.ChecksumIEEE(buf[:40]))
+	return append(b, buf[:]...)
+}
+
+// unmarshalBlockStats decodes block statistics from b. It returns false if b
+// is too short or its checksum does not match.
+func unmarshalBlockStats(b []byte) (BlockStats, bool) {
+	var s BlockStats
+	if len(b) < blockStatsSize {
+		return s, false
+	}
+	if crc32.ChecksumIEEE(b[:40]) != binary.BigEndian.Uint32(b[40:44]) {
+		return s, false
+	}
+	s.MinAt = int64(binary.BigEndian.Uint64(b[0:8]))
+	s.Min = binary.BigEndian.Uint64(b[8:16])
+	s.MaxAt = int64(binary.BigEndian.Uint64(b[16:24]))
+	s.Max = binary.BigEndian.Uint64(b[24:32])
+	s.Sum = binary.BigEndian.Uint64(b[32:40])
+	return s, true
+}
@@ -1161,8 +1161,19 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return fmt.Errorf("invalid index entry for block. min=%d, max=%d", minTime, maxTime)
 		}
 
-		// Write the key and value
-		if err := w.WriteBlock(key, minTime, maxTime, block); err == ErrMaxBlocksExceeded {
+		// Write the key and value, along with the statistics of the block if
+		// the iterator knows them.
+		var stats BlockStats
+		var hasStats bool
+		if si, ok := iter.(blockStatsIterator); ok {
+			stats, hasStats = si.BlockStats()
+		}
+		if hasStats {
+			err = w.WriteBlockStats(key, minTime, maxTime, block, stats)
+		} else {
+			err = w.WriteBlock(key, minTime, maxTime, block)
+		}
+		if err == ErrMaxBlocksExceeded {
 			if err := w.WriteIndex(); err != nil {
 				return err
 			}
@@ -1240,6 +1251,12 @@ type KeyIterator interface {
 	EstimatedIndexSize() int
 }
 
+// blockStatsIterator is implemented by the key iterators that know the
+// statistics of the values of the blocks they read.
+type blockStatsIterator interface {
+	BlockStats() (BlockStats, bool)
+}
+
 // tsmKeyIterator implements the KeyIterator for set of TSMReaders.  Iteration produces
 // keys in sorted order and the values between the keys sorted and deduped.  If any of
 // the readers have associated tombstone entries, they are returned as part of iteration.
@@ -1878,6 +1895,10 @@ type cacheBlock struct {
 	minTime, maxTime int64
 	b                []byte
 	err              error
+
+	// stats are the statistics of the values of the block, if ok.
+	stats BlockStats
+	ok    bool
 }
 
 // NewCacheKeyIterator returns a new KeyIterator from a Cache.
@@ -1971,6 +1992,7 @@ func (c *cacheKeyIterator) encode() {
 						b, err = Values(values[:end]).Encode(nil)
 					}
 
+					stats, ok := NewValuesStats(values[:end])
 					values = values[end:]
 
 					c.blocks[i] = append(c.blocks[i], cacheBlock{
@@ -1979,6 +2001,8 @@ func (c *cacheKeyIterator) encode() {
 						maxTime: maxTime,
 						b:       b,
 						err:     err,
+						stats:   stats,
+						ok:      ok,
 					})
 
 					if err != nil {
@@ -2022,6 +2046,12 @@ func (c *cacheKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return blk.k, blk.minTime, blk.maxTime, blk.b, blk.err
 }
 
+// BlockStats returns the statistics of the values of the block last read.
+func (c *cacheKeyIterator) BlockStats() (BlockStats, bool) {
+	blk := c.blocks[c.i][0]
+	return blk.stats, blk.ok
+}
+
 func (c *cacheKeyIterator) Close() error {
 	return nil
 }
@@ -0,0 +1,104 @@
+package tsm1
+
+import (
+	"bytes"
+	"math"
+)
+
+// CopyOptions describes the subset of a TSM file written by Copy.
+type CopyOptions struct {
+	// Prefix limits the copy to keys that begin with Prefix. An empty Prefix
+	// copies every key.
+	Prefix []byte
+
+	// ReplacePrefix, if non-nil, replaces Prefix in every key that is written.
+	// Replacing a common prefix preserves the ordering of the copied keys.
+	ReplacePrefix []byte
+
+	// MinTime and MaxTime limit the copy to values in [MinTime, MaxTime].
+	MinTime, MaxTime int64
+}
+
+// NewCopyOptions returns CopyOptions that copy all data for keys beginning
+// with prefix.
+func NewCopyOptions(prefix []byte) CopyOptions {
+	return CopyOptions{
+		Prefix:  prefix,
+		MinTime: math.MinInt64,
+		MaxTime: math.MaxInt64,
+	}
+}
+
+// Copy writes the blocks of r selected by opts to w, excluding any data that has
+// been deleted by tombstones. Blocks that lie entirely within the time range and
+// contain no deleted data are copied without being decoded.
+//
+// Copy returns the number of blocks written. The caller is responsible for
+// calling WriteIndex on w if any blocks were written.
+func Copy(r *TSMReader, w TSMWriter, opts CopyOptions) (int, error) {
+	var (
+		n      int
+		outKey []byte
+		trbuf  []TimeRange
+		vals   []Value
+	)
+
+	iter := r.index.Iterator(opts.Prefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, opts.Prefix) {
+			break
+		}
+
+		outKey = key
+		if opts.ReplacePrefix != nil {
+			outKey = append(append(outKey[:0:0], opts.ReplacePrefix...), key[len(opts.Prefix):]...)
+		}
+
+		trbuf = r.TombstoneRange(key, trbuf[:0])
+		for _, entry := range iter.Entries() {
+			if entry.MaxTime < opts.MinTime || entry.MinTime > opts.MaxTime {
+				continue
+			}
+
+			if entry.MinTime >= opts.MinTime && entry.MaxTime <= opts.MaxTime && !overlapsTimeRanges(trbuf, entry.MinTime, entry.MaxTime) {
+				_, buf, err := r.ReadBytes(&entry, nil)
+				if err != nil {
+					return n, err
+				}
+				if err := w.WriteBlock(outKey, entry.MinTime, entry.MaxTime, buf); err != nil {
+					return n, err
+				}
+				n++
+				continue
+			}
+
+			// The block is only partially selected, so decode it and trim the values.
+			var err error
+			if vals, err = r.ReadAt(&entry, vals[:0]); err != nil {
+				return n, err
+			}
+			values := Values(vals).Include(opts.MinTime, opts.MaxTime)
+			for _, tr := range trbuf {
+				values = values.Exclude(tr.Min, tr.Max)
+			}
+			if len(values) == 0 {
+				continue
+			}
+			if err := w.Write(outKey, values); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, iter.Err()
+}
+
+func overlapsTimeRanges(trs []TimeRange, min, max int64) bool {
+	for _, tr := range trs {
+		if tr.Overlaps(min, max) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package tsm1
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	data := map[string][]Value{
+		"aaa,k=1": {NewValue(1, 1.0), NewValue(2, 2.0)},
+		"bbb,k=1": {NewValue(1, int64(1)), NewValue(2, int64(2)), NewValue(3, int64(3)), NewValue(4, int64(4))},
+		"bbb,k=2": {NewValue(1, "a"), NewValue(5, "b")},
+		"ccc,k=1": {NewValue(1, true)},
+	}
+
+	tests := []struct {
+		name    string
+		opts    CopyOptions
+		deletes []TimeRange
+		exp     map[string][]Value
+	}{
+		{
+			name: "all",
+			opts: NewCopyOptions(nil),
+			exp:  data,
+		},
+		{
+			name: "prefix",
+			opts: NewCopyOptions([]byte("bbb")),
+			exp: map[string][]Value{
+				"bbb,k=1": data["bbb,k=1"],
+				"bbb,k=2": data["bbb,k=2"],
+			},
+		},
+		{
+			name: "replace prefix",
+			opts: CopyOptions{
+				Prefix:        []byte("bbb"),
+				ReplacePrefix: []byte("zz"),
+				MinTime:       math.MinInt64,
+				MaxTime:       math.MaxInt64,
+			},
+			exp: map[string][]Value{
+				"zz,k=1": data["bbb,k=1"],
+				"zz,k=2": data["bbb,k=2"],
+			},
+		},
+		{
+			name: "time range",
+			opts: CopyOptions{Prefix: []byte("bbb"), MinTime: 2, MaxTime: 4},
+			exp: map[string][]Value{
+				"bbb,k=1": {NewValue(2, int64(2)), NewValue(3, int64(3)), NewValue(4, int64(4))},
+			},
+		},
+		{
+			name:    "tombstones",
+			opts:    NewCopyOptions([]byte("bbb")),
+			deletes: []TimeRange{{Min: 2, Max: 3}},
+			exp: map[string][]Value{
+				"bbb,k=1": {NewValue(1, int64(1)), NewValue(4, int64(4))},
+				"bbb,k=2": data["bbb,k=2"],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := mustTempDir()
+			defer os.RemoveAll(dir)
+
+			r := mustOpenCopyTSM(t, mustWriteCopyTSM(t, dir, data))
+			defer r.Close()
+			for _, tr := range tt.deletes {
+				if err := r.DeleteRange([][]byte{[]byte("bbb,k=1")}, tr.Min, tr.Max); err != nil {
+					t.Fatalf("unexpected error deleting: %v", err)
+				}
+			}
+
+			f := mustTempFile(dir)
+			w, err := NewTSMWriter(f)
+			if err != nil {
+				t.Fatalf("unexpected error creating writer: %v", err)
+			}
+			n, err := Copy(r, w, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error copying: %v", err)
+			}
+			if n > 0 {
+				fatalIfErr(t, "writing index", w.WriteIndex())
+			}
+			fatalIfErr(t, "closing writer", w.Close())
+
+			if n == 0 {
+				if len(tt.exp) != 0 {
+					t.Fatalf("no blocks copied, exp %d keys", len(tt.exp))
+				}
+				return
+			}
+
+			out := mustOpenCopyTSM(t, f.Name())
+			defer out.Close()
+
+			got := make(map[string][]Value)
+			for iter := out.Iterator(nil); iter.Next(); {
+				for _, entry := range iter.Entries() {
+					vals, err := out.ReadAt(&entry, nil)
+					if err != nil {
+						t.Fatalf("unexpected error reading: %v", err)
+					}
+					got[string(iter.Key())] = append(got[string(iter.Key())], vals...)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("copied data mismatch: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func mustWriteCopyTSM(t *testing.T, dir string, data map[string][]Value) string {
+	t.Helper()
+
+	f := mustTempFile(dir)
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	for _, key := range []string{"aaa,k=1", "bbb,k=1", "bbb,k=2", "ccc,k=1"} {
+		if err := w.Write([]byte(key), data[key]); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	fatalIfErr(t, "writing index", w.WriteIndex())
+	fatalIfErr(t, "closing writer", w.Close())
+	return f.Name()
+}
+
+func mustOpenCopyTSM(t *testing.T, path string) *TSMReader {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error opening: %v", err)
+	}
+	r, err := NewTSMReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	return r
+}
@@ -0,0 +1,38 @@
+package tsm1
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/fs"
+)
+
+// CreateBackup snapshots the cache to TSM files and then hard links every TSM
+// and tombstone file into a new temporary directory, returning its path.
+//
+// The directory is not removed by the engine; the caller owns it once
+// CreateBackup returns.
+func (e *Engine) CreateBackup(ctx context.Context) (string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := e.WriteSnapshot(ctx, CacheStatusBackup); err != nil {
+		return "", err
+	}
+	return e.FileStore.CreateSnapshot(ctx)
+}
+
+// ImportFile moves the TSM file at path into the engine as a new generation and
+// makes its data available to queries. Any series in the file must already have
+// been added to the index by the caller.
+//
+// path must be on the same filesystem as the engine.
+func (e *Engine) ImportFile(path string) error {
+	generation := e.FileStore.NextGeneration()
+	name := filepath.Join(e.path, e.formatFileName(generation, 1)+"."+TSMFileExtension+"."+TmpTSMFileExtension)
+	if err := fs.RenameFile(path, name); err != nil {
+		return err
+	}
+	return e.FileStore.Replace(nil, []string{name})
+}
@@ -66,6 +66,14 @@ type TSMFile interface {
 	ReadBooleanBlockAt(entry *IndexEntry, values *[]BooleanValue) ([]BooleanValue, error)
 	ReadBooleanArrayBlockAt(entry *IndexEntry, values *tsdb.BooleanArray) error
 
+	// ReadBytes returns the checksum and the encoded bytes of the block of
+	// entry.
+	ReadBytes(entry *IndexEntry, buf []byte) (uint32, []byte, error)
+
+	// BlockStats returns the statistics of the values of the block of entry,
+	// if the file records them.
+	BlockStats(entry *IndexEntry) (BlockStats, bool)
+
 	// Entries returns the index entries for all blocks for the given key.
 	ReadEntries(key []byte, entries []IndexEntry) ([]IndexEntry, error)
 
@@ -606,7 +614,7 @@ func (f *FileStore) Open(ctx context.Context) error {
 		if fi.IsDir() && strings.HasSuffix(fi.Name(), ext) {
 			ss := strings.Split(filepath.Base(fi.Name()), ".")
 			if len(ss) == 2 {
-				if i, err := strconv.Atoi(ss[0]); err != nil {
+				if i, err := strconv.Atoi(ss[0]); err == nil {
 					if i > f.currentTempDirID {
 						f.currentTempDirID = i
 					}
@@ -1317,6 +1325,10 @@ type KeyCursor struct {
 	current []*location
 	buf     []Value
 
+	// overlaps records whether each block of seeks overlaps another block.
+	// It is computed on first use by the aggregate cursors.
+	overlaps []bool
+
 	ctx context.Context
 	col *metrics.Group
 
@@ -1407,6 +1419,7 @@ func (c *KeyCursor) Close() {
 	c.buf = nil
 	c.seeks = nil
 	c.current = nil
+	c.overlaps = nil
 }
 
 // seek positions the cursor at the given time.
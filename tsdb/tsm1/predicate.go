@@ -75,7 +75,10 @@ func NewProtobufPredicate(pred *datatypes.Predicate) (Predicate, error) {
 		if node.GetNodeType() == datatypes.NodeTypeTagRef {
 			switch value := node.GetValue().(type) {
 			case *datatypes.Node_TagRefValue:
-				locs[value.TagRefValue] = len(locs)
+				// Tags referenced more than once share a single location.
+				if _, ok := locs[value.TagRefValue]; !ok {
+					locs[value.TagRefValue] = len(locs)
+				}
 			}
 		}
 	})
@@ -65,6 +65,18 @@ func TestPredicate_Matches(t *testing.T) {
 			Matches: true,
 		},
 
+		{
+			Name: "Repeated Tag Unmatching",
+			Predicate: predicate(
+				andNode(
+					orNode(
+						comparisonNode(datatypes.ComparisonEqual, tagNode("foo"), stringNode("bar")),
+						comparisonNode(datatypes.ComparisonEqual, tagNode("foo"), stringNode("baz"))),
+					comparisonNode(datatypes.ComparisonNotEqual, tagNode("tag3"), stringNode("val3")))),
+			Key:     "bucketorg,foo=bar,tag3=val3",
+			Matches: false,
+		},
+
 		{
 			Name: "Logical And Short Circuit",
 			Predicate: predicate(
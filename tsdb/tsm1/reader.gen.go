@@ -108,6 +108,7 @@ type blockAccessor interface {
 	readBooleanBlock(entry *IndexEntry, values *[]BooleanValue) ([]BooleanValue, error)
 	readBooleanArrayBlock(entry *IndexEntry, values *tsdb.BooleanArray) error
 	readBytes(entry *IndexEntry, buf []byte) (uint32, []byte, error)
+	readBlockStats(entry *IndexEntry) (BlockStats, bool)
 	rename(path string) error
 	path() string
 	close() error
@@ -195,6 +195,12 @@ func (t *TSMReader) ReadBytes(e *IndexEntry, b []byte) (uint32, []byte, error) {
 	return n, v, err
 }
 
+// BlockStats returns the statistics of the values of the block of entry. It
+// returns false if the file does not record statistics for the block.
+func (t *TSMReader) BlockStats(entry *IndexEntry) (BlockStats, bool) {
+	return t.accessor.readBlockStats(entry)
+}
+
 // Type returns the type of values stored at the given key.
 func (t *TSMReader) Type(key []byte) (byte, error) {
 	return t.index.Type(key)
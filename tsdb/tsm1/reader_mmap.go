@@ -25,6 +25,10 @@ type mmapAccessor struct {
 	f     *os.File
 	_path string // If the underlying file is renamed then this gets updated
 
+	// statsEnd is the offset of the block statistics magic number, or zero
+	// if the blocks of the file are not followed by their statistics.
+	statsEnd int64
+
 	index *indirectIndex
 }
 
@@ -73,6 +77,13 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 		return nil, fmt.Errorf("mmapAccessor: invalid indexStart")
 	}
 
+	if indexStart >= 5+blockStatsMagicSize {
+		magic := m.b[indexStart-blockStatsMagicSize : indexStart]
+		if binary.BigEndian.Uint32(magic) == BlockStatsMagicNumber {
+			m.statsEnd = int64(indexStart) - blockStatsMagicSize
+		}
+	}
+
 	m.index = NewIndirectIndex()
 	if err := m.index.UnmarshalBinary(m.b[indexStart:indexOfsPos]); err != nil {
 		return nil, err
@@ -174,6 +185,28 @@ func (m *mmapAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, e
 	return crc, block, nil
 }
 
+// readBlockStats returns the statistics that follow the block of entry, if
+// the file has them.
+func (m *mmapAccessor) readBlockStats(entry *IndexEntry) (BlockStats, bool) {
+	if m.statsEnd == 0 {
+		return BlockStats{}, false
+	}
+
+	m.incAccess()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pos := entry.Offset + int64(entry.Size)
+	if pos+blockStatsSize > m.statsEnd || int64(len(m.b)) < m.statsEnd {
+		return BlockStats{}, false
+	}
+	if entry.Size <= 4 || !hasBlockStats(m.b[entry.Offset+4]) {
+		return BlockStats{}, false
+	}
+	return unmarshalBlockStats(m.b[pos : pos+blockStatsSize])
+}
+
 // readAll returns all values for a key in all blocks.
 func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 	m.incAccess()
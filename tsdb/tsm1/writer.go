@@ -31,6 +31,9 @@ is stored in the index.
 │ 4 bytes │ N bytes │ 4 bytes │ N bytes │ 4 bytes │ N bytes │
 └─────────┴─────────┴─────────┴─────────┴─────────┴─────────┘
 
+Float, integer and unsigned blocks may be followed by statistics of their
+values that are not included in the size of the block, see block_stats.go.
+
 Following the blocks is the index for the blocks in the file.  The index is
 composed of a sequence of index entries ordered lexicographically by key and
 then by time.  Each index entry starts with a key length and key followed by a
@@ -138,6 +141,10 @@ type TSMWriter interface {
 	// timestamp values are used as the minimum and maximum values for the index entry.
 	WriteBlock(key []byte, minTime, maxTime int64, block []byte) error
 
+	// WriteBlockStats writes a new block like WriteBlock, followed by stats, the
+	// statistics of the values of the block, so that they are not decoded from it.
+	WriteBlockStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error
+
 	// WriteIndex finishes the TSM write streams and writes the index.
 	WriteIndex() error
 
@@ -593,6 +600,13 @@ type tsmWriter struct {
 	lastSync int64
 
 	stats MeasurementStats
+
+	// blockStats is true once the statistics of a block have been written.
+	blockStats bool
+	// noBlockStats is true once a block has been written without statistics
+	// before any were written, after which the file has none.
+	noBlockStats bool
+	buf          []byte
 }
 
 // NewTSMWriter returns a new TSMWriter writing to w.
@@ -698,6 +712,11 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	// Increment file position pointer
 	t.n += int64(n)
 
+	stats, ok := NewValuesStats(values)
+	if err := t.writeBlockStats(blockType, stats, ok); err != nil {
+		return err
+	}
+
 	if len(t.index.Entries(key)) >= maxIndexEntries {
 		return ErrMaxBlocksExceeded
 	}
@@ -709,6 +728,16 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 // exceeds max entries for a given key, ErrMaxBlocksExceeded is returned.  This indicates
 // that the index is now full for this key and no future writes to this key will succeed.
 func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte) error {
+	return t.writeBlock(key, minTime, maxTime, block, BlockStats{}, false)
+}
+
+// WriteBlockStats writes block like WriteBlock, followed by stats, the statistics
+// of its values.
+func (t *tsmWriter) WriteBlockStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error {
+	return t.writeBlock(key, minTime, maxTime, block, stats, true)
+}
+
+func (t *tsmWriter) writeBlock(key []byte, minTime, maxTime int64, block []byte, stats BlockStats, ok bool) error {
 	if len(key) > maxKeyLength {
 		return ErrMaxKeyLengthExceeded
 	}
@@ -754,6 +783,10 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	// Increment file position pointer (checksum + block len)
 	t.n += int64(n)
 
+	if err := t.writeBlockStats(blockType, stats, ok); err != nil {
+		return err
+	}
+
 	// fsync the file periodically to avoid long pauses with very big files.
 	if t.n-t.lastSync > fsyncEvery {
 		if err := t.sync(); err != nil {
@@ -772,12 +805,22 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 // WriteIndex writes the index section of the file.  If there are no index entries to write,
 // this returns ErrNoValues.
 func (t *tsmWriter) WriteIndex() error {
-	indexPos := t.n
-
 	if t.index.KeyCount() == 0 {
 		return ErrNoValues
 	}
 
+	// Tell readers that the blocks are followed by their statistics.
+	if t.blockStats {
+		var buf [blockStatsMagicSize]byte
+		binary.BigEndian.PutUint32(buf[:], BlockStatsMagicNumber)
+		if _, err := t.w.Write(buf[:]); err != nil {
+			return err
+		}
+		t.n += blockStatsMagicSize
+	}
+
+	indexPos := t.n
+
 	// Set the destination file on the index so we can periodically
 	// fsync while writing the index.
 	if f, ok := t.wrapped.(syncer); ok {
@@ -797,6 +840,42 @@ func (t *tsmWriter) WriteIndex() error {
 	return err
 }
 
+// writeBlockStats writes stats after the block of type typ, or writes the
+// block without statistics if ok is false. Statistics are not computed from
+// blocks written as is, such as those of compactions, as that would require
+// decoding them, so readers decode the blocks without statistics instead.
+func (t *tsmWriter) writeBlockStats(typ byte, stats BlockStats, ok bool) error {
+	if !hasBlockStats(typ) {
+		return nil
+	}
+
+	if !ok || t.noBlockStats {
+		if !t.blockStats {
+			t.noBlockStats = true
+			return nil
+		}
+
+		// The blocks before are followed by their statistics, so the block
+		// is followed by an empty record whose checksum never matches.
+		var buf [blockStatsSize]byte
+		n, err := t.w.Write(buf[:])
+		if err != nil {
+			return err
+		}
+		t.n += int64(n)
+		return nil
+	}
+
+	t.buf = stats.AppendTo(t.buf[:0])
+	n, err := t.w.Write(t.buf)
+	if err != nil {
+		return err
+	}
+	t.n += int64(n)
+	t.blockStats = true
+	return nil
+}
+
 func (t *tsmWriter) Flush() error {
 	if err := t.w.Flush(); err != nil {
 		return err
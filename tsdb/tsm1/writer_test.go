@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"testing"
 
@@ -645,3 +646,180 @@ func TestTSMWriter_Sync(t *testing.T) {
 		t.Fatal("failed to sync")
 	}
 }
+
+func TestTSMWriter_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+
+	data := []struct {
+		key    string
+		values []tsm1.Value
+		exp    tsm1.BlockStats
+		ok     bool
+	}{
+		{
+			key:    "float",
+			values: []tsm1.Value{tsm1.NewValue(1, 2.5), tsm1.NewValue(2, -1.5), tsm1.NewValue(3, 4.0), tsm1.NewValue(4, -1.5)},
+			exp:    tsm1.BlockStats{MinAt: 2, Min: math.Float64bits(-1.5), MaxAt: 3, Max: math.Float64bits(4.0), Sum: math.Float64bits(3.5)},
+			ok:     true,
+		},
+		{
+			key:    "integer",
+			values: []tsm1.Value{tsm1.NewValue(1, int64(7)), tsm1.NewValue(2, int64(-3)), tsm1.NewValue(3, int64(7))},
+			exp:    tsm1.BlockStats{MinAt: 2, Min: uint64(0xfffffffffffffffd), MaxAt: 1, Max: 7, Sum: 11},
+			ok:     true,
+		},
+		{
+			key:    "string",
+			values: []tsm1.Value{tsm1.NewValue(1, "a"), tsm1.NewValue(2, "b")},
+		},
+		{
+			key:    "unsigned",
+			values: []tsm1.Value{tsm1.NewValue(1, uint64(5)), tsm1.NewValue(2, uint64(9)), tsm1.NewValue(3, uint64(1))},
+			exp:    tsm1.BlockStats{MinAt: 3, Min: 1, MaxAt: 2, Max: 9, Sum: 15},
+			ok:     true,
+		},
+	}
+
+	for _, d := range data {
+		if err := w.Write([]byte(d.key), d.values); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	fd, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+
+	r, err := tsm1.NewTSMReader(fd)
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r.Close()
+
+	for _, d := range data {
+		entries, err := r.ReadEntries([]byte(d.key), nil)
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("unexpected entries for %s: %v, %v", d.key, entries, err)
+		}
+
+		got, ok := r.BlockStats(&entries[0])
+		if ok != d.ok {
+			t.Fatalf("block stats mismatch for %s: got %v, exp %v", d.key, ok, d.ok)
+		}
+		if ok && got != d.exp {
+			t.Fatalf("block stats mismatch for %s: got %+v, exp %+v", d.key, got, d.exp)
+		}
+
+		readValues, err := r.ReadAll([]byte(d.key))
+		if err != nil {
+			t.Fatalf("unexpected error reading: %v", err)
+		}
+		if len(readValues) != len(d.values) {
+			t.Fatalf("read values length mismatch for %s: got %v, exp %v", d.key, len(readValues), len(d.values))
+		}
+		for i, v := range d.values {
+			if v.Value() != readValues[i].Value() {
+				t.Fatalf("read value mismatch for %s (%d): got %v, exp %v", d.key, i, readValues[i].Value(), v.Value())
+			}
+		}
+	}
+}
+
+func TestTSMWriter_WriteBlock_BlockStats(t *testing.T) {
+	block, err := tsm1.Values([]tsm1.Value{tsm1.NewValue(1, 1.5), tsm1.NewValue(2, 2.5)}).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	values := []tsm1.Value{tsm1.NewValue(1, int64(3)), tsm1.NewValue(2, int64(4))}
+
+	tests := []struct {
+		name  string
+		write func(w tsm1.TSMWriter) error
+		stats map[string]bool
+	}{
+		{
+			name: "block after values",
+			write: func(w tsm1.TSMWriter) error {
+				if err := w.Write([]byte("a"), values); err != nil {
+					return err
+				}
+				if err := w.WriteBlock([]byte("b"), 1, 2, block); err != nil {
+					return err
+				}
+				return w.Write([]byte("c"), values)
+			},
+			stats: map[string]bool{"a": true, "b": false, "c": true},
+		},
+		{
+			name: "values after block",
+			write: func(w tsm1.TSMWriter) error {
+				if err := w.WriteBlock([]byte("a"), 1, 2, block); err != nil {
+					return err
+				}
+				return w.Write([]byte("b"), values)
+			},
+			stats: map[string]bool{"a": false, "b": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+			f := MustTempFile(dir)
+
+			w, err := tsm1.NewTSMWriter(f)
+			if err != nil {
+				t.Fatalf("unexpected error creating writer: %v", err)
+			}
+			if err := tt.write(w); err != nil {
+				t.Fatalf("unexpected error writing: %v", err)
+			}
+			if err := w.WriteIndex(); err != nil {
+				t.Fatalf("unexpected error writing index: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error closing: %v", err)
+			}
+
+			fd, err := os.Open(f.Name())
+			if err != nil {
+				t.Fatalf("unexpected error open file: %v", err)
+			}
+			r, err := tsm1.NewTSMReader(fd)
+			if err != nil {
+				t.Fatalf("unexpected error created reader: %v", err)
+			}
+			defer r.Close()
+
+			for key, exp := range tt.stats {
+				entries, err := r.ReadEntries([]byte(key), nil)
+				if err != nil || len(entries) != 1 {
+					t.Fatalf("unexpected entries for %s: %v, %v", key, entries, err)
+				}
+				if _, ok := r.BlockStats(&entries[0]); ok != exp {
+					t.Fatalf("block stats mismatch for %s: got %v, exp %v", key, ok, exp)
+				}
+				if readValues, err := r.ReadAll([]byte(key)); err != nil || len(readValues) != 2 {
+					t.Fatalf("unexpected values for %s: %v, %v", key, readValues, err)
+				}
+			}
+		})
+	}
+}
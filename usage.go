@@ -23,6 +23,22 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+
+	// UsageWriteRequestsThrottled is the name of the metrics for tracking the number of writes rejected by a rate limit.
+	UsageWriteRequestsThrottled UsageMetric = "usage_write_requests_throttled"
+	// UsageQueryRequestsThrottled is the name of the metrics for tracking the number of queries rejected by a quota.
+	UsageQueryRequestsThrottled UsageMetric = "usage_query_requests_throttled"
+	// UsageQueryConcurrency is the name of the metrics for tracking the number of running queries.
+	UsageQueryConcurrency UsageMetric = "usage_query_concurrency"
+
+	// UsageLimitWriteBytes is the name of the metrics for the limit of write bytes per second.
+	UsageLimitWriteBytes UsageMetric = "usage_limit_write_bytes_per_second"
+	// UsageLimitWritePoints is the name of the metrics for the limit of written points per second.
+	UsageLimitWritePoints UsageMetric = "usage_limit_write_points_per_second"
+	// UsageLimitQueryConcurrency is the name of the metrics for the limit of concurrent queries.
+	UsageLimitQueryConcurrency UsageMetric = "usage_limit_query_concurrency"
+	// UsageLimitQueryMemoryBytes is the name of the metrics for the limit of the memory of a query.
+	UsageLimitQueryMemoryBytes UsageMetric = "usage_limit_query_memory_bytes"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.
@@ -38,6 +54,25 @@ type UsageService interface {
 	GetUsage(ctx context.Context, filter UsageFilter) (map[UsageMetric]*Usage, error)
 }
 
+// MultiUsageService merges the usage statistics of several usage services.
+type MultiUsageService []UsageService
+
+// GetUsage returns the usage of all the services, a metric of a later service
+// replaces the same metric of an earlier one.
+func (s MultiUsageService) GetUsage(ctx context.Context, filter UsageFilter) (map[UsageMetric]*Usage, error) {
+	usage := make(map[UsageMetric]*Usage)
+	for _, svc := range s {
+		u, err := svc.GetUsage(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for m, v := range u {
+			usage[m] = v
+		}
+	}
+	return usage, nil
+}
+
 // UsageFilter is used to filter usage.
 type UsageFilter struct {
 	OrgID    *ID
@@ -107,6 +107,9 @@ type UserResourceMapping struct {
 	MappingType  MappingType  `json:"mappingType"`
 	ResourceType ResourceType `json:"resourceType"`
 	ResourceID   ID           `json:"resourceID"`
+
+	// RoleIDs are the roles granted to a member or an owner of an organization.
+	RoleIDs []ID `json:"roleIDs,omitempty"`
 }
 
 // Validate reports any validation errors for the mapping.
@@ -0,0 +1,273 @@
+package write
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Column data types of the #datatype annotation of annotated CSV.
+const (
+	csvMeasurement     = "measurement"
+	csvTag             = "tag"
+	csvDateTime        = "dateTime"
+	csvDateTimeRFC3339 = "dateTime:RFC3339"
+	csvDateTimeNumber  = "dateTime:number"
+	csvField           = "field"
+	csvDouble          = "double"
+	csvLong            = "long"
+	csvUnsignedLong    = "unsignedLong"
+	csvBoolean         = "boolean"
+	csvString          = "string"
+	csvIgnored         = "ignored"
+)
+
+// csvTable is the annotations and header of the rows that follow them.
+type csvTable struct {
+	types    []string
+	defaults []string
+	header   []string
+}
+
+// ParseCSV converts annotated CSV to points. The annotations are similar to
+// the ones of Flux CSV: a #datatype annotation gives the type of every column,
+// it is followed by a header row with the column names and then data rows.
+//
+//	#datatype measurement,tag,double,dateTime:RFC3339
+//	m,host,usage,time
+//	cpu,server01,2.5,2019-08-01T00:00:00Z
+//
+// Every table has exactly one measurement column, and at most one dateTime
+// column; rows without a timestamp are written at defaultTime. Columns of
+// type field are floats, booleans or strings depending on their value,
+// whereas double, long, unsignedLong, boolean and string columns are fields
+// of that type. Empty tags and fields are omitted. An optional #default
+// annotation gives the value of empty cells. A new #datatype annotation
+// starts a new table. Quoted values cannot span lines.
+func ParseCSV(r io.Reader, defaultTime time.Time, precision string) (models.Points, error) {
+	var (
+		points models.Points
+		table  *csvTable
+		row    int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		row++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok {
+				return nil, &ParseError{Row: row, Column: pe.Column, Msg: pe.Err.Error()}
+			}
+			return nil, &ParseError{Row: row, Column: 1, Msg: err.Error()}
+		}
+
+		if strings.HasPrefix(record[0], "#") {
+			table, err = parseCSVAnnotation(table, record, row)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if table == nil {
+			return nil, &ParseError{Row: row, Column: 1, Msg: "missing #datatype annotation"}
+		}
+
+		if len(record) != len(table.types) {
+			return nil, &ParseError{
+				Row:    row,
+				Column: 1,
+				Msg:    fmt.Sprintf("row has %d columns, but #datatype has %d", len(record), len(table.types)),
+			}
+		}
+
+		if table.header == nil {
+			for i, name := range record {
+				if name == "" && table.types[i] != csvIgnored && table.types[i] != csvMeasurement && !isCSVDateTime(table.types[i]) {
+					return nil, &ParseError{Row: row, Column: i + 1, Msg: "missing column name"}
+				}
+			}
+			table.header = record
+			continue
+		}
+
+		p, err := table.point(record, row, defaultTime, precision)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// parseCSVAnnotation applies an annotation row to the current table and
+// returns the resulting table. The first cell holds both the annotation name
+// and the value of the first column, separated by white space.
+func parseCSVAnnotation(table *csvTable, record []string, row int) (*csvTable, error) {
+	cells := make([]string, len(record))
+	copy(cells, record)
+
+	name := cells[0]
+	cells[0] = ""
+	if i := strings.IndexAny(name, " \t"); i >= 0 {
+		name, cells[0] = name[:i], strings.TrimSpace(name[i+1:])
+	}
+
+	switch name {
+	case "#datatype":
+		measurements, times := 0, 0
+		for i, typ := range cells {
+			switch {
+			case typ == csvMeasurement:
+				measurements++
+			case isCSVDateTime(typ):
+				times++
+			case typ == csvTag, typ == csvField, typ == csvDouble, typ == csvLong, typ == csvUnsignedLong,
+				typ == csvBoolean, typ == csvString, typ == csvIgnored:
+			default:
+				return nil, &ParseError{Row: row, Column: i + 1, Msg: fmt.Sprintf("unknown data type %q", typ)}
+			}
+		}
+		if measurements != 1 {
+			return nil, &ParseError{Row: row, Column: 1, Msg: "#datatype must have exactly one measurement column"}
+		}
+		if times > 1 {
+			return nil, &ParseError{Row: row, Column: 1, Msg: "#datatype must have at most one dateTime column"}
+		}
+		return &csvTable{types: cells}, nil
+	case "#default":
+		if table == nil {
+			return nil, &ParseError{Row: row, Column: 1, Msg: "#default must follow a #datatype annotation"}
+		}
+		if len(cells) != len(table.types) {
+			return nil, &ParseError{
+				Row:    row,
+				Column: 1,
+				Msg:    fmt.Sprintf("#default has %d columns, but #datatype has %d", len(cells), len(table.types)),
+			}
+		}
+		table.defaults = cells
+		return table, nil
+	default:
+		// other annotations, such as #group, do not apply to writes.
+		return table, nil
+	}
+}
+
+func isCSVDateTime(typ string) bool {
+	return typ == csvDateTime || typ == csvDateTimeRFC3339 || typ == csvDateTimeNumber
+}
+
+func (t *csvTable) point(record []string, row int, defaultTime time.Time, precision string) (models.Point, error) {
+	var (
+		measurement string
+		ts          = defaultTime
+		tags        = make(map[string]string)
+		fields      = make(models.Fields)
+	)
+
+	for i, value := range record {
+		if value == "" && t.defaults != nil {
+			value = t.defaults[i]
+		}
+		name := t.header[i]
+
+		var err error
+		switch typ := t.types[i]; typ {
+		case csvIgnored:
+		case csvMeasurement:
+			if value == "" {
+				return nil, &ParseError{Row: row, Column: i + 1, Msg: "missing measurement"}
+			}
+			measurement = value
+		case csvTag:
+			if value != "" {
+				tags[name] = value
+			}
+		case csvDateTime, csvDateTimeRFC3339, csvDateTimeNumber:
+			if value == "" {
+				continue
+			}
+			switch typ {
+			case csvDateTimeRFC3339:
+				if ts, err = time.Parse(time.RFC3339Nano, value); err != nil {
+					err = fmt.Errorf("invalid %s value %q", typ, value)
+				}
+			case csvDateTimeNumber:
+				n, perr := strconv.ParseInt(value, 10, 64)
+				if perr != nil {
+					err = fmt.Errorf("invalid %s value %q", typ, value)
+				}
+				ts = time.Unix(0, n*models.GetPrecisionMultiplier(precision)).UTC()
+			default:
+				ts, err = parseTime(value, precision)
+			}
+		default:
+			if value == "" {
+				continue
+			}
+			fields[name], err = parseCSVField(typ, value)
+		}
+		if err != nil {
+			return nil, &ParseError{Row: row, Column: i + 1, Msg: err.Error()}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, &ParseError{Row: row, Column: 1, Msg: "row has no fields"}
+	}
+
+	p, err := models.NewPoint(measurement, models.NewTags(tags), fields, ts)
+	if err != nil {
+		return nil, &ParseError{Row: row, Column: 1, Msg: err.Error()}
+	}
+	return p, nil
+}
+
+func parseCSVField(typ, value string) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch typ {
+	case csvDouble:
+		v, err = strconv.ParseFloat(value, 64)
+	case csvLong:
+		v, err = strconv.ParseInt(value, 10, 64)
+	case csvUnsignedLong:
+		v, err = strconv.ParseUint(value, 10, 64)
+	case csvBoolean:
+		v, err = strconv.ParseBool(value)
+	case csvString:
+		v = value
+	default:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f, nil
+		}
+		if value == "true" || value == "false" {
+			return value == "true", nil
+		}
+		v = value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q", typ, value)
+	}
+	return v, nil
+}
@@ -0,0 +1,153 @@
+package write
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/models"
+)
+
+func pointStrings(points models.Points) []string {
+	var ss []string
+	for _, p := range points {
+		ss = append(ss, p.String())
+	}
+	return ss
+}
+
+func TestParseCSV(t *testing.T) {
+	now := time.Unix(0, 42)
+
+	tests := []struct {
+		name      string
+		input     string
+		precision string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name: "typed columns",
+			input: "#datatype measurement,tag,double,long,unsignedLong,boolean,string,dateTime:RFC3339\n" +
+				"m,host,f,i,u,b,s,time\n" +
+				"cpu,a,1.5,2,3,true,hello,1970-01-01T00:00:01Z\n",
+			want: []string{`cpu,host=a b=true,f=1.5,i=2i,s="hello",u=3u 1000000000`},
+		},
+		{
+			name: "inferred fields and numeric timestamps",
+			input: "#datatype measurement,tag,field,field,field,dateTime\n" +
+				"m,host,f,b,s,time\n" +
+				"cpu,a,1,false,up,2\n",
+			precision: "s",
+			want:      []string{`cpu,host=a b=false,f=1,s="up" 2000000000`},
+		},
+		{
+			name: "empty values and defaults",
+			input: "#datatype measurement,tag,tag,double,double\n" +
+				"#default cpu,,x,,\n" +
+				"m,host,region,f,g\n" +
+				",,,1,\n" +
+				"mem,a,,,2\n",
+			want: []string{
+				"cpu,region=x f=1 42",
+				"mem,host=a,region=x g=2 42",
+			},
+		},
+		{
+			name: "multiple tables and ignored columns",
+			input: "#datatype measurement,ignored,double\n" +
+				"m,x,f\n" +
+				"cpu,foo,1\n" +
+				"\n" +
+				"#group false,false,false\n" +
+				"#datatype measurement,long\n" +
+				"m,n\n" +
+				"mem,2\n",
+			want: []string{
+				"cpu f=1 42",
+				"mem n=2i 42",
+			},
+		},
+		{
+			name:    "missing datatype",
+			input:   "m,f\ncpu,1\n",
+			wantErr: "row 1, column 1: missing #datatype annotation",
+		},
+		{
+			name:    "unknown data type",
+			input:   "#datatype measurement,tag,float\n",
+			wantErr: `row 1, column 3: unknown data type "float"`,
+		},
+		{
+			name:    "no measurement column",
+			input:   "#datatype tag,double\n",
+			wantErr: "row 1, column 1: #datatype must have exactly one measurement column",
+		},
+		{
+			name:    "wrong number of columns",
+			input:   "#datatype measurement,double\nm,f\ncpu,1,2\n",
+			wantErr: "row 3, column 1: row has 3 columns, but #datatype has 2",
+		},
+		{
+			name:    "invalid field",
+			input:   "#datatype measurement,tag,long\nm,t,f\ncpu,a,1\ncpu,b,1.5\n",
+			wantErr: `row 4, column 3: invalid long value "1.5"`,
+		},
+		{
+			name:    "invalid timestamp",
+			input:   "#datatype measurement,double,dateTime:RFC3339\nm,f,time\ncpu,1,yesterday\n",
+			wantErr: `row 3, column 3: invalid dateTime:RFC3339 value "yesterday"`,
+		},
+		{
+			name:    "no fields",
+			input:   "#datatype measurement,double\nm,f\ncpu,\n",
+			wantErr: "row 3, column 1: row has no fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precision := tt.precision
+			if precision == "" {
+				precision = "ns"
+			}
+
+			points, err := ParseCSV(strings.NewReader(tt.input), now, precision)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseCSV() error = %v, want %s", err, tt.wantErr)
+				}
+				if _, ok := err.(*ParseError); !ok {
+					t.Errorf("ParseCSV() error type = %T, want *ParseError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCSV() unexpected error: %v", err)
+			}
+
+			if got := pointStrings(points); !cmp.Equal(got, tt.want) {
+				t.Errorf("ParseCSV() -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestFormatFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Format
+	}{
+		{contentType: "", want: FormatLineProtocol},
+		{contentType: "text/plain; charset=utf-8", want: FormatLineProtocol},
+		{contentType: "application/x-www-form-urlencoded", want: FormatLineProtocol},
+		{contentType: "text/csv", want: FormatCSV},
+		{contentType: "text/csv; charset=utf-8", want: FormatCSV},
+		{contentType: "application/json; charset=utf-8", want: FormatJSON},
+	}
+	for _, tt := range tests {
+		if got := FormatFromContentType(tt.contentType); got != tt.want {
+			t.Errorf("FormatFromContentType(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
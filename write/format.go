@@ -0,0 +1,129 @@
+package write
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Format is the format of data written to InfluxDB.
+type Format string
+
+const (
+	// FormatLineProtocol is line protocol, which is the default format.
+	FormatLineProtocol Format = "lp"
+	// FormatCSV is annotated CSV, see ParseCSV.
+	FormatCSV Format = "csv"
+	// FormatJSON is the JSON mapping format, see ParseJSON.
+	FormatJSON Format = "json"
+)
+
+// Valid returns an error if the format is not known.
+func (f Format) Valid() error {
+	switch f {
+	case FormatLineProtocol, FormatCSV, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid format %q; valid formats are lp, csv and json", string(f))
+	}
+}
+
+// ContentType returns the Content-Type of data in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// FormatFromContentType returns the format of a Content-Type. Anything that
+// is not CSV or JSON is line protocol, so clients that do not set a
+// Content-Type keep working.
+func FormatFromContentType(ct string) Format {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return FormatLineProtocol
+	}
+
+	switch mt {
+	case "text/csv", "application/csv":
+		return FormatCSV
+	case "application/json":
+		return FormatJSON
+	default:
+		return FormatLineProtocol
+	}
+}
+
+// FormatFromPath returns the format of a file by its extension.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatLineProtocol
+	}
+}
+
+// ParsePoints reads CSV or JSON data from r and converts it to points.
+// Points without a timestamp are written at defaultTime and numeric
+// timestamps are in the given precision.
+func ParsePoints(f Format, r io.Reader, defaultTime time.Time, precision string) (models.Points, error) {
+	switch f {
+	case FormatCSV:
+		return ParseCSV(r, defaultTime, precision)
+	case FormatJSON:
+		return ParseJSON(r, defaultTime, precision)
+	default:
+		return nil, fmt.Errorf("cannot convert %q to points", string(f))
+	}
+}
+
+// LineProtocol encodes points as line protocol with nanosecond timestamps.
+func LineProtocol(points models.Points) []byte {
+	var buf []byte
+	for _, p := range points {
+		buf = p.AppendString(buf)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// ParseError is returned when data cannot be converted to points. Row is the
+// 1-based line of the offending value; Column is its 1-based CSV column, or
+// its 1-based character offset in the line for JSON.
+type ParseError struct {
+	Row    int
+	Column int
+	Msg    string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("row %d, column %d: %s", e.Row, e.Column, e.Msg)
+}
+
+// parseTime parses a timestamp that is either a number in the given
+// precision or an RFC3339 string.
+func parseTime(s, precision string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(0, n*models.GetPrecisionMultiplier(precision)).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q; timestamps must be RFC3339 or a number", s)
+	}
+	return t, nil
+}
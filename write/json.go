@@ -0,0 +1,195 @@
+package write
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// jsonPoint is a point of the JSON mapping format.
+type jsonPoint struct {
+	Measurement string                 `json:"measurement"`
+	Tags        map[string]string      `json:"tags"`
+	Fields      map[string]interface{} `json:"fields"`
+	Time        interface{}            `json:"time"`
+}
+
+// ParseJSON converts the JSON mapping format to points. The data is either an
+// array of objects or a stream of objects, such as newline delimited JSON.
+// Every object is a point:
+//
+//	{
+//	  "measurement": "cpu",
+//	  "tags": {"host": "server01"},
+//	  "fields": {"usage": 2.5, "healthy": true},
+//	  "time": "2019-08-01T00:00:00Z"
+//	}
+//
+// Field values are numbers, booleans or strings; as in line protocol, numbers
+// are written as floats. The time is optional and is either an RFC3339 string
+// or a number in the given precision; points without a time are written at
+// defaultTime.
+func ParseJSON(r io.Reader, defaultTime time.Time, precision string) (models.Points, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var points models.Points
+	appendPoint := func(pos int) (int, error) {
+		raw, err := decodeJSONValue(data, pos)
+		if err != nil {
+			return 0, err
+		}
+		p, err := parseJSONPoint(data, pos, raw, defaultTime, precision)
+		if err != nil {
+			return 0, err
+		}
+		points = append(points, p)
+		return pos + len(raw), nil
+	}
+
+	pos := skipJSONSpace(data, 0)
+	if pos < len(data) && data[pos] == '[' {
+		pos = skipJSONSpace(data, pos+1)
+		if pos < len(data) && data[pos] == ']' {
+			pos++
+		} else {
+			for {
+				if pos, err = appendPoint(pos); err != nil {
+					return nil, err
+				}
+				pos = skipJSONSpace(data, pos)
+				if pos < len(data) && data[pos] == ']' {
+					pos++
+					break
+				}
+				if pos >= len(data) || data[pos] != ',' {
+					return nil, jsonError(data, pos, "expected , or ] after array element")
+				}
+				pos = skipJSONSpace(data, pos+1)
+			}
+		}
+		if pos = skipJSONSpace(data, pos); pos < len(data) {
+			return nil, jsonError(data, pos, "unexpected data after array")
+		}
+		return points, nil
+	}
+
+	for pos < len(data) {
+		if pos, err = appendPoint(pos); err != nil {
+			return nil, err
+		}
+		pos = skipJSONSpace(data, pos)
+	}
+	return points, nil
+}
+
+// decodeJSONValue returns the JSON value that starts at pos.
+func decodeJSONValue(data []byte, pos int) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(data[pos:])).Decode(&raw); err != nil {
+		if se, ok := err.(*json.SyntaxError); ok {
+			return nil, jsonError(data, pos+int(se.Offset)-1, se.Error())
+		}
+		if err == io.ErrUnexpectedEOF {
+			return nil, jsonError(data, len(data), "unexpected end of JSON input")
+		}
+		return nil, jsonError(data, pos, err.Error())
+	}
+	return raw, nil
+}
+
+func parseJSONPoint(data []byte, pos int, raw json.RawMessage, defaultTime time.Time, precision string) (models.Point, error) {
+	if raw[0] != '{' {
+		return nil, jsonError(data, pos, "point must be an object")
+	}
+
+	var jp jsonPoint
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&jp); err != nil {
+		if te, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, jsonError(data, pos+int(te.Offset)-1, fmt.Sprintf("expected %s, got %s", te.Type, te.Value))
+		}
+		return nil, jsonError(data, pos, err.Error())
+	}
+
+	if jp.Measurement == "" {
+		return nil, jsonError(data, pos, "missing measurement")
+	}
+	if len(jp.Fields) == 0 {
+		return nil, jsonError(data, pos, "point has no fields")
+	}
+
+	fields := make(models.Fields, len(jp.Fields))
+	for k, v := range jp.Fields {
+		switch v := v.(type) {
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, jsonError(data, pos, fmt.Sprintf("invalid number %s for field %q", v, k))
+			}
+			fields[k] = f
+		case bool, string:
+			fields[k] = v
+		default:
+			return nil, jsonError(data, pos, fmt.Sprintf("field %q must be a number, boolean or string", k))
+		}
+	}
+
+	ts := defaultTime
+	switch t := jp.Time.(type) {
+	case nil:
+	case json.Number:
+		var err error
+		if ts, err = parseTime(t.String(), precision); err != nil {
+			return nil, jsonError(data, pos, err.Error())
+		}
+	case string:
+		var err error
+		if ts, err = parseTime(t, precision); err != nil {
+			return nil, jsonError(data, pos, err.Error())
+		}
+	default:
+		return nil, jsonError(data, pos, "time must be a number or a string")
+	}
+
+	p, err := models.NewPoint(jp.Measurement, models.NewTags(jp.Tags), fields, ts)
+	if err != nil {
+		return nil, jsonError(data, pos, err.Error())
+	}
+	return p, nil
+}
+
+func skipJSONSpace(data []byte, pos int) int {
+	for pos < len(data) {
+		switch data[pos] {
+		case ' ', '\t', '\r', '\n':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// jsonError returns a ParseError at the line and column of offset pos of
+// data. The offsets of encoding/json errors point just past the offending
+// byte, so callers subtract one from them.
+func jsonError(data []byte, pos int, msg string) error {
+	if pos > len(data) {
+		pos = len(data)
+	} else if pos < 0 {
+		pos = 0
+	}
+	line := bytes.Count(data[:pos], []byte("\n")) + 1
+	col := pos - bytes.LastIndexByte(data[:pos], '\n')
+	return &ParseError{Row: line, Column: col, Msg: msg}
+}
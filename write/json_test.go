@@ -0,0 +1,99 @@
+package write
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseJSON(t *testing.T) {
+	now := time.Unix(0, 42)
+
+	tests := []struct {
+		name      string
+		input     string
+		precision string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name: "array of points",
+			input: `[
+  {"measurement": "cpu", "tags": {"host": "a"}, "fields": {"f": 1.5, "b": true, "s": "up"}, "time": "1970-01-01T00:00:01Z"},
+  {"measurement": "mem", "fields": {"f": 2}}
+]`,
+			want: []string{
+				`cpu,host=a b=true,f=1.5,s="up" 1000000000`,
+				"mem f=2 42",
+			},
+		},
+		{
+			name:      "newline delimited points with numeric timestamps",
+			input:     "{\"measurement\": \"cpu\", \"fields\": {\"f\": 1}, \"time\": 2}\n{\"measurement\": \"cpu\", \"fields\": {\"f\": 2}, \"time\": 3}\n",
+			precision: "ms",
+			want: []string{
+				"cpu f=1 2000000",
+				"cpu f=2 3000000",
+			},
+		},
+		{
+			name:  "empty array",
+			input: " [ ] ",
+		},
+		{
+			name:    "missing measurement",
+			input:   "[\n  {\"measurement\": \"cpu\", \"fields\": {\"f\": 1}},\n  {\"fields\": {\"f\": 1}}\n]",
+			wantErr: "row 3, column 3: missing measurement",
+		},
+		{
+			name:    "invalid field value",
+			input:   `{"measurement": "cpu", "fields": {"f": [1]}}`,
+			wantErr: `row 1, column 1: field "f" must be a number, boolean or string`,
+		},
+		{
+			name:    "invalid tag value",
+			input:   "\n\n{\"measurement\": \"cpu\", \"tags\": {\"t\": 1}, \"fields\": {\"f\": 1}}",
+			wantErr: "row 3, column 38: expected string, got number",
+		},
+		{
+			name:    "unknown key",
+			input:   `{"measurement": "cpu", "field": {"f": 1}}`,
+			wantErr: `row 1, column 1: json: unknown field "field"`,
+		},
+		{
+			name:    "syntax error",
+			input:   "[\n  {\"measurement\": \"cpu\" \"fields\": {}}\n]",
+			wantErr: "row 2, column 25: invalid character '\"' after object key:value pair",
+		},
+		{
+			name:    "missing comma",
+			input:   `[{"measurement": "cpu", "fields": {"f": 1}} {}]`,
+			wantErr: "row 1, column 45: expected , or ] after array element",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precision := tt.precision
+			if precision == "" {
+				precision = "ns"
+			}
+
+			points, err := ParseJSON(strings.NewReader(tt.input), now, precision)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseJSON() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSON() unexpected error: %v", err)
+			}
+
+			if got := pointStrings(points); !cmp.Equal(got, tt.want) {
+				t.Errorf("ParseJSON() -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}